@@ -32,6 +32,9 @@ func main() {
 	// Set logrus to output to stdout for debugging
 	logrus.SetOutput(os.Stdout)
 	logrus.SetLevel(logrus.DebugLevel)
+
+	// Capture log lines for the support log tail endpoint
+	logrus.AddHook(services.LogStream())
 	
 	logrus.Info("Starting NodePath Chat Server...")
 
@@ -50,6 +53,10 @@ func main() {
 		"port": cfg.Port,
 	}).Debug("Configuration loaded")
 
+	// Start distributed tracing; spans export over OTLP when an endpoint is set
+	tracer := services.InitTracing(cfg.OTLPEndpoint, cfg.OTLPServiceName)
+	go tracer.StartExporter()
+
 	// Initialize Supabase database (required)
 	var db *sql.DB
 	var err error
@@ -266,6 +273,12 @@ func main() {
 		return c.JSON(healthData)
 	})
 
+	// Prometheus metrics for the message pipeline
+	app.Get("/metrics", func(c *fiber.Ctx) error {
+		c.Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		return c.SendString(services.Metrics().Render())
+	})
+
 	// WebSocket endpoint for real-time communication
 	app.Use("/ws", func(c *fiber.Ctx) error {
 		// Check if connection is a WebSocket upgrade
@@ -341,6 +354,12 @@ func main() {
 	api := app.Group("/api")
 	handlers.SetupRoutes(api)
 
+	// Admin-only pprof endpoints for profiling engine hot paths
+	handlers.SetupDebugRoutes(app)
+
+	// Public short link redirect (no auth - clicked from chat messages)
+	app.Get("/s/:code", handlers.RedirectShortLink)
+
 	// Add middleware to force no-cache and prevent 304 responses - MUST BE BEFORE STATIC SERVING
 	app.Use("/assets/*", func(c *fiber.Ctx) error {
 		c.Set("Cache-Control", "no-cache, no-store, must-revalidate")
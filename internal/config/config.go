@@ -41,6 +41,13 @@ type Config struct {
 	WebSocketEnabled   bool   // Enable WebSocket support
 	CDNEnabled         bool   // Enable CDN for media files
 	CDNBaseURL         string // CDN base URL
+	MessageWorkers     int    // Base webhook message worker goroutines
+	MessageQueueSize   int    // Webhook message queue buffer size
+
+	// Observability configuration
+	OTLPEndpoint     string // OTLP/HTTP collector base URL for trace export (empty disables export)
+	OTLPServiceName  string // Service name reported on exported spans
+	TurnLatencySLOMs int    // p95 webhook-to-send latency target per device in milliseconds
 }
 
 // Load loads configuration from environment variables with performance optimizations
@@ -79,6 +86,13 @@ func Load() *Config {
 		WebSocketEnabled:   getEnvAsBool("WEBSOCKET_ENABLED", true),
 		CDNEnabled:         getEnvAsBool("CDN_ENABLED", false),
 		CDNBaseURL:         getEnv("CDN_BASE_URL", ""),
+		MessageWorkers:     getEnvAsInt("MESSAGE_WORKERS", 10),
+		MessageQueueSize:   getEnvAsInt("MESSAGE_QUEUE_SIZE", 1000),
+
+		// Observability configuration
+		OTLPEndpoint:     getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		OTLPServiceName:  getEnv("OTEL_SERVICE_NAME", "nodepath-chat"),
+		TurnLatencySLOMs: getEnvAsInt("TURN_LATENCY_SLO_MS", 5000),
 	}
 
 	return cfg
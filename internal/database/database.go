@@ -119,6 +119,8 @@ func RunMigrations(db *sql.DB) error {
 		createAIWhatsappTable,
 		createWasapBotTable,
 		createConversationLogTable,
+		createMessagesTable,
+		createConversationEventsTable,
 		createOrdersTable,
 		createAIWhatsappSessionTable,
 		createWasapBotSessionTable,
@@ -371,6 +373,41 @@ CREATE INDEX IF NOT EXISTS idx_conversation_log_created_at ON conversation_log(c
 CREATE INDEX IF NOT EXISTS idx_conversation_log_stage ON conversation_log(stage);
 `
 
+// Structured per-message storage replacing conv_last string concatenation
+// for querying and pagination; conv_last keeps being written for legacy readers
+const createMessagesTable = `
+CREATE TABLE IF NOT EXISTS messages (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('inbound', 'outbound')),
+    message_type VARCHAR(10) DEFAULT 'text' CHECK (message_type IN ('text', 'image', 'document', 'audio', 'video')),
+    content TEXT NOT NULL,
+    stage VARCHAR(255) DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_prospect_device ON messages(prospect_num, id_device);
+CREATE INDEX IF NOT EXISTS idx_messages_created_at ON messages(created_at);
+`
+
+// Append-only event log for conversation state changes (event sourcing);
+// rows are never updated or deleted, and seq orders events per conversation
+const createConversationEventsTable = `
+CREATE TABLE IF NOT EXISTS conversation_event (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    seq INT NOT NULL,
+    event_type VARCHAR(50) NOT NULL CHECK (event_type IN ('message_received', 'message_sent', 'node_transition', 'stage_change')),
+    detail TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (prospect_num, id_device, seq)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_event_conversation ON conversation_event(prospect_num, id_device, seq);
+`
+
 // Orders table for Billplz payment integration
 const createOrdersTable = `
 CREATE TABLE IF NOT EXISTS orders (
@@ -18,10 +18,12 @@ import (
 
 // AIWhatsappHandlers contains all AI WhatsApp webhook handlers
 type AIWhatsappHandlers struct {
-	AIWhatsappService services.AIWhatsappService
-	AIRepo            repository.AIWhatsappRepository
-	DeviceRepo        repository.DeviceSettingsRepository
-	mainHandlers      *Handlers // Reference to main handlers for flow routing
+	AIWhatsappService  services.AIWhatsappService
+	AIRepo             repository.AIWhatsappRepository
+	DeviceRepo         repository.DeviceSettingsRepository
+	mainHandlers       *Handlers                           // Reference to main handlers for flow routing
+	inboundMedia       *services.InboundMediaService       // Downloads and stores media from provider webhooks
+	voiceTranscription *services.VoiceTranscriptionService // Transcribes incoming voice notes
 }
 
 // NewAIWhatsappHandlers creates a new AI WhatsApp handlers instance
@@ -43,6 +45,58 @@ func (h *AIWhatsappHandlers) SetMainHandlers(mainHandlers *Handlers) {
 	h.mainHandlers = mainHandlers
 }
 
+// SetInboundMediaService sets the service used to download webhook media
+func (h *AIWhatsappHandlers) SetInboundMediaService(inboundMedia *services.InboundMediaService) {
+	h.inboundMedia = inboundMedia
+}
+
+// SetVoiceTranscriptionService sets the service used to transcribe voice notes
+func (h *AIWhatsappHandlers) SetVoiceTranscriptionService(voiceTranscription *services.VoiceTranscriptionService) {
+	h.voiceTranscription = voiceTranscription
+}
+
+// resolveInboundMedia downloads provider-hosted media and appends the stored
+// file URL to the message text, so the reference reaches conversation history,
+// flow nodes and AI prompts like any other media link. Voice notes are
+// additionally transcribed and the transcript becomes the message content,
+// flagged as audio-origin. When the download fails the original provider URL
+// is kept so the message is never lost
+func (h *AIWhatsappHandlers) resolveInboundMedia(message, mediaURL, deviceID, provider string) string {
+	if mediaURL == "" {
+		return message
+	}
+
+	resolvedURL := mediaURL
+	if h.inboundMedia != nil {
+		storedURL, err := h.inboundMedia.Ingest(mediaURL)
+		if err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"provider":  provider,
+				"media_url": mediaURL,
+			}).Warn("⚠️ INBOUND MEDIA: Download failed, keeping provider URL")
+		} else {
+			resolvedURL = storedURL
+		}
+	}
+
+	// Voice notes become their transcript, marked as audio-origin
+	if h.voiceTranscription != nil {
+		if transcript, ok := h.voiceTranscription.TranscribeIfAudio(resolvedURL, deviceID); ok {
+			transcriptLine := services.VoiceNoteMarker + " " + transcript
+			if message == "" {
+				message = transcriptLine
+			} else {
+				message = message + "\n" + transcriptLine
+			}
+		}
+	}
+
+	if message == "" {
+		return resolvedURL
+	}
+	return message + "\n" + resolvedURL
+}
+
 // getAuthMiddleware returns the authentication middleware from main handlers
 func (h *AIWhatsappHandlers) getAuthMiddleware() fiber.Handler {
 	if h.mainHandlers != nil && h.mainHandlers.authHandlers != nil {
@@ -78,6 +132,10 @@ func (h *AIWhatsappHandlers) SetupAIWhatsappRoutes(api fiber.Router) {
 	api.Post("/webhook/wablas/:device_id", h.HandleWablasWebhook)
 	api.Post("/webhook/whacenter/:device_id", h.HandleWhacenterWebhook)
 	api.Post("/webhook/waha/:device_id", h.HandleWahaWebhook)
+	api.Post("/webhook/twilio/:device_id", h.HandleTwilioWebhook)
+	api.Get("/webhook/meta/:device_id", h.HandleMetaWebhookVerify)
+	api.Post("/webhook/meta/:device_id", h.HandleMetaWebhook)
+	api.Post("/webhook/telegram/:device_id", h.HandleTelegramWebhook)
 
 	// Test endpoints for webhook data extraction (no auth required for testing)
 	api.Post("/test/waha/extraction", h.TestWahaExtraction)
@@ -128,11 +186,13 @@ type WhatsappWebhookRequest struct {
 
 // WablasWebhookRequest represents incoming Wablas webhook data
 type WablasWebhookRequest struct {
-	Phone    string `json:"phone"`
-	Message  string `json:"message"`
-	Device   string `json:"device"`
-	Time     string `json:"time"`
-	IsFromMe bool   `json:"isFromMe"`
+	Phone       string `json:"phone"`
+	Message     string `json:"message"`
+	Device      string `json:"device"`
+	Time        string `json:"time"`
+	IsFromMe    bool   `json:"isFromMe"`
+	MessageType string `json:"messageType"` // "text", "image", "audio", "video", "document"
+	URL         string `json:"url"`         // Media URL for non-text messages
 }
 
 // WhacenterWebhookRequest represents incoming Whacenter webhook data
@@ -141,6 +201,7 @@ type WhacenterWebhookRequest struct {
 	Text   string `json:"text"`
 	Device string `json:"device"`
 	Date   string `json:"date"`
+	File   string `json:"file"` // Media URL for non-text messages
 }
 
 // WahaWebhookRequest represents incoming WAHA webhook data
@@ -159,6 +220,18 @@ type WahaWebhookRequest struct {
 	} `json:"payload"`
 }
 
+// TwilioWebhookRequest represents incoming Twilio webhook data
+// Twilio posts application/x-www-form-urlencoded with its native field names
+type TwilioWebhookRequest struct {
+	From        string `form:"From"`
+	To          string `form:"To"`
+	Body        string `form:"Body"`
+	ProfileName string `form:"ProfileName"`
+	NumMedia    string `form:"NumMedia"`
+	MediaURL    string `form:"MediaUrl0"`
+	MessageSid  string `form:"MessageSid"`
+}
+
 // StartAIConversationRequest represents request to start AI conversation
 type StartAIConversationRequest struct {
 	ProspectNum  string `json:"prospect_num"`
@@ -339,8 +412,12 @@ func (h *AIWhatsappHandlers) HandleWablasWebhook(c *fiber.Ctx) error {
 		})
 	}
 
-	// Process the message asynchronously
-	go h.processIncomingMessage(req.Phone, req.Message, deviceID, "wablas", req.Phone)
+	// Process the message asynchronously; media download happens off the
+	// webhook response path
+	go func() {
+		message := h.resolveInboundMedia(req.Message, req.URL, deviceID, "wablas")
+		h.processIncomingMessage(req.Phone, message, deviceID, "wablas", req.Phone)
+	}()
 
 	return h.successResponse(c, map[string]string{"status": "received"})
 }
@@ -441,8 +518,12 @@ func (h *AIWhatsappHandlers) HandleWhacenterWebhook(c *fiber.Ctx) error {
 		})
 	}
 
-	// Process the message asynchronously
-	go h.processIncomingMessage(phoneNumber, message, deviceID, "whacenter", senderName)
+	// Process the message asynchronously; media download happens off the
+	// webhook response path
+	go func() {
+		resolvedMessage := h.resolveInboundMedia(message, req.File, deviceID, "whacenter")
+		h.processIncomingMessage(phoneNumber, resolvedMessage, deviceID, "whacenter", senderName)
+	}()
 
 	return h.successResponse(c, map[string]string{"status": "received"})
 }
@@ -498,8 +579,8 @@ func (h *AIWhatsappHandlers) HandleWahaWebhook(c *fiber.Ctx) error {
 		})
 	}
 
-	// Validate required fields
-	if extractedData.SenderPhone == "" || extractedData.Message == "" {
+	// Validate required fields; media-only messages arrive with an empty body
+	if extractedData.SenderPhone == "" || (extractedData.Message == "" && extractedData.MediaURL == "") {
 		logrus.WithFields(logrus.Fields{
 			"sender_phone": extractedData.SenderPhone,
 			"message":      truncateString(extractedData.Message, 100),
@@ -718,6 +799,11 @@ func (h *AIWhatsappHandlers) HandleWahaWebhook(c *fiber.Ctx) error {
 	// Route through the standardized webhook processing system
 	// This ensures WAHA follows the same flow node logic as Whacenter
 	go func() {
+		// Download and store any attached media off the webhook response path
+		if extractedData.MediaURL != "" {
+			extractedData.Message = h.resolveInboundMedia(extractedData.Message, extractedData.MediaURL, deviceID, "waha")
+			webhookData["message"] = extractedData.Message
+		}
 		if h.mainHandlers != nil {
 			err := h.mainHandlers.processWebhookMessage(webhookData, deviceID, "waha")
 			if err != nil {
@@ -746,6 +832,289 @@ func (h *AIWhatsappHandlers) HandleWahaWebhook(c *fiber.Ctx) error {
 	})
 }
 
+// HandleTwilioWebhook handles incoming Twilio WhatsApp webhook requests.
+// Twilio posts form-encoded data with the sender in whatsapp:+E164 form and
+// routes through the same standardized flow processing as the other providers
+func (h *AIWhatsappHandlers) HandleTwilioWebhook(c *fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	if deviceID == "" {
+		return h.errorResponse(c, fiber.StatusBadRequest, "Device ID is required")
+	}
+
+	var req TwilioWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		logrus.WithError(err).Error("Failed to parse Twilio webhook request")
+		return h.errorResponse(c, fiber.StatusBadRequest, "Invalid request format")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"device_id":    deviceID,
+		"from":         req.From,
+		"message":      truncateString(req.Body, 100),
+		"profile_name": req.ProfileName,
+		"num_media":    req.NumMedia,
+		"message_sid":  req.MessageSid,
+	}).Info("Received Twilio webhook")
+
+	// Status callbacks (delivered/read receipts) carry no sender - ignore
+	if req.From == "" {
+		return c.JSON(fiber.Map{
+			"status": "ignored",
+			"reason": "no sender (status callback)",
+		})
+	}
+
+	if req.Body == "" && req.MediaURL == "" {
+		return c.JSON(fiber.Map{
+			"status": "ignored",
+			"reason": "empty message",
+		})
+	}
+
+	// Route through the standardized webhook processing system with Twilio's
+	// native field names; the twilio case in processWebhookMessage extracts them
+	webhookData := map[string]interface{}{
+		"From":        req.From,
+		"Body":        req.Body,
+		"ProfileName": req.ProfileName,
+		"NumMedia":    req.NumMedia,
+		"MediaUrl0":   req.MediaURL,
+	}
+
+	go func() {
+		if h.mainHandlers != nil {
+			if err := h.mainHandlers.processWebhookMessage(webhookData, deviceID, "twilio"); err != nil {
+				logrus.WithError(err).WithFields(logrus.Fields{
+					"device_id": deviceID,
+					"from":      req.From,
+				}).Error("❌ TWILIO: Failed to process message through standardized flow routing")
+			}
+		} else {
+			// Fallback to direct processing if main handlers not available
+			phoneNumber := strings.TrimPrefix(strings.TrimPrefix(req.From, "whatsapp:"), "+")
+			senderName := req.ProfileName
+			if senderName == "" {
+				senderName = "User"
+			}
+			h.processIncomingMessage(phoneNumber, req.Body, deviceID, "twilio", senderName)
+		}
+	}()
+
+	return h.successResponse(c, map[string]string{"status": "received"})
+}
+
+// MetaWebhookPayload represents incoming Meta Cloud API webhook data. Meta
+// nests messages under entry[].changes[].value
+type MetaWebhookPayload struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				Contacts []struct {
+					Profile struct {
+						Name string `json:"name"`
+					} `json:"profile"`
+					WaID string `json:"wa_id"`
+				} `json:"contacts"`
+				Messages []struct {
+					From string `json:"from"`
+					Type string `json:"type"`
+					Text struct {
+						Body string `json:"body"`
+					} `json:"text"`
+					Image struct {
+						ID string `json:"id"`
+					} `json:"image"`
+				} `json:"messages"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// HandleMetaWebhookVerify answers Meta's webhook subscription handshake. The
+// verify token configured in the Meta app dashboard must be the device ID
+func (h *AIWhatsappHandlers) HandleMetaWebhookVerify(c *fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	mode := c.Query("hub.mode")
+	verifyToken := c.Query("hub.verify_token")
+	challenge := c.Query("hub.challenge")
+
+	if mode == "subscribe" && verifyToken == deviceID && challenge != "" {
+		logrus.WithField("device_id", deviceID).Info("✅ META: Webhook subscription verified")
+		return c.SendString(challenge)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"device_id": deviceID,
+		"mode":      mode,
+	}).Warn("⚠️ META: Webhook verification failed")
+	return c.SendStatus(fiber.StatusForbidden)
+}
+
+// HandleMetaWebhook handles incoming Meta Cloud API webhook messages. When
+// the device has an app secret configured, the X-Hub-Signature-256 header is
+// verified against the raw body before the payload is trusted
+func (h *AIWhatsappHandlers) HandleMetaWebhook(c *fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	if deviceID == "" {
+		return h.errorResponse(c, fiber.StatusBadRequest, "Device ID is required")
+	}
+
+	body := c.Body()
+
+	// Verify the payload signature when the device carries an app secret
+	device, err := h.DeviceRepo.GetDeviceSettingsByDevice(deviceID)
+	if err == nil && device != nil {
+		if appSecret := services.MetaAppSecret(device); appSecret != "" {
+			if !services.MetaVerifySignature(appSecret, body, c.Get("X-Hub-Signature-256")) {
+				logrus.WithField("device_id", deviceID).Warn("🚫 META: Webhook signature verification failed")
+				return h.errorResponse(c, fiber.StatusForbidden, "Invalid webhook signature")
+			}
+		}
+	}
+
+	var payload MetaWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		logrus.WithError(err).Error("Failed to parse Meta webhook request")
+		return h.errorResponse(c, fiber.StatusBadRequest, "Invalid request format")
+	}
+
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			// Sender profile names arrive alongside the messages
+			senderNames := make(map[string]string)
+			for _, contact := range change.Value.Contacts {
+				if contact.Profile.Name != "" {
+					senderNames[contact.WaID] = contact.Profile.Name
+				}
+			}
+
+			for _, message := range change.Value.Messages {
+				// Status updates and unsupported types carry no text
+				if message.Type != "text" || message.Text.Body == "" {
+					logrus.WithFields(logrus.Fields{
+						"device_id":    deviceID,
+						"message_type": message.Type,
+					}).Info("⏭️ META: Skipping non-text message")
+					continue
+				}
+
+				senderName := senderNames[message.From]
+				if senderName == "" {
+					senderName = "User"
+				}
+
+				logrus.WithFields(logrus.Fields{
+					"device_id":   deviceID,
+					"from":        message.From,
+					"message":     truncateString(message.Text.Body, 100),
+					"sender_name": senderName,
+				}).Info("Received Meta webhook message")
+
+				// Route through the standardized webhook processing system
+				webhookData := map[string]interface{}{
+					"from":        message.From,
+					"message":     message.Text.Body,
+					"sender_name": senderName,
+				}
+
+				go func(data map[string]interface{}, from, text, name string) {
+					if h.mainHandlers != nil {
+						if err := h.mainHandlers.processWebhookMessage(data, deviceID, "meta"); err != nil {
+							logrus.WithError(err).WithFields(logrus.Fields{
+								"device_id": deviceID,
+								"from":      from,
+							}).Error("❌ META: Failed to process message through standardized flow routing")
+						}
+					} else {
+						// Fallback to direct processing if main handlers not available
+						h.processIncomingMessage(from, text, deviceID, "meta", name)
+					}
+				}(webhookData, message.From, message.Text.Body, senderName)
+			}
+		}
+	}
+
+	return h.successResponse(c, map[string]string{"status": "received"})
+}
+
+// TelegramWebhookRequest represents an incoming Telegram bot update. Only
+// message updates are subscribed; the chat ID stands in for the prospect
+// number so the same flows run over Telegram
+type TelegramWebhookRequest struct {
+	UpdateID int64 `json:"update_id"`
+	Message  struct {
+		From struct {
+			ID        int64  `json:"id"`
+			IsBot     bool   `json:"is_bot"`
+			FirstName string `json:"first_name"`
+			LastName  string `json:"last_name"`
+			Username  string `json:"username"`
+		} `json:"from"`
+		Chat struct {
+			ID   int64  `json:"id"`
+			Type string `json:"type"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// HandleTelegramWebhook handles incoming Telegram bot updates and routes them
+// through the same flow processing as the WhatsApp providers
+func (h *AIWhatsappHandlers) HandleTelegramWebhook(c *fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	if deviceID == "" {
+		return h.errorResponse(c, fiber.StatusBadRequest, "Device ID is required")
+	}
+
+	var req TelegramWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		logrus.WithError(err).Error("Failed to parse Telegram webhook request")
+		return h.errorResponse(c, fiber.StatusBadRequest, "Invalid request format")
+	}
+
+	// Non-message updates and bot echoes carry nothing to process
+	if req.Message.Chat.ID == 0 || req.Message.Text == "" || req.Message.From.IsBot {
+		return c.JSON(fiber.Map{
+			"status": "ignored",
+			"reason": "no processable message",
+		})
+	}
+
+	// Group chats are ignored like on the WhatsApp channel
+	if req.Message.Chat.Type != "private" {
+		logrus.WithFields(logrus.Fields{
+			"device_id": deviceID,
+			"chat_type": req.Message.Chat.Type,
+		}).Info("⏭️ TELEGRAM: Ignoring non-private chat message")
+		return c.JSON(fiber.Map{
+			"status": "ignored",
+			"reason": "group message",
+		})
+	}
+
+	chatID := strconv.FormatInt(req.Message.Chat.ID, 10)
+	senderName := strings.TrimSpace(req.Message.From.FirstName + " " + req.Message.From.LastName)
+	if senderName == "" {
+		senderName = req.Message.From.Username
+	}
+	if senderName == "" {
+		senderName = "User"
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"device_id":   deviceID,
+		"chat_id":     chatID,
+		"message":     truncateString(req.Message.Text, 100),
+		"sender_name": senderName,
+	}).Info("Received Telegram webhook")
+
+	// Process the message asynchronously; the chat ID takes the place of the
+	// prospect phone number throughout the flow engine
+	go h.processIncomingMessage(chatID, req.Message.Text, deviceID, "telegram", senderName)
+
+	return h.successResponse(c, map[string]string{"status": "received"})
+}
+
 // extractWahaFields extracts fields from WAHA webhook payload using multiple fallback methods
 // Handles different WAHA payload structures that may vary in production
 func (h *AIWhatsappHandlers) extractWahaFields(payload map[string]interface{}) (from, message, event, session string, isGroup bool) {
@@ -774,6 +1143,7 @@ type WahaWebhookData struct {
 	RawPhone    string `json:"raw_phone"` // Keep original for second pass
 	SenderName  string `json:"sender_name"`
 	Message     string `json:"message"`
+	MediaURL    string `json:"media_url"`
 	IsFromMe    bool   `json:"is_from_me"`
 	IsGroup     bool   `json:"is_group"`
 }
@@ -820,6 +1190,20 @@ func (h *AIWhatsappHandlers) extractWahaWebhookData(webhookPayload map[string]in
 		logrus.WithField("extraction_method", "payload_body").Info("🔍 WAHA: Message extracted from payload.body")
 	}
 
+	// $wa_media = $payload['media']['url'] — present for image/voice/video messages
+	if mediaObj, ok := payload["media"].(map[string]interface{}); ok {
+		if urlVal, ok := mediaObj["url"].(string); ok && urlVal != "" {
+			result.MediaURL = urlVal
+			logrus.WithField("extraction_method", "payload_media_url").Info("🔍 WAHA: Media URL extracted from payload.media.url")
+		}
+	}
+	if result.MediaURL == "" {
+		if mediaURLVal, ok := dataPayload["mediaUrl"].(string); ok && mediaURLVal != "" {
+			result.MediaURL = mediaURLVal
+			logrus.WithField("extraction_method", "data_mediaUrl").Info("🔍 WAHA: Media URL extracted from data.mediaUrl")
+		}
+	}
+
 	// $wa_no_raw = $payload['from'] or $payload['_data']['from'] ?? null
 	// FIRST PASS: Extract RAW phone (with suffix intact)
 	var idNoWaha string
@@ -987,6 +1371,17 @@ func (h *AIWhatsappHandlers) ToggleHumanTakeover(c *fiber.Ctx) error {
 		"human":        req.Human,
 	}).Info("Human takeover toggled")
 
+	// Auto-assign the handoff to an online agent when takeover is enabled
+	if req.Human && h.mainHandlers != nil && h.mainHandlers.assignmentService != nil {
+		if conv, convErr := h.AIRepo.GetAIWhatsappByProspectNum(req.ProspectNum); convErr == nil && conv != nil {
+			go func(prospectNum, idDevice string) {
+				if _, assignErr := h.mainHandlers.assignmentService.AssignConversation(prospectNum, idDevice, "", ""); assignErr != nil {
+					logrus.WithError(assignErr).Warn("Failed to auto-assign handoff conversation")
+				}
+			}(req.ProspectNum, conv.IDDevice)
+		}
+	}
+
 	return h.successResponse(c, map[string]interface{}{
 		"prospect_num": req.ProspectNum,
 		"human":        req.Human,
@@ -1159,19 +1554,25 @@ func (h *AIWhatsappHandlers) ProcessDeviceCommand(c *fiber.Ctx) error {
 	return h.successResponse(c, map[string]string{"status": "processed"})
 }
 
-// processIncomingMessage processes incoming WhatsApp messages asynchronously
+// processIncomingMessage processes incoming messages from any channel
+// asynchronously. The channel is derived from the provider: for WhatsApp
+// gateways prospectNum is the phone number, for Telegram it is the chat ID.
 // Updated to accept senderName parameter to properly save prospect_name
 func (h *AIWhatsappHandlers) processIncomingMessage(prospectNum, message, deviceID, provider, senderName string) {
+	channel := services.ChannelForProvider(provider)
+
 	logrus.WithFields(logrus.Fields{
 		"prospect_num": prospectNum,
 		"device_id":    deviceID,
 		"provider":     provider,
+		"channel":      channel,
 		"message":      message,
 		"sender_name":  senderName,
 	}).Info("Processing incoming message")
 
-	// Check if this is a device command
-	if strings.HasPrefix(message, "%") || strings.HasPrefix(message, "#") || strings.ToLower(message) == "cmd" {
+	// Check if this is a device command - an operator convention on the
+	// WhatsApp channel only
+	if channel == services.ChannelWhatsApp && (strings.HasPrefix(message, "%") || strings.HasPrefix(message, "#") || strings.ToLower(message) == "cmd") {
 		err := h.AIWhatsappService.ProcessDeviceCommand(prospectNum, message, deviceID)
 		if err != nil {
 			logrus.WithError(err).Error("Failed to process device command")
@@ -1573,6 +1974,13 @@ func (h *AIWhatsappHandlers) GetAllAIWhatsappData(c *fiber.Ctx) error {
 		transformedData[i] = transformed
 	}
 
+	// Viewer-role users get phone numbers and emails masked
+	if h.mainHandlers != nil && h.mainHandlers.piiService != nil && h.mainHandlers.piiService.IsViewer(userID) {
+		for i := range transformedData {
+			transformedData[i] = h.mainHandlers.piiService.MaskValue(transformedData[i]).(map[string]interface{})
+		}
+	}
+
 	// Calculate pagination info
 	totalPages := (total + limit - 1) / limit
 
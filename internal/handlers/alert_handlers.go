@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAlerts returns the failure-rate alerts raised since startup, newest
+// first, so the dashboard can show what the alerting service flagged. Alerts
+// are keyed by device across every tenant, so the endpoint is admin-only
+func (h *Handlers) GetAlerts(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+	return h.successResponse(c, h.alertingService.RecentAlerts())
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// API key authentication for the public REST API. External systems get a
+// key scoped to what they need (flows:read, messages:send, ...) and send it
+// as `Authorization: Bearer npk_...`. Only the SHA-256 hash is stored; the
+// plaintext is shown once at creation
+
+// apiKeyPrefix marks our keys so the middleware can tell them apart from
+// other bearer tokens
+const apiKeyPrefix = "npk_"
+
+// API key scopes. The wildcard grants everything a session user could do
+const (
+	APIScopeAll          = "*"
+	APIScopeFlowsRead    = "flows:read"
+	APIScopeFlowsWrite   = "flows:write"
+	APIScopeMessagesSend = "messages:send"
+	APIScopeDevicesRead  = "devices:read"
+)
+
+// validAPIScopes is the set accepted at key creation
+var validAPIScopes = map[string]bool{
+	APIScopeAll:          true,
+	APIScopeFlowsRead:    true,
+	APIScopeFlowsWrite:   true,
+	APIScopeMessagesSend: true,
+	APIScopeDevicesRead:  true,
+}
+
+// APIKeyInfo is what key listings return - never the key itself
+type APIKeyInfo struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Revoked    bool       `json:"revoked"`
+}
+
+// migrateAPIKeys creates the API key table and its indexes
+func (ah *AuthHandlers) migrateAPIKeys() error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS api_keys_nodepath (
+			id CHAR(36) PRIMARY KEY,
+			user_id CHAR(36) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			prefix VARCHAR(16) NOT NULL,
+			key_hash CHAR(64) UNIQUE NOT NULL,
+			scopes VARCHAR(512) NOT NULL,
+			last_used_at TIMESTAMP NULL DEFAULT NULL,
+			revoked_at TIMESTAMP NULL DEFAULT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys_nodepath (user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_api_keys_hash ON api_keys_nodepath (key_hash)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := ah.db.Exec(stmt); err != nil {
+			logrus.WithError(err).Error("Failed to create api_keys table")
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateAPIKey issues a new API key for the authenticated user. The response
+// contains the plaintext key exactly once
+func (ah *AuthHandlers) CreateAPIKey(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"success": false, "error": "Invalid request body"})
+	}
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"success": false, "error": "name is required"})
+	}
+	if len(req.Scopes) == 0 {
+		return c.Status(400).JSON(fiber.Map{"success": false, "error": "at least one scope is required"})
+	}
+	for _, scope := range req.Scopes {
+		if !validAPIScopes[scope] {
+			return c.Status(400).JSON(fiber.Map{"success": false, "error": "unknown scope: " + scope})
+		}
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return c.Status(500).JSON(fiber.Map{"success": false, "error": "Failed to generate key"})
+	}
+	plaintext := apiKeyPrefix + hex.EncodeToString(secret)
+	hash := hashAPIKey(plaintext)
+	prefix := plaintext[:len(apiKeyPrefix)+8]
+
+	id := generateUUID()
+	_, err := ah.db.Exec(repository.Rebind(`
+		INSERT INTO api_keys_nodepath (id, user_id, name, prefix, key_hash, scopes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, NOW())
+	`), id, userID, req.Name, prefix, hash, strings.Join(req.Scopes, ","))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to store API key")
+		return c.Status(500).JSON(fiber.Map{"success": false, "error": "Failed to create API key"})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": userID,
+		"key_id":  id,
+		"scopes":  req.Scopes,
+	}).Info("🔑 API KEY: Key created")
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"id":     id,
+			"name":   req.Name,
+			"key":    plaintext,
+			"prefix": prefix,
+			"scopes": req.Scopes,
+		},
+		"message": "Store this key now - it will not be shown again",
+	})
+}
+
+// ListAPIKeys returns the user's API keys without the key material
+func (ah *AuthHandlers) ListAPIKeys(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	rows, err := ah.db.Query(repository.Rebind(`
+		SELECT id, name, prefix, scopes, last_used_at, created_at, revoked_at
+		FROM api_keys_nodepath
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+	`), userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list API keys")
+		return c.Status(500).JSON(fiber.Map{"success": false, "error": "Failed to list API keys"})
+	}
+	defer rows.Close()
+
+	keys := make([]APIKeyInfo, 0)
+	for rows.Next() {
+		var info APIKeyInfo
+		var scopes string
+		var lastUsedAt, revokedAt sql.NullTime
+		if err := rows.Scan(&info.ID, &info.Name, &info.Prefix, &scopes, &lastUsedAt, &info.CreatedAt, &revokedAt); err != nil {
+			continue
+		}
+		info.Scopes = strings.Split(scopes, ",")
+		if lastUsedAt.Valid {
+			info.LastUsedAt = &lastUsedAt.Time
+		}
+		info.Revoked = revokedAt.Valid
+		keys = append(keys, info)
+	}
+
+	return c.JSON(fiber.Map{"success": true, "data": keys})
+}
+
+// RevokeAPIKey revokes one of the user's API keys immediately
+func (ah *AuthHandlers) RevokeAPIKey(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+	keyID := c.Params("id")
+
+	result, err := ah.db.Exec(repository.Rebind(`
+		UPDATE api_keys_nodepath SET revoked_at = NOW()
+		WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+	`), keyID, userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to revoke API key")
+		return c.Status(500).JSON(fiber.Map{"success": false, "error": "Failed to revoke API key"})
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return c.Status(404).JSON(fiber.Map{"success": false, "error": "API key not found"})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": userID,
+		"key_id":  keyID,
+	}).Info("🔑 API KEY: Key revoked")
+
+	return c.JSON(fiber.Map{"success": true, "message": "API key revoked"})
+}
+
+// authenticateAPIKey resolves a bearer key to its user and scopes. The
+// revoked check is part of the query so a revoked key dies immediately
+func (ah *AuthHandlers) authenticateAPIKey(plaintext string) (string, []string, bool) {
+	var keyID, userID, scopes string
+	err := ah.db.QueryRow(repository.Rebind(`
+		SELECT id, user_id, scopes
+		FROM api_keys_nodepath
+		WHERE key_hash = ? AND revoked_at IS NULL
+	`), hashAPIKey(plaintext)).Scan(&keyID, &userID, &scopes)
+	if err != nil {
+		return "", nil, false
+	}
+
+	// Last-used is best effort; authentication already succeeded
+	if _, err := ah.db.Exec(repository.Rebind(`UPDATE api_keys_nodepath SET last_used_at = NOW() WHERE id = ?`), keyID); err != nil {
+		logrus.WithError(err).Debug("Failed to update API key last_used_at")
+	}
+
+	return userID, strings.Split(scopes, ","), true
+}
+
+// RequireScope gates a route group for API key callers. Session users pass
+// unchanged; key callers need the method-matching scope (readScope on
+// GET/HEAD, writeScope otherwise)
+func (ah *AuthHandlers) RequireScope(readScope, writeScope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scopes, ok := c.Locals("api_key_scopes").([]string)
+		if !ok {
+			// Session-authenticated request - scopes do not apply
+			return c.Next()
+		}
+
+		required := writeScope
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			required = readScope
+		}
+
+		for _, scope := range scopes {
+			if scope == APIScopeAll || scope == required {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "API key is missing the " + required + " scope",
+		})
+	}
+}
+
+// APIKeyScopeGate enforces a scope on routes that are not behind the full
+// auth middleware. Requests without a bearer key pass through unchanged;
+// requests presenting one must hold the scope
+func (ah *AuthHandlers) APIKeyScopeGate(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		bearer := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+		if !strings.HasPrefix(bearer, apiKeyPrefix) {
+			return c.Next()
+		}
+
+		userID, scopes, ok := ah.authenticateAPIKey(bearer)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid or revoked API key",
+			})
+		}
+		c.Locals("user_id", userID)
+		c.Locals("api_key_scopes", scopes)
+
+		for _, held := range scopes {
+			if held == APIScopeAll || held == scope {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"success": false,
+			"error":   "API key is missing the " + scope + " scope",
+		})
+	}
+}
+
+// SessionOnlyMiddleware is AuthMiddleware without the API key path, for
+// sensitive routes like key management itself
+func (ah *AuthHandlers) SessionOnlyMiddleware() fiber.Handler {
+	sessionAuth := ah.AuthMiddleware()
+	return func(c *fiber.Ctx) error {
+		if strings.HasPrefix(strings.TrimPrefix(c.Get("Authorization"), "Bearer "), apiKeyPrefix) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"error":   "API keys cannot manage API keys - log in instead",
+			})
+		}
+		return sessionAuth(c)
+	}
+}
+
+// hashAPIKey returns the stored form of a key
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"database/sql"
+	"strconv"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Approval queue handlers: per-flow review-before-send mode where AI-generated
+// responses wait for an agent to approve, edit or reject them
+
+// GetFlowReviewMode returns whether the flow holds AI responses for review
+func (h *Handlers) GetFlowReviewMode(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"flow_id": flowID,
+			"enabled": h.approvalService.IsReviewEnabled(flowID),
+		},
+	})
+}
+
+// SetFlowReviewMode switches review-before-send mode for a flow
+func (h *Handlers) SetFlowReviewMode(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.approvalService.SetReviewEnabled(flowID, req.Enabled); err != nil {
+		logrus.WithError(err).Error("Failed to set flow review mode")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to set review mode",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"flow_id": flowID,
+			"enabled": req.Enabled,
+		},
+	})
+}
+
+// GetPendingApprovals returns the device's unreviewed messages, oldest first
+func (h *Handlers) GetPendingApprovals(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   "id_device query parameter is required",
+		})
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	messages, err := h.approvalService.Pending(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get pending approvals")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get pending approvals",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    messages,
+	})
+}
+
+// ApproveMessage releases a queued message to the prospect, optionally with
+// the reviewer's edit
+func (h *Handlers) ApproveMessage(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid message ID",
+		})
+	}
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+		})
+	}
+
+	var req struct {
+		EditedContent string `json:"edited_content"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request body",
+			})
+		}
+	}
+
+	pending, err := h.approvalService.GetMessage(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get pending message")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get pending message",
+		})
+	}
+	if pending == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"error":   "Pending message not found",
+		})
+	}
+	if err := h.requireDeviceAccess(c, pending.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.approvalService.Approve(id, userID, req.EditedContent); err != nil {
+		logrus.WithError(err).Error("Failed to approve message")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Message approved and sent",
+	})
+}
+
+// RejectMessage drops a queued message without sending it
+func (h *Handlers) RejectMessage(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid message ID",
+		})
+	}
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+		})
+	}
+
+	pending, err := h.approvalService.GetMessage(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get pending message")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get pending message",
+		})
+	}
+	if pending == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"error":   "Pending message not found",
+		})
+	}
+	if err := h.requireDeviceAccess(c, pending.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.approvalService.Reject(id, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"error":   "Pending message not found",
+			})
+		}
+		logrus.WithError(err).Error("Failed to reject message")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Message rejected",
+	})
+}
+
+// GetFlowEditLog returns the flow's approved-with-changes messages for prompt
+// tuning, newest first
+func (h *Handlers) GetFlowEditLog(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	edits, err := h.approvalService.EditLog(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get flow edit log")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get edit log",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    edits,
+	})
+}
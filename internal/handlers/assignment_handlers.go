@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Assignment handlers expose agent availability, automatic routing and manual
+// reassignment for human-handoff conversations
+
+// SetAgentAvailability marks the authenticated agent online or offline for a device
+func (h *Handlers) SetAgentAvailability(c *fiber.Ctx) error {
+	agentID, ok := c.Locals("user_id").(string)
+	if !ok || agentID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	var req struct {
+		IDDevice string   `json:"id_device"`
+		Online   bool     `json:"online"`
+		Skills   []string `json:"skills"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	// Update declared skills first so queued handoffs can match the new skill set
+	if req.Skills != nil {
+		if err := h.assignmentService.SetAgentSkills(agentID, req.IDDevice, req.Skills); err != nil {
+			logrus.WithError(err).Error("Failed to set agent skills")
+			return h.errorResponse(c, 500, "Failed to update skills")
+		}
+	}
+
+	if err := h.assignmentService.SetAgentOnline(agentID, req.IDDevice, req.Online); err != nil {
+		logrus.WithError(err).Error("Failed to set agent availability")
+		return h.errorResponse(c, 500, "Failed to update availability")
+	}
+
+	return h.successMessageResponse(c, "Availability updated", fiber.Map{
+		"agent_id":  agentID,
+		"id_device": req.IDDevice,
+		"online":    req.Online,
+		"skills":    req.Skills,
+	})
+}
+
+// AssignConversation routes a handoff conversation to an agent using the requested strategy
+func (h *Handlers) AssignConversation(c *fiber.Ctx) error {
+	var req struct {
+		ProspectNum string `json:"prospect_num"`
+		IDDevice    string `json:"id_device"`
+		Strategy    string `json:"strategy"`
+		Skill       string `json:"skill"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.ProspectNum == "" || req.IDDevice == "" {
+		return h.errorResponse(c, 400, "prospect_num and id_device are required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	assignment, err := h.assignmentService.AssignConversation(req.ProspectNum, req.IDDevice, req.Skill, models.AssignmentStrategy(req.Strategy))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to assign conversation")
+		return h.errorResponse(c, 500, "Failed to assign conversation")
+	}
+
+	if assignment == nil {
+		return h.errorResponse(c, 409, "No online agents available, handoff queued")
+	}
+
+	return h.successMessageResponse(c, "Conversation assigned", assignment)
+}
+
+// ReassignConversation manually moves a conversation to a specific agent
+func (h *Handlers) ReassignConversation(c *fiber.Ctx) error {
+	var req struct {
+		ProspectNum string `json:"prospect_num"`
+		IDDevice    string `json:"id_device"`
+		AgentID     string `json:"agent_id"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.ProspectNum == "" || req.IDDevice == "" || req.AgentID == "" {
+		return h.errorResponse(c, 400, "prospect_num, id_device and agent_id are required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	assignment, err := h.assignmentService.ReassignConversation(req.ProspectNum, req.IDDevice, req.AgentID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reassign conversation")
+		return h.errorResponse(c, 500, "Failed to reassign conversation")
+	}
+
+	return h.successMessageResponse(c, "Conversation reassigned", assignment)
+}
+
+// GetConversationAssignment returns the current assignment and history for a conversation
+func (h *Handlers) GetConversationAssignment(c *fiber.Ctx) error {
+	prospectNum := c.Params("prospect_num")
+	idDevice := c.Query("id_device")
+
+	if prospectNum == "" || idDevice == "" {
+		return h.errorResponse(c, 400, "prospect_num and id_device are required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	assignment, err := h.assignmentService.GetAssignment(prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get conversation assignment")
+		return h.errorResponse(c, 500, "Failed to retrieve assignment")
+	}
+
+	history, err := h.assignmentService.GetAssignmentHistory(prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get assignment history")
+		return h.errorResponse(c, 500, "Failed to retrieve assignment history")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"assignment": assignment,
+		"history":    history,
+	})
+}
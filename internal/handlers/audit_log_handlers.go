@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Audit log handlers export and verify the tamper-evident message log for
+// compliance audits
+
+// ExportAuditLog returns a device's audit log entries for a period together
+// with a verification of the device's full hash chain. Dates are YYYY-MM-DD;
+// the period defaults to the last 30 days
+func (h *Handlers) ExportAuditLog(c *fiber.Ctx) error {
+	deviceID := c.Query("device_id")
+	if deviceID == "" {
+		return h.errorResponse(c, 400, "device_id query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, deviceID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -30)
+	if startParam := c.Query("start"); startParam != "" {
+		parsed, err := time.Parse("2006-01-02", startParam)
+		if err != nil {
+			return h.errorResponse(c, 400, "start must be YYYY-MM-DD")
+		}
+		start = parsed
+	}
+	if endParam := c.Query("end"); endParam != "" {
+		parsed, err := time.Parse("2006-01-02", endParam)
+		if err != nil {
+			return h.errorResponse(c, 400, "end must be YYYY-MM-DD")
+		}
+		// Include the whole end day
+		end = parsed.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	entries, verification, err := h.auditLogService.Export(deviceID, start, end)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to export audit log")
+		return h.errorResponse(c, 500, "Failed to export audit log")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"device_id":    deviceID,
+		"start":        start.Format("2006-01-02"),
+		"end":          end.Format("2006-01-02"),
+		"entries":      entries,
+		"verification": verification,
+	})
+}
+
+// VerifyAuditLog recomputes a device's whole audit chain and reports
+// whether it is intact
+func (h *Handlers) VerifyAuditLog(c *fiber.Ctx) error {
+	deviceID := c.Query("device_id")
+	if deviceID == "" {
+		return h.errorResponse(c, 400, "device_id query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, deviceID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	verification, err := h.auditLogService.Verify(deviceID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to verify audit log")
+		return h.errorResponse(c, 500, "Failed to verify audit log")
+	}
+
+	return h.successResponse(c, verification)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash/fnv"
+	"strings"
 	"time"
 
 	"nodepath-chat/internal/models"
@@ -112,6 +113,11 @@ func NewAuthHandlers(db *sql.DB) *AuthHandlers {
 		if err := ah.autoMigrate(); err != nil {
 			logrus.WithError(err).Error("Failed to auto-migrate auth tables")
 		}
+		// A missing api_keys table would silently break every API key
+		// caller, so refuse to start without it
+		if err := ah.migrateAPIKeys(); err != nil {
+			logrus.WithError(err).Fatal("Failed to auto-migrate API key table")
+		}
 	}
 	return ah
 }
@@ -523,9 +529,25 @@ func convertUUIDToInt(uuid string) int {
 	return int(h.Sum32())
 }
 
-// AuthMiddleware validates session tokens and sets user context
+// AuthMiddleware validates session tokens and sets user context. API keys
+// sent as `Authorization: Bearer npk_...` are accepted as an alternative to
+// the session cookie so external systems can call /api programmatically
 func (ah *AuthHandlers) AuthMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// API key authentication for external integrations
+		if bearer := strings.TrimPrefix(c.Get("Authorization"), "Bearer "); strings.HasPrefix(bearer, apiKeyPrefix) {
+			userID, scopes, ok := ah.authenticateAPIKey(bearer)
+			if !ok {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"success": false,
+					"error":   "Invalid or revoked API key",
+				})
+			}
+			c.Locals("user_id", userID)
+			c.Locals("api_key_scopes", scopes)
+			return c.Next()
+		}
+
 		// Get session token from cookie
 		token := c.Cookies("session_token")
 		if token == "" {
@@ -665,6 +687,14 @@ func (ah *AuthHandlers) SetupAuthRoutes(api fiber.Router) {
 
 	// Device check endpoint
 	auth.Get("/device-status", ah.AuthMiddleware(), ah.GetDeviceStatus)
+
+	// API key management for external integrations (session auth only - a
+	// key cannot mint or revoke keys)
+	apiKeys := api.Group("/api-keys")
+	apiKeys.Use(ah.SessionOnlyMiddleware())
+	apiKeys.Post("/", ah.CreateAPIKey)
+	apiKeys.Get("/", ah.ListAPIKeys)
+	apiKeys.Delete("/:id", ah.RevokeAPIKey)
 }
 
 // SetupTemplateRoutes configures template serving routes
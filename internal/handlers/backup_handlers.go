@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportTenantBackup exports the authenticated user's data (flows, devices
+// minus secrets, prospects with conversations) as one JSON bundle
+func (h *Handlers) ExportTenantBackup(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	backup, err := h.backupService.Export(userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to export tenant backup")
+		return h.errorResponse(c, 500, "Failed to export tenant backup")
+	}
+
+	return h.successResponse(c, backup)
+}
+
+// RestoreTenantBackup re-imports a previously exported bundle into the
+// authenticated user's account. Rows that already exist are left untouched;
+// bundles referencing another account's devices are rejected
+func (h *Handlers) RestoreTenantBackup(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	var backup services.TenantBackup
+	if err := c.BodyParser(&backup); err != nil {
+		return h.errorResponse(c, 400, "Invalid backup bundle")
+	}
+
+	result, err := h.backupService.Restore(&backup, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrRestoreDeviceOwned) {
+			return h.errorResponse(c, 403, "Backup bundle references a device owned by another account")
+		}
+		logrus.WithError(err).Error("Failed to restore tenant backup")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, result)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetBranchLocations returns a device's configured branches
+func (h *Handlers) GetBranchLocations(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	branches, err := h.branchLocationService.GetBranches(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get branch locations")
+		return h.errorResponse(c, 500, "Failed to get branch locations")
+	}
+
+	return h.successResponse(c, branches)
+}
+
+// CreateBranchLocation adds a branch/outlet for nearest-branch routing
+func (h *Handlers) CreateBranchLocation(c *fiber.Ctx) error {
+	var branch models.BranchLocation
+	if err := c.BodyParser(&branch); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if branch.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if err := h.requireDeviceAccess(c, branch.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.branchLocationService.CreateBranch(&branch); err != nil {
+		logrus.WithError(err).Error("Failed to create branch location")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, branch)
+}
+
+// UpdateBranchLocation updates an existing branch
+func (h *Handlers) UpdateBranchLocation(c *fiber.Ctx) error {
+	var branch models.BranchLocation
+	if err := c.BodyParser(&branch); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	branch.ID = c.Params("id")
+
+	existing, err := h.branchLocationService.GetBranch(branch.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get branch location")
+		return h.errorResponse(c, 500, "Failed to update branch location")
+	}
+	if existing == nil {
+		return h.errorResponse(c, 404, "Branch location not found")
+	}
+	if err := h.requireDeviceAccess(c, existing.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+	// Branches cannot be moved between devices
+	branch.IDDevice = existing.IDDevice
+
+	if err := h.branchLocationService.UpdateBranch(&branch); err != nil {
+		logrus.WithError(err).Error("Failed to update branch location")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, branch)
+}
+
+// DeleteBranchLocation removes a branch
+func (h *Handlers) DeleteBranchLocation(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	existing, err := h.branchLocationService.GetBranch(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get branch location")
+		return h.errorResponse(c, 500, "Failed to delete branch location")
+	}
+	if existing == nil {
+		return h.errorResponse(c, 404, "Branch location not found")
+	}
+	if err := h.requireDeviceAccess(c, existing.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.branchLocationService.DeleteBranch(id); err != nil {
+		logrus.WithError(err).Error("Failed to delete branch location")
+		return h.errorResponse(c, 500, "Failed to delete branch location")
+	}
+
+	return h.successResponse(c, fiber.Map{"deleted": id})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetDuplicateProspects lists phone numbers with isolated histories on more
+// than one of the user's devices
+func (h *Handlers) GetDuplicateProspects(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	duplicates, err := h.contactService.Duplicates(userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get duplicate prospects")
+		return h.errorResponse(c, 500, "Failed to get duplicate prospects")
+	}
+
+	return h.successResponse(c, duplicates)
+}
+
+// GetContactIdentityMode returns whether the user's devices share flow state
+func (h *Handlers) GetContactIdentityMode(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	mode, err := h.contactService.IdentityMode(userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get contact identity mode")
+		return h.errorResponse(c, 500, "Failed to get contact identity mode")
+	}
+
+	return h.successResponse(c, fiber.Map{"identity_mode": mode})
+}
+
+// SetContactIdentityModeRequest carries the flow-state sharing mode
+type SetContactIdentityModeRequest struct {
+	IdentityMode string `json:"identity_mode"`
+}
+
+// SetContactIdentityMode saves whether the user's devices share flow state
+func (h *Handlers) SetContactIdentityMode(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	var req SetContactIdentityModeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if err := h.contactService.SetIdentityMode(userID, req.IdentityMode); err != nil {
+		logrus.WithError(err).Error("Failed to set contact identity mode")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, fiber.Map{"identity_mode": req.IdentityMode})
+}
+
+// GetContact returns the org-level contact behind a phone number with its
+// linked device-scoped conversations
+func (h *Handlers) GetContact(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	contact, err := h.contactService.ContactForUser(userID, c.Params("phone"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get contact")
+		return h.errorResponse(c, 500, "Failed to get contact")
+	}
+
+	return h.successResponse(c, contact)
+}
+
+// GetContactHistory returns a contact's conversation logs merged across
+// every device of the user, newest first
+func (h *Handlers) GetContactHistory(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	history, err := h.contactService.UnifiedHistory(userID, c.Params("phone"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get contact history")
+		return h.errorResponse(c, 500, "Failed to get contact history")
+	}
+
+	return h.successResponse(c, history)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"strconv"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// ListConversationEvents returns entries from a conversation's append-only
+// event log in sequence order, for analytics consumers and replay. Pass
+// ?device_id= (required) plus optional ?after_seq= and ?limit= for cursor
+// pagination
+func (h *Handlers) ListConversationEvents(c *fiber.Ctx) error {
+	prospectNum := c.Params("prospect")
+	idDevice := c.Query("device_id")
+	if prospectNum == "" || idDevice == "" {
+		return h.errorResponse(c, 400, "prospect and device_id are required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	afterSeq, err := strconv.Atoi(c.Query("after_seq", "0"))
+	if err != nil || afterSeq < 0 {
+		afterSeq = 0
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	events, err := h.conversationEventService.ListEvents(prospectNum, idDevice, afterSeq, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list conversation events")
+		return h.errorResponse(c, 500, "Failed to list conversation events")
+	}
+
+	nextSeq := afterSeq
+	if len(events) > 0 {
+		nextSeq = events[len(events)-1].Seq
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"events":       events,
+			"after_seq":    afterSeq,
+			"next_seq":     nextSeq,
+			"limit":        limit,
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"strconv"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// SearchConversations runs a full-text search over a device's conversation
+// content. Results come back best match first with highlighted snippets;
+// the optional sender filter ("user" or "bot") restricts which side of the
+// conversation the terms must appear on
+func (h *Handlers) SearchConversations(c *fiber.Ctx) error {
+	deviceID := c.Query("device_id")
+	if deviceID == "" {
+		return h.errorResponse(c, 400, "device_id query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, deviceID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	query := c.Query("q")
+	if query == "" {
+		return h.errorResponse(c, 400, "q query parameter is required")
+	}
+
+	sender := c.Query("sender")
+	if sender != "" && sender != "user" && sender != "bot" {
+		return h.errorResponse(c, 400, "sender must be 'user' or 'bot'")
+	}
+
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, _ = strconv.Atoi(limitParam)
+	}
+
+	results, err := h.conversationSearchService.Search(deviceID, query, sender, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to search conversations")
+		return h.errorResponse(c, 500, "Failed to search conversations")
+	}
+
+	// Viewer-role users get prospect numbers masked
+	if userID, ok := c.Locals("user_id").(string); ok && h.piiService.IsViewer(userID) {
+		for i := range results {
+			results[i].ProspectNum = services.MaskPhone(results[i].ProspectNum)
+		}
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"query":   query,
+		"results": results,
+	})
+}
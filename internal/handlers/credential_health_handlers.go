@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetCredentialHealth returns the latest credential check results, for one
+// device when ?device_id is given or for every monitored device otherwise
+func (h *Handlers) GetCredentialHealth(c *fiber.Ctx) error {
+	if deviceID := c.Query("device_id"); deviceID != "" {
+		if err := h.requireDeviceAccess(c, deviceID, models.OrgRoleViewer); err != nil {
+			return err
+		}
+		health := h.credentialHealthService.GetHealth(deviceID)
+		if health == nil {
+			return h.errorResponse(c, 404, "Device has not been checked yet")
+		}
+		return h.successResponse(c, health)
+	}
+
+	// The unfiltered view spans every monitored device - admins only
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"devices": h.credentialHealthService.ListHealth(),
+	})
+}
+
+// CheckCredentialHealth re-validates a device's provider credentials
+// immediately, so owners can confirm a fix without waiting for the next
+// scheduled check
+func (h *Handlers) CheckCredentialHealth(c *fiber.Ctx) error {
+	var req struct {
+		DeviceID string `json:"device_id"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.DeviceID == "" {
+		return h.errorResponse(c, 400, "device_id is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.DeviceID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	device, err := h.deviceSettingsService.GetByIDDevice(req.DeviceID)
+	if err != nil || device == nil {
+		logrus.WithError(err).WithField("device_id", req.DeviceID).Error("Failed to load device for credential check")
+		return h.errorResponse(c, 404, "Device not found")
+	}
+
+	health := h.credentialHealthService.CheckDevice(device)
+	return h.successResponse(c, health)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// RotateDeviceCredentials swaps a device's provider credential for a new one.
+// The new token is validated against the provider before the swap, so a bad
+// paste never replaces a working credential; conversation history and flows
+// are untouched
+func (h *Handlers) RotateDeviceCredentials(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return h.errorResponse(c, 400, "Device setting ID is required")
+	}
+
+	var req struct {
+		Instance string `json:"instance"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.Instance == "" {
+		return h.errorResponse(c, 400, "instance is required")
+	}
+
+	setting, err := h.deviceSettingsService.GetByID(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get device setting for credential rotation")
+		if err.Error() == "device setting not found" {
+			return h.errorResponse(c, 404, "Device setting not found")
+		}
+		return h.errorResponse(c, 500, "Failed to retrieve device setting")
+	}
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	// Swapping the provider credential redirects the device - admin-level access only
+	if !h.canAccessDeviceSetting(setting, userID, models.OrgRoleAdmin) {
+		return h.errorResponse(c, 403, "You do not have access to this device")
+	}
+
+	result, err := h.credentialRotationService.Rotate(setting, req.Instance)
+	if err != nil {
+		logrus.WithError(err).WithField("id", id).Error("Failed to rotate device credentials")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, result)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// RegisterCustomNodeTypeRequest is the payload to register a webhook-backed
+// node type
+type RegisterCustomNodeTypeRequest struct {
+	IDDevice       string `json:"id_device"`
+	TypeName       string `json:"type_name"`
+	EndpointURL    string `json:"endpoint_url"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+}
+
+// RegisterCustomNodeType registers a custom node type for a device
+func (h *Handlers) RegisterCustomNodeType(c *fiber.Ctx) error {
+	var req RegisterCustomNodeTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" || req.TypeName == "" || req.EndpointURL == "" {
+		return h.errorResponse(c, 400, "id_device, type_name and endpoint_url are required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	nodeType, err := h.customNodeService.RegisterType(req.IDDevice, req.TypeName, req.EndpointURL, req.TimeoutSeconds)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to register custom node type")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, nodeType)
+}
+
+// GetCustomNodeTypes returns the device's registered node types
+func (h *Handlers) GetCustomNodeTypes(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	nodeTypes, err := h.customNodeService.GetTypes(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get custom node types")
+		return h.errorResponse(c, 500, "Failed to get custom node types")
+	}
+
+	return h.successResponse(c, nodeTypes)
+}
+
+// UpdateCustomNodeTypeRequest toggles a registration
+type UpdateCustomNodeTypeRequest struct {
+	IDDevice string `json:"id_device"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// UpdateCustomNodeType enables or disables a registered node type
+func (h *Handlers) UpdateCustomNodeType(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req UpdateCustomNodeTypeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.customNodeService.SetTypeEnabled(id, req.IDDevice, req.Enabled); err != nil {
+		logrus.WithError(err).Error("Failed to update custom node type")
+		return h.errorResponse(c, 500, "Failed to update custom node type")
+	}
+
+	return h.successResponse(c, fiber.Map{"id": id, "enabled": req.Enabled})
+}
+
+// DeleteCustomNodeType removes a registered node type. The owning device is
+// passed as ?id_device= so the delete stays scoped to devices the caller can
+// access
+func (h *Handlers) DeleteCustomNodeType(c *fiber.Ctx) error {
+	id := c.Params("id")
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.customNodeService.DeleteType(id, idDevice); err != nil {
+		logrus.WithError(err).Error("Failed to delete custom node type")
+		return h.errorResponse(c, 500, "Failed to delete custom node type")
+	}
+
+	return h.successResponse(c, fiber.Map{"id": id, "deleted": true})
+}
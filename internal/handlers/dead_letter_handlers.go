@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"strconv"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Dead-letter queue handlers: operators list webhook messages that exhausted
+// their retries and either requeue them through the normal pipeline or
+// discard them
+
+// ListDeadLetters returns the newest dead-lettered messages for a device
+func (h *Handlers) ListDeadLetters(c *fiber.Ctx) error {
+	if h.deadLetterService == nil {
+		return h.errorResponse(c, 503, "Dead letter service not available")
+	}
+
+	deviceID := c.Query("device_id")
+	if deviceID == "" {
+		return h.errorResponse(c, 400, "device_id query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, deviceID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	messages, err := h.deadLetterService.List(deviceID, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list dead-lettered messages")
+		return h.errorResponse(c, 500, "Failed to list dead-lettered messages")
+	}
+
+	return h.successResponse(c, messages)
+}
+
+// RequeueDeadLetter runs a dead-lettered message through the webhook
+// pipeline again and removes it from the table
+func (h *Handlers) RequeueDeadLetter(c *fiber.Ctx) error {
+	if h.deadLetterService == nil {
+		return h.errorResponse(c, 503, "Dead letter service not available")
+	}
+	if h.whatsappService == nil {
+		return h.errorResponse(c, 503, "WhatsApp service not available")
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid message ID")
+	}
+
+	message, err := h.deadLetterService.Get(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get dead-lettered message")
+		return h.errorResponse(c, 500, "Failed to get dead-lettered message")
+	}
+	if message == nil {
+		return h.errorResponse(c, 404, "Dead-lettered message not found")
+	}
+	if err := h.requireDeviceAccess(c, message.DeviceID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.whatsappService.ProcessIncomingMessageFromWebhook(
+		message.ProspectNum, message.Content, message.DeviceID, message.Provider, message.SenderName,
+	); err != nil {
+		logrus.WithError(err).Error("Failed to requeue dead-lettered message")
+		return h.errorResponse(c, 500, "Failed to requeue message")
+	}
+
+	if err := h.deadLetterService.Remove(id); err != nil {
+		logrus.WithError(err).Error("Failed to remove requeued dead-lettered message")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":        id,
+		"device_id": message.DeviceID,
+	}).Info("🔁 DEAD LETTER: Message requeued by operator")
+
+	return h.successMessageResponse(c, "Message requeued successfully", nil)
+}
+
+// DiscardDeadLetter deletes a dead-lettered message without reprocessing it
+func (h *Handlers) DiscardDeadLetter(c *fiber.Ctx) error {
+	if h.deadLetterService == nil {
+		return h.errorResponse(c, 503, "Dead letter service not available")
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid message ID")
+	}
+
+	message, err := h.deadLetterService.Get(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get dead-lettered message")
+		return h.errorResponse(c, 500, "Failed to get dead-lettered message")
+	}
+	if message == nil {
+		return h.errorResponse(c, 404, "Dead-lettered message not found")
+	}
+	if err := h.requireDeviceAccess(c, message.DeviceID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.deadLetterService.Remove(id); err != nil {
+		logrus.WithError(err).Error("Failed to discard dead-lettered message")
+		return h.errorResponse(c, 500, "Failed to discard message")
+	}
+
+	return h.successMessageResponse(c, "Message discarded", nil)
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"bytes"
 	"database/sql"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,6 +14,7 @@ import (
 	"regexp"
 
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -38,11 +38,21 @@ func (h *Handlers) GetDeviceSettings(c *fiber.Ctx) error {
 		return h.errorResponse(c, 401, "Authentication required")
 	}
 
-	// Get device settings filtered by user ID
-	settings, err := h.deviceSettingsService.GetByUserIDString(userIDStr)
-	if err != nil {
-		logrus.WithError(err).WithField("userID", userIDStr).Error("Failed to get device settings")
-		return h.errorResponse(c, 500, "Failed to retrieve device settings")
+	// Get device settings for the user plus everyone sharing an organization
+	// with them, so teams see each other's devices
+	teamIDs := []string{userIDStr}
+	if h.organizationService != nil {
+		teamIDs = h.organizationService.TeamUserIDs(userIDStr)
+	}
+
+	var settings []*models.DeviceSettings
+	for _, teamID := range teamIDs {
+		teamSettings, err := h.deviceSettingsService.GetByUserIDString(teamID)
+		if err != nil {
+			logrus.WithError(err).WithField("userID", teamID).Error("Failed to get device settings")
+			return h.errorResponse(c, 500, "Failed to retrieve device settings")
+		}
+		settings = append(settings, teamSettings...)
 	}
 
 	return h.successResponse(c, settings)
@@ -71,8 +81,9 @@ func (h *Handlers) GetDeviceSettingsById(c *fiber.Ctx) error {
 		return h.errorResponse(c, 500, "Failed to retrieve device setting")
 	}
 
-	// Check if the device setting belongs to the authenticated user
-	if setting.UserID.Valid && setting.UserID.String != userIDStr {
+	// Check if the user owns the device setting or shares an organization
+	// with its owner (viewer role is enough for read access)
+	if !h.canAccessDeviceSetting(setting, userIDStr, models.OrgRoleViewer) {
 		logrus.WithFields(logrus.Fields{
 			"userID":        userIDStr,
 			"settingUserID": setting.UserID.String,
@@ -169,8 +180,8 @@ func (h *Handlers) UpdateDeviceSettings(c *fiber.Ctx) error {
 		return h.errorResponse(c, 500, "Failed to retrieve device setting")
 	}
 
-	// Check ownership
-	if existingSetting.UserID.Valid && existingSetting.UserID.String != userIDStr {
+	// Check ownership; organization agents and above may update team devices
+	if !h.canAccessDeviceSetting(existingSetting, userIDStr, models.OrgRoleAgent) {
 		logrus.WithFields(logrus.Fields{
 			"userID":        userIDStr,
 			"settingUserID": existingSetting.UserID.String,
@@ -225,8 +236,8 @@ func (h *Handlers) DeleteDeviceSettings(c *fiber.Ctx) error {
 		return h.errorResponse(c, 500, "Failed to retrieve device setting")
 	}
 
-	// Check ownership
-	if existingSetting.UserID.Valid && existingSetting.UserID.String != userIDStr {
+	// Check ownership; deleting a team device requires organization admin
+	if !h.canAccessDeviceSetting(existingSetting, userIDStr, models.OrgRoleAdmin) {
 		logrus.WithFields(logrus.Fields{
 			"userID":        userIDStr,
 			"settingUserID": existingSetting.UserID.String,
@@ -567,18 +578,83 @@ func (h *Handlers) GenerateWhacenterDevice(c *fiber.Ctx) error {
 }
 
 // HandleWebhook processes incoming webhook requests from WhatsApp providers with comprehensive monitoring
+// Webhook bodies are copied out of fiber's request buffer before async
+// processing. At fleet scale those per-request copies dominate handler
+// allocations, so the copies come from a pool and are returned as soon as the
+// payload has been decoded; anything over the cap is rejected outright
+const maxWebhookBodyBytes = 1 << 20 // 1 MiB
+
+var webhookBodyPool = sync.Pool{
+	New: func() interface{} {
+		return make([]byte, 0, 4096)
+	},
+}
+
+// acquireWebhookBuffer returns a pooled byte slice of length n
+func acquireWebhookBuffer(n int) []byte {
+	buf := webhookBodyPool.Get().([]byte)
+	if cap(buf) < n {
+		buf = make([]byte, n)
+	}
+	return buf[:n]
+}
+
+// releaseWebhookBuffer returns a buffer to the pool for reuse
+func releaseWebhookBuffer(buf []byte) {
+	if cap(buf) > maxWebhookBodyBytes {
+		return
+	}
+	webhookBodyPool.Put(buf[:0])
+}
+
 func (h *Handlers) HandleWebhook(c *fiber.Ctx) error {
 	// Extract all data before returning
 	idDevice := c.Params("id_device")
 	instance := c.Params("instance")
 
-	// Copy body immediately
 	body := c.Body()
-	bodyCopy := make([]byte, len(body))
+	if len(body) > maxWebhookBodyBytes {
+		logrus.WithFields(logrus.Fields{
+			"id_device": idDevice,
+			"body_size": len(body),
+		}).Warn("📨 WEBHOOK: Payload exceeds size cap, rejecting")
+		return c.Status(413).JSON(fiber.Map{
+			"status":  "error",
+			"message": "payload too large",
+		})
+	}
+
+	// Copy the body into a pooled buffer; fiber reuses its request buffers
+	// once the handler returns
+	bodyCopy := acquireWebhookBuffer(len(body))
 	copy(bodyCopy, body)
 
+	// Reject forged payloads before anything is enqueued. Devices without a
+	// configured secret keep accepting unsigned webhooks
+	if h.webhookSignatureService != nil {
+		if err := h.webhookSignatureService.VerifyRequest(idDevice, bodyCopy, func(name string) string {
+			return c.Get(name)
+		}); err != nil {
+			releaseWebhookBuffer(bodyCopy)
+			return c.Status(401).JSON(fiber.Map{
+				"status":  "error",
+				"message": "invalid webhook signature",
+			})
+		}
+	}
+
+	// Decode before queuing so the goroutine only holds the parsed fields,
+	// not the raw body; the buffer goes straight back to the pool
+	var webhookData map[string]interface{}
+	if err := json.Unmarshal(bodyCopy, &webhookData); err != nil {
+		logrus.WithError(err).Warn("Failed to parse webhook data")
+		webhookData = make(map[string]interface{})
+	}
+	bodySize := len(bodyCopy)
+	releaseWebhookBuffer(bodyCopy)
+
 	// Launch async processing BEFORE returning
-	go h.processWebhookAsync(idDevice, instance, bodyCopy)
+	go h.processWebhookAsync(idDevice, instance, webhookData, bodySize)
 
 	// Return 200 OK immediately
 	return c.Status(200).JSON(fiber.Map{
@@ -588,12 +664,12 @@ func (h *Handlers) HandleWebhook(c *fiber.Ctx) error {
 }
 
 // processWebhookAsync handles the actual webhook processing
-func (h *Handlers) processWebhookAsync(idDevice, instance string, body []byte) {
+func (h *Handlers) processWebhookAsync(idDevice, instance string, webhookData map[string]interface{}, bodySize int) {
 	// Log
 	logrus.WithFields(logrus.Fields{
 		"id_device": idDevice,
 		"instance":  instance,
-		"body_size": len(body),
+		"body_size": bodySize,
 	}).Info("📨 WEBHOOK: Async processing started")
 
 	// Validate
@@ -609,19 +685,18 @@ func (h *Handlers) processWebhookAsync(idDevice, instance string, body []byte) {
 		return
 	}
 
-	// Parse webhook data
-	var webhookData map[string]interface{}
-	if err := json.Unmarshal(body, &webhookData); err != nil {
-		logrus.WithError(err).Warn("Failed to parse webhook data")
-		webhookData = make(map[string]interface{})
-	}
-
 	// Log parsed data
 	logrus.WithFields(logrus.Fields{
 		"webhook_data": webhookData,
 		"id_device":    idDevice,
 	}).Info("📨 WEBHOOK DATA RECEIVED")
 
+	// Intercept message edit/delete events before the normal message pipeline
+	if eventType := extractMessageEventType(webhookData); eventType != "" {
+		h.handleMessageEvent(eventType, webhookData, idDevice, deviceSettings.Provider)
+		return
+	}
+
 	// Process the message
 	err = h.processWebhookMessageWithRetry(webhookData, idDevice, deviceSettings.Provider)
 	if err != nil {
@@ -960,15 +1035,12 @@ func (h *Handlers) GetDeviceStatus(c *fiber.Ctx) error {
 		"details":      map[string]interface{}{},
 	}
 
-	// Check status based on provider
-	switch device.Provider {
-	case "whacenter":
-		logrus.Info("[STATUS] Checking Whacenter status")
-		status = h.checkWhacenterStatus(device, status)
-	case "wablas":
-		logrus.Info("[STATUS] Checking Wablas status")
-		status = h.checkWablasStatus(device, status)
-	default:
+	// Check status through the registered provider plugin
+	if provider, ok := services.GetProvider(strings.ToLower(device.Provider)); ok {
+		logrus.WithField("provider", provider.Name()).Info("[STATUS] Checking provider status")
+		status = provider.GetStatus(device)
+		status["device_id"] = deviceID
+	} else {
 		logrus.WithField("provider", device.Provider).Warn("[STATUS] Unsupported provider")
 		status["status"] = "unsupported_provider"
 		status["details"] = map[string]interface{}{
@@ -976,501 +1048,142 @@ func (h *Handlers) GetDeviceStatus(c *fiber.Ctx) error {
 		}
 	}
 
-	logrus.WithField("final_status", status).Info("[STATUS] Returning final status")
-	return h.successResponse(c, status)
-}
-
-// checkWhacenterStatus checks the status of a Whacenter device
-func (h *Handlers) checkWhacenterStatus(device *models.DeviceSettings, status map[string]interface{}) map[string]interface{} {
-	logrus.WithFields(logrus.Fields{
-		"device_id":      device.ID,
-		"instance_valid": device.Instance.Valid,
-		"instance_value": device.Instance.String,
-	}).Info("[WHACENTER] Starting Whacenter status check")
-
-	if !device.Instance.Valid || device.Instance.String == "" {
-		logrus.Error("[WHACENTER] Device instance not configured")
-		status["status"] = "not_configured"
-		status["details"] = map[string]interface{}{
-			"error": "Device instance not configured",
-		}
-		return status
-	}
-
-	// Make API call to check Whacenter device status using the correct endpoint
-	client := &http.Client{Timeout: 10 * time.Second}
-	// Use the hardcoded API key for whacenter requests
-	whacenterAPIKey := "abebe840-156c-441c-8252-da0342c5a07c"
-	// Use the correct statusDevice API endpoint with device_id and api_key parameters
-	apiURL := fmt.Sprintf("https://api.whacenter.com/api/statusDevice?api_key=%s&device_id=%s",
-		whacenterAPIKey, url.QueryEscape(device.Instance.String))
-
-	logrus.WithFields(logrus.Fields{
-		"api_url": apiURL,
-	}).Info("[WHACENTER] Making API request")
-
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		logrus.WithError(err).Error("[WHACENTER] Failed to create HTTP request")
-		status["status"] = "error"
-		status["details"] = map[string]interface{}{
-			"error":   "Failed to create status request",
-			"details": err.Error(),
-		}
-		return status
-	}
-
-	// No authorization header needed for statusDevice endpoint
-	req.Header.Set("Accept", "application/json")
-
-	logrus.WithFields(logrus.Fields{
-		"headers": req.Header,
-	}).Info("[WHACENTER] Request headers set")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("[WHACENTER] HTTP request failed")
-		status["status"] = "connection_error"
-		status["details"] = map[string]interface{}{
-			"error":   "Failed to connect to Whacenter API",
-			"details": err.Error(),
+	// Raise the disconnect notification when the status transitioned
+	if h.notificationService != nil {
+		if statusText, ok := status["status"].(string); ok {
+			h.notificationService.NotifyDeviceStatus(deviceID, statusText)
 		}
-		return status
 	}
-	defer resp.Body.Close()
 
-	logrus.WithFields(logrus.Fields{
-		"status_code": resp.StatusCode,
-		"headers":     resp.Header,
-	}).Info("[WHACENTER] Received API response")
+	logrus.WithField("final_status", status).Info("[STATUS] Returning final status")
+	return h.successResponse(c, status)
+}
 
-	// Read response body for logging
-	bodyBytes, err := io.ReadAll(resp.Body)
+// DebugDevices returns all device settings for debugging
+func (h *Handlers) DebugDevices(c *fiber.Ctx) error {
+	devices, err := h.deviceSettingsService.GetAll()
 	if err != nil {
-		logrus.WithError(err).Error("[WHACENTER] Failed to read response body")
-		status["status"] = "error"
-		status["details"] = map[string]interface{}{
-			"error":   "Failed to read API response",
-			"details": err.Error(),
-		}
-		return status
+		return h.errorResponse(c, 500, "Failed to get device settings")
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"response_body": string(bodyBytes),
-		"body_length":   len(bodyBytes),
-	}).Info("[WHACENTER] Response body received")
-
-	if resp.StatusCode == 200 {
-		var apiResponse map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &apiResponse); err == nil {
-			logrus.WithField("parsed_response", apiResponse).Info("[WHACENTER] Successfully parsed JSON response")
-
-			// Parse the response according to statusDevice API format
-			if data, ok := apiResponse["data"].(map[string]interface{}); ok {
-				if deviceStatus, ok := data["status"].(string); ok {
-					logrus.WithField("device_status", deviceStatus).Info("[WHACENTER] Found device status")
-
-					if deviceStatus == "NOT CONNECTED" {
-						status["connected"] = false
-						status["status"] = "disconnected"
-
-						// Fetch QR code when device is not connected
-						qrCode := h.getWhacenterQRCode(device.Instance.String)
-						if qrCode != "" {
-							status["qr_code"] = qrCode
-						}
-					} else {
-						status["connected"] = true
-						status["status"] = "connected"
-					}
-					status["device_status"] = deviceStatus
-				} else {
-					logrus.Warn("[WHACENTER] No 'status' field found in data")
-					status["status"] = "unknown"
-				}
-				status["details"] = data
-			} else {
-				logrus.Warn("[WHACENTER] No 'data' field found in response")
-				status["status"] = "invalid_response"
-				status["details"] = apiResponse
-			}
-		} else {
-			logrus.WithError(err).Error("[WHACENTER] Failed to parse JSON response")
-			status["status"] = "parse_error"
-			status["details"] = map[string]interface{}{
-				"error":        "Failed to parse API response",
-				"raw_response": string(bodyBytes),
-				"parse_error":  err.Error(),
-			}
-		}
-	} else if resp.StatusCode == 404 {
-		// Handle 404 specifically - device not found in Whacenter
-		logrus.WithFields(logrus.Fields{
-			"device_instance": device.Instance.String,
-			"api_url":         apiURL,
-		}).Warn("[WHACENTER] Device not found in Whacenter system")
-
-		status["status"] = "device_not_found"
-		status["connected"] = false
-		status["details"] = map[string]interface{}{
-			"error":           "Device not found in Whacenter system",
-			"message":         "The device may have been deleted from Whacenter or the device ID is incorrect",
-			"device_instance": device.Instance.String,
-			"http_status":     404,
-			"response_body":   string(bodyBytes),
-			"suggestion":      "Please regenerate the device or check if it exists in your Whacenter dashboard",
-		}
-	} else {
-		logrus.WithFields(logrus.Fields{
-			"status_code":   resp.StatusCode,
-			"response_body": string(bodyBytes),
-		}).Error("[WHACENTER] API returned non-200 status")
-
-		status["status"] = "api_error"
-		status["details"] = map[string]interface{}{
-			"http_status":   resp.StatusCode,
-			"error":         "API returned error status",
-			"response_body": string(bodyBytes),
+	// Create a simplified view for debugging
+	var debugData []map[string]interface{}
+	for _, device := range devices {
+		data := map[string]interface{}{
+			"id":           device.ID,
+			"provider":     device.Provider,
+			"id_device":    getStringFromNullString(device.IDDevice),
+			"instance":     getStringFromNullString(device.Instance),
+			"device_id":    getStringFromNullString(device.DeviceID),
+			"phone_number": getStringFromNullString(device.PhoneNumber),
+			"created_at":   device.CreatedAt,
 		}
+		debugData = append(debugData, data)
 	}
 
-	logrus.WithField("final_status", status).Info("[WHACENTER] Returning status")
-	return status
+	return h.successResponse(c, map[string]interface{}{
+		"total_devices": len(devices),
+		"devices":       debugData,
+	})
 }
 
-// getWhacenterQRCode fetches QR code for Whacenter device when not connected
-func (h *Handlers) getWhacenterQRCode(deviceID string) string {
-	logrus.WithField("device_id", deviceID).Info("[WHACENTER] Fetching QR code")
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	// Use the hardcoded API key for whacenter requests
-	whacenterAPIKey := "abebe840-156c-441c-8252-da0342c5a07c"
-	qrURL := fmt.Sprintf("https://api.whacenter.com/api/qr?api_key=%s&device_id=%s",
-		whacenterAPIKey, url.QueryEscape(deviceID))
-
-	req, err := http.NewRequest("GET", qrURL, nil)
-	if err != nil {
-		logrus.WithError(err).Error("[WHACENTER] Failed to create QR request")
-		return ""
-	}
-
-	// Accept both JSON and image responses
-	req.Header.Set("Accept", "application/json, image/png")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("[WHACENTER] QR request failed")
-		return ""
-	}
-	defer resp.Body.Close()
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("[WHACENTER] Failed to read QR response")
-		return ""
+// Helper function to convert sql.NullString to string
+// extractMessageEventType returns "edit" or "delete" when the webhook payload is a
+// message modification event instead of a new inbound message
+func extractMessageEventType(webhookData map[string]interface{}) string {
+	eventValue := ""
+	if ev, ok := webhookData["event"].(string); ok {
+		eventValue = ev
+	} else if action, ok := webhookData["action"].(string); ok {
+		eventValue = action
 	}
 
-	if resp.StatusCode == 200 {
-		// Check if response is a PNG image (like in the PHP code)
-		// PNG signature: \x89PNG\r\n\x1a\n (first 8 bytes)
-		logrus.WithFields(logrus.Fields{
-			"response_length": len(bodyBytes),
-			"first_8_bytes":   fmt.Sprintf("%x", bodyBytes[:min(8, len(bodyBytes))]),
-			"content_type":    resp.Header.Get("Content-Type"),
-		}).Info("[WHACENTER] Analyzing QR response format")
-
-		if len(bodyBytes) >= 8 {
-			// Check for PNG signature: first byte is 0x89, followed by "PNG"
-			if bodyBytes[0] == 0x89 && string(bodyBytes[1:4]) == "PNG" {
-				// It's a valid PNG image, convert to base64 data URL
-				logrus.Info("[WHACENTER] Successfully fetched QR code as PNG image")
-				return fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(bodyBytes))
-			}
-		}
-
-		// If not PNG, try to parse as JSON response
-		logrus.Info("[WHACENTER] Response is not PNG format, trying JSON parsing")
-		var qrResponse map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &qrResponse); err == nil {
-			logrus.WithField("json_response", qrResponse).Info("[WHACENTER] Successfully parsed JSON response")
-			if data, ok := qrResponse["data"].(map[string]interface{}); ok {
-				if qrCode, ok := data["qr"].(string); ok {
-					logrus.Info("[WHACENTER] Successfully fetched QR code from JSON")
-					return qrCode
-				}
-				logrus.Warn("[WHACENTER] No 'qr' field found in JSON data")
-			} else {
-				logrus.Warn("[WHACENTER] No 'data' field found in JSON response")
-			}
-		} else {
-			logrus.WithError(err).Warn("[WHACENTER] Failed to parse response as JSON")
-			// Log raw response for debugging
-			logrus.WithField("raw_response", string(bodyBytes[:min(200, len(bodyBytes))])).Warn("[WHACENTER] Raw response preview")
-		}
+	switch strings.ToLower(eventValue) {
+	case "message.edited", "message_edited", "edited", "edit":
+		return "edit"
+	case "message.revoked", "message_revoked", "message_deleted", "revoked", "deleted", "delete":
+		return "delete"
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"status_code":     resp.StatusCode,
-		"response_length": len(bodyBytes),
-		"content_type":    resp.Header.Get("Content-Type"),
-	}).Warn("[WHACENTER] Failed to fetch QR code")
-
 	return ""
 }
 
-// checkWablasStatus checks the status of a Wablas device
-func (h *Handlers) checkWablasStatus(device *models.DeviceSettings, status map[string]interface{}) map[string]interface{} {
-	logrus.WithFields(logrus.Fields{
-		"device_id":      device.ID,
-		"instance_valid": device.Instance.Valid,
-		"instance_value": device.Instance.String,
-	}).Info("[WABLAS] Starting Wablas status check")
-
-	// Check if instance (API key) is configured
-	if !device.Instance.Valid || device.Instance.String == "" {
-		logrus.Error("[WABLAS] Device instance not configured")
-		status["status"] = "NOT CONNECTED"
-		status["qr"] = "timeout"
-		status["details"] = map[string]interface{}{
-			"error": "Device instance not configured",
-		}
-		return status
-	}
-
-	// Extract token from instance - following PHP pattern: $token = explode('.', $auth_header)[0];
-	authHeader := device.Instance.String
-	var token string
-	if strings.Contains(authHeader, ".") {
-		parts := strings.Split(authHeader, ".")
-		token = parts[0]
-	} else {
-		token = authHeader // Use full string if no dot found
+// handleMessageEvent processes message edit/delete events: updates stored conversation
+// history, flags the change in the inbox, and re-evaluates the current flow step when
+// the edited message was the pending user input
+func (h *Handlers) handleMessageEvent(eventType string, webhookData map[string]interface{}, idDevice, provider string) {
+	// Extract the prospect number using the same fields as the normal pipeline
+	from := ""
+	if fromVal, ok := webhookData["from"].(string); ok {
+		from = fromVal
+	} else if phoneVal, ok := webhookData["phone"].(string); ok {
+		from = phoneVal
 	}
 
-	// **STEP 1: CHECK DEVICE STATUS** - following PHP pattern
-	client := &http.Client{Timeout: 10 * time.Second}
-	apiURL := fmt.Sprintf("https://my.wablas.com/api/device/info?token=%s", url.QueryEscape(token))
-
-	// Log API request (without sensitive token details)
-	logrus.WithFields(logrus.Fields{
-		"api_url":      "https://my.wablas.com/api/device/info",
-		"token_prefix": token[:min(8, len(token))] + "...",
-	}).Info("[WABLAS] Making API request")
-
-	logrus.WithFields(logrus.Fields{
-		"api_url":      apiURL,
-		"token_prefix": token[:min(8, len(token))] + "...",
-	}).Info("[WABLAS] Making API request")
-
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		logrus.WithError(err).Error("[WABLAS] Failed to create HTTP request")
-		status["status"] = "NOT CONNECTED"
-		status["qr"] = "timeout"
-		status["details"] = map[string]interface{}{
-			"error":   "Failed to create status request",
-			"details": err.Error(),
-		}
-		return status
+	if from == "" {
+		logrus.WithField("id_device", idDevice).Warn("⚠️ WEBHOOK: Message event missing 'from' field, ignoring")
+		return
 	}
 
-	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("[WABLAS] HTTP request failed")
-		status["status"] = "NOT CONNECTED"
-		status["qr"] = "timeout"
-		status["details"] = map[string]interface{}{
-			"error":   "Failed to connect to Wablas API",
-			"details": err.Error(),
-		}
-		return status
+	newText := ""
+	if msgVal, ok := webhookData["message"].(string); ok {
+		newText = msgVal
+	} else if bodyVal, ok := webhookData["body"].(string); ok {
+		newText = bodyVal
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("[WABLAS] Failed to read response body")
-		status["status"] = "NOT CONNECTED"
-		status["qr"] = "timeout"
-		status["details"] = map[string]interface{}{
-			"error":   "Failed to read API response",
-			"details": err.Error(),
-		}
-		return status
+	// Some providers include the previous text so we can match the exact history entry
+	oldText := ""
+	if oldVal, ok := webhookData["edited_text"].(string); ok {
+		oldText = oldVal
+	} else if oldVal, ok := webhookData["previous_message"].(string); ok {
+		oldText = oldVal
 	}
 
 	logrus.WithFields(logrus.Fields{
-		"status_code":   resp.StatusCode,
-		"response_body": string(bodyBytes),
-	}).Info("[WABLAS] Received API response")
-
-	if resp.StatusCode != 200 {
-		logrus.WithFields(logrus.Fields{
-			"status_code":   resp.StatusCode,
-			"response_body": string(bodyBytes),
-		}).Error("[WABLAS] API returned non-200 status")
-
-		status["status"] = "NOT CONNECTED"
-		status["qr"] = "timeout"
-		status["details"] = map[string]interface{}{
-			"http_status":   resp.StatusCode,
-			"error":         "API returned error status",
-			"response_body": string(bodyBytes),
-		}
-		return status
-	}
-
-	// **Decode JSON Response** - following PHP pattern
-	var data map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &data); err != nil {
-		logrus.WithError(err).Error("[WABLAS] Failed to parse JSON response")
-		status["status"] = "NOT CONNECTED"
-		status["qr"] = "timeout"
-		status["details"] = map[string]interface{}{
-			"error":        "Failed to parse API response",
-			"raw_response": string(bodyBytes),
-			"parse_error":  err.Error(),
-		}
-		return status
-	}
+		"id_device":  idDevice,
+		"from":       from,
+		"event_type": eventType,
+	}).Info("📨 WEBHOOK: Processing message event")
 
-	// Check API response status - following PHP pattern
-	if apiStatus, ok := data["status"].(bool); !ok || !apiStatus {
-		logrus.Warn("[WABLAS] API response status is false or missing")
-		status["status"] = "NOT CONNECTED"
-		status["qr"] = "timeout"
-		status["details"] = data
-		return status
+	if h.messageEventService == nil {
+		logrus.Error("❌ WEBHOOK: Message event service not available")
+		return
 	}
 
-	// **Extract Device Status** - following PHP pattern
-	var deviceStatus string = "UNKNOWN"
-	var deviceID string
-	var image interface{} = nil
-
-	if dataObj, ok := data["data"].(map[string]interface{}); ok {
-		if ds, ok := dataObj["status"].(string); ok {
-			deviceStatus = ds
-		}
-		if serial, ok := dataObj["serial"].(string); ok {
-			deviceID = serial
+	switch eventType {
+	case "edit":
+		pendingInput, err := h.messageEventService.HandleMessageEdited(from, idDevice, oldText, newText)
+		if err != nil {
+			logrus.WithError(err).Error("❌ WEBHOOK: Failed to handle message edit event")
+			return
 		}
-	}
-
-	// **STEP 2: FETCH QR CODE IF NOT CONNECTED** - following PHP pattern
-	if deviceStatus == "disconnected" && deviceID != "" {
-		qrURL := fmt.Sprintf("https://my.wablas.com/api/device/scan?token=%s", url.QueryEscape(token))
-		image = qrURL
-	}
-
-	// **Return Final Response** - following PHP pattern
-	status["status"] = deviceStatus
-	status["provider"] = "wablas"
-	if dataObj, ok := data["data"].(map[string]interface{}); ok {
-		status["data"] = dataObj
-	} else {
-		status["data"] = map[string]interface{}{}
-	}
-	if image != nil {
-		status["image"] = image
-		status["qr"] = image // Also set qr field for compatibility
-	} else {
-		status["image"] = nil
-		status["qr"] = nil
-	}
-	if message, ok := data["message"].(string); ok {
-		status["message"] = message
-	} else {
-		status["message"] = "No message returned"
-	}
 
-	logrus.WithField("final_status", status).Info("[WABLAS] Returning status")
-	return status
-}
-
-// getWablasQRCode fetches QR code from Wablas API when device is disconnected
-func (h *Handlers) getWablasQRCode(token string) string {
-	client := &http.Client{Timeout: 10 * time.Second}
-	qrURL := fmt.Sprintf("https://my.wablas.com/api/device/scan?token=%s", url.QueryEscape(token))
-
-	logrus.WithField("qr_url", qrURL).Info("[WABLAS] Fetching QR code")
-
-	req, err := http.NewRequest("GET", qrURL, nil)
-	if err != nil {
-		logrus.WithError(err).Error("[WABLAS] Failed to create QR request")
-		return ""
-	}
-
-	req.Header.Set("Authorization", token)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("[WABLAS] Failed to fetch QR code")
-		return ""
-	}
-	defer resp.Body.Close()
+		// Re-evaluate the current flow step with the corrected text when the edited
+		// message was the input the flow is waiting on
+		if pendingInput && newText != "" && h.whatsappService != nil {
+			senderName := "User"
+			if senderNameVal, ok := webhookData["sender_name"].(string); ok && senderNameVal != "" {
+				senderName = senderNameVal
+			}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("[WABLAS] Failed to read QR response body")
-		return ""
-	}
+			logrus.WithFields(logrus.Fields{
+				"id_device": idDevice,
+				"from":      from,
+			}).Info("✏️ WEBHOOK: Edited message was pending user input, re-evaluating flow step")
 
-	if resp.StatusCode == 200 {
-		var qrResponse map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &qrResponse); err == nil {
-			if data, ok := qrResponse["data"].(map[string]interface{}); ok {
-				if qrCode, ok := data["qr"].(string); ok {
-					logrus.WithField("qr_code_length", len(qrCode)).Info("[WABLAS] QR code fetched successfully")
-					return qrCode
-				}
+			if err := h.whatsappService.ProcessIncomingMessageFromWebhook(from, newText, idDevice, provider, senderName); err != nil {
+				logrus.WithError(err).Error("❌ WEBHOOK: Failed to re-evaluate flow for edited message")
 			}
 		}
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"status_code":   resp.StatusCode,
-		"response_body": string(bodyBytes),
-	}).Warn("[WABLAS] Failed to get QR code")
-
-	return ""
-}
-
-// DebugDevices returns all device settings for debugging
-func (h *Handlers) DebugDevices(c *fiber.Ctx) error {
-	devices, err := h.deviceSettingsService.GetAll()
-	if err != nil {
-		return h.errorResponse(c, 500, "Failed to get device settings")
-	}
 
-	// Create a simplified view for debugging
-	var debugData []map[string]interface{}
-	for _, device := range devices {
-		data := map[string]interface{}{
-			"id":           device.ID,
-			"provider":     device.Provider,
-			"id_device":    getStringFromNullString(device.IDDevice),
-			"instance":     getStringFromNullString(device.Instance),
-			"device_id":    getStringFromNullString(device.DeviceID),
-			"phone_number": getStringFromNullString(device.PhoneNumber),
-			"created_at":   device.CreatedAt,
+	case "delete":
+		if err := h.messageEventService.HandleMessageDeleted(from, idDevice, newText); err != nil {
+			logrus.WithError(err).Error("❌ WEBHOOK: Failed to handle message delete event")
 		}
-		debugData = append(debugData, data)
 	}
-
-	return h.successResponse(c, map[string]interface{}{
-		"total_devices": len(devices),
-		"devices":       debugData,
-	})
 }
 
-// Helper function to convert sql.NullString to string
 // processWebhookMessageWithRetry processes incoming webhook messages with error handling for retry logic
 func (h *Handlers) processWebhookMessageWithRetry(webhookData map[string]interface{}, idDevice, provider string) error {
 	defer func() {
@@ -1492,13 +1205,25 @@ func (h *Handlers) processWebhookMessageWithRetry(webhookData map[string]interfa
 // processWebhookMessage processes incoming webhook messages and integrates with AI WhatsApp service with performance monitoring
 func (h *Handlers) processWebhookMessage(webhookData map[string]interface{}, idDevice, provider string) error {
 	startTime := time.Now()
-	logrus.WithFields(logrus.Fields{
+
+	// Open the trace for this message; downstream stages attach child spans
+	// through the device/prospect correlation keys
+	traceSpan := services.Tracing().Begin("webhook.process", map[string]string{
+		"provider":  provider,
+		"device_id": idDevice,
+	}, services.DeviceTraceKey(idDevice))
+	defer services.Tracing().Finish(traceSpan)
+
+	logrus.WithFields(traceSpan.LogFields()).WithFields(logrus.Fields{
 		"id_device":             idDevice,
 		"provider":              provider,
-		"webhook_data":          webhookData,
 		"processing_start_time": startTime,
 	}).Info("🔄 WEBHOOK: Processing webhook message for AI integration with monitoring")
 
+	if h.webhookStatsService != nil {
+		h.webhookStatsService.RecordReceived(provider)
+	}
+
 	// Extract message data based on provider to get from/prospect number
 	var from, message, messageType, senderName string
 	var isGroup bool
@@ -1510,6 +1235,14 @@ func (h *Handlers) processWebhookMessage(webhookData map[string]interface{}, idD
 		from = phoneVal
 	}
 
+	if from != "" {
+		services.Tracing().Alias(traceSpan, services.TraceKey(idDevice, from))
+		traceSpan.SetAttribute("prospect_num", from)
+		if h.turnLatencyService != nil {
+			h.turnLatencyService.TurnStarted(idDevice, from)
+		}
+	}
+
 	// EXECUTION LOCK: Prevent duplicate parallel processing (matching PHP ZChatInput logic)
 	if from != "" && h.executionProcessRepo != nil {
 		// 1. Create new execution record
@@ -1664,12 +1397,42 @@ func (h *Handlers) processWebhookMessage(webhookData map[string]interface{}, idD
 			"check_percent": checkPercent,
 		}).Info("📨 WEBHOOK: Processing WAHA message through standardized flow routing")
 
-	default:
-		// Generic webhook format
-		if fromVal, ok := webhookData["from"].(string); ok {
-			from = fromVal
+	case "twilio":
+		// Twilio posts its native form field names (From/Body/ProfileName)
+		// with the sender in whatsapp:+E164 form
+		logrus.Info("🔍 WEBHOOK: Processing as Twilio provider")
+		if fromVal, ok := webhookData["From"].(string); ok {
+			from = strings.TrimPrefix(strings.TrimPrefix(fromVal, "whatsapp:"), "+")
+			logrus.WithField("from", from).Info("✅ TWILIO: Found 'From' field")
 		}
-		if msgVal, ok := webhookData["message"].(string); ok {
+		if msgVal, ok := webhookData["Body"].(string); ok {
+			message = msgVal
+			logrus.WithField("message", truncateString(message, 50)).Info("✅ TWILIO: Found 'Body' field")
+		}
+		// Media messages carry the first attachment URL instead of a body
+		if message == "" {
+			if mediaVal, ok := webhookData["MediaUrl0"].(string); ok && mediaVal != "" {
+				message = mediaVal
+				messageType = "image"
+				logrus.WithField("media_url", mediaVal).Info("✅ TWILIO: Using 'MediaUrl0' as message")
+			}
+		}
+		// Twilio's official API never delivers group chats to the webhook
+		isGroup = false
+
+		// Extract sender name from the WhatsApp profile
+		if senderNameVal, ok := webhookData["ProfileName"].(string); ok && senderNameVal != "" {
+			senderName = senderNameVal
+		} else {
+			senderName = "User" // Default fallback for Twilio
+		}
+
+	default:
+		// Generic webhook format
+		if fromVal, ok := webhookData["from"].(string); ok {
+			from = fromVal
+		}
+		if msgVal, ok := webhookData["message"].(string); ok {
 			message = msgVal
 		}
 		if msgTypeVal, ok := webhookData["message_type"].(string); ok {
@@ -1691,6 +1454,25 @@ func (h *Handlers) processWebhookMessage(webhookData map[string]interface{}, idD
 
 	// Validate required fields
 	if from == "" || message == "" {
+		missingFields := []string{}
+		if from == "" {
+			missingFields = append(missingFields, "from")
+		}
+		if message == "" {
+			missingFields = append(missingFields, "message")
+		}
+		if h.webhookStatsService != nil {
+			for _, field := range missingFields {
+				h.webhookStatsService.RecordParseFailure(provider, field)
+			}
+		}
+		if h.alertingService != nil {
+			h.alertingService.RecordParseError(idDevice, provider)
+		}
+		// Capture the raw payload (PII-masked) so the new format can be supported
+		if h.payloadCaptureService != nil {
+			go h.payloadCaptureService.CaptureUnknownPayload(idDevice, provider, missingFields, webhookData)
+		}
 		logrus.WithFields(logrus.Fields{
 			"id_device": idDevice,
 			"from":      from,
@@ -1701,6 +1483,9 @@ func (h *Handlers) processWebhookMessage(webhookData map[string]interface{}, idD
 
 	// Skip group messages if configured to do so
 	if isGroup {
+		if h.webhookStatsService != nil {
+			h.webhookStatsService.RecordGroupSkip(provider)
+		}
 		logrus.WithFields(logrus.Fields{
 			"id_device": idDevice,
 			"from":      from,
@@ -1811,6 +1596,9 @@ func (h *Handlers) processWebhookMessage(webhookData map[string]interface{}, idD
 					// Fallback to AI conversation if flow processing fails
 					h.processAIConversation(from, message, idDevice, provider, senderName, startTime)
 				} else {
+					if h.webhookStatsService != nil {
+						h.webhookStatsService.RecordProcessingLatency(provider, time.Since(startTime))
+					}
 					logrus.WithFields(logrus.Fields{
 						"id_device":                idDevice,
 						"flow_processing_duration": flowProcessingDuration,
@@ -1950,6 +1738,10 @@ func (h *Handlers) processAIConversation(from, message, idDevice, provider, send
 			totalProcessingTime := time.Since(aiProcessingStart)
 			totalRequestTime := time.Since(requestStartTime)
 
+			if h.webhookStatsService != nil {
+				h.webhookStatsService.RecordProcessingLatency(provider, totalRequestTime)
+			}
+
 			logrus.WithFields(logrus.Fields{
 				"id_device":                idDevice,
 				"to":                       from,
@@ -2238,6 +2030,248 @@ func (h *Handlers) GenerateWahaDevice(c *fiber.Ctx) error {
 	})
 }
 
+// GenerateMetaDevice registers a device using the Meta WhatsApp Cloud API.
+// Unlike the gateway providers there is nothing to provision remotely: the
+// number already exists in the Meta Business Manager, so this validates the
+// phone number ID + permanent token pair and stores them (with the optional
+// app secret for webhook signature verification) in the instance column
+func (h *Handlers) GenerateMetaDevice(c *fiber.Ctx) error {
+	// Get user ID from context
+	userIDStr := c.Locals("user_id").(string)
+
+	var req struct {
+		models.CreateDeviceSettingsRequest
+		PhoneNumberID string `json:"phone_number_id"`
+		AccessToken   string `json:"access_token"`
+		AppSecret     string `json:"app_secret"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	// Validate required fields
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "ID Device is required")
+	}
+	if req.PhoneNumberID == "" {
+		return h.errorResponse(c, 400, "Phone number ID is required")
+	}
+	if req.AccessToken == "" {
+		return h.errorResponse(c, 400, "Access token is required")
+	}
+
+	// Credentials are colon-delimited, so the segments must not contain one
+	if strings.Contains(req.PhoneNumberID, ":") || strings.Contains(req.AccessToken, ":") {
+		return h.errorResponse(c, 400, "Phone number ID and access token must not contain ':'")
+	}
+
+	// Verify the credentials against the Graph API before saving
+	client := &http.Client{Timeout: 30 * time.Second}
+	verifyURL := fmt.Sprintf("https://graph.facebook.com/v19.0/%s?fields=display_phone_number,verified_name", req.PhoneNumberID)
+	verifyRequest, err := http.NewRequest("GET", verifyURL, nil)
+	if err != nil {
+		return h.errorResponse(c, 500, "Failed to create verification request")
+	}
+	verifyRequest.Header.Set("Authorization", "Bearer "+req.AccessToken)
+
+	verifyResp, err := client.Do(verifyRequest)
+	if err != nil {
+		logrus.WithError(err).Error("❌ META: Failed to call Graph API for credential verification")
+		return h.errorResponse(c, 500, fmt.Sprintf("Failed to communicate with Meta Graph API: %v", err))
+	}
+	defer verifyResp.Body.Close()
+
+	verifyBody, err := io.ReadAll(verifyResp.Body)
+	if err != nil {
+		return h.errorResponse(c, 500, "Failed to read API response")
+	}
+
+	if verifyResp.StatusCode != 200 {
+		logrus.WithFields(logrus.Fields{
+			"status_code":   verifyResp.StatusCode,
+			"response_body": string(verifyBody),
+		}).Error("❌ META: Credential verification failed")
+		return h.errorResponse(c, 400, fmt.Sprintf("Meta rejected the credentials: %s", string(verifyBody)))
+	}
+
+	var numberData map[string]interface{}
+	if err := json.Unmarshal(verifyBody, &numberData); err != nil {
+		return h.errorResponse(c, 500, "Failed to parse API response")
+	}
+
+	displayPhoneNumber, _ := numberData["display_phone_number"].(string)
+	phoneNumber := req.PhoneNumber
+	if phoneNumber == "" {
+		phoneNumber = displayPhoneNumber
+	}
+
+	// Instance stores PHONE_NUMBER_ID:PERMANENT_TOKEN[:APP_SECRET]
+	instance := fmt.Sprintf("%s:%s", req.PhoneNumberID, req.AccessToken)
+	if req.AppSecret != "" {
+		instance = fmt.Sprintf("%s:%s", instance, req.AppSecret)
+	}
+
+	// Webhook endpoint to configure in the Meta app dashboard; the verify
+	// token for the subscription handshake is the device ID
+	webhook := fmt.Sprintf("https://nodepath-chat-production.up.railway.app/api/ai-whatsapp/webhook/meta/%s", req.IDDevice)
+
+	createReq := &models.CreateDeviceSettingsRequest{
+		UserID:       userIDStr,
+		APIKeyOption: req.APIKeyOption,
+		WebhookID:    webhook,
+		Provider:     "meta",
+		PhoneNumber:  phoneNumber,
+		APIKey:       req.APIKey, // Preserve the original OpenRouter API key
+		IDDevice:     req.IDDevice,
+		IDERP:        req.IDERP,
+		IDAdmin:      req.IDAdmin,
+		Instance:     instance,
+	}
+
+	// Upsert device setting in database (update if exists, create if not)
+	deviceSetting, err := h.deviceSettingsService.Upsert(createReq)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to save device setting to database")
+		return h.errorResponse(c, 500, "Failed to save device setting")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"provider":             "meta",
+		"device_setting_id":    deviceSetting.ID,
+		"phone_number_id":      req.PhoneNumberID,
+		"display_phone_number": displayPhoneNumber,
+		"webhook_url":          webhook,
+	}).Info("✅ META: Device generated successfully")
+
+	return h.successResponse(c, map[string]interface{}{
+		"success": true,
+		"message": "Device generated successfully via Meta Cloud API",
+		"data": map[string]interface{}{
+			"phone_number_id":      req.PhoneNumberID,
+			"display_phone_number": displayPhoneNumber,
+			"webhook_url":          webhook,
+			"verify_token":         req.IDDevice,
+			"provider":             "meta",
+		},
+	})
+}
+
+// GenerateTelegramDevice registers a device using a Telegram bot. The bot is
+// created with BotFather; this validates the token, points the bot's webhook
+// at our Telegram endpoint and stores the token in the instance column
+func (h *Handlers) GenerateTelegramDevice(c *fiber.Ctx) error {
+	// Get user ID from context
+	userIDStr := c.Locals("user_id").(string)
+
+	var req struct {
+		models.CreateDeviceSettingsRequest
+		BotToken string `json:"bot_token"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	// Validate required fields
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "ID Device is required")
+	}
+	if req.BotToken == "" {
+		return h.errorResponse(c, 400, "Bot token is required")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	// Validate the token via getMe before saving
+	getMeURL := fmt.Sprintf("https://api.telegram.org/bot%s/getMe", req.BotToken)
+	getMeResp, err := client.Get(getMeURL)
+	if err != nil {
+		logrus.WithError(err).Error("❌ TELEGRAM: Failed to call getMe for token validation")
+		return h.errorResponse(c, 500, fmt.Sprintf("Failed to communicate with Telegram API: %v", err))
+	}
+	defer getMeResp.Body.Close()
+
+	getMeBody, err := io.ReadAll(getMeResp.Body)
+	if err != nil {
+		return h.errorResponse(c, 500, "Failed to read API response")
+	}
+
+	var getMeResponse struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			Username string `json:"username"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(getMeBody, &getMeResponse); err != nil || !getMeResponse.OK {
+		logrus.WithField("response_body", string(getMeBody)).Error("❌ TELEGRAM: Token validation failed")
+		return h.errorResponse(c, 400, fmt.Sprintf("Telegram rejected the bot token: %s", string(getMeBody)))
+	}
+
+	// Point the bot's updates at our webhook endpoint
+	webhook := fmt.Sprintf("https://nodepath-chat-production.up.railway.app/api/ai-whatsapp/webhook/telegram/%s", req.IDDevice)
+	setWebhookURL := fmt.Sprintf("https://api.telegram.org/bot%s/setWebhook", req.BotToken)
+	webhookPayload, _ := json.Marshal(map[string]interface{}{
+		"url":             webhook,
+		"allowed_updates": []string{"message"},
+	})
+
+	setWebhookResp, err := client.Post(setWebhookURL, "application/json", strings.NewReader(string(webhookPayload)))
+	if err != nil {
+		logrus.WithError(err).Error("❌ TELEGRAM: Failed to set webhook")
+		return h.errorResponse(c, 500, fmt.Sprintf("Failed to set Telegram webhook: %v", err))
+	}
+	defer setWebhookResp.Body.Close()
+
+	setWebhookBody, _ := io.ReadAll(setWebhookResp.Body)
+	var setWebhookResponse struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(setWebhookBody, &setWebhookResponse); err != nil || !setWebhookResponse.OK {
+		logrus.WithField("response_body", string(setWebhookBody)).Error("❌ TELEGRAM: setWebhook failed")
+		return h.errorResponse(c, 500, fmt.Sprintf("Telegram webhook setup failed: %s", string(setWebhookBody)))
+	}
+
+	// Save device data to database - instance stores the bot token
+	createReq := &models.CreateDeviceSettingsRequest{
+		UserID:       userIDStr,
+		APIKeyOption: req.APIKeyOption,
+		WebhookID:    webhook,
+		Provider:     "telegram",
+		PhoneNumber:  req.PhoneNumber,
+		APIKey:       req.APIKey, // Preserve the original OpenRouter API key
+		IDDevice:     req.IDDevice,
+		IDERP:        req.IDERP,
+		IDAdmin:      req.IDAdmin,
+		Instance:     req.BotToken,
+	}
+
+	// Upsert device setting in database (update if exists, create if not)
+	deviceSetting, err := h.deviceSettingsService.Upsert(createReq)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to save device setting to database")
+		return h.errorResponse(c, 500, "Failed to save device setting")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"provider":          "telegram",
+		"device_setting_id": deviceSetting.ID,
+		"bot_username":      getMeResponse.Result.Username,
+		"webhook_url":       webhook,
+	}).Info("✅ TELEGRAM: Device generated successfully")
+
+	return h.successResponse(c, map[string]interface{}{
+		"success": true,
+		"message": "Device generated successfully via Telegram",
+		"data": map[string]interface{}{
+			"bot_username": getMeResponse.Result.Username,
+			"webhook_url":  webhook,
+			"provider":     "telegram",
+		},
+	})
+}
+
 // sendWhatsappResponse sends AI response back to WhatsApp through the appropriate provider
 // This function now properly implements the PHP logic for onemessage combining
 func (h *Handlers) sendWhatsappResponse(to, idDevice, provider string, response interface{}) {
@@ -2349,7 +2383,7 @@ func (h *Handlers) sendWhatsappResponse(to, idDevice, provider string, response
 		}
 
 		// Add small delay between messages to avoid rate limiting
-		time.Sleep(5000 * time.Millisecond)
+		h.clock.Sleep(5000 * time.Millisecond)
 	}
 
 	// Handle any remaining onemessage parts (shouldn't happen but just in case)
@@ -2365,21 +2399,21 @@ func (h *Handlers) sendWhatsappResponse(to, idDevice, provider string, response
 func (h *Handlers) sendTextMessage(to, message string, deviceSettings *models.DeviceSettings, provider string) {
 	// Add delay before sending (similar to PHP delax parameter)
 	delay := 1 * time.Second
-	time.Sleep(delay)
+	h.clock.Sleep(delay)
 
 	// Determine provider based on instance length if not specified
 	if provider == "" {
 		provider = h.determineProviderFromInstance(deviceSettings.Instance.String)
 	}
 
-	switch provider {
-	case "whacenter":
-		h.sendWhacenterTextMessage(to, message, deviceSettings)
-	case "wablas":
-		h.sendWablasTextMessage(to, message, deviceSettings)
-
-	default:
+	gateway, ok := services.GetProvider(strings.ToLower(provider))
+	if !ok {
 		logrus.WithField("provider", provider).Warn("⚠️ WHATSAPP: Unsupported provider for text message")
+		return
+	}
+
+	if err := gateway.SendText(deviceSettings, to, message); err != nil {
+		logrus.WithError(err).WithField("provider", gateway.Name()).Error("❌ WHATSAPP: Failed to send text message")
 	}
 }
 
@@ -2387,157 +2421,24 @@ func (h *Handlers) sendTextMessage(to, message string, deviceSettings *models.De
 func (h *Handlers) sendImageMessage(to, imageURL string, deviceSettings *models.DeviceSettings, provider string) {
 	// Add delay before sending (similar to PHP delax parameter)
 	delay := 1 * time.Second
-	time.Sleep(delay)
+	h.clock.Sleep(delay)
 
 	// Determine provider based on instance length if not specified
 	if provider == "" {
 		provider = h.determineProviderFromInstance(deviceSettings.Instance.String)
 	}
 
-	switch provider {
-	case "whacenter":
-		h.sendWhacenterImageMessage(to, imageURL, deviceSettings)
-	case "wablas":
-		h.sendWablasImageMessage(to, imageURL, deviceSettings)
-	case "waha":
-		// For WAHA, use the multimedia function with empty caption
-		h.sendWahaMultimediaMessage(to, imageURL, "", deviceSettings)
-	default:
+	gateway, ok := services.GetProvider(strings.ToLower(provider))
+	if !ok {
 		logrus.WithField("provider", provider).Warn("⚠️ WHATSAPP: Unsupported provider for image message")
-	}
-}
-
-// sendWhacenterTextMessage sends text message via Whacenter API
-func (h *Handlers) sendWhacenterTextMessage(to, message string, deviceSettings *models.DeviceSettings) {
-	if !deviceSettings.Instance.Valid {
-		logrus.Error("❌ WHACENTER: No instance available")
-		return
-	}
-
-	// Whacenter API endpoint for sending messages
-	apiURL := "https://api.whacenter.com/api/send"
-
-	// Prepare request payload - Use instance for device_id as per Whacenter API requirements
-	payload := map[string]interface{}{
-		"device_id": deviceSettings.Instance.String, // ✅ Use instance
-		"number":    to,
-		"message":   message,
-		"type":      "text",
-	}
-
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to marshal payload")
-		return
-	}
-
-	// Create HTTP request
-	req, err := http.NewRequest("POST", apiURL, strings.NewReader(string(payloadBytes)))
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to create request")
-		return
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+deviceSettings.Instance.String)
-
-	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to send message")
-		return
-	}
-	defer resp.Body.Close()
-
-	// Read response body for error details
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to read response body")
 		return
 	}
 
-	// Log response details
-	logFields := logrus.Fields{
-		"to":            to,
-		"status_code":   resp.StatusCode,
-		"response_body": string(respBody),
-	}
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		logFields["status"] = "success"
-		logrus.WithFields(logFields).Info("📤 WHACENTER: Text message sent successfully")
-	} else {
-		logFields["status"] = "error"
-		logrus.WithFields(logFields).Error("❌ WHACENTER: Text message failed")
+	if err := gateway.SendMedia(deviceSettings, to, imageURL); err != nil {
+		logrus.WithError(err).WithField("provider", gateway.Name()).Error("❌ WHATSAPP: Failed to send image message")
 	}
 }
 
-// sendWablasTextMessage sends text message via Wablas API
-func (h *Handlers) sendWablasTextMessage(to, message string, deviceSettings *models.DeviceSettings) {
-	if !deviceSettings.Instance.Valid {
-		logrus.Error("❌ WABLAS: No instance available")
-		return
-	}
-
-	// Wablas API endpoint for sending messages
-	apiURL := "https://my.wablas.com/api/send-message"
-
-	// Prepare form data
-	formData := url.Values{}
-	formData.Set("phone", to)
-	formData.Set("message", message)
-	formData.Set("isGroup", "false")
-
-	// Create HTTP request
-	req, err := http.NewRequest("POST", apiURL, strings.NewReader(formData.Encode()))
-	if err != nil {
-		logrus.WithError(err).Error("❌ WABLAS: Failed to create request")
-		return
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Authorization", deviceSettings.Instance.String)
-
-	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WABLAS: Failed to send message")
-		return
-	}
-	defer resp.Body.Close()
-
-	// Read response body for error details
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WABLAS: Failed to read response body")
-		return
-	}
-
-	// Log response details
-	logFields := logrus.Fields{
-		"to":            to,
-		"status_code":   resp.StatusCode,
-		"response_body": string(respBody),
-	}
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		logFields["status"] = "success"
-		logrus.WithFields(logFields).Info("📤 WABLAS: Text message sent successfully")
-	} else {
-		logFields["status"] = "error"
-		logrus.WithFields(logFields).Error("❌ WABLAS: Text message failed")
-	}
-}
-
-// sendWhacenterImageMessage sends image message via Whacenter API
-func (h *Handlers) sendWhacenterImageMessage(to, imageURL string, deviceSettings *models.DeviceSettings) {
-	h.sendWhacenterMultimediaMessage(to, imageURL, "image", deviceSettings)
-}
-
 // sendWhacenterMultimediaMessage sends multimedia messages (video, audio, image) via Whacenter API
 // Equivalent to PHP sendChatMessage function for Whacenter provider
 func (h *Handlers) sendWhacenterMultimediaMessage(to, fileURL, fileType string, deviceSettings *models.DeviceSettings) {
@@ -2613,11 +2514,6 @@ func (h *Handlers) sendWhacenterMultimediaMessage(to, fileURL, fileType string,
 	}
 }
 
-// sendWablasImageMessage sends image message via Wablas API
-func (h *Handlers) sendWablasImageMessage(to, imageURL string, deviceSettings *models.DeviceSettings) {
-	h.sendWablasMultimediaMessage(to, imageURL, "image", deviceSettings)
-}
-
 // sendWablasMultimediaMessage sends multimedia messages (video, audio, image) via Wablas API
 // Equivalent to PHP sendChatMessage function for Wablas provider
 func (h *Handlers) sendWablasMultimediaMessage(to, fileURL, fileType string, deviceSettings *models.DeviceSettings) {
@@ -2702,7 +2598,7 @@ func (h *Handlers) sendChatMessage(to, reply, fileURL string, deviceSettings *mo
 	}).Info("🔍 HANDLERS: MEDIA URL RECEIVED FOR TRACING")
 
 	// Add delay before sending
-	time.Sleep(delay)
+	h.clock.Sleep(delay)
 
 	// Determine provider based on instance length
 	provider := h.determineProviderFromInstance(deviceSettings.Instance.String)
@@ -3171,7 +3067,7 @@ func (h *Handlers) GetWahaDeviceStatus(c *fiber.Ctx) error {
 			logrus.Info("✅ WAHA: Session start request sent")
 
 			// Wait 2 seconds to allow WAHA to update status
-			time.Sleep(2 * time.Second)
+			h.clock.Sleep(2 * time.Second)
 
 			// Recheck status
 			logrus.Info("🔍 WAHA: Rechecking session status after start")
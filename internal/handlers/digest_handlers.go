@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// digestHistoryDefaultLimit is how many stored digests are returned when the
+// request does not specify a limit
+const digestHistoryDefaultLimit = 30
+
+// GenerateDigestRequest asks for an on-demand digest
+type GenerateDigestRequest struct {
+	IDDevice string `json:"id_device"`
+	Period   string `json:"period"`
+}
+
+// GetDigestReports returns a device's stored digest history, newest first
+func (h *Handlers) GetDigestReports(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	limit := digestHistoryDefaultLimit
+	if raw := c.Query("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	reports, err := h.digestService.GetReports(idDevice, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get digest reports")
+		return h.errorResponse(c, 500, "Failed to get digest reports")
+	}
+
+	return h.successResponse(c, reports)
+}
+
+// GenerateDigestReport generates a digest for the period ending now, outside
+// the schedule
+func (h *Handlers) GenerateDigestReport(c *fiber.Ctx) error {
+	var req GenerateDigestRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if req.Period == "" {
+		req.Period = models.DigestPeriodDaily
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	report, err := h.digestService.GenerateDigest(req.IDDevice, req.Period, time.Now())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to generate digest report")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, report)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"strconv"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Document template management: operators upload PDF layouts (invoices,
+// quotes) as titled line lists with {{variable}} placeholders, preview them
+// with sample variables, and reference them from document nodes by ID
+
+// CreateDocumentTemplateRequest is the body for uploading a template
+type CreateDocumentTemplateRequest struct {
+	Name  string                        `json:"name"`
+	Title string                        `json:"title"`
+	Lines []models.DocumentTemplateLine `json:"lines"`
+}
+
+// PreviewDocumentTemplateRequest carries sample variables for a preview render
+type PreviewDocumentTemplateRequest struct {
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// ListDocumentTemplates returns the user's document templates
+func (h *Handlers) ListDocumentTemplates(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	templates, err := h.documentService.ListTemplates(userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list document templates")
+		return h.errorResponse(c, 500, "Failed to list templates")
+	}
+	return h.successResponse(c, templates)
+}
+
+// CreateDocumentTemplate stores a new document template
+func (h *Handlers) CreateDocumentTemplate(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	var req CreateDocumentTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	template := &models.DocumentTemplate{
+		UserID: userID,
+		Name:   req.Name,
+		Title:  req.Title,
+		Lines:  req.Lines,
+	}
+	if err := h.documentService.CreateTemplate(template); err != nil {
+		logrus.WithError(err).Error("Failed to create document template")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, template)
+}
+
+// GetDocumentTemplate returns one of the user's templates
+func (h *Handlers) GetDocumentTemplate(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid template ID")
+	}
+
+	template, err := h.documentService.GetTemplate(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get document template")
+		return h.errorResponse(c, 500, "Failed to get template")
+	}
+	if template == nil || template.UserID != userID {
+		return h.errorResponse(c, 404, "Template not found")
+	}
+
+	return h.successResponse(c, template)
+}
+
+// DeleteDocumentTemplate removes one of the user's templates
+func (h *Handlers) DeleteDocumentTemplate(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid template ID")
+	}
+
+	if err := h.documentService.DeleteTemplate(id, userID); err != nil {
+		logrus.WithError(err).Error("Failed to delete document template")
+		return h.errorResponse(c, 500, "Failed to delete template")
+	}
+	return h.successMessageResponse(c, "Template deleted", nil)
+}
+
+// PreviewDocumentTemplate renders the template with sample variables and
+// returns the PDF inline
+func (h *Handlers) PreviewDocumentTemplate(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid template ID")
+	}
+
+	template, err := h.documentService.GetTemplate(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get document template")
+		return h.errorResponse(c, 500, "Failed to get template")
+	}
+	if template == nil || template.UserID != userID {
+		return h.errorResponse(c, 404, "Template not found")
+	}
+
+	var req PreviewDocumentTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.Variables == nil {
+		req.Variables = make(map[string]interface{})
+	}
+
+	data := h.documentService.RenderTemplate(template, req.Variables)
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", "inline; filename=preview.pdf")
+	return c.Send(data)
+}
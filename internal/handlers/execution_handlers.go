@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// SignalExecutionRequest is the body of POST /api/executions/:id/signal
+type SignalExecutionRequest struct {
+	Event   string                 `json:"event"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// SignalExecution delivers an external event to a flow execution suspended on a
+// wait_event node (e.g. payment confirmed, human approval granted)
+func (h *Handlers) SignalExecution(c *fiber.Ctx) error {
+	executionID := c.Params("id")
+	if executionID == "" {
+		return h.errorResponse(c, 400, "Execution ID is required")
+	}
+
+	var req SignalExecutionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.Event == "" {
+		return h.errorResponse(c, 400, "Event name is required")
+	}
+
+	execution, err := h.aiWhatsappHandlers.AIRepo.GetAIWhatsappByExecutionID(executionID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to look up execution for signal")
+		return h.errorResponse(c, 500, "Failed to signal execution")
+	}
+	if execution == nil {
+		return h.errorResponse(c, 404, "Execution not found")
+	}
+	if err := h.requireDeviceAccess(c, execution.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	resumed, err := h.whatsappService.SignalExecution(executionID, req.Event, req.Payload)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"execution_id": executionID,
+			"event":        req.Event,
+		}).Error("Failed to signal execution")
+		return h.errorResponse(c, 500, "Failed to signal execution")
+	}
+
+	if !resumed {
+		return h.errorResponse(c, 409, "Execution is not waiting for this event")
+	}
+
+	return h.successMessageResponse(c, "Execution resumed", fiber.Map{
+		"execution_id": executionID,
+		"event":        req.Event,
+	})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FlowDebugRequest identifies the conversation to debug
+type FlowDebugRequest struct {
+	IDDevice    string `json:"id_device"`
+	ProspectNum string `json:"prospect_num"`
+}
+
+// EnableFlowDebug switches a test prospect's conversation into debug mode so
+// engine decisions stream to the flow editor's debug panel over WebSocket
+func (h *Handlers) EnableFlowDebug(c *fiber.Ctx) error {
+	var req FlowDebugRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" || req.ProspectNum == "" {
+		return h.errorResponse(c, 400, "id_device and prospect_num are required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	h.flowDebugService.Enable(req.IDDevice, req.ProspectNum)
+	return h.successResponse(c, fiber.Map{
+		"id_device":    req.IDDevice,
+		"prospect_num": req.ProspectNum,
+		"debug":        true,
+	})
+}
+
+// DisableFlowDebug switches a conversation back to normal processing
+func (h *Handlers) DisableFlowDebug(c *fiber.Ctx) error {
+	var req FlowDebugRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" || req.ProspectNum == "" {
+		return h.errorResponse(c, 400, "id_device and prospect_num are required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	h.flowDebugService.Disable(req.IDDevice, req.ProspectNum)
+	return h.successResponse(c, fiber.Map{
+		"id_device":    req.IDDevice,
+		"prospect_num": req.ProspectNum,
+		"debug":        false,
+	})
+}
+
+// GetFlowDebugSessions lists the conversations currently in debug mode. The
+// session list spans every device, so the endpoint is admin-only
+func (h *Handlers) GetFlowDebugSessions(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+	return h.successResponse(c, h.flowDebugService.Sessions())
+}
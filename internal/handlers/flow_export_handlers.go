@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Flow export/import handlers move flows between environments and devices
+// as portable JSON documents
+
+// ImportFlowRequest wraps the export document with the import target
+type ImportFlowRequest struct {
+	IDDevice string               `json:"id_device"`
+	Name     string               `json:"name,omitempty"`
+	Flow     *services.FlowExport `json:"flow"`
+}
+
+// ExportFlow serializes a flow as a downloadable JSON document
+func (h *Handlers) ExportFlow(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	export, err := h.flowExportService.ExportFlow(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to export flow")
+		return h.errorResponse(c, 500, "Failed to export flow")
+	}
+	if export == nil {
+		return h.errorResponse(c, 404, "Flow not found")
+	}
+
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=flow-%s.json", flowID))
+	return c.JSON(export)
+}
+
+// ImportFlow creates a new flow on a device from an export document,
+// remapping all node and edge IDs so the import never collides with
+// existing flows
+func (h *Handlers) ImportFlow(c *fiber.Ctx) error {
+	var req ImportFlowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if req.Flow == nil {
+		return h.errorResponse(c, 400, "flow export document is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	flow, err := h.flowExportService.ImportFlow(req.Flow, req.IDDevice, req.Name)
+	if err != nil {
+		var validationErr *services.FlowValidationError
+		if errors.As(err, &validationErr) {
+			return h.errorResponse(c, 400, validationErr.Error())
+		}
+		logrus.WithError(err).Error("Failed to import flow")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successMessageResponse(c, "Flow imported successfully", flow)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// UpdateOrderStatusRequest is the body for moving an order through its lifecycle
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// GetFlowOrders returns a device's captured orders, optionally filtered by status
+func (h *Handlers) GetFlowOrders(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	orders, err := h.flowOrderService.GetOrders(idDevice, c.Query("status"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get orders")
+		return h.errorResponse(c, 500, "Failed to get orders")
+	}
+
+	return h.successResponse(c, orders)
+}
+
+// GetFlowOrder returns a single captured order
+func (h *Handlers) GetFlowOrder(c *fiber.Ctx) error {
+	order, err := h.flowOrderService.GetOrder(c.Params("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get order")
+		return h.errorResponse(c, 500, "Failed to get order")
+	}
+	if order == nil {
+		return h.errorResponse(c, 404, "Order not found")
+	}
+	if err := h.requireDeviceAccess(c, order.IDDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	return h.successResponse(c, order)
+}
+
+// UpdateFlowOrderStatus moves an order to a new status, enforcing transitions
+func (h *Handlers) UpdateFlowOrderStatus(c *fiber.Ctx) error {
+	var req UpdateOrderStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.Status == "" {
+		return h.errorResponse(c, 400, "status is required")
+	}
+
+	order, err := h.flowOrderService.GetOrder(c.Params("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get order")
+		return h.errorResponse(c, 500, "Failed to update order status")
+	}
+	if order == nil {
+		return h.errorResponse(c, 404, "Order not found")
+	}
+	if err := h.requireDeviceAccess(c, order.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.flowOrderService.UpdateOrderStatus(c.Params("id"), models.OrderStatus(req.Status)); err != nil {
+		logrus.WithError(err).Error("Failed to update order status")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, fiber.Map{"id": c.Params("id"), "status": req.Status})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"strconv"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Flow version handlers: the flow editor publishes drafts, browses history
+// and rolls back to an earlier version. Conversations mid-execution keep
+// running the version they were pinned to
+
+// GetFlowVersions returns a flow's published version history
+func (h *Handlers) GetFlowVersions(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	versions, err := h.flowVersionService.ListVersions(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list flow versions")
+		return h.errorResponse(c, 500, "Failed to list flow versions")
+	}
+
+	return h.successResponse(c, versions)
+}
+
+// PublishFlowVersion snapshots the flow's current draft as its next version
+func (h *Handlers) PublishFlowVersion(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	version, err := h.flowVersionService.Publish(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to publish flow version")
+		return h.errorResponse(c, 500, "Failed to publish flow")
+	}
+
+	return h.successResponse(c, version)
+}
+
+// RollbackFlowVersion restores a published version into the draft and
+// republishes it as the newest version
+func (h *Handlers) RollbackFlowVersion(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+	versionNumber, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid version number")
+	}
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	version, err := h.flowVersionService.Rollback(flowID, versionNumber)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to roll back flow version")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, version)
+}
@@ -3,11 +3,14 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
 	"nodepath-chat/internal/config"
 	"nodepath-chat/internal/models"
 	"nodepath-chat/internal/repository"
 	"nodepath-chat/internal/services"
+	"nodepath-chat/internal/utils"
 	"nodepath-chat/internal/whatsapp"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,23 +19,79 @@ import (
 
 // Handlers contains all HTTP handlers
 type Handlers struct {
-	flowService           *services.FlowService
-	aiService             *services.AIService
-	queueService          *services.QueueService
-	whatsappService       *whatsapp.Service
-	deviceSettingsService *services.DeviceSettingsService
-	websocketService      *services.WebSocketService
-	mediaService          *services.MediaService
-	mediaDetectionService *services.MediaDetectionService
-	healthService         *services.HealthService
-	aiWhatsappHandlers    *AIWhatsappHandlers
-	authHandlers          *AuthHandlers
-	wasapBotHandlers      *WasapBotHandlers
-	profileHandlers       *ProfileHandlers
-	billingHandlers       *BillingHandlers
-	appDataHandlers       *AppDataHandlers // Optimized app data handlers
-	executionProcessRepo  repository.ExecutionProcessRepository
-	db                    *sql.DB // Add database field
+	flowService               *services.FlowService
+	aiService                 *services.AIService
+	queueService              *services.QueueService
+	whatsappService           *whatsapp.Service
+	deviceSettingsService     *services.DeviceSettingsService
+	websocketService          *services.WebSocketService
+	mediaService              *services.MediaService
+	mediaDetectionService     *services.MediaDetectionService
+	messageEventService       *services.MessageEventService
+	readStateService          *services.ReadStateService
+	assignmentService         *services.AssignmentService
+	inboxService              *services.InboxService
+	slaService                *services.SLAService
+	transcriptService         *services.TranscriptService
+	productService            *services.ProductService
+	flowOrderService          *services.FlowOrderService
+	voucherService            *services.VoucherService
+	referralService           *services.ReferralService
+	branchLocationService     *services.BranchLocationService
+	qrCampaignService         *services.QRCampaignService
+	shortLinkService          *services.ShortLinkService
+	leadIntakeService         *services.LeadIntakeService
+	outboundPolicyService     *services.OutboundPolicyService
+	lifecycleWebhookService   *services.LifecycleWebhookService
+	warmupService             *services.WarmupService
+	flowDebugService          *services.FlowDebugService
+	alertingService           *services.AlertingService
+	notificationService       *services.NotificationConnectorService
+	credentialHealthService   *services.CredentialHealthService
+	turnLatencyService        *services.TurnLatencyService
+	credentialRotationService *services.CredentialRotationService
+	webhookSignatureService   *services.WebhookSignatureService
+	organizationService       *services.OrganizationService
+	incidentSnapshotService   *services.IncidentSnapshotService
+	conversationEventService  *services.ConversationEventService
+	digestService             *services.DigestService
+	retentionService          *services.RetentionService
+	backupService             *services.BackupService
+	warehouseExportService    *services.WarehouseExportService
+	customNodeService         *services.CustomNodeService
+	scriptService             *services.ScriptService
+	stageHistoryService       *services.StageHistoryService
+	leadScoringService        *services.LeadScoringService
+	contactService            *services.ContactService
+	statusService             *services.StatusService
+	usageService              *services.UsageService
+	onboardingService         *services.OnboardingService
+	savedViewService          *services.SavedViewService
+	approvalService           *services.ApprovalService
+	promptFeedbackService     *services.PromptFeedbackService
+	redTeamService            *services.RedTeamService
+	qualityService            *services.QualityService
+	languageService           *services.LanguageService
+	deadLetterService         *services.DeadLetterService
+	documentService           *services.DocumentService
+	flowVersionService        *services.FlowVersionService
+	flowExportService         *services.FlowExportService
+	conversationSearchService *services.ConversationSearchService
+	semanticSearchService     *services.SemanticSearchService
+	auditLogService           *services.AuditLogService
+	piiService                *services.PIIService
+	webhookStatsService       *services.WebhookStatsService
+	payloadCaptureService     *services.PayloadCaptureService
+	healthService             *services.HealthService
+	aiWhatsappHandlers        *AIWhatsappHandlers
+	authHandlers              *AuthHandlers
+	wasapBotHandlers          *WasapBotHandlers
+	profileHandlers           *ProfileHandlers
+	billingHandlers           *BillingHandlers
+	appDataHandlers           *AppDataHandlers // Optimized app data handlers
+	executionProcessRepo      repository.ExecutionProcessRepository
+	db                        *sql.DB     // Add database field
+	clock                     utils.Clock // Clock abstraction so send pacing is testable
 }
 
 // NewHandlers creates a new handlers instance
@@ -60,6 +119,370 @@ func NewHandlers(
 	// Initialize AI WhatsApp service
 	aiWhatsappService := services.NewAIWhatsappService(aiRepo, deviceRepo, flowService, mediaDetectionService, cfg)
 
+	// Initialize message event service for inbound edit/delete events
+	messageEventService := services.NewMessageEventService(aiRepo, websocketService)
+
+	// Initialize read state service for agent inbox synchronization
+	readStateRepo := repository.NewReadStateRepository(db)
+	readStateService := services.NewReadStateService(readStateRepo, websocketService)
+
+	// Initialize assignment service for handoff routing
+	assignmentRepo := repository.NewAssignmentRepository(db)
+	assignmentService := services.NewAssignmentService(assignmentRepo, websocketService)
+
+	// Wire assignment into the flow engine so manual nodes can route handoffs by skill
+	if whatsappService != nil {
+		whatsappService.SetAssignmentService(assignmentService)
+	}
+
+	// Initialize SLA service for handoff first-response enforcement
+	slaRepo := repository.NewSLARepository(db)
+	slaService := services.NewSLAService(slaRepo, assignmentService, websocketService)
+	if whatsappService != nil {
+		slaService.SetMessageSender(whatsappService)
+	}
+	assignmentService.SetSLAService(slaService)
+	go slaService.StartMonitor()
+
+	// Initialize the agent inbox for live conversation takeover
+	inboxService := services.NewInboxService(aiRepo, aiWhatsappService, websocketService)
+	inboxService.SetAssignmentService(assignmentService)
+	if whatsappService != nil {
+		inboxService.SetMessageSender(whatsappService)
+	}
+	go inboxService.StartIdleSweeper()
+
+	// Initialize transcript service for conversation exports
+	transcriptService := services.NewTranscriptService(aiRepo, mediaDetectionService)
+
+	// Initialize product catalog service and wire it into the flow engine so
+	// catalog nodes can send the product list
+	productRepo := repository.NewProductRepository(db)
+	productService := services.NewProductService(productRepo)
+	if whatsappService != nil {
+		whatsappService.SetProductService(productService)
+	}
+
+	// Initialize cart and order capture services for the shopping flow
+	cartRepo := repository.NewCartRepository(db)
+	cartService := services.NewCartService(cartRepo)
+	flowOrderRepo := repository.NewFlowOrderRepository(db)
+	flowOrderService := services.NewFlowOrderService(flowOrderRepo)
+	if whatsappService != nil {
+		whatsappService.SetCartService(cartService)
+		whatsappService.SetFlowOrderService(flowOrderService)
+	}
+
+	// Initialize voucher service for discount code generation and redemption
+	voucherRepo := repository.NewVoucherRepository(db)
+	voucherService := services.NewVoucherService(voucherRepo)
+	if whatsappService != nil {
+		whatsappService.SetVoucherService(voucherService)
+	}
+
+	// Initialize referral service for invite links and attribution
+	referralRepo := repository.NewReferralRepository(db)
+	referralService := services.NewReferralService(referralRepo, deviceSettingsService)
+	if whatsappService != nil {
+		whatsappService.SetReferralService(referralService)
+	}
+
+	// Initialize branch location service for nearest-branch routing
+	branchLocationRepo := repository.NewBranchLocationRepository(db)
+	branchLocationService := services.NewBranchLocationService(branchLocationRepo)
+	if whatsappService != nil {
+		whatsappService.SetBranchLocationService(branchLocationService)
+	}
+
+	// Initialize QR campaign service for printed entry points and scan tracking
+	qrCampaignRepo := repository.NewQRCampaignRepository(db)
+	qrCampaignService := services.NewQRCampaignService(qrCampaignRepo, deviceSettingsService)
+	if whatsappService != nil {
+		whatsappService.SetQRCampaignService(qrCampaignService)
+	}
+
+	// Initialize short link service for tracked URL shortening
+	shortLinkRepo := repository.NewShortLinkRepository(db)
+	shortLinkService := services.NewShortLinkService(shortLinkRepo)
+	if whatsappService != nil {
+		whatsappService.SetShortLinkService(shortLinkService)
+	}
+
+	// Initialize lead intake service for external lead sources
+	leadSourceRepo := repository.NewLeadSourceRepository(db)
+	leadIntakeService := services.NewLeadIntakeService(leadSourceRepo)
+
+	// Initialize outbound policy service for opt-outs and quiet hours
+	outboundPolicyRepo := repository.NewOutboundPolicyRepository(db)
+	outboundPolicyService := services.NewOutboundPolicyService(outboundPolicyRepo)
+	if whatsappService != nil {
+		whatsappService.SetOutboundPolicyService(outboundPolicyService)
+	}
+
+	// Initialize lifecycle webhook service so execution state changes reach
+	// subscribed external systems
+	webhookSubscriptionRepo := repository.NewWebhookSubscriptionRepository(db)
+	lifecycleWebhookService := services.NewLifecycleWebhookService(webhookSubscriptionRepo)
+	lifecycleWebhookService.SetMessageBus(services.NewMessageBusFromEnv())
+	aiWhatsappService.SetLifecycleWebhookService(lifecycleWebhookService)
+	if whatsappService != nil {
+		whatsappService.SetLifecycleWebhookService(lifecycleWebhookService)
+	}
+
+	// Initialize warmup service for daily send caps on new numbers
+	warmupRepo := repository.NewWarmupRepository(db)
+	warmupService := services.NewWarmupService(warmupRepo)
+	if whatsappService != nil {
+		whatsappService.SetWarmupService(warmupService)
+	}
+
+	// Initialize notification connectors so operational events reach the
+	// team's Slack/Discord channels
+	notificationConnectorRepo := repository.NewNotificationConnectorRepository(db)
+	notificationService := services.NewNotificationConnectorService(notificationConnectorRepo)
+	slaService.SetNotificationService(notificationService)
+	warmupService.SetNotificationService(notificationService)
+	aiWhatsappService.SetNotificationService(notificationService)
+
+	// Initialize the credential health monitor so expired provider tokens
+	// alert owners and block sends with a clear error
+	credentialHealthService := services.NewCredentialHealthService(deviceRepo)
+	credentialHealthService.SetNotificationService(notificationService)
+	if whatsappService != nil {
+		whatsappService.SetCredentialHealthService(credentialHealthService)
+	}
+	go credentialHealthService.StartMonitor()
+
+	// Self-service credential rotation: validate, swap atomically and
+	// re-register webhooks without recreating the device
+	credentialRotationService := services.NewCredentialRotationService(deviceSettingsService, credentialHealthService)
+
+	// Track webhook-to-first-send latency per turn and alert on SLO breaches
+	turnLatencyService := services.NewTurnLatencyService(time.Duration(cfg.TurnLatencySLOMs) * time.Millisecond)
+	turnLatencyService.SetNotificationService(notificationService)
+	if whatsappService != nil {
+		whatsappService.SetTurnLatencyService(turnLatencyService)
+	}
+
+	// Initialize webhook signature verification for devices with a secret
+	webhookSecretRepo := repository.NewWebhookSecretRepository(db)
+	webhookSignatureService := services.NewWebhookSignatureService(webhookSecretRepo)
+
+	// Organizations let teams share devices, flows and conversations under
+	// role-based permissions
+	organizationRepo := repository.NewOrganizationRepository(db)
+	organizationService := services.NewOrganizationService(organizationRepo)
+
+	// Initialize digest reports with a scheduler for daily/weekly delivery
+	digestReportRepo := repository.NewDigestReportRepository(db)
+	digestService := services.NewDigestService(digestReportRepo, aiService)
+	if whatsappService != nil {
+		whatsappService.SetDigestService(digestService)
+	}
+	go digestService.StartScheduler()
+
+	// Initialize retention policies with a scheduled purger for expired
+	// conversation data
+	retentionRepo := repository.NewRetentionRepository(db)
+	retentionService := services.NewRetentionService(retentionRepo)
+	go retentionService.StartPurger()
+
+	// Initialize tenant backup export/restore for disaster recovery and
+	// environment cloning
+	backupService := services.NewBackupService(db)
+
+	// Initialize the analytics warehouse export so lifecycle events stream to
+	// customer-managed BigQuery/ClickHouse endpoints
+	warehouseExportRepo := repository.NewWarehouseExportRepository(db)
+	warehouseExportService := services.NewWarehouseExportService(warehouseExportRepo)
+	if aiWhatsappService != nil {
+		aiWhatsappService.SetWarehouseExportService(warehouseExportService)
+	}
+	go warehouseExportService.StartExporter()
+
+	// Initialize the custom node registry so flows can use partner-registered
+	// webhook-backed node types
+	customNodeRepo := repository.NewCustomNodeRepository(db)
+	customNodeService := services.NewCustomNodeService(customNodeRepo)
+	if whatsappService != nil {
+		whatsappService.SetCustomNodeService(customNodeService)
+	}
+
+	// Initialize the sandboxed script engine with its per-flow permission gate
+	scriptPermissionRepo := repository.NewScriptPermissionRepository(db)
+	scriptService := services.NewScriptService(scriptPermissionRepo)
+	if whatsappService != nil {
+		whatsappService.SetScriptService(scriptService)
+	}
+
+	// Initialize stage history so every stage transition is recorded with its
+	// timestamp and trigger
+	stageHistoryRepo := repository.NewStageHistoryRepository(db)
+	stageHistoryService := services.NewStageHistoryService(stageHistoryRepo)
+	aiWhatsappService.SetStageHistoryService(stageHistoryService)
+
+	// Initialize lead scoring so conversation signals (stages, keywords,
+	// intents, reply latency) accumulate into a per-prospect score with
+	// threshold triggers
+	leadScoringRepo := repository.NewLeadScoringRepository(db)
+	leadScoringService := services.NewLeadScoringService(leadScoringRepo)
+	leadScoringService.SetNotificationService(notificationService)
+	leadScoringService.SetAIWhatsappService(aiWhatsappService)
+	aiWhatsappService.SetLeadScoringService(leadScoringService)
+	if whatsappService != nil {
+		whatsappService.SetLeadScoringService(leadScoringService)
+	}
+
+	// Initialize org-level contacts linking device-scoped prospects, with
+	// optional shared flow state across an org's devices
+	contactRepo := repository.NewContactRepository(db)
+	contactService := services.NewContactService(contactRepo)
+	aiWhatsappService.SetContactService(contactService)
+
+	// Initialize the public status page fed by provider send outcomes and
+	// admin-posted incidents
+	incidentRepo := repository.NewIncidentRepository(db)
+	statusService := services.NewStatusService(incidentRepo)
+	if whatsappService != nil {
+		whatsappService.SetStatusService(statusService)
+	}
+
+	// Initialize flow debug service so test conversations can stream engine
+	// decisions to the editor's debug panel
+	flowDebugService := services.NewFlowDebugService(websocketService)
+	if whatsappService != nil {
+		whatsappService.SetFlowDebugService(flowDebugService)
+	}
+
+	// Initialize alerting service for failure-rate anomaly detection
+	alertingService := services.NewAlertingService(websocketService)
+	if whatsappService != nil {
+		whatsappService.SetAlertingService(alertingService)
+	}
+
+	// Initialize the per-tenant usage dashboard aggregations, cached in Redis
+	usageRepo := repository.NewUsageRepository(db)
+	usageService := services.NewUsageService(usageRepo, aiService, alertingService, queueService)
+
+	// Initialize the onboarding wizard tracking and starter flow shortcut
+	onboardingRepo := repository.NewOnboardingRepository(db)
+	onboardingService := services.NewOnboardingService(onboardingRepo, flowService)
+
+	// Initialize saved views for the inbox and prospect lists
+	savedViewRepo := repository.NewSavedViewRepository(db)
+	savedViewService := services.NewSavedViewService(savedViewRepo)
+
+	// Initialize the human-in-the-loop approval queue for flows in
+	// review-before-send mode
+	approvalRepo := repository.NewApprovalRepository(db)
+	approvalService := services.NewApprovalService(approvalRepo, websocketService)
+	approvalService.SetAIWhatsappService(aiWhatsappService)
+
+	// Initialize the prompt feedback loop aggregating agent edits into
+	// system-prompt suggestions
+	promptFeedbackRepo := repository.NewPromptFeedbackRepository(db)
+	promptFeedbackService := services.NewPromptFeedbackService(promptFeedbackRepo)
+	approvalService.SetPromptFeedbackService(promptFeedbackService)
+
+	// Initialize the red team runner for pre-launch prompt hardening
+	redTeamService := services.NewRedTeamService(flowService, aiService, deviceSettingsService)
+
+	// Initialize the background conversation quality evaluator
+	qualityRepo := repository.NewQualityRepository(db)
+	qualityService := services.NewQualityService(qualityRepo, aiService)
+	go qualityService.StartScheduler()
+
+	// Initialize per-conversation language resolution for node translations,
+	// with machine translation fallback for flows that opt in
+	languageRepo := repository.NewLanguageRepository(db)
+	languageService := services.NewLanguageService(languageRepo)
+	languageService.SetAIService(aiService)
+
+	if whatsappService != nil {
+		approvalService.SetSender(whatsappService)
+		whatsappService.SetApprovalService(approvalService)
+		whatsappService.SetLanguageService(languageService)
+	}
+
+	// Initialize image generation service for image_gen nodes
+	imageGenService := services.NewImageGenService(mediaService)
+	if whatsappService != nil {
+		whatsappService.SetImageGenService(imageGenService)
+	}
+
+	// Initialize dead letter service for webhook messages that exhaust retries
+	failedMessageRepo := repository.NewFailedMessageRepository(db)
+	deadLetterService := services.NewDeadLetterService(failedMessageRepo)
+	if whatsappService != nil {
+		whatsappService.SetDeadLetterService(deadLetterService)
+	}
+
+	// Initialize document service for PDF document nodes
+	documentTemplateRepo := repository.NewDocumentTemplateRepository(db)
+	documentService := services.NewDocumentService(documentTemplateRepo, mediaService)
+	if whatsappService != nil {
+		whatsappService.SetDocumentService(documentService)
+	}
+
+	// Initialize flow versioning for the draft/publish lifecycle
+	flowVersionRepo := repository.NewFlowVersionRepository(db)
+	flowVersionService := services.NewFlowVersionService(flowVersionRepo, flowService)
+	if whatsappService != nil {
+		whatsappService.SetFlowVersionService(flowVersionService)
+	}
+
+	// Initialize role-based PII masking for viewer accounts
+	userRoleRepo := repository.NewUserRoleRepository(db)
+	piiService := services.NewPIIService(userRoleRepo)
+	if websocketService != nil {
+		websocketService.SetPIIService(piiService)
+	}
+
+	// Initialize the tamper-evident audit log for regulated customers
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	auditLogService := services.NewAuditLogService(auditLogRepo)
+	aiWhatsappService.SetAuditLogService(auditLogService)
+
+	// Initialize the append-only conversation event log (event sourcing)
+	conversationEventRepo := repository.NewConversationEventRepository(db)
+	conversationEventService := services.NewConversationEventService(conversationEventRepo)
+	aiWhatsappService.SetConversationEventService(conversationEventService)
+	if whatsappService != nil {
+		whatsappService.SetConversationEventService(conversationEventService)
+	}
+
+	// Initialize flow export/import for copying flows between environments
+	flowExportService := services.NewFlowExportService(flowService)
+
+	// Initialize conversation search for the inbox
+	conversationSearchRepo := repository.NewConversationSearchRepository(db)
+	conversationSearchService := services.NewConversationSearchService(conversationSearchRepo)
+
+	// Initialize embedding-based semantic search over conversation history
+	conversationEmbeddingRepo := repository.NewConversationEmbeddingRepository(db)
+	semanticSearchService := services.NewSemanticSearchService(conversationEmbeddingRepo, aiRepo, aiService, deviceSettingsService)
+
+	// Initialize webhook stats service for per-provider processing metrics
+	webhookStatsService := services.NewWebhookStatsService()
+
+	// Initialize payload capture service for unknown webhook formats
+	unknownPayloadRepo := repository.NewUnknownPayloadRepository(db)
+	payloadCaptureService := services.NewPayloadCaptureService(unknownPayloadRepo)
+
+	// Initialize incident snapshot service for downloadable diagnostic bundles
+	incidentSnapshotService := services.NewIncidentSnapshotService(aiRepo, executionProcessRepo, failedMessageRepo, unknownPayloadRepo, queueService)
+
+	// One-time backfill of the structured messages table from legacy conv_last
+	// history; skips conversations that already have rows, so repeated startups
+	// are cheap
+	go func() {
+		if inserted, err := aiRepo.BackfillMessagesFromConvLast(); err != nil {
+			logrus.WithError(err).Warn("Failed to backfill messages table from conv_last")
+		} else if inserted > 0 {
+			logrus.WithField("inserted", inserted).Info("💬 MESSAGES: Backfilled structured message rows from conv_last")
+		}
+	}()
+
 	// Initialize AI WhatsApp handlers
 	aiWhatsappHandlers := NewAIWhatsappHandlers(aiWhatsappService, aiRepo, deviceRepo)
 
@@ -85,28 +508,90 @@ func NewHandlers(
 
 	// Create main handlers instance
 	mainHandlers := &Handlers{
-		flowService:           flowService,
-		aiService:             aiService,
-		queueService:          queueService,
-		whatsappService:       whatsappService,
-		deviceSettingsService: deviceSettingsService,
-		websocketService:      websocketService,
-		mediaService:          mediaService,
-		mediaDetectionService: mediaDetectionService,
-		healthService:         healthService,
-		aiWhatsappHandlers:    aiWhatsappHandlers,
-		authHandlers:          authHandlers,
-		wasapBotHandlers:      wasapBotHandlers,
-		profileHandlers:       profileHandlers,
-		billingHandlers:       billingHandlers,
-		appDataHandlers:       appDataHandlers, // Add optimized app data handlers
-		executionProcessRepo:  executionProcessRepo,
-		db:                    db, // Store the database
+		flowService:               flowService,
+		aiService:                 aiService,
+		queueService:              queueService,
+		whatsappService:           whatsappService,
+		deviceSettingsService:     deviceSettingsService,
+		websocketService:          websocketService,
+		mediaService:              mediaService,
+		mediaDetectionService:     mediaDetectionService,
+		messageEventService:       messageEventService,
+		readStateService:          readStateService,
+		assignmentService:         assignmentService,
+		inboxService:              inboxService,
+		slaService:                slaService,
+		transcriptService:         transcriptService,
+		productService:            productService,
+		flowOrderService:          flowOrderService,
+		voucherService:            voucherService,
+		referralService:           referralService,
+		branchLocationService:     branchLocationService,
+		qrCampaignService:         qrCampaignService,
+		shortLinkService:          shortLinkService,
+		leadIntakeService:         leadIntakeService,
+		outboundPolicyService:     outboundPolicyService,
+		lifecycleWebhookService:   lifecycleWebhookService,
+		warmupService:             warmupService,
+		flowDebugService:          flowDebugService,
+		alertingService:           alertingService,
+		notificationService:       notificationService,
+		credentialHealthService:   credentialHealthService,
+		turnLatencyService:        turnLatencyService,
+		credentialRotationService: credentialRotationService,
+		webhookSignatureService:   webhookSignatureService,
+		organizationService:       organizationService,
+		incidentSnapshotService:   incidentSnapshotService,
+		conversationEventService:  conversationEventService,
+		digestService:             digestService,
+		retentionService:          retentionService,
+		backupService:             backupService,
+		warehouseExportService:    warehouseExportService,
+		customNodeService:         customNodeService,
+		scriptService:             scriptService,
+		stageHistoryService:       stageHistoryService,
+		leadScoringService:        leadScoringService,
+		contactService:            contactService,
+		statusService:             statusService,
+		usageService:              usageService,
+		onboardingService:         onboardingService,
+		savedViewService:          savedViewService,
+		approvalService:           approvalService,
+		promptFeedbackService:     promptFeedbackService,
+		redTeamService:            redTeamService,
+		qualityService:            qualityService,
+		languageService:           languageService,
+		deadLetterService:         deadLetterService,
+		documentService:           documentService,
+		flowVersionService:        flowVersionService,
+		flowExportService:         flowExportService,
+		conversationSearchService: conversationSearchService,
+		semanticSearchService:     semanticSearchService,
+		auditLogService:           auditLogService,
+		piiService:                piiService,
+		webhookStatsService:       webhookStatsService,
+		payloadCaptureService:     payloadCaptureService,
+		healthService:             healthService,
+		aiWhatsappHandlers:        aiWhatsappHandlers,
+		authHandlers:              authHandlers,
+		wasapBotHandlers:          wasapBotHandlers,
+		profileHandlers:           profileHandlers,
+		billingHandlers:           billingHandlers,
+		appDataHandlers:           appDataHandlers, // Add optimized app data handlers
+		executionProcessRepo:      executionProcessRepo,
+		db:                        db, // Store the database
+		clock:                     utils.NewSystemClock(),
 	}
 
 	// Set the reference to main handlers in AI WhatsApp handlers for flow routing
 	aiWhatsappHandlers.SetMainHandlers(mainHandlers)
 
+	// Let webhook handlers download and store incoming media from providers
+	aiWhatsappHandlers.SetInboundMediaService(services.NewInboundMediaService(mediaService))
+
+	// Transcribe incoming voice notes so flows and AI prompts see the words
+	aiWhatsappHandlers.SetVoiceTranscriptionService(services.NewVoiceTranscriptionService(aiService, mediaDetectionService))
+
 	return mainHandlers
 }
 
@@ -115,12 +600,39 @@ func (h *Handlers) SetupRoutes(api fiber.Router) {
 	// Flow routes - protected with device requirement
 	flows := api.Group("/flows")
 	flows.Use(h.authHandlers.AuthMiddleware())
+	flows.Use(h.authHandlers.RequireScope(APIScopeFlowsRead, APIScopeFlowsWrite))
 	flows.Use(h.authHandlers.DeviceRequiredMiddleware())
 	flows.Get("/", h.GetFlows)
 	flows.Post("/", h.CreateFlow)
+	// Debug routes are registered before /:id so "debug" is not taken as a flow ID
+	flows.Get("/debug", h.GetFlowDebugSessions) // List conversations in debug mode
+	flows.Get("/timeouts", h.GetNodeTimeoutStats)
+	flows.Post("/debug", h.EnableFlowDebug) // Stream engine decisions for a test prospect
+	flows.Delete("/debug", h.DisableFlowDebug)
+	flows.Post("/import", h.ImportFlow)
 	flows.Get("/:id", h.GetFlow)
+	flows.Get("/:id/export", h.ExportFlow)
+	flows.Get("/:id/script-permission", h.GetFlowScriptPermission)
+	flows.Put("/:id/script-permission", h.SetFlowScriptPermission)
+	flows.Get("/:id/review-mode", h.GetFlowReviewMode)
+	flows.Put("/:id/review-mode", h.SetFlowReviewMode)
+	flows.Get("/:id/edit-log", h.GetFlowEditLog)
+	flows.Post("/:id/edits", h.RecordFlowMessageEdit)
+	flows.Get("/:id/prompt-feedback", h.GetPromptFeedbackReport)
+	flows.Get("/:id/prompt-suggestions", h.GetPromptSuggestions)
+	flows.Post("/:id/red-team", h.RunFlowRedTeam)
+	flows.Get("/:id/quality", h.GetFlowQualitySummary)
+	flows.Get("/:id/translation-status", h.GetFlowTranslationStatus)
+	flows.Get("/:id/auto-translate", h.GetFlowAutoTranslate)
+	flows.Put("/:id/auto-translate", h.SetFlowAutoTranslate)
+	flows.Get("/:id/machine-translations", h.GetFlowMachineTranslations)
+	flows.Get("/:id/versions", h.GetFlowVersions)
+	flows.Post("/:id/versions", h.PublishFlowVersion)
+	flows.Post("/:id/versions/:version/rollback", h.RollbackFlowVersion)
 	flows.Put("/:id", h.UpdateFlow)
 	flows.Delete("/:id", h.DeleteFlow)
+	flows.Post("/:id/start", h.StartFlowForNumber)   // Proactively start a flow for a phone number
+	flows.Post("/preview-spintax", h.PreviewSpintax) // Show sample expansions of a spintax template
 
 	// Test chat routes removed
 
@@ -131,17 +643,22 @@ func (h *Handlers) SetupRoutes(api fiber.Router) {
 	executions.Get("/", h.GetExecutions)
 	executions.Get("/:id", h.GetExecution)
 	executions.Put("/:id/complete", h.CompleteExecution)
+	executions.Post("/:id/signal", h.SignalExecution) // Deliver external events to wait-event nodes
 	executions.Delete("/:id", h.DeleteExecution)
 
 	// WhatsApp routes - simplified for webhook-based system
 	whatsapp := api.Group("/whatsapp")
-	whatsapp.Post("/send", h.SendWhatsAppMessage)
+	whatsapp.Post("/send", h.authHandlers.APIKeyScopeGate(APIScopeMessagesSend), h.SendWhatsAppMessage)
 
 	// Queue management routes - protected with device requirement
 	queue := api.Group("/queue")
 	queue.Use(h.authHandlers.AuthMiddleware())
 	queue.Use(h.authHandlers.DeviceRequiredMiddleware())
 	queue.Get("/stats", h.GetQueueStats)
+	queue.Get("/workers", h.GetMessageWorkerMetrics)
+	queue.Get("/dead-letters", h.ListDeadLetters)
+	queue.Post("/dead-letters/:id/requeue", h.RequeueDeadLetter)
+	queue.Delete("/dead-letters/:id", h.DiscardDeadLetter)
 	queue.Delete("/failed", h.ClearFailedQueue)
 
 	// AI routes
@@ -161,6 +678,68 @@ func (h *Handlers) SetupRoutes(api fiber.Router) {
 	dashboard.Use(h.authHandlers.AuthMiddleware())
 	dashboard.Get("/chart-data", h.GetDashboardChartData)
 
+	// Usage dashboard aggregations - scoped to the requesting user
+	usage := api.Group("/usage")
+	usage.Use(h.authHandlers.AuthMiddleware())
+	usage.Get("/dashboard", h.GetUsageDashboard)
+
+	// Onboarding wizard progress and the starter flow shortcut
+	onboarding := api.Group("/onboarding")
+	onboarding.Use(h.authHandlers.AuthMiddleware())
+	onboarding.Get("/state", h.GetOnboardingState)
+	onboarding.Post("/steps/:step", h.CompleteOnboardingStep)
+	onboarding.Post("/starter-flow", h.CreateStarterFlow)
+
+	// Organizations and role-based membership management
+	organizations := api.Group("/organizations")
+	organizations.Use(h.authHandlers.AuthMiddleware())
+	organizations.Get("/", h.GetOrganizations)
+	organizations.Post("/", h.CreateOrganization)
+	organizations.Get("/:id/members", h.GetOrganizationMembers)
+	organizations.Post("/:id/members", h.AddOrganizationMember)
+	organizations.Delete("/:id/members/:user_id", h.RemoveOrganizationMember)
+
+	// Live log tail for support (admin only, PII-redacted)
+	support := api.Group("/support")
+	support.Use(h.authHandlers.AuthMiddleware())
+	support.Get("/logs/stream", h.requireAdminRole, h.StreamSupportLogs)
+	support.Get("/incident-snapshot", h.requireAdminRole, h.CaptureIncidentSnapshot)
+
+	// Saved views for the inbox and prospect lists
+	views := api.Group("/views")
+	views.Use(h.authHandlers.AuthMiddleware())
+	views.Get("/", h.GetSavedViews)
+	views.Post("/", h.CreateSavedView)
+	views.Put("/:id", h.UpdateSavedView)
+	views.Delete("/:id", h.DeleteSavedView)
+	views.Post("/:id/default", h.SetDefaultSavedView)
+
+	// Automated conversation quality evaluations
+	quality := api.Group("/quality")
+	quality.Use(h.authHandlers.AuthMiddleware())
+	quality.Get("/scores", h.GetQualityScores)
+
+	// Machine translation flags per conversation
+	translations := api.Group("/translations")
+	translations.Use(h.authHandlers.AuthMiddleware())
+	translations.Get("/log", h.GetMachineTranslationLog)
+
+	// PDF document templates for document nodes
+	documents := api.Group("/documents")
+	documents.Use(h.authHandlers.AuthMiddleware())
+	documents.Get("/templates", h.ListDocumentTemplates)
+	documents.Post("/templates", h.CreateDocumentTemplate)
+	documents.Get("/templates/:id", h.GetDocumentTemplate)
+	documents.Post("/templates/:id/preview", h.PreviewDocumentTemplate)
+	documents.Delete("/templates/:id", h.DeleteDocumentTemplate)
+
+	// Approval queue for flows in review-before-send mode
+	approvals := api.Group("/approvals")
+	approvals.Use(h.authHandlers.AuthMiddleware())
+	approvals.Get("/", h.GetPendingApprovals)
+	approvals.Post("/:id/approve", h.ApproveMessage)
+	approvals.Post("/:id/reject", h.RejectMessage)
+
 	// Health check routes for system monitoring
 	health := api.Group("/health")
 	health.Get("/", h.HandleHealthCheck)
@@ -170,6 +749,15 @@ func (h *Handlers) SetupRoutes(api fiber.Router) {
 	health.Get("/metrics", h.HandleHealthMetrics)
 	health.Delete("/cache", h.HandleClearHealthCache)
 
+	// Public status page (no auth - customers check it during outages) and
+	// the protected incident management API behind it
+	api.Get("/status", h.GetPublicStatus)
+	incidents := api.Group("/incidents")
+	incidents.Use(h.authHandlers.AuthMiddleware())
+	incidents.Get("/", h.GetIncidents)
+	incidents.Post("/", h.CreateIncident)
+	incidents.Put("/:id", h.UpdateIncident)
+
 	// Config routes (removed database config endpoint - no longer needed with Supabase)
 
 	// Device settings routes (protected with authentication middleware)
@@ -177,10 +765,17 @@ func (h *Handlers) SetupRoutes(api fiber.Router) {
 	deviceSettings.Use(h.authHandlers.AuthMiddleware())
 	deviceSettings.Get("/", h.GetDeviceSettings)
 	deviceSettings.Get("/device-ids", h.GetDeviceIDs)
+	// Credential health routes - must be before /:id to avoid conflicts
+	deviceSettings.Get("/credential-health", h.GetCredentialHealth)
+	deviceSettings.Post("/credential-health/check", h.CheckCredentialHealth)
+	deviceSettings.Get("/turn-latency", h.GetTurnLatencyReport)
 	deviceSettings.Post("/", h.CreateDeviceSettings)
 	// Device status route - must be before /:id to avoid conflicts
 	deviceSettings.Get("/:id/status", h.GetDeviceStatus)
 	deviceSettings.Get("/:id/waha-status", h.GetWahaDeviceStatus)
+	deviceSettings.Post("/:id/rotate-credentials", h.RotateDeviceCredentials)
+	deviceSettings.Put("/:id/webhook-secret", h.SetWebhookSecret)
+	deviceSettings.Delete("/:id/webhook-secret", h.DeleteWebhookSecret)
 	deviceSettings.Get("/:id", h.GetDeviceSettingsById)
 	deviceSettings.Put("/:id", h.UpdateDeviceSettings)
 	deviceSettings.Delete("/:id", h.DeleteDeviceSettings)
@@ -188,6 +783,8 @@ func (h *Handlers) SetupRoutes(api fiber.Router) {
 	deviceSettings.Post("/generate-whacenter", h.GenerateWhacenterDevice)
 	deviceSettings.Post("/generate-wablas", h.GenerateWablasDevice)
 	deviceSettings.Post("/generate-waha", h.GenerateWahaDevice)
+	deviceSettings.Post("/generate-meta", h.GenerateMetaDevice)
+	deviceSettings.Post("/generate-telegram", h.GenerateTelegramDevice)
 
 	// AI WhatsApp routes - delegate to AIWhatsappHandlers (must be registered before generic webhook routes)
 	aiWhatsapp := api.Group("/ai-whatsapp")
@@ -209,6 +806,138 @@ func (h *Handlers) SetupRoutes(api fiber.Router) {
 	stageValues.Put("/:id", h.UpdateStageValue)
 	stageValues.Delete("/:id", h.DeleteStageValue)
 
+	// Inbox read-state routes (protected with authentication)
+	inbox := api.Group("/inbox")
+	inbox.Use(h.authHandlers.AuthMiddleware())
+	inbox.Post("/read-state", h.MarkConversationRead)
+	inbox.Get("/unread-states", h.GetInboxUnreadStates)
+	inbox.Get("/conversations", h.GetHumanInboxConversations)
+	inbox.Post("/:conversation/assign", h.AssignInboxConversation)
+	inbox.Post("/:conversation/reply", h.ReplyInboxConversation)
+	inbox.Post("/:conversation/return", h.ReturnInboxConversationToBot)
+
+	// Assignment routes for handoff routing (protected with authentication)
+	assignments := api.Group("/assignments")
+	assignments.Use(h.authHandlers.AuthMiddleware())
+	assignments.Post("/availability", h.SetAgentAvailability)
+	assignments.Post("/assign", h.AssignConversation)
+	assignments.Post("/reassign", h.ReassignConversation)
+	assignments.Get("/:prospect_num", h.GetConversationAssignment)
+
+	// Conversation export routes (protected with authentication)
+	conversations := api.Group("/conversations")
+	conversations.Use(h.authHandlers.AuthMiddleware())
+	conversations.Get("/search", h.SearchConversations)
+	conversations.Get("/search/semantic", h.SemanticSearchConversations)
+	conversations.Post("/search/reindex", h.ReindexConversationEmbeddings)
+	conversations.Get("/:prospect/export", h.ExportConversationData)
+	conversations.Get("/:prospect/messages", h.ListConversationMessages)
+	conversations.Get("/:prospect/events", h.ListConversationEvents)
+	conversations.Get("/:prospect/:device/export", h.ExportConversationTranscript)
+
+	// Audit log routes for compliance exports (protected with authentication)
+	audit := api.Group("/audit")
+	audit.Use(h.authHandlers.AuthMiddleware())
+	audit.Get("/logs", h.ExportAuditLog)
+	audit.Get("/verify", h.VerifyAuditLog)
+
+	// Role routes behind PII masking (protected with authentication)
+	roles := api.Group("/roles")
+	roles.Use(h.authHandlers.AuthMiddleware())
+	roles.Get("/me", h.GetMyRole)
+	roles.Put("/", h.SetUserRole)
+
+	// Product catalog routes (protected with device requirement)
+	products := api.Group("/products")
+	products.Use(h.authHandlers.AuthMiddleware())
+	products.Use(h.authHandlers.DeviceRequiredMiddleware())
+	products.Get("/", h.GetProducts)
+	products.Post("/", h.CreateProduct)
+	products.Get("/:id", h.GetProduct)
+	products.Put("/:id", h.UpdateProduct)
+	products.Delete("/:id", h.DeleteProduct)
+
+	// Order routes for e-commerce sync (protected with device requirement)
+	orders := api.Group("/orders")
+	orders.Use(h.authHandlers.AuthMiddleware())
+	orders.Use(h.authHandlers.DeviceRequiredMiddleware())
+	orders.Get("/", h.GetFlowOrders)
+	orders.Get("/:id", h.GetFlowOrder)
+	orders.Put("/:id/status", h.UpdateFlowOrderStatus)
+
+	// Voucher routes for code issuing and POS redemption (protected with authentication)
+	vouchers := api.Group("/vouchers")
+	vouchers.Use(h.authHandlers.AuthMiddleware())
+	vouchers.Get("/", h.GetVouchers)
+	vouchers.Post("/redeem", h.RedeemVoucher)
+
+	// Referral routes for invite links and the leaderboard report (protected with authentication)
+	referrals := api.Group("/referrals")
+	referrals.Use(h.authHandlers.AuthMiddleware())
+	referrals.Get("/link", h.GetReferralLink)
+	referrals.Get("/leaderboard", h.GetReferralLeaderboard)
+
+	// Branch location routes for nearest-branch routing (protected with device requirement)
+	branches := api.Group("/branches")
+	branches.Use(h.authHandlers.AuthMiddleware())
+	branches.Use(h.authHandlers.DeviceRequiredMiddleware())
+	branches.Get("/", h.GetBranchLocations)
+	branches.Post("/", h.CreateBranchLocation)
+	branches.Put("/:id", h.UpdateBranchLocation)
+	branches.Delete("/:id", h.DeleteBranchLocation)
+
+	// QR campaign routes for printed entry-point QR codes (protected with authentication)
+	qr := api.Group("/qr")
+	qr.Use(h.authHandlers.AuthMiddleware())
+	qr.Get("/campaigns", h.GetQRCampaigns)
+	qr.Post("/campaigns", h.CreateQRCampaign)
+	qr.Get("/campaigns/:id/image", h.GetQRCampaignImage)
+	qr.Delete("/campaigns/:id", h.DeleteQRCampaign)
+
+	// Short link routes for URL shortening and click analytics (protected with
+	// authentication; the public /s/:code redirect is registered in main)
+	links := api.Group("/links")
+	links.Use(h.authHandlers.AuthMiddleware())
+	links.Get("/", h.GetShortLinks)
+	links.Post("/", h.CreateShortLink)
+	links.Get("/settings", h.GetShortLinkSetting)
+	links.Put("/settings", h.SaveShortLinkSetting)
+	links.Delete(":id", h.DeleteShortLink)
+
+	// Lead intake routes: source management is protected, the intake endpoint
+	// itself is public (external forms and Meta post to it with the token)
+	leads := api.Group("/leads")
+	leads.Get("/sources", h.authHandlers.AuthMiddleware(), h.GetLeadSources)
+	leads.Post("/sources", h.authHandlers.AuthMiddleware(), h.CreateLeadSource)
+	leads.Delete("/sources/:id", h.authHandlers.AuthMiddleware(), h.DeleteLeadSource)
+	leads.Get("/intake/:token", h.VerifyLeadIntake)
+	leads.Post("/intake/:token", h.ReceiveLeadIntake)
+
+	// Lead scoring rules and per-prospect scores (protected)
+	leads.Get("/scoring", h.authHandlers.AuthMiddleware(), h.GetLeadScoringConfig)
+	leads.Put("/scoring", h.authHandlers.AuthMiddleware(), h.SetLeadScoringConfig)
+	leads.Get("/:id/score", h.authHandlers.AuthMiddleware(), h.GetLeadScore)
+
+	// Outbound policy routes for the opt-out list and quiet hours (protected
+	// with authentication)
+	outbound := api.Group("/outbound")
+	outbound.Use(h.authHandlers.AuthMiddleware())
+	outbound.Get("/optouts", h.GetOptOuts)
+	outbound.Post("/optouts", h.AddOptOut)
+	outbound.Delete("/optouts/:prospect_num", h.RemoveOptOut)
+	outbound.Get("/quiet-hours", h.GetQuietHours)
+	outbound.Put("/quiet-hours", h.SaveQuietHours)
+	outbound.Get("/warmup", h.GetWarmupProfile)
+	outbound.Put("/warmup", h.SaveWarmupProfile)
+	outbound.Get("/warmup/usage", h.GetWarmupUsage)
+
+	// SLA routes for handoff first-response policies (protected with authentication)
+	sla := api.Group("/sla")
+	sla.Use(h.authHandlers.AuthMiddleware())
+	sla.Get("/policy", h.GetSLAPolicy)
+	sla.Put("/policy", h.SaveSLAPolicy)
+	sla.Get("/report", h.GetSLAComplianceReport)
+
 	// Authentication routes
 	h.authHandlers.SetupAuthRoutes(api)
 
@@ -236,8 +965,80 @@ func (h *Handlers) SetupRoutes(api fiber.Router) {
 	billing.Get("/orders", h.billingHandlers.GetOrders)          // Get user's orders
 	billing.Get("/all-orders", h.billingHandlers.GetAllOrders)   // Admin: Get all orders
 
+	// Failure-rate alerts raised by the alerting service
+	alerts := api.Group("/alerts")
+	alerts.Get("/", h.authHandlers.AuthMiddleware(), h.GetAlerts)
+
+	// Digest report history and on-demand generation
+	digests := api.Group("/digests")
+	digests.Use(h.authHandlers.AuthMiddleware())
+	digests.Get("/", h.GetDigestReports)
+	digests.Post("/generate", h.GenerateDigestReport)
+
+	// Data retention policies with dry-run preview and manual purge
+	retention := api.Group("/retention")
+	retention.Use(h.authHandlers.AuthMiddleware())
+	retention.Get("/", h.GetRetentionPolicy)
+	retention.Put("/", h.SaveRetentionPolicy)
+	retention.Get("/preview", h.PreviewRetentionPurge)
+	retention.Post("/run", h.RunRetentionPurge)
+
+	// Tenant backup export and restore
+	backup := api.Group("/backup")
+	backup.Use(h.authHandlers.AuthMiddleware())
+	backup.Get("/export", h.ExportTenantBackup)
+	backup.Post("/restore", h.RestoreTenantBackup)
+
+	// Analytics warehouse export connector with on-demand backfill
+	warehouse := api.Group("/warehouse")
+	warehouse.Use(h.authHandlers.AuthMiddleware())
+	warehouse.Get("/", h.GetWarehouseExportConfig)
+	warehouse.Put("/", h.SaveWarehouseExportConfig)
+	warehouse.Post("/backfill", h.RunWarehouseBackfill)
+
+	// Registry for webhook-backed custom node types
+	customNodes := api.Group("/custom-nodes")
+	customNodes.Use(h.authHandlers.AuthMiddleware())
+	customNodes.Get("/", h.GetCustomNodeTypes)
+	customNodes.Post("/", h.RegisterCustomNodeType)
+	customNodes.Put("/:id", h.UpdateCustomNodeType)
+	customNodes.Delete("/:id", h.DeleteCustomNodeType)
+
+	// Prospect stage journeys and time-in-stage reports
+	prospects := api.Group("/prospects")
+	prospects.Use(h.authHandlers.AuthMiddleware())
+	prospects.Get("/stage-report", h.GetStageDurationReport)
+	prospects.Get("/:id/stages", h.GetProspectStageHistory)
+
+	// Org-level contacts behind device-scoped prospects
+	contacts := api.Group("/contacts")
+	contacts.Use(h.authHandlers.AuthMiddleware())
+	contacts.Get("/duplicates", h.GetDuplicateProspects)
+	contacts.Get("/identity-mode", h.GetContactIdentityMode)
+	contacts.Put("/identity-mode", h.SetContactIdentityMode)
+	contacts.Get("/:phone", h.GetContact)
+	contacts.Get("/:phone/history", h.GetContactHistory)
+
+	// Slack/Discord notification connectors for operational events
+	notifications := api.Group("/notifications")
+	notifications.Use(h.authHandlers.AuthMiddleware())
+	notifications.Get("/connectors", h.GetNotificationConnectors)
+	notifications.Post("/connectors", h.CreateNotificationConnector)
+	notifications.Put("/connectors/:id", h.ToggleNotificationConnector)
+	notifications.Delete("/connectors/:id", h.DeleteNotificationConnector)
+
 	// Webhook routes for receiving messages from providers
 	webhook := api.Group("/webhook")
+	webhook.Get("/stats", h.authHandlers.AuthMiddleware(), h.GetWebhookStats)
+	webhook.Get("/unknown-payloads", h.authHandlers.AuthMiddleware(), h.GetUnknownWebhookPayloads)
+
+	// Lifecycle webhook subscriptions for execution state mirroring (protected
+	// with authentication; registered before the catch-all inbound route)
+	webhook.Get("/subscriptions", h.authHandlers.AuthMiddleware(), h.GetWebhookSubscriptions)
+	webhook.Post("/subscriptions", h.authHandlers.AuthMiddleware(), h.CreateWebhookSubscription)
+	webhook.Put("/subscriptions/:id", h.authHandlers.AuthMiddleware(), h.ToggleWebhookSubscription)
+	webhook.Delete("/subscriptions/:id", h.authHandlers.AuthMiddleware(), h.DeleteWebhookSubscription)
+
 	webhook.Post("/:id_device/:instance", h.HandleWebhook)
 }
 
@@ -304,6 +1105,10 @@ func (h *Handlers) CreateFlow(c *fiber.Ctx) error {
 	}
 
 	if err := h.flowService.CreateFlow(&flow); err != nil {
+		var validationErr *services.FlowValidationError
+		if errors.As(err, &validationErr) {
+			return h.errorResponse(c, 400, validationErr.Error())
+		}
 		logrus.WithError(err).Error("Failed to create flow")
 		return h.errorResponse(c, 500, "Failed to create flow")
 	}
@@ -345,6 +1150,10 @@ func (h *Handlers) UpdateFlow(c *fiber.Ctx) error {
 
 	flow.ID = flowID
 	if err := h.flowService.UpdateFlow(&flow); err != nil {
+		var validationErr *services.FlowValidationError
+		if errors.As(err, &validationErr) {
+			return h.errorResponse(c, 400, validationErr.Error())
+		}
 		logrus.WithError(err).Error("Failed to update flow")
 		return h.errorResponse(c, 500, "Failed to update flow")
 	}
@@ -474,5 +1283,3 @@ func (h *Handlers) HandleClearHealthCache(c *fiber.Ctx) error {
 		"message": "Health check cache cleared successfully",
 	})
 }
-
-
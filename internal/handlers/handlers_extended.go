@@ -97,6 +97,7 @@ type SendWhatsAppMessageRequest struct {
 	Message     string `json:"message"`
 	MediaURL    string `json:"media_url,omitempty"`
 	MediaType   string `json:"media_type,omitempty"`
+	DeviceID    string `json:"device_id,omitempty"`
 }
 
 // SendWhatsAppMessage sends a WhatsApp message
@@ -127,6 +128,11 @@ func (h *Handlers) SendWhatsAppMessage(c *fiber.Ctx) error {
 		return h.errorResponse(c, 500, "Failed to send message")
 	}
 
+	// An agent-sent message counts as the first response for SLA tracking
+	if req.DeviceID != "" && h.slaService != nil {
+		h.slaService.RecordFirstResponse(req.PhoneNumber, req.DeviceID)
+	}
+
 	return h.successMessageResponse(c, "Message sent successfully", nil)
 }
 
@@ -151,6 +157,15 @@ func (h *Handlers) GetQueueStats(c *fiber.Ctx) error {
 	return h.successResponse(c, stats)
 }
 
+// GetMessageWorkerMetrics returns webhook message queue occupancy and worker pool size
+func (h *Handlers) GetMessageWorkerMetrics(c *fiber.Ctx) error {
+	if h.whatsappService == nil {
+		return h.errorResponse(c, 503, "WhatsApp service not available")
+	}
+
+	return h.successResponse(c, h.whatsappService.QueueMetrics())
+}
+
 // ClearFailedQueue clears the failed message queue
 func (h *Handlers) ClearFailedQueue(c *fiber.Ctx) error {
 	if h.queueService == nil {
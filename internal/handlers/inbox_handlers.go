@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Inbox read-state handlers keep per-agent read cursors in sync so multiple
+// agents working the same device don't double-handle conversations
+
+// MarkConversationRead moves the authenticated agent's read cursor for a conversation
+func (h *Handlers) MarkConversationRead(c *fiber.Ctx) error {
+	agentID, ok := c.Locals("user_id").(string)
+	if !ok || agentID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	var req struct {
+		ProspectNum string `json:"prospect_num"`
+		IDDevice    string `json:"id_device"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.ProspectNum == "" || req.IDDevice == "" {
+		return h.errorResponse(c, 400, "prospect_num and id_device are required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	if err := h.readStateService.MarkConversationRead(agentID, req.ProspectNum, req.IDDevice); err != nil {
+		logrus.WithError(err).Error("Failed to mark conversation as read")
+		return h.errorResponse(c, 500, "Failed to update read state")
+	}
+
+	return h.successMessageResponse(c, "Conversation marked as read", nil)
+}
+
+// GetInboxUnreadStates returns per-conversation unread flags and the total unread
+// count for the authenticated agent on a device
+func (h *Handlers) GetInboxUnreadStates(c *fiber.Ctx) error {
+	agentID, ok := c.Locals("user_id").(string)
+	if !ok || agentID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	states, unreadCount, err := h.readStateService.GetUnreadStates(agentID, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get inbox unread states")
+		return h.errorResponse(c, 500, "Failed to retrieve unread states")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"conversations": states,
+		"unread_count":  unreadCount,
+	})
+}
+
+// GetHumanInboxConversations lists the user's conversations currently in
+// human takeover mode
+func (h *Handlers) GetHumanInboxConversations(c *fiber.Ctx) error {
+	agentID, ok := c.Locals("user_id").(string)
+	if !ok || agentID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	conversations, err := h.inboxService.ListHumanConversations(agentID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list human-mode conversations")
+		return h.errorResponse(c, 500, "Failed to retrieve inbox conversations")
+	}
+
+	return h.successResponse(c, conversations)
+}
+
+// AssignInboxConversation hands a human-mode conversation to a specific agent
+func (h *Handlers) AssignInboxConversation(c *fiber.Ctx) error {
+	agentID, ok := c.Locals("user_id").(string)
+	if !ok || agentID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	prospectNum := c.Params("conversation")
+	if prospectNum == "" {
+		return h.errorResponse(c, 400, "Conversation is required")
+	}
+
+	var req struct {
+		IDDevice string `json:"id_device"`
+		AgentID  string `json:"agent_id"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+	// Default to self-assignment when no target agent is given
+	if req.AgentID == "" {
+		req.AgentID = agentID
+	}
+
+	assignment, err := h.inboxService.AssignConversation(prospectNum, req.IDDevice, req.AgentID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to assign inbox conversation")
+		return h.errorResponse(c, 500, "Failed to assign conversation")
+	}
+
+	return h.successMessageResponse(c, "Conversation assigned successfully", assignment)
+}
+
+// ReplyInboxConversation sends an agent reply into a human-mode conversation
+func (h *Handlers) ReplyInboxConversation(c *fiber.Ctx) error {
+	agentID, ok := c.Locals("user_id").(string)
+	if !ok || agentID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	prospectNum := c.Params("conversation")
+	if prospectNum == "" {
+		return h.errorResponse(c, 400, "Conversation is required")
+	}
+
+	var req struct {
+		IDDevice string `json:"id_device"`
+		Message  string `json:"message"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" || req.Message == "" {
+		return h.errorResponse(c, 400, "id_device and message are required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.inboxService.SendAgentReply(req.IDDevice, prospectNum, agentID, req.Message); err != nil {
+		logrus.WithError(err).Error("Failed to send inbox reply")
+		return h.errorResponse(c, 500, err.Error())
+	}
+
+	return h.successMessageResponse(c, "Reply sent successfully", nil)
+}
+
+// ReturnInboxConversationToBot ends human takeover for a conversation
+func (h *Handlers) ReturnInboxConversationToBot(c *fiber.Ctx) error {
+	agentID, ok := c.Locals("user_id").(string)
+	if !ok || agentID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	prospectNum := c.Params("conversation")
+	if prospectNum == "" {
+		return h.errorResponse(c, 400, "Conversation is required")
+	}
+
+	var req struct {
+		IDDevice string `json:"id_device"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.inboxService.ReturnToBot(req.IDDevice, prospectNum, "agent_request"); err != nil {
+		logrus.WithError(err).Error("Failed to return conversation to bot")
+		return h.errorResponse(c, 500, "Failed to return conversation to bot")
+	}
+
+	return h.successMessageResponse(c, "Conversation returned to bot", nil)
+}
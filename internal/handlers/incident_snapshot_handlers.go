@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// CaptureIncidentSnapshot builds and serves a zipped diagnostic bundle for a
+// prospect/device pair, for attaching to bug reports. Pass ?prospect_num= and
+// ?device_id=
+func (h *Handlers) CaptureIncidentSnapshot(c *fiber.Ctx) error {
+	prospectNum := c.Query("prospect_num")
+	idDevice := c.Query("device_id")
+	if prospectNum == "" || idDevice == "" {
+		return h.errorResponse(c, 400, "prospect_num and device_id query parameters are required")
+	}
+
+	bundle, err := h.incidentSnapshotService.BuildSnapshot(prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to capture incident snapshot")
+		return h.errorResponse(c, 500, "Failed to capture incident snapshot")
+	}
+
+	filename := fmt.Sprintf("snapshot_%s_%s_%s.zip", idDevice, prospectNum, time.Now().Format("20060102-150405"))
+	c.Set("Content-Type", "application/zip")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	return c.Send(bundle)
+}
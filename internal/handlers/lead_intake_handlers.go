@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateLeadSourceRequest is the payload for configuring a lead intake source
+type CreateLeadSourceRequest struct {
+	IDDevice        string `json:"id_device"`
+	Name            string `json:"name"`
+	SourceType      string `json:"source_type"`
+	FlowID          string `json:"flow_id"`
+	TemplateMessage string `json:"template_message"`
+}
+
+// GetLeadSources returns a device's lead intake sources with lead counts
+func (h *Handlers) GetLeadSources(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	sources, err := h.leadIntakeService.GetLeadSources(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get lead sources")
+		return h.errorResponse(c, 500, "Failed to get lead sources")
+	}
+
+	return h.successResponse(c, sources)
+}
+
+// CreateLeadSource configures an intake source and returns its public URL
+func (h *Handlers) CreateLeadSource(c *fiber.Ctx) error {
+	var req CreateLeadSourceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	source, err := h.leadIntakeService.CreateLeadSource(req.IDDevice, req.Name, req.SourceType, req.FlowID, req.TemplateMessage)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create lead source")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, source)
+}
+
+// DeleteLeadSource removes an intake source. The owning device is passed as
+// ?id_device= so the delete stays scoped to devices the caller can access
+func (h *Handlers) DeleteLeadSource(c *fiber.Ctx) error {
+	id := c.Params("id")
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.leadIntakeService.DeleteLeadSource(id, idDevice); err != nil {
+		logrus.WithError(err).Error("Failed to delete lead source")
+		return h.errorResponse(c, 500, "Failed to delete lead source")
+	}
+
+	return h.successResponse(c, fiber.Map{"deleted": id})
+}
+
+// VerifyLeadIntake answers the Meta webhook verification handshake: Meta
+// calls GET with hub.verify_token and expects hub.challenge echoed back
+func (h *Handlers) VerifyLeadIntake(c *fiber.Ctx) error {
+	source, err := h.leadIntakeService.GetLeadSourceByToken(c.Params("token"))
+	if err != nil || source == nil {
+		return c.Status(404).SendString("Unknown lead source")
+	}
+
+	if c.Query("hub.verify_token") != source.VerifyToken {
+		return c.Status(403).SendString("Verify token mismatch")
+	}
+
+	return c.SendString(c.Query("hub.challenge"))
+}
+
+// ReceiveLeadIntake accepts a lead payload on a source's public intake URL,
+// creates the prospect, and starts the mapped flow
+func (h *Handlers) ReceiveLeadIntake(c *fiber.Ctx) error {
+	source, err := h.leadIntakeService.GetLeadSourceByToken(c.Params("token"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to look up lead source")
+		return h.errorResponse(c, 500, "Failed to look up lead source")
+	}
+	if source == nil {
+		return h.errorResponse(c, 404, "Unknown lead source")
+	}
+
+	lead, err := h.leadIntakeService.ExtractLead(source, c.Body())
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to extract lead from intake payload")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	h.leadIntakeService.CountLead(source.ID)
+
+	// Process off the request path so slow sends never time out the webhook
+	if h.whatsappService != nil {
+		go func() {
+			if err := h.whatsappService.ProcessLeadIntake(source, lead.Name, lead.Phone); err != nil {
+				logrus.WithError(err).Error("Failed to process lead intake")
+			}
+		}()
+	}
+
+	return h.successResponse(c, fiber.Map{"received": true})
+}
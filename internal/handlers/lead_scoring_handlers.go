@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetLeadScoringConfig returns the device's lead scoring rules
+func (h *Handlers) GetLeadScoringConfig(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	config, err := h.leadScoringService.GetConfig(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get lead scoring config")
+		return h.errorResponse(c, 500, "Failed to get lead scoring config")
+	}
+
+	return h.successResponse(c, config)
+}
+
+// SetLeadScoringConfig saves the device's lead scoring rules
+func (h *Handlers) SetLeadScoringConfig(c *fiber.Ctx) error {
+	var config models.LeadScoringConfig
+	if err := c.BodyParser(&config); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if config.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, config.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.leadScoringService.SaveConfig(&config); err != nil {
+		logrus.WithError(err).Error("Failed to save lead scoring config")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, config)
+}
+
+// GetLeadScore returns a prospect's current lead score
+func (h *Handlers) GetLeadScore(c *fiber.Ctx) error {
+	prospectNum := c.Params("id")
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	score, err := h.leadScoringService.Score(prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get lead score")
+		return h.errorResponse(c, 500, "Failed to get lead score")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"score":        score,
+	})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StreamSupportLogs tails recent and live log lines for one device or trace
+// over Server-Sent Events, so support can watch a customer's message
+// processing without server access. Lines are PII-redacted before capture.
+// Filter with ?device_id= or ?trace_id= (at least one is required)
+func (h *Handlers) StreamSupportLogs(c *fiber.Ctx) error {
+	deviceID := c.Query("device_id")
+	traceID := c.Query("trace_id")
+	if deviceID == "" && traceID == "" {
+		return h.errorResponse(c, 400, "device_id or trace_id query parameter is required")
+	}
+
+	id, lines, backlog := services.LogStream().Subscribe(deviceID, traceID)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer services.LogStream().Unsubscribe(id)
+
+		for _, line := range backlog {
+			if err := writeSSELogLine(w, line); err != nil {
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		// Heartbeats keep intermediaries from closing an idle stream
+		heartbeat := time.NewTicker(15 * time.Second)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					return
+				}
+				if err := writeSSELogLine(w, line); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := w.WriteString(": ping\n\n"); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeSSELogLine emits one log line as an SSE data event
+func writeSSELogLine(w *bufio.Writer, line services.LogLine) error {
+	payload, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", payload)
+	return err
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"strconv"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// ListConversationMessages returns a page of structured message rows for a
+// conversation, in chronological order. Pass ?device_id= (required) plus
+// optional ?limit= and ?offset= for pagination
+func (h *Handlers) ListConversationMessages(c *fiber.Ctx) error {
+	prospectNum := c.Params("prospect")
+	idDevice := c.Query("device_id")
+	if prospectNum == "" || idDevice == "" {
+		return h.errorResponse(c, 400, "prospect and device_id are required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	messages, total, err := h.aiWhatsappHandlers.AIRepo.ListMessages(prospectNum, idDevice, limit, offset)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list conversation messages")
+		return h.errorResponse(c, 500, "Failed to list conversation messages")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"messages":     messages,
+			"total":        total,
+			"limit":        limit,
+			"offset":       offset,
+		},
+	})
+}
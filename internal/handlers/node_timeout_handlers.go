@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetNodeTimeoutStats returns per-node-type watchdog timeout counts since
+// startup
+func (h *Handlers) GetNodeTimeoutStats(c *fiber.Ctx) error {
+	if h.whatsappService == nil {
+		return h.errorResponse(c, 503, "WhatsApp service not available")
+	}
+
+	return h.successResponse(c, h.whatsappService.NodeTimeoutStats())
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateNotificationConnectorRequest is the payload for registering a Slack
+// or Discord channel webhook
+type CreateNotificationConnectorRequest struct {
+	IDDevice   string `json:"id_device"`
+	Platform   string `json:"platform"`
+	WebhookURL string `json:"webhook_url"`
+	Events     string `json:"events"`
+}
+
+// ToggleNotificationConnectorRequest enables or disables a connector
+type ToggleNotificationConnectorRequest struct {
+	IDDevice string `json:"id_device"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// GetNotificationConnectors returns a device's notification connectors
+func (h *Handlers) GetNotificationConnectors(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	connectors, err := h.notificationService.GetConnectors(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get notification connectors")
+		return h.errorResponse(c, 500, "Failed to get notification connectors")
+	}
+
+	return h.successResponse(c, connectors)
+}
+
+// CreateNotificationConnector registers a Slack or Discord channel webhook
+// for operational events
+func (h *Handlers) CreateNotificationConnector(c *fiber.Ctx) error {
+	var req CreateNotificationConnectorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	connector, err := h.notificationService.CreateConnector(req.IDDevice, req.Platform, req.WebhookURL, req.Events)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create notification connector")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, connector)
+}
+
+// ToggleNotificationConnector enables or disables a connector
+func (h *Handlers) ToggleNotificationConnector(c *fiber.Ctx) error {
+	var req ToggleNotificationConnectorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.notificationService.SetConnectorEnabled(c.Params("id"), req.IDDevice, req.Enabled); err != nil {
+		logrus.WithError(err).Error("Failed to toggle notification connector")
+		return h.errorResponse(c, 500, "Failed to toggle notification connector")
+	}
+
+	return h.successResponse(c, fiber.Map{"id": c.Params("id"), "enabled": req.Enabled})
+}
+
+// DeleteNotificationConnector removes a connector. The owning device is
+// passed as ?id_device= so the delete stays scoped to devices the caller can
+// access
+func (h *Handlers) DeleteNotificationConnector(c *fiber.Ctx) error {
+	id := c.Params("id")
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.notificationService.DeleteConnector(id, idDevice); err != nil {
+		logrus.WithError(err).Error("Failed to delete notification connector")
+		return h.errorResponse(c, 500, "Failed to delete notification connector")
+	}
+
+	return h.successResponse(c, fiber.Map{"deleted": id})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetOnboardingState returns the user's wizard progress in step order
+func (h *Handlers) GetOnboardingState(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	state, err := h.onboardingService.State(userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get onboarding state")
+		return h.errorResponse(c, 500, "Failed to get onboarding state")
+	}
+
+	return h.successResponse(c, state)
+}
+
+// CompleteOnboardingStep marks one wizard step done
+func (h *Handlers) CompleteOnboardingStep(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	if err := h.onboardingService.CompleteStep(userID, c.Params("step")); err != nil {
+		logrus.WithError(err).Error("Failed to complete onboarding step")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, fiber.Map{"step": c.Params("step"), "completed": true})
+}
+
+// CreateStarterFlowRequest optionally names the device to bind the flow to
+type CreateStarterFlowRequest struct {
+	IDDevice string `json:"id_device"`
+}
+
+// CreateStarterFlow creates the one-click starter flow for the user's device
+func (h *Handlers) CreateStarterFlow(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	var req CreateStarterFlowRequest
+	if err := c.BodyParser(&req); err != nil && len(c.Body()) > 0 {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	flow, err := h.onboardingService.CreateStarterFlow(userID, req.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create starter flow")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, flow)
+}
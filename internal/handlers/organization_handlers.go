@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// orgErrorResponse maps organization service errors onto HTTP statuses
+func (h *Handlers) orgErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		return h.errorResponse(c, 404, "Organization not found")
+	case errors.Is(err, services.ErrOrganizationForbidden):
+		return h.errorResponse(c, 403, "Access denied: insufficient organization role")
+	default:
+		return h.errorResponse(c, 400, err.Error())
+	}
+}
+
+// CreateOrganization creates an organization owned by the authenticated user
+func (h *Handlers) CreateOrganization(c *fiber.Ctx) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok || userIDStr == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	var req models.CreateOrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	org, err := h.organizationService.CreateOrganization(userIDStr, req.Name)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create organization")
+		return h.orgErrorResponse(c, err)
+	}
+
+	return h.successMessageResponse(c, "Organization created successfully", org)
+}
+
+// GetOrganizations lists the authenticated user's organizations
+func (h *Handlers) GetOrganizations(c *fiber.Ctx) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok || userIDStr == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	orgs, err := h.organizationService.ListOrganizations(userIDStr)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list organizations")
+		return h.errorResponse(c, 500, "Failed to retrieve organizations")
+	}
+
+	return h.successResponse(c, orgs)
+}
+
+// GetOrganizationMembers lists an organization's members
+func (h *Handlers) GetOrganizationMembers(c *fiber.Ctx) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok || userIDStr == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	orgID := c.Params("id")
+	if orgID == "" {
+		return h.errorResponse(c, 400, "Organization ID is required")
+	}
+
+	members, err := h.organizationService.ListMembers(userIDStr, orgID)
+	if err != nil {
+		return h.orgErrorResponse(c, err)
+	}
+
+	return h.successResponse(c, members)
+}
+
+// AddOrganizationMember adds a user to an organization or changes their role
+func (h *Handlers) AddOrganizationMember(c *fiber.Ctx) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok || userIDStr == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	orgID := c.Params("id")
+	if orgID == "" {
+		return h.errorResponse(c, 400, "Organization ID is required")
+	}
+
+	var req models.AddOrganizationMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.UserID == "" {
+		return h.errorResponse(c, 400, "Member user ID is required")
+	}
+
+	if err := h.organizationService.AddMember(userIDStr, orgID, req.UserID, req.Role); err != nil {
+		return h.orgErrorResponse(c, err)
+	}
+
+	return h.successMessageResponse(c, "Member added successfully", nil)
+}
+
+// RemoveOrganizationMember removes a user from an organization
+func (h *Handlers) RemoveOrganizationMember(c *fiber.Ctx) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok || userIDStr == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	orgID := c.Params("id")
+	memberID := c.Params("user_id")
+	if orgID == "" || memberID == "" {
+		return h.errorResponse(c, 400, "Organization ID and member user ID are required")
+	}
+
+	if err := h.organizationService.RemoveMember(userIDStr, orgID, memberID); err != nil {
+		return h.orgErrorResponse(c, err)
+	}
+
+	return h.successMessageResponse(c, "Member removed successfully", nil)
+}
+
+// canAccessDeviceSetting reports whether the user may access the device
+// setting at the required organization role. Owners always pass; legacy
+// settings without a user keep their historical open access
+func (h *Handlers) canAccessDeviceSetting(setting *models.DeviceSettings, userID, requiredRole string) bool {
+	if !setting.UserID.Valid || setting.UserID.String == userID {
+		return true
+	}
+	if h.organizationService == nil {
+		return false
+	}
+	return h.organizationService.CanAccessUserResources(userID, setting.UserID.String, requiredRole)
+}
+
+// requireDeviceAccess verifies the authenticated user owns the device (or
+// shares an organization with its owner at the required role or above)
+// before any device-scoped data is touched. Returns a ready error response
+// when access is denied, nil when the caller may proceed
+func (h *Handlers) requireDeviceAccess(c *fiber.Ctx, idDevice, requiredRole string) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok || userIDStr == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	setting, err := h.deviceSettingsService.GetByIDDevice(idDevice)
+	if err != nil {
+		if err.Error() == "device setting not found" {
+			return h.errorResponse(c, 404, "Device not found")
+		}
+		logrus.WithError(err).Error("Failed to check device ownership")
+		return h.errorResponse(c, 500, "Failed to verify device access")
+	}
+
+	if !h.canAccessDeviceSetting(setting, userIDStr, requiredRole) {
+		return h.errorResponse(c, 403, "You do not have access to this device")
+	}
+	return nil
+}
+
+// requireFlowAccess resolves a flow to its owning device and applies the same
+// ownership check as requireDeviceAccess. Flows without a device keep their
+// historical open access. Returns a ready error response when access is
+// denied, nil when the caller may proceed
+func (h *Handlers) requireFlowAccess(c *fiber.Ctx, flowID, requiredRole string) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok || userIDStr == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	flow, err := h.flowService.GetFlow(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to check flow ownership")
+		return h.errorResponse(c, 500, "Failed to verify flow access")
+	}
+	if flow == nil {
+		return h.errorResponse(c, 404, "Flow not found")
+	}
+	if flow.IdDevice == "" {
+		return nil
+	}
+
+	return h.requireDeviceAccess(c, flow.IdDevice, requiredRole)
+}
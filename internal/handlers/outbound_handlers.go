@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// StartFlowRequest is the payload for starting a flow proactively
+type StartFlowRequest struct {
+	PhoneNumber string                 `json:"phone_number"`
+	IDDevice    string                 `json:"id_device"`
+	Variables   map[string]interface{} `json:"variables"`
+}
+
+// OptOutRequest is the payload for opt-out list changes
+type OptOutRequest struct {
+	IDDevice    string `json:"id_device"`
+	ProspectNum string `json:"prospect_num"`
+}
+
+// StartFlowForNumber starts a flow for a phone number and sends its first
+// messages proactively. Returns 403 when the opt-out list or quiet hours
+// block the send
+func (h *Handlers) StartFlowForNumber(c *fiber.Ctx) error {
+	var req StartFlowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.PhoneNumber == "" || req.IDDevice == "" {
+		return h.errorResponse(c, 400, "phone_number and id_device are required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+	if req.Variables == nil {
+		req.Variables = make(map[string]interface{})
+	}
+
+	blockReason, err := h.whatsappService.StartFlowForProspect(c.Params("id"), req.IDDevice, req.PhoneNumber, req.Variables)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to start flow for number")
+		return h.errorResponse(c, 500, "Failed to start flow")
+	}
+	if blockReason != "" {
+		return h.errorResponse(c, 403, blockReason)
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"flow_id":      c.Params("id"),
+		"phone_number": req.PhoneNumber,
+		"started":      true,
+	})
+}
+
+// GetOptOuts returns the device's opt-out list
+func (h *Handlers) GetOptOuts(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	optOuts, err := h.outboundPolicyService.GetOptOuts(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get opt-outs")
+		return h.errorResponse(c, 500, "Failed to get opt-outs")
+	}
+
+	return h.successResponse(c, optOuts)
+}
+
+// AddOptOut puts a number on the device's opt-out list
+func (h *Handlers) AddOptOut(c *fiber.Ctx) error {
+	var req OptOutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" || req.ProspectNum == "" {
+		return h.errorResponse(c, 400, "id_device and prospect_num are required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.outboundPolicyService.AddOptOut(req.IDDevice, req.ProspectNum); err != nil {
+		logrus.WithError(err).Error("Failed to add opt-out")
+		return h.errorResponse(c, 500, "Failed to add opt-out")
+	}
+
+	return h.successResponse(c, req)
+}
+
+// RemoveOptOut takes a number off the device's opt-out list
+func (h *Handlers) RemoveOptOut(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	prospectNum := c.Params("prospect_num")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.outboundPolicyService.RemoveOptOut(idDevice, prospectNum); err != nil {
+		logrus.WithError(err).Error("Failed to remove opt-out")
+		return h.errorResponse(c, 500, "Failed to remove opt-out")
+	}
+
+	return h.successResponse(c, fiber.Map{"removed": prospectNum})
+}
+
+// GetQuietHours returns the device's quiet hours
+func (h *Handlers) GetQuietHours(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	quietHours, err := h.outboundPolicyService.GetQuietHours(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get quiet hours")
+		return h.errorResponse(c, 500, "Failed to get quiet hours")
+	}
+
+	return h.successResponse(c, quietHours)
+}
+
+// SaveQuietHours saves the device's quiet hours
+func (h *Handlers) SaveQuietHours(c *fiber.Ctx) error {
+	var quietHours models.QuietHours
+	if err := c.BodyParser(&quietHours); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if quietHours.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, quietHours.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.outboundPolicyService.SetQuietHours(&quietHours); err != nil {
+		logrus.WithError(err).Error("Failed to save quiet hours")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, quietHours)
+}
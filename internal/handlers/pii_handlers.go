@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Role handlers manage the viewer/admin split behind PII masking
+
+// GetMyRole returns the authenticated user's access role
+func (h *Handlers) GetMyRole(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"role": h.piiService.RoleForUser(userID),
+	})
+}
+
+// SetUserRole assigns a role to a user. Only explicitly assigned admins can
+// change roles; until the first admin exists, any authenticated user may
+// bootstrap one
+func (h *Handlers) SetUserRole(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.CanManageRoles(userID) {
+		return h.errorResponse(c, 403, "Only admins can change roles")
+	}
+
+	var req struct {
+		UserID string `json:"user_id"`
+		Role   string `json:"role"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.UserID == "" || req.Role == "" {
+		return h.errorResponse(c, 400, "user_id and role are required")
+	}
+
+	if err := h.piiService.SetRole(req.UserID, req.Role); err != nil {
+		logrus.WithError(err).Error("Failed to set user role")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successMessageResponse(c, "Role updated", nil)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
+)
+
+// SetupDebugRoutes registers the pprof endpoints under /debug/pprof for
+// profiling engine hot paths. They require an authenticated session with the
+// admin role, so profiles never leak to regular users
+func (h *Handlers) SetupDebugRoutes(app *fiber.App) {
+	app.Use("/debug/pprof", h.authHandlers.AuthMiddleware(), h.requireAdminRole, pprof.New())
+}
+
+// requireAdminRole blocks users without an explicitly assigned admin role.
+// Unassigned users are denied: operational endpoints fail closed, unlike
+// PII masking where unassigned users keep full access
+func (h *Handlers) requireAdminRole(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+	return c.Next()
+}
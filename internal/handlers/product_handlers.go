@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetProducts returns the product catalog for a device
+func (h *Handlers) GetProducts(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	products, err := h.productService.GetProducts(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get products")
+		return h.errorResponse(c, 500, "Failed to get products")
+	}
+
+	return h.successResponse(c, products)
+}
+
+// GetProduct returns a single product by ID
+func (h *Handlers) GetProduct(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	product, err := h.productService.GetProduct(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get product")
+		return h.errorResponse(c, 500, "Failed to get product")
+	}
+	if product == nil {
+		return h.errorResponse(c, 404, "Product not found")
+	}
+	if err := h.requireDeviceAccess(c, product.IDDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	return h.successResponse(c, product)
+}
+
+// CreateProduct adds a product to a device's catalog
+func (h *Handlers) CreateProduct(c *fiber.Ctx) error {
+	var product models.Product
+	if err := c.BodyParser(&product); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if product.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if err := h.requireDeviceAccess(c, product.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.productService.CreateProduct(&product); err != nil {
+		logrus.WithError(err).Error("Failed to create product")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, product)
+}
+
+// UpdateProduct updates an existing catalog product
+func (h *Handlers) UpdateProduct(c *fiber.Ctx) error {
+	var product models.Product
+	if err := c.BodyParser(&product); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	product.ID = c.Params("id")
+
+	existing, err := h.productService.GetProduct(product.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get product")
+		return h.errorResponse(c, 500, "Failed to update product")
+	}
+	if existing == nil {
+		return h.errorResponse(c, 404, "Product not found")
+	}
+	if err := h.requireDeviceAccess(c, existing.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+	// Products cannot be moved between devices
+	product.IDDevice = existing.IDDevice
+
+	if err := h.productService.UpdateProduct(&product); err != nil {
+		logrus.WithError(err).Error("Failed to update product")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, product)
+}
+
+// DeleteProduct removes a product from the catalog
+func (h *Handlers) DeleteProduct(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	existing, err := h.productService.GetProduct(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get product")
+		return h.errorResponse(c, 500, "Failed to delete product")
+	}
+	if existing == nil {
+		return h.errorResponse(c, 404, "Product not found")
+	}
+	if err := h.requireDeviceAccess(c, existing.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.productService.DeleteProduct(id); err != nil {
+		logrus.WithError(err).Error("Failed to delete product")
+		return h.errorResponse(c, 500, "Failed to delete product")
+	}
+
+	return h.successResponse(c, fiber.Map{"deleted": id})
+}
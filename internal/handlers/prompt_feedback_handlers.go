@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"nodepath-chat/internal/models"
+)
+
+// Prompt feedback handlers: captured agent edits to AI responses are
+// aggregated per flow into a correction report and system-prompt suggestions
+
+// RecordFlowMessageEdit captures a diff between an AI-proposed message and
+// the text an agent sent from the takeover console
+func (h *Handlers) RecordFlowMessageEdit(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"success": false,
+			"error":   "User not authenticated",
+		})
+	}
+
+	var req struct {
+		NodeID      string `json:"node_id"`
+		IDDevice    string `json:"id_device"`
+		ProspectNum string `json:"prospect_num"`
+		Original    string `json:"original"`
+		Edited      string `json:"edited"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	err := h.promptFeedbackService.RecordEdit(&models.RecordedEdit{
+		FlowID:      flowID,
+		NodeID:      req.NodeID,
+		IDDevice:    req.IDDevice,
+		ProspectNum: req.ProspectNum,
+		Source:      models.MessageEditSourceTakeover,
+		Original:    req.Original,
+		Edited:      req.Edited,
+		EditedBy:    userID,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Failed to record message edit")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to record message edit",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Edit recorded",
+	})
+}
+
+// GetPromptFeedbackReport returns the flow's aggregated agent corrections
+func (h *Handlers) GetPromptFeedbackReport(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	report, err := h.promptFeedbackService.Report(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to build prompt feedback report")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to build feedback report",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetPromptSuggestions returns system-prompt amendments derived from the
+// flow's recurring agent corrections
+func (h *Handlers) GetPromptSuggestions(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	suggestions, err := h.promptFeedbackService.Suggest(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to build prompt suggestions")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to build prompt suggestions",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    suggestions,
+	})
+}
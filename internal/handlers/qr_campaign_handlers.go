@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateQRCampaignRequest is the payload for creating a QR entry campaign
+type CreateQRCampaignRequest struct {
+	IDDevice string `json:"id_device"`
+	Name     string `json:"name"`
+	FlowID   string `json:"flow_id"`
+	Keyword  string `json:"keyword"`
+}
+
+// GetQRCampaigns returns a device's QR campaigns with scan counts
+func (h *Handlers) GetQRCampaigns(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	campaigns, err := h.qrCampaignService.GetCampaigns(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get QR campaigns")
+		return h.errorResponse(c, 500, "Failed to get QR campaigns")
+	}
+
+	return h.successResponse(c, campaigns)
+}
+
+// CreateQRCampaign creates a campaign and returns its wa.me invite link
+func (h *Handlers) CreateQRCampaign(c *fiber.Ctx) error {
+	var req CreateQRCampaignRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	campaign, err := h.qrCampaignService.CreateCampaign(req.IDDevice, req.Name, req.FlowID, req.Keyword)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create QR campaign")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, campaign)
+}
+
+// GetQRCampaignImage renders a campaign's QR code as PNG or SVG
+func (h *Handlers) GetQRCampaignImage(c *fiber.Ctx) error {
+	campaign, err := h.qrCampaignService.GetCampaign(c.Params("id"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get QR campaign")
+		return h.errorResponse(c, 500, "Failed to get QR campaign")
+	}
+	if campaign == nil {
+		return h.errorResponse(c, 404, "QR campaign not found")
+	}
+	if err := h.requireDeviceAccess(c, campaign.IDDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	image, contentType, err := h.qrCampaignService.RenderQRImage(campaign, c.Query("format", "png"), c.QueryInt("size", 300))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to render QR campaign image")
+		return h.errorResponse(c, 502, "Failed to render QR code image")
+	}
+
+	c.Set("Content-Type", contentType)
+	return c.Send(image)
+}
+
+// DeleteQRCampaign removes a campaign
+func (h *Handlers) DeleteQRCampaign(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	campaign, err := h.qrCampaignService.GetCampaign(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get QR campaign")
+		return h.errorResponse(c, 500, "Failed to delete QR campaign")
+	}
+	if campaign == nil {
+		return h.errorResponse(c, 404, "QR campaign not found")
+	}
+	if err := h.requireDeviceAccess(c, campaign.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.qrCampaignService.DeleteCampaign(id); err != nil {
+		logrus.WithError(err).Error("Failed to delete QR campaign")
+		return h.errorResponse(c, 500, "Failed to delete QR campaign")
+	}
+
+	return h.successResponse(c, fiber.Map{"deleted": id})
+}
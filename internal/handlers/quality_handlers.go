@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Quality handlers expose the automated conversation evaluations and the
+// per-flow-version score comparison
+
+// GetQualityScores returns a device's conversation evaluations, newest first
+func (h *Handlers) GetQualityScores(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   "id_device query parameter is required",
+		})
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	scores, err := h.qualityService.Scores(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get quality scores")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get quality scores",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    scores,
+	})
+}
+
+// GetFlowQualitySummary returns the flow's averaged rubric scores per
+// version so prompt changes can be compared objectively
+func (h *Handlers) GetFlowQualitySummary(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	summaries, err := h.qualityService.VersionSummaries(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get flow quality summary")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get quality summary",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    summaries,
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// RedTeamRunRequest optionally extends the builtin probe battery
+type RedTeamRunRequest struct {
+	Probes []services.RedTeamProbe `json:"probes"`
+}
+
+// RunFlowRedTeam fires the adversarial prompt battery at the flow's AI nodes
+// and returns the report of unsafe responses
+func (h *Handlers) RunFlowRedTeam(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	var req RedTeamRunRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request body",
+			})
+		}
+	}
+
+	for _, probe := range req.Probes {
+		if probe.Prompt == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"error":   "Custom probes need a prompt",
+			})
+		}
+	}
+
+	report, err := h.redTeamService.Run(flowID, req.Probes)
+	if err != nil {
+		logrus.WithError(err).WithField("flow_id", flowID).Error("Red team run failed")
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    report,
+	})
+}
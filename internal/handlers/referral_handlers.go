@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetReferralLink returns (creating if needed) a prospect's wa.me invite link
+func (h *Handlers) GetReferralLink(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	prospectNum := c.Query("prospect_num")
+	if idDevice == "" || prospectNum == "" {
+		return h.errorResponse(c, 400, "id_device and prospect_num query parameters are required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	referral, err := h.referralService.GetOrCreateInviteLink(idDevice, prospectNum)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get referral link")
+		return h.errorResponse(c, 500, "Failed to get referral link")
+	}
+
+	return h.successResponse(c, referral)
+}
+
+// GetReferralLeaderboard returns the device's top referrers
+func (h *Handlers) GetReferralLeaderboard(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	entries, err := h.referralService.GetLeaderboard(idDevice, c.QueryInt("limit", 10))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get referral leaderboard")
+		return h.errorResponse(c, 500, "Failed to get referral leaderboard")
+	}
+
+	return h.successResponse(c, entries)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetRetentionPolicy returns the device's data retention policy
+func (h *Handlers) GetRetentionPolicy(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	policy, err := h.retentionService.GetPolicy(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get retention policy")
+		return h.errorResponse(c, 500, "Failed to get retention policy")
+	}
+
+	return h.successResponse(c, policy)
+}
+
+// SaveRetentionPolicy saves the device's data retention policy
+func (h *Handlers) SaveRetentionPolicy(c *fiber.Ctx) error {
+	var policy models.RetentionPolicy
+	if err := c.BodyParser(&policy); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if policy.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	// Retention policies decide what gets deleted - admin-level access only
+	if err := h.requireDeviceAccess(c, policy.IDDevice, models.OrgRoleAdmin); err != nil {
+		return err
+	}
+
+	if err := h.retentionService.SavePolicy(&policy); err != nil {
+		logrus.WithError(err).Error("Failed to save retention policy")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, policy)
+}
+
+// PreviewRetentionPurge reports how many rows enforcing the policy now would
+// touch, without deleting anything
+func (h *Handlers) PreviewRetentionPurge(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	preview, err := h.retentionService.Preview(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to preview retention purge")
+		return h.errorResponse(c, 500, "Failed to preview retention purge")
+	}
+
+	return h.successResponse(c, preview)
+}
+
+// RunRetentionPurgeRequest identifies the device to purge
+type RunRetentionPurgeRequest struct {
+	IDDevice string `json:"id_device"`
+}
+
+// RunRetentionPurge enforces the device's retention policy immediately
+func (h *Handlers) RunRetentionPurge(c *fiber.Ctx) error {
+	var req RunRetentionPurgeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	// Purging destroys conversation history - admin-level access only
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAdmin); err != nil {
+		return err
+	}
+
+	result, err := h.retentionService.Enforce(req.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to run retention purge")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, result)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetSavedViews lists the user's saved views plus views shared to the device
+func (h *Handlers) GetSavedViews(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	views, err := h.savedViewService.List(userID, c.Query("scope"), c.Query("id_device"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list saved views")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, views)
+}
+
+// CreateSavedView stores a new view for the user
+func (h *Handlers) CreateSavedView(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	var view models.SavedView
+	if err := c.BodyParser(&view); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	view.UserID = userID
+
+	if err := h.savedViewService.Create(&view); err != nil {
+		logrus.WithError(err).Error("Failed to create saved view")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, view)
+}
+
+// UpdateSavedView changes an owned view's definition
+func (h *Handlers) UpdateSavedView(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid view ID")
+	}
+
+	var view models.SavedView
+	if err := c.BodyParser(&view); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	view.ID = id
+	view.UserID = userID
+
+	if err := h.savedViewService.Update(&view); err != nil {
+		logrus.WithError(err).Error("Failed to update saved view")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, view)
+}
+
+// DeleteSavedView removes an owned view
+func (h *Handlers) DeleteSavedView(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid view ID")
+	}
+
+	if err := h.savedViewService.Delete(id, userID); err != nil {
+		logrus.WithError(err).Error("Failed to delete saved view")
+		return h.errorResponse(c, 404, "View not found")
+	}
+
+	return h.successResponse(c, fiber.Map{"deleted": id})
+}
+
+// SetDefaultSavedView makes one owned view the user's default for its scope
+func (h *Handlers) SetDefaultSavedView(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid view ID")
+	}
+
+	if err := h.savedViewService.SetDefault(id, userID); err != nil {
+		logrus.WithError(err).Error("Failed to set default view")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, fiber.Map{"id": id, "is_default": true})
+}
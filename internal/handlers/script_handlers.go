@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetFlowScriptPermission reports whether script nodes may run in the flow
+func (h *Handlers) GetFlowScriptPermission(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"flow_id": flowID,
+		"enabled": h.scriptService.IsFlowScriptEnabled(flowID),
+	})
+}
+
+// SetFlowScriptPermissionRequest toggles script execution for a flow
+type SetFlowScriptPermissionRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SetFlowScriptPermission grants or revokes script execution for a flow
+func (h *Handlers) SetFlowScriptPermission(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	var req SetFlowScriptPermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	// Script nodes run arbitrary code against conversations - admin-level access only
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleAdmin); err != nil {
+		return err
+	}
+
+	if err := h.scriptService.SetFlowScriptEnabled(flowID, req.Enabled); err != nil {
+		logrus.WithError(err).Error("Failed to set script permission")
+		return h.errorResponse(c, 500, "Failed to set script permission")
+	}
+
+	return h.successResponse(c, fiber.Map{"flow_id": flowID, "enabled": req.Enabled})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"strconv"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Semantic search handlers rank conversations by meaning using embeddings,
+// complementing the keyword full-text search
+
+// SemanticSearchConversations returns the device's conversations closest in
+// meaning to the query, best match first
+func (h *Handlers) SemanticSearchConversations(c *fiber.Ctx) error {
+	deviceID := c.Query("device_id")
+	if deviceID == "" {
+		return h.errorResponse(c, 400, "device_id query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, deviceID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	query := c.Query("q")
+	if query == "" {
+		return h.errorResponse(c, 400, "q query parameter is required")
+	}
+
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, _ = strconv.Atoi(limitParam)
+	}
+
+	results, err := h.semanticSearchService.Search(deviceID, query, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to run semantic conversation search")
+		return h.errorResponse(c, 500, "Failed to search conversations")
+	}
+
+	// Viewer-role users get prospect numbers masked
+	if userID, ok := c.Locals("user_id").(string); ok && h.piiService.IsViewer(userID) {
+		for i := range results {
+			results[i].ProspectNum = services.MaskPhone(results[i].ProspectNum)
+		}
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"query":   query,
+		"results": results,
+	})
+}
+
+// ReindexConversationEmbeddings (re)embeds the device's conversation
+// history into the semantic index, skipping unchanged chunks
+func (h *Handlers) ReindexConversationEmbeddings(c *fiber.Ctx) error {
+	var req struct {
+		DeviceID string `json:"device_id"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.DeviceID == "" {
+		return h.errorResponse(c, 400, "device_id is required")
+	}
+	if err := h.requireDeviceAccess(c, req.DeviceID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	indexed, err := h.semanticSearchService.ReindexDevice(req.DeviceID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reindex conversation embeddings")
+		return h.errorResponse(c, 500, "Failed to reindex conversations")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"indexed_chunks": indexed,
+	})
+}
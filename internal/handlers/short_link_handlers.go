@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateShortLinkRequest is the payload for shortening a URL
+type CreateShortLinkRequest struct {
+	IDDevice   string `json:"id_device"`
+	TargetURL  string `json:"target_url"`
+	ExpiryDays int    `json:"expiry_days"`
+}
+
+// ShortLinkSettingRequest toggles automatic outbound URL shortening
+type ShortLinkSettingRequest struct {
+	IDDevice    string `json:"id_device"`
+	AutoShorten bool   `json:"auto_shorten"`
+}
+
+// GetShortLinks returns a device's short links with click counts
+func (h *Handlers) GetShortLinks(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	links, err := h.shortLinkService.GetLinks(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get short links")
+		return h.errorResponse(c, 500, "Failed to get short links")
+	}
+
+	return h.successResponse(c, links)
+}
+
+// CreateShortLink shortens a URL and returns its public redirect
+func (h *Handlers) CreateShortLink(c *fiber.Ctx) error {
+	var req CreateShortLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	link, err := h.shortLinkService.CreateShortLink(req.IDDevice, "", req.TargetURL, req.ExpiryDays)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create short link")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, link)
+}
+
+// DeleteShortLink removes a short link. The owning device is passed as
+// ?id_device= so the delete stays scoped to devices the caller can access
+func (h *Handlers) DeleteShortLink(c *fiber.Ctx) error {
+	id := c.Params("id")
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.shortLinkService.DeleteLink(id, idDevice); err != nil {
+		logrus.WithError(err).Error("Failed to delete short link")
+		return h.errorResponse(c, 500, "Failed to delete short link")
+	}
+
+	return h.successResponse(c, fiber.Map{"deleted": id})
+}
+
+// GetShortLinkSetting returns a device's auto-shorten toggle
+func (h *Handlers) GetShortLinkSetting(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	enabled, err := h.shortLinkService.GetAutoShorten(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get short link setting")
+		return h.errorResponse(c, 500, "Failed to get short link setting")
+	}
+
+	return h.successResponse(c, fiber.Map{"id_device": idDevice, "auto_shorten": enabled})
+}
+
+// SaveShortLinkSetting sets a device's auto-shorten toggle
+func (h *Handlers) SaveShortLinkSetting(c *fiber.Ctx) error {
+	var req ShortLinkSettingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.shortLinkService.SetAutoShorten(req.IDDevice, req.AutoShorten); err != nil {
+		logrus.WithError(err).Error("Failed to save short link setting")
+		return h.errorResponse(c, 500, "Failed to save short link setting")
+	}
+
+	return h.successResponse(c, fiber.Map{"id_device": req.IDDevice, "auto_shorten": req.AutoShorten})
+}
+
+// RedirectShortLink resolves /s/:code and redirects to the target URL. Clicks
+// on prospect-attributed links are handed to the flow engine so a waiting
+// flow can resume (e.g. "clicked pricing page" moves the prospect on)
+func (h *Handlers) RedirectShortLink(c *fiber.Ctx) error {
+	link, err := h.shortLinkService.Resolve(c.Params("code"))
+	if err != nil {
+		if errors.Is(err, services.ErrShortLinkNotFound) {
+			return c.Status(404).SendString("Link not found")
+		}
+		if errors.Is(err, services.ErrShortLinkExpired) {
+			return c.Status(410).SendString("Link expired")
+		}
+		logrus.WithError(err).Error("Failed to resolve short link")
+		return c.Status(500).SendString("Failed to resolve link")
+	}
+
+	// Trigger flow resumption off the request path so the redirect stays fast
+	if h.whatsappService != nil {
+		go h.whatsappService.HandleShortLinkClick(link)
+	}
+
+	return c.Redirect(link.TargetURL, fiber.StatusFound)
+}
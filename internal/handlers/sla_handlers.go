@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// SLA handlers manage per-device first-response policies for human handoffs
+// and expose compliance reporting
+
+// GetSLAPolicy returns the SLA policy for a device
+func (h *Handlers) GetSLAPolicy(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	policy, err := h.slaService.GetPolicy(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get SLA policy")
+		return h.errorResponse(c, 500, "Failed to retrieve SLA policy")
+	}
+
+	if policy == nil {
+		return h.errorResponse(c, 404, "No SLA policy configured for this device")
+	}
+
+	return h.successResponse(c, policy)
+}
+
+// SaveSLAPolicy creates or updates the SLA policy for a device
+func (h *Handlers) SaveSLAPolicy(c *fiber.Ctx) error {
+	var policy models.SLAPolicy
+	if err := c.BodyParser(&policy); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if policy.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, policy.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.slaService.SavePolicy(&policy); err != nil {
+		logrus.WithError(err).Error("Failed to save SLA policy")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successMessageResponse(c, "SLA policy saved", policy)
+}
+
+// GetSLAComplianceReport summarizes SLA performance for a device
+func (h *Handlers) GetSLAComplianceReport(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	report, err := h.slaService.GetComplianceReport(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get SLA compliance report")
+		return h.errorResponse(c, 500, "Failed to retrieve compliance report")
+	}
+
+	return h.successResponse(c, report)
+}
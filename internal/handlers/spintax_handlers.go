@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SpintaxPreviewRequest represents a spintax preview request
+type SpintaxPreviewRequest struct {
+	Text    string `json:"text"`
+	Samples int    `json:"samples"`
+}
+
+// previewSampleLimit caps how many expansions a single preview returns
+const previewSampleLimit = 20
+
+// PreviewSpintax expands a spintax template several times so the flow
+// editor can show users what variations their message will produce
+func (h *Handlers) PreviewSpintax(c *fiber.Ctx) error {
+	var req SpintaxPreviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.Text == "" {
+		return h.errorResponse(c, 400, "text is required")
+	}
+
+	samples := req.Samples
+	if samples <= 0 {
+		samples = 5
+	}
+	if samples > previewSampleLimit {
+		samples = previewSampleLimit
+	}
+
+	expansions := make([]string, 0, samples)
+	for i := 0; i < samples; i++ {
+		expansions = append(expansions, utils.ExpandSpintax(req.Text))
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"text":    req.Text,
+		"samples": expansions,
+	})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetProspectStageHistory returns a prospect's recorded stage journey,
+// oldest transition first
+func (h *Handlers) GetProspectStageHistory(c *fiber.Ctx) error {
+	prospectNum := c.Params("id")
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	history, err := h.stageHistoryService.History(prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get stage history")
+		return h.errorResponse(c, 500, "Failed to get stage history")
+	}
+
+	return h.successResponse(c, history)
+}
+
+// GetStageDurationReport returns the device's average time-in-stage report
+func (h *Handlers) GetStageDurationReport(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	report, err := h.stageHistoryService.AverageTimeInStage(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to compute stage duration report")
+		return h.errorResponse(c, 500, "Failed to compute stage duration report")
+	}
+
+	return h.successResponse(c, report)
+}
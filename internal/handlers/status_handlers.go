@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"database/sql"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetPublicStatus serves the unauthenticated status page summary. The
+// payload is cached inside the service so public traffic never hits the
+// database directly
+func (h *Handlers) GetPublicStatus(c *fiber.Ctx) error {
+	c.Set("Cache-Control", "public, max-age=30")
+	return c.JSON(h.statusService.PublicStatus())
+}
+
+// GetIncidents lists recent incidents including resolved ones (admin)
+func (h *Handlers) GetIncidents(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+
+	incidents, err := h.statusService.Incidents()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get incidents")
+		return h.errorResponse(c, 500, "Failed to get incidents")
+	}
+
+	return h.successResponse(c, incidents)
+}
+
+// CreateIncidentRequest carries a new status page incident
+type CreateIncidentRequest struct {
+	Title    string `json:"title"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// CreateIncident posts a new incident to the status page (admin)
+func (h *Handlers) CreateIncident(c *fiber.Ctx) error {
+	var req CreateIncidentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+
+	incident, err := h.statusService.CreateIncident(req.Title, req.Severity, req.Message)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create incident")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, incident)
+}
+
+// UpdateIncidentRequest carries an incident status change
+type UpdateIncidentRequest struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// UpdateIncident changes an incident's status and latest message (admin)
+func (h *Handlers) UpdateIncident(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return h.errorResponse(c, 400, "Invalid incident ID")
+	}
+
+	var req UpdateIncidentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+
+	if err := h.statusService.UpdateIncident(id, req.Status, req.Message); err != nil {
+		if err == sql.ErrNoRows {
+			return h.errorResponse(c, 404, "Incident not found")
+		}
+		logrus.WithError(err).Error("Failed to update incident")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, fiber.Map{"id": id, "status": req.Status})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportConversationTranscript produces a formatted HTML transcript of a conversation
+// with media thumbnails inlined. Use ?download=1 to receive it as an attachment
+func (h *Handlers) ExportConversationTranscript(c *fiber.Ctx) error {
+	prospectNum := c.Params("prospect")
+	idDevice := c.Params("device")
+
+	if prospectNum == "" || idDevice == "" {
+		return h.errorResponse(c, 400, "Prospect number and device ID are required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	transcript, err := h.transcriptService.RenderHTMLTranscript(prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to render conversation transcript")
+		return h.errorResponse(c, 500, "Failed to export transcript")
+	}
+
+	if transcript == nil {
+		return h.errorResponse(c, 404, "Conversation not found")
+	}
+
+	// Viewer-role users get the prospect's number masked throughout the export
+	exportNum := prospectNum
+	if userID, ok := c.Locals("user_id").(string); ok && h.piiService.IsViewer(userID) {
+		exportNum = services.MaskPhone(prospectNum)
+		transcript = bytes.ReplaceAll(transcript, []byte(prospectNum), []byte(exportNum))
+	}
+
+	c.Set("Content-Type", "text/html; charset=utf-8")
+	if c.Query("download") == "1" {
+		c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"transcript_%s_%s.html\"", exportNum, idDevice))
+	}
+
+	return c.Send(transcript)
+}
+
+// ExportConversationData serves a conversation's reconstructed transcript as
+// JSON (default) or CSV for archiving and BI pipelines. The device is passed
+// as ?device_id=, with optional ?start= / ?end= (YYYY-MM-DD) date filtering
+// and ?format=csv for CSV output
+func (h *Handlers) ExportConversationData(c *fiber.Ctx) error {
+	prospectNum := c.Params("prospect")
+	idDevice := c.Query("device_id")
+	if prospectNum == "" || idDevice == "" {
+		return h.errorResponse(c, 400, "Prospect number and device_id query parameter are required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	var start, end *time.Time
+	if startParam := c.Query("start"); startParam != "" {
+		parsed, err := time.Parse("2006-01-02", startParam)
+		if err != nil {
+			return h.errorResponse(c, 400, "start must be YYYY-MM-DD")
+		}
+		start = &parsed
+	}
+	if endParam := c.Query("end"); endParam != "" {
+		parsed, err := time.Parse("2006-01-02", endParam)
+		if err != nil {
+			return h.errorResponse(c, 400, "end must be YYYY-MM-DD")
+		}
+		// Include the whole end day
+		endOfDay := parsed.AddDate(0, 0, 1).Add(-time.Second)
+		end = &endOfDay
+	}
+
+	entries, err := h.transcriptService.BuildStructuredTranscript(prospectNum, idDevice, start, end)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to build structured transcript")
+		return h.errorResponse(c, 500, "Failed to export conversation")
+	}
+	if entries == nil {
+		return h.errorResponse(c, 404, "Conversation not found")
+	}
+
+	// Viewer-role users get the prospect's number masked throughout the export
+	exportNum := prospectNum
+	if userID, ok := c.Locals("user_id").(string); ok && h.piiService.IsViewer(userID) {
+		exportNum = services.MaskPhone(prospectNum)
+	}
+
+	if c.Query("format") == "csv" {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write([]string{"prospect_num", "id_device", "role", "timestamp", "content", "media_type", "media_url"})
+		for _, entry := range entries {
+			_ = writer.Write([]string{exportNum, idDevice, entry.Role, entry.Timestamp, entry.Content, entry.MediaType, entry.MediaURL})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			logrus.WithError(err).Error("Failed to write transcript CSV")
+			return h.errorResponse(c, 500, "Failed to export conversation")
+		}
+
+		c.Set("Content-Type", "text/csv; charset=utf-8")
+		c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"conversation_%s_%s.csv\"", exportNum, idDevice))
+		return c.Send(buf.Bytes())
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"prospect_num": exportNum,
+		"id_device":    idDevice,
+		"messages":     entries,
+	})
+}
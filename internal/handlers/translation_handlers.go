@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetFlowTranslationStatus reports the flow's translation coverage: which
+// languages its message/image nodes carry and which languages each node is
+// still missing
+func (h *Handlers) GetFlowTranslationStatus(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	flow, err := h.flowService.GetFlow(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get flow for translation status")
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"error":   "Flow not found",
+		})
+	}
+
+	nodes, err := h.flowService.GetFlowNodes(flow)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to parse flow nodes for translation status")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to parse flow nodes",
+		})
+	}
+
+	report := h.languageService.TranslationReport(flowID, nodes)
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetFlowAutoTranslate returns whether the flow machine-translates content
+// for languages without a human translation
+func (h *Handlers) GetFlowAutoTranslate(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	enabled, err := h.languageService.AutoTranslateEnabled(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get auto-translate setting")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get auto-translate setting",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"flow_id": flowID,
+			"enabled": enabled,
+		},
+	})
+}
+
+// SetFlowAutoTranslate toggles the flow's machine translation fallback
+func (h *Handlers) SetFlowAutoTranslate(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   "Invalid request body",
+		})
+	}
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.languageService.SetAutoTranslate(flowID, req.Enabled); err != nil {
+		logrus.WithError(err).Error("Failed to set auto-translate setting")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to set auto-translate setting",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"flow_id": flowID,
+			"enabled": req.Enabled,
+		},
+	})
+}
+
+// GetFlowMachineTranslations returns the flow's cached machine translations
+// for review; adding one to the node's translations map overrides it
+func (h *Handlers) GetFlowMachineTranslations(c *fiber.Ctx) error {
+	flowID := c.Params("id")
+
+	if err := h.requireFlowAccess(c, flowID, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	translations, err := h.languageService.MachineTranslations(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get machine translations")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get machine translations",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    translations,
+	})
+}
+
+// GetMachineTranslationLog returns a conversation's machine-translated
+// deliveries so the conversation view can flag those messages
+func (h *Handlers) GetMachineTranslationLog(c *fiber.Ctx) error {
+	prospectNum := c.Query("prospect_num")
+	idDevice := c.Query("id_device")
+	if prospectNum == "" || idDevice == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"error":   "prospect_num and id_device query parameters are required",
+		})
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	log, err := h.languageService.MachineTranslationLog(prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get machine translation log")
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to get machine translation log",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    log,
+	})
+}
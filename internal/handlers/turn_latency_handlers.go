@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTurnLatencyReport returns per-device response latency percentiles. With
+// ?device_id it returns one device, otherwise every device with samples
+// (admins only, since that view spans every tenant)
+func (h *Handlers) GetTurnLatencyReport(c *fiber.Ctx) error {
+	if idDevice := c.Query("device_id"); idDevice != "" {
+		if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+			return err
+		}
+		return h.successResponse(c, h.turnLatencyService.Report(idDevice))
+	}
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+	return h.successResponse(c, h.turnLatencyService.ListReports())
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetUnknownWebhookPayloads returns recently captured webhook payloads that failed
+// normalization (PII-masked), optionally filtered by ?provider=. The capture
+// buffer spans every device, so the endpoint is admin-only
+func (h *Handlers) GetUnknownWebhookPayloads(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	if !h.piiService.HasAssignedAdminRole(userID) {
+		return h.errorResponse(c, 403, "Admin role required")
+	}
+
+	provider := c.Query("provider")
+	limit := c.QueryInt("limit", 50)
+
+	payloads, err := h.payloadCaptureService.GetUnknownPayloads(provider, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get unknown webhook payloads")
+		return h.errorResponse(c, 500, "Failed to retrieve unknown webhook payloads")
+	}
+
+	return h.successResponse(c, payloads)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetUsageDashboard returns the authenticated user's aggregated usage:
+// messages per day, AI tokens per day, active conversations, top flows and
+// provider error rates
+func (h *Handlers) GetUsageDashboard(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "User not authenticated")
+	}
+
+	dashboard, err := h.usageService.Dashboard(userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to build usage dashboard")
+		return h.errorResponse(c, 500, "Failed to build usage dashboard")
+	}
+
+	return h.successResponse(c, dashboard)
+}
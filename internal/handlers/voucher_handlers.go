@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// RedeemVoucherRequest is the body for validating and consuming a voucher code
+type RedeemVoucherRequest struct {
+	Code        string `json:"code"`
+	IDDevice    string `json:"id_device"`
+	ProspectNum string `json:"prospect_num"`
+	Source      string `json:"source"` // "flow" or "pos"
+}
+
+// GetVouchers returns a device's issued voucher codes
+func (h *Handlers) GetVouchers(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	vouchers, err := h.voucherService.GetVouchers(idDevice, c.Query("campaign"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get vouchers")
+		return h.errorResponse(c, 500, "Failed to get vouchers")
+	}
+
+	return h.successResponse(c, vouchers)
+}
+
+// RedeemVoucher validates and consumes a voucher code. POS systems call this
+// at checkout; flows call it when the prospect sends their code back
+func (h *Handlers) RedeemVoucher(c *fiber.Ctx) error {
+	var req RedeemVoucherRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.Code == "" || req.IDDevice == "" {
+		return h.errorResponse(c, 400, "code and id_device are required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	voucher, err := h.voucherService.RedeemVoucher(req.Code, req.IDDevice, req.ProspectNum, req.Source)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrVoucherNotFound):
+			return h.errorResponse(c, 404, err.Error())
+		case errors.Is(err, services.ErrVoucherExpired), errors.Is(err, services.ErrVoucherExhausted):
+			return h.errorResponse(c, 409, err.Error())
+		default:
+			logrus.WithError(err).Error("Failed to redeem voucher")
+			return h.errorResponse(c, 500, "Failed to redeem voucher")
+		}
+	}
+
+	return h.successResponse(c, voucher)
+}
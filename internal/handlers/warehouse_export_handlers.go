@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetWarehouseExportConfig returns the device's analytics warehouse connector
+func (h *Handlers) GetWarehouseExportConfig(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	config, err := h.warehouseExportService.GetConfig(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get warehouse export config")
+		return h.errorResponse(c, 500, "Failed to get warehouse export config")
+	}
+
+	return h.successResponse(c, config)
+}
+
+// SaveWarehouseExportConfig saves the device's analytics warehouse connector
+func (h *Handlers) SaveWarehouseExportConfig(c *fiber.Ctx) error {
+	var config models.WarehouseExportConfig
+	if err := c.BodyParser(&config); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if config.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	// The connector decides where conversation data gets shipped - admin-level access only
+	if err := h.requireDeviceAccess(c, config.IDDevice, models.OrgRoleAdmin); err != nil {
+		return err
+	}
+
+	if err := h.warehouseExportService.SaveConfig(&config); err != nil {
+		logrus.WithError(err).Error("Failed to save warehouse export config")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, config)
+}
+
+// RunWarehouseBackfillRequest identifies the device to backfill
+type RunWarehouseBackfillRequest struct {
+	IDDevice string `json:"id_device"`
+}
+
+// RunWarehouseBackfill replays the device's existing prospects into the
+// warehouse export so BI starts with full history
+func (h *Handlers) RunWarehouseBackfill(c *fiber.Ctx) error {
+	var req RunWarehouseBackfillRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	queued, err := h.warehouseExportService.Backfill(req.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to run warehouse backfill")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"id_device": req.IDDevice,
+		"queued":    queued,
+	})
+}
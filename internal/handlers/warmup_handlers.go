@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// GetWarmupProfile returns the device's warm-up schedule
+func (h *Handlers) GetWarmupProfile(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	profile, err := h.warmupService.GetProfile(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get warmup profile")
+		return h.errorResponse(c, 500, "Failed to get warmup profile")
+	}
+
+	return h.successResponse(c, profile)
+}
+
+// SaveWarmupProfile saves the device's warm-up schedule
+func (h *Handlers) SaveWarmupProfile(c *fiber.Ctx) error {
+	var profile models.WarmupProfile
+	if err := c.BodyParser(&profile); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if profile.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, profile.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.warmupService.SaveProfile(&profile); err != nil {
+		logrus.WithError(err).Error("Failed to save warmup profile")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, profile)
+}
+
+// GetWarmupUsage returns today's cap and how much of it is used
+func (h *Handlers) GetWarmupUsage(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	cap, used, err := h.warmupService.GetUsage(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get warmup usage")
+		return h.errorResponse(c, 500, "Failed to get warmup usage")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"id_device": idDevice,
+		"cap":       cap,
+		"used":      used,
+	})
+}
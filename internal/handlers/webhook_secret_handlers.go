@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// SetWebhookSecret stores the HMAC secret used to verify inbound webhooks
+// for a device. Once set, unsigned or badly signed payloads are rejected
+func (h *Handlers) SetWebhookSecret(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return h.errorResponse(c, 400, "Device setting ID is required")
+	}
+
+	var req struct {
+		Secret string `json:"secret"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.Secret == "" {
+		return h.errorResponse(c, 400, "secret is required")
+	}
+
+	setting, err := h.deviceSettingsService.GetByID(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get device setting for webhook secret")
+		if err.Error() == "device setting not found" {
+			return h.errorResponse(c, 404, "Device setting not found")
+		}
+		return h.errorResponse(c, 500, "Failed to retrieve device setting")
+	}
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	// The secret gates webhook authenticity - admin-level access only
+	if !h.canAccessDeviceSetting(setting, userID, models.OrgRoleAdmin) {
+		return h.errorResponse(c, 403, "You do not have access to this device")
+	}
+
+	if err := h.webhookSignatureService.SetSecret(setting.IDDevice.String, req.Secret); err != nil {
+		return h.errorResponse(c, 500, "Failed to store webhook secret")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"id_device":            setting.IDDevice.String,
+		"verification_enabled": true,
+	})
+}
+
+// DeleteWebhookSecret removes a device's webhook secret, returning it to
+// accepting unsigned payloads
+func (h *Handlers) DeleteWebhookSecret(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return h.errorResponse(c, 400, "Device setting ID is required")
+	}
+
+	setting, err := h.deviceSettingsService.GetByID(id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get device setting for webhook secret")
+		if err.Error() == "device setting not found" {
+			return h.errorResponse(c, 404, "Device setting not found")
+		}
+		return h.errorResponse(c, 500, "Failed to retrieve device setting")
+	}
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return h.errorResponse(c, 401, "Authentication required")
+	}
+	// The secret gates webhook authenticity - admin-level access only
+	if !h.canAccessDeviceSetting(setting, userID, models.OrgRoleAdmin) {
+		return h.errorResponse(c, 403, "You do not have access to this device")
+	}
+
+	if err := h.webhookSignatureService.ClearSecret(setting.IDDevice.String); err != nil {
+		return h.errorResponse(c, 500, "Failed to remove webhook secret")
+	}
+
+	return h.successResponse(c, fiber.Map{
+		"id_device":            setting.IDDevice.String,
+		"verification_enabled": false,
+	})
+}
@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetWebhookStats returns per-provider webhook processing metrics (volume,
+// parse failures by field, average extract→send latency, group-message skips)
+func (h *Handlers) GetWebhookStats(c *fiber.Ctx) error {
+	return h.successResponse(c, h.webhookStatsService.GetStats())
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateWebhookSubscriptionRequest is the payload for registering a lifecycle
+// webhook
+type CreateWebhookSubscriptionRequest struct {
+	IDDevice string `json:"id_device"`
+	URL      string `json:"url"`
+	Events   string `json:"events"`
+}
+
+// ToggleWebhookSubscriptionRequest enables or disables a subscription
+type ToggleWebhookSubscriptionRequest struct {
+	IDDevice string `json:"id_device"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// GetWebhookSubscriptions returns a device's lifecycle webhook subscriptions
+func (h *Handlers) GetWebhookSubscriptions(c *fiber.Ctx) error {
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleViewer); err != nil {
+		return err
+	}
+
+	subscriptions, err := h.lifecycleWebhookService.GetSubscriptions(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get webhook subscriptions")
+		return h.errorResponse(c, 500, "Failed to get webhook subscriptions")
+	}
+
+	return h.successResponse(c, subscriptions)
+}
+
+// CreateWebhookSubscription registers a webhook URL for lifecycle events and
+// returns it with the signing secret
+func (h *Handlers) CreateWebhookSubscription(c *fiber.Ctx) error {
+	var req CreateWebhookSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	subscription, err := h.lifecycleWebhookService.CreateSubscription(req.IDDevice, req.URL, req.Events)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create webhook subscription")
+		return h.errorResponse(c, 400, err.Error())
+	}
+
+	return h.successResponse(c, subscription)
+}
+
+// ToggleWebhookSubscription enables or disables a subscription
+func (h *Handlers) ToggleWebhookSubscription(c *fiber.Ctx) error {
+	var req ToggleWebhookSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return h.errorResponse(c, 400, "Invalid request body")
+	}
+
+	if req.IDDevice == "" {
+		return h.errorResponse(c, 400, "id_device is required")
+	}
+	if err := h.requireDeviceAccess(c, req.IDDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.lifecycleWebhookService.SetSubscriptionEnabled(c.Params("id"), req.IDDevice, req.Enabled); err != nil {
+		logrus.WithError(err).Error("Failed to toggle webhook subscription")
+		return h.errorResponse(c, 500, "Failed to toggle webhook subscription")
+	}
+
+	return h.successResponse(c, fiber.Map{"id": c.Params("id"), "enabled": req.Enabled})
+}
+
+// DeleteWebhookSubscription removes a subscription. The owning device is
+// passed as ?id_device= so the delete stays scoped to devices the caller can
+// access
+func (h *Handlers) DeleteWebhookSubscription(c *fiber.Ctx) error {
+	id := c.Params("id")
+	idDevice := c.Query("id_device")
+	if idDevice == "" {
+		return h.errorResponse(c, 400, "id_device query parameter is required")
+	}
+	if err := h.requireDeviceAccess(c, idDevice, models.OrgRoleAgent); err != nil {
+		return err
+	}
+
+	if err := h.lifecycleWebhookService.DeleteSubscription(id, idDevice); err != nil {
+		logrus.WithError(err).Error("Failed to delete webhook subscription")
+		return h.errorResponse(c, 500, "Failed to delete webhook subscription")
+	}
+
+	return h.successResponse(c, fiber.Map{"deleted": id})
+}
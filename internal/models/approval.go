@@ -0,0 +1,42 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Pending message statuses in the approval queue
+const (
+	PendingMessageStatusPending  = "pending"
+	PendingMessageStatusApproved = "approved"
+	PendingMessageStatusRejected = "rejected"
+)
+
+// PendingMessage is one AI-generated response held for human review before
+// it is sent. EditedContent holds the reviewer's rewrite when they changed
+// the message on approval
+type PendingMessage struct {
+	ID            int            `json:"id" db:"id"`
+	FlowID        string         `json:"flow_id" db:"flow_id"`
+	NodeID        string         `json:"node_id" db:"node_id"`
+	ProspectNum   string         `json:"prospect_num" db:"prospect_num"`
+	IDDevice      string         `json:"id_device" db:"id_device"`
+	MessageType   string         `json:"message_type" db:"message_type"`
+	Content       string         `json:"content" db:"content"`
+	EditedContent sql.NullString `json:"edited_content" db:"edited_content"`
+	Status        string         `json:"status" db:"status"`
+	ReviewedBy    sql.NullString `json:"reviewed_by" db:"reviewed_by"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	ReviewedAt    *time.Time     `json:"reviewed_at" db:"reviewed_at"`
+}
+
+// MessageEdit is one approved-with-changes message: the AI draft and the
+// reviewer's rewrite, kept per flow as training signal for prompt tuning
+type MessageEdit struct {
+	FlowID     string    `json:"flow_id"`
+	NodeID     string    `json:"node_id"`
+	Original   string    `json:"original"`
+	Edited     string    `json:"edited"`
+	ReviewedBy string    `json:"reviewed_by"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+}
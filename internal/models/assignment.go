@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// AssignmentStrategy represents how a handoff conversation is routed to an agent
+type AssignmentStrategy string
+
+const (
+	AssignmentStrategyRoundRobin  AssignmentStrategy = "round_robin"
+	AssignmentStrategyLeastActive AssignmentStrategy = "least_active"
+	AssignmentStrategyManual      AssignmentStrategy = "manual"
+)
+
+// AgentStatus represents an agent's online status and declared skills on a device
+type AgentStatus struct {
+	ID             int        `json:"id" db:"id"`
+	AgentID        string     `json:"agent_id" db:"agent_id"`
+	IDDevice       string     `json:"id_device" db:"id_device"`
+	IsOnline       bool       `json:"is_online" db:"is_online"`
+	Skills         []string   `json:"skills" db:"skills"` // Stored comma-separated in the database
+	LastAssignedAt *time.Time `json:"last_assigned_at" db:"last_assigned_at"`
+	LastSeen       time.Time  `json:"last_seen" db:"last_seen"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// HasSkill reports whether the agent declared a skill tag
+func (a *AgentStatus) HasSkill(skill string) bool {
+	for _, s := range a.Skills {
+		if s == skill {
+			return true
+		}
+	}
+	return false
+}
+
+// QueuedHandoff represents a handoff waiting for a matching agent to come online
+type QueuedHandoff struct {
+	ID          int       `json:"id" db:"id"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	Skill       *string   `json:"skill" db:"skill"`
+	Strategy    string    `json:"strategy" db:"strategy"`
+	QueuedAt    time.Time `json:"queued_at" db:"queued_at"`
+}
+
+// ConversationAssignment represents the current agent assignment for a conversation
+type ConversationAssignment struct {
+	ID          int       `json:"id" db:"id"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	AgentID     string    `json:"agent_id" db:"agent_id"`
+	Strategy    string    `json:"strategy" db:"strategy"`
+	Skill       *string   `json:"skill" db:"skill"`
+	AssignedAt  time.Time `json:"assigned_at" db:"assigned_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AssignmentHistoryEntry represents one entry in a conversation's assignment trail
+type AssignmentHistoryEntry struct {
+	ID              int       `json:"id" db:"id"`
+	ProspectNum     string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice        string    `json:"id_device" db:"id_device"`
+	AgentID         string    `json:"agent_id" db:"agent_id"`
+	PreviousAgentID *string   `json:"previous_agent_id" db:"previous_agent_id"`
+	Strategy        string    `json:"strategy" db:"strategy"`
+	Skill           *string   `json:"skill" db:"skill"`
+	AssignedAt      time.Time `json:"assigned_at" db:"assigned_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// AuditLogEntry is one message in the tamper-evident audit log. Each
+// entry's hash covers its own fields plus the previous entry's hash, so any
+// edit or deletion breaks the chain from that point on and is detectable by
+// re-verification
+type AuditLogEntry struct {
+	ID          int64     `json:"id"`
+	IDDevice    string    `json:"id_device"`
+	ProspectNum string    `json:"prospect_num"`
+	Sender      string    `json:"sender"` // "user" or "bot"
+	Content     string    `json:"content"`
+	PrevHash    string    `json:"prev_hash"`
+	Hash        string    `json:"hash"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AuditVerification reports the outcome of re-verifying an audit log chain
+type AuditVerification struct {
+	Valid         bool   `json:"valid"`
+	Records       int    `json:"records"`
+	FirstBrokenID int64  `json:"first_broken_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// BranchLocation is one physical outlet of the organisation. nearest_branch
+// flow nodes pick the closest one to a prospect's shared location
+type BranchLocation struct {
+	ID           string    `json:"id" db:"id"`
+	IDDevice     string    `json:"id_device" db:"id_device"`
+	Name         string    `json:"name" db:"name"`
+	Address      string    `json:"address" db:"address"`
+	Latitude     float64   `json:"latitude" db:"latitude"`
+	Longitude    float64   `json:"longitude" db:"longitude"`
+	Phone        string    `json:"phone" db:"phone"`
+	BranchDevice string    `json:"branch_device" db:"branch_device"`
+	IsActive     bool      `json:"is_active" db:"is_active"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
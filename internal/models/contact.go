@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Contact identity modes: whether flow state for the same number is shared
+// across the org's devices or isolated per device (the historical behavior)
+const (
+	ContactIdentityShared   = "shared"
+	ContactIdentityIsolated = "isolated"
+)
+
+// Contact is the org-level identity behind device-scoped prospects: one row
+// per (user, phone number) linking every device the number has messaged
+type Contact struct {
+	ID          int       `json:"id" db:"id"`
+	UserID      string    `json:"user_id" db:"user_id"`
+	PhoneNumber string    `json:"phone_number" db:"phone_number"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+
+	// Prospects are the device-scoped conversations linked to this contact
+	Prospects []ContactProspect `json:"prospects,omitempty"`
+}
+
+// ContactProspect summarizes one device-scoped conversation of a contact
+type ContactProspect struct {
+	IDDevice        string `json:"id_device" db:"id_device"`
+	ProspectNum     string `json:"prospect_num" db:"prospect_num"`
+	Stage           string `json:"stage" db:"stage"`
+	ExecutionStatus string `json:"execution_status" db:"execution_status"`
+	FlowID          string `json:"flow_id" db:"flow_id"`
+}
+
+// DuplicateProspect is a phone number seen on more than one of the org's
+// devices, each with its own isolated history
+type DuplicateProspect struct {
+	PhoneNumber string `json:"phone_number" db:"phone_number"`
+	DeviceCount int    `json:"device_count" db:"device_count"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ConversationEmbedding is one embedded chunk of a conversation, stored so
+// semantic search can rank conversations by meaning rather than keywords.
+// Conversations are chunked into windows of consecutive messages; each chunk
+// keeps its source text as the snippet shown in search results
+type ConversationEmbedding struct {
+	ID          int64     `json:"id"`
+	IDDevice    string    `json:"id_device"`
+	ProspectNum string    `json:"prospect_num"`
+	ChunkIndex  int       `json:"chunk_index"`
+	ContentHash string    `json:"content_hash"`
+	Snippet     string    `json:"snippet"`
+	Embedding   []float64 `json:"-"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SemanticSearchResult is one conversation chunk ranked by similarity to
+// the search query
+type SemanticSearchResult struct {
+	ProspectNum string    `json:"prospect_num"`
+	Snippet     string    `json:"snippet"`
+	Similarity  float64   `json:"similarity"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ConversationSearchRow is one conversation matched by a full-text search,
+// as read from the database. Score is the MATCH ... AGAINST relevance (0
+// when the LIKE fallback answered the query)
+type ConversationSearchRow struct {
+	ProspectNum  string
+	ProspectName sql.NullString
+	Stage        sql.NullString
+	ConvLast     sql.NullString
+	UpdatedAt    time.Time
+	Score        float64
+}
+
+// ConversationSearchSnippet is one matching message line with the query
+// terms wrapped in <mark> tags for the inbox to highlight
+type ConversationSearchSnippet struct {
+	Sender string `json:"sender"` // "user" or "bot"
+	Text   string `json:"text"`
+}
+
+// ConversationSearchResult is one conversation in a search response, with
+// up to a few highlighted snippets of the matching messages
+type ConversationSearchResult struct {
+	ProspectNum  string                      `json:"prospect_num"`
+	ProspectName string                      `json:"prospect_name,omitempty"`
+	Stage        string                      `json:"stage,omitempty"`
+	Score        float64                     `json:"score"`
+	MatchCount   int                         `json:"match_count"`
+	UpdatedAt    time.Time                   `json:"updated_at"`
+	Snippets     []ConversationSearchSnippet `json:"snippets"`
+}
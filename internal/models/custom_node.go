@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Custom node types let partners extend the flow editor with nodes
+// implemented as external webhooks: the engine POSTs the node config plus
+// execution state and the endpoint answers with effects and a next-node
+// decision. Registered type names carry this prefix so they can never shadow
+// a built-in node type
+const CustomNodeTypePrefix = "custom_"
+
+// Effect types a custom node endpoint may return
+const (
+	CustomNodeEffectMessage = "message"
+	CustomNodeEffectImage   = "image"
+	CustomNodeEffectAudio   = "audio"
+	CustomNodeEffectVideo   = "video"
+)
+
+// CustomNodeType is one registered webhook-backed node type
+type CustomNodeType struct {
+	ID             string    `json:"id" db:"id"`
+	IDDevice       string    `json:"id_device" db:"id_device"`
+	TypeName       string    `json:"type_name" db:"type_name"`
+	EndpointURL    string    `json:"endpoint_url" db:"endpoint_url"`
+	Secret         string    `json:"secret" db:"secret"`
+	TimeoutSeconds int       `json:"timeout_seconds" db:"timeout_seconds"`
+	Enabled        bool      `json:"enabled" db:"enabled"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// CustomNodeRequest is what the engine POSTs to the endpoint
+type CustomNodeRequest struct {
+	NodeID      string                 `json:"node_id"`
+	NodeType    string                 `json:"node_type"`
+	Config      map[string]interface{} `json:"config"`
+	IDDevice    string                 `json:"id_device"`
+	ProspectNum string                 `json:"prospect_num"`
+	Stage       string                 `json:"stage"`
+	UserInput   string                 `json:"user_input"`
+	Variables   map[string]interface{} `json:"variables"`
+}
+
+// CustomNodeEffect is one side effect the endpoint asks the engine to perform
+type CustomNodeEffect struct {
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	MediaURL string `json:"media_url"`
+}
+
+// CustomNodeResult is the endpoint's answer: effects to deliver, variables to
+// merge and which node to continue with (empty follows the default edge)
+type CustomNodeResult struct {
+	Effects    []CustomNodeEffect     `json:"effects"`
+	Variables  map[string]interface{} `json:"variables"`
+	NextNodeID string                 `json:"next_node_id"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Digest report periods
+const (
+	DigestPeriodDaily  = "daily"
+	DigestPeriodWeekly = "weekly"
+)
+
+// DigestReport is one stored per-device digest covering a reporting period.
+// Report holds the serialized DigestMetrics so the metric set can grow
+// without schema changes
+type DigestReport struct {
+	ID          string          `json:"id" db:"id"`
+	IDDevice    string          `json:"id_device" db:"id_device"`
+	Period      string          `json:"period" db:"period"`
+	PeriodStart time.Time       `json:"period_start" db:"period_start"`
+	PeriodEnd   time.Time       `json:"period_end" db:"period_end"`
+	Report      json.RawMessage `json:"report" db:"report"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
+
+// DigestMetrics is the metric set computed for one digest period
+type DigestMetrics struct {
+	MessagesIn   int64            `json:"messages_in"`
+	MessagesOut  int64            `json:"messages_out"`
+	NewProspects int64            `json:"new_prospects"`
+	StageCounts  map[string]int64 `json:"stage_counts"`
+	AIRequests   int64            `json:"ai_requests"`
+	AITokens     int64            `json:"ai_tokens"`
+	AICostUSD    float64          `json:"ai_cost_usd"`
+	TopIntents   []DigestIntent   `json:"top_intents"`
+}
+
+// DigestIntent is one intent label with its classification count
+type DigestIntent struct {
+	Intent string `json:"intent"`
+	Count  int64  `json:"count"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// DocumentTemplateLine is one text line of a document template. Text may
+// contain {{variable}} placeholders; a line whose text is exactly
+// {{line_items}} expands a slice variable into one rendered line per item
+type DocumentTemplateLine struct {
+	Text string `json:"text"`
+	Size int    `json:"size,omitempty"`
+	Bold bool   `json:"bold,omitempty"`
+}
+
+// DocumentTemplate is a reusable PDF layout (invoice, quote) owned by a
+// user. Document nodes fill it with flow variables and send the result as a
+// document message
+type DocumentTemplate struct {
+	ID        int64                  `json:"id" db:"id"`
+	UserID    string                 `json:"user_id" db:"user_id"`
+	Name      string                 `json:"name" db:"name"`
+	Title     string                 `json:"title" db:"title"`
+	Lines     []DocumentTemplateLine `json:"lines"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// FailedMessage is a webhook message that exhausted its retries and was
+// dead-lettered for operator review. Requeueing a row runs it through the
+// normal webhook pipeline again and removes it from the table
+type FailedMessage struct {
+	ID             int64     `json:"id" db:"id"`
+	IdempotencyKey string    `json:"idempotency_key" db:"idempotency_key"`
+	DeviceID       string    `json:"device_id" db:"device_id"`
+	ProspectNum    string    `json:"prospect_num" db:"prospect_num"`
+	Content        string    `json:"content" db:"content"`
+	Provider       string    `json:"provider" db:"provider"`
+	SenderName     string    `json:"sender_name" db:"sender_name"`
+	LastError      string    `json:"last_error" db:"last_error"`
+	Retries        int       `json:"retries" db:"retries"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// OrderStatus is the lifecycle state of an order captured from a flow
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusConfirmed OrderStatus = "confirmed"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// CartItem is one product in a conversation's shopping cart, joined with the
+// catalog fields needed to render and price it
+type CartItem struct {
+	ProductID string  `json:"product_id" db:"product_id"`
+	Name      string  `json:"name" db:"name"`
+	SKU       string  `json:"sku" db:"sku"`
+	Price     float64 `json:"price" db:"price"`
+	Quantity  int     `json:"quantity" db:"quantity"`
+}
+
+// OrderLine is one line of a persisted order - a snapshot of the cart item at
+// capture time, so later catalog edits do not rewrite past orders
+type OrderLine struct {
+	ProductID string  `json:"product_id"`
+	Name      string  `json:"name"`
+	SKU       string  `json:"sku"`
+	Price     float64 `json:"price"`
+	Quantity  int     `json:"quantity"`
+}
+
+// FlowOrder is an order captured by an order summary node. Integrations poll
+// orders by status and push them to e-commerce backends
+type FlowOrder struct {
+	ID          string      `json:"id" db:"id"`
+	ProspectNum string      `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string      `json:"id_device" db:"id_device"`
+	Status      OrderStatus `json:"status" db:"status"`
+	Total       float64     `json:"total" db:"total"`
+	Items       []OrderLine `json:"items"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+}
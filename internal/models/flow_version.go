@@ -0,0 +1,19 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FlowVersion is one published snapshot of a chatbot flow. The live
+// chatbot_flows row acts as the editable draft; publishing freezes its nodes
+// and edges as the next version. Active executions pin the version that was
+// current when they started, so edits never break mid-flight conversations
+type FlowVersion struct {
+	ID        int64            `json:"id" db:"id"`
+	FlowID    string           `json:"flow_id" db:"flow_id"`
+	Version   int              `json:"version" db:"version"`
+	Nodes     *json.RawMessage `json:"nodes,omitempty" db:"nodes"`
+	Edges     *json.RawMessage `json:"edges,omitempty" db:"edges"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
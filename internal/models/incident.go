@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Incident statuses as posted by admins on the public status page
+const (
+	IncidentStatusOpen       = "open"
+	IncidentStatusMonitoring = "monitoring"
+	IncidentStatusResolved   = "resolved"
+)
+
+// Incident severities
+const (
+	IncidentSeverityMinor    = "minor"
+	IncidentSeverityMajor    = "major"
+	IncidentSeverityCritical = "critical"
+)
+
+// Incident is an admin-posted notice shown on the public status page
+type Incident struct {
+	ID        int       `json:"id" db:"id"`
+	Title     string    `json:"title" db:"title"`
+	Severity  string    `json:"severity" db:"severity"`
+	Status    string    `json:"status" db:"status"`
+	Message   string    `json:"message" db:"message"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProviderStatus summarizes recent delivery connectivity for one provider
+type ProviderStatus struct {
+	Provider    string  `json:"provider"`
+	Status      string  `json:"status"`
+	SuccessRate float64 `json:"success_rate"`
+	Samples     int     `json:"samples"`
+}
+
+// PublicStatus is the cached, unauthenticated status page payload
+type PublicStatus struct {
+	Status        string           `json:"status"`
+	UptimeSeconds int64            `json:"uptime_seconds"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+	Providers     []ProviderStatus `json:"providers"`
+	Incidents     []Incident       `json:"incidents"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// LeadScoringConfig is a device's scoring rule set. Each signal adds its
+// configured points to the prospect's score; crossing NotifyThreshold fires
+// the lead.qualified notification and optionally starts the drip flow
+type LeadScoringConfig struct {
+	IDDevice string `json:"id_device"`
+	Enabled  bool   `json:"enabled"`
+	// StagePoints awards points when the prospect reaches a stage
+	StagePoints map[string]int `json:"stage_points,omitempty"`
+	// KeywordPoints awards points when an inbound message contains a keyword
+	KeywordPoints map[string]int `json:"keyword_points,omitempty"`
+	// IntentPoints awards points when the classifier matches an intent
+	IntentPoints map[string]int `json:"intent_points,omitempty"`
+	// FastReplyPoints is awarded when the prospect replies within
+	// FastReplySeconds of the last outbound message
+	FastReplySeconds int `json:"fast_reply_seconds,omitempty"`
+	FastReplyPoints  int `json:"fast_reply_points,omitempty"`
+	// NotifyThreshold fires the qualification trigger when crossed upward
+	NotifyThreshold int `json:"notify_threshold,omitempty"`
+	// DripFlowID is started for the prospect when the threshold is crossed
+	DripFlowID string `json:"drip_flow_id,omitempty"`
+}
+
+// LeadScore is a prospect's current score
+type LeadScore struct {
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	Score       int       `json:"score" db:"score"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
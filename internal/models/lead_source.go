@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Lead source types for external intake
+const (
+	LeadSourceGeneric = "generic"
+	LeadSourceMeta    = "meta"
+)
+
+// LeadSource is one configured external lead intake (a web form or a Meta
+// Lead Ads page). Leads posted to its intake URL become WhatsApp prospects
+// on the mapped device, optionally greeted with TemplateMessage before the
+// mapped flow starts
+type LeadSource struct {
+	ID              string    `json:"id" db:"id"`
+	IDDevice        string    `json:"id_device" db:"id_device"`
+	Name            string    `json:"name" db:"name"`
+	SourceType      string    `json:"source_type" db:"source_type"`
+	FlowID          string    `json:"flow_id" db:"flow_id"`
+	TemplateMessage string    `json:"template_message" db:"template_message"`
+	VerifyToken     string    `json:"verify_token" db:"verify_token"`
+	LeadCount       int       `json:"lead_count" db:"lead_count"`
+	IntakeURL       string    `json:"intake_url,omitempty"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
@@ -32,6 +32,21 @@ const (
 	NodeTypeStage             NodeType = "stage"
 	NodeTypeUserReply         NodeType = "user_reply"
 	NodeTypeWaitingReplyTimes NodeType = "waiting_reply_times"
+	NodeTypeWaitEvent         NodeType = "wait_event"
+	NodeTypeParallel          NodeType = "parallel"
+	NodeTypeJoin              NodeType = "join"
+	NodeTypeLoop              NodeType = "loop"
+	NodeTypeCatalog           NodeType = "catalog"
+	NodeTypeOrderSummary      NodeType = "order_summary"
+	NodeTypeVoucher           NodeType = "voucher"
+	NodeTypeNearestBranch     NodeType = "nearest_branch"
+	NodeTypeIntentRouter      NodeType = "intent_router"
+	NodeTypeEntityExtract     NodeType = "entity_extract"
+	NodeTypeForm              NodeType = "form"
+	NodeTypeScript            NodeType = "script"
+	NodeTypeImageGen          NodeType = "image_gen"
+	NodeTypeDocument          NodeType = "document"
+	NodeTypeReceiptVerify     NodeType = "receipt_verify"
 )
 
 // ExecutionStatus represents the status of a flow execution
@@ -116,13 +131,18 @@ type FlowNode struct {
 	Position Position               `json:"position"`
 }
 
-// FlowEdge represents a connection between nodes
+// FlowEdge represents a connection between nodes. Condition holds an optional
+// expression (e.g. `input contains "price"`) that must pass for the edge to be
+// taken; Weight enables weighted-random selection among a node's outgoing
+// edges for A/B testing message copy
 type FlowEdge struct {
-	ID           string `json:"id"`
-	Source       string `json:"source"`
-	Target       string `json:"target"`
-	SourceHandle string `json:"sourceHandle,omitempty"`
-	TargetHandle string `json:"targetHandle,omitempty"`
+	ID           string  `json:"id"`
+	Source       string  `json:"source"`
+	Target       string  `json:"target"`
+	SourceHandle string  `json:"sourceHandle,omitempty"`
+	TargetHandle string  `json:"targetHandle,omitempty"`
+	Condition    string  `json:"condition,omitempty"`
+	Weight       float64 `json:"weight,omitempty"`
 }
 
 // Position represents the position of a node in the flow builder
@@ -145,9 +165,10 @@ type OpenRouterRequest struct {
 	Model             string                 `json:"model"`
 	Messages          []OpenRouterMessage    `json:"messages"`
 	Stream            bool                   `json:"stream"`
-	Temperature       float64                `json:"temperature"`        // Recommended setting: 0.67
-	TopP              float64                `json:"top_p"`              // Keep responses within natural probability range: 1
-	RepetitionPenalty float64                `json:"repetition_penalty"` // Avoid repetitive responses: 1
+	Temperature       float64                `json:"temperature"`          // Recommended setting: 0.67
+	TopP              float64                `json:"top_p"`                // Keep responses within natural probability range: 1
+	MaxTokens         int                    `json:"max_tokens,omitempty"` // 0 leaves the response uncapped
+	RepetitionPenalty float64                `json:"repetition_penalty"`   // Avoid repetitive responses: 1
 	Other             map[string]interface{} `json:"-"`
 }
 
@@ -233,6 +254,40 @@ type AIWhatsapp struct {
 	UpdateToday     *time.Time     `json:"update_today" db:"update_today"`
 }
 
+// Message is one structured row in the messages table, replacing conv_last
+// string concatenation for querying and pagination
+type Message struct {
+	ID          int64          `json:"id" db:"id"`
+	ProspectNum string         `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string         `json:"id_device" db:"id_device"`
+	Direction   string         `json:"direction" db:"direction"` // 'inbound' or 'outbound'
+	MessageType string         `json:"message_type" db:"message_type"`
+	Content     string         `json:"content" db:"content"`
+	Stage       sql.NullString `json:"stage" db:"stage"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// Conversation event types recorded in the append-only conversation_event table
+const (
+	ConversationEventMessageReceived = "message_received"
+	ConversationEventMessageSent     = "message_sent"
+	ConversationEventNodeTransition  = "node_transition"
+	ConversationEventStageChange     = "stage_change"
+)
+
+// ConversationEvent is one immutable entry in a conversation's event log,
+// ordered by a per-conversation sequence number. Derived tables (messages,
+// stage history) can be rebuilt by replaying these events
+type ConversationEvent struct {
+	ID          int64     `json:"id" db:"id"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	Seq         int       `json:"seq" db:"seq"`
+	EventType   string    `json:"event_type" db:"event_type"`
+	Detail      string    `json:"detail" db:"detail"` // JSON payload describing the change
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
 // ConversationLog represents a log entry for AI conversations
 type ConversationLog struct {
 	ID          int            `json:"id" db:"id"`
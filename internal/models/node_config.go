@@ -0,0 +1,579 @@
+package models
+
+// Typed node config structs replace ad-hoc node.Data map lookups. Historical
+// flows stored the same setting under several keys (imageUrl vs image vs
+// mediaUrl), so each accessor normalizes the known aliases in priority order.
+// The flow save path rewrites node data to the canonical (first-listed) keys
+
+// StartNodeConfig configures a start node
+type StartNodeConfig struct {
+	Keyword string `json:"keyword,omitempty"`
+	// Context window settings bound how much conversation history the
+	// flow's AI nodes see; zero values leave the window unlimited
+	ContextMaxTurns       int  `json:"contextMaxTurns,omitempty"`
+	ContextMaxTokens      int  `json:"contextMaxTokens,omitempty"`
+	ContextExcludeMedia   bool `json:"contextExcludeMedia,omitempty"`
+	ContextIncludeSummary bool `json:"contextIncludeSummary,omitempty"`
+}
+
+// MessageNodeConfig configures a message node. QuickReplies are optional
+// suggested answers rendered as provider buttons where supported and as a
+// numbered list elsewhere. Translations holds alternative message texts keyed
+// by language code ("ms", "en", ...); the prospect's detected or stored
+// language picks one at runtime, falling back to Message
+type MessageNodeConfig struct {
+	Message      string            `json:"message"`
+	QuickReplies []string          `json:"quickReplies,omitempty"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// ImageNodeConfig configures an image node. Translations holds per-language
+// image URLs (localized creatives) keyed the same way as message translations
+type ImageNodeConfig struct {
+	ImageURL     string            `json:"imageUrl"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// AudioNodeConfig configures an audio node
+type AudioNodeConfig struct {
+	AudioURL string `json:"audioUrl"`
+}
+
+// VideoNodeConfig configures a video node
+type VideoNodeConfig struct {
+	VideoURL string `json:"videoUrl"`
+}
+
+// What a pending delay does when the user replies before it fires
+const (
+	// DelayOnReplyCancel drops the scheduled continuation (default)
+	DelayOnReplyCancel = "cancel"
+	// DelayOnReplySend lets the continuation fire as scheduled
+	DelayOnReplySend = "send"
+	// DelayOnReplyQueue postpones the continuation until after the reply is
+	// handled
+	DelayOnReplyQueue = "queue"
+)
+
+// DelayNodeConfig configures a delay node. OnReply picks what happens to the
+// scheduled continuation when the user replies mid-delay
+type DelayNodeConfig struct {
+	Seconds float64 `json:"delay"`
+	OnReply string  `json:"onReply,omitempty"`
+}
+
+// NodeCondition is one branch rule on a condition node. Synonyms are
+// alternative phrasings matched like additional values
+type NodeCondition struct {
+	Type     string   `json:"type"`
+	Value    string   `json:"value"`
+	Label    string   `json:"label,omitempty"`
+	Synonyms []string `json:"synonyms,omitempty"`
+}
+
+// ConditionNodeConfig configures a condition node
+type ConditionNodeConfig struct {
+	Conditions []NodeCondition `json:"conditions"`
+}
+
+// StageNodeConfig configures a stage node
+type StageNodeConfig struct {
+	Stage string `json:"stage"`
+}
+
+// ManualNodeConfig configures a manual handoff node
+type ManualNodeConfig struct {
+	Skill string `json:"skill,omitempty"`
+}
+
+// WaitEventNodeConfig configures a wait_event node. The execution suspends
+// until an external signal with the matching event name arrives, or the
+// timeout elapses and the flow continues down the next edge
+type WaitEventNodeConfig struct {
+	Event          string  `json:"event"`
+	TimeoutSeconds float64 `json:"timeoutSeconds,omitempty"`
+}
+
+// LoopNodeConfig configures a loop node. The body path (edge with source handle
+// "body") repeats Iterations times, or while Condition holds when set; the flow
+// then continues down the "done" edge. The validator caps Iterations
+type LoopNodeConfig struct {
+	Iterations int    `json:"iterations"`
+	Condition  string `json:"condition,omitempty"`
+}
+
+// CatalogNodeConfig configures a catalog node. The node sends the device's
+// active products as a numbered, paginated list; the prospect's pick is stored
+// under Variable for later nodes and edge conditions
+type CatalogNodeConfig struct {
+	Message  string `json:"message,omitempty"`
+	PageSize int    `json:"pageSize,omitempty"`
+	Variable string `json:"variable,omitempty"`
+}
+
+// OrderSummaryNodeConfig configures an order summary node. The node renders
+// the conversation's cart, captures it as a pending order and clears the cart
+type OrderSummaryNodeConfig struct {
+	Message string `json:"message,omitempty"`
+}
+
+// VoucherNodeConfig configures a voucher node. The node issues a unique code
+// to the prospect; Message may contain a {{code}} placeholder, otherwise the
+// code is appended to a default message
+type VoucherNodeConfig struct {
+	Campaign       string  `json:"campaign,omitempty"`
+	Discount       float64 `json:"discount"`
+	ExpiryDays     int     `json:"expiryDays,omitempty"`
+	MaxRedemptions int     `json:"maxRedemptions,omitempty"`
+	Message        string  `json:"message,omitempty"`
+}
+
+// NearestBranchNodeConfig configures a nearest_branch node. Message is the
+// prompt asking the prospect to share their location
+type NearestBranchNodeConfig struct {
+	Message string `json:"message,omitempty"`
+}
+
+// IntentRouterNodeConfig configures an intent_router node. The user message
+// is classified against the labeled Intents by the AI provider and the flow
+// branches down the edge whose source handle matches the winning intent;
+// verdicts below MinConfidence take the fallback edge instead
+type IntentRouterNodeConfig struct {
+	Intents       []string `json:"intents"`
+	MinConfidence float64  `json:"minConfidence,omitempty"`
+}
+
+// Entity field types an entity_extract node may capture
+const (
+	EntityTypeText   = "text"
+	EntityTypeNumber = "number"
+	EntityTypeDate   = "date"
+)
+
+// EntityField is one entity an entity_extract node captures. Type defaults
+// to text; Description helps the extractor disambiguate similar fields
+type EntityField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// EntityExtractNodeConfig configures an entity_extract node. The user message
+// is run through the AI provider and each captured entity is validated
+// against its type and written into a flow variable of the same name
+type EntityExtractNodeConfig struct {
+	Entities []EntityField `json:"entities"`
+}
+
+// FormSlot is one value a form node collects. Prompt is the question asked
+// when the slot is still missing; Type reuses the entity field types
+type FormSlot struct {
+	Name   string `json:"name"`
+	Prompt string `json:"prompt,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+// FormNodeConfig configures a form node. The node keeps asking for missing
+// slots across turns (accepting several slots in one reply, in any order),
+// then confirms the collected data before the flow advances
+type FormNodeConfig struct {
+	Slots          []FormSlot `json:"slots"`
+	ConfirmMessage string     `json:"confirmMessage,omitempty"`
+}
+
+// ScriptNodeConfig configures a script node. The program runs in the
+// sandboxed script engine with access to the execution variables
+type ScriptNodeConfig struct {
+	Script string `json:"script"`
+}
+
+// ImageGenOverlay is one piece of text drawn onto the base image of an
+// image_gen node. X/Y position the top-left corner of the text, Size is the
+// rendered text height in pixels and Color is a hex value like #1a1a1a
+type ImageGenOverlay struct {
+	Text  string `json:"text"`
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Size  int    `json:"size,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// ReceiptVerifyNodeConfig configures a receipt_verify node. The node asks
+// the prospect for a receipt photo, OCRs it through the vision model and
+// compares the read amount (and optionally the payment reference) against
+// the expected values; both expectations may carry {{variable}} placeholders.
+// The flow branches down the match, mismatch or review edge
+type ReceiptVerifyNodeConfig struct {
+	Message           string  `json:"message,omitempty"`
+	ExpectedAmount    string  `json:"expectedAmount,omitempty"`
+	ExpectedReference string  `json:"expectedReference,omitempty"`
+	Tolerance         float64 `json:"tolerance,omitempty"`
+	MinConfidence     float64 `json:"minConfidence,omitempty"`
+}
+
+// DocumentNodeConfig configures a document node. The node fills the PDF
+// template with the execution variables (quote amount, customer name,
+// line items) and sends the rendered file as a document message with an
+// optional Caption
+type DocumentNodeConfig struct {
+	TemplateID int64  `json:"templateId"`
+	Caption    string `json:"caption,omitempty"`
+}
+
+// ImageGenNodeConfig configures an image_gen node. When Prompt is set the
+// image comes from the AI image provider; otherwise Overlays are rendered
+// onto BaseImageURL. Text fields may contain {{variable}} placeholders, so
+// the same node produces a personalized certificate, ticket or offer per
+// prospect. Caption is sent alongside the generated image
+type ImageGenNodeConfig struct {
+	BaseImageURL string            `json:"baseImageUrl,omitempty"`
+	Prompt       string            `json:"prompt,omitempty"`
+	Caption      string            `json:"caption,omitempty"`
+	Overlays     []ImageGenOverlay `json:"overlays,omitempty"`
+}
+
+// AIPromptNodeConfig configures an AI prompt node
+type AIPromptNodeConfig struct {
+	SystemPrompt string  `json:"systemPrompt"`
+	APIProvider  string  `json:"apiProvider,omitempty"`
+	Instance     string  `json:"instance,omitempty"`
+	Vision       bool    `json:"vision,omitempty"`
+	Model        string  `json:"model,omitempty"`       // Overrides the device-level model
+	Temperature  float64 `json:"temperature,omitempty"` // 0 falls back to the default (0.67)
+	TopP         float64 `json:"topP,omitempty"`        // 0 falls back to the default (1.0)
+	MaxTokens    int     `json:"maxTokens,omitempty"`   // 0 leaves the response uncapped
+}
+
+// dataString returns the first non-empty string among the given keys
+func (n *FlowNode) dataString(keys ...string) string {
+	for _, key := range keys {
+		if value, ok := n.Data[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// dataStringSlice returns the first non-empty string list among the given keys
+func (n *FlowNode) dataStringSlice(keys ...string) []string {
+	for _, key := range keys {
+		items, ok := n.Data[key].([]interface{})
+		if !ok {
+			continue
+		}
+		var values []string
+		for _, item := range items {
+			if value, ok := item.(string); ok && value != "" {
+				values = append(values, value)
+			}
+		}
+		if len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+// dataStringMap returns the first non-empty string map among the given keys
+func (n *FlowNode) dataStringMap(keys ...string) map[string]string {
+	for _, key := range keys {
+		raw, ok := n.Data[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		values := make(map[string]string)
+		for mapKey, item := range raw {
+			if value, ok := item.(string); ok && value != "" {
+				values[mapKey] = value
+			}
+		}
+		if len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+// dataBool returns the first boolean value among the given keys
+func (n *FlowNode) dataBool(keys ...string) bool {
+	for _, key := range keys {
+		if value, ok := n.Data[key].(bool); ok {
+			return value
+		}
+	}
+	return false
+}
+
+// dataFloat returns the first numeric value among the given keys
+func (n *FlowNode) dataFloat(keys ...string) float64 {
+	for _, key := range keys {
+		switch value := n.Data[key].(type) {
+		case float64:
+			return value
+		case int:
+			return float64(value)
+		}
+	}
+	return 0
+}
+
+// StartConfig returns the typed config for a start node
+func (n *FlowNode) StartConfig() StartNodeConfig {
+	return StartNodeConfig{
+		Keyword:               n.dataString("keyword"),
+		ContextMaxTurns:       int(n.dataFloat("contextMaxTurns", "context_max_turns")),
+		ContextMaxTokens:      int(n.dataFloat("contextMaxTokens", "context_max_tokens")),
+		ContextExcludeMedia:   n.dataBool("contextExcludeMedia", "context_exclude_media"),
+		ContextIncludeSummary: n.dataBool("contextIncludeSummary", "context_include_summary"),
+	}
+}
+
+// MessageConfig returns the typed config for a message node
+func (n *FlowNode) MessageConfig() MessageNodeConfig {
+	return MessageNodeConfig{
+		Message:      n.dataString("message", "text"),
+		QuickReplies: n.dataStringSlice("quickReplies", "buttons", "suggestions"),
+		Translations: n.dataStringMap("translations"),
+	}
+}
+
+// ImageConfig returns the typed config for an image node
+func (n *FlowNode) ImageConfig() ImageNodeConfig {
+	return ImageNodeConfig{
+		ImageURL:     n.dataString("imageUrl", "image", "mediaUrl"),
+		Translations: n.dataStringMap("translations"),
+	}
+}
+
+// AudioConfig returns the typed config for an audio node
+func (n *FlowNode) AudioConfig() AudioNodeConfig {
+	return AudioNodeConfig{AudioURL: n.dataString("audioUrl", "audio", "mediaUrl")}
+}
+
+// VideoConfig returns the typed config for a video node
+func (n *FlowNode) VideoConfig() VideoNodeConfig {
+	return VideoNodeConfig{VideoURL: n.dataString("videoUrl", "video", "mediaUrl")}
+}
+
+// DelayConfig returns the typed config for a delay node
+func (n *FlowNode) DelayConfig() DelayNodeConfig {
+	return DelayNodeConfig{
+		Seconds: n.dataFloat("delay", "delaySeconds"),
+		OnReply: n.dataString("onReply", "replyPolicy"),
+	}
+}
+
+// ConditionConfig returns the typed config for a condition node
+func (n *FlowNode) ConditionConfig() ConditionNodeConfig {
+	config := ConditionNodeConfig{}
+	conditions, ok := n.Data["conditions"].([]interface{})
+	if !ok {
+		return config
+	}
+	for _, conditionInterface := range conditions {
+		condition, ok := conditionInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _ := condition["type"].(string)
+		conditionValue, _ := condition["value"].(string)
+		conditionLabel, _ := condition["label"].(string)
+		var synonyms []string
+		if items, ok := condition["synonyms"].([]interface{}); ok {
+			for _, item := range items {
+				if synonym, ok := item.(string); ok && synonym != "" {
+					synonyms = append(synonyms, synonym)
+				}
+			}
+		}
+		config.Conditions = append(config.Conditions, NodeCondition{
+			Type:     conditionType,
+			Value:    conditionValue,
+			Label:    conditionLabel,
+			Synonyms: synonyms,
+		})
+	}
+	return config
+}
+
+// StageConfig returns the typed config for a stage node
+func (n *FlowNode) StageConfig() StageNodeConfig {
+	return StageNodeConfig{Stage: n.dataString("stage")}
+}
+
+// ManualConfig returns the typed config for a manual handoff node
+func (n *FlowNode) ManualConfig() ManualNodeConfig {
+	return ManualNodeConfig{Skill: n.dataString("skill", "handoff_skill")}
+}
+
+// WaitEventConfig returns the typed config for a wait_event node
+func (n *FlowNode) WaitEventConfig() WaitEventNodeConfig {
+	return WaitEventNodeConfig{
+		Event:          n.dataString("event", "event_name"),
+		TimeoutSeconds: n.dataFloat("timeoutSeconds", "timeout"),
+	}
+}
+
+// LoopConfig returns the typed config for a loop node
+func (n *FlowNode) LoopConfig() LoopNodeConfig {
+	return LoopNodeConfig{
+		Iterations: int(n.dataFloat("iterations", "count", "times")),
+		Condition:  n.dataString("condition", "while"),
+	}
+}
+
+// CatalogConfig returns the typed config for a catalog node
+func (n *FlowNode) CatalogConfig() CatalogNodeConfig {
+	return CatalogNodeConfig{
+		Message:  n.dataString("message", "text"),
+		PageSize: int(n.dataFloat("pageSize", "page_size")),
+		Variable: n.dataString("variable", "saveAs"),
+	}
+}
+
+// OrderSummaryConfig returns the typed config for an order summary node
+func (n *FlowNode) OrderSummaryConfig() OrderSummaryNodeConfig {
+	return OrderSummaryNodeConfig{Message: n.dataString("message", "text")}
+}
+
+// VoucherConfig returns the typed config for a voucher node
+func (n *FlowNode) VoucherConfig() VoucherNodeConfig {
+	return VoucherNodeConfig{
+		Campaign:       n.dataString("campaign"),
+		Discount:       n.dataFloat("discount"),
+		ExpiryDays:     int(n.dataFloat("expiryDays", "expiry_days")),
+		MaxRedemptions: int(n.dataFloat("maxRedemptions", "max_redemptions")),
+		Message:        n.dataString("message", "text"),
+	}
+}
+
+// NearestBranchConfig returns the typed config for a nearest_branch node
+func (n *FlowNode) NearestBranchConfig() NearestBranchNodeConfig {
+	return NearestBranchNodeConfig{Message: n.dataString("message", "text")}
+}
+
+// IntentRouterConfig returns the typed config for an intent_router node
+func (n *FlowNode) IntentRouterConfig() IntentRouterNodeConfig {
+	return IntentRouterNodeConfig{
+		Intents:       n.dataStringSlice("intents"),
+		MinConfidence: n.dataFloat("minConfidence", "min_confidence"),
+	}
+}
+
+// EntityExtractConfig returns the typed config for an entity_extract node
+func (n *FlowNode) EntityExtractConfig() EntityExtractNodeConfig {
+	config := EntityExtractNodeConfig{}
+	entities, ok := n.Data["entities"].([]interface{})
+	if !ok {
+		return config
+	}
+	for _, entityInterface := range entities {
+		entity, ok := entityInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := entity["name"].(string)
+		entityType, _ := entity["type"].(string)
+		description, _ := entity["description"].(string)
+		config.Entities = append(config.Entities, EntityField{
+			Name:        name,
+			Type:        entityType,
+			Description: description,
+		})
+	}
+	return config
+}
+
+// ScriptConfig returns the typed config for a script node
+func (n *FlowNode) ScriptConfig() ScriptNodeConfig {
+	return ScriptNodeConfig{Script: n.dataString("script", "code")}
+}
+
+// FormConfig returns the typed config for a form node
+func (n *FlowNode) FormConfig() FormNodeConfig {
+	config := FormNodeConfig{ConfirmMessage: n.dataString("confirmMessage", "confirm_message")}
+	slots, ok := n.Data["slots"].([]interface{})
+	if !ok {
+		return config
+	}
+	for _, slotInterface := range slots {
+		slot, ok := slotInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := slot["name"].(string)
+		prompt, _ := slot["prompt"].(string)
+		slotType, _ := slot["type"].(string)
+		config.Slots = append(config.Slots, FormSlot{
+			Name:   name,
+			Prompt: prompt,
+			Type:   slotType,
+		})
+	}
+	return config
+}
+
+// ReceiptVerifyConfig returns the typed config for a receipt_verify node
+func (n *FlowNode) ReceiptVerifyConfig() ReceiptVerifyNodeConfig {
+	return ReceiptVerifyNodeConfig{
+		Message:           n.dataString("message", "text"),
+		ExpectedAmount:    n.dataString("expectedAmount", "expected_amount", "amount"),
+		ExpectedReference: n.dataString("expectedReference", "expected_reference", "reference"),
+		Tolerance:         n.dataFloat("tolerance"),
+		MinConfidence:     n.dataFloat("minConfidence", "min_confidence"),
+	}
+}
+
+// DocumentConfig returns the typed config for a document node
+func (n *FlowNode) DocumentConfig() DocumentNodeConfig {
+	return DocumentNodeConfig{
+		TemplateID: int64(n.dataFloat("templateId", "template_id")),
+		Caption:    n.dataString("caption", "message"),
+	}
+}
+
+// ImageGenConfig returns the typed config for an image_gen node
+func (n *FlowNode) ImageGenConfig() ImageGenNodeConfig {
+	config := ImageGenNodeConfig{
+		BaseImageURL: n.dataString("baseImageUrl", "base_image_url", "imageUrl"),
+		Prompt:       n.dataString("prompt"),
+		Caption:      n.dataString("caption", "message"),
+	}
+	overlays, ok := n.Data["overlays"].([]interface{})
+	if !ok {
+		return config
+	}
+	for _, overlayInterface := range overlays {
+		overlay, ok := overlayInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		text, _ := overlay["text"].(string)
+		x, _ := overlay["x"].(float64)
+		y, _ := overlay["y"].(float64)
+		size, _ := overlay["size"].(float64)
+		color, _ := overlay["color"].(string)
+		config.Overlays = append(config.Overlays, ImageGenOverlay{
+			Text:  text,
+			X:     int(x),
+			Y:     int(y),
+			Size:  int(size),
+			Color: color,
+		})
+	}
+	return config
+}
+
+// AIPromptConfig returns the typed config for an AI prompt node
+func (n *FlowNode) AIPromptConfig() AIPromptNodeConfig {
+	return AIPromptNodeConfig{
+		SystemPrompt: n.dataString("systemPrompt", "system_prompt"),
+		APIProvider:  n.dataString("apiProvider", "apiprovider"),
+		Instance:     n.dataString("instance"),
+		Vision:       n.dataBool("vision", "enableVision"),
+		Model:        n.dataString("model", "modelOverride"),
+		Temperature:  n.dataFloat("temperature"),
+		TopP:         n.dataFloat("topP", "top_p"),
+		MaxTokens:    int(n.dataFloat("maxTokens", "max_tokens")),
+	}
+}
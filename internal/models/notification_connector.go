@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Operational events posted to notification connectors
+const (
+	OpsEventDeviceDisconnected  = "device.disconnected"
+	OpsEventSLABreached         = "handoff.sla_breached"
+	OpsEventFlowCompleted       = "campaign.flow_completed"
+	OpsEventQuotaNearLimit      = "quota.near_limit"
+	OpsEventLeadQualified       = "lead.qualified"
+	OpsEventCredentialUnhealthy = "device.credential_unhealthy"
+	OpsEventLatencySLOBreach    = "device.latency_slo_breached"
+)
+
+// Notification connector platforms
+const (
+	ConnectorPlatformSlack   = "slack"
+	ConnectorPlatformDiscord = "discord"
+)
+
+// NotificationConnector is one Slack or Discord channel webhook that receives
+// operational events for a device. Events is a comma-separated list of event
+// names; empty means all events
+type NotificationConnector struct {
+	ID         string    `json:"id" db:"id"`
+	IDDevice   string    `json:"id_device" db:"id_device"`
+	Platform   string    `json:"platform" db:"platform"`
+	WebhookURL string    `json:"webhook_url" db:"webhook_url"`
+	Events     string    `json:"events" db:"events"`
+	Enabled    bool      `json:"enabled" db:"enabled"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// WantsEvent reports whether the connector listens for an event
+func (n *NotificationConnector) WantsEvent(event string) bool {
+	if n.Events == "" {
+		return true
+	}
+	for _, name := range strings.Split(n.Events, ",") {
+		if strings.TrimSpace(name) == event {
+			return true
+		}
+	}
+	return false
+}
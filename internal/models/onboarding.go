@@ -0,0 +1,31 @@
+package models
+
+// Onboarding wizard steps, in the order a new user completes them
+const (
+	OnboardingStepCreateDevice    = "create_device"
+	OnboardingStepConnectProvider = "connect_provider"
+	OnboardingStepScanQR          = "scan_qr"
+	OnboardingStepCreateFlow      = "create_flow"
+	OnboardingStepSendTest        = "send_test_message"
+)
+
+// OnboardingSteps is the wizard order served to the frontend
+var OnboardingSteps = []string{
+	OnboardingStepCreateDevice,
+	OnboardingStepConnectProvider,
+	OnboardingStepScanQR,
+	OnboardingStepCreateFlow,
+	OnboardingStepSendTest,
+}
+
+// OnboardingStepState is one wizard step with its completion state
+type OnboardingStepState struct {
+	Step      string `json:"step"`
+	Completed bool   `json:"completed"`
+}
+
+// OnboardingState is the user's overall wizard progress
+type OnboardingState struct {
+	Steps     []OnboardingStepState `json:"steps"`
+	Completed bool                  `json:"completed"`
+}
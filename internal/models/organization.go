@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Organization roles, ordered from most to least privileged. Owners manage
+// the organization itself, admins manage membership and devices, agents work
+// conversations, viewers get read-only access
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleAgent  = "agent"
+	OrgRoleViewer = "viewer"
+)
+
+// orgRoleRank orders roles for permission comparisons
+var orgRoleRank = map[string]int{
+	OrgRoleOwner:  4,
+	OrgRoleAdmin:  3,
+	OrgRoleAgent:  2,
+	OrgRoleViewer: 1,
+}
+
+// OrgRoleValid reports whether role is one of the known organization roles
+func OrgRoleValid(role string) bool {
+	_, ok := orgRoleRank[role]
+	return ok
+}
+
+// OrgRoleAllows reports whether role meets or exceeds the required role
+func OrgRoleAllows(role, required string) bool {
+	return orgRoleRank[role] >= orgRoleRank[required]
+}
+
+// Organization is a team that shares devices, flows and conversations
+type Organization struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	OwnerID   string    `json:"owner_id" db:"owner_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// OrganizationMember is one user's membership in an organization
+type OrganizationMember struct {
+	OrgID     string    `json:"org_id" db:"org_id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateOrganizationRequest is the payload for creating an organization
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+// AddOrganizationMemberRequest is the payload for adding or re-roling a member
+type AddOrganizationMemberRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
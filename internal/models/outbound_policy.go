@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// OptOut is one phone number that must not receive proactive outbound
+// messages from a device
+type OptOut struct {
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// QuietHours is a device's do-not-disturb window for proactive outbound
+// messages. The window can wrap past midnight (e.g. 22 to 8)
+type QuietHours struct {
+	IDDevice  string `json:"id_device" db:"id_device"`
+	Enabled   bool   `json:"enabled" db:"enabled"`
+	StartHour int    `json:"start_hour" db:"start_hour"`
+	EndHour   int    `json:"end_hour" db:"end_hour"`
+}
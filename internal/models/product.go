@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Product is one catalog entry a device can sell. Catalog flow nodes send the
+// active products for a device as a paginated list the prospect picks from
+type Product struct {
+	ID          string    `json:"id" db:"id"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	ImageURL    string    `json:"image_url" db:"image_url"`
+	Price       float64   `json:"price" db:"price"`
+	SKU         string    `json:"sku" db:"sku"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
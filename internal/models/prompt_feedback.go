@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Sources of agent corrections to AI-generated messages
+const (
+	MessageEditSourceApproval = "approval"
+	MessageEditSourceTakeover = "takeover"
+)
+
+// RecordedEdit is one captured diff between an AI-proposed message and the
+// text an agent actually sent, from the approval queue or the takeover console
+type RecordedEdit struct {
+	ID          int       `json:"id" db:"id"`
+	FlowID      string    `json:"flow_id" db:"flow_id"`
+	NodeID      string    `json:"node_id" db:"node_id"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	Source      string    `json:"source" db:"source"`
+	Original    string    `json:"original" db:"original"`
+	Edited      string    `json:"edited" db:"edited"`
+	EditedBy    string    `json:"edited_by" db:"edited_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// CorrectionPattern is one recurring kind of correction agents make to a
+// flow's AI responses
+type CorrectionPattern struct {
+	Kind    string `json:"kind"`
+	Count   int    `json:"count"`
+	Example string `json:"example,omitempty"`
+}
+
+// WordFrequency counts how many distinct edits added or removed a word
+type WordFrequency struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// PromptFeedbackReport aggregates the agent corrections captured for a flow
+type PromptFeedbackReport struct {
+	FlowID            string              `json:"flow_id"`
+	TotalEdits        int                 `json:"total_edits"`
+	BySource          map[string]int      `json:"by_source"`
+	Patterns          []CorrectionPattern `json:"patterns"`
+	FrequentAdditions []WordFrequency     `json:"frequent_additions"`
+	FrequentRemovals  []WordFrequency     `json:"frequent_removals"`
+	GeneratedAt       time.Time           `json:"generated_at"`
+}
+
+// PromptSuggestion is one proposed system-prompt amendment derived from
+// recurring agent corrections
+type PromptSuggestion struct {
+	Amendment   string `json:"amendment"`
+	Reason      string `json:"reason"`
+	Occurrences int    `json:"occurrences"`
+}
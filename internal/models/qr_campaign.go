@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// QRCampaign is one printed/in-store QR entry point. The QR encodes a wa.me
+// link prefilled with EntryKeyword; when a prospect scans it the suffix
+// identifies the campaign while the base Keyword starts the mapped flow
+type QRCampaign struct {
+	ID           string    `json:"id" db:"id"`
+	IDDevice     string    `json:"id_device" db:"id_device"`
+	Name         string    `json:"name" db:"name"`
+	FlowID       string    `json:"flow_id" db:"flow_id"`
+	Keyword      string    `json:"keyword" db:"keyword"`
+	EntryKeyword string    `json:"entry_keyword" db:"entry_keyword"`
+	ScanCount    int       `json:"scan_count" db:"scan_count"`
+	InviteLink   string    `json:"invite_link,omitempty"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
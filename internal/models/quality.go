@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// QualityScore is one automated LLM evaluation of a completed conversation
+// against the rubric (accuracy, tone, goal completion). FlowVersion is a
+// fingerprint of the flow's nodes at evaluation time so scores can be
+// compared across prompt changes
+type QualityScore struct {
+	ID             int       `json:"id" db:"id"`
+	ProspectNum    string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice       string    `json:"id_device" db:"id_device"`
+	FlowID         string    `json:"flow_id" db:"flow_id"`
+	FlowVersion    string    `json:"flow_version" db:"flow_version"`
+	Accuracy       float64   `json:"accuracy" db:"accuracy"`
+	Tone           float64   `json:"tone" db:"tone"`
+	GoalCompletion float64   `json:"goal_completion" db:"goal_completion"`
+	Overall        float64   `json:"overall" db:"overall"`
+	Summary        string    `json:"summary" db:"summary"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// QualityVersionSummary averages the rubric scores per flow version so
+// prompt changes can be compared objectively
+type QualityVersionSummary struct {
+	FlowID            string    `json:"flow_id"`
+	FlowVersion       string    `json:"flow_version"`
+	Conversations     int       `json:"conversations"`
+	AvgAccuracy       float64   `json:"avg_accuracy"`
+	AvgTone           float64   `json:"avg_tone"`
+	AvgGoalCompletion float64   `json:"avg_goal_completion"`
+	AvgOverall        float64   `json:"avg_overall"`
+	LastScoredAt      time.Time `json:"last_scored_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ConversationReadState represents a per-agent read cursor for a conversation
+type ConversationReadState struct {
+	ID          int       `json:"id" db:"id"`
+	AgentID     string    `json:"agent_id" db:"agent_id"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	LastReadAt  time.Time `json:"last_read_at" db:"last_read_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ConversationUnreadState represents the unread flag for a single conversation
+// as seen by one agent in the inbox
+type ConversationUnreadState struct {
+	ProspectNum string `json:"prospect_num"`
+	Unread      bool   `json:"unread"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Referral is a prospect's unique invite code. The code travels inside a
+// wa.me deep link; conversations opening with it credit the referrer
+type Referral struct {
+	ID          string    `json:"id" db:"id"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	Code        string    `json:"code" db:"code"`
+	InviteLink  string    `json:"invite_link,omitempty"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReferralLeaderboardEntry is one row of the referral leaderboard report
+type ReferralLeaderboardEntry struct {
+	ProspectNum   string `json:"prospect_num" db:"referrer_num"`
+	ReferralCount int    `json:"referral_count" db:"referral_count"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// RetentionPolicy controls how long a device keeps raw conversation data.
+// Conversation content (message logs and stored transcripts) is purged after
+// ConversationDays; completed flow executions are deleted after
+// ExecutionDays. Aggregates such as digest reports are always kept. A zero
+// value for either knob disables that purge
+type RetentionPolicy struct {
+	IDDevice         string `json:"id_device" db:"id_device"`
+	Enabled          bool   `json:"enabled" db:"enabled"`
+	ConversationDays int    `json:"conversation_days" db:"conversation_days"`
+	ExecutionDays    int    `json:"execution_days" db:"execution_days"`
+}
+
+// RetentionPreview reports how many rows a purge run would touch, for the
+// dry-run endpoint
+type RetentionPreview struct {
+	IDDevice            string `json:"id_device"`
+	ConversationLogs    int64  `json:"conversation_logs"`
+	ConversationBlobs   int64  `json:"conversation_blobs"`
+	CompletedExecutions int64  `json:"completed_executions"`
+}
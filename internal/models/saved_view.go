@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Saved view scopes: which list the view applies to
+const (
+	SavedViewScopeInbox     = "inbox"
+	SavedViewScopeProspects = "prospects"
+)
+
+// SavedView is a per-user saved filter/sort/column preset for the inbox or
+// prospect lists. A shared view is visible to everyone working the same
+// device, so teams can standardize on named queues
+type SavedView struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	IDDevice  string    `json:"id_device" db:"id_device"`
+	Name      string    `json:"name" db:"name"`
+	Scope     string    `json:"scope" db:"scope"`
+	Filters   string    `json:"filters" db:"filters"`
+	Sort      string    `json:"sort" db:"sort"`
+	Columns   string    `json:"columns" db:"columns"`
+	IsDefault bool      `json:"is_default" db:"is_default"`
+	Shared    bool      `json:"shared" db:"shared"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ShortLink is one shortened URL. The code resolves through the public
+// /s/:code redirect; each redirect counts a click until ExpiresAt passes.
+// ProspectNum is set for links shortened inside an outbound message so the
+// click can be attributed to that conversation
+type ShortLink struct {
+	ID          string     `json:"id" db:"id"`
+	IDDevice    string     `json:"id_device" db:"id_device"`
+	ProspectNum string     `json:"prospect_num,omitempty" db:"prospect_num"`
+	Code        string     `json:"code" db:"code"`
+	TargetURL   string     `json:"target_url" db:"target_url"`
+	ClickCount  int        `json:"click_count" db:"click_count"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	ShortURL    string     `json:"short_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsExpired reports whether the link has passed its expiry
+func (l *ShortLink) IsExpired() bool {
+	return l.ExpiresAt != nil && time.Now().After(*l.ExpiresAt)
+}
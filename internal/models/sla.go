@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// SLAEscalationAction represents what happens when a handoff breaches its SLA
+type SLAEscalationAction string
+
+const (
+	SLAActionNotifyManager  SLAEscalationAction = "notify_manager"
+	SLAActionReassign       SLAEscalationAction = "reassign"
+	SLAActionApologyMessage SLAEscalationAction = "apology_message"
+)
+
+// SLAPolicy represents a per-device first-response SLA for human handoffs
+type SLAPolicy struct {
+	ID                   int       `json:"id" db:"id"`
+	IDDevice             string    `json:"id_device" db:"id_device"`
+	FirstResponseMinutes int       `json:"first_response_minutes" db:"first_response_minutes"`
+	EscalationAction     string    `json:"escalation_action" db:"escalation_action"`
+	ApologyMessage       *string   `json:"apology_message" db:"apology_message"`
+	ManagerAgentID       *string   `json:"manager_agent_id" db:"manager_agent_id"`
+	IsActive             bool      `json:"is_active" db:"is_active"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SLAEvent tracks one handoff from creation to first agent response
+type SLAEvent struct {
+	ID              int        `json:"id" db:"id"`
+	ProspectNum     string     `json:"prospect_num" db:"prospect_num"`
+	IDDevice        string     `json:"id_device" db:"id_device"`
+	HandoffAt       time.Time  `json:"handoff_at" db:"handoff_at"`
+	FirstResponseAt *time.Time `json:"first_response_at" db:"first_response_at"`
+	Breached        bool       `json:"breached" db:"breached"`
+	EscalatedAt     *time.Time `json:"escalated_at" db:"escalated_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SLAComplianceReport summarizes SLA performance for a device
+type SLAComplianceReport struct {
+	IDDevice           string  `json:"id_device"`
+	TotalHandoffs      int     `json:"total_handoffs"`
+	MetSLA             int     `json:"met_sla"`
+	Breached           int     `json:"breached"`
+	Pending            int     `json:"pending"`
+	CompliancePercent  float64 `json:"compliance_percent"`
+	AvgResponseMinutes float64 `json:"avg_response_minutes"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// StageTransition is one recorded stage change for a prospect. Source names
+// what triggered it: a flow node ID or "ai_response" for stages extracted
+// from AI replies
+type StageTransition struct {
+	ID          int       `json:"id" db:"id"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	FromStage   string    `json:"from_stage" db:"from_stage"`
+	ToStage     string    `json:"to_stage" db:"to_stage"`
+	Source      string    `json:"source" db:"source"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// StageDuration is the average time prospects spend in one stage before
+// moving on
+type StageDuration struct {
+	Stage          string  `json:"stage"`
+	AverageSeconds float64 `json:"average_seconds"`
+	Samples        int     `json:"samples"`
+}
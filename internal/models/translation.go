@@ -0,0 +1,65 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ProspectLanguage is the language stored for one conversation, either
+// detected from the prospect's messages or set explicitly
+type ProspectLanguage struct {
+	ID          int            `json:"id" db:"id"`
+	ProspectNum string         `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string         `json:"id_device" db:"id_device"`
+	Language    string         `json:"language" db:"language"`
+	Source      sql.NullString `json:"source" db:"source"` // "detected" or "set"
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// MachineTranslation is one cached machine translation of a node's default
+// content. The source hash invalidates the cache when the default content
+// changes; a human translation added to the node always wins over this
+type MachineTranslation struct {
+	ID             int       `json:"id" db:"id"`
+	FlowID         string    `json:"flow_id" db:"flow_id"`
+	NodeID         string    `json:"node_id" db:"node_id"`
+	Language       string    `json:"language" db:"language"`
+	SourceHash     string    `json:"source_hash" db:"source_hash"`
+	SourceText     string    `json:"source_text" db:"source_text"`
+	TranslatedText string    `json:"translated_text" db:"translated_text"`
+	UseCount       int       `json:"use_count" db:"use_count"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	LastUsedAt     time.Time `json:"last_used_at" db:"last_used_at"`
+}
+
+// MachineTranslationUse flags one machine-translated delivery in a
+// conversation so the conversation view can mark those messages
+type MachineTranslationUse struct {
+	ID          int       `json:"id" db:"id"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	IDDevice    string    `json:"id_device" db:"id_device"`
+	FlowID      string    `json:"flow_id" db:"flow_id"`
+	NodeID      string    `json:"node_id" db:"node_id"`
+	Language    string    `json:"language" db:"language"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// NodeTranslationStatus reports one translatable node's coverage against the
+// languages used anywhere in the flow
+type NodeTranslationStatus struct {
+	NodeID    string   `json:"node_id"`
+	Type      NodeType `json:"type"`
+	Languages []string `json:"languages"`
+	Missing   []string `json:"missing"`
+}
+
+// FlowTranslationReport summarizes translation coverage for a flow: Languages
+// is the union of language codes used by any node, Nodes lists each
+// translatable node with the languages it is missing, and Complete is true
+// when no node is missing any of them
+type FlowTranslationReport struct {
+	FlowID    string                  `json:"flow_id"`
+	Languages []string                `json:"languages"`
+	Nodes     []NodeTranslationStatus `json:"nodes"`
+	Complete  bool                    `json:"complete"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UnknownWebhookPayload is a webhook payload the normalization layer could not
+// extract required fields from, stored with PII masked so maintainers can add
+// the new provider variant to the extraction logic
+type UnknownWebhookPayload struct {
+	ID            int       `json:"id" db:"id"`
+	IDDevice      string    `json:"id_device" db:"id_device"`
+	Provider      string    `json:"provider" db:"provider"`
+	MissingFields string    `json:"missing_fields" db:"missing_fields"`
+	PayloadMasked string    `json:"payload_masked" db:"payload_masked"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
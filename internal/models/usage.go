@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// DailyMessageCount is one day of inbound/outbound message volume
+type DailyMessageCount struct {
+	Date     string `json:"date" db:"date"`
+	Inbound  int64  `json:"inbound" db:"inbound"`
+	Outbound int64  `json:"outbound" db:"outbound"`
+}
+
+// DailyTokenCount is one day of AI token consumption
+type DailyTokenCount struct {
+	Date   string `json:"date"`
+	Tokens int64  `json:"tokens"`
+}
+
+// FlowUsageCount is one flow with its conversation count
+type FlowUsageCount struct {
+	FlowID        string `json:"flow_id" db:"flow_id"`
+	Conversations int64  `json:"conversations" db:"conversations"`
+}
+
+// ProviderErrorRate is one provider's recent send failure rate
+type ProviderErrorRate struct {
+	Provider  string  `json:"provider"`
+	ErrorRate float64 `json:"error_rate"`
+	Failures  int64   `json:"failures"`
+	Total     int64   `json:"total"`
+}
+
+// UsageDashboard aggregates one org's usage for the dashboard
+type UsageDashboard struct {
+	GeneratedAt         time.Time           `json:"generated_at"`
+	MessagesPerDay      []DailyMessageCount `json:"messages_per_day"`
+	TokensPerDay        []DailyTokenCount   `json:"tokens_per_day"`
+	ActiveConversations int64               `json:"active_conversations"`
+	TopFlows            []FlowUsageCount    `json:"top_flows"`
+	ProviderErrorRates  []ProviderErrorRate `json:"provider_error_rates"`
+}
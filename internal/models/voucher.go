@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Voucher is a discount code issued by a voucher flow node. Codes expire and
+// carry a redemption limit; POS systems validate them via the redeem endpoint
+type Voucher struct {
+	ID              string     `json:"id" db:"id"`
+	IDDevice        string     `json:"id_device" db:"id_device"`
+	Code            string     `json:"code" db:"code"`
+	Campaign        string     `json:"campaign" db:"campaign"`
+	Discount        float64    `json:"discount" db:"discount"`
+	IssuedTo        string     `json:"issued_to" db:"issued_to"`
+	MaxRedemptions  int        `json:"max_redemptions" db:"max_redemptions"`
+	RedemptionCount int        `json:"redemption_count" db:"redemption_count"`
+	ExpiresAt       *time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// VoucherRedemption is one logged use of a voucher code
+type VoucherRedemption struct {
+	ID          int       `json:"id" db:"id"`
+	Code        string    `json:"code" db:"code"`
+	ProspectNum string    `json:"prospect_num" db:"prospect_num"`
+	Source      string    `json:"source" db:"source"`
+	RedeemedAt  time.Time `json:"redeemed_at" db:"redeemed_at"`
+}
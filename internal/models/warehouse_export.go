@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// WarehouseSchemaVersion is stamped on every exported event so warehouse-side
+// pipelines can migrate when the row shape changes
+const WarehouseSchemaVersion = 1
+
+// WarehouseExportConfig is a device's analytics warehouse connector. The
+// endpoint receives batched events as JSON lines, which ClickHouse's HTTP
+// interface (FORMAT JSONEachRow) and BigQuery ingestion proxies accept
+type WarehouseExportConfig struct {
+	IDDevice   string    `json:"id_device" db:"id_device"`
+	Endpoint   string    `json:"endpoint" db:"endpoint"`
+	AuthHeader string    `json:"auth_header" db:"auth_header"`
+	Enabled    bool      `json:"enabled" db:"enabled"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// WarehouseEvent is one exported row
+type WarehouseEvent struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Event         string                 `json:"event"`
+	IDDevice      string                 `json:"id_device"`
+	Timestamp     string                 `json:"timestamp"`
+	Data          map[string]interface{} `json:"data"`
+}
+
+// WarehouseProspectSnapshot is one prospect row replayed during a backfill
+type WarehouseProspectSnapshot struct {
+	ProspectNum     string `json:"prospect_num"`
+	ProspectName    string `json:"prospect_name"`
+	Stage           string `json:"stage"`
+	ExecutionStatus string `json:"execution_status"`
+	Niche           string `json:"niche"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
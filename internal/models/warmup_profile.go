@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// WarmupProfile is a device's send warm-up schedule. The daily cap starts at
+// BaseCap on StartedAt and grows by GrowthPerDay each day until MaxCap
+type WarmupProfile struct {
+	IDDevice     string    `json:"id_device" db:"id_device"`
+	Enabled      bool      `json:"enabled" db:"enabled"`
+	StartedAt    time.Time `json:"started_at" db:"started_at"`
+	BaseCap      int       `json:"base_cap" db:"base_cap"`
+	GrowthPerDay int       `json:"growth_per_day" db:"growth_per_day"`
+	MaxCap       int       `json:"max_cap" db:"max_cap"`
+}
+
+// CapForDate returns the allowed daily sends on a given date
+func (p *WarmupProfile) CapForDate(date time.Time) int {
+	days := int(date.Sub(p.StartedAt).Hours() / 24)
+	if days < 0 {
+		days = 0
+	}
+	cap := p.BaseCap + days*p.GrowthPerDay
+	if cap > p.MaxCap {
+		cap = p.MaxCap
+	}
+	return cap
+}
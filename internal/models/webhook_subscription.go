@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Execution lifecycle events emitted to webhook subscriptions
+const (
+	ExecutionEventStarted      = "execution.started"
+	ExecutionEventStageChanged = "execution.stage_changed"
+	ExecutionEventWaitingReply = "execution.waiting_reply"
+	ExecutionEventCompleted    = "execution.completed"
+	ExecutionEventExpired      = "execution.expired"
+	ExecutionEventHandedOff    = "execution.handed_off"
+)
+
+// WebhookSubscription is one external URL that receives execution lifecycle
+// events for a device. Events is a comma-separated list of event names; empty
+// means all events. Payloads are signed with the subscription secret
+type WebhookSubscription struct {
+	ID        string    `json:"id" db:"id"`
+	IDDevice  string    `json:"id_device" db:"id_device"`
+	URL       string    `json:"url" db:"url"`
+	Events    string    `json:"events" db:"events"`
+	Secret    string    `json:"secret" db:"secret"`
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// WantsEvent reports whether the subscription listens for an event
+func (w *WebhookSubscription) WantsEvent(event string) bool {
+	if w.Events == "" {
+		return true
+	}
+	for _, name := range strings.Split(w.Events, ",") {
+		if strings.TrimSpace(name) == event {
+			return true
+		}
+	}
+	return false
+}
@@ -26,8 +26,10 @@ type AIWhatsappRepository interface {
 	GetAIWhatsappByNiche(niche string) ([]models.AIWhatsapp, error)
 	GetActiveAIConversations() ([]models.AIWhatsapp, error)
 	GetConversationHistory(prospectNum string, limit int) ([]models.ConversationLog, error)
+	GetConversationHistoryByDevice(prospectNum, idDevice string, limit int) ([]models.ConversationLog, error)
 	GetConversationLogsByStage(stage string) ([]models.ConversationLog, error)
 	GetAIWhatsappByProspectAndDevice(prospectNum, idDevice string) (*models.AIWhatsapp, error)
+	GetAIWhatsappByExecutionID(executionID string) (*models.AIWhatsapp, error)
 
 	// Update operations
 	UpdateAIWhatsapp(ai *models.AIWhatsapp) error
@@ -54,6 +56,14 @@ type AIWhatsappRepository interface {
 	// Data table operations
 	GetAllAIWhatsappData(limit, offset int, deviceFilter, stageFilter, search string, userID string, startDate, endDate *time.Time) ([]models.AIWhatsapp, int, error)
 
+	// Agent inbox: conversations currently in human takeover
+	ListHumanConversations(userID string) ([]models.AIWhatsapp, error)
+
+	// Structured message storage (messages table); conv_last stays dual-written
+	InsertMessageRow(prospectNum, idDevice, direction, messageType, content, stage string) error
+	ListMessages(prospectNum, idDevice string, limit, offset int) ([]models.Message, int, error)
+	BackfillMessagesFromConvLast() (int, error)
+
 	// Database access for transactions
 	GetDB() *sql.DB
 
@@ -571,6 +581,51 @@ func (r *aiWhatsappRepository) GetAllAIWhatsappData(limit, offset int, deviceFil
 	return conversations, total, nil
 }
 
+// ListHumanConversations returns the user's conversations currently in human
+// takeover mode, newest activity first, for the agent inbox
+func (r *aiWhatsappRepository) ListHumanConversations(userID string) ([]models.AIWhatsapp, error) {
+	query := `
+		SELECT a.id_prospect, a.id_device, a.prospect_num, a.prospect_name, a.stage, a.date_order, a.conv_last,
+		       a.conv_current, a.human, a.niche, a.intro,
+		       a.balas, a.keywordiklan, a.marketer, a.update_today,
+		       a.created_at, a.updated_at
+		FROM ai_whatsapp a
+		JOIN device_setting d ON a.id_device = d.id_device
+		WHERE d.user_id = ? AND a.human = 1
+		ORDER BY a.updated_at DESC
+	`
+
+	rows, err := r.db.Query(Rebind(query), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list human conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var conversations []models.AIWhatsapp
+	for rows.Next() {
+		ai := models.AIWhatsapp{}
+		var convLastJSON sql.NullString
+		var convCurrentSQL sql.NullString
+
+		err := rows.Scan(
+			&ai.IDProspect, &ai.IDDevice, &ai.ProspectNum, &ai.ProspectName, &ai.Stage, &ai.DateOrder, &convLastJSON,
+			&convCurrentSQL, &ai.Human, &ai.Niche, &ai.Intro,
+			&ai.Balas, &ai.KeywordIklan, &ai.Marketer, &ai.UpdateToday,
+			&ai.CreatedAt, &ai.UpdatedAt,
+		)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan human conversation")
+			continue
+		}
+
+		ai.ConvLast = convLastJSON
+		ai.ConvCurrent = convCurrentSQL
+		conversations = append(conversations, ai)
+	}
+
+	return conversations, rows.Err()
+}
+
 // GetAnalyticsData retrieves analytics data from ai_whatsapp table with date filtering
 func (r *aiWhatsappRepository) GetAnalyticsData(startDate, endDate time.Time, idDevice string, userID string) (map[string]interface{}, error) {
 	logrus.WithFields(logrus.Fields{
@@ -965,6 +1020,44 @@ func (r *aiWhatsappRepository) GetConversationHistory(prospectNum string, limit
 	return logs, nil
 }
 
+// GetConversationHistoryByDevice retrieves conversation history for a
+// prospect scoped to one device, so exports never mix in rows the same
+// phone number produced on other devices
+func (r *aiWhatsappRepository) GetConversationHistoryByDevice(prospectNum, idDevice string, limit int) ([]models.ConversationLog, error) {
+	query := `
+		SELECT id, prospect_num, id_device, message, sender, stage, created_at
+		FROM conversation_log
+		WHERE prospect_num = ? AND id_device = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(Rebind(query), prospectNum, idDevice, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get conversation history for device")
+		return nil, fmt.Errorf("failed to get conversation history for device: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []models.ConversationLog
+	for rows.Next() {
+		log := models.ConversationLog{}
+		err := rows.Scan(
+			&log.ID, &log.ProspectNum, &log.IDDevice, &log.Message,
+			&log.Sender, &log.Stage, &log.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan conversation log")
+			continue
+		}
+
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
+
 // GetConversationLogsByStage retrieves conversation logs by stage
 func (r *aiWhatsappRepository) GetConversationLogsByStage(stage string) ([]models.ConversationLog, error) {
 	query := `
@@ -1386,6 +1479,58 @@ func (r *aiWhatsappRepository) GetAIWhatsappByProspectAndDevice(prospectNum, idD
 	return ai, nil
 }
 
+// GetAIWhatsappByExecutionID retrieves AI WhatsApp conversation by its flow execution ID
+func (r *aiWhatsappRepository) GetAIWhatsappByExecutionID(executionID string) (*models.AIWhatsapp, error) {
+	// Check if database connection is available
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is not available")
+	}
+
+	query := `
+		SELECT id_prospect, id_device, prospect_num, stage, date_order, conv_last,
+		       conv_current, human, niche, intro,
+		       balas, keywordiklan, marketer, update_today,
+		       created_at, updated_at,
+		       current_node_id, waiting_for_reply, flow_id, last_node_id,
+		       flow_reference, execution_status, execution_id
+		FROM ai_whatsapp
+		WHERE execution_id = ?
+	`
+
+	row := r.db.QueryRow(Rebind(query), executionID)
+
+	ai := &models.AIWhatsapp{}
+	var convLastJSON sql.NullString
+	var convCurrentSQL sql.NullString
+
+	err := row.Scan(
+		&ai.IDProspect, &ai.IDDevice, &ai.ProspectNum, &ai.Stage, &ai.DateOrder, &convLastJSON,
+		&convCurrentSQL, &ai.Human, &ai.Niche, &ai.Intro,
+		&ai.Balas, &ai.KeywordIklan, &ai.Marketer, &ai.UpdateToday,
+		&ai.CreatedAt, &ai.UpdatedAt,
+		&ai.CurrentNodeID, &ai.WaitingForReply, &ai.FlowID, &ai.LastNodeID,
+		&ai.FlowReference, &ai.ExecutionStatus, &ai.ExecutionID,
+	)
+
+	ai.ConvCurrent = convCurrentSQL
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found
+		}
+		logrus.WithError(err).Error("Failed to get AI WhatsApp conversation by execution ID")
+		return nil, fmt.Errorf("failed to get AI WhatsApp conversation: %w", err)
+	}
+
+	if convLastJSON.Valid && convLastJSON.String != "" {
+		ai.ConvLast = convLastJSON
+	} else {
+		ai.ConvLast = sql.NullString{Valid: false}
+	}
+
+	return ai, nil
+}
+
 // SaveConversationHistory saves conversation history to conv_last field as plain text
 // If record exists, it updates the conv_last field; otherwise, it creates a new record
 // Saves NULL instead of empty string when there's no conversation data
@@ -1522,6 +1667,23 @@ func (r *aiWhatsappRepository) SaveConversationHistory(prospectNum, idDevice, us
 			}).Info("New conversation record created successfully")
 		}
 
+		// Dual-write the entries as structured message rows; conv_last above
+		// stays authoritative for legacy readers
+		insertMessage := `
+			INSERT INTO messages (prospect_num, id_device, direction, message_type, content, stage, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`
+		if userMessage != "" {
+			if _, err := tx.Exec(insertMessage, prospectNum, idDevice, "inbound", "text", userMessage, stageValue, now); err != nil {
+				return fmt.Errorf("failed to insert inbound message row: %w", err)
+			}
+		}
+		if botResponse != "" {
+			if _, err := tx.Exec(insertMessage, prospectNum, idDevice, "outbound", "text", botResponse, stageValue, now); err != nil {
+				return fmt.Errorf("failed to insert outbound message row: %w", err)
+			}
+		}
+
 		return nil
 	})
 }
@@ -1852,3 +2014,150 @@ func (r *aiWhatsappRepository) ReleaseSession(phoneNumber, deviceID string) erro
 
 	return nil
 }
+
+// InsertMessageRow stores one structured message row
+func (r *aiWhatsappRepository) InsertMessageRow(prospectNum, idDevice, direction, messageType, content, stage string) error {
+	var stageValue interface{}
+	if stage != "" {
+		stageValue = stage
+	}
+	if messageType == "" {
+		messageType = "text"
+	}
+
+	query := `
+		INSERT INTO messages (prospect_num, id_device, direction, message_type, content, stage, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.Exec(Rebind(query), prospectNum, idDevice, direction, messageType, content, stageValue, time.Now())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert message row")
+		return fmt.Errorf("failed to insert message row: %w", err)
+	}
+	return nil
+}
+
+// ListMessages returns a page of structured messages for a conversation in
+// chronological order, plus the total count for pagination
+func (r *aiWhatsappRepository) ListMessages(prospectNum, idDevice string, limit, offset int) ([]models.Message, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM messages WHERE prospect_num = ? AND id_device = ?`
+	if err := r.db.QueryRow(Rebind(countQuery), prospectNum, idDevice).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	query := `
+		SELECT id, prospect_num, id_device, direction, message_type, content, stage, created_at
+		FROM messages
+		WHERE prospect_num = ? AND id_device = ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.Query(Rebind(query), prospectNum, idDevice, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []models.Message
+	for rows.Next() {
+		msg := models.Message{}
+		if err := rows.Scan(&msg.ID, &msg.ProspectNum, &msg.IDDevice, &msg.Direction, &msg.MessageType, &msg.Content, &msg.Stage, &msg.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, total, rows.Err()
+}
+
+// BackfillMessagesFromConvLast parses conv_last for conversations that have
+// no structured rows yet and inserts one row per line, using the
+// conversation's updated_at as the best available timestamp. Returns the
+// number of rows inserted; safe to run repeatedly
+func (r *aiWhatsappRepository) BackfillMessagesFromConvLast() (int, error) {
+	query := `
+		SELECT a.prospect_num, a.id_device, a.conv_last, a.stage, a.updated_at
+		FROM ai_whatsapp a
+		WHERE a.conv_last IS NOT NULL AND a.conv_last != '' AND a.id_device IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM messages m
+			WHERE m.prospect_num = a.prospect_num AND m.id_device = a.id_device
+		  )
+	`
+	rows, err := r.db.Query(Rebind(query))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query conversations for backfill: %w", err)
+	}
+	defer rows.Close()
+
+	type backfillRow struct {
+		prospectNum string
+		idDevice    string
+		convLast    string
+		stage       sql.NullString
+		updatedAt   time.Time
+	}
+	var pending []backfillRow
+	for rows.Next() {
+		var row backfillRow
+		if err := rows.Scan(&row.prospectNum, &row.idDevice, &row.convLast, &row.stage, &row.updatedAt); err != nil {
+			return 0, fmt.Errorf("failed to scan conversation for backfill: %w", err)
+		}
+		pending = append(pending, row)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	inserted := 0
+	insertQuery := `
+		INSERT INTO messages (prospect_num, id_device, direction, message_type, content, stage, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	for _, row := range pending {
+		for _, line := range strings.Split(row.convLast, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+
+			direction := "outbound"
+			content := line
+			switch {
+			case strings.HasPrefix(line, "USER:"):
+				direction = "inbound"
+				content = strings.TrimSpace(strings.TrimPrefix(line, "USER:"))
+			case strings.HasPrefix(line, "BOT_COMBINED:"):
+				content = strings.TrimSpace(strings.TrimPrefix(line, "BOT_COMBINED:"))
+			case strings.HasPrefix(line, "BOT:"):
+				content = strings.TrimSpace(strings.TrimPrefix(line, "BOT:"))
+			}
+
+			// Bot entries are JSON-encoded strings in some historical formats
+			if strings.HasPrefix(content, `"`) {
+				var decoded string
+				if err := json.Unmarshal([]byte(content), &decoded); err == nil {
+					content = decoded
+				}
+			}
+			if content == "" {
+				continue
+			}
+
+			var stageValue interface{}
+			if row.stage.Valid && row.stage.String != "" {
+				stageValue = row.stage.String
+			}
+			if _, err := r.db.Exec(Rebind(insertQuery), row.prospectNum, row.idDevice, direction, "text", content, stageValue, row.updatedAt); err != nil {
+				logrus.WithError(err).WithFields(logrus.Fields{
+					"prospect_num": row.prospectNum,
+					"id_device":    row.idDevice,
+				}).Warn("Failed to backfill message row, skipping conversation line")
+				continue
+			}
+			inserted++
+		}
+	}
+
+	return inserted, nil
+}
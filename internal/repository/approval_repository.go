@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ApprovalRepository handles database operations for the human-in-the-loop
+// message approval queue and the per-flow review-mode switch
+type ApprovalRepository interface {
+	IsReviewEnabled(flowID string) (bool, error)
+	SetReviewEnabled(flowID string, enabled bool) error
+	EnqueueMessage(message *models.PendingMessage) error
+	GetPendingMessages(idDevice string) ([]models.PendingMessage, error)
+	GetMessage(id int) (*models.PendingMessage, error)
+	ResolveMessage(id int, status, editedContent, reviewedBy string) error
+	GetEditLog(flowID string, limit int) ([]models.MessageEdit, error)
+}
+
+type approvalRepository struct {
+	db pgDB
+}
+
+// NewApprovalRepository creates a new approval repository
+func NewApprovalRepository(db *sql.DB) ApprovalRepository {
+	return &approvalRepository{db: pgDB{db}}
+}
+
+// IsReviewEnabled reports whether the flow holds AI responses for review.
+// Flows without a row default to sending directly
+func (r *approvalRepository) IsReviewEnabled(flowID string) (bool, error) {
+	var enabled bool
+	err := r.db.QueryRow(`SELECT enabled FROM flow_review_nodepath WHERE flow_id = ?`, flowID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to check flow review mode")
+		return false, err
+	}
+	return enabled, nil
+}
+
+// SetReviewEnabled switches review-before-send mode for a flow
+func (r *approvalRepository) SetReviewEnabled(flowID string, enabled bool) error {
+	query := `
+		INSERT INTO flow_review_nodepath (flow_id, enabled)
+		VALUES (?, ?)
+		ON CONFLICT (flow_id) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	if _, err := r.db.Exec(query, flowID, enabled); err != nil {
+		logrus.WithError(err).Error("Failed to set flow review mode")
+		return err
+	}
+
+	return nil
+}
+
+// EnqueueMessage inserts one AI response awaiting review
+func (r *approvalRepository) EnqueueMessage(message *models.PendingMessage) error {
+	query := `
+		INSERT INTO approval_queue_nodepath (flow_id, node_id, prospect_num, id_device, message_type, content, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query,
+		message.FlowID, message.NodeID, message.ProspectNum, message.IDDevice,
+		message.MessageType, message.Content, models.PendingMessageStatusPending,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to enqueue pending message")
+		return err
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		message.ID = int(id)
+	}
+	message.Status = models.PendingMessageStatusPending
+
+	return nil
+}
+
+// GetPendingMessages returns the device's unreviewed messages, oldest first
+// so agents work the queue in conversation order
+func (r *approvalRepository) GetPendingMessages(idDevice string) ([]models.PendingMessage, error) {
+	query := `
+		SELECT id, flow_id, node_id, prospect_num, id_device, message_type, content, edited_content, status, reviewed_by, created_at, reviewed_at
+		FROM approval_queue_nodepath
+		WHERE id_device = ? AND status = ?
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, idDevice, models.PendingMessageStatusPending)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get pending messages")
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]models.PendingMessage, 0)
+	for rows.Next() {
+		message, err := scanPendingMessage(rows.Scan)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan pending message row")
+			continue
+		}
+		messages = append(messages, *message)
+	}
+
+	return messages, rows.Err()
+}
+
+// GetMessage returns one queued message by ID
+func (r *approvalRepository) GetMessage(id int) (*models.PendingMessage, error) {
+	row := r.db.QueryRow(`
+		SELECT id, flow_id, node_id, prospect_num, id_device, message_type, content, edited_content, status, reviewed_by, created_at, reviewed_at
+		FROM approval_queue_nodepath
+		WHERE id = ?
+	`, id)
+
+	message, err := scanPendingMessage(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get pending message")
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// ResolveMessage records the review outcome for a still-pending message
+func (r *approvalRepository) ResolveMessage(id int, status, editedContent, reviewedBy string) error {
+	query := `
+		UPDATE approval_queue_nodepath
+		SET status = ?, edited_content = ?, reviewed_by = ?, reviewed_at = NOW()
+		WHERE id = ? AND status = ?
+	`
+
+	edited := sql.NullString{String: editedContent, Valid: editedContent != ""}
+	result, err := r.db.Exec(query, status, edited, reviewedBy, id, models.PendingMessageStatusPending)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to resolve pending message")
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetEditLog returns the flow's approved-with-changes messages, newest first
+func (r *approvalRepository) GetEditLog(flowID string, limit int) ([]models.MessageEdit, error) {
+	query := `
+		SELECT flow_id, node_id, content, edited_content, COALESCE(reviewed_by, ''), reviewed_at
+		FROM approval_queue_nodepath
+		WHERE flow_id = ? AND status = ? AND edited_content IS NOT NULL AND edited_content != content
+		ORDER BY reviewed_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, flowID, models.PendingMessageStatusApproved, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get message edit log")
+		return nil, err
+	}
+	defer rows.Close()
+
+	edits := make([]models.MessageEdit, 0)
+	for rows.Next() {
+		var edit models.MessageEdit
+		if err := rows.Scan(&edit.FlowID, &edit.NodeID, &edit.Original, &edit.Edited, &edit.ReviewedBy, &edit.ReviewedAt); err != nil {
+			logrus.WithError(err).Error("Failed to scan message edit row")
+			continue
+		}
+		edits = append(edits, edit)
+	}
+
+	return edits, rows.Err()
+}
+
+func scanPendingMessage(scan func(dest ...interface{}) error) (*models.PendingMessage, error) {
+	message := &models.PendingMessage{}
+	err := scan(
+		&message.ID, &message.FlowID, &message.NodeID, &message.ProspectNum, &message.IDDevice,
+		&message.MessageType, &message.Content, &message.EditedContent,
+		&message.Status, &message.ReviewedBy, &message.CreatedAt, &message.ReviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AssignmentRepository handles database operations for handoff conversation assignment
+type AssignmentRepository interface {
+	// Agent status operations
+	SetAgentOnline(agentID, idDevice string, online bool) error
+	SetAgentSkills(agentID, idDevice string, skills []string) error
+	GetOnlineAgents(idDevice string) ([]models.AgentStatus, error)
+	TouchAgentAssignment(agentID, idDevice string) error
+
+	// Assignment operations
+	GetAssignment(prospectNum, idDevice string) (*models.ConversationAssignment, error)
+	UpsertAssignment(prospectNum, idDevice, agentID, strategy, skill string) error
+	GetActiveAssignmentCounts(idDevice string) (map[string]int, error)
+	GetAssignmentHistory(prospectNum, idDevice string) ([]models.AssignmentHistoryEntry, error)
+
+	// Handoff queue operations for handoffs with no matching online agent
+	EnqueueHandoff(prospectNum, idDevice, skill, strategy string) error
+	GetQueuedHandoffs(idDevice string) ([]models.QueuedHandoff, error)
+	RemoveQueuedHandoff(id int) error
+}
+
+type assignmentRepository struct {
+	db pgDB
+}
+
+// NewAssignmentRepository creates a new assignment repository
+func NewAssignmentRepository(db *sql.DB) AssignmentRepository {
+	return &assignmentRepository{db: pgDB{db}}
+}
+
+// SetAgentOnline marks an agent online or offline for a device
+func (r *assignmentRepository) SetAgentOnline(agentID, idDevice string, online bool) error {
+	onlineValue := 0
+	if online {
+		onlineValue = 1
+	}
+
+	query := `
+		INSERT INTO agent_status_nodepath (agent_id, id_device, is_online, last_seen)
+		VALUES (?, ?, ?, NOW())
+		ON CONFLICT (agent_id, id_device) DO UPDATE SET is_online = EXCLUDED.is_online, last_seen = NOW()
+	`
+
+	_, err := r.db.Exec(query, agentID, idDevice, onlineValue)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set agent online status")
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"agent_id":  agentID,
+		"id_device": idDevice,
+		"is_online": online,
+	}).Info("✅ Agent online status updated")
+
+	return nil
+}
+
+// SetAgentSkills replaces an agent's declared skill tags for a device
+func (r *assignmentRepository) SetAgentSkills(agentID, idDevice string, skills []string) error {
+	skillsValue := strings.Join(skills, ",")
+
+	query := `
+		INSERT INTO agent_status_nodepath (agent_id, id_device, skills, last_seen)
+		VALUES (?, ?, ?, NOW())
+		ON CONFLICT (agent_id, id_device) DO UPDATE SET skills = EXCLUDED.skills, last_seen = NOW()
+	`
+
+	_, err := r.db.Exec(query, agentID, idDevice, skillsValue)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set agent skills")
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"agent_id":  agentID,
+		"id_device": idDevice,
+		"skills":    skillsValue,
+	}).Info("✅ Agent skills updated")
+
+	return nil
+}
+
+// GetOnlineAgents returns all online agents for a device ordered by round-robin
+// position (agents who were assigned longest ago come first)
+func (r *assignmentRepository) GetOnlineAgents(idDevice string) ([]models.AgentStatus, error) {
+	query := `
+		SELECT id, agent_id, id_device, is_online, skills, last_assigned_at, last_seen, created_at, updated_at
+		FROM agent_status_nodepath
+		WHERE id_device = ? AND is_online = 1
+		ORDER BY last_assigned_at IS NULL DESC, last_assigned_at ASC
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get online agents")
+		return nil, err
+	}
+	defer rows.Close()
+
+	agents := make([]models.AgentStatus, 0)
+	for rows.Next() {
+		var agent models.AgentStatus
+		var isOnline int
+		var skills sql.NullString
+		if err := rows.Scan(
+			&agent.ID,
+			&agent.AgentID,
+			&agent.IDDevice,
+			&isOnline,
+			&skills,
+			&agent.LastAssignedAt,
+			&agent.LastSeen,
+			&agent.CreatedAt,
+			&agent.UpdatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan agent status row")
+			continue
+		}
+		agent.IsOnline = isOnline == 1
+		if skills.Valid && skills.String != "" {
+			agent.Skills = utils.SplitAndTrim(skills.String, ",")
+		}
+		agents = append(agents, agent)
+	}
+
+	return agents, rows.Err()
+}
+
+// TouchAgentAssignment records that an agent just received an assignment (for round-robin ordering)
+func (r *assignmentRepository) TouchAgentAssignment(agentID, idDevice string) error {
+	query := `
+		UPDATE agent_status_nodepath
+		SET last_assigned_at = NOW()
+		WHERE agent_id = ? AND id_device = ?
+	`
+
+	_, err := r.db.Exec(query, agentID, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to touch agent assignment time")
+		return err
+	}
+
+	return nil
+}
+
+// GetAssignment returns the current assignment for a conversation, or nil if unassigned
+func (r *assignmentRepository) GetAssignment(prospectNum, idDevice string) (*models.ConversationAssignment, error) {
+	query := `
+		SELECT id, prospect_num, id_device, agent_id, strategy, skill, assigned_at, updated_at
+		FROM conversation_assignment_nodepath
+		WHERE prospect_num = ? AND id_device = ?
+	`
+
+	var assignment models.ConversationAssignment
+	err := r.db.QueryRow(query, prospectNum, idDevice).Scan(
+		&assignment.ID,
+		&assignment.ProspectNum,
+		&assignment.IDDevice,
+		&assignment.AgentID,
+		&assignment.Strategy,
+		&assignment.Skill,
+		&assignment.AssignedAt,
+		&assignment.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get conversation assignment")
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+// UpsertAssignment sets the current assignment for a conversation and appends a history entry
+func (r *assignmentRepository) UpsertAssignment(prospectNum, idDevice, agentID, strategy, skill string) error {
+	// Capture the previous agent for the history trail before overwriting
+	var previousAgentID interface{}
+	existing, err := r.GetAssignment(prospectNum, idDevice)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		previousAgentID = existing.AgentID
+	}
+
+	var skillValue interface{}
+	if skill != "" {
+		skillValue = skill
+	}
+
+	query := `
+		INSERT INTO conversation_assignment_nodepath (prospect_num, id_device, agent_id, strategy, skill)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (prospect_num, id_device) DO UPDATE SET agent_id = EXCLUDED.agent_id, strategy = EXCLUDED.strategy, skill = EXCLUDED.skill, assigned_at = NOW()
+	`
+
+	_, err = r.db.Exec(query, prospectNum, idDevice, agentID, strategy, skillValue)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to upsert conversation assignment")
+		return err
+	}
+
+	historyQuery := `
+		INSERT INTO conversation_assignment_history_nodepath (prospect_num, id_device, agent_id, previous_agent_id, strategy, skill)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	if _, err := r.db.Exec(historyQuery, prospectNum, idDevice, agentID, previousAgentID, strategy, skillValue); err != nil {
+		logrus.WithError(err).Error("Failed to insert assignment history entry")
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"agent_id":     agentID,
+		"strategy":     strategy,
+	}).Info("✅ Conversation assignment updated")
+
+	return nil
+}
+
+// GetActiveAssignmentCounts returns the number of conversations currently assigned
+// to each agent on a device (used by the least-active strategy)
+func (r *assignmentRepository) GetActiveAssignmentCounts(idDevice string) (map[string]int, error) {
+	query := `
+		SELECT agent_id, COUNT(*)
+		FROM conversation_assignment_nodepath
+		WHERE id_device = ?
+		GROUP BY agent_id
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get active assignment counts")
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var agentID string
+		var count int
+		if err := rows.Scan(&agentID, &count); err != nil {
+			logrus.WithError(err).Error("Failed to scan assignment count row")
+			continue
+		}
+		counts[agentID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetAssignmentHistory returns the assignment trail for a conversation, newest first
+func (r *assignmentRepository) GetAssignmentHistory(prospectNum, idDevice string) ([]models.AssignmentHistoryEntry, error) {
+	query := `
+		SELECT id, prospect_num, id_device, agent_id, previous_agent_id, strategy, skill, assigned_at
+		FROM conversation_assignment_history_nodepath
+		WHERE prospect_num = ? AND id_device = ?
+		ORDER BY assigned_at DESC
+	`
+
+	rows, err := r.db.Query(query, prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get assignment history")
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]models.AssignmentHistoryEntry, 0)
+	for rows.Next() {
+		var entry models.AssignmentHistoryEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.ProspectNum,
+			&entry.IDDevice,
+			&entry.AgentID,
+			&entry.PreviousAgentID,
+			&entry.Strategy,
+			&entry.Skill,
+			&entry.AssignedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan assignment history row")
+			continue
+		}
+		history = append(history, entry)
+	}
+
+	return history, rows.Err()
+}
+
+// EnqueueHandoff queues a handoff that has no matching online agent
+func (r *assignmentRepository) EnqueueHandoff(prospectNum, idDevice, skill, strategy string) error {
+	var skillValue interface{}
+	if skill != "" {
+		skillValue = skill
+	}
+
+	query := `
+		INSERT INTO handoff_queue_nodepath (prospect_num, id_device, skill, strategy)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (prospect_num, id_device) DO UPDATE SET skill = EXCLUDED.skill, strategy = EXCLUDED.strategy, queued_at = NOW()
+	`
+
+	_, err := r.db.Exec(query, prospectNum, idDevice, skillValue, strategy)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to enqueue handoff")
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"skill":        skill,
+	}).Info("⏳ Handoff queued waiting for a matching agent")
+
+	return nil
+}
+
+// GetQueuedHandoffs returns waiting handoffs for a device, oldest first
+func (r *assignmentRepository) GetQueuedHandoffs(idDevice string) ([]models.QueuedHandoff, error) {
+	query := `
+		SELECT id, prospect_num, id_device, skill, strategy, queued_at
+		FROM handoff_queue_nodepath
+		WHERE id_device = ?
+		ORDER BY queued_at ASC
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get queued handoffs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	handoffs := make([]models.QueuedHandoff, 0)
+	for rows.Next() {
+		var handoff models.QueuedHandoff
+		if err := rows.Scan(
+			&handoff.ID,
+			&handoff.ProspectNum,
+			&handoff.IDDevice,
+			&handoff.Skill,
+			&handoff.Strategy,
+			&handoff.QueuedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan queued handoff row")
+			continue
+		}
+		handoffs = append(handoffs, handoff)
+	}
+
+	return handoffs, rows.Err()
+}
+
+// RemoveQueuedHandoff deletes a handoff from the waiting queue
+func (r *assignmentRepository) RemoveQueuedHandoff(id int) error {
+	query := `DELETE FROM handoff_queue_nodepath WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove queued handoff")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AuditLogRepository stores the append-only message audit log in
+// audit_log_nodepath. Entries are only ever inserted - updates and deletes
+// would break the hash chain, which is the point
+type AuditLogRepository interface {
+	Insert(entry *models.AuditLogEntry) error
+	LatestHash(deviceID string) (string, error)
+	ListByDevicePeriod(deviceID string, start, end time.Time) ([]models.AuditLogEntry, error)
+	ListByDevice(deviceID string) ([]models.AuditLogEntry, error)
+}
+
+type auditLogRepository struct {
+	db pgDB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *sql.DB) AuditLogRepository {
+	return &auditLogRepository{db: pgDB{db}}
+}
+
+// Insert appends one entry to the device's chain
+func (r *auditLogRepository) Insert(entry *models.AuditLogEntry) error {
+	query := `
+		INSERT INTO audit_log_nodepath
+			(id_device, prospect_num, sender, content, prev_hash, hash, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query,
+		entry.IDDevice, entry.ProspectNum, entry.Sender, entry.Content,
+		entry.PrevHash, entry.Hash, entry.CreatedAt)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert audit log entry")
+		return err
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		entry.ID = id
+	}
+	return nil
+}
+
+// LatestHash returns the newest entry's hash for a device, or empty when
+// the chain has no entries yet
+func (r *auditLogRepository) LatestHash(deviceID string) (string, error) {
+	query := `
+		SELECT hash
+		FROM audit_log_nodepath
+		WHERE id_device = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	var hash string
+	err := r.db.QueryRow(query, deviceID).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest audit hash: %w", err)
+	}
+	return hash, nil
+}
+
+// ListByDevicePeriod returns a device's entries inside the period, oldest first
+func (r *auditLogRepository) ListByDevicePeriod(deviceID string, start, end time.Time) ([]models.AuditLogEntry, error) {
+	query := `
+		SELECT id, id_device, prospect_num, sender, content, prev_hash, hash, created_at
+		FROM audit_log_nodepath
+		WHERE id_device = ? AND created_at >= ? AND created_at <= ?
+		ORDER BY id ASC
+	`
+	rows, err := r.db.Query(query, deviceID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanEntries(rows)
+}
+
+// ListByDevice returns a device's full chain, oldest first
+func (r *auditLogRepository) ListByDevice(deviceID string) ([]models.AuditLogEntry, error) {
+	query := `
+		SELECT id, id_device, prospect_num, sender, content, prev_hash, hash, created_at
+		FROM audit_log_nodepath
+		WHERE id_device = ?
+		ORDER BY id ASC
+	`
+	rows, err := r.db.Query(query, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanEntries(rows)
+}
+
+func (r *auditLogRepository) scanEntries(rows *sql.Rows) ([]models.AuditLogEntry, error) {
+	var entries []models.AuditLogEntry
+	for rows.Next() {
+		entry := models.AuditLogEntry{}
+		err := rows.Scan(
+			&entry.ID, &entry.IDDevice, &entry.ProspectNum, &entry.Sender,
+			&entry.Content, &entry.PrevHash, &entry.Hash, &entry.CreatedAt)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan audit log entry")
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
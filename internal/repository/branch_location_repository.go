@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BranchLocationRepository handles database operations for branch locations
+type BranchLocationRepository interface {
+	CreateBranchLocation(branch *models.BranchLocation) error
+	GetBranchLocationByID(id string) (*models.BranchLocation, error)
+	GetBranchLocationsByDevice(idDevice string, activeOnly bool) ([]models.BranchLocation, error)
+	UpdateBranchLocation(branch *models.BranchLocation) error
+	DeleteBranchLocation(id string) error
+}
+
+type branchLocationRepository struct {
+	db pgDB
+}
+
+// NewBranchLocationRepository creates a new branch location repository
+func NewBranchLocationRepository(db *sql.DB) BranchLocationRepository {
+	return &branchLocationRepository{db: pgDB{db}}
+}
+
+// CreateBranchLocation inserts a new branch
+func (r *branchLocationRepository) CreateBranchLocation(branch *models.BranchLocation) error {
+	query := `
+		INSERT INTO branch_location_nodepath (id, id_device, name, address, latitude, longitude, phone, branch_device, is_active)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		branch.ID,
+		branch.IDDevice,
+		branch.Name,
+		branch.Address,
+		branch.Latitude,
+		branch.Longitude,
+		branch.Phone,
+		branch.BranchDevice,
+		branch.IsActive,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create branch location")
+		return err
+	}
+
+	return nil
+}
+
+// GetBranchLocationByID returns a single branch, or nil when not found
+func (r *branchLocationRepository) GetBranchLocationByID(id string) (*models.BranchLocation, error) {
+	query := `
+		SELECT id, id_device, name, address, latitude, longitude, phone, branch_device, is_active, created_at, updated_at
+		FROM branch_location_nodepath
+		WHERE id = ?
+	`
+
+	var branch models.BranchLocation
+	err := r.db.QueryRow(query, id).Scan(
+		&branch.ID,
+		&branch.IDDevice,
+		&branch.Name,
+		&branch.Address,
+		&branch.Latitude,
+		&branch.Longitude,
+		&branch.Phone,
+		&branch.BranchDevice,
+		&branch.IsActive,
+		&branch.CreatedAt,
+		&branch.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get branch location")
+		return nil, err
+	}
+
+	return &branch, nil
+}
+
+// GetBranchLocationsByDevice returns a device's branches
+func (r *branchLocationRepository) GetBranchLocationsByDevice(idDevice string, activeOnly bool) ([]models.BranchLocation, error) {
+	query := `
+		SELECT id, id_device, name, address, latitude, longitude, phone, branch_device, is_active, created_at, updated_at
+		FROM branch_location_nodepath
+		WHERE id_device = ?
+	`
+	if activeOnly {
+		query += " AND is_active = TRUE"
+	}
+	query += " ORDER BY name ASC"
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get branch locations")
+		return nil, err
+	}
+	defer rows.Close()
+
+	branches := make([]models.BranchLocation, 0)
+	for rows.Next() {
+		var branch models.BranchLocation
+		if err := rows.Scan(
+			&branch.ID,
+			&branch.IDDevice,
+			&branch.Name,
+			&branch.Address,
+			&branch.Latitude,
+			&branch.Longitude,
+			&branch.Phone,
+			&branch.BranchDevice,
+			&branch.IsActive,
+			&branch.CreatedAt,
+			&branch.UpdatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan branch location row")
+			continue
+		}
+		branches = append(branches, branch)
+	}
+
+	return branches, rows.Err()
+}
+
+// UpdateBranchLocation updates an existing branch
+func (r *branchLocationRepository) UpdateBranchLocation(branch *models.BranchLocation) error {
+	query := `
+		UPDATE branch_location_nodepath
+		SET name = ?, address = ?, latitude = ?, longitude = ?, phone = ?, branch_device = ?, is_active = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query,
+		branch.Name,
+		branch.Address,
+		branch.Latitude,
+		branch.Longitude,
+		branch.Phone,
+		branch.BranchDevice,
+		branch.IsActive,
+		branch.ID,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update branch location")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBranchLocation removes a branch
+func (r *branchLocationRepository) DeleteBranchLocation(id string) error {
+	query := `DELETE FROM branch_location_nodepath WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete branch location")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CartRepository handles database operations for conversation shopping carts
+type CartRepository interface {
+	UpsertCartItem(prospectNum, idDevice, productID string, quantity int) error
+	RemoveCartItem(prospectNum, idDevice, productID string) error
+	GetCartItems(prospectNum, idDevice string) ([]models.CartItem, error)
+	ClearCart(prospectNum, idDevice string) error
+}
+
+type cartRepository struct {
+	db pgDB
+}
+
+// NewCartRepository creates a new cart repository
+func NewCartRepository(db *sql.DB) CartRepository {
+	return &cartRepository{db: pgDB{db}}
+}
+
+// UpsertCartItem adds a product to the cart, accumulating quantity when it is
+// already there
+func (r *cartRepository) UpsertCartItem(prospectNum, idDevice, productID string, quantity int) error {
+	query := `
+		INSERT INTO cart_item_nodepath (prospect_num, id_device, product_id, quantity)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (prospect_num, id_device, product_id) DO UPDATE SET quantity = cart_item_nodepath.quantity + EXCLUDED.quantity
+	`
+
+	_, err := r.db.Exec(query, prospectNum, idDevice, productID, quantity)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to upsert cart item")
+		return err
+	}
+
+	return nil
+}
+
+// RemoveCartItem drops one product from the cart entirely
+func (r *cartRepository) RemoveCartItem(prospectNum, idDevice, productID string) error {
+	query := `
+		DELETE FROM cart_item_nodepath
+		WHERE prospect_num = ? AND id_device = ? AND product_id = ?
+	`
+
+	_, err := r.db.Exec(query, prospectNum, idDevice, productID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove cart item")
+		return err
+	}
+
+	return nil
+}
+
+// GetCartItems returns the cart joined with catalog fields, oldest first
+func (r *cartRepository) GetCartItems(prospectNum, idDevice string) ([]models.CartItem, error) {
+	query := `
+		SELECT c.product_id, p.name, p.sku, p.price, c.quantity
+		FROM cart_item_nodepath c
+		JOIN product_nodepath p ON p.id = c.product_id
+		WHERE c.prospect_num = ? AND c.id_device = ?
+		ORDER BY c.created_at ASC
+	`
+
+	rows, err := r.db.Query(query, prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get cart items")
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.CartItem, 0)
+	for rows.Next() {
+		var item models.CartItem
+		if err := rows.Scan(&item.ProductID, &item.Name, &item.SKU, &item.Price, &item.Quantity); err != nil {
+			logrus.WithError(err).Error("Failed to scan cart item row")
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// ClearCart empties a conversation's cart
+func (r *cartRepository) ClearCart(prospectNum, idDevice string) error {
+	query := `DELETE FROM cart_item_nodepath WHERE prospect_num = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to clear cart")
+		return err
+	}
+
+	return nil
+}
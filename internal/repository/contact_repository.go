@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ContactRepository handles database operations for org-level contacts and
+// their links to device-scoped prospects
+type ContactRepository interface {
+	GetUserIDByDevice(idDevice string) (string, error)
+	GetOrCreateContact(userID, phoneNumber string) (*models.Contact, error)
+	GetContactProspects(userID, phoneNumber string) ([]models.ContactProspect, error)
+	GetUnifiedHistory(userID, phoneNumber string, limit int) ([]models.ConversationLog, error)
+	GetDuplicateProspects(userID string) ([]models.DuplicateProspect, error)
+	GetIdentityMode(userID string) (string, error)
+	SetIdentityMode(userID, mode string) error
+	GetActiveSiblingExecution(userID, phoneNumber, excludeDevice string) (*models.ContactProspect, error)
+}
+
+type contactRepository struct {
+	db pgDB
+}
+
+// NewContactRepository creates a new contact repository
+func NewContactRepository(db *sql.DB) ContactRepository {
+	return &contactRepository{db: pgDB{db}}
+}
+
+// GetUserIDByDevice resolves which user (org) owns a device
+func (r *contactRepository) GetUserIDByDevice(idDevice string) (string, error) {
+	var userID string
+	err := r.db.QueryRow(`SELECT user_id FROM device_setting WHERE id_device = ?`, idDevice).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to resolve device owner")
+		return "", err
+	}
+	return userID, nil
+}
+
+// GetOrCreateContact returns the org-level contact for a phone number,
+// creating it on first sight
+func (r *contactRepository) GetOrCreateContact(userID, phoneNumber string) (*models.Contact, error) {
+	insert := `INSERT INTO contact_nodepath (user_id, phone_number) VALUES (?, ?) ON CONFLICT DO NOTHING`
+	if _, err := r.db.Exec(insert, userID, phoneNumber); err != nil {
+		logrus.WithError(err).Error("Failed to create contact")
+		return nil, err
+	}
+
+	contact := &models.Contact{}
+	query := `SELECT id, user_id, phone_number, created_at FROM contact_nodepath WHERE user_id = ? AND phone_number = ?`
+	err := r.db.QueryRow(query, userID, phoneNumber).Scan(
+		&contact.ID, &contact.UserID, &contact.PhoneNumber, &contact.CreatedAt,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get contact")
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// GetContactProspects returns the latest device-scoped conversation per
+// device for a contact's phone number across the org's devices
+func (r *contactRepository) GetContactProspects(userID, phoneNumber string) ([]models.ContactProspect, error) {
+	query := `
+		SELECT a.id_device, a.prospect_num,
+			COALESCE(a.stage, ''), COALESCE(a.execution_status, ''), COALESCE(a.flow_id, '')
+		FROM ai_whatsapp a
+		JOIN device_setting d ON d.id_device = a.id_device
+		WHERE d.user_id = ? AND a.prospect_num = ?
+		ORDER BY a.id_device ASC, a.id DESC
+	`
+
+	rows, err := r.db.Query(query, userID, phoneNumber)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get contact prospects")
+		return nil, err
+	}
+	defer rows.Close()
+
+	prospects := make([]models.ContactProspect, 0)
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var prospect models.ContactProspect
+		if err := rows.Scan(
+			&prospect.IDDevice, &prospect.ProspectNum,
+			&prospect.Stage, &prospect.ExecutionStatus, &prospect.FlowID,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan contact prospect row")
+			continue
+		}
+		// Keep only the latest conversation per device
+		if seen[prospect.IDDevice] {
+			continue
+		}
+		seen[prospect.IDDevice] = true
+		prospects = append(prospects, prospect)
+	}
+
+	return prospects, rows.Err()
+}
+
+// GetUnifiedHistory returns the contact's conversation logs across every
+// device of the org, newest first
+func (r *contactRepository) GetUnifiedHistory(userID, phoneNumber string, limit int) ([]models.ConversationLog, error) {
+	query := `
+		SELECT c.id, c.prospect_num, c.id_device, c.message, c.sender, c.stage, c.created_at
+		FROM conversation_log c
+		JOIN device_setting d ON d.id_device = c.id_device
+		WHERE d.user_id = ? AND c.prospect_num = ?
+		ORDER BY c.created_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, userID, phoneNumber, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get unified contact history")
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := make([]models.ConversationLog, 0)
+	for rows.Next() {
+		log := models.ConversationLog{}
+		if err := rows.Scan(
+			&log.ID, &log.ProspectNum, &log.IDDevice, &log.Message,
+			&log.Sender, &log.Stage, &log.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan unified history row")
+			continue
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+// GetDuplicateProspects lists phone numbers seen on more than one of the
+// org's devices
+func (r *contactRepository) GetDuplicateProspects(userID string) ([]models.DuplicateProspect, error) {
+	query := `
+		SELECT a.prospect_num, COUNT(DISTINCT a.id_device) AS device_count
+		FROM ai_whatsapp a
+		JOIN device_setting d ON d.id_device = a.id_device
+		WHERE d.user_id = ?
+		GROUP BY a.prospect_num
+		HAVING COUNT(DISTINCT a.id_device) > 1
+		ORDER BY device_count DESC, a.prospect_num ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get duplicate prospects")
+		return nil, err
+	}
+	defer rows.Close()
+
+	duplicates := make([]models.DuplicateProspect, 0)
+	for rows.Next() {
+		var duplicate models.DuplicateProspect
+		if err := rows.Scan(&duplicate.PhoneNumber, &duplicate.DeviceCount); err != nil {
+			logrus.WithError(err).Error("Failed to scan duplicate prospect row")
+			continue
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return duplicates, rows.Err()
+}
+
+// GetIdentityMode returns the org's flow-state sharing mode; isolated when
+// never configured
+func (r *contactRepository) GetIdentityMode(userID string) (string, error) {
+	var mode string
+	err := r.db.QueryRow(`SELECT identity_mode FROM contact_identity_nodepath WHERE user_id = ?`, userID).Scan(&mode)
+	if err == sql.ErrNoRows {
+		return models.ContactIdentityIsolated, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get contact identity mode")
+		return models.ContactIdentityIsolated, err
+	}
+	return mode, nil
+}
+
+// SetIdentityMode saves the org's flow-state sharing mode
+func (r *contactRepository) SetIdentityMode(userID, mode string) error {
+	query := `
+		INSERT INTO contact_identity_nodepath (user_id, identity_mode)
+		VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET identity_mode = EXCLUDED.identity_mode
+	`
+
+	if _, err := r.db.Exec(query, userID, mode); err != nil {
+		logrus.WithError(err).Error("Failed to set contact identity mode")
+		return err
+	}
+
+	return nil
+}
+
+// GetActiveSiblingExecution finds an active flow execution for the phone
+// number on another device of the same org
+func (r *contactRepository) GetActiveSiblingExecution(userID, phoneNumber, excludeDevice string) (*models.ContactProspect, error) {
+	query := `
+		SELECT a.id_device, a.prospect_num,
+			COALESCE(a.stage, ''), COALESCE(a.execution_status, ''), COALESCE(a.flow_id, '')
+		FROM ai_whatsapp a
+		JOIN device_setting d ON d.id_device = a.id_device
+		WHERE d.user_id = ? AND a.prospect_num = ? AND a.id_device != ?
+			AND a.execution_status = 'active'
+		ORDER BY a.id DESC
+		LIMIT 1
+	`
+
+	prospect := &models.ContactProspect{}
+	err := r.db.QueryRow(query, userID, phoneNumber, excludeDevice).Scan(
+		&prospect.IDDevice, &prospect.ProspectNum,
+		&prospect.Stage, &prospect.ExecutionStatus, &prospect.FlowID,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get active sibling execution")
+		return nil, err
+	}
+
+	return prospect, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ConversationEmbeddingRepository stores embedded conversation chunks in
+// conversation_embedding_nodepath. Vectors are kept as JSON arrays; ranking
+// happens in the service since MySQL has no vector distance operator
+type ConversationEmbeddingRepository interface {
+	Upsert(embedding *models.ConversationEmbedding) error
+	GetHashes(deviceID, prospectNum string) (map[int]string, error)
+	ListByDevice(deviceID string) ([]models.ConversationEmbedding, error)
+	DeleteFromChunk(deviceID, prospectNum string, fromChunkIndex int) error
+}
+
+type conversationEmbeddingRepository struct {
+	db pgDB
+}
+
+// NewConversationEmbeddingRepository creates a new conversation embedding repository
+func NewConversationEmbeddingRepository(db *sql.DB) ConversationEmbeddingRepository {
+	return &conversationEmbeddingRepository{db: pgDB{db}}
+}
+
+// Upsert writes one chunk's embedding, replacing the previous vector when
+// the chunk's content changed
+func (r *conversationEmbeddingRepository) Upsert(embedding *models.ConversationEmbedding) error {
+	vectorJSON, err := json.Marshal(embedding.Embedding)
+	if err != nil {
+		return fmt.Errorf("failed to serialize embedding vector: %w", err)
+	}
+
+	query := `
+		INSERT INTO conversation_embedding_nodepath
+			(id_device, prospect_num, chunk_index, content_hash, snippet, embedding, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, NOW())
+		ON CONFLICT (id_device, prospect_num, chunk_index) DO UPDATE SET
+			content_hash = EXCLUDED.content_hash,
+			snippet = EXCLUDED.snippet,
+			embedding = EXCLUDED.embedding,
+			updated_at = NOW()
+	`
+	_, err = r.db.Exec(query,
+		embedding.IDDevice, embedding.ProspectNum, embedding.ChunkIndex,
+		embedding.ContentHash, embedding.Snippet, string(vectorJSON))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to upsert conversation embedding")
+		return err
+	}
+	return nil
+}
+
+// GetHashes returns the stored content hash per chunk index for a
+// conversation, so reindexing can skip unchanged chunks
+func (r *conversationEmbeddingRepository) GetHashes(deviceID, prospectNum string) (map[int]string, error) {
+	query := `
+		SELECT chunk_index, content_hash
+		FROM conversation_embedding_nodepath
+		WHERE id_device = ? AND prospect_num = ?
+	`
+	rows, err := r.db.Query(query, deviceID, prospectNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get embedding hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[int]string)
+	for rows.Next() {
+		var chunkIndex int
+		var hash string
+		if err := rows.Scan(&chunkIndex, &hash); err != nil {
+			logrus.WithError(err).Error("Failed to scan embedding hash")
+			continue
+		}
+		hashes[chunkIndex] = hash
+	}
+	return hashes, nil
+}
+
+// ListByDevice returns every embedded chunk on a device with its vector
+func (r *conversationEmbeddingRepository) ListByDevice(deviceID string) ([]models.ConversationEmbedding, error) {
+	query := `
+		SELECT id, id_device, prospect_num, chunk_index, content_hash, snippet, embedding, updated_at
+		FROM conversation_embedding_nodepath
+		WHERE id_device = ?
+	`
+	rows, err := r.db.Query(query, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversation embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var embeddings []models.ConversationEmbedding
+	for rows.Next() {
+		embedding := models.ConversationEmbedding{}
+		var vectorJSON string
+		err := rows.Scan(
+			&embedding.ID, &embedding.IDDevice, &embedding.ProspectNum,
+			&embedding.ChunkIndex, &embedding.ContentHash, &embedding.Snippet,
+			&vectorJSON, &embedding.UpdatedAt)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan conversation embedding")
+			continue
+		}
+		if err := json.Unmarshal([]byte(vectorJSON), &embedding.Embedding); err != nil {
+			logrus.WithError(err).Error("Failed to parse stored embedding vector")
+			continue
+		}
+		embeddings = append(embeddings, embedding)
+	}
+	return embeddings, nil
+}
+
+// DeleteFromChunk removes chunks at or past the index, for conversations
+// that shrank since the last reindex
+func (r *conversationEmbeddingRepository) DeleteFromChunk(deviceID, prospectNum string, fromChunkIndex int) error {
+	query := `
+		DELETE FROM conversation_embedding_nodepath
+		WHERE id_device = ? AND prospect_num = ? AND chunk_index >= ?
+	`
+	_, err := r.db.Exec(query, deviceID, prospectNum, fromChunkIndex)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete stale conversation embeddings")
+		return err
+	}
+	return nil
+}
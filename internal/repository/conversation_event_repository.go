@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ConversationEventRepository persists the append-only conversation event log.
+// Rows are only ever inserted; seq is assigned atomically per conversation
+type ConversationEventRepository interface {
+	// Append inserts one event with the next sequence number and returns it
+	Append(prospectNum, idDevice, eventType, detail string) (int, error)
+
+	// List returns events for a conversation with seq greater than afterSeq,
+	// in sequence order
+	List(prospectNum, idDevice string, afterSeq, limit int) ([]models.ConversationEvent, error)
+}
+
+type conversationEventRepository struct {
+	db pgDB
+}
+
+// NewConversationEventRepository creates a new conversation event repository
+func NewConversationEventRepository(db *sql.DB) ConversationEventRepository {
+	return &conversationEventRepository{db: pgDB{db}}
+}
+
+// appendRetries bounds how often Append re-runs after losing a seq race to a
+// concurrent writer for the same conversation
+const appendRetries = 3
+
+// Append inserts one event, assigning the next per-conversation sequence
+// number in the same statement and returning the inserted row's seq. Two
+// writers can still compute the same MAX(seq)+1; the UNIQUE constraint turns
+// the loser into a unique-violation error, which is retried with a fresh
+// sequence number instead of dropping the event
+func (r *conversationEventRepository) Append(prospectNum, idDevice, eventType, detail string) (int, error) {
+	insertQuery := `
+		INSERT INTO conversation_event (prospect_num, id_device, seq, event_type, detail)
+		SELECT ?, ?, COALESCE(MAX(seq), 0) + 1, ?, ?
+		FROM conversation_event
+		WHERE prospect_num = ? AND id_device = ?
+		RETURNING seq
+	`
+
+	var err error
+	for attempt := 0; attempt <= appendRetries; attempt++ {
+		var seq int
+		err = r.db.QueryRow(insertQuery, prospectNum, idDevice, eventType, detail, prospectNum, idDevice).Scan(&seq)
+		if err == nil {
+			return seq, nil
+		}
+		if !isUniqueViolationError(err) {
+			break
+		}
+	}
+	return 0, fmt.Errorf("failed to append conversation event: %w", err)
+}
+
+// isUniqueViolationError reports whether err is PostgreSQL unique_violation
+func isUniqueViolationError(err error) bool {
+	if pqErr, ok := err.(*pq.Error); ok {
+		return pqErr.Code == "23505"
+	}
+	return false
+}
+
+// List returns events after the given sequence number in sequence order
+func (r *conversationEventRepository) List(prospectNum, idDevice string, afterSeq, limit int) ([]models.ConversationEvent, error) {
+	query := `
+		SELECT id, prospect_num, id_device, seq, event_type, COALESCE(detail, ''), created_at
+		FROM conversation_event
+		WHERE prospect_num = ? AND id_device = ? AND seq > ?
+		ORDER BY seq ASC
+		LIMIT ?
+	`
+	rows, err := r.db.Query(query, prospectNum, idDevice, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversation events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.ConversationEvent
+	for rows.Next() {
+		event := models.ConversationEvent{}
+		if err := rows.Scan(&event.ID, &event.ProspectNum, &event.IDDevice, &event.Seq, &event.EventType, &event.Detail, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
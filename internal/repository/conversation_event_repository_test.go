@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// The stub driver below stands in for PostgreSQL: it fails the first insert
+// attempt of every Append with a unique-violation (as if a concurrent writer
+// claimed the same seq) and assigns a fresh seq on the retry. That drives
+// Append through the exact code path a real seq race takes
+
+type appendRaceState struct {
+	mu              sync.Mutex
+	nextSeq         int64
+	attempted       map[string]bool
+	failuresIssued  int
+	sawPlaceholder  bool
+	unexpectedQuery string
+}
+
+func (s *appendRaceState) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.Contains(query, "?") {
+		s.sawPlaceholder = true
+	}
+	if !strings.Contains(query, "INSERT INTO conversation_event") {
+		s.unexpectedQuery = query
+		return nil, errors.New("unexpected query")
+	}
+
+	// args: prospect_num, id_device, event_type, detail, prospect_num, id_device
+	detail, _ := args[3].Value.(string)
+	if !s.attempted[detail] {
+		s.attempted[detail] = true
+		s.failuresIssued++
+		return nil, &pq.Error{Code: "23505"}
+	}
+
+	s.nextSeq++
+	return &seqRows{seq: s.nextSeq}, nil
+}
+
+type seqRows struct {
+	seq  int64
+	done bool
+}
+
+func (r *seqRows) Columns() []string { return []string{"seq"} }
+func (r *seqRows) Close() error      { return nil }
+func (r *seqRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.seq
+	return nil
+}
+
+type appendRaceConn struct {
+	state *appendRaceState
+}
+
+func (c *appendRaceConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c *appendRaceConn) Close() error              { return nil }
+func (c *appendRaceConn) Begin() (driver.Tx, error) { return nil, errors.New("tx not supported") }
+func (c *appendRaceConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.state.query(query, args)
+}
+
+type appendRaceDriver struct{}
+
+func (appendRaceDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("open through the connector")
+}
+
+type appendRaceConnector struct {
+	state *appendRaceState
+}
+
+func (c appendRaceConnector) Connect(context.Context) (driver.Conn, error) {
+	return &appendRaceConn{state: c.state}, nil
+}
+func (c appendRaceConnector) Driver() driver.Driver { return appendRaceDriver{} }
+
+// TestConversationEventAppendRetriesOnSeqRace runs concurrent Appends where
+// every writer loses its first seq to a simulated concurrent insert. Each
+// Append must retry past the unique violation and come back with the seq of
+// the row it actually inserted
+func TestConversationEventAppendRetriesOnSeqRace(t *testing.T) {
+	state := &appendRaceState{attempted: make(map[string]bool)}
+	db := sql.OpenDB(appendRaceConnector{state: state})
+	defer db.Close()
+
+	repo := NewConversationEventRepository(db)
+
+	const writers = 8
+	var wg sync.WaitGroup
+	seqs := make([]int, writers)
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			seqs[i], errs[i] = repo.Append("60123456789", "device-1", "stage_change", fmt.Sprintf("writer-%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	if state.unexpectedQuery != "" {
+		t.Fatalf("driver saw unexpected query: %s", state.unexpectedQuery)
+	}
+	if state.sawPlaceholder {
+		t.Error("insert reached the driver with unbound ? placeholders")
+	}
+
+	seen := make(map[int]bool, writers)
+	for i := 0; i < writers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("writer %d: Append returned error: %v", i, errs[i])
+		}
+		if seqs[i] <= 0 {
+			t.Fatalf("writer %d: Append returned seq %d, want > 0", i, seqs[i])
+		}
+		if seen[seqs[i]] {
+			t.Fatalf("writer %d: seq %d returned twice", i, seqs[i])
+		}
+		seen[seqs[i]] = true
+	}
+
+	if state.failuresIssued != writers {
+		t.Errorf("driver issued %d unique violations, want %d (one per writer)", state.failuresIssued, writers)
+	}
+}
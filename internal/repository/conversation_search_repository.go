@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ConversationSearchRepository runs full-text searches over conversation
+// content. Conversations live in ai_whatsapp.conv_last (one "USER:"/"BOT:"
+// line per message), so the search is a tsquery match over that column with
+// ts_rank relevance ranking. When the full-text query cannot run (e.g. the
+// input does not parse as a tsquery) the search degrades to a LIKE scan so
+// it still answers, just without ranking
+type ConversationSearchRepository interface {
+	Search(deviceID, query string, limit int) ([]models.ConversationSearchRow, error)
+}
+
+type conversationSearchRepository struct {
+	db pgDB
+}
+
+// NewConversationSearchRepository creates a new conversation search repository
+func NewConversationSearchRepository(db *sql.DB) ConversationSearchRepository {
+	return &conversationSearchRepository{db: pgDB{db}}
+}
+
+// Search returns conversations matching the query, best match first.
+// Results are always scoped to one device
+func (r *conversationSearchRepository) Search(deviceID, query string, limit int) ([]models.ConversationSearchRow, error) {
+	fulltextQuery := `
+		SELECT prospect_num, prospect_name, stage, conv_last, updated_at,
+		       ts_rank(to_tsvector('simple', COALESCE(conv_last, '')), plainto_tsquery('simple', ?)) AS score
+		FROM ai_whatsapp
+		WHERE id_device = ?
+		  AND to_tsvector('simple', COALESCE(conv_last, '')) @@ plainto_tsquery('simple', ?)
+		ORDER BY score DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(fulltextQuery, query, deviceID, query, limit)
+	if err != nil {
+		// A tsquery syntax error means the input could not be parsed as a
+		// full-text query - degrade to a LIKE scan instead of failing the
+		// search outright
+		if strings.Contains(err.Error(), "tsquery") {
+			logrus.Warn("🔍 SEARCH: Query not usable as tsquery, falling back to LIKE scan")
+			return r.searchLike(deviceID, query, limit)
+		}
+		logrus.WithError(err).Error("Failed to run conversation full-text search")
+		return nil, fmt.Errorf("failed to search conversations: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanRows(rows, true)
+}
+
+// searchLike answers the query without a FULLTEXT index: substring match,
+// newest conversation first, score 0
+func (r *conversationSearchRepository) searchLike(deviceID, query string, limit int) ([]models.ConversationSearchRow, error) {
+	likeQuery := `
+		SELECT prospect_num, prospect_name, stage, conv_last, updated_at
+		FROM ai_whatsapp
+		WHERE id_device = ?
+		  AND conv_last LIKE CONCAT('%', ?, '%')
+		ORDER BY updated_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(likeQuery, deviceID, query, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to run conversation LIKE search")
+		return nil, fmt.Errorf("failed to search conversations: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanRows(rows, false)
+}
+
+// scanRows reads search rows, with or without the relevance score column
+func (r *conversationSearchRepository) scanRows(rows *sql.Rows, withScore bool) ([]models.ConversationSearchRow, error) {
+	var results []models.ConversationSearchRow
+	for rows.Next() {
+		row := models.ConversationSearchRow{}
+		var err error
+		if withScore {
+			err = rows.Scan(&row.ProspectNum, &row.ProspectName, &row.Stage, &row.ConvLast, &row.UpdatedAt, &row.Score)
+		} else {
+			err = rows.Scan(&row.ProspectNum, &row.ProspectName, &row.Stage, &row.ConvLast, &row.UpdatedAt)
+		}
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan conversation search row")
+			continue
+		}
+		results = append(results, row)
+	}
+	return results, nil
+}
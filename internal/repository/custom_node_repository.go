@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CustomNodeRepository handles database operations for webhook-backed custom
+// node types
+type CustomNodeRepository interface {
+	CreateCustomNodeType(nodeType *models.CustomNodeType) error
+	GetCustomNodeTypesByDevice(idDevice string) ([]models.CustomNodeType, error)
+	GetEnabledCustomNodeType(idDevice, typeName string) (*models.CustomNodeType, error)
+	SetCustomNodeTypeEnabled(id, idDevice string, enabled bool) error
+	DeleteCustomNodeType(id, idDevice string) error
+}
+
+type customNodeRepository struct {
+	db pgDB
+}
+
+// NewCustomNodeRepository creates a new custom node repository
+func NewCustomNodeRepository(db *sql.DB) CustomNodeRepository {
+	return &customNodeRepository{db: pgDB{db}}
+}
+
+// CreateCustomNodeType inserts a new registered node type
+func (r *customNodeRepository) CreateCustomNodeType(nodeType *models.CustomNodeType) error {
+	query := `
+		INSERT INTO custom_node_type_nodepath (id, id_device, type_name, endpoint_url, secret, timeout_seconds, enabled)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		nodeType.ID,
+		nodeType.IDDevice,
+		nodeType.TypeName,
+		nodeType.EndpointURL,
+		nodeType.Secret,
+		nodeType.TimeoutSeconds,
+		nodeType.Enabled,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create custom node type")
+		return err
+	}
+
+	return nil
+}
+
+// GetCustomNodeTypesByDevice returns all of a device's registered node types
+func (r *customNodeRepository) GetCustomNodeTypesByDevice(idDevice string) ([]models.CustomNodeType, error) {
+	query := `
+		SELECT id, id_device, type_name, endpoint_url, secret, timeout_seconds, enabled, created_at
+		FROM custom_node_type_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get custom node types")
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodeTypes := make([]models.CustomNodeType, 0)
+	for rows.Next() {
+		var nodeType models.CustomNodeType
+		if err := rows.Scan(
+			&nodeType.ID,
+			&nodeType.IDDevice,
+			&nodeType.TypeName,
+			&nodeType.EndpointURL,
+			&nodeType.Secret,
+			&nodeType.TimeoutSeconds,
+			&nodeType.Enabled,
+			&nodeType.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan custom node type row")
+			continue
+		}
+		nodeTypes = append(nodeTypes, nodeType)
+	}
+
+	return nodeTypes, rows.Err()
+}
+
+// GetEnabledCustomNodeType returns the device's enabled registration for a
+// type name, or nil when none exists
+func (r *customNodeRepository) GetEnabledCustomNodeType(idDevice, typeName string) (*models.CustomNodeType, error) {
+	query := `
+		SELECT id, id_device, type_name, endpoint_url, secret, timeout_seconds, enabled, created_at
+		FROM custom_node_type_nodepath
+		WHERE id_device = ? AND type_name = ? AND enabled = TRUE
+	`
+
+	var nodeType models.CustomNodeType
+	err := r.db.QueryRow(query, idDevice, typeName).Scan(
+		&nodeType.ID,
+		&nodeType.IDDevice,
+		&nodeType.TypeName,
+		&nodeType.EndpointURL,
+		&nodeType.Secret,
+		&nodeType.TimeoutSeconds,
+		&nodeType.Enabled,
+		&nodeType.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get custom node type")
+		return nil, err
+	}
+
+	return &nodeType, nil
+}
+
+// SetCustomNodeTypeEnabled toggles a registration, scoped to the owning device
+func (r *customNodeRepository) SetCustomNodeTypeEnabled(id, idDevice string, enabled bool) error {
+	query := `UPDATE custom_node_type_nodepath SET enabled = ? WHERE id = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, enabled, id, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update custom node type")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCustomNodeType removes a registration, scoped to the owning device
+func (r *customNodeRepository) DeleteCustomNodeType(id, idDevice string) error {
+	query := `DELETE FROM custom_node_type_nodepath WHERE id = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, id, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete custom node type")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DigestReportRepository handles database operations for stored digest
+// reports and the aggregate queries they are built from
+type DigestReportRepository interface {
+	CreateDigestReport(report *models.DigestReport) error
+	GetDigestReportsByDevice(idDevice string, limit int) ([]models.DigestReport, error)
+	GetActiveDeviceIDs() ([]string, error)
+	CountNewProspects(idDevice string, from, to time.Time) (int64, error)
+	GetStageMovement(idDevice string, from, to time.Time) (map[string]int64, error)
+	GetOwnerEmail(idDevice string) (string, error)
+}
+
+type digestReportRepository struct {
+	db pgDB
+}
+
+// NewDigestReportRepository creates a new digest report repository
+func NewDigestReportRepository(db *sql.DB) DigestReportRepository {
+	return &digestReportRepository{db: pgDB{db}}
+}
+
+// CreateDigestReport inserts a generated digest
+func (r *digestReportRepository) CreateDigestReport(report *models.DigestReport) error {
+	query := `
+		INSERT INTO digest_report_nodepath (id, id_device, period, period_start, period_end, report)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		report.ID,
+		report.IDDevice,
+		report.Period,
+		report.PeriodStart,
+		report.PeriodEnd,
+		string(report.Report),
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create digest report")
+		return err
+	}
+
+	return nil
+}
+
+// GetDigestReportsByDevice returns a device's stored digests, newest first
+func (r *digestReportRepository) GetDigestReportsByDevice(idDevice string, limit int) ([]models.DigestReport, error) {
+	query := `
+		SELECT id, id_device, period, period_start, period_end, report, created_at
+		FROM digest_report_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, idDevice, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get digest reports")
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]models.DigestReport, 0)
+	for rows.Next() {
+		var report models.DigestReport
+		var reportJSON string
+		if err := rows.Scan(
+			&report.ID,
+			&report.IDDevice,
+			&report.Period,
+			&report.PeriodStart,
+			&report.PeriodEnd,
+			&reportJSON,
+			&report.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan digest report row")
+			continue
+		}
+		report.Report = []byte(reportJSON)
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+// GetActiveDeviceIDs returns every device ID with settings, for scheduled
+// digest runs
+func (r *digestReportRepository) GetActiveDeviceIDs() ([]string, error) {
+	query := `
+		SELECT DISTINCT id_device
+		FROM device_setting
+		WHERE id_device IS NOT NULL AND id_device != ''
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get device IDs for digest run")
+		return nil, err
+	}
+	defer rows.Close()
+
+	deviceIDs := make([]string, 0)
+	for rows.Next() {
+		var idDevice string
+		if err := rows.Scan(&idDevice); err != nil {
+			continue
+		}
+		deviceIDs = append(deviceIDs, idDevice)
+	}
+
+	return deviceIDs, rows.Err()
+}
+
+// CountNewProspects counts conversations first seen in the period
+func (r *digestReportRepository) CountNewProspects(idDevice string, from, to time.Time) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM ai_whatsapp
+		WHERE id_device = ? AND created_at >= ? AND created_at < ?
+	`
+
+	var count int64
+	if err := r.db.QueryRow(query, idDevice, from, to).Scan(&count); err != nil {
+		logrus.WithError(err).Error("Failed to count new prospects")
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetStageMovement counts prospects per stage among conversations that moved
+// during the period
+func (r *digestReportRepository) GetStageMovement(idDevice string, from, to time.Time) (map[string]int64, error) {
+	query := `
+		SELECT COALESCE(stage, ''), COUNT(*)
+		FROM ai_whatsapp
+		WHERE id_device = ? AND updated_at >= ? AND updated_at < ?
+		GROUP BY stage
+	`
+
+	rows, err := r.db.Query(query, idDevice, from, to)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get stage movement")
+		return nil, err
+	}
+	defer rows.Close()
+
+	stages := make(map[string]int64)
+	for rows.Next() {
+		var stage string
+		var count int64
+		if err := rows.Scan(&stage, &count); err != nil {
+			continue
+		}
+		if stage == "" {
+			stage = "(no stage)"
+		}
+		stages[stage] = count
+	}
+
+	return stages, rows.Err()
+}
+
+// GetOwnerEmail resolves the email of the user owning a device
+func (r *digestReportRepository) GetOwnerEmail(idDevice string) (string, error) {
+	query := `
+		SELECT u.email
+		FROM device_setting d
+		JOIN users u ON u.id = d.user_id
+		WHERE d.id_device = ?
+		LIMIT 1
+	`
+
+	var email string
+	err := r.db.QueryRow(query, idDevice).Scan(&email)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get device owner email")
+		return "", err
+	}
+	return email, nil
+}
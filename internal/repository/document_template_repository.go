@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DocumentTemplateRepository handles database operations for PDF document
+// templates. Lines are stored as a JSON blob alongside the template metadata
+type DocumentTemplateRepository interface {
+	Insert(template *models.DocumentTemplate) error
+	GetByID(id int64) (*models.DocumentTemplate, error)
+	GetByName(userID, name string) (*models.DocumentTemplate, error)
+	ListByUser(userID string) ([]models.DocumentTemplate, error)
+	Delete(id int64, userID string) error
+}
+
+type documentTemplateRepository struct {
+	db pgDB
+}
+
+// NewDocumentTemplateRepository creates a new document template repository
+func NewDocumentTemplateRepository(db *sql.DB) DocumentTemplateRepository {
+	return &documentTemplateRepository{db: pgDB{db}}
+}
+
+// Insert stores a new document template
+func (r *documentTemplateRepository) Insert(template *models.DocumentTemplate) error {
+	lines, err := json.Marshal(template.Lines)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRow(`
+		INSERT INTO document_template_nodepath (user_id, name, title, lines, created_at, updated_at)
+		VALUES (?, ?, ?, ?, NOW(), NOW())
+		RETURNING id
+	`, template.UserID, template.Name, template.Title, string(lines)).Scan(&template.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert document template")
+		return err
+	}
+	return nil
+}
+
+// GetByID returns one template, nil when it does not exist
+func (r *documentTemplateRepository) GetByID(id int64) (*models.DocumentTemplate, error) {
+	return r.scanOne(r.db.QueryRow(`
+		SELECT id, user_id, name, title, lines, created_at, updated_at
+		FROM document_template_nodepath
+		WHERE id = ?
+	`, id))
+}
+
+// GetByName returns a user's template by name, nil when it does not exist
+func (r *documentTemplateRepository) GetByName(userID, name string) (*models.DocumentTemplate, error) {
+	return r.scanOne(r.db.QueryRow(`
+		SELECT id, user_id, name, title, lines, created_at, updated_at
+		FROM document_template_nodepath
+		WHERE user_id = ? AND name = ?
+	`, userID, name))
+}
+
+// ListByUser returns all templates owned by a user
+func (r *documentTemplateRepository) ListByUser(userID string) ([]models.DocumentTemplate, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, name, title, lines, created_at, updated_at
+		FROM document_template_nodepath
+		WHERE user_id = ?
+		ORDER BY name
+	`, userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list document templates")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.DocumentTemplate
+	for rows.Next() {
+		var template models.DocumentTemplate
+		var lines string
+		if err := rows.Scan(&template.ID, &template.UserID, &template.Name, &template.Title,
+			&lines, &template.CreatedAt, &template.UpdatedAt); err != nil {
+			logrus.WithError(err).Error("Failed to scan document template")
+			continue
+		}
+		if err := json.Unmarshal([]byte(lines), &template.Lines); err != nil {
+			logrus.WithError(err).Error("Failed to parse document template lines")
+			continue
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// Delete removes a template; the user scope stops cross-account deletes
+func (r *documentTemplateRepository) Delete(id int64, userID string) error {
+	_, err := r.db.Exec(`DELETE FROM document_template_nodepath WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete document template")
+	}
+	return err
+}
+
+// scanOne reads one template row, returning nil on sql.ErrNoRows
+func (r *documentTemplateRepository) scanOne(row *sql.Row) (*models.DocumentTemplate, error) {
+	template := &models.DocumentTemplate{}
+	var lines string
+	err := row.Scan(&template.ID, &template.UserID, &template.Name, &template.Title,
+		&lines, &template.CreatedAt, &template.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get document template")
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(lines), &template.Lines); err != nil {
+		logrus.WithError(err).Error("Failed to parse document template lines")
+		return nil, err
+	}
+	return template, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FailedMessageRepository handles database operations for the webhook
+// dead-letter queue
+type FailedMessageRepository interface {
+	Insert(message *models.FailedMessage) error
+	GetByID(id int64) (*models.FailedMessage, error)
+	ListByDevice(deviceID string, limit int) ([]models.FailedMessage, error)
+	Delete(id int64) error
+}
+
+type failedMessageRepository struct {
+	db pgDB
+}
+
+// NewFailedMessageRepository creates a new failed message repository
+func NewFailedMessageRepository(db *sql.DB) FailedMessageRepository {
+	return &failedMessageRepository{db: pgDB{db}}
+}
+
+// Insert dead-letters one webhook message
+func (r *failedMessageRepository) Insert(message *models.FailedMessage) error {
+	query := `
+		INSERT INTO failed_message_nodepath
+			(idempotency_key, device_id, prospect_num, content, provider, sender_name, last_error, retries, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW())
+		RETURNING id
+	`
+	err := r.db.QueryRow(query,
+		message.IdempotencyKey, message.DeviceID, message.ProspectNum,
+		message.Content, message.Provider, message.SenderName,
+		message.LastError, message.Retries).Scan(&message.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert dead-lettered message")
+		return err
+	}
+	return nil
+}
+
+// GetByID returns one dead-lettered message
+func (r *failedMessageRepository) GetByID(id int64) (*models.FailedMessage, error) {
+	query := `
+		SELECT id, idempotency_key, device_id, prospect_num, content, provider, sender_name, last_error, retries, created_at
+		FROM failed_message_nodepath
+		WHERE id = ?
+	`
+	message := &models.FailedMessage{}
+	err := r.db.QueryRow(query, id).Scan(
+		&message.ID, &message.IdempotencyKey, &message.DeviceID, &message.ProspectNum,
+		&message.Content, &message.Provider, &message.SenderName,
+		&message.LastError, &message.Retries, &message.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get dead-lettered message")
+		return nil, err
+	}
+	return message, nil
+}
+
+// ListByDevice returns the newest dead-lettered messages for a device
+func (r *failedMessageRepository) ListByDevice(deviceID string, limit int) ([]models.FailedMessage, error) {
+	query := `
+		SELECT id, idempotency_key, device_id, prospect_num, content, provider, sender_name, last_error, retries, created_at
+		FROM failed_message_nodepath
+		WHERE device_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+	rows, err := r.db.Query(query, deviceID, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list dead-lettered messages")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []models.FailedMessage
+	for rows.Next() {
+		var message models.FailedMessage
+		if err := rows.Scan(
+			&message.ID, &message.IdempotencyKey, &message.DeviceID, &message.ProspectNum,
+			&message.Content, &message.Provider, &message.SenderName,
+			&message.LastError, &message.Retries, &message.CreatedAt); err != nil {
+			logrus.WithError(err).Error("Failed to scan dead-lettered message")
+			continue
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// Delete removes a dead-lettered message, after a successful requeue or an
+// operator discard
+func (r *failedMessageRepository) Delete(id int64) error {
+	_, err := r.db.Exec(`DELETE FROM failed_message_nodepath WHERE id = ?`, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete dead-lettered message")
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlowOrderRepository handles database operations for orders captured in flows
+type FlowOrderRepository interface {
+	CreateFlowOrder(order *models.FlowOrder) error
+	GetFlowOrderByID(id string) (*models.FlowOrder, error)
+	GetFlowOrdersByDevice(idDevice string, status string) ([]models.FlowOrder, error)
+	UpdateFlowOrderStatus(id string, status models.OrderStatus) error
+}
+
+type flowOrderRepository struct {
+	db pgDB
+}
+
+// NewFlowOrderRepository creates a new flow order repository
+func NewFlowOrderRepository(db *sql.DB) FlowOrderRepository {
+	return &flowOrderRepository{db: pgDB{db}}
+}
+
+// CreateFlowOrder persists a new order with its line snapshot
+func (r *flowOrderRepository) CreateFlowOrder(order *models.FlowOrder) error {
+	itemsJSON, err := json.Marshal(order.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO flow_order_nodepath (id, prospect_num, id_device, status, total, items_json)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.Exec(query,
+		order.ID,
+		order.ProspectNum,
+		order.IDDevice,
+		string(order.Status),
+		order.Total,
+		string(itemsJSON),
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create flow order")
+		return err
+	}
+
+	return nil
+}
+
+// GetFlowOrderByID returns a single order, or nil when not found
+func (r *flowOrderRepository) GetFlowOrderByID(id string) (*models.FlowOrder, error) {
+	query := `
+		SELECT id, prospect_num, id_device, status, total, items_json, created_at, updated_at
+		FROM flow_order_nodepath
+		WHERE id = ?
+	`
+
+	order, err := scanFlowOrder(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get flow order")
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetFlowOrdersByDevice returns a device's orders, optionally filtered by status
+func (r *flowOrderRepository) GetFlowOrdersByDevice(idDevice string, status string) ([]models.FlowOrder, error) {
+	query := `
+		SELECT id, prospect_num, id_device, status, total, items_json, created_at, updated_at
+		FROM flow_order_nodepath
+		WHERE id_device = ?
+	`
+	args := []interface{}{idDevice}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get flow orders")
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.FlowOrder, 0)
+	for rows.Next() {
+		order, err := scanFlowOrder(rows)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan flow order row")
+			continue
+		}
+		orders = append(orders, *order)
+	}
+
+	return orders, rows.Err()
+}
+
+// UpdateFlowOrderStatus moves an order to a new lifecycle status
+func (r *flowOrderRepository) UpdateFlowOrderStatus(id string, status models.OrderStatus) error {
+	query := `UPDATE flow_order_nodepath SET status = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, string(status), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update flow order status")
+		return err
+	}
+
+	return nil
+}
+
+func scanFlowOrder(row rowScanner) (*models.FlowOrder, error) {
+	var order models.FlowOrder
+	var itemsJSON string
+	if err := row.Scan(
+		&order.ID,
+		&order.ProspectNum,
+		&order.IDDevice,
+		&order.Status,
+		&order.Total,
+		&itemsJSON,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(itemsJSON), &order.Items); err != nil {
+		logrus.WithError(err).WithField("order_id", order.ID).Warn("Failed to parse order items JSON")
+		order.Items = []models.OrderLine{}
+	}
+
+	return &order, nil
+}
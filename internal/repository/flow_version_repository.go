@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlowVersionRepository handles database operations for published flow
+// snapshots and the per-execution version pins
+type FlowVersionRepository interface {
+	CreateVersion(version *models.FlowVersion) error
+	GetVersion(flowID string, version int) (*models.FlowVersion, error)
+	ListVersions(flowID string) ([]models.FlowVersion, error)
+	LatestVersion(flowID string) (int, error)
+	PinExecution(prospectNum, idDevice, flowID string, version int) error
+	GetPin(prospectNum, idDevice, flowID string) (int, error)
+	ClearPin(prospectNum, idDevice string) error
+}
+
+type flowVersionRepository struct {
+	db pgDB
+}
+
+// NewFlowVersionRepository creates a new flow version repository
+func NewFlowVersionRepository(db *sql.DB) FlowVersionRepository {
+	return &flowVersionRepository{db: pgDB{db}}
+}
+
+// CreateVersion stores a snapshot as the flow's next version number
+func (r *flowVersionRepository) CreateVersion(version *models.FlowVersion) error {
+	query := `
+		INSERT INTO flow_version_nodepath (flow_id, version, nodes, edges, created_at)
+		SELECT ?, COALESCE(MAX(version), 0) + 1, ?, ?, NOW()
+		FROM flow_version_nodepath WHERE flow_id = ?
+		RETURNING id, version
+	`
+	err := r.db.QueryRow(query, version.FlowID, rawJSONArg(version.Nodes), rawJSONArg(version.Edges), version.FlowID).
+		Scan(&version.ID, &version.Version)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create flow version")
+		return err
+	}
+	return nil
+}
+
+// GetVersion returns one snapshot with its payload, nil when it does not exist
+func (r *flowVersionRepository) GetVersion(flowID string, version int) (*models.FlowVersion, error) {
+	flowVersion := &models.FlowVersion{}
+	err := r.db.QueryRow(`
+		SELECT id, flow_id, version, nodes, edges, created_at
+		FROM flow_version_nodepath
+		WHERE flow_id = ? AND version = ?
+	`, flowID, version).Scan(
+		&flowVersion.ID, &flowVersion.FlowID, &flowVersion.Version,
+		&flowVersion.Nodes, &flowVersion.Edges, &flowVersion.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get flow version")
+		return nil, err
+	}
+	return flowVersion, nil
+}
+
+// ListVersions returns a flow's version history, newest first, without the
+// node and edge payloads
+func (r *flowVersionRepository) ListVersions(flowID string) ([]models.FlowVersion, error) {
+	rows, err := r.db.Query(`
+		SELECT id, flow_id, version, created_at
+		FROM flow_version_nodepath
+		WHERE flow_id = ?
+		ORDER BY version DESC
+	`, flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list flow versions")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.FlowVersion
+	for rows.Next() {
+		var version models.FlowVersion
+		if err := rows.Scan(&version.ID, &version.FlowID, &version.Version, &version.CreatedAt); err != nil {
+			logrus.WithError(err).Error("Failed to scan flow version")
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// LatestVersion returns the newest published version number, 0 when the
+// flow has never been published
+func (r *flowVersionRepository) LatestVersion(flowID string) (int, error) {
+	var latest int
+	err := r.db.QueryRow(`
+		SELECT COALESCE(MAX(version), 0) FROM flow_version_nodepath WHERE flow_id = ?
+	`, flowID).Scan(&latest)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get latest flow version")
+		return 0, err
+	}
+	return latest, nil
+}
+
+// PinExecution records which published version a conversation runs on
+func (r *flowVersionRepository) PinExecution(prospectNum, idDevice, flowID string, version int) error {
+	query := `
+		INSERT INTO execution_flow_pin_nodepath (prospect_num, id_device, flow_id, version, created_at)
+		VALUES (?, ?, ?, ?, NOW())
+		ON CONFLICT (prospect_num, id_device) DO UPDATE SET flow_id = EXCLUDED.flow_id, version = EXCLUDED.version, created_at = NOW()
+	`
+	_, err := r.db.Exec(query, prospectNum, idDevice, flowID, version)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to pin execution flow version")
+	}
+	return err
+}
+
+// GetPin returns the pinned version for a conversation, 0 when unpinned
+func (r *flowVersionRepository) GetPin(prospectNum, idDevice, flowID string) (int, error) {
+	var version int
+	err := r.db.QueryRow(`
+		SELECT version FROM execution_flow_pin_nodepath
+		WHERE prospect_num = ? AND id_device = ? AND flow_id = ?
+	`, prospectNum, idDevice, flowID).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get execution flow pin")
+		return 0, err
+	}
+	return version, nil
+}
+
+// ClearPin removes a conversation's pin, e.g. when its execution completes
+func (r *flowVersionRepository) ClearPin(prospectNum, idDevice string) error {
+	_, err := r.db.Exec(`
+		DELETE FROM execution_flow_pin_nodepath WHERE prospect_num = ? AND id_device = ?
+	`, prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to clear execution flow pin")
+	}
+	return err
+}
+
+// rawJSONArg converts an optional raw JSON payload to a driver argument
+func rawJSONArg(raw *json.RawMessage) interface{} {
+	if raw == nil || len(*raw) == 0 {
+		return nil
+	}
+	return string(*raw)
+}
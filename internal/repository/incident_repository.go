@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IncidentRepository handles database operations for status page incidents
+type IncidentRepository interface {
+	CreateIncident(incident *models.Incident) error
+	UpdateIncident(id int, status, message string) error
+	GetOpenIncidents() ([]models.Incident, error)
+	GetIncidents(limit int) ([]models.Incident, error)
+}
+
+type incidentRepository struct {
+	db pgDB
+}
+
+// NewIncidentRepository creates a new incident repository
+func NewIncidentRepository(db *sql.DB) IncidentRepository {
+	return &incidentRepository{db: pgDB{db}}
+}
+
+// CreateIncident inserts a new incident
+func (r *incidentRepository) CreateIncident(incident *models.Incident) error {
+	query := `
+		INSERT INTO incident_nodepath (title, severity, status, message)
+		VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, incident.Title, incident.Severity, incident.Status, incident.Message)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create incident")
+		return err
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		incident.ID = int(id)
+	}
+
+	return nil
+}
+
+// UpdateIncident changes an incident's status and latest message
+func (r *incidentRepository) UpdateIncident(id int, status, message string) error {
+	query := `UPDATE incident_nodepath SET status = ?, message = ?, updated_at = NOW() WHERE id = ?`
+
+	result, err := r.db.Exec(query, status, message, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update incident")
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetOpenIncidents returns unresolved incidents, newest first
+func (r *incidentRepository) GetOpenIncidents() ([]models.Incident, error) {
+	return r.queryIncidents(`
+		SELECT id, title, severity, status, message, created_at, updated_at
+		FROM incident_nodepath
+		WHERE status != ?
+		ORDER BY created_at DESC
+	`, models.IncidentStatusResolved)
+}
+
+// GetIncidents returns recent incidents including resolved ones, newest first
+func (r *incidentRepository) GetIncidents(limit int) ([]models.Incident, error) {
+	return r.queryIncidents(`
+		SELECT id, title, severity, status, message, created_at, updated_at
+		FROM incident_nodepath
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, limit)
+}
+
+func (r *incidentRepository) queryIncidents(query string, args ...interface{}) ([]models.Incident, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get incidents")
+		return nil, err
+	}
+	defer rows.Close()
+
+	incidents := make([]models.Incident, 0)
+	for rows.Next() {
+		var incident models.Incident
+		if err := rows.Scan(
+			&incident.ID, &incident.Title, &incident.Severity,
+			&incident.Status, &incident.Message,
+			&incident.CreatedAt, &incident.UpdatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan incident row")
+			continue
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
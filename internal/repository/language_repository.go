@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LanguageRepository handles database operations for per-conversation
+// language preferences, the per-flow auto-translate toggle and the machine
+// translation cache
+type LanguageRepository interface {
+	GetLanguage(prospectNum, idDevice string) (string, error)
+	UpsertLanguage(prospectNum, idDevice, language, source string) error
+	GetAutoTranslate(flowID string) (bool, error)
+	SetAutoTranslate(flowID string, enabled bool) error
+	GetCachedTranslation(nodeID, language, sourceHash string) (string, error)
+	UpsertCachedTranslation(translation *models.MachineTranslation) error
+	GetMachineTranslationsByFlow(flowID string) ([]models.MachineTranslation, error)
+	LogMachineTranslationUse(prospectNum, idDevice, flowID, nodeID, language string) error
+	GetMachineTranslationLog(prospectNum, idDevice string) ([]models.MachineTranslationUse, error)
+	GetDeviceAPIKey(idDevice string) (string, error)
+}
+
+type languageRepository struct {
+	db pgDB
+}
+
+// NewLanguageRepository creates a new language repository
+func NewLanguageRepository(db *sql.DB) LanguageRepository {
+	return &languageRepository{db: pgDB{db}}
+}
+
+// GetLanguage returns the stored language for a conversation, empty when none
+// has been detected or set yet
+func (r *languageRepository) GetLanguage(prospectNum, idDevice string) (string, error) {
+	var language string
+	err := r.db.QueryRow(`
+		SELECT language FROM prospect_language_nodepath
+		WHERE prospect_num = ? AND id_device = ?
+	`, prospectNum, idDevice).Scan(&language)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get prospect language")
+		return "", err
+	}
+	return language, nil
+}
+
+// UpsertLanguage stores or updates the conversation's language
+func (r *languageRepository) UpsertLanguage(prospectNum, idDevice, language, source string) error {
+	query := `
+		INSERT INTO prospect_language_nodepath (prospect_num, id_device, language, source, updated_at)
+		VALUES (?, ?, ?, ?, NOW())
+		ON CONFLICT (prospect_num, id_device) DO UPDATE SET language = EXCLUDED.language, source = EXCLUDED.source, updated_at = NOW()
+	`
+
+	_, err := r.db.Exec(query, prospectNum, idDevice, language, source)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to upsert prospect language")
+	}
+	return err
+}
+
+// GetAutoTranslate returns whether machine translation fallback is enabled
+// for the flow, defaulting to disabled
+func (r *languageRepository) GetAutoTranslate(flowID string) (bool, error) {
+	var enabled bool
+	err := r.db.QueryRow(`SELECT enabled FROM flow_auto_translate_nodepath WHERE flow_id = ?`, flowID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get auto-translate setting")
+		return false, err
+	}
+	return enabled, nil
+}
+
+// SetAutoTranslate stores the flow's machine translation fallback toggle
+func (r *languageRepository) SetAutoTranslate(flowID string, enabled bool) error {
+	query := `
+		INSERT INTO flow_auto_translate_nodepath (flow_id, enabled)
+		VALUES (?, ?)
+		ON CONFLICT (flow_id) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	_, err := r.db.Exec(query, flowID, enabled)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set auto-translate setting")
+	}
+	return err
+}
+
+// GetCachedTranslation returns the cached machine translation for the node's
+// content in the language, empty when none exists. A hit bumps the usage
+// counters so stale entries are distinguishable from active ones
+func (r *languageRepository) GetCachedTranslation(nodeID, language, sourceHash string) (string, error) {
+	var translated string
+	err := r.db.QueryRow(`
+		SELECT translated_text FROM machine_translation_nodepath
+		WHERE node_id = ? AND language = ? AND source_hash = ?
+	`, nodeID, language, sourceHash).Scan(&translated)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get cached translation")
+		return "", err
+	}
+
+	if _, err := r.db.Exec(`
+		UPDATE machine_translation_nodepath
+		SET use_count = use_count + 1, last_used_at = NOW()
+		WHERE node_id = ? AND language = ? AND source_hash = ?
+	`, nodeID, language, sourceHash); err != nil {
+		logrus.WithError(err).Warn("Failed to bump translation use count")
+	}
+
+	return translated, nil
+}
+
+// UpsertCachedTranslation stores one machine translation. Re-translations of
+// changed source content insert new rows because the hash differs
+func (r *languageRepository) UpsertCachedTranslation(translation *models.MachineTranslation) error {
+	query := `
+		INSERT INTO machine_translation_nodepath (flow_id, node_id, language, source_hash, source_text, translated_text, use_count, last_used_at)
+		VALUES (?, ?, ?, ?, ?, ?, 1, NOW())
+		ON CONFLICT (flow_id, node_id, language, source_hash) DO UPDATE SET translated_text = EXCLUDED.translated_text, use_count = machine_translation_nodepath.use_count + 1, last_used_at = NOW()
+	`
+
+	_, err := r.db.Exec(query,
+		translation.FlowID, translation.NodeID, translation.Language,
+		translation.SourceHash, translation.SourceText, translation.TranslatedText,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to upsert cached translation")
+	}
+	return err
+}
+
+// GetMachineTranslationsByFlow returns the flow's cached machine translations
+// so they can be reviewed and promoted into node translations
+func (r *languageRepository) GetMachineTranslationsByFlow(flowID string) ([]models.MachineTranslation, error) {
+	query := `
+		SELECT id, flow_id, node_id, language, source_hash, source_text, translated_text, use_count, created_at, last_used_at
+		FROM machine_translation_nodepath
+		WHERE flow_id = ?
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := r.db.Query(query, flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get machine translations")
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make([]models.MachineTranslation, 0)
+	for rows.Next() {
+		var translation models.MachineTranslation
+		err := rows.Scan(
+			&translation.ID, &translation.FlowID, &translation.NodeID, &translation.Language,
+			&translation.SourceHash, &translation.SourceText, &translation.TranslatedText,
+			&translation.UseCount, &translation.CreatedAt, &translation.LastUsedAt,
+		)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan machine translation row")
+			continue
+		}
+		translations = append(translations, translation)
+	}
+
+	return translations, rows.Err()
+}
+
+// LogMachineTranslationUse flags that a machine-translated message was
+// delivered in a conversation
+func (r *languageRepository) LogMachineTranslationUse(prospectNum, idDevice, flowID, nodeID, language string) error {
+	query := `
+		INSERT INTO machine_translation_log_nodepath (prospect_num, id_device, flow_id, node_id, language)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, prospectNum, idDevice, flowID, nodeID, language)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to log machine translation use")
+	}
+	return err
+}
+
+// GetMachineTranslationLog returns the conversation's machine-translated
+// deliveries, newest first, so the conversation view can flag them
+func (r *languageRepository) GetMachineTranslationLog(prospectNum, idDevice string) ([]models.MachineTranslationUse, error) {
+	query := `
+		SELECT id, prospect_num, id_device, flow_id, node_id, language, created_at
+		FROM machine_translation_log_nodepath
+		WHERE prospect_num = ? AND id_device = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get machine translation log")
+		return nil, err
+	}
+	defer rows.Close()
+
+	uses := make([]models.MachineTranslationUse, 0)
+	for rows.Next() {
+		var use models.MachineTranslationUse
+		err := rows.Scan(
+			&use.ID, &use.ProspectNum, &use.IDDevice, &use.FlowID,
+			&use.NodeID, &use.Language, &use.CreatedAt,
+		)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan machine translation log row")
+			continue
+		}
+		uses = append(uses, use)
+	}
+
+	return uses, rows.Err()
+}
+
+// GetDeviceAPIKey returns the AI API key configured for a device, used for
+// the translation model call
+func (r *languageRepository) GetDeviceAPIKey(idDevice string) (string, error) {
+	var apiKey sql.NullString
+	err := r.db.QueryRow(`SELECT api_key FROM device_setting WHERE id_device = ?`, idDevice).Scan(&apiKey)
+	if err != nil {
+		return "", err
+	}
+	return apiKey.String, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LeadScoringRepository handles database operations for lead scoring
+// configuration and prospect scores
+type LeadScoringRepository interface {
+	GetLeadScoringConfig(idDevice string) (*models.LeadScoringConfig, error)
+	SetLeadScoringConfig(config *models.LeadScoringConfig) error
+	GetLeadScore(prospectNum, idDevice string) (int, error)
+	AddLeadScore(prospectNum, idDevice string, delta int) (int, error)
+}
+
+type leadScoringRepository struct {
+	db pgDB
+}
+
+// NewLeadScoringRepository creates a new lead scoring repository
+func NewLeadScoringRepository(db *sql.DB) LeadScoringRepository {
+	return &leadScoringRepository{db: pgDB{db}}
+}
+
+// GetLeadScoringConfig returns a device's scoring rules, or nil when never set
+func (r *leadScoringRepository) GetLeadScoringConfig(idDevice string) (*models.LeadScoringConfig, error) {
+	query := `SELECT enabled, config FROM lead_scoring_nodepath WHERE id_device = ?`
+
+	var enabled bool
+	var configJSON string
+	err := r.db.QueryRow(query, idDevice).Scan(&enabled, &configJSON)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get lead scoring config")
+		return nil, err
+	}
+
+	config := &models.LeadScoringConfig{}
+	if err := json.Unmarshal([]byte(configJSON), config); err != nil {
+		logrus.WithError(err).Error("Failed to decode lead scoring config")
+		return nil, err
+	}
+	config.IDDevice = idDevice
+	config.Enabled = enabled
+
+	return config, nil
+}
+
+// SetLeadScoringConfig saves a device's scoring rules
+func (r *leadScoringRepository) SetLeadScoringConfig(config *models.LeadScoringConfig) error {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO lead_scoring_nodepath (id_device, enabled, config)
+		VALUES (?, ?, ?)
+		ON CONFLICT (id_device) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			config = EXCLUDED.config
+	`
+
+	_, err = r.db.Exec(query, config.IDDevice, config.Enabled, string(configJSON))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set lead scoring config")
+		return err
+	}
+
+	return nil
+}
+
+// GetLeadScore returns a prospect's current score, zero when never scored
+func (r *leadScoringRepository) GetLeadScore(prospectNum, idDevice string) (int, error) {
+	query := `SELECT score FROM lead_score_nodepath WHERE prospect_num = ? AND id_device = ?`
+
+	var score int
+	err := r.db.QueryRow(query, prospectNum, idDevice).Scan(&score)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get lead score")
+		return 0, err
+	}
+
+	return score, nil
+}
+
+// AddLeadScore adds delta to a prospect's score and returns the new value
+func (r *leadScoringRepository) AddLeadScore(prospectNum, idDevice string, delta int) (int, error) {
+	query := `
+		INSERT INTO lead_score_nodepath (prospect_num, id_device, score)
+		VALUES (?, ?, ?)
+		ON CONFLICT (prospect_num, id_device) DO UPDATE SET score = lead_score_nodepath.score + EXCLUDED.score
+	`
+
+	if _, err := r.db.Exec(query, prospectNum, idDevice, delta); err != nil {
+		logrus.WithError(err).Error("Failed to add lead score")
+		return 0, err
+	}
+
+	return r.GetLeadScore(prospectNum, idDevice)
+}
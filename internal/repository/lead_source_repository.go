@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LeadSourceRepository handles database operations for lead intake sources
+type LeadSourceRepository interface {
+	CreateLeadSource(source *models.LeadSource) error
+	GetLeadSourceByToken(verifyToken string) (*models.LeadSource, error)
+	GetLeadSourcesByDevice(idDevice string) ([]models.LeadSource, error)
+	IncrementLeadCount(id string) error
+	DeleteLeadSource(id, idDevice string) error
+}
+
+type leadSourceRepository struct {
+	db pgDB
+}
+
+// NewLeadSourceRepository creates a new lead source repository
+func NewLeadSourceRepository(db *sql.DB) LeadSourceRepository {
+	return &leadSourceRepository{db: pgDB{db}}
+}
+
+// CreateLeadSource inserts a new lead source
+func (r *leadSourceRepository) CreateLeadSource(source *models.LeadSource) error {
+	query := `
+		INSERT INTO lead_source_nodepath (id, id_device, name, source_type, flow_id, template_message, verify_token)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		source.ID,
+		source.IDDevice,
+		source.Name,
+		source.SourceType,
+		source.FlowID,
+		source.TemplateMessage,
+		source.VerifyToken,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create lead source")
+		return err
+	}
+
+	return nil
+}
+
+// GetLeadSourceByToken returns the source behind an intake token, or nil when
+// not found
+func (r *leadSourceRepository) GetLeadSourceByToken(verifyToken string) (*models.LeadSource, error) {
+	query := `
+		SELECT id, id_device, name, source_type, flow_id, template_message, verify_token, lead_count, created_at
+		FROM lead_source_nodepath
+		WHERE verify_token = ?
+	`
+
+	var source models.LeadSource
+	err := r.db.QueryRow(query, verifyToken).Scan(
+		&source.ID,
+		&source.IDDevice,
+		&source.Name,
+		&source.SourceType,
+		&source.FlowID,
+		&source.TemplateMessage,
+		&source.VerifyToken,
+		&source.LeadCount,
+		&source.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get lead source")
+		return nil, err
+	}
+
+	return &source, nil
+}
+
+// GetLeadSourcesByDevice returns a device's lead sources, newest first
+func (r *leadSourceRepository) GetLeadSourcesByDevice(idDevice string) ([]models.LeadSource, error) {
+	query := `
+		SELECT id, id_device, name, source_type, flow_id, template_message, verify_token, lead_count, created_at
+		FROM lead_source_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get lead sources")
+		return nil, err
+	}
+	defer rows.Close()
+
+	sources := make([]models.LeadSource, 0)
+	for rows.Next() {
+		var source models.LeadSource
+		if err := rows.Scan(
+			&source.ID,
+			&source.IDDevice,
+			&source.Name,
+			&source.SourceType,
+			&source.FlowID,
+			&source.TemplateMessage,
+			&source.VerifyToken,
+			&source.LeadCount,
+			&source.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan lead source row")
+			continue
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, rows.Err()
+}
+
+// IncrementLeadCount counts one lead received through a source
+func (r *leadSourceRepository) IncrementLeadCount(id string) error {
+	query := `UPDATE lead_source_nodepath SET lead_count = lead_count + 1 WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to increment lead count")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteLeadSource removes a lead source, scoped to the owning device
+func (r *leadSourceRepository) DeleteLeadSource(id, idDevice string) error {
+	query := `DELETE FROM lead_source_nodepath WHERE id = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, id, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete lead source")
+		return err
+	}
+
+	return nil
+}
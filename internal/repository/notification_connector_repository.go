@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationConnectorRepository handles database operations for Slack and
+// Discord notification connectors
+type NotificationConnectorRepository interface {
+	CreateNotificationConnector(connector *models.NotificationConnector) error
+	GetNotificationConnectorsByDevice(idDevice string) ([]models.NotificationConnector, error)
+	GetEnabledNotificationConnectors(idDevice string) ([]models.NotificationConnector, error)
+	SetNotificationConnectorEnabled(id, idDevice string, enabled bool) error
+	DeleteNotificationConnector(id, idDevice string) error
+}
+
+type notificationConnectorRepository struct {
+	db pgDB
+}
+
+// NewNotificationConnectorRepository creates a new notification connector repository
+func NewNotificationConnectorRepository(db *sql.DB) NotificationConnectorRepository {
+	return &notificationConnectorRepository{db: pgDB{db}}
+}
+
+// CreateNotificationConnector inserts a new connector
+func (r *notificationConnectorRepository) CreateNotificationConnector(connector *models.NotificationConnector) error {
+	query := `
+		INSERT INTO notification_connector_nodepath (id, id_device, platform, webhook_url, events, enabled)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		connector.ID,
+		connector.IDDevice,
+		connector.Platform,
+		connector.WebhookURL,
+		connector.Events,
+		connector.Enabled,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create notification connector")
+		return err
+	}
+
+	return nil
+}
+
+// GetNotificationConnectorsByDevice returns all of a device's connectors
+func (r *notificationConnectorRepository) GetNotificationConnectorsByDevice(idDevice string) ([]models.NotificationConnector, error) {
+	return r.queryConnectors(`
+		SELECT id, id_device, platform, webhook_url, events, enabled, created_at
+		FROM notification_connector_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+	`, idDevice)
+}
+
+// GetEnabledNotificationConnectors returns the device's enabled connectors
+func (r *notificationConnectorRepository) GetEnabledNotificationConnectors(idDevice string) ([]models.NotificationConnector, error) {
+	return r.queryConnectors(`
+		SELECT id, id_device, platform, webhook_url, events, enabled, created_at
+		FROM notification_connector_nodepath
+		WHERE id_device = ? AND enabled = TRUE
+	`, idDevice)
+}
+
+func (r *notificationConnectorRepository) queryConnectors(query string, args ...interface{}) ([]models.NotificationConnector, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get notification connectors")
+		return nil, err
+	}
+	defer rows.Close()
+
+	connectors := make([]models.NotificationConnector, 0)
+	for rows.Next() {
+		var connector models.NotificationConnector
+		if err := rows.Scan(
+			&connector.ID,
+			&connector.IDDevice,
+			&connector.Platform,
+			&connector.WebhookURL,
+			&connector.Events,
+			&connector.Enabled,
+			&connector.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan notification connector row")
+			continue
+		}
+		connectors = append(connectors, connector)
+	}
+
+	return connectors, rows.Err()
+}
+
+// SetNotificationConnectorEnabled toggles a connector, scoped to the owning device
+func (r *notificationConnectorRepository) SetNotificationConnectorEnabled(id, idDevice string, enabled bool) error {
+	query := `UPDATE notification_connector_nodepath SET enabled = ? WHERE id = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, enabled, id, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to toggle notification connector")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteNotificationConnector removes a connector, scoped to the owning device
+func (r *notificationConnectorRepository) DeleteNotificationConnector(id, idDevice string) error {
+	query := `DELETE FROM notification_connector_nodepath WHERE id = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, id, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete notification connector")
+		return err
+	}
+
+	return nil
+}
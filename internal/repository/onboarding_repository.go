@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OnboardingRepository handles database operations for onboarding progress.
+// Steps the data already proves (a device exists, a flow exists) are derived
+// from the live tables; the rest are explicit marks in onboarding_nodepath
+type OnboardingRepository interface {
+	HasDevice(userID string) (bool, error)
+	HasProvider(userID string) (bool, error)
+	HasFlow(userID string) (bool, error)
+	GetFirstDeviceID(userID string) (string, error)
+	MarkStep(userID, step string) error
+	GetMarkedSteps(userID string) (map[string]bool, error)
+}
+
+type onboardingRepository struct {
+	db pgDB
+}
+
+// NewOnboardingRepository creates a new onboarding repository
+func NewOnboardingRepository(db *sql.DB) OnboardingRepository {
+	return &onboardingRepository{db: pgDB{db}}
+}
+
+func (r *onboardingRepository) exists(query string, args ...interface{}) (bool, error) {
+	var count int
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
+		logrus.WithError(err).Error("Failed to check onboarding state")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// HasDevice reports whether the user created at least one device
+func (r *onboardingRepository) HasDevice(userID string) (bool, error) {
+	return r.exists(`SELECT COUNT(*) FROM device_setting WHERE user_id = ?`, userID)
+}
+
+// HasProvider reports whether any of the user's devices has a provider and
+// API key configured
+func (r *onboardingRepository) HasProvider(userID string) (bool, error) {
+	return r.exists(`
+		SELECT COUNT(*) FROM device_setting
+		WHERE user_id = ? AND provider != '' AND api_key IS NOT NULL AND api_key != ''
+	`, userID)
+}
+
+// HasFlow reports whether the user created at least one flow
+func (r *onboardingRepository) HasFlow(userID string) (bool, error) {
+	return r.exists(`
+		SELECT COUNT(*) FROM chatbot_flows
+		WHERE id_device IN (SELECT id_device FROM device_setting WHERE user_id = ?)
+	`, userID)
+}
+
+// GetFirstDeviceID returns the user's oldest device, or "" when none exists
+func (r *onboardingRepository) GetFirstDeviceID(userID string) (string, error) {
+	var idDevice string
+	err := r.db.QueryRow(
+		`SELECT id_device FROM device_setting WHERE user_id = ? ORDER BY created_at ASC LIMIT 1`,
+		userID,
+	).Scan(&idDevice)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get first device")
+		return "", err
+	}
+	return idDevice, nil
+}
+
+// MarkStep records one completed wizard step; marking twice is a no-op
+func (r *onboardingRepository) MarkStep(userID, step string) error {
+	query := `INSERT INTO onboarding_nodepath (user_id, step) VALUES (?, ?) ON CONFLICT DO NOTHING`
+
+	if _, err := r.db.Exec(query, userID, step); err != nil {
+		logrus.WithError(err).Error("Failed to mark onboarding step")
+		return err
+	}
+
+	return nil
+}
+
+// GetMarkedSteps returns the steps the user explicitly completed
+func (r *onboardingRepository) GetMarkedSteps(userID string) (map[string]bool, error) {
+	rows, err := r.db.Query(`SELECT step FROM onboarding_nodepath WHERE user_id = ?`, userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get onboarding steps")
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := make(map[string]bool)
+	for rows.Next() {
+		var step string
+		if err := rows.Scan(&step); err != nil {
+			continue
+		}
+		steps[step] = true
+	}
+
+	return steps, rows.Err()
+}
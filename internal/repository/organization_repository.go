@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OrganizationRepository stores organizations and their memberships in
+// organization_nodepath and organization_member_nodepath
+type OrganizationRepository interface {
+	CreateOrganization(org *models.Organization) error
+	GetOrganization(id string) (*models.Organization, error)
+	ListOrganizationsByUser(userID string) ([]*models.Organization, error)
+	AddMember(orgID, userID, role string) error
+	RemoveMember(orgID, userID string) error
+	GetMemberRole(orgID, userID string) (string, error)
+	ListMembers(orgID string) ([]*models.OrganizationMember, error)
+	ListSharedRoles(actorID, ownerID string) ([]string, error)
+	ListTeamUserIDs(userID string) ([]string, error)
+}
+
+type organizationRepository struct {
+	db pgDB
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(db *sql.DB) OrganizationRepository {
+	return &organizationRepository{db: pgDB{db}}
+}
+
+// CreateOrganization inserts a new organization
+func (r *organizationRepository) CreateOrganization(org *models.Organization) error {
+	query := `
+		INSERT INTO organization_nodepath (id, name, owner_id, created_at)
+		VALUES (?, ?, ?, NOW())
+	`
+	_, err := r.db.Exec(query, org.ID, org.Name, org.OwnerID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create organization")
+		return err
+	}
+	return nil
+}
+
+// GetOrganization returns the organization, or nil when none exists
+func (r *organizationRepository) GetOrganization(id string) (*models.Organization, error) {
+	query := `SELECT id, name, owner_id, created_at FROM organization_nodepath WHERE id = ?`
+	org := &models.Organization{}
+	err := r.db.QueryRow(query, id).Scan(&org.ID, &org.Name, &org.OwnerID, &org.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return org, nil
+}
+
+// ListOrganizationsByUser returns the organizations the user belongs to
+func (r *organizationRepository) ListOrganizationsByUser(userID string) ([]*models.Organization, error) {
+	query := `
+		SELECT o.id, o.name, o.owner_id, o.created_at
+		FROM organization_nodepath o
+		JOIN organization_member_nodepath m ON m.org_id = o.id
+		WHERE m.user_id = ?
+		ORDER BY o.created_at ASC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer rows.Close()
+
+	var orgs []*models.Organization
+	for rows.Next() {
+		org := &models.Organization{}
+		if err := rows.Scan(&org.ID, &org.Name, &org.OwnerID, &org.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, rows.Err()
+}
+
+// AddMember adds a user to an organization, replacing the role when the
+// membership already exists
+func (r *organizationRepository) AddMember(orgID, userID, role string) error {
+	query := `
+		INSERT INTO organization_member_nodepath (org_id, user_id, role, created_at)
+		VALUES (?, ?, ?, NOW())
+		ON CONFLICT (org_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`
+	_, err := r.db.Exec(query, orgID, userID, role)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add organization member")
+		return err
+	}
+	return nil
+}
+
+// RemoveMember removes a user from an organization
+func (r *organizationRepository) RemoveMember(orgID, userID string) error {
+	query := `DELETE FROM organization_member_nodepath WHERE org_id = ? AND user_id = ?`
+	_, err := r.db.Exec(query, orgID, userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove organization member")
+		return err
+	}
+	return nil
+}
+
+// GetMemberRole returns the user's role in the organization, or empty when
+// the user is not a member
+func (r *organizationRepository) GetMemberRole(orgID, userID string) (string, error) {
+	query := `SELECT role FROM organization_member_nodepath WHERE org_id = ? AND user_id = ?`
+	var role string
+	err := r.db.QueryRow(query, orgID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get member role: %w", err)
+	}
+	return role, nil
+}
+
+// ListMembers returns the organization's memberships
+func (r *organizationRepository) ListMembers(orgID string) ([]*models.OrganizationMember, error) {
+	query := `
+		SELECT org_id, user_id, role, created_at
+		FROM organization_member_nodepath
+		WHERE org_id = ?
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*models.OrganizationMember
+	for rows.Next() {
+		member := &models.OrganizationMember{}
+		if err := rows.Scan(&member.OrgID, &member.UserID, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// ListSharedRoles returns the actor's roles in every organization the
+// resource owner also belongs to
+func (r *organizationRepository) ListSharedRoles(actorID, ownerID string) ([]string, error) {
+	query := `
+		SELECT m.role
+		FROM organization_member_nodepath m
+		JOIN organization_member_nodepath o ON o.org_id = m.org_id
+		WHERE m.user_id = ? AND o.user_id = ?
+	`
+	rows, err := r.db.Query(query, actorID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared roles: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to scan shared role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// ListTeamUserIDs returns the distinct user IDs sharing at least one
+// organization with the user, including the user themselves
+func (r *organizationRepository) ListTeamUserIDs(userID string) ([]string, error) {
+	query := `
+		SELECT DISTINCT o.user_id
+		FROM organization_member_nodepath m
+		JOIN organization_member_nodepath o ON o.org_id = m.org_id
+		WHERE m.user_id = ?
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team user IDs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{userID}
+	seen := map[string]bool{userID: true}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan team user ID: %w", err)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OutboundPolicyRepository handles database operations for opt-out lists and
+// quiet hours
+type OutboundPolicyRepository interface {
+	IsOptedOut(idDevice, prospectNum string) (bool, error)
+	AddOptOut(idDevice, prospectNum string) error
+	RemoveOptOut(idDevice, prospectNum string) error
+	GetOptOuts(idDevice string) ([]models.OptOut, error)
+	GetQuietHours(idDevice string) (*models.QuietHours, error)
+	SetQuietHours(quietHours *models.QuietHours) error
+}
+
+type outboundPolicyRepository struct {
+	db pgDB
+}
+
+// NewOutboundPolicyRepository creates a new outbound policy repository
+func NewOutboundPolicyRepository(db *sql.DB) OutboundPolicyRepository {
+	return &outboundPolicyRepository{db: pgDB{db}}
+}
+
+// IsOptedOut reports whether a number is on the device's opt-out list
+func (r *outboundPolicyRepository) IsOptedOut(idDevice, prospectNum string) (bool, error) {
+	query := `SELECT 1 FROM opt_out_nodepath WHERE id_device = ? AND prospect_num = ?`
+
+	var one int
+	err := r.db.QueryRow(query, idDevice, prospectNum).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to check opt-out")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// AddOptOut puts a number on the device's opt-out list
+func (r *outboundPolicyRepository) AddOptOut(idDevice, prospectNum string) error {
+	query := `INSERT INTO opt_out_nodepath (id_device, prospect_num) VALUES (?, ?) ON CONFLICT DO NOTHING`
+
+	_, err := r.db.Exec(query, idDevice, prospectNum)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add opt-out")
+		return err
+	}
+
+	return nil
+}
+
+// RemoveOptOut takes a number off the device's opt-out list
+func (r *outboundPolicyRepository) RemoveOptOut(idDevice, prospectNum string) error {
+	query := `DELETE FROM opt_out_nodepath WHERE id_device = ? AND prospect_num = ?`
+
+	_, err := r.db.Exec(query, idDevice, prospectNum)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to remove opt-out")
+		return err
+	}
+
+	return nil
+}
+
+// GetOptOuts returns the device's opt-out list, newest first
+func (r *outboundPolicyRepository) GetOptOuts(idDevice string) ([]models.OptOut, error) {
+	query := `
+		SELECT id_device, prospect_num, created_at
+		FROM opt_out_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get opt-outs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	optOuts := make([]models.OptOut, 0)
+	for rows.Next() {
+		var optOut models.OptOut
+		if err := rows.Scan(&optOut.IDDevice, &optOut.ProspectNum, &optOut.CreatedAt); err != nil {
+			logrus.WithError(err).Error("Failed to scan opt-out row")
+			continue
+		}
+		optOuts = append(optOuts, optOut)
+	}
+
+	return optOuts, rows.Err()
+}
+
+// GetQuietHours returns the device's quiet hours, or nil when never configured
+func (r *outboundPolicyRepository) GetQuietHours(idDevice string) (*models.QuietHours, error) {
+	query := `SELECT id_device, enabled, start_hour, end_hour FROM quiet_hour_nodepath WHERE id_device = ?`
+
+	var quietHours models.QuietHours
+	err := r.db.QueryRow(query, idDevice).Scan(
+		&quietHours.IDDevice,
+		&quietHours.Enabled,
+		&quietHours.StartHour,
+		&quietHours.EndHour,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get quiet hours")
+		return nil, err
+	}
+
+	return &quietHours, nil
+}
+
+// SetQuietHours saves the device's quiet hours
+func (r *outboundPolicyRepository) SetQuietHours(quietHours *models.QuietHours) error {
+	query := `
+		INSERT INTO quiet_hour_nodepath (id_device, enabled, start_hour, end_hour)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (id_device) DO UPDATE SET enabled = EXCLUDED.enabled, start_hour = EXCLUDED.start_hour, end_hour = EXCLUDED.end_hour
+	`
+
+	_, err := r.db.Exec(query, quietHours.IDDevice, quietHours.Enabled, quietHours.StartHour, quietHours.EndHour)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set quiet hours")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProductRepository handles database operations for the product catalog
+type ProductRepository interface {
+	CreateProduct(product *models.Product) error
+	GetProductByID(id string) (*models.Product, error)
+	GetProductsByDevice(idDevice string, activeOnly bool) ([]models.Product, error)
+	UpdateProduct(product *models.Product) error
+	DeleteProduct(id string) error
+}
+
+type productRepository struct {
+	db pgDB
+}
+
+// NewProductRepository creates a new product repository
+func NewProductRepository(db *sql.DB) ProductRepository {
+	return &productRepository{db: pgDB{db}}
+}
+
+// CreateProduct inserts a new catalog product
+func (r *productRepository) CreateProduct(product *models.Product) error {
+	query := `
+		INSERT INTO product_nodepath (id, id_device, name, description, image_url, price, sku, is_active)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		product.ID,
+		product.IDDevice,
+		product.Name,
+		product.Description,
+		product.ImageURL,
+		product.Price,
+		product.SKU,
+		product.IsActive,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create product")
+		return err
+	}
+
+	return nil
+}
+
+// GetProductByID returns a single product, or nil when not found
+func (r *productRepository) GetProductByID(id string) (*models.Product, error) {
+	query := `
+		SELECT id, id_device, name, description, image_url, price, sku, is_active, created_at, updated_at
+		FROM product_nodepath
+		WHERE id = ?
+	`
+
+	var product models.Product
+	err := r.db.QueryRow(query, id).Scan(
+		&product.ID,
+		&product.IDDevice,
+		&product.Name,
+		&product.Description,
+		&product.ImageURL,
+		&product.Price,
+		&product.SKU,
+		&product.IsActive,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get product")
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// GetProductsByDevice returns a device's products ordered by name
+func (r *productRepository) GetProductsByDevice(idDevice string, activeOnly bool) ([]models.Product, error) {
+	query := `
+		SELECT id, id_device, name, description, image_url, price, sku, is_active, created_at, updated_at
+		FROM product_nodepath
+		WHERE id_device = ?
+	`
+	if activeOnly {
+		query += " AND is_active = TRUE"
+	}
+	query += " ORDER BY name ASC"
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get products for device")
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.Product, 0)
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(
+			&product.ID,
+			&product.IDDevice,
+			&product.Name,
+			&product.Description,
+			&product.ImageURL,
+			&product.Price,
+			&product.SKU,
+			&product.IsActive,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan product row")
+			continue
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+// UpdateProduct updates an existing product's editable fields
+func (r *productRepository) UpdateProduct(product *models.Product) error {
+	query := `
+		UPDATE product_nodepath
+		SET name = ?, description = ?, image_url = ?, price = ?, sku = ?, is_active = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query,
+		product.Name,
+		product.Description,
+		product.ImageURL,
+		product.Price,
+		product.SKU,
+		product.IsActive,
+		product.ID,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update product")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteProduct removes a product from the catalog
+func (r *productRepository) DeleteProduct(id string) error {
+	query := `DELETE FROM product_nodepath WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete product")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PromptFeedbackRepository stores the diffs between AI-proposed and
+// agent-edited messages used for prompt improvement
+type PromptFeedbackRepository interface {
+	InsertEdit(edit *models.RecordedEdit) error
+	GetEditsByFlow(flowID string, limit int) ([]models.RecordedEdit, error)
+}
+
+type promptFeedbackRepository struct {
+	db pgDB
+}
+
+// NewPromptFeedbackRepository creates a new prompt feedback repository
+func NewPromptFeedbackRepository(db *sql.DB) PromptFeedbackRepository {
+	return &promptFeedbackRepository{db: pgDB{db}}
+}
+
+// InsertEdit records one AI-versus-agent message diff
+func (r *promptFeedbackRepository) InsertEdit(edit *models.RecordedEdit) error {
+	query := `
+		INSERT INTO message_edit_nodepath (flow_id, node_id, id_device, prospect_num, source, original, edited, edited_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(query,
+		edit.FlowID, edit.NodeID, edit.IDDevice, edit.ProspectNum,
+		edit.Source, edit.Original, edit.Edited, edit.EditedBy,
+	).Scan(&edit.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert message edit")
+		return err
+	}
+
+	return nil
+}
+
+// GetEditsByFlow returns the flow's captured edits, newest first
+func (r *promptFeedbackRepository) GetEditsByFlow(flowID string, limit int) ([]models.RecordedEdit, error) {
+	query := `
+		SELECT id, flow_id, node_id, id_device, prospect_num, source, original, edited, edited_by, created_at
+		FROM message_edit_nodepath
+		WHERE flow_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, flowID, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get message edits")
+		return nil, err
+	}
+	defer rows.Close()
+
+	edits := make([]models.RecordedEdit, 0)
+	for rows.Next() {
+		var edit models.RecordedEdit
+		err := rows.Scan(
+			&edit.ID, &edit.FlowID, &edit.NodeID, &edit.IDDevice, &edit.ProspectNum,
+			&edit.Source, &edit.Original, &edit.Edited, &edit.EditedBy, &edit.CreatedAt,
+		)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan message edit row")
+			continue
+		}
+		edits = append(edits, edit)
+	}
+
+	return edits, rows.Err()
+}
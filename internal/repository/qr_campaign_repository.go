@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// QRCampaignRepository handles database operations for QR entry campaigns
+type QRCampaignRepository interface {
+	CreateQRCampaign(campaign *models.QRCampaign) error
+	GetQRCampaignByID(id string) (*models.QRCampaign, error)
+	GetQRCampaignByEntryKeyword(idDevice, entryKeyword string) (*models.QRCampaign, error)
+	GetQRCampaignsByDevice(idDevice string) ([]models.QRCampaign, error)
+	IncrementScanCount(id string) error
+	DeleteQRCampaign(id string) error
+}
+
+type qrCampaignRepository struct {
+	db pgDB
+}
+
+// NewQRCampaignRepository creates a new QR campaign repository
+func NewQRCampaignRepository(db *sql.DB) QRCampaignRepository {
+	return &qrCampaignRepository{db: pgDB{db}}
+}
+
+// CreateQRCampaign inserts a new campaign
+func (r *qrCampaignRepository) CreateQRCampaign(campaign *models.QRCampaign) error {
+	query := `
+		INSERT INTO qr_campaign_nodepath (id, id_device, name, flow_id, keyword, entry_keyword)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		campaign.ID,
+		campaign.IDDevice,
+		campaign.Name,
+		campaign.FlowID,
+		campaign.Keyword,
+		campaign.EntryKeyword,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create QR campaign")
+		return err
+	}
+
+	return nil
+}
+
+// GetQRCampaignByID returns a single campaign, or nil when not found
+func (r *qrCampaignRepository) GetQRCampaignByID(id string) (*models.QRCampaign, error) {
+	query := `
+		SELECT id, id_device, name, flow_id, keyword, entry_keyword, scan_count, created_at
+		FROM qr_campaign_nodepath
+		WHERE id = ?
+	`
+
+	return r.scanQRCampaign(r.db.QueryRow(query, id))
+}
+
+// GetQRCampaignByEntryKeyword matches an inbound entry keyword to its campaign
+func (r *qrCampaignRepository) GetQRCampaignByEntryKeyword(idDevice, entryKeyword string) (*models.QRCampaign, error) {
+	query := `
+		SELECT id, id_device, name, flow_id, keyword, entry_keyword, scan_count, created_at
+		FROM qr_campaign_nodepath
+		WHERE id_device = ? AND entry_keyword = ?
+	`
+
+	return r.scanQRCampaign(r.db.QueryRow(query, idDevice, entryKeyword))
+}
+
+func (r *qrCampaignRepository) scanQRCampaign(row *sql.Row) (*models.QRCampaign, error) {
+	var campaign models.QRCampaign
+	err := row.Scan(
+		&campaign.ID,
+		&campaign.IDDevice,
+		&campaign.Name,
+		&campaign.FlowID,
+		&campaign.Keyword,
+		&campaign.EntryKeyword,
+		&campaign.ScanCount,
+		&campaign.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get QR campaign")
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+// GetQRCampaignsByDevice returns a device's campaigns, newest first
+func (r *qrCampaignRepository) GetQRCampaignsByDevice(idDevice string) ([]models.QRCampaign, error) {
+	query := `
+		SELECT id, id_device, name, flow_id, keyword, entry_keyword, scan_count, created_at
+		FROM qr_campaign_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get QR campaigns")
+		return nil, err
+	}
+	defer rows.Close()
+
+	campaigns := make([]models.QRCampaign, 0)
+	for rows.Next() {
+		var campaign models.QRCampaign
+		if err := rows.Scan(
+			&campaign.ID,
+			&campaign.IDDevice,
+			&campaign.Name,
+			&campaign.FlowID,
+			&campaign.Keyword,
+			&campaign.EntryKeyword,
+			&campaign.ScanCount,
+			&campaign.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan QR campaign row")
+			continue
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, rows.Err()
+}
+
+// IncrementScanCount counts one scan of a campaign's QR
+func (r *qrCampaignRepository) IncrementScanCount(id string) error {
+	query := `UPDATE qr_campaign_nodepath SET scan_count = scan_count + 1 WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to increment QR scan count")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteQRCampaign removes a campaign
+func (r *qrCampaignRepository) DeleteQRCampaign(id string) error {
+	query := `DELETE FROM qr_campaign_nodepath WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete QR campaign")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// QualityRepository handles database operations for automated conversation
+// quality evaluations
+type QualityRepository interface {
+	GetUnscoredCompletedConversations(limit int) ([]models.AIWhatsapp, error)
+	GetConversationTranscript(prospectNum, idDevice string, limit int) ([]models.ConversationLog, error)
+	GetDeviceAPIKey(idDevice string) (string, error)
+	GetFlowNodesJSON(flowID string) (string, error)
+	InsertScore(score *models.QualityScore) error
+	GetScoresByDevice(idDevice string, limit int) ([]models.QualityScore, error)
+	GetVersionSummaries(flowID string) ([]models.QualityVersionSummary, error)
+}
+
+type qualityRepository struct {
+	db pgDB
+}
+
+// NewQualityRepository creates a new quality repository
+func NewQualityRepository(db *sql.DB) QualityRepository {
+	return &qualityRepository{db: pgDB{db}}
+}
+
+// GetUnscoredCompletedConversations samples completed flow executions that
+// have not been evaluated yet, most recent first
+func (r *qualityRepository) GetUnscoredCompletedConversations(limit int) ([]models.AIWhatsapp, error) {
+	query := `
+		SELECT a.prospect_num, a.id_device, a.flow_id
+		FROM ai_whatsapp a
+		WHERE a.execution_status = 'completed'
+		AND a.flow_id IS NOT NULL AND a.flow_id != ''
+		AND NOT EXISTS (
+			SELECT 1 FROM quality_score_nodepath q
+			WHERE q.prospect_num = a.prospect_num AND q.id_device = a.id_device
+		)
+		ORDER BY a.updated_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get unscored completed conversations")
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := make([]models.AIWhatsapp, 0)
+	for rows.Next() {
+		var conversation models.AIWhatsapp
+		if err := rows.Scan(&conversation.ProspectNum, &conversation.IDDevice, &conversation.FlowID); err != nil {
+			logrus.WithError(err).Error("Failed to scan unscored conversation row")
+			continue
+		}
+		conversations = append(conversations, conversation)
+	}
+
+	return conversations, rows.Err()
+}
+
+// GetConversationTranscript returns the conversation messages in order
+func (r *qualityRepository) GetConversationTranscript(prospectNum, idDevice string, limit int) ([]models.ConversationLog, error) {
+	query := `
+		SELECT message, sender
+		FROM conversation_log
+		WHERE prospect_num = ? AND id_device = ?
+		ORDER BY created_at ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, prospectNum, idDevice, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get conversation transcript")
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]models.ConversationLog, 0)
+	for rows.Next() {
+		var message models.ConversationLog
+		if err := rows.Scan(&message.Message, &message.Sender); err != nil {
+			logrus.WithError(err).Error("Failed to scan transcript row")
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// GetDeviceAPIKey returns the AI API key configured for a device
+func (r *qualityRepository) GetDeviceAPIKey(idDevice string) (string, error) {
+	var apiKey sql.NullString
+	err := r.db.QueryRow(`SELECT api_key FROM device_setting WHERE id_device = ?`, idDevice).Scan(&apiKey)
+	if err != nil {
+		return "", err
+	}
+	return apiKey.String, nil
+}
+
+// GetFlowNodesJSON returns the flow's raw nodes JSON used to fingerprint the
+// flow version
+func (r *qualityRepository) GetFlowNodesJSON(flowID string) (string, error) {
+	var nodes sql.NullString
+	err := r.db.QueryRow(`SELECT nodes FROM chatbot_flows WHERE id = ?`, flowID).Scan(&nodes)
+	if err != nil {
+		return "", err
+	}
+	return nodes.String, nil
+}
+
+// InsertScore stores one conversation evaluation
+func (r *qualityRepository) InsertScore(score *models.QualityScore) error {
+	query := `
+		INSERT INTO quality_score_nodepath (prospect_num, id_device, flow_id, flow_version, accuracy, tone, goal_completion, overall, summary)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(query,
+		score.ProspectNum, score.IDDevice, score.FlowID, score.FlowVersion,
+		score.Accuracy, score.Tone, score.GoalCompletion, score.Overall, score.Summary,
+	).Scan(&score.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert quality score")
+		return err
+	}
+
+	return nil
+}
+
+// GetScoresByDevice returns the device's evaluations, newest first
+func (r *qualityRepository) GetScoresByDevice(idDevice string, limit int) ([]models.QualityScore, error) {
+	query := `
+		SELECT id, prospect_num, id_device, flow_id, flow_version, accuracy, tone, goal_completion, overall, summary, created_at
+		FROM quality_score_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, idDevice, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get quality scores")
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := make([]models.QualityScore, 0)
+	for rows.Next() {
+		var score models.QualityScore
+		err := rows.Scan(
+			&score.ID, &score.ProspectNum, &score.IDDevice, &score.FlowID, &score.FlowVersion,
+			&score.Accuracy, &score.Tone, &score.GoalCompletion, &score.Overall, &score.Summary, &score.CreatedAt,
+		)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan quality score row")
+			continue
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
+
+// GetVersionSummaries averages the rubric scores per flow version, newest
+// version first
+func (r *qualityRepository) GetVersionSummaries(flowID string) ([]models.QualityVersionSummary, error) {
+	query := `
+		SELECT flow_id, flow_version, COUNT(*) as conversations,
+			AVG(accuracy), AVG(tone), AVG(goal_completion), AVG(overall),
+			MAX(created_at)
+		FROM quality_score_nodepath
+		WHERE flow_id = ?
+		GROUP BY flow_id, flow_version
+		ORDER BY MAX(created_at) DESC
+	`
+
+	rows, err := r.db.Query(query, flowID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get quality version summaries")
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make([]models.QualityVersionSummary, 0)
+	for rows.Next() {
+		var summary models.QualityVersionSummary
+		err := rows.Scan(
+			&summary.FlowID, &summary.FlowVersion, &summary.Conversations,
+			&summary.AvgAccuracy, &summary.AvgTone, &summary.AvgGoalCompletion, &summary.AvgOverall,
+			&summary.LastScoredAt,
+		)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan quality summary row")
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReadStateRepository handles database operations for per-agent conversation read cursors
+type ReadStateRepository interface {
+	UpsertReadCursor(agentID, prospectNum, idDevice string) error
+	GetReadCursor(agentID, prospectNum, idDevice string) (*models.ConversationReadState, error)
+	GetUnreadStates(agentID, idDevice string) ([]models.ConversationUnreadState, error)
+}
+
+type readStateRepository struct {
+	db pgDB
+}
+
+// NewReadStateRepository creates a new read state repository
+func NewReadStateRepository(db *sql.DB) ReadStateRepository {
+	return &readStateRepository{db: pgDB{db}}
+}
+
+// UpsertReadCursor moves an agent's read cursor for a conversation to now
+func (r *readStateRepository) UpsertReadCursor(agentID, prospectNum, idDevice string) error {
+	query := `
+		INSERT INTO conversation_read_state_nodepath (agent_id, prospect_num, id_device, last_read_at)
+		VALUES (?, ?, ?, NOW())
+		ON CONFLICT (agent_id, prospect_num, id_device) DO UPDATE SET last_read_at = NOW(), updated_at = NOW()
+	`
+
+	_, err := r.db.Exec(query, agentID, prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to upsert read cursor")
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"agent_id":     agentID,
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+	}).Info("✅ Read cursor updated")
+
+	return nil
+}
+
+// GetReadCursor returns an agent's read cursor for a conversation, or nil if none exists
+func (r *readStateRepository) GetReadCursor(agentID, prospectNum, idDevice string) (*models.ConversationReadState, error) {
+	query := `
+		SELECT id, agent_id, prospect_num, id_device, last_read_at, created_at, updated_at
+		FROM conversation_read_state_nodepath
+		WHERE agent_id = ? AND prospect_num = ? AND id_device = ?
+	`
+
+	var state models.ConversationReadState
+	err := r.db.QueryRow(query, agentID, prospectNum, idDevice).Scan(
+		&state.ID,
+		&state.AgentID,
+		&state.ProspectNum,
+		&state.IDDevice,
+		&state.LastReadAt,
+		&state.CreatedAt,
+		&state.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get read cursor")
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// GetUnreadStates returns the unread flag for each conversation on a device as seen
+// by one agent. A conversation is unread when it has no cursor or was updated after
+// the agent's last read
+func (r *readStateRepository) GetUnreadStates(agentID, idDevice string) ([]models.ConversationUnreadState, error) {
+	query := `
+		SELECT a.prospect_num,
+		       (r.last_read_at IS NULL OR a.updated_at > r.last_read_at) AS unread
+		FROM ai_whatsapp_nodepath a
+		LEFT JOIN conversation_read_state_nodepath r
+		       ON r.prospect_num = a.prospect_num
+		      AND r.id_device = a.id_device
+		      AND r.agent_id = ?
+		WHERE a.id_device = ?
+	`
+
+	rows, err := r.db.Query(query, agentID, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get unread states")
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make([]models.ConversationUnreadState, 0)
+	for rows.Next() {
+		var state models.ConversationUnreadState
+		if err := rows.Scan(&state.ProspectNum, &state.Unread); err != nil {
+			logrus.WithError(err).Error("Failed to scan unread state row")
+			continue
+		}
+		states = append(states, state)
+	}
+
+	return states, rows.Err()
+}
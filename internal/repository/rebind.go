@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Rebind rewrites `?` placeholders into the ordinal $1..$n form PostgreSQL
+// expects. Repository queries are written with `?` for readability; every
+// statement passes through here before it reaches the lib/pq driver.
+// Question marks inside single-quoted SQL literals are left untouched
+func Rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query) + 16)
+	n := 0
+	inLiteral := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			inLiteral = !inLiteral
+			sb.WriteRune(r)
+		case r == '?' && !inLiteral:
+			n++
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(n))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// pgDB wraps the shared *sql.DB so repository statements written with `?`
+// placeholders are rebound for PostgreSQL before execution. Repositories
+// store this instead of the raw handle; everything else on *sql.DB is
+// promoted unchanged
+type pgDB struct {
+	*sql.DB
+}
+
+// Exec rebinds the query's placeholders and executes it
+func (d pgDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.DB.Exec(Rebind(query), args...)
+}
+
+// Query rebinds the query's placeholders and runs it
+func (d pgDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.DB.Query(Rebind(query), args...)
+}
+
+// QueryRow rebinds the query's placeholders and runs it
+func (d pgDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return d.DB.QueryRow(Rebind(query), args...)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import "testing"
+
+// TestRebind covers the placeholder rewrite every repository statement goes
+// through before reaching the PostgreSQL driver
+func TestRebind(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "no placeholders",
+			in:   "SELECT 1",
+			want: "SELECT 1",
+		},
+		{
+			name: "ordinal numbering",
+			in:   "SELECT * FROM t WHERE a = ? AND b = ? AND c = ?",
+			want: "SELECT * FROM t WHERE a = $1 AND b = $2 AND c = $3",
+		},
+		{
+			name: "question mark inside literal is kept",
+			in:   "SELECT * FROM t WHERE a = '?' AND b = ?",
+			want: "SELECT * FROM t WHERE a = '?' AND b = $1",
+		},
+		{
+			name: "upsert",
+			in:   "INSERT INTO t (a, b) VALUES (?, ?) ON CONFLICT (a) DO UPDATE SET b = EXCLUDED.b",
+			want: "INSERT INTO t (a, b) VALUES ($1, $2) ON CONFLICT (a) DO UPDATE SET b = EXCLUDED.b",
+		},
+	}
+
+	for _, tc := range cases {
+		if got := Rebind(tc.in); got != tc.want {
+			t.Errorf("%s: Rebind(%q) = %q, want %q", tc.name, tc.in, got, tc.want)
+		}
+	}
+}
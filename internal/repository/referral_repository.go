@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReferralRepository handles database operations for referral tracking
+type ReferralRepository interface {
+	CreateReferral(referral *models.Referral) error
+	GetReferralByProspect(idDevice, prospectNum string) (*models.Referral, error)
+	GetReferralByCode(code string) (*models.Referral, error)
+	RecordAttribution(code, idDevice, referrerNum, referredNum string) error
+	CountReferrals(idDevice, referrerNum string) (int, error)
+	GetLeaderboard(idDevice string, limit int) ([]models.ReferralLeaderboardEntry, error)
+}
+
+type referralRepository struct {
+	db pgDB
+}
+
+// NewReferralRepository creates a new referral repository
+func NewReferralRepository(db *sql.DB) ReferralRepository {
+	return &referralRepository{db: pgDB{db}}
+}
+
+// CreateReferral inserts a prospect's invite code
+func (r *referralRepository) CreateReferral(referral *models.Referral) error {
+	query := `
+		INSERT INTO referral_nodepath (id, id_device, prospect_num, code)
+		VALUES (?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, referral.ID, referral.IDDevice, referral.ProspectNum, referral.Code)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create referral")
+		return err
+	}
+
+	return nil
+}
+
+// GetReferralByProspect returns a prospect's referral record, or nil
+func (r *referralRepository) GetReferralByProspect(idDevice, prospectNum string) (*models.Referral, error) {
+	query := `
+		SELECT id, id_device, prospect_num, code, created_at
+		FROM referral_nodepath
+		WHERE id_device = ? AND prospect_num = ?
+	`
+
+	return r.scanReferral(r.db.QueryRow(query, idDevice, prospectNum))
+}
+
+// GetReferralByCode returns the referral a code belongs to, or nil
+func (r *referralRepository) GetReferralByCode(code string) (*models.Referral, error) {
+	query := `
+		SELECT id, id_device, prospect_num, code, created_at
+		FROM referral_nodepath
+		WHERE code = ?
+	`
+
+	return r.scanReferral(r.db.QueryRow(query, code))
+}
+
+func (r *referralRepository) scanReferral(row *sql.Row) (*models.Referral, error) {
+	var referral models.Referral
+	err := row.Scan(
+		&referral.ID,
+		&referral.IDDevice,
+		&referral.ProspectNum,
+		&referral.Code,
+		&referral.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get referral")
+		return nil, err
+	}
+
+	return &referral, nil
+}
+
+// RecordAttribution credits a referrer for a new conversation. Each referred
+// number counts once per code
+func (r *referralRepository) RecordAttribution(code, idDevice, referrerNum, referredNum string) error {
+	query := `
+		INSERT INTO referral_attribution_nodepath (code, id_device, referrer_num, referred_num)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := r.db.Exec(query, code, idDevice, referrerNum, referredNum)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to record referral attribution")
+		return err
+	}
+
+	return nil
+}
+
+// CountReferrals returns how many conversations a referrer has brought in
+func (r *referralRepository) CountReferrals(idDevice, referrerNum string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM referral_attribution_nodepath
+		WHERE id_device = ? AND referrer_num = ?
+	`
+
+	var count int
+	if err := r.db.QueryRow(query, idDevice, referrerNum).Scan(&count); err != nil {
+		logrus.WithError(err).Error("Failed to count referrals")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetLeaderboard returns the top referrers for a device
+func (r *referralRepository) GetLeaderboard(idDevice string, limit int) ([]models.ReferralLeaderboardEntry, error) {
+	query := `
+		SELECT referrer_num, COUNT(*) AS referral_count
+		FROM referral_attribution_nodepath
+		WHERE id_device = ?
+		GROUP BY referrer_num
+		ORDER BY referral_count DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, idDevice, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get referral leaderboard")
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.ReferralLeaderboardEntry, 0)
+	for rows.Next() {
+		var entry models.ReferralLeaderboardEntry
+		if err := rows.Scan(&entry.ProspectNum, &entry.ReferralCount); err != nil {
+			logrus.WithError(err).Error("Failed to scan leaderboard row")
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
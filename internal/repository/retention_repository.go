@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RetentionRepository handles database operations for data retention
+// policies and the purges they drive
+type RetentionRepository interface {
+	GetRetentionPolicy(idDevice string) (*models.RetentionPolicy, error)
+	SetRetentionPolicy(policy *models.RetentionPolicy) error
+	GetEnabledRetentionPolicies() ([]models.RetentionPolicy, error)
+	CountOldConversationLogs(idDevice string, cutoff time.Time) (int64, error)
+	DeleteOldConversationLogs(idDevice string, cutoff time.Time) (int64, error)
+	CountOldConversationBlobs(idDevice string, cutoff time.Time) (int64, error)
+	ClearOldConversationBlobs(idDevice string, cutoff time.Time) (int64, error)
+	CountOldCompletedExecutions(idDevice string, cutoff time.Time) (int64, error)
+	DeleteOldCompletedExecutions(idDevice string, cutoff time.Time) (int64, error)
+}
+
+type retentionRepository struct {
+	db pgDB
+}
+
+// NewRetentionRepository creates a new retention repository
+func NewRetentionRepository(db *sql.DB) RetentionRepository {
+	return &retentionRepository{db: pgDB{db}}
+}
+
+// GetRetentionPolicy returns a device's retention policy, or nil when never set
+func (r *retentionRepository) GetRetentionPolicy(idDevice string) (*models.RetentionPolicy, error) {
+	query := `
+		SELECT id_device, enabled, conversation_days, execution_days
+		FROM retention_policy_nodepath
+		WHERE id_device = ?
+	`
+
+	var policy models.RetentionPolicy
+	err := r.db.QueryRow(query, idDevice).Scan(
+		&policy.IDDevice,
+		&policy.Enabled,
+		&policy.ConversationDays,
+		&policy.ExecutionDays,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get retention policy")
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// SetRetentionPolicy saves a device's retention policy
+func (r *retentionRepository) SetRetentionPolicy(policy *models.RetentionPolicy) error {
+	query := `
+		INSERT INTO retention_policy_nodepath (id_device, enabled, conversation_days, execution_days)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (id_device) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			conversation_days = EXCLUDED.conversation_days,
+			execution_days = EXCLUDED.execution_days
+	`
+
+	_, err := r.db.Exec(query,
+		policy.IDDevice,
+		policy.Enabled,
+		policy.ConversationDays,
+		policy.ExecutionDays,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set retention policy")
+		return err
+	}
+
+	return nil
+}
+
+// GetEnabledRetentionPolicies returns every enabled policy for scheduled runs
+func (r *retentionRepository) GetEnabledRetentionPolicies() ([]models.RetentionPolicy, error) {
+	query := `
+		SELECT id_device, enabled, conversation_days, execution_days
+		FROM retention_policy_nodepath
+		WHERE enabled = TRUE
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get enabled retention policies")
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := make([]models.RetentionPolicy, 0)
+	for rows.Next() {
+		var policy models.RetentionPolicy
+		if err := rows.Scan(
+			&policy.IDDevice,
+			&policy.Enabled,
+			&policy.ConversationDays,
+			&policy.ExecutionDays,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan retention policy row")
+			continue
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// CountOldConversationLogs counts purgeable conversation log rows
+func (r *retentionRepository) CountOldConversationLogs(idDevice string, cutoff time.Time) (int64, error) {
+	return r.countRows(`
+		SELECT COUNT(*)
+		FROM conversation_log
+		WHERE id_device = ? AND created_at < ?
+	`, idDevice, cutoff)
+}
+
+// DeleteOldConversationLogs removes conversation log rows past the cutoff
+func (r *retentionRepository) DeleteOldConversationLogs(idDevice string, cutoff time.Time) (int64, error) {
+	return r.execRows(`
+		DELETE FROM conversation_log
+		WHERE id_device = ? AND created_at < ?
+	`, idDevice, cutoff)
+}
+
+// CountOldConversationBlobs counts conversations whose stored transcript
+// would be blanked
+func (r *retentionRepository) CountOldConversationBlobs(idDevice string, cutoff time.Time) (int64, error) {
+	return r.countRows(`
+		SELECT COUNT(*)
+		FROM ai_whatsapp
+		WHERE id_device = ? AND updated_at < ?
+			AND (conv_last IS NOT NULL OR conv_current IS NOT NULL)
+	`, idDevice, cutoff)
+}
+
+// ClearOldConversationBlobs blanks stored transcripts past the cutoff while
+// keeping the prospect row itself (stage, status and counters survive)
+func (r *retentionRepository) ClearOldConversationBlobs(idDevice string, cutoff time.Time) (int64, error) {
+	return r.execRows(`
+		UPDATE ai_whatsapp
+		SET conv_last = NULL, conv_current = NULL
+		WHERE id_device = ? AND updated_at < ?
+			AND (conv_last IS NOT NULL OR conv_current IS NOT NULL)
+	`, idDevice, cutoff)
+}
+
+// CountOldCompletedExecutions counts deletable completed conversations
+func (r *retentionRepository) CountOldCompletedExecutions(idDevice string, cutoff time.Time) (int64, error) {
+	return r.countRows(`
+		SELECT COUNT(*)
+		FROM ai_whatsapp
+		WHERE id_device = ? AND updated_at < ? AND execution_status = 'completed'
+	`, idDevice, cutoff)
+}
+
+// DeleteOldCompletedExecutions removes completed conversations past the cutoff
+func (r *retentionRepository) DeleteOldCompletedExecutions(idDevice string, cutoff time.Time) (int64, error) {
+	return r.execRows(`
+		DELETE FROM ai_whatsapp
+		WHERE id_device = ? AND updated_at < ? AND execution_status = 'completed'
+	`, idDevice, cutoff)
+}
+
+func (r *retentionRepository) countRows(query string, idDevice string, cutoff time.Time) (int64, error) {
+	var count int64
+	if err := r.db.QueryRow(query, idDevice, cutoff).Scan(&count); err != nil {
+		logrus.WithError(err).Error("Failed to count rows for retention")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *retentionRepository) execRows(query string, idDevice string, cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(query, idDevice, cutoff)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to purge rows for retention")
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, nil
+	}
+	return affected, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SavedViewRepository handles database operations for saved inbox and
+// prospect list views
+type SavedViewRepository interface {
+	CreateView(view *models.SavedView) error
+	UpdateView(view *models.SavedView) error
+	DeleteView(id int, userID string) error
+	GetView(id int) (*models.SavedView, error)
+	ListViews(userID, scope, idDevice string) ([]models.SavedView, error)
+	SetDefaultView(id int, userID, scope string) error
+}
+
+type savedViewRepository struct {
+	db pgDB
+}
+
+// NewSavedViewRepository creates a new saved view repository
+func NewSavedViewRepository(db *sql.DB) SavedViewRepository {
+	return &savedViewRepository{db: pgDB{db}}
+}
+
+const savedViewColumns = `id, user_id, id_device, name, scope, filters, sort, columns, is_default, shared, created_at, updated_at`
+
+// CreateView inserts a new saved view
+func (r *savedViewRepository) CreateView(view *models.SavedView) error {
+	query := `
+		INSERT INTO saved_view_nodepath (user_id, id_device, name, scope, filters, sort, columns, is_default, shared)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(query,
+		view.UserID, view.IDDevice, view.Name, view.Scope,
+		view.Filters, view.Sort, view.Columns, view.IsDefault, view.Shared,
+	).Scan(&view.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create saved view")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateView updates an owned view's definition
+func (r *savedViewRepository) UpdateView(view *models.SavedView) error {
+	query := `
+		UPDATE saved_view_nodepath
+		SET name = ?, filters = ?, sort = ?, columns = ?, shared = ?, updated_at = NOW()
+		WHERE id = ? AND user_id = ?
+	`
+
+	result, err := r.db.Exec(query,
+		view.Name, view.Filters, view.Sort, view.Columns, view.Shared,
+		view.ID, view.UserID,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update saved view")
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteView removes an owned view
+func (r *savedViewRepository) DeleteView(id int, userID string) error {
+	result, err := r.db.Exec(`DELETE FROM saved_view_nodepath WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete saved view")
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetView returns one view by ID
+func (r *savedViewRepository) GetView(id int) (*models.SavedView, error) {
+	view := &models.SavedView{}
+	err := r.db.QueryRow(
+		`SELECT `+savedViewColumns+` FROM saved_view_nodepath WHERE id = ?`, id,
+	).Scan(
+		&view.ID, &view.UserID, &view.IDDevice, &view.Name, &view.Scope,
+		&view.Filters, &view.Sort, &view.Columns, &view.IsDefault, &view.Shared,
+		&view.CreatedAt, &view.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get saved view")
+		return nil, err
+	}
+
+	return view, nil
+}
+
+// ListViews returns the user's own views plus views shared to the device,
+// optionally filtered by scope
+func (r *savedViewRepository) ListViews(userID, scope, idDevice string) ([]models.SavedView, error) {
+	query := `
+		SELECT ` + savedViewColumns + `
+		FROM saved_view_nodepath
+		WHERE (user_id = ? OR (shared = TRUE AND id_device = ? AND id_device != ''))
+	`
+	args := []interface{}{userID, idDevice}
+	if scope != "" {
+		query += ` AND scope = ?`
+		args = append(args, scope)
+	}
+	query += ` ORDER BY is_default DESC, name ASC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list saved views")
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := make([]models.SavedView, 0)
+	for rows.Next() {
+		var view models.SavedView
+		if err := rows.Scan(
+			&view.ID, &view.UserID, &view.IDDevice, &view.Name, &view.Scope,
+			&view.Filters, &view.Sort, &view.Columns, &view.IsDefault, &view.Shared,
+			&view.CreatedAt, &view.UpdatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan saved view row")
+			continue
+		}
+		views = append(views, view)
+	}
+
+	return views, rows.Err()
+}
+
+// SetDefaultView makes one owned view the user's default for its scope,
+// clearing any previous default
+func (r *savedViewRepository) SetDefaultView(id int, userID, scope string) error {
+	if _, err := r.db.Exec(
+		`UPDATE saved_view_nodepath SET is_default = FALSE WHERE user_id = ? AND scope = ?`,
+		userID, scope,
+	); err != nil {
+		logrus.WithError(err).Error("Failed to clear default views")
+		return err
+	}
+
+	result, err := r.db.Exec(
+		`UPDATE saved_view_nodepath SET is_default = TRUE WHERE id = ? AND user_id = ?`,
+		id, userID,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set default view")
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ScriptPermissionRepository handles database operations for per-flow script
+// node permissions
+type ScriptPermissionRepository interface {
+	GetScriptPermission(flowID string) (bool, error)
+	SetScriptPermission(flowID string, enabled bool) error
+}
+
+type scriptPermissionRepository struct {
+	db pgDB
+}
+
+// NewScriptPermissionRepository creates a new script permission repository
+func NewScriptPermissionRepository(db *sql.DB) ScriptPermissionRepository {
+	return &scriptPermissionRepository{db: pgDB{db}}
+}
+
+// GetScriptPermission reports whether script nodes may run in a flow; flows
+// never granted permission default to disabled
+func (r *scriptPermissionRepository) GetScriptPermission(flowID string) (bool, error) {
+	query := `SELECT enabled FROM script_permission_nodepath WHERE flow_id = ?`
+
+	var enabled bool
+	err := r.db.QueryRow(query, flowID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get script permission")
+		return false, err
+	}
+
+	return enabled, nil
+}
+
+// SetScriptPermission grants or revokes script execution for a flow
+func (r *scriptPermissionRepository) SetScriptPermission(flowID string, enabled bool) error {
+	query := `
+		INSERT INTO script_permission_nodepath (flow_id, enabled)
+		VALUES (?, ?)
+		ON CONFLICT (flow_id) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	_, err := r.db.Exec(query, flowID, enabled)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set script permission")
+		return err
+	}
+
+	return nil
+}
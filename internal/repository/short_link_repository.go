@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ShortLinkRepository handles database operations for short links
+type ShortLinkRepository interface {
+	CreateShortLink(link *models.ShortLink) error
+	GetShortLinkByCode(code string) (*models.ShortLink, error)
+	GetShortLinkByTarget(idDevice, prospectNum, targetURL string) (*models.ShortLink, error)
+	GetShortLinksByDevice(idDevice string) ([]models.ShortLink, error)
+	IncrementClickCount(id string) error
+	DeleteShortLink(id, idDevice string) error
+	GetAutoShorten(idDevice string) (bool, error)
+	SetAutoShorten(idDevice string, enabled bool) error
+}
+
+type shortLinkRepository struct {
+	db pgDB
+}
+
+// NewShortLinkRepository creates a new short link repository
+func NewShortLinkRepository(db *sql.DB) ShortLinkRepository {
+	return &shortLinkRepository{db: pgDB{db}}
+}
+
+// CreateShortLink inserts a new short link
+func (r *shortLinkRepository) CreateShortLink(link *models.ShortLink) error {
+	query := `
+		INSERT INTO short_link_nodepath (id, id_device, prospect_num, code, target_url, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		link.ID,
+		link.IDDevice,
+		link.ProspectNum,
+		link.Code,
+		link.TargetURL,
+		link.ExpiresAt,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create short link")
+		return err
+	}
+
+	return nil
+}
+
+// GetShortLinkByCode returns the link behind a code, or nil when not found
+func (r *shortLinkRepository) GetShortLinkByCode(code string) (*models.ShortLink, error) {
+	query := `
+		SELECT id, id_device, prospect_num, code, target_url, click_count, expires_at, created_at
+		FROM short_link_nodepath
+		WHERE code = ?
+	`
+
+	return r.scanShortLink(r.db.QueryRow(query, code))
+}
+
+// GetShortLinkByTarget returns a device's existing link for a target URL so
+// the same URL is not shortened twice
+func (r *shortLinkRepository) GetShortLinkByTarget(idDevice, prospectNum, targetURL string) (*models.ShortLink, error) {
+	query := `
+		SELECT id, id_device, prospect_num, code, target_url, click_count, expires_at, created_at
+		FROM short_link_nodepath
+		WHERE id_device = ? AND prospect_num = ? AND target_url = ?
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	return r.scanShortLink(r.db.QueryRow(query, idDevice, prospectNum, targetURL))
+}
+
+func (r *shortLinkRepository) scanShortLink(row *sql.Row) (*models.ShortLink, error) {
+	var link models.ShortLink
+	err := row.Scan(
+		&link.ID,
+		&link.IDDevice,
+		&link.ProspectNum,
+		&link.Code,
+		&link.TargetURL,
+		&link.ClickCount,
+		&link.ExpiresAt,
+		&link.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get short link")
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// GetShortLinksByDevice returns a device's short links, newest first
+func (r *shortLinkRepository) GetShortLinksByDevice(idDevice string) ([]models.ShortLink, error) {
+	query := `
+		SELECT id, id_device, prospect_num, code, target_url, click_count, expires_at, created_at
+		FROM short_link_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get short links")
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]models.ShortLink, 0)
+	for rows.Next() {
+		var link models.ShortLink
+		if err := rows.Scan(
+			&link.ID,
+			&link.IDDevice,
+			&link.ProspectNum,
+			&link.Code,
+			&link.TargetURL,
+			&link.ClickCount,
+			&link.ExpiresAt,
+			&link.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan short link row")
+			continue
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// IncrementClickCount counts one redirect through a link
+func (r *shortLinkRepository) IncrementClickCount(id string) error {
+	query := `UPDATE short_link_nodepath SET click_count = click_count + 1 WHERE id = ?`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to increment short link clicks")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteShortLink removes a short link, scoped to the owning device
+func (r *shortLinkRepository) DeleteShortLink(id, idDevice string) error {
+	query := `DELETE FROM short_link_nodepath WHERE id = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, id, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete short link")
+		return err
+	}
+
+	return nil
+}
+
+// GetAutoShorten returns whether outbound URL shortening is enabled for a device
+func (r *shortLinkRepository) GetAutoShorten(idDevice string) (bool, error) {
+	query := `SELECT auto_shorten FROM short_link_setting_nodepath WHERE id_device = ?`
+
+	var enabled bool
+	err := r.db.QueryRow(query, idDevice).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get short link setting")
+		return false, err
+	}
+
+	return enabled, nil
+}
+
+// SetAutoShorten enables or disables outbound URL shortening for a device
+func (r *shortLinkRepository) SetAutoShorten(idDevice string, enabled bool) error {
+	query := `
+		INSERT INTO short_link_setting_nodepath (id_device, auto_shorten)
+		VALUES (?, ?)
+		ON CONFLICT (id_device) DO UPDATE SET auto_shorten = EXCLUDED.auto_shorten
+	`
+
+	_, err := r.db.Exec(query, idDevice, enabled)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set short link setting")
+		return err
+	}
+
+	return nil
+}
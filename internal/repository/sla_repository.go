@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SLARepository handles database operations for SLA policies and handoff SLA events
+type SLARepository interface {
+	// Policy operations
+	GetPolicy(idDevice string) (*models.SLAPolicy, error)
+	GetActivePolicies() ([]models.SLAPolicy, error)
+	UpsertPolicy(policy *models.SLAPolicy) error
+
+	// Event operations
+	CreateEvent(prospectNum, idDevice string) error
+	MarkFirstResponse(prospectNum, idDevice string) error
+	GetOpenBreaches(idDevice string, firstResponseMinutes int) ([]models.SLAEvent, error)
+	MarkEscalated(eventID int) error
+	GetComplianceReport(idDevice string) (*models.SLAComplianceReport, error)
+}
+
+type slaRepository struct {
+	db pgDB
+}
+
+// NewSLARepository creates a new SLA repository
+func NewSLARepository(db *sql.DB) SLARepository {
+	return &slaRepository{db: pgDB{db}}
+}
+
+// GetPolicy returns the SLA policy for a device, or nil when none is configured
+func (r *slaRepository) GetPolicy(idDevice string) (*models.SLAPolicy, error) {
+	query := `
+		SELECT id, id_device, first_response_minutes, escalation_action, apology_message, manager_agent_id, is_active, created_at, updated_at
+		FROM sla_policy_nodepath
+		WHERE id_device = ?
+	`
+
+	policy, err := r.scanPolicy(r.db.QueryRow(query, idDevice))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get SLA policy")
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// GetActivePolicies returns all active SLA policies for the breach monitor
+func (r *slaRepository) GetActivePolicies() ([]models.SLAPolicy, error) {
+	query := `
+		SELECT id, id_device, first_response_minutes, escalation_action, apology_message, manager_agent_id, is_active, created_at, updated_at
+		FROM sla_policy_nodepath
+		WHERE is_active = TRUE
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get active SLA policies")
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := make([]models.SLAPolicy, 0)
+	for rows.Next() {
+		policy, err := r.scanPolicy(rows)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to scan SLA policy row")
+			continue
+		}
+		policies = append(policies, *policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// scanner covers both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *slaRepository) scanPolicy(row rowScanner) (*models.SLAPolicy, error) {
+	var policy models.SLAPolicy
+	var isActive int
+	err := row.Scan(
+		&policy.ID,
+		&policy.IDDevice,
+		&policy.FirstResponseMinutes,
+		&policy.EscalationAction,
+		&policy.ApologyMessage,
+		&policy.ManagerAgentID,
+		&isActive,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	policy.IsActive = isActive == 1
+	return &policy, nil
+}
+
+// UpsertPolicy creates or updates the SLA policy for a device
+func (r *slaRepository) UpsertPolicy(policy *models.SLAPolicy) error {
+	isActive := 0
+	if policy.IsActive {
+		isActive = 1
+	}
+
+	query := `
+		INSERT INTO sla_policy_nodepath (id_device, first_response_minutes, escalation_action, apology_message, manager_agent_id, is_active)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id_device) DO UPDATE SET
+			first_response_minutes = EXCLUDED.first_response_minutes,
+			escalation_action = EXCLUDED.escalation_action,
+			apology_message = EXCLUDED.apology_message,
+			manager_agent_id = EXCLUDED.manager_agent_id,
+			is_active = EXCLUDED.is_active
+	`
+
+	_, err := r.db.Exec(query,
+		policy.IDDevice,
+		policy.FirstResponseMinutes,
+		policy.EscalationAction,
+		policy.ApologyMessage,
+		policy.ManagerAgentID,
+		isActive,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to upsert SLA policy")
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device":              policy.IDDevice,
+		"first_response_minutes": policy.FirstResponseMinutes,
+		"escalation_action":      policy.EscalationAction,
+	}).Info("✅ SLA policy saved")
+
+	return nil
+}
+
+// CreateEvent records a new handoff awaiting first agent response. Reassignments
+// of an already-open handoff do not start a second clock
+func (r *slaRepository) CreateEvent(prospectNum, idDevice string) error {
+	query := `
+		INSERT INTO sla_event_nodepath (prospect_num, id_device)
+		SELECT ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM sla_event_nodepath
+			WHERE prospect_num = ? AND id_device = ?
+			  AND first_response_at IS NULL AND escalated_at IS NULL
+		)
+	`
+
+	_, err := r.db.Exec(query, prospectNum, idDevice, prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create SLA event")
+		return err
+	}
+
+	return nil
+}
+
+// MarkFirstResponse stamps the first agent response on any open events for a conversation
+func (r *slaRepository) MarkFirstResponse(prospectNum, idDevice string) error {
+	query := `
+		UPDATE sla_event_nodepath
+		SET first_response_at = NOW()
+		WHERE prospect_num = ? AND id_device = ? AND first_response_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, prospectNum, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to mark SLA first response")
+		return err
+	}
+
+	return nil
+}
+
+// GetOpenBreaches returns events past the first-response target that have not
+// been responded to or escalated yet
+func (r *slaRepository) GetOpenBreaches(idDevice string, firstResponseMinutes int) ([]models.SLAEvent, error) {
+	query := `
+		SELECT id, prospect_num, id_device, handoff_at, first_response_at, breached, escalated_at, created_at
+		FROM sla_event_nodepath
+		WHERE id_device = ?
+		  AND first_response_at IS NULL
+		  AND escalated_at IS NULL
+		  AND handoff_at < NOW() - (? * INTERVAL '1 minute')
+	`
+
+	rows, err := r.db.Query(query, idDevice, firstResponseMinutes)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get open SLA breaches")
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.SLAEvent, 0)
+	for rows.Next() {
+		var event models.SLAEvent
+		var breached int
+		if err := rows.Scan(
+			&event.ID,
+			&event.ProspectNum,
+			&event.IDDevice,
+			&event.HandoffAt,
+			&event.FirstResponseAt,
+			&breached,
+			&event.EscalatedAt,
+			&event.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan SLA event row")
+			continue
+		}
+		event.Breached = breached == 1
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// MarkEscalated flags an event as breached and escalated
+func (r *slaRepository) MarkEscalated(eventID int) error {
+	query := `
+		UPDATE sla_event_nodepath
+		SET breached = 1, escalated_at = NOW()
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query, eventID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to mark SLA event escalated")
+		return err
+	}
+
+	return nil
+}
+
+// GetComplianceReport summarizes SLA performance for a device
+func (r *slaRepository) GetComplianceReport(idDevice string) (*models.SLAComplianceReport, error) {
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COALESCE(SUM(CASE WHEN first_response_at IS NOT NULL AND breached = 0 THEN 1 ELSE 0 END), 0) AS met,
+			COALESCE(SUM(CASE WHEN breached = 1 THEN 1 ELSE 0 END), 0) AS breached,
+			COALESCE(SUM(CASE WHEN first_response_at IS NULL AND breached = 0 THEN 1 ELSE 0 END), 0) AS pending,
+			COALESCE(AVG(CASE WHEN first_response_at IS NOT NULL THEN TIMESTAMPDIFF(MINUTE, handoff_at, first_response_at) END), 0) AS avg_minutes
+		FROM sla_event_nodepath
+		WHERE id_device = ?
+	`
+
+	report := &models.SLAComplianceReport{IDDevice: idDevice}
+	err := r.db.QueryRow(query, idDevice).Scan(
+		&report.TotalHandoffs,
+		&report.MetSLA,
+		&report.Breached,
+		&report.Pending,
+		&report.AvgResponseMinutes,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get SLA compliance report")
+		return nil, err
+	}
+
+	resolved := report.MetSLA + report.Breached
+	if resolved > 0 {
+		report.CompliancePercent = float64(report.MetSLA) / float64(resolved) * 100
+	}
+
+	return report, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StageHistoryRepository handles database operations for recorded stage
+// transitions
+type StageHistoryRepository interface {
+	RecordStageTransition(transition *models.StageTransition) error
+	GetStageHistory(prospectNum, idDevice string) ([]models.StageTransition, error)
+	GetStageTransitionsByDevice(idDevice string) ([]models.StageTransition, error)
+}
+
+type stageHistoryRepository struct {
+	db pgDB
+}
+
+// NewStageHistoryRepository creates a new stage history repository
+func NewStageHistoryRepository(db *sql.DB) StageHistoryRepository {
+	return &stageHistoryRepository{db: pgDB{db}}
+}
+
+// RecordStageTransition inserts one stage change
+func (r *stageHistoryRepository) RecordStageTransition(transition *models.StageTransition) error {
+	query := `
+		INSERT INTO stage_history_nodepath (prospect_num, id_device, from_stage, to_stage, source)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		transition.ProspectNum,
+		transition.IDDevice,
+		transition.FromStage,
+		transition.ToStage,
+		transition.Source,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to record stage transition")
+		return err
+	}
+
+	return nil
+}
+
+// GetStageHistory returns a prospect's stage journey, oldest first
+func (r *stageHistoryRepository) GetStageHistory(prospectNum, idDevice string) ([]models.StageTransition, error) {
+	return r.queryTransitions(`
+		SELECT id, prospect_num, id_device, from_stage, to_stage, source, created_at
+		FROM stage_history_nodepath
+		WHERE prospect_num = ? AND id_device = ?
+		ORDER BY created_at ASC, id ASC
+	`, prospectNum, idDevice)
+}
+
+// GetStageTransitionsByDevice returns every transition for a device, grouped
+// by prospect in chronological order, for time-in-stage reports
+func (r *stageHistoryRepository) GetStageTransitionsByDevice(idDevice string) ([]models.StageTransition, error) {
+	return r.queryTransitions(`
+		SELECT id, prospect_num, id_device, from_stage, to_stage, source, created_at
+		FROM stage_history_nodepath
+		WHERE id_device = ?
+		ORDER BY prospect_num ASC, created_at ASC, id ASC
+	`, idDevice)
+}
+
+func (r *stageHistoryRepository) queryTransitions(query string, args ...interface{}) ([]models.StageTransition, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get stage transitions")
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := make([]models.StageTransition, 0)
+	for rows.Next() {
+		var transition models.StageTransition
+		if err := rows.Scan(
+			&transition.ID,
+			&transition.ProspectNum,
+			&transition.IDDevice,
+			&transition.FromStage,
+			&transition.ToStage,
+			&transition.Source,
+			&transition.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan stage transition row")
+			continue
+		}
+		transitions = append(transitions, transition)
+	}
+
+	return transitions, rows.Err()
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UnknownPayloadRepository handles database operations for the unknown webhook payload store
+type UnknownPayloadRepository interface {
+	CreateUnknownPayload(idDevice, provider, missingFields, payloadMasked string) error
+	GetUnknownPayloads(provider string, limit int) ([]models.UnknownWebhookPayload, error)
+}
+
+type unknownPayloadRepository struct {
+	db pgDB
+}
+
+// NewUnknownPayloadRepository creates a new unknown payload repository
+func NewUnknownPayloadRepository(db *sql.DB) UnknownPayloadRepository {
+	return &unknownPayloadRepository{db: pgDB{db}}
+}
+
+// CreateUnknownPayload stores one masked payload that failed field extraction
+func (r *unknownPayloadRepository) CreateUnknownPayload(idDevice, provider, missingFields, payloadMasked string) error {
+	query := `
+		INSERT INTO unknown_webhook_payload_nodepath (id_device, provider, missing_fields, payload_masked)
+		VALUES (?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, idDevice, provider, missingFields, payloadMasked)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to store unknown webhook payload")
+		return err
+	}
+
+	return nil
+}
+
+// GetUnknownPayloads returns the most recent captured payloads, optionally filtered by provider
+func (r *unknownPayloadRepository) GetUnknownPayloads(provider string, limit int) ([]models.UnknownWebhookPayload, error) {
+	query := `
+		SELECT id, id_device, provider, missing_fields, payload_masked, created_at
+		FROM unknown_webhook_payload_nodepath
+	`
+	args := []interface{}{}
+	if provider != "" {
+		query += " WHERE provider = ?"
+		args = append(args, provider)
+	}
+	query += " ORDER BY created_at DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get unknown webhook payloads")
+		return nil, err
+	}
+	defer rows.Close()
+
+	payloads := make([]models.UnknownWebhookPayload, 0)
+	for rows.Next() {
+		var payload models.UnknownWebhookPayload
+		if err := rows.Scan(
+			&payload.ID,
+			&payload.IDDevice,
+			&payload.Provider,
+			&payload.MissingFields,
+			&payload.PayloadMasked,
+			&payload.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan unknown webhook payload row")
+			continue
+		}
+		payloads = append(payloads, payload)
+	}
+
+	return payloads, rows.Err()
+}
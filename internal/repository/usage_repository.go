@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UsageRepository handles the aggregation queries behind per-tenant usage
+// dashboards. Everything is scoped to one user's devices
+type UsageRepository interface {
+	GetUserDeviceIDs(userID string) ([]string, error)
+	GetMessagesPerDay(userID string, days int) ([]models.DailyMessageCount, error)
+	GetActiveConversations(userID string) (int64, error)
+	GetTopFlows(userID string, limit int) ([]models.FlowUsageCount, error)
+}
+
+type usageRepository struct {
+	db pgDB
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *sql.DB) UsageRepository {
+	return &usageRepository{db: pgDB{db}}
+}
+
+// GetUserDeviceIDs lists the user's device IDs
+func (r *usageRepository) GetUserDeviceIDs(userID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT id_device FROM device_setting WHERE user_id = ?`, userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get user device IDs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	deviceIDs := make([]string, 0)
+	for rows.Next() {
+		var idDevice string
+		if err := rows.Scan(&idDevice); err != nil {
+			continue
+		}
+		deviceIDs = append(deviceIDs, idDevice)
+	}
+
+	return deviceIDs, rows.Err()
+}
+
+// GetMessagesPerDay counts inbound and outbound messages per day over the
+// user's devices, oldest day first
+func (r *usageRepository) GetMessagesPerDay(userID string, days int) ([]models.DailyMessageCount, error) {
+	query := `
+		SELECT to_char(c.created_at, 'YYYY-MM-DD') AS day,
+			SUM(CASE WHEN c.sender = 'user' THEN 1 ELSE 0 END) AS inbound,
+			SUM(CASE WHEN c.sender = 'bot' THEN 1 ELSE 0 END) AS outbound
+		FROM conversation_log c
+		JOIN device_setting d ON d.id_device = c.id_device
+		WHERE d.user_id = ? AND c.created_at >= CURRENT_DATE - (? * INTERVAL '1 day')
+		GROUP BY day
+		ORDER BY day ASC
+	`
+
+	rows, err := r.db.Query(query, userID, days)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get messages per day")
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]models.DailyMessageCount, 0)
+	for rows.Next() {
+		var count models.DailyMessageCount
+		if err := rows.Scan(&count.Date, &count.Inbound, &count.Outbound); err != nil {
+			logrus.WithError(err).Error("Failed to scan daily message count")
+			continue
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// GetActiveConversations counts conversations mid-flow on the user's devices
+func (r *usageRepository) GetActiveConversations(userID string) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM ai_whatsapp a
+		JOIN device_setting d ON d.id_device = a.id_device
+		WHERE d.user_id = ? AND a.execution_status = 'active'
+	`
+
+	var count int64
+	if err := r.db.QueryRow(query, userID).Scan(&count); err != nil {
+		logrus.WithError(err).Error("Failed to count active conversations")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetTopFlows ranks the user's flows by conversation count
+func (r *usageRepository) GetTopFlows(userID string, limit int) ([]models.FlowUsageCount, error) {
+	query := `
+		SELECT a.flow_id, COUNT(*) AS conversations
+		FROM ai_whatsapp a
+		JOIN device_setting d ON d.id_device = a.id_device
+		WHERE d.user_id = ? AND a.flow_id IS NOT NULL AND a.flow_id != ''
+		GROUP BY a.flow_id
+		ORDER BY conversations DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, userID, limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get top flows")
+		return nil, err
+	}
+	defer rows.Close()
+
+	flows := make([]models.FlowUsageCount, 0)
+	for rows.Next() {
+		var flow models.FlowUsageCount
+		if err := rows.Scan(&flow.FlowID, &flow.Conversations); err != nil {
+			logrus.WithError(err).Error("Failed to scan flow usage count")
+			continue
+		}
+		flows = append(flows, flow)
+	}
+
+	return flows, rows.Err()
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UserRoleRepository stores per-user access roles in user_role_nodepath.
+// Users without a row keep the historical full-access behavior
+type UserRoleRepository interface {
+	GetRole(userID string) (string, error)
+	SetRole(userID, role string) error
+	HasAnyAdmin() (bool, error)
+}
+
+type userRoleRepository struct {
+	db pgDB
+}
+
+// NewUserRoleRepository creates a new user role repository
+func NewUserRoleRepository(db *sql.DB) UserRoleRepository {
+	return &userRoleRepository{db: pgDB{db}}
+}
+
+// GetRole returns the user's role, or empty when none is assigned
+func (r *userRoleRepository) GetRole(userID string) (string, error) {
+	query := `SELECT role FROM user_role_nodepath WHERE user_id = ?`
+	var role string
+	err := r.db.QueryRow(query, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+	return role, nil
+}
+
+// HasAnyAdmin reports whether any user has been assigned the admin role yet
+func (r *userRoleRepository) HasAnyAdmin() (bool, error) {
+	query := `SELECT 1 FROM user_role_nodepath WHERE role = 'admin' LIMIT 1`
+	var one int
+	err := r.db.QueryRow(query).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check for assigned admins: %w", err)
+	}
+	return true, nil
+}
+
+// SetRole assigns or replaces the user's role
+func (r *userRoleRepository) SetRole(userID, role string) error {
+	query := `
+		INSERT INTO user_role_nodepath (user_id, role, updated_at)
+		VALUES (?, ?, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = NOW()
+	`
+	_, err := r.db.Exec(query, userID, role)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set user role")
+		return err
+	}
+	return nil
+}
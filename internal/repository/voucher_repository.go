@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// VoucherRepository handles database operations for voucher codes
+type VoucherRepository interface {
+	CreateVoucher(voucher *models.Voucher) error
+	GetVoucherByCode(code string) (*models.Voucher, error)
+	GetVouchersByDevice(idDevice, campaign string) ([]models.Voucher, error)
+	IncrementRedemption(code string) (bool, error)
+	RecordRedemption(code, prospectNum, source string) error
+}
+
+type voucherRepository struct {
+	db pgDB
+}
+
+// NewVoucherRepository creates a new voucher repository
+func NewVoucherRepository(db *sql.DB) VoucherRepository {
+	return &voucherRepository{db: pgDB{db}}
+}
+
+// CreateVoucher inserts a new voucher code
+func (r *voucherRepository) CreateVoucher(voucher *models.Voucher) error {
+	query := `
+		INSERT INTO voucher_nodepath (id, id_device, code, campaign, discount, issued_to, max_redemptions, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		voucher.ID,
+		voucher.IDDevice,
+		voucher.Code,
+		voucher.Campaign,
+		voucher.Discount,
+		voucher.IssuedTo,
+		voucher.MaxRedemptions,
+		voucher.ExpiresAt,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create voucher")
+		return err
+	}
+
+	return nil
+}
+
+// GetVoucherByCode returns a voucher, or nil when the code does not exist
+func (r *voucherRepository) GetVoucherByCode(code string) (*models.Voucher, error) {
+	query := `
+		SELECT id, id_device, code, campaign, discount, issued_to, max_redemptions, redemption_count, expires_at, created_at
+		FROM voucher_nodepath
+		WHERE code = ?
+	`
+
+	var voucher models.Voucher
+	err := r.db.QueryRow(query, code).Scan(
+		&voucher.ID,
+		&voucher.IDDevice,
+		&voucher.Code,
+		&voucher.Campaign,
+		&voucher.Discount,
+		&voucher.IssuedTo,
+		&voucher.MaxRedemptions,
+		&voucher.RedemptionCount,
+		&voucher.ExpiresAt,
+		&voucher.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get voucher")
+		return nil, err
+	}
+
+	return &voucher, nil
+}
+
+// GetVouchersByDevice returns a device's vouchers, optionally filtered by campaign
+func (r *voucherRepository) GetVouchersByDevice(idDevice, campaign string) ([]models.Voucher, error) {
+	query := `
+		SELECT id, id_device, code, campaign, discount, issued_to, max_redemptions, redemption_count, expires_at, created_at
+		FROM voucher_nodepath
+		WHERE id_device = ?
+	`
+	args := []interface{}{idDevice}
+	if campaign != "" {
+		query += " AND campaign = ?"
+		args = append(args, campaign)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get vouchers")
+		return nil, err
+	}
+	defer rows.Close()
+
+	vouchers := make([]models.Voucher, 0)
+	for rows.Next() {
+		var voucher models.Voucher
+		if err := rows.Scan(
+			&voucher.ID,
+			&voucher.IDDevice,
+			&voucher.Code,
+			&voucher.Campaign,
+			&voucher.Discount,
+			&voucher.IssuedTo,
+			&voucher.MaxRedemptions,
+			&voucher.RedemptionCount,
+			&voucher.ExpiresAt,
+			&voucher.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan voucher row")
+			continue
+		}
+		vouchers = append(vouchers, voucher)
+	}
+
+	return vouchers, rows.Err()
+}
+
+// IncrementRedemption atomically consumes one use of a code. Returns false
+// when the usage limit was already reached, so concurrent redemptions cannot
+// overshoot the limit
+func (r *voucherRepository) IncrementRedemption(code string) (bool, error) {
+	query := `
+		UPDATE voucher_nodepath
+		SET redemption_count = redemption_count + 1
+		WHERE code = ? AND redemption_count < max_redemptions
+	`
+
+	result, err := r.db.Exec(query, code)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to increment voucher redemption")
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// RecordRedemption logs one use of a code for campaign auditing
+func (r *voucherRepository) RecordRedemption(code, prospectNum, source string) error {
+	query := `
+		INSERT INTO voucher_redemption_nodepath (code, prospect_num, source)
+		VALUES (?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, code, prospectNum, source)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to record voucher redemption")
+		return err
+	}
+
+	return nil
+}
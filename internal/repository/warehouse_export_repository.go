@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WarehouseExportRepository handles database operations for analytics
+// warehouse export connectors
+type WarehouseExportRepository interface {
+	GetWarehouseExportConfig(idDevice string) (*models.WarehouseExportConfig, error)
+	SetWarehouseExportConfig(config *models.WarehouseExportConfig) error
+	GetEnabledWarehouseExportConfigs() ([]models.WarehouseExportConfig, error)
+	GetProspectsForBackfill(idDevice string) ([]models.WarehouseProspectSnapshot, error)
+}
+
+type warehouseExportRepository struct {
+	db pgDB
+}
+
+// NewWarehouseExportRepository creates a new warehouse export repository
+func NewWarehouseExportRepository(db *sql.DB) WarehouseExportRepository {
+	return &warehouseExportRepository{db: pgDB{db}}
+}
+
+// GetWarehouseExportConfig returns a device's connector, or nil when never set
+func (r *warehouseExportRepository) GetWarehouseExportConfig(idDevice string) (*models.WarehouseExportConfig, error) {
+	query := `
+		SELECT id_device, endpoint, auth_header, enabled, created_at
+		FROM warehouse_export_nodepath
+		WHERE id_device = ?
+	`
+
+	var config models.WarehouseExportConfig
+	err := r.db.QueryRow(query, idDevice).Scan(
+		&config.IDDevice,
+		&config.Endpoint,
+		&config.AuthHeader,
+		&config.Enabled,
+		&config.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get warehouse export config")
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// SetWarehouseExportConfig saves a device's connector
+func (r *warehouseExportRepository) SetWarehouseExportConfig(config *models.WarehouseExportConfig) error {
+	query := `
+		INSERT INTO warehouse_export_nodepath (id_device, endpoint, auth_header, enabled)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (id_device) DO UPDATE SET
+			endpoint = EXCLUDED.endpoint,
+			auth_header = EXCLUDED.auth_header,
+			enabled = EXCLUDED.enabled
+	`
+
+	_, err := r.db.Exec(query,
+		config.IDDevice,
+		config.Endpoint,
+		config.AuthHeader,
+		config.Enabled,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set warehouse export config")
+		return err
+	}
+
+	return nil
+}
+
+// GetEnabledWarehouseExportConfigs returns every enabled connector for the
+// flush loop
+func (r *warehouseExportRepository) GetEnabledWarehouseExportConfigs() ([]models.WarehouseExportConfig, error) {
+	query := `
+		SELECT id_device, endpoint, auth_header, enabled, created_at
+		FROM warehouse_export_nodepath
+		WHERE enabled = TRUE
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get enabled warehouse export configs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	configs := make([]models.WarehouseExportConfig, 0)
+	for rows.Next() {
+		var config models.WarehouseExportConfig
+		if err := rows.Scan(
+			&config.IDDevice,
+			&config.Endpoint,
+			&config.AuthHeader,
+			&config.Enabled,
+			&config.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan warehouse export config row")
+			continue
+		}
+		configs = append(configs, config)
+	}
+
+	return configs, rows.Err()
+}
+
+// GetProspectsForBackfill returns a device's prospects as snapshot rows so an
+// export can be backfilled with pre-connector history
+func (r *warehouseExportRepository) GetProspectsForBackfill(idDevice string) ([]models.WarehouseProspectSnapshot, error) {
+	query := `
+		SELECT COALESCE(prospect_num, ''), COALESCE(prospect_name, ''),
+			COALESCE(stage, ''), COALESCE(execution_status, ''),
+			COALESCE(niche, ''),
+			COALESCE(to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), ''),
+			COALESCE(to_char(updated_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), '')
+		FROM ai_whatsapp
+		WHERE id_device = ?
+	`
+
+	rows, err := r.db.Query(query, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get prospects for warehouse backfill")
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make([]models.WarehouseProspectSnapshot, 0)
+	for rows.Next() {
+		var snapshot models.WarehouseProspectSnapshot
+		if err := rows.Scan(
+			&snapshot.ProspectNum,
+			&snapshot.ProspectName,
+			&snapshot.Stage,
+			&snapshot.ExecutionStatus,
+			&snapshot.Niche,
+			&snapshot.CreatedAt,
+			&snapshot.UpdatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan prospect snapshot row")
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
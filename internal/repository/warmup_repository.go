@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WarmupRepository handles database operations for warm-up profiles and daily
+// send counters
+type WarmupRepository interface {
+	GetWarmupProfile(idDevice string) (*models.WarmupProfile, error)
+	SetWarmupProfile(profile *models.WarmupProfile) error
+	GetDailySendCount(idDevice string, date time.Time) (int, error)
+	IncrementDailySendCount(idDevice string, date time.Time) error
+}
+
+type warmupRepository struct {
+	db pgDB
+}
+
+// NewWarmupRepository creates a new warmup repository
+func NewWarmupRepository(db *sql.DB) WarmupRepository {
+	return &warmupRepository{db: pgDB{db}}
+}
+
+// GetWarmupProfile returns a device's warm-up profile, or nil when never set
+func (r *warmupRepository) GetWarmupProfile(idDevice string) (*models.WarmupProfile, error) {
+	query := `
+		SELECT id_device, enabled, started_at, base_cap, growth_per_day, max_cap
+		FROM warmup_profile_nodepath
+		WHERE id_device = ?
+	`
+
+	var profile models.WarmupProfile
+	err := r.db.QueryRow(query, idDevice).Scan(
+		&profile.IDDevice,
+		&profile.Enabled,
+		&profile.StartedAt,
+		&profile.BaseCap,
+		&profile.GrowthPerDay,
+		&profile.MaxCap,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get warmup profile")
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// SetWarmupProfile saves a device's warm-up profile
+func (r *warmupRepository) SetWarmupProfile(profile *models.WarmupProfile) error {
+	query := `
+		INSERT INTO warmup_profile_nodepath (id_device, enabled, started_at, base_cap, growth_per_day, max_cap)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id_device) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			started_at = EXCLUDED.started_at,
+			base_cap = EXCLUDED.base_cap,
+			growth_per_day = EXCLUDED.growth_per_day,
+			max_cap = EXCLUDED.max_cap
+	`
+
+	_, err := r.db.Exec(query,
+		profile.IDDevice,
+		profile.Enabled,
+		profile.StartedAt.Format("2006-01-02"),
+		profile.BaseCap,
+		profile.GrowthPerDay,
+		profile.MaxCap,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to set warmup profile")
+		return err
+	}
+
+	return nil
+}
+
+// GetDailySendCount returns how many proactive sends a device used on a date
+func (r *warmupRepository) GetDailySendCount(idDevice string, date time.Time) (int, error) {
+	query := `SELECT sent_count FROM daily_send_count_nodepath WHERE id_device = ? AND send_date = ?`
+
+	var count int
+	err := r.db.QueryRow(query, idDevice, date.Format("2006-01-02")).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get daily send count")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// IncrementDailySendCount counts one proactive send on a date
+func (r *warmupRepository) IncrementDailySendCount(idDevice string, date time.Time) error {
+	query := `
+		INSERT INTO daily_send_count_nodepath (id_device, send_date, sent_count)
+		VALUES (?, ?, 1)
+		ON CONFLICT (id_device, send_date) DO UPDATE SET sent_count = daily_send_count_nodepath.sent_count + 1
+	`
+
+	_, err := r.db.Exec(query, idDevice, date.Format("2006-01-02"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to increment daily send count")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookSecretRepository handles database operations for per-device webhook
+// signing secrets
+type WebhookSecretRepository interface {
+	SetSecret(idDevice, secret string) error
+	GetSecret(idDevice string) (string, error)
+	DeleteSecret(idDevice string) error
+}
+
+type webhookSecretRepository struct {
+	db pgDB
+}
+
+// NewWebhookSecretRepository creates a new webhook secret repository
+func NewWebhookSecretRepository(db *sql.DB) WebhookSecretRepository {
+	return &webhookSecretRepository{db: pgDB{db}}
+}
+
+// SetSecret stores or replaces the webhook signing secret for a device
+func (r *webhookSecretRepository) SetSecret(idDevice, secret string) error {
+	query := `
+		INSERT INTO webhook_secret_nodepath (id_device, secret, created_at, updated_at)
+		VALUES (?, ?, NOW(), NOW())
+		ON CONFLICT (id_device) DO UPDATE SET secret = EXCLUDED.secret, updated_at = NOW()
+	`
+
+	_, err := r.db.Exec(query, idDevice, secret)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to store webhook secret")
+		return err
+	}
+
+	logrus.WithField("id_device", idDevice).Info("✅ Webhook secret updated")
+	return nil
+}
+
+// GetSecret returns the device's webhook secret, or "" when none is configured
+func (r *webhookSecretRepository) GetSecret(idDevice string) (string, error) {
+	query := `SELECT secret FROM webhook_secret_nodepath WHERE id_device = ?`
+
+	var secret string
+	err := r.db.QueryRow(query, idDevice).Scan(&secret)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		// A missing table means the feature was never provisioned, not that
+		// a secret exists: treat it as "no secret configured" so inbound
+		// webhooks keep flowing. Real infrastructure errors still surface so
+		// callers can fail closed
+		if isUndefinedTableError(err) {
+			logrus.Warn("webhook_secret_nodepath table missing, treating all devices as unsigned")
+			return "", nil
+		}
+		logrus.WithError(err).Error("Failed to get webhook secret")
+		return "", err
+	}
+	return secret, nil
+}
+
+// isUndefinedTableError reports whether err is PostgreSQL undefined_table
+func isUndefinedTableError(err error) bool {
+	if pqErr, ok := err.(*pq.Error); ok {
+		return pqErr.Code == "42P01"
+	}
+	return false
+}
+
+// DeleteSecret removes the device's webhook secret, disabling verification
+func (r *webhookSecretRepository) DeleteSecret(idDevice string) error {
+	_, err := r.db.Exec(`DELETE FROM webhook_secret_nodepath WHERE id_device = ?`, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete webhook secret")
+		return err
+	}
+
+	logrus.WithField("id_device", idDevice).Info("✅ Webhook secret removed")
+	return nil
+}
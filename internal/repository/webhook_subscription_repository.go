@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"database/sql"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookSubscriptionRepository handles database operations for outbound
+// webhook subscriptions
+type WebhookSubscriptionRepository interface {
+	CreateWebhookSubscription(subscription *models.WebhookSubscription) error
+	GetWebhookSubscriptionsByDevice(idDevice string) ([]models.WebhookSubscription, error)
+	GetEnabledWebhookSubscriptions(idDevice string) ([]models.WebhookSubscription, error)
+	SetWebhookSubscriptionEnabled(id, idDevice string, enabled bool) error
+	DeleteWebhookSubscription(id, idDevice string) error
+}
+
+type webhookSubscriptionRepository struct {
+	db pgDB
+}
+
+// NewWebhookSubscriptionRepository creates a new webhook subscription repository
+func NewWebhookSubscriptionRepository(db *sql.DB) WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{db: pgDB{db}}
+}
+
+// CreateWebhookSubscription inserts a new subscription
+func (r *webhookSubscriptionRepository) CreateWebhookSubscription(subscription *models.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscription_nodepath (id, id_device, url, events, secret, enabled)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		subscription.ID,
+		subscription.IDDevice,
+		subscription.URL,
+		subscription.Events,
+		subscription.Secret,
+		subscription.Enabled,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create webhook subscription")
+		return err
+	}
+
+	return nil
+}
+
+// GetWebhookSubscriptionsByDevice returns all of a device's subscriptions
+func (r *webhookSubscriptionRepository) GetWebhookSubscriptionsByDevice(idDevice string) ([]models.WebhookSubscription, error) {
+	return r.querySubscriptions(`
+		SELECT id, id_device, url, events, secret, enabled, created_at
+		FROM webhook_subscription_nodepath
+		WHERE id_device = ?
+		ORDER BY created_at DESC
+	`, idDevice)
+}
+
+// GetEnabledWebhookSubscriptions returns the device's enabled subscriptions
+func (r *webhookSubscriptionRepository) GetEnabledWebhookSubscriptions(idDevice string) ([]models.WebhookSubscription, error) {
+	return r.querySubscriptions(`
+		SELECT id, id_device, url, events, secret, enabled, created_at
+		FROM webhook_subscription_nodepath
+		WHERE id_device = ? AND enabled = TRUE
+	`, idDevice)
+}
+
+func (r *webhookSubscriptionRepository) querySubscriptions(query string, args ...interface{}) ([]models.WebhookSubscription, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get webhook subscriptions")
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]models.WebhookSubscription, 0)
+	for rows.Next() {
+		var subscription models.WebhookSubscription
+		if err := rows.Scan(
+			&subscription.ID,
+			&subscription.IDDevice,
+			&subscription.URL,
+			&subscription.Events,
+			&subscription.Secret,
+			&subscription.Enabled,
+			&subscription.CreatedAt,
+		); err != nil {
+			logrus.WithError(err).Error("Failed to scan webhook subscription row")
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// SetWebhookSubscriptionEnabled toggles a subscription, scoped to the owning device
+func (r *webhookSubscriptionRepository) SetWebhookSubscriptionEnabled(id, idDevice string, enabled bool) error {
+	query := `UPDATE webhook_subscription_nodepath SET enabled = ? WHERE id = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, enabled, id, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to toggle webhook subscription")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhookSubscription removes a subscription, scoped to the owning device
+func (r *webhookSubscriptionRepository) DeleteWebhookSubscription(id, idDevice string) error {
+	query := `DELETE FROM webhook_subscription_nodepath WHERE id = ? AND id_device = ?`
+
+	_, err := r.db.Exec(query, id, idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete webhook subscription")
+		return err
+	}
+
+	return nil
+}
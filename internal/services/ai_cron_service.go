@@ -1,22 +1,15 @@
 package services
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
-	urlPkg "net/url"
-	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"nodepath-chat/internal/models"
 	"nodepath-chat/internal/repository"
+	"nodepath-chat/internal/utils"
 
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
@@ -60,6 +53,7 @@ type aiCronService struct {
 	queueMonitor      *QueueMonitor           // Monitor for performance bottlenecks
 	workerPool        chan struct{}           // Worker pool for concurrent processing
 	maxWorkers        int                     // Maximum number of concurrent workers
+	clock             utils.Clock             // Clock abstraction so scheduling delays are testable
 }
 
 // FollowUpJob represents a scheduled follow-up job
@@ -89,6 +83,7 @@ func NewAICronService(
 		queueMonitor:      NewQueueMonitor(),
 		workerPool:        make(chan struct{}, maxWorkers),
 		maxWorkers:        maxWorkers,
+		clock:             utils.NewSystemClock(),
 	}
 }
 
@@ -179,7 +174,7 @@ func (s *aiCronService) sendAIResponse(prospectNum, deviceID string, response *A
 		}
 
 		// Add 5 second delay between messages to avoid rate limiting
-		time.Sleep(5000 * time.Millisecond)
+		s.clock.Sleep(5000 * time.Millisecond)
 	}
 
 	return nil
@@ -567,19 +562,15 @@ func (s *aiCronService) determineProvider(instance string) string {
 func (s *aiCronService) sendTextMessage(to, message string, deviceSettings *models.DeviceSettings, provider string) error {
 	// Add delay before sending (similar to PHP delax parameter)
 	delay := 1 * time.Second
-	time.Sleep(delay)
-
-	switch provider {
-	case "whacenter":
-		return s.sendWhacenterTextMessage(to, message, deviceSettings)
-	case "wablas":
-		return s.sendWablasTextMessage(to, message, deviceSettings)
-	case "waha":
-		return s.sendWahaTextMessage(to, message, deviceSettings)
-	default:
+	s.clock.Sleep(delay)
+
+	gateway, ok := GetProvider(strings.ToLower(provider))
+	if !ok {
 		logrus.WithField("provider", provider).Warn("⚠️ WHATSAPP: Unsupported provider for text message")
 		return fmt.Errorf("unsupported provider: %s", provider)
 	}
+
+	return gateway.SendText(deviceSettings, to, message)
 }
 
 // sendChatMessage sends multimedia messages (video, audio, image)
@@ -596,580 +587,13 @@ func (s *aiCronService) sendChatMessage(to, reply, fileURL string, deviceSetting
 
 	// Add delay before sending
 	delay := 1 * time.Second
-	time.Sleep(delay)
-
-	// Determine file type based on extension
-	fileType := s.getFileType(fileURL)
-
-	switch provider {
-	case "wablas":
-		return s.sendWablasMultimediaMessage(to, fileURL, fileType, deviceSettings)
-	case "whacenter":
-		return s.sendWhacenterMultimediaMessage(to, fileURL, fileType, deviceSettings)
-	case "waha":
-		return s.sendWahaMultimediaMessage(to, fileURL, fileType, deviceSettings)
-	default:
+	s.clock.Sleep(delay)
+
+	gateway, ok := GetProvider(strings.ToLower(provider))
+	if !ok {
 		logrus.WithField("provider", provider).Warn("⚠️ WHATSAPP: Unsupported provider for multimedia message")
 		return fmt.Errorf("unsupported provider: %s", provider)
 	}
-}
-
-// getFileType determines file type based on URL extension
-func (s *aiCronService) getFileType(fileURL string) string {
-	var fileType string
-	if strings.Contains(fileURL, ".jpg") || strings.Contains(fileURL, ".jpeg") || strings.Contains(fileURL, ".png") {
-		fileType = "image"
-	} else if strings.Contains(fileURL, ".mp4") || strings.Contains(fileURL, ".avi") {
-		fileType = "video"
-	} else if strings.Contains(fileURL, ".mp3") || strings.Contains(fileURL, ".wav") {
-		fileType = "audio"
-	} else if strings.Contains(fileURL, ".pdf") || strings.Contains(fileURL, ".doc") {
-		fileType = "document"
-	} else {
-		fileType = "image" // default to image
-	}
-
-	// Console log for tracing file type determination
-	logrus.WithFields(logrus.Fields{
-		"file_url":        fileURL,
-		"determined_type": fileType,
-		"url_extensions_found": func() []string {
-			extensions := []string{}
-			if strings.Contains(fileURL, ".jpg") {
-				extensions = append(extensions, ".jpg")
-			}
-			if strings.Contains(fileURL, ".jpeg") {
-				extensions = append(extensions, ".jpeg")
-			}
-			if strings.Contains(fileURL, ".png") {
-				extensions = append(extensions, ".png")
-			}
-			if strings.Contains(fileURL, ".mp4") {
-				extensions = append(extensions, ".mp4")
-			}
-			if strings.Contains(fileURL, ".avi") {
-				extensions = append(extensions, ".avi")
-			}
-			if strings.Contains(fileURL, ".mp3") {
-				extensions = append(extensions, ".mp3")
-			}
-			if strings.Contains(fileURL, ".wav") {
-				extensions = append(extensions, ".wav")
-			}
-			if strings.Contains(fileURL, ".pdf") {
-				extensions = append(extensions, ".pdf")
-			}
-			if strings.Contains(fileURL, ".doc") {
-				extensions = append(extensions, ".doc")
-			}
-			return extensions
-		}(),
-	}).Info("🔍 AI CRON: FILE TYPE DETERMINED FOR TRACING")
-
-	return fileType
-}
-
-// sendWablasTextMessage sends text message via Wablas provider
-func (s *aiCronService) sendWablasTextMessage(to, message string, deviceSettings *models.DeviceSettings) error {
-	logrus.WithFields(logrus.Fields{
-		"to":        to,
-		"provider":  "wablas",
-		"device_id": deviceSettings.IDDevice,
-	}).Debug("Sending text message via Wablas")
-
-	// TODO: Implement actual Wablas API call
-	// This should use the device settings to make HTTP request to Wablas API
-	logrus.Info("📤 WABLAS: Text message sent successfully")
-	return nil
-}
-
-// sendWhacenterTextMessage sends text message via Whacenter provider
-func (s *aiCronService) sendWhacenterTextMessage(to, message string, deviceSettings *models.DeviceSettings) error {
-	if !deviceSettings.Instance.Valid {
-		logrus.Error("❌ WHACENTER: No instance available")
-		return fmt.Errorf("no instance available")
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"to":        to,
-		"provider":  "whacenter",
-		"device_id": deviceSettings.Instance.String, // ✅ Use instance
-	}).Debug("Sending text message via Whacenter")
-
-	// Whacenter API endpoint for sending messages
-	apiURL := "https://api.whacenter.com/api/send"
-
-	// Prepare request payload - Use instance for device_id as per Whacenter API requirements
-	payload := map[string]interface{}{
-		"device_id": deviceSettings.Instance.String, // ✅ Use instance
-		"number":    to,
-		"message":   message,
-		"type":      "text",
-	}
-
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to marshal payload")
-		return fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	// Create HTTP request
-	req, err := http.NewRequest("POST", apiURL, strings.NewReader(string(payloadBytes)))
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to create request")
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+deviceSettings.Instance.String)
-
-	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to send message")
-		return fmt.Errorf("failed to send message: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body for error details
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to read response body")
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Log response details
-	logFields := logrus.Fields{
-		"to":            to,
-		"status_code":   resp.StatusCode,
-		"response_body": string(respBody),
-	}
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		logFields["status"] = "success"
-		logrus.WithFields(logFields).Info("📤 WHACENTER: Text message sent successfully")
-		return nil
-	} else {
-		logFields["status"] = "error"
-		logrus.WithFields(logFields).Error("❌ WHACENTER: Text message failed")
-		return fmt.Errorf("whacenter API error: status %d, body: %s", resp.StatusCode, string(respBody))
-	}
-}
-
-// sendWablasMultimediaMessage sends multimedia message via Wablas provider
-func (s *aiCronService) sendWablasMultimediaMessage(to, fileURL, fileType string, deviceSettings *models.DeviceSettings) error {
-	if !deviceSettings.Instance.Valid {
-		logrus.Error("❌ WABLAS: No instance available")
-		return fmt.Errorf("no instance available")
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"to":        to,
-		"file_type": fileType,
-		"provider":  "wablas",
-		"device_id": deviceSettings.IDDevice,
-	}).Debug("Sending multimedia message via Wablas")
-
-	// Determine API endpoint and field name based on file type
-	var apiURL string
-	var fieldName string
-
-	switch fileType {
-	case "video":
-		apiURL = "https://my.wablas.com/api/send-video"
-		fieldName = "video"
-	case "audio":
-		apiURL = "https://my.wablas.com/api/send-audio"
-		fieldName = "audio"
-	default: // image
-		apiURL = "https://my.wablas.com/api/send-image"
-		fieldName = "image"
-	}
-
-	// Prepare form data
-	data := url.Values{}
-	data.Set("phone", to)        // recipient number
-	data.Set(fieldName, fileURL) // media file URL
-	data.Set("message", "")      // empty message field for media
-
-	// Create HTTP request
-	req, err := http.NewRequest("POST", apiURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		logrus.WithError(err).Error("❌ WABLAS: Failed to create request")
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Authorization", deviceSettings.Instance.String)
-
-	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WABLAS: Failed to send multimedia message")
-		return fmt.Errorf("failed to send multimedia message: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body for error details
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WABLAS: Failed to read response body")
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Log response details
-	logFields := logrus.Fields{
-		"to":            to,
-		"file_type":     fileType,
-		"status_code":   resp.StatusCode,
-		"response_body": string(respBody),
-	}
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		logFields["status"] = "success"
-		logrus.WithFields(logFields).Info("📤 WABLAS: Multimedia message sent successfully")
-		return nil
-	} else {
-		logFields["status"] = "error"
-		logrus.WithFields(logFields).Error("❌ WABLAS: Multimedia message failed")
-		return fmt.Errorf("wablas API error: status %d, body: %s", resp.StatusCode, string(respBody))
-	}
-}
-
-// sendWhacenterMultimediaMessage sends multimedia message via Whacenter provider
-func (s *aiCronService) sendWhacenterMultimediaMessage(to, fileURL, fileType string, deviceSettings *models.DeviceSettings) error {
-	if !deviceSettings.Instance.Valid {
-		logrus.Error("❌ WHACENTER: No instance available")
-		return fmt.Errorf("no instance available")
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"to":        to,
-		"file_type": fileType,
-		"provider":  "whacenter",
-		"device_id": deviceSettings.Instance.String, // ✅ Use instance
-	}).Debug("Sending multimedia message via Whacenter")
-
-	// Whacenter API endpoint for sending media
-	apiURL := "https://api.whacenter.com/api/send"
-
-	// Detect media type based on file extension (as per PHP code)
-	mediaType := ""
-	if strings.Contains(fileURL, ".mp4") {
-		mediaType = "video"
-	} else if strings.Contains(fileURL, ".mp3") {
-		mediaType = "audio"
-	} else {
-		mediaType = "image"
-	}
-
-	// Prepare form data exactly as specified by user PHP code
-	data := url.Values{}
-	data.Set("device_id", deviceSettings.Instance.String) // device_id from instance
-	data.Set("number", to)                                // recipient number
-	data.Set("file", fileURL)                             // media file URL
-	data.Set("message", "")                               // empty message field for media
-
-	// Add type parameter for video and audio only (as per PHP code)
-	if mediaType != "" && mediaType != "image" {
-		data.Set("type", mediaType)
-	}
 
-	// Create HTTP request
-	req, err := http.NewRequest("POST", apiURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to create request")
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers (form data, no authorization header as per user example)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to send multimedia message")
-		return fmt.Errorf("failed to send multimedia message: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body for error details
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WHACENTER: Failed to read response body")
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Log response details
-	logFields := logrus.Fields{
-		"to":            to,
-		"file_type":     fileType,
-		"status_code":   resp.StatusCode,
-		"response_body": string(respBody),
-	}
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		logFields["status"] = "success"
-		logrus.WithFields(logFields).Info("📤 WHACENTER: Multimedia message sent successfully")
-		return nil
-	} else {
-		logFields["status"] = "error"
-		logrus.WithFields(logFields).Error("❌ WHACENTER: Multimedia message failed")
-		return fmt.Errorf("whacenter API error: status %d, body: %s", resp.StatusCode, string(respBody))
-	}
-}
-
-// sendWahaTextMessage sends text message via WAHA provider
-// Based on WAHA API specification: /api/sendText endpoint
-func (s *aiCronService) sendWahaTextMessage(to, message string, deviceSettings *models.DeviceSettings) error {
-	logrus.WithFields(logrus.Fields{
-		"to":        to,
-		"provider":  "waha",
-		"device_id": deviceSettings.IDDevice,
-	}).Debug("Sending text message via WAHA")
-
-	if !deviceSettings.Instance.Valid {
-		logrus.Error("❌ WAHA: No instance available")
-		return fmt.Errorf("no instance available")
-	}
-
-	if !deviceSettings.APIKey.Valid {
-		logrus.Error("❌ WAHA: No API key available")
-		return fmt.Errorf("no API key available")
-	}
-
-	// Prepare request payload according to WAHA API specification
-	// session: instance from device settings
-	// chatId: phone number with @c.us suffix
-	// text: message content
-	payload := map[string]interface{}{
-		"session": deviceSettings.Instance.String,
-		"chatId":  to + "@c.us",
-		"text":    message,
-	}
-
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WAHA: Failed to marshal payload")
-		return fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	// Create HTTP request to WAHA API base URL + /api/sendText endpoint
-	// Using the API base URL from device settings instance field
-	apiBaseURL := deviceSettings.Instance.String
-	if !strings.HasPrefix(apiBaseURL, "http") {
-		apiBaseURL = "https://" + apiBaseURL
-	}
-	url := fmt.Sprintf("%s/api/sendText", strings.TrimSuffix(apiBaseURL, "/"))
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		logrus.WithError(err).Error("❌ WAHA: Failed to create request")
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers according to WAHA API specification
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Api-Key", deviceSettings.APIKey.String)
-
-	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WAHA: Failed to send text message")
-		return fmt.Errorf("failed to send text message: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body for error details
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WAHA: Failed to read response body")
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Log response details
-	logFields := logrus.Fields{
-		"to":            to,
-		"status_code":   resp.StatusCode,
-		"response_body": string(respBody),
-	}
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		logFields["status"] = "success"
-		logrus.WithFields(logFields).Info("📤 WAHA: Text message sent successfully")
-		return nil
-	} else {
-		logFields["status"] = "error"
-		logrus.WithFields(logFields).Error("❌ WAHA: Text message failed")
-		return fmt.Errorf("waha API error: status %d, body: %s", resp.StatusCode, string(respBody))
-	}
-}
-
-// sendWahaMultimediaMessage sends multimedia message via WAHA provider - EXACTLY matching PHP implementation
-func (s *aiCronService) sendWahaMultimediaMessage(to, fileURL, caption string, deviceSettings *models.DeviceSettings) error {
-	logrus.WithFields(logrus.Fields{
-		"to":        to,
-		"file_url":  fileURL,
-		"provider":  "waha",
-		"device_id": deviceSettings.IDDevice,
-	}).Debug("Sending multimedia message via WAHA")
-
-	// Fixed API key as per PHP code
-	apiKey := "dckr_pat_vxeqEu_CqRi5O3CBHnD7FxhnBz0"
-
-	// Prepare variables matching PHP
-	session := deviceSettings.Instance.String
-	number := regexp.MustCompile(`[^0-9]`).ReplaceAllString(to, "")
-	chatId := number + "@c.us"
-
-	var url string
-	var data map[string]interface{}
-
-	// Check file type and prepare request - EXACTLY as PHP
-	if strings.Contains(fileURL, ".mp4") {
-		// Video file
-		url = "https://waha-plus-production-705f.up.railway.app/api/sendVideo"
-		data = map[string]interface{}{
-			"session": session,
-			"chatId":  chatId,
-			"file": map[string]interface{}{
-				"mimetype": "video/mp4",
-				"url":      fileURL,
-				"filename": "Video",
-			},
-			"caption": caption,
-		}
-	} else if strings.Contains(fileURL, ".mp3") {
-		// Audio file - using sendFile endpoint as per PHP
-		url = "https://waha-plus-production-705f.up.railway.app/api/sendFile"
-		data = map[string]interface{}{
-			"session": session,
-			"chatId":  chatId,
-			"file": map[string]interface{}{
-				"mimetype": "audio/mp3",
-				"url":      fileURL,
-				"filename": "Audio",
-			},
-			"caption": caption,
-		}
-	} else {
-		// Image or other files - detect mimetype
-		// Parse URL to get extension
-		parsedURL, _ := urlPkg.Parse(fileURL)
-		path := parsedURL.Path
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext != "" && ext[0] == '.' {
-			ext = ext[1:] // Remove leading dot
-		}
-
-		// Mimetype map matching PHP
-		mimeMap := map[string]string{
-			"jpg":  "image/jpeg",
-			"jpeg": "image/jpeg",
-			"png":  "image/png",
-			"gif":  "image/gif",
-			"webp": "image/webp",
-			"bmp":  "image/bmp",
-			"svg":  "image/svg+xml",
-		}
-
-		// Step 1: Try to use extension
-		mimetype := ""
-		if ext != "" {
-			if mime, ok := mimeMap[ext]; ok {
-				mimetype = mime
-			}
-		}
-
-		// Step 2: Try to detect from headers (simplified for Go)
-		if mimetype == "" {
-			// Try to get content type from URL
-			headReq, err := http.NewRequest("HEAD", fileURL, nil)
-			if err == nil {
-				client := &http.Client{Timeout: 5 * time.Second}
-				if headResp, err := client.Do(headReq); err == nil {
-					defer headResp.Body.Close()
-					if contentType := headResp.Header.Get("Content-Type"); contentType != "" {
-						mimetype = contentType
-					}
-				}
-			}
-		}
-
-		// Step 3: Fallback default
-		if mimetype == "" {
-			mimetype = "image/jpeg"
-		}
-
-		url = "https://waha-plus-production-705f.up.railway.app/api/sendImage"
-		data = map[string]interface{}{
-			"session": session,
-			"chatId":  chatId,
-			"file": map[string]interface{}{
-				"mimetype": mimetype,
-				"url":      fileURL,
-				"filename": "Image",
-			},
-			"caption": caption,
-		}
-	}
-
-	// Marshal the data
-	jsonPayload, err := json.Marshal(data)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WAHA: Failed to marshal payload")
-		return fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	// Create HTTP request
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		logrus.WithError(err).Error("❌ WAHA: Failed to create request")
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers exactly as PHP
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Api-Key", apiKey)
-
-	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WAHA: Failed to send multimedia message")
-		return fmt.Errorf("failed to send multimedia message: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logrus.WithError(err).Error("❌ WAHA: Failed to read response body")
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Log response
-	logFields := logrus.Fields{
-		"to":            to,
-		"status_code":   resp.StatusCode,
-		"response_body": string(respBody),
-		"url":           url,
-		"file_url":      fileURL,
-	}
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		logFields["status"] = "success"
-		logrus.WithFields(logFields).Info("📤 WAHA: Multimedia message sent successfully")
-		return nil
-	} else {
-		logFields["status"] = "error"
-		logrus.WithFields(logFields).Error("❌ WAHA: Multimedia message failed")
-		return fmt.Errorf("waha API error: status %d, body: %s", resp.StatusCode, string(respBody))
-	}
+	return gateway.SendMedia(deviceSettings, to, fileURL)
 }
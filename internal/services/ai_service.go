@@ -16,6 +16,7 @@ import (
 	"nodepath-chat/internal/config"
 	"nodepath-chat/internal/models"
 	"nodepath-chat/internal/repository"
+	"nodepath-chat/internal/utils"
 
 	"github.com/sirupsen/logrus"
 )
@@ -59,6 +60,11 @@ type AIService struct {
 	circuitBreaker *CircuitBreaker
 	// Advanced rate limiter for API calls
 	rateLimiter *APIRateLimiter
+	// Clock abstraction so retry backoff is testable
+	clock utils.Clock
+	// Per-device daily usage buckets for digest reporting
+	usage    map[string]map[string]*aiUsageDay
+	usageMux sync.Mutex
 }
 
 // NewAIService creates a new AI service with performance optimizations
@@ -85,6 +91,8 @@ func NewAIService(cfg *config.Config, deviceRepo repository.DeviceSettingsReposi
 		semaphore:      make(chan struct{}, 100), // Limit concurrent AI requests
 		circuitBreaker: &CircuitBreaker{},        // Initialize circuit breaker
 		rateLimiter:    rateLimiter,              // Initialize rate limiter
+		clock:          utils.NewSystemClock(),   // Real clock; swapped for a fake in tests
+		usage:          make(map[string]map[string]*aiUsageDay),
 	}
 }
 
@@ -94,8 +102,32 @@ func maskAPIKey(apiKey string) string {
 	return apiKey
 }
 
-// GenerateResponse generates an AI response using OpenRouter with caching and concurrency control
-func (s *AIService) GenerateResponse(systemPrompt, userInput, apiKey, deviceID string, conversationHistory []models.ConversationMessage) (string, error) {
+// AIGenerationParams overrides the default generation settings for one AI
+// call. Zero values fall back to the device-level defaults: the model from
+// the device's api_key_option, temperature 0.67, top_p 1.0 and no token cap
+type AIGenerationParams struct {
+	Model       string
+	Temperature float64
+	TopP        float64
+	MaxTokens   int
+}
+
+// fingerprint folds the overrides into the response cache key so calls with
+// different settings never share a cached answer
+func (p *AIGenerationParams) fingerprint() string {
+	return fmt.Sprintf("model=%s|temp=%g|top_p=%g|max_tokens=%d", p.Model, p.Temperature, p.TopP, p.MaxTokens)
+}
+
+// GenerateResponse generates an AI response using OpenRouter with caching and concurrency control.
+// Optional image URLs are attached to the user message and routed through a vision-capable model
+func (s *AIService) GenerateResponse(systemPrompt, userInput, apiKey, deviceID string, conversationHistory []models.ConversationMessage, imageURLs ...string) (string, error) {
+	return s.GenerateResponseWithParams(systemPrompt, userInput, apiKey, deviceID, conversationHistory, nil, imageURLs...)
+}
+
+// GenerateResponseWithParams is GenerateResponse with per-call generation
+// overrides, used by AI prompt nodes that configure their own model,
+// temperature, top_p or max_tokens
+func (s *AIService) GenerateResponseWithParams(systemPrompt, userInput, apiKey, deviceID string, conversationHistory []models.ConversationMessage, params *AIGenerationParams, imageURLs ...string) (string, error) {
 	// Use device-specific API key logic
 	apiKey = s.getAPIKey(apiKey, deviceID)
 
@@ -103,6 +135,12 @@ func (s *AIService) GenerateResponse(systemPrompt, userInput, apiKey, deviceID s
 		return "", fmt.Errorf("no API key provided")
 	}
 
+	// Image attachments need multimodal messages, which the standard request
+	// shape cannot carry - route them through the vision path
+	if len(imageURLs) > 0 {
+		return s.generateVisionResponse(systemPrompt, userInput, apiKey, deviceID, conversationHistory, params, imageURLs)
+	}
+
 	// 🔍 DEBUG TRACE: Log final API key state
 	logrus.WithFields(logrus.Fields{
 		"device_id":                  deviceID,
@@ -114,6 +152,9 @@ func (s *AIService) GenerateResponse(systemPrompt, userInput, apiKey, deviceID s
 
 	// Check cache first
 	cacheKey := s.generateCacheKey(systemPrompt, userInput, conversationHistory)
+	if params != nil {
+		cacheKey = cacheKey + "|" + params.fingerprint()
+	}
 	if cachedResponse := s.getCachedResponse(cacheKey); cachedResponse != "" {
 		logrus.Debug("Returning cached AI response")
 		return cachedResponse, nil
@@ -130,17 +171,42 @@ func (s *AIService) GenerateResponse(systemPrompt, userInput, apiKey, deviceID s
 	// Build messages for OpenRouter
 	messages := s.buildMessages(systemPrompt, userInput, conversationHistory)
 
-	// Create request with PHP payload structure parameters and device-specific model
+	// Resolve generation settings: node-level overrides win, otherwise the
+	// device-level defaults apply
+	model := s.getAIModel(deviceID) // Use device-specific model selection
+	temperature := 0.67             // Recommended setting from PHP code
+	topP := 1.0                     // Keep responses within natural probability range
+	maxTokens := 0
+	if params != nil {
+		if params.Model != "" {
+			model = params.Model
+		}
+		if params.Temperature > 0 {
+			temperature = params.Temperature
+		}
+		if params.TopP > 0 {
+			topP = params.TopP
+		}
+		if params.MaxTokens > 0 {
+			maxTokens = params.MaxTokens
+		}
+	}
+
+	// Create request with PHP payload structure parameters
 	request := models.OpenRouterRequest{
-		Model:             s.getAIModel(deviceID), // Use device-specific model selection
+		Model:             model,
 		Messages:          messages,
 		Stream:            false,
-		Temperature:       0.67, // Recommended setting from PHP code
-		TopP:              1.0,  // Keep responses within natural probability range
-		RepetitionPenalty: 1.0,  // Avoid repetitive responses
+		Temperature:       temperature,
+		TopP:              topP,
+		MaxTokens:         maxTokens,
+		RepetitionPenalty: 1.0, // Avoid repetitive responses
 	}
 
-	// Make API call with retries
+	// Make API call with retries, timed under the message's trace
+	aiSpan := Tracing().StartChild("ai.generate", DeviceTraceKey(deviceID))
+	aiSpan.SetAttribute("model", model)
+
 	var response *models.OpenRouterResponse
 	var err error
 
@@ -150,15 +216,16 @@ func (s *AIService) GenerateResponse(systemPrompt, userInput, apiKey, deviceID s
 			break
 		}
 
-		logrus.WithFields(logrus.Fields{
+		logrus.WithFields(aiSpan.LogFields()).WithFields(logrus.Fields{
 			"attempt": attempt,
 			"error":   err.Error(),
 		}).Warn("OpenRouter API call failed, retrying")
 
 		if attempt < maxRetries {
-			time.Sleep(retryDelay * time.Duration(attempt))
+			s.clock.Sleep(retryDelay * time.Duration(attempt))
 		}
 	}
+	aiSpan.EndWithError(err)
 
 	if err != nil {
 		logrus.WithError(err).Error("All OpenRouter API attempts failed")
@@ -178,8 +245,13 @@ func (s *AIService) GenerateResponse(systemPrompt, userInput, apiKey, deviceID s
 	// Cache the response
 	s.setCachedResponse(cacheKey, content)
 
+	// Record the effective generation settings with the call so any response
+	// can be reproduced later
 	logrus.WithFields(logrus.Fields{
 		"model":         response.Model,
+		"temperature":   temperature,
+		"top_p":         topP,
+		"max_tokens":    maxTokens,
 		"prompt_tokens": response.Usage.PromptTokens,
 		"total_tokens":  response.Usage.TotalTokens,
 	}).Info("OpenRouter API call successful")
@@ -228,7 +300,7 @@ func (s *AIService) GenerateAdvancedResponse(systemPrompt, userInput, apiKey, de
 		}).Warn("OpenRouter API call failed, retrying")
 
 		if attempt < maxRetries {
-			time.Sleep(retryDelay * time.Duration(attempt))
+			s.clock.Sleep(retryDelay * time.Duration(attempt))
 		}
 	}
 
@@ -373,6 +445,11 @@ func (s *AIService) makeOpenRouterRequest(request models.OpenRouterRequest, apiK
 		provider = "openai"
 	}
 
+	started := time.Now()
+	defer func() {
+		Metrics().ObserveHistogram(MetricAICallSeconds, map[string]string{"provider": provider}, time.Since(started).Seconds())
+	}()
+
 	// Check rate limits before making request
 	if err := s.rateLimiter.CheckRateLimit(provider, deviceID); err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -438,6 +515,7 @@ func (s *AIService) makeOpenRouterRequest(request models.OpenRouterRequest, apiK
 
 	// Record successful API call
 	s.recordAPISuccess()
+	s.recordUsage(deviceID, response.Usage)
 	return &response, nil
 }
 
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"nodepath-chat/internal/models"
+)
+
+// In-memory per-device AI usage accounting. Every OpenRouter call records its
+// token usage into a per-day bucket and intent classifications are counted by
+// label, so digests can report AI spend and top intents without a usage
+// table. Counters reset on restart, same as the webhook stats
+
+// aiUsageRetentionDays is how many daily buckets are kept per device
+const aiUsageRetentionDays = 35
+
+// aiCostPerMillionTokens is the blended USD estimate used to price token
+// usage in reports; OpenRouter bills per model, this is a ballpark
+const aiCostPerMillionTokens = 10.0
+
+// aiUsageDay accumulates one device's AI usage for one calendar day
+type aiUsageDay struct {
+	requests     int64
+	promptTokens int64
+	totalTokens  int64
+	intents      map[string]int64
+}
+
+// AIUsageSummary is the aggregated usage over a reporting window
+type AIUsageSummary struct {
+	Requests         int64              `json:"requests"`
+	PromptTokens     int64              `json:"prompt_tokens"`
+	TotalTokens      int64              `json:"total_tokens"`
+	EstimatedCostUSD float64            `json:"estimated_cost_usd"`
+	TopIntents       []IntentUsageCount `json:"top_intents"`
+}
+
+// IntentUsageCount is one intent label with its classification count
+type IntentUsageCount struct {
+	Intent string `json:"intent"`
+	Count  int64  `json:"count"`
+}
+
+func aiUsageDateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// usageDay returns the device's bucket for the day, creating it if needed.
+// Caller must hold usageMux
+func (s *AIService) usageDay(deviceID string, t time.Time) *aiUsageDay {
+	if s.usage == nil {
+		s.usage = make(map[string]map[string]*aiUsageDay)
+	}
+	days, ok := s.usage[deviceID]
+	if !ok {
+		days = make(map[string]*aiUsageDay)
+		s.usage[deviceID] = days
+	}
+
+	key := aiUsageDateKey(t)
+	day, ok := days[key]
+	if !ok {
+		day = &aiUsageDay{intents: make(map[string]int64)}
+		days[key] = day
+
+		// Drop buckets past the retention horizon
+		horizon := aiUsageDateKey(t.AddDate(0, 0, -aiUsageRetentionDays))
+		for date := range days {
+			if date < horizon {
+				delete(days, date)
+			}
+		}
+	}
+	return day
+}
+
+// recordUsage counts one successful OpenRouter call for the device
+func (s *AIService) recordUsage(deviceID string, usage models.OpenRouterUsage) {
+	s.usageMux.Lock()
+	defer s.usageMux.Unlock()
+
+	day := s.usageDay(deviceID, time.Now())
+	day.requests++
+	day.promptTokens += int64(usage.PromptTokens)
+	day.totalTokens += int64(usage.TotalTokens)
+}
+
+// recordIntent counts one intent classification result for the device
+func (s *AIService) recordIntent(deviceID, intent string) {
+	if intent == "" {
+		return
+	}
+
+	s.usageMux.Lock()
+	defer s.usageMux.Unlock()
+	s.usageDay(deviceID, time.Now()).intents[intent]++
+}
+
+// UsageBetween aggregates the device's AI usage for [from, to) and returns
+// the top intents sorted by count
+func (s *AIService) UsageBetween(deviceID string, from, to time.Time, topIntents int) AIUsageSummary {
+	s.usageMux.Lock()
+	defer s.usageMux.Unlock()
+
+	summary := AIUsageSummary{TopIntents: make([]IntentUsageCount, 0)}
+	intents := make(map[string]int64)
+
+	fromKey := aiUsageDateKey(from)
+	toKey := aiUsageDateKey(to)
+	for date, day := range s.usage[deviceID] {
+		if date < fromKey || date >= toKey {
+			continue
+		}
+		summary.Requests += day.requests
+		summary.PromptTokens += day.promptTokens
+		summary.TotalTokens += day.totalTokens
+		for intent, count := range day.intents {
+			intents[intent] += count
+		}
+	}
+
+	summary.EstimatedCostUSD = float64(summary.TotalTokens) / 1_000_000 * aiCostPerMillionTokens
+
+	for intent, count := range intents {
+		summary.TopIntents = append(summary.TopIntents, IntentUsageCount{Intent: intent, Count: count})
+	}
+	sort.Slice(summary.TopIntents, func(i, j int) bool {
+		if summary.TopIntents[i].Count != summary.TopIntents[j].Count {
+			return summary.TopIntents[i].Count > summary.TopIntents[j].Count
+		}
+		return summary.TopIntents[i].Intent < summary.TopIntents[j].Intent
+	})
+	if topIntents > 0 && len(summary.TopIntents) > topIntents {
+		summary.TopIntents = summary.TopIntents[:topIntents]
+	}
+
+	return summary
+}
+
+// TokensPerDay sums daily token totals across the given devices, oldest day
+// first, for the org usage dashboard
+func (s *AIService) TokensPerDay(deviceIDs []string, from time.Time) []models.DailyTokenCount {
+	s.usageMux.Lock()
+	defer s.usageMux.Unlock()
+
+	fromKey := aiUsageDateKey(from)
+	totals := make(map[string]int64)
+	for _, deviceID := range deviceIDs {
+		for date, day := range s.usage[deviceID] {
+			if date < fromKey {
+				continue
+			}
+			totals[date] += day.totalTokens
+		}
+	}
+
+	counts := make([]models.DailyTokenCount, 0, len(totals))
+	for date, tokens := range totals {
+		counts = append(counts, models.DailyTokenCount{Date: date, Tokens: tokens})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Date < counts[j].Date })
+
+	return counts
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Vision support for AI prompt nodes: incoming image URLs are attached to the
+// user message so a vision-capable model can answer questions about the photo
+// ("is this the receipt?") instead of only seeing a bare link
+
+// visionContent is one part of a multimodal message - the plain
+// OpenRouterMessage only carries text, so vision requests build their own
+type visionContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL *struct {
+		URL string `json:"url"`
+	} `json:"image_url,omitempty"`
+}
+
+type visionMessage struct {
+	Role    string          `json:"role"`
+	Content []visionContent `json:"content"`
+}
+
+type visionRequest struct {
+	Model       string          `json:"model"`
+	Messages    []visionMessage `json:"messages"`
+	Temperature float64         `json:"temperature"`
+}
+
+// visionTextContent wraps plain text as a single-part multimodal content list
+func visionTextContent(text string) []visionContent {
+	return []visionContent{{Type: "text", Text: text}}
+}
+
+// generateVisionResponse answers the user input with the attached images in
+// view, through a vision-capable model on the device's provider
+func (s *AIService) generateVisionResponse(systemPrompt, userInput, apiKey, deviceID string, conversationHistory []models.ConversationMessage, params *AIGenerationParams, imageURLs []string) (string, error) {
+	apiKey = s.getAPIKey(apiKey, deviceID)
+	if apiKey == "" {
+		return "", fmt.Errorf("no API key provided")
+	}
+
+	baseURL := s.getAPIURL(deviceID)
+	model := "openai/gpt-4o-mini"
+	if baseURL == openAIBaseURL {
+		model = "gpt-4o-mini"
+	}
+	temperature := 0.67 // Match the text path so answers read the same
+	if params != nil {
+		if params.Model != "" {
+			model = params.Model
+		}
+		if params.Temperature > 0 {
+			temperature = params.Temperature
+		}
+	}
+
+	var messages []visionMessage
+	if systemPrompt != "" {
+		messages = append(messages, visionMessage{Role: "system", Content: visionTextContent(systemPrompt)})
+	}
+	for _, historyMsg := range conversationHistory {
+		if historyMsg.Content == "" {
+			continue
+		}
+		messages = append(messages, visionMessage{Role: historyMsg.Role, Content: visionTextContent(historyMsg.Content)})
+	}
+
+	userContent := visionTextContent(userInput)
+	for _, imageURL := range imageURLs {
+		userContent = append(userContent, visionContent{Type: "image_url", ImageURL: &struct {
+			URL string `json:"url"`
+		}{URL: imageURL}})
+	}
+	messages = append(messages, visionMessage{Role: "user", Content: userContent})
+
+	request := visionRequest{
+		Model:       model,
+		Messages:    messages,
+		Temperature: temperature,
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal vision request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create vision request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	client := &http.Client{Timeout: 120 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vision request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vision response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vision API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse vision response: %w", err)
+	}
+	if len(parsed.Choices) == 0 || parsed.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("vision API returned no content")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"model":     model,
+		"device_id": deviceID,
+		"images":    len(imageURLs),
+	}).Info("👁️ AI VISION: Vision response generated")
+
+	return parsed.Choices[0].Message.Content, nil
+}
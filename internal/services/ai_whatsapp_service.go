@@ -48,6 +48,7 @@ type AIWhatsappService interface {
 
 	// Update stage in database for AI response tracking
 	UpdateStage(phoneNumber, deviceID, stage string) error
+	UpdateStageWithSource(phoneNumber, deviceID, stage, source string) error
 
 	// Log conversation
 	LogConversation(prospectNum string, idDevice string, message, sender, stage string) error
@@ -89,6 +90,9 @@ type AIWhatsappService interface {
 	// Get any flow execution (regardless of status) - used for delayed message processing
 	GetFlowExecutionByProspectAndDevice(prospectNum, idDevice string) (*models.AIWhatsapp, error)
 
+	// Get a flow execution by its execution ID - used for external wait-event signals
+	GetFlowExecutionByExecutionID(executionID string) (*models.AIWhatsapp, error)
+
 	// Update flow execution state
 	UpdateFlowExecution(prospectNum, idDevice, currentNode string, variables map[string]interface{}, status string) error
 
@@ -107,6 +111,28 @@ type AIWhatsappService interface {
 
 	// Get repository for direct access
 	GetRepository() repository.AIWhatsappRepository
+
+	// Wire lifecycle webhook emission into execution state changes
+	SetLifecycleWebhookService(webhooks *LifecycleWebhookService)
+
+	// Wire flow-completion notices into Slack/Discord connectors
+	SetNotificationService(notifications *NotificationConnectorService)
+
+	// Wire lifecycle events into the analytics warehouse export
+	SetWarehouseExportService(warehouse *WarehouseExportService)
+
+	// Wire stage transition recording into stage updates
+	SetStageHistoryService(stageHistory *StageHistoryService)
+
+	// Wire conversation signals into lead scoring
+	SetLeadScoringService(leadScoring *LeadScoringService)
+
+	// Wire org-level contact identity into flow-state sharing
+	SetContactService(contacts *ContactService)
+
+	// Wire tamper-evident audit logging into message recording
+	SetAuditLogService(auditLog *AuditLogService)
+	SetConversationEventService(events *ConversationEventService)
 }
 
 // AIWhatsappResponse represents the response from AI WhatsApp service
@@ -163,9 +189,17 @@ type aiWhatsappService struct {
 	httpClient            *http.Client
 	circuitBreaker        *CircuitBreakerWhatsapp
 	// Advanced rate limiter for API calls
-	rateLimiter       *APIRateLimiter
-	cfg               *config.Config
-	responseProcessor *AIResponseProcessor
+	rateLimiter        *APIRateLimiter
+	cfg                *config.Config
+	responseProcessor  *AIResponseProcessor
+	lifecycleWebhooks  *LifecycleWebhookService
+	notifications      *NotificationConnectorService
+	warehouse          *WarehouseExportService
+	stageHistory       *StageHistoryService
+	leadScoring        *LeadScoringService
+	contacts           *ContactService
+	auditLog           *AuditLogService
+	conversationEvents *ConversationEventService
 }
 
 // maskAPIKeyForLogging masks API key for logging purposes
@@ -644,7 +678,17 @@ func (s *aiWhatsappService) SetHumanMode(prospectNum, idDevice string, human boo
 
 	// Update human field
 	aiConv.Human = humanValue
-	return s.aiRepo.UpdateAIWhatsapp(aiConv)
+	if err := s.aiRepo.UpdateAIWhatsapp(aiConv); err != nil {
+		return err
+	}
+
+	if human {
+		s.emitLifecycleEvent(idDevice, models.ExecutionEventHandedOff, map[string]interface{}{
+			"prospect_num": prospectNum,
+		})
+	}
+
+	return nil
 }
 
 // SaveConversationHistory saves conversation history to conv_last field
@@ -659,8 +703,33 @@ func (s *aiWhatsappService) SaveConversationHistory(prospectNum, idDevice, userM
 		"prospect_name": prospectName,
 	}).Info("Saving conversation history")
 
+	// Every recorded message also goes onto the device's audit chain
+	if s.auditLog != nil {
+		s.auditLog.Record(idDevice, prospectNum, "user", userMessage)
+		s.auditLog.Record(idDevice, prospectNum, "bot", botResponse)
+	}
+
 	// Use repository method to handle create or update logic
-	return s.aiRepo.SaveConversationHistory(prospectNum, idDevice, userMessage, botResponse, stage, prospectName)
+	if err := s.aiRepo.SaveConversationHistory(prospectNum, idDevice, userMessage, botResponse, stage, prospectName); err != nil {
+		return err
+	}
+
+	if s.conversationEvents != nil {
+		if userMessage != "" {
+			s.conversationEvents.Record(prospectNum, idDevice, models.ConversationEventMessageReceived, map[string]interface{}{
+				"content": userMessage,
+				"stage":   stage,
+			})
+		}
+		if botResponse != "" {
+			s.conversationEvents.Record(prospectNum, idDevice, models.ConversationEventMessageSent, map[string]interface{}{
+				"content": botResponse,
+				"stage":   stage,
+			})
+		}
+	}
+
+	return nil
 }
 
 // ProcessDeviceCommand processes device-specific commands
@@ -1298,6 +1367,19 @@ func (s *aiWhatsappService) StartFlowExecution(prospectNum, idDevice, flowRefere
 		"flow_reference": flowReference,
 	}).Info("Starting flow execution")
 
+	// Shared-identity orgs run one flow per contact: when the same number is
+	// already mid-flow on a sibling device, don't start a parallel execution
+	if s.contacts != nil {
+		if sibling := s.contacts.ActiveSharedExecution(idDevice, prospectNum); sibling != nil {
+			logrus.WithFields(logrus.Fields{
+				"prospect_num":   prospectNum,
+				"id_device":      idDevice,
+				"sibling_device": sibling.IDDevice,
+			}).Info("👥 CONTACT: Contact already in an active flow on a sibling device, skipping start")
+			return nil, fmt.Errorf("contact %s already has an active flow on device %s (shared identity mode)", prospectNum, sibling.IDDevice)
+		}
+	}
+
 	// Generate unique execution ID
 	executionID := fmt.Sprintf("%s_%s_%d", prospectNum, idDevice, time.Now().Unix())
 
@@ -1427,6 +1509,12 @@ func (s *aiWhatsappService) StartFlowExecution(prospectNum, idDevice, flowRefere
 		"flow_reference": flowReference,
 	}).Info("Flow execution started successfully")
 
+	s.emitLifecycleEvent(idDevice, models.ExecutionEventStarted, map[string]interface{}{
+		"prospect_num": prospectNum,
+		"execution_id": executionID,
+		"flow_id":      flowReference,
+	})
+
 	return aiConv, nil
 }
 
@@ -1472,6 +1560,21 @@ func (s *aiWhatsappService) GetFlowExecutionByProspectAndDevice(prospectNum, idD
 	return aiConv, nil
 }
 
+// GetFlowExecutionByExecutionID retrieves a flow execution by its execution ID
+// This is used by the wait-event signal endpoint where only the execution ID is known
+func (s *aiWhatsappService) GetFlowExecutionByExecutionID(executionID string) (*models.AIWhatsapp, error) {
+	aiConv, err := s.aiRepo.GetAIWhatsappByExecutionID(executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI WhatsApp record: %w", err)
+	}
+
+	if aiConv == nil {
+		return nil, nil // No record found
+	}
+
+	return aiConv, nil
+}
+
 // UpdateFlowExecution updates flow execution state in ai_whatsapp
 // Uses UpdateFlowTrackingFields to preserve conversation history and other important data
 func (s *aiWhatsappService) UpdateFlowExecution(prospectNum, idDevice, currentNode string, variables map[string]interface{}, status string) error {
@@ -1552,7 +1655,27 @@ func (s *aiWhatsappService) CompleteFlowExecution(prospectNum, idDevice string)
 		"id_device":    idDevice,
 	}).Info("Completing flow execution")
 
-	return s.UpdateFlowExecution(prospectNum, idDevice, "", nil, "completed")
+	flowID := "unknown"
+	if aiConv, err := s.aiRepo.GetAIWhatsappByProspectAndDevice(prospectNum, idDevice); err == nil && aiConv != nil && aiConv.FlowID.Valid {
+		flowID = aiConv.FlowID.String
+	}
+
+	if err := s.UpdateFlowExecution(prospectNum, idDevice, "", nil, "completed"); err != nil {
+		return err
+	}
+
+	Metrics().IncCounter(MetricFlowCompletions, map[string]string{"flow_id": flowID})
+
+	s.emitLifecycleEvent(idDevice, models.ExecutionEventCompleted, map[string]interface{}{
+		"prospect_num": prospectNum,
+	})
+
+	if s.notifications != nil {
+		s.notifications.NotifyEvent(idDevice, models.OpsEventFlowCompleted,
+			fmt.Sprintf("Prospect %s completed the flow on device %s", prospectNum, idDevice))
+	}
+
+	return nil
 }
 
 // GetFlowExecutionVariables retrieves flow execution variables from ai_whatsapp
@@ -1566,8 +1689,16 @@ func (s *aiWhatsappService) GetFlowExecutionVariables(prospectNum, idDevice stri
 		return nil, fmt.Errorf("AI WhatsApp record not found")
 	}
 
-	// Variables removed from database - return empty map
-	return make(map[string]interface{}), nil
+	// Variables removed from database - expose computed variables only
+	variables := make(map[string]interface{})
+
+	if s.leadScoring != nil {
+		if score, err := s.leadScoring.Score(prospectNum, idDevice); err == nil {
+			variables["lead_score"] = score
+		}
+	}
+
+	return variables, nil
 }
 
 // isCircuitBreakerOpen checks if the circuit breaker is open for WhatsApp AI service
@@ -1624,6 +1755,12 @@ func (s *aiWhatsappService) GetRepository() repository.AIWhatsappRepository {
 
 // UpdateStage updates the stage field in ai_whatsapp
 func (s *aiWhatsappService) UpdateStage(phoneNumber, deviceID, stage string) error {
+	return s.UpdateStageWithSource(phoneNumber, deviceID, stage, "")
+}
+
+// UpdateStageWithSource updates the stage and records the transition in the
+// stage history with what triggered it (a node ID or "ai_response")
+func (s *aiWhatsappService) UpdateStageWithSource(phoneNumber, deviceID, stage, source string) error {
 	// Get active execution
 	execution, err := s.GetActiveFlowExecution(phoneNumber, deviceID)
 	if err != nil {
@@ -1631,7 +1768,14 @@ func (s *aiWhatsappService) UpdateStage(phoneNumber, deviceID, stage string) err
 	}
 
 	if execution == nil {
-		// No active execution, try to update by phone number and device ID
+		// No active execution, try to update by phone number and device ID.
+		// Read the outgoing stage first so the transition can be recorded
+		previousStage := ""
+		_ = s.aiRepo.GetDB().QueryRow(
+			`SELECT COALESCE(stage, '') FROM ai_whatsapp WHERE prospect_num = ? AND id_device = ? ORDER BY id DESC LIMIT 1`,
+			phoneNumber, deviceID,
+		).Scan(&previousStage)
+
 		query := `UPDATE ai_whatsapp SET stage = ? WHERE prospect_num = ? AND id_device = ? ORDER BY id DESC LIMIT 1`
 		result, err := s.aiRepo.GetDB().Exec(query, stage, phoneNumber, deviceID)
 		if err != nil {
@@ -1645,6 +1789,27 @@ func (s *aiWhatsappService) UpdateStage(phoneNumber, deviceID, stage string) err
 				"device_id":    deviceID,
 				"stage":        stage,
 			}).Info("✅ Updated stage in ai_whatsapp")
+
+			if s.stageHistory != nil {
+				s.stageHistory.Record(phoneNumber, deviceID, previousStage, stage, source)
+			}
+
+			if s.conversationEvents != nil && previousStage != stage {
+				s.conversationEvents.Record(phoneNumber, deviceID, models.ConversationEventStageChange, map[string]interface{}{
+					"from":   previousStage,
+					"to":     stage,
+					"source": source,
+				})
+			}
+
+			if s.leadScoring != nil && previousStage != stage {
+				s.leadScoring.ScoreStage(deviceID, phoneNumber, stage)
+			}
+
+			s.emitLifecycleEvent(deviceID, models.ExecutionEventStageChanged, map[string]interface{}{
+				"prospect_num": phoneNumber,
+				"stage":        stage,
+			})
 		}
 		return nil
 	}
@@ -1661,6 +1826,28 @@ func (s *aiWhatsappService) UpdateStage(phoneNumber, deviceID, stage string) err
 		"stage":        stage,
 	}).Info("✅ Updated stage for flow execution")
 
+	if s.stageHistory != nil {
+		s.stageHistory.Record(phoneNumber, deviceID, execution.Stage.String, stage, source)
+	}
+
+	if s.conversationEvents != nil && execution.Stage.String != stage {
+		s.conversationEvents.Record(phoneNumber, deviceID, models.ConversationEventStageChange, map[string]interface{}{
+			"from":   execution.Stage.String,
+			"to":     stage,
+			"source": source,
+		})
+	}
+
+	if s.leadScoring != nil && execution.Stage.String != stage {
+		s.leadScoring.ScoreStage(deviceID, phoneNumber, stage)
+	}
+
+	s.emitLifecycleEvent(deviceID, models.ExecutionEventStageChanged, map[string]interface{}{
+		"prospect_num": phoneNumber,
+		"execution_id": execution.ExecutionID.String,
+		"stage":        stage,
+	})
+
 	return nil
 }
 
@@ -1674,3 +1861,58 @@ func (s *aiWhatsappService) TryAcquireSession(phoneNumber, deviceID string) (boo
 func (s *aiWhatsappService) ReleaseSession(phoneNumber, deviceID string) error {
 	return s.aiRepo.ReleaseSession(phoneNumber, deviceID)
 }
+
+// SetLifecycleWebhookService wires execution lifecycle webhook emission in
+func (s *aiWhatsappService) SetLifecycleWebhookService(webhooks *LifecycleWebhookService) {
+	s.lifecycleWebhooks = webhooks
+}
+
+// SetNotificationService wires flow-completion notices into Slack/Discord
+// connectors
+func (s *aiWhatsappService) SetNotificationService(notifications *NotificationConnectorService) {
+	s.notifications = notifications
+}
+
+// SetWarehouseExportService wires lifecycle events into the analytics
+// warehouse export buffers
+func (s *aiWhatsappService) SetWarehouseExportService(warehouse *WarehouseExportService) {
+	s.warehouse = warehouse
+}
+
+// SetStageHistoryService wires stage transition recording into stage updates
+func (s *aiWhatsappService) SetStageHistoryService(stageHistory *StageHistoryService) {
+	s.stageHistory = stageHistory
+}
+
+// SetLeadScoringService wires stage-reached signals into lead scoring
+func (s *aiWhatsappService) SetLeadScoringService(leadScoring *LeadScoringService) {
+	s.leadScoring = leadScoring
+}
+
+// SetAuditLogService wires message recording into the tamper-evident audit log
+func (s *aiWhatsappService) SetAuditLogService(auditLog *AuditLogService) {
+	s.auditLog = auditLog
+}
+
+// SetConversationEventService wires state mutations into the append-only
+// conversation event log
+func (s *aiWhatsappService) SetConversationEventService(events *ConversationEventService) {
+	s.conversationEvents = events
+}
+
+// SetContactService wires org-level contact identity into flow starts
+func (s *aiWhatsappService) SetContactService(contacts *ContactService) {
+	s.contacts = contacts
+}
+
+// emitLifecycleEvent delivers an execution lifecycle event when webhooks are
+// wired; emission is fire-and-forget
+func (s *aiWhatsappService) emitLifecycleEvent(idDevice, event string, data map[string]interface{}) {
+	if s.warehouse != nil {
+		s.warehouse.RecordEvent(idDevice, event, data)
+	}
+	if s.lifecycleWebhooks == nil {
+		return
+	}
+	s.lifecycleWebhooks.EmitEvent(idDevice, event, data)
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertingService watches message send results and webhook parse errors per
+// device and provider, and raises an alert when the failure rate breaks its
+// error budget or spikes well above the recent baseline. Alerts go to the
+// device's dashboard over WebSocket and, when SLACK_ALERT_WEBHOOK_URL is set,
+// to a Slack incoming webhook. Counters are in-memory and reset on restart,
+// same as the webhook stats
+
+// alertWindowMinutes is the rolling window the failure rate is computed over
+const alertWindowMinutes = 5
+
+// alertBaselineMinutes is how far back the baseline reaches beyond the window
+const alertBaselineMinutes = 30
+
+// alertMinSamples is the minimum number of sends in the window before the
+// failure rate is trusted; a single failed send is not an incident
+const alertMinSamples = 5
+
+// alertFailureRateBudget is the absolute failure rate that always alerts
+const alertFailureRateBudget = 0.5
+
+// alertSpikeFactor flags a rate this many times above the baseline as an
+// anomaly even when the absolute budget is not yet blown
+const alertSpikeFactor = 3.0
+
+// alertSpikeMinRate keeps the spike detector quiet while the absolute rate is
+// still negligible (3x of almost nothing is still almost nothing)
+const alertSpikeMinRate = 0.2
+
+// alertCooldown throttles repeat alerts for the same device and provider
+const alertCooldown = 15 * time.Minute
+
+// alertEventType is the WebSocket message type dashboard alerts are sent as
+const alertEventType = "failure_alert"
+
+// FailureAlert is one raised alert, kept for the API and sent to channels
+type FailureAlert struct {
+	IDDevice    string    `json:"id_device"`
+	Provider    string    `json:"provider"`
+	Kind        string    `json:"kind"` // "send_failures" or "webhook_parse_errors"
+	Rate        float64   `json:"rate"`
+	Baseline    float64   `json:"baseline"`
+	Failures    int64     `json:"failures"`
+	Total       int64     `json:"total"`
+	Message     string    `json:"message"`
+	LogsURL     string    `json:"logs_url"`
+	TriggeredAt time.Time `json:"triggered_at"`
+}
+
+// minuteBucket accumulates one minute of outcomes for a device+provider
+type minuteBucket struct {
+	minute   int64
+	total    int64
+	failures int64
+}
+
+// failureSeries is a rolling per-minute history for one device+provider+kind
+type failureSeries struct {
+	buckets []minuteBucket
+}
+
+// AlertingService computes rolling failure rates and notifies on anomalies
+type AlertingService struct {
+	websocketService *WebSocketService
+	httpClient       *http.Client
+
+	mu           sync.Mutex
+	series       map[string]*failureSeries
+	lastAlert    map[string]time.Time
+	recentAlerts []FailureAlert
+}
+
+// NewAlertingService creates a new alerting service
+func NewAlertingService(websocketService *WebSocketService) *AlertingService {
+	return &AlertingService{
+		websocketService: websocketService,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		series:    make(map[string]*failureSeries),
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// RecordSendResult counts one outbound send attempt for a device and provider
+// and checks the rolling failure rate
+func (s *AlertingService) RecordSendResult(idDevice, provider string, failed bool) {
+	s.record(idDevice, provider, "send_failures", failed)
+}
+
+// RecordParseError counts one webhook payload that failed normalization and
+// checks for a spike. Parse errors have no success counterpart, so the series
+// tracks pure counts and the spike detector does the work
+func (s *AlertingService) RecordParseError(idDevice, provider string) {
+	s.record(idDevice, provider, "webhook_parse_errors", true)
+}
+
+// RecentAlerts returns the alerts raised since startup, newest first
+func (s *AlertingService) RecentAlerts() []FailureAlert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	alerts := make([]FailureAlert, len(s.recentAlerts))
+	copy(alerts, s.recentAlerts)
+	for i, j := 0, len(alerts)-1; i < j; i, j = i+1, j-1 {
+		alerts[i], alerts[j] = alerts[j], alerts[i]
+	}
+	return alerts
+}
+
+func alertSeriesKey(idDevice, provider, kind string) string {
+	return idDevice + "|" + provider + "|" + kind
+}
+
+// record appends the outcome to the series and evaluates it. Any resulting
+// notification is sent outside the lock
+func (s *AlertingService) record(idDevice, provider, kind string, failed bool) {
+	now := time.Now()
+	minute := now.Unix() / 60
+	key := alertSeriesKey(idDevice, provider, kind)
+
+	s.mu.Lock()
+	entry, ok := s.series[key]
+	if !ok {
+		entry = &failureSeries{}
+		s.series[key] = entry
+	}
+
+	if n := len(entry.buckets); n > 0 && entry.buckets[n-1].minute == minute {
+		entry.buckets[n-1].total++
+		if failed {
+			entry.buckets[n-1].failures++
+		}
+	} else {
+		bucket := minuteBucket{minute: minute, total: 1}
+		if failed {
+			bucket.failures = 1
+		}
+		entry.buckets = append(entry.buckets, bucket)
+	}
+
+	// Drop buckets that fell out of the baseline horizon
+	horizon := minute - alertBaselineMinutes
+	trim := 0
+	for trim < len(entry.buckets) && entry.buckets[trim].minute < horizon {
+		trim++
+	}
+	entry.buckets = entry.buckets[trim:]
+
+	alert := s.evaluate(idDevice, provider, kind, entry, minute, now)
+	if alert != nil {
+		s.lastAlert[key] = now
+		s.recentAlerts = append(s.recentAlerts, *alert)
+	}
+	s.mu.Unlock()
+
+	if alert != nil {
+		s.notify(alert)
+	}
+}
+
+// evaluate compares the current window against the baseline and returns an
+// alert when the error budget is blown or the rate spiked. Caller holds the
+// mutex
+func (s *AlertingService) evaluate(idDevice, provider, kind string, entry *failureSeries, minute int64, now time.Time) *FailureAlert {
+	if last, ok := s.lastAlert[alertSeriesKey(idDevice, provider, kind)]; ok && now.Sub(last) < alertCooldown {
+		return nil
+	}
+
+	var windowTotal, windowFailures, baseTotal, baseFailures int64
+	windowStart := minute - alertWindowMinutes
+	for _, bucket := range entry.buckets {
+		if bucket.minute >= windowStart {
+			windowTotal += bucket.total
+			windowFailures += bucket.failures
+		} else {
+			baseTotal += bucket.total
+			baseFailures += bucket.failures
+		}
+	}
+
+	if kind == "webhook_parse_errors" {
+		// Pure error counts: alert on a burst clearly above the baseline's
+		// per-window average
+		baselinePerWindow := float64(baseFailures) / (float64(alertBaselineMinutes-alertWindowMinutes) / float64(alertWindowMinutes))
+		if windowFailures < 2*alertMinSamples {
+			return nil
+		}
+		if float64(windowFailures) < alertSpikeFactor*baselinePerWindow {
+			return nil
+		}
+		return &FailureAlert{
+			IDDevice:    idDevice,
+			Provider:    provider,
+			Kind:        kind,
+			Rate:        float64(windowFailures),
+			Baseline:    baselinePerWindow,
+			Failures:    windowFailures,
+			Total:       windowTotal,
+			Message:     fmt.Sprintf("Webhook parse errors spiked for %s/%s: %d in the last %d min (baseline %.1f)", idDevice, provider, windowFailures, alertWindowMinutes, baselinePerWindow),
+			LogsURL:     alertBaseURL() + "/api/webhook/stats",
+			TriggeredAt: now,
+		}
+	}
+
+	if windowTotal < alertMinSamples {
+		return nil
+	}
+	rate := float64(windowFailures) / float64(windowTotal)
+	baseline := 0.0
+	if baseTotal > 0 {
+		baseline = float64(baseFailures) / float64(baseTotal)
+	}
+
+	budgetBlown := rate >= alertFailureRateBudget
+	spiked := rate >= alertSpikeMinRate && baseline > 0 && rate >= alertSpikeFactor*baseline
+	if !budgetBlown && !spiked {
+		return nil
+	}
+
+	return &FailureAlert{
+		IDDevice:    idDevice,
+		Provider:    provider,
+		Kind:        kind,
+		Rate:        rate,
+		Baseline:    baseline,
+		Failures:    windowFailures,
+		Total:       windowTotal,
+		Message:     fmt.Sprintf("Send failure rate for %s/%s hit %.0f%% (%d of %d in the last %d min, baseline %.0f%%)", idDevice, provider, rate*100, windowFailures, windowTotal, alertWindowMinutes, baseline*100),
+		LogsURL:     alertBaseURL() + "/api/alerts",
+		TriggeredAt: now,
+	}
+}
+
+// notify fans the alert out to every configured channel
+func (s *AlertingService) notify(alert *FailureAlert) {
+	logrus.WithFields(logrus.Fields{
+		"id_device": alert.IDDevice,
+		"provider":  alert.Provider,
+		"kind":      alert.Kind,
+		"rate":      alert.Rate,
+		"failures":  alert.Failures,
+		"total":     alert.Total,
+	}).Error("🚨 ALERT: " + alert.Message)
+
+	if s.websocketService != nil && alert.IDDevice != "" {
+		s.websocketService.SendToDevice(alert.IDDevice, alertEventType, alert)
+	}
+
+	if webhookURL := os.Getenv("SLACK_ALERT_WEBHOOK_URL"); webhookURL != "" {
+		go s.sendSlackAlert(webhookURL, alert)
+	}
+}
+
+// sendSlackAlert posts the alert to a Slack incoming webhook
+func (s *AlertingService) sendSlackAlert(webhookURL string, alert *FailureAlert) {
+	payload, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("🚨 %s\nLogs: %s", alert.Message, alert.LogsURL),
+	})
+	if err != nil {
+		return
+	}
+
+	resp, err := s.httpClient.Post(webhookURL, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		logrus.WithError(err).Warn("🚨 ALERT: Failed to post alert to Slack webhook")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logrus.WithField("status", resp.StatusCode).Warn("🚨 ALERT: Slack webhook rejected the alert")
+	}
+}
+
+// alertBaseURL resolves the public base URL the same way short links do:
+// BASE_URL (set in Railway) > RAILWAY_PUBLIC_DOMAIN > localhost
+func alertBaseURL() string {
+	if baseURL := os.Getenv("BASE_URL"); baseURL != "" {
+		return strings.TrimRight(baseURL, "/")
+	}
+	if domain := os.Getenv("RAILWAY_PUBLIC_DOMAIN"); domain != "" {
+		return "https://" + domain
+	}
+	return "http://localhost:8080"
+}
+
+// SendErrorRates aggregates the retained send-failure series of the given
+// devices into one error rate per provider, for the org usage dashboard
+func (s *AlertingService) SendErrorRates(deviceIDs []string) []models.ProviderErrorRate {
+	wanted := make(map[string]bool, len(deviceIDs))
+	for _, idDevice := range deviceIDs {
+		wanted[idDevice] = true
+	}
+
+	s.mu.Lock()
+	totals := make(map[string]*models.ProviderErrorRate)
+	for key, entry := range s.series {
+		parts := strings.SplitN(key, "|", 3)
+		if len(parts) != 3 || parts[2] != "send_failures" || !wanted[parts[0]] {
+			continue
+		}
+
+		rate, ok := totals[parts[1]]
+		if !ok {
+			rate = &models.ProviderErrorRate{Provider: parts[1]}
+			totals[parts[1]] = rate
+		}
+		for _, bucket := range entry.buckets {
+			rate.Total += bucket.total
+			rate.Failures += bucket.failures
+		}
+	}
+	s.mu.Unlock()
+
+	rates := make([]models.ProviderErrorRate, 0, len(totals))
+	for _, rate := range totals {
+		if rate.Total > 0 {
+			rate.ErrorRate = float64(rate.Failures) / float64(rate.Total)
+		}
+		rates = append(rates, *rate)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Provider < rates[j].Provider })
+
+	return rates
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ApprovalService implements review-before-send mode: flows with review
+// enabled land their AI-generated responses in an approval queue instead of
+// sending them. Agents approve, edit or reject each message over the API;
+// queue changes are pushed to the device's dashboard over WebSocket, and
+// approved-with-changes messages build a per-flow edit log for prompt tuning
+
+// approvalEditLogLimit caps the edit log response
+const approvalEditLogLimit = 100
+
+// WebSocket event types for approval queue changes
+const (
+	approvalPendingEventType  = "approval_pending"
+	approvalResolvedEventType = "approval_resolved"
+)
+
+// ApprovalSender sends reviewer-released messages to the prospect
+type ApprovalSender interface {
+	SendMessageFromDevice(deviceID, phoneNumber, message string) error
+	SendMediaMessage(deviceID, phoneNumber, mediaURL string) error
+}
+
+// ApprovalService manages the human-in-the-loop message approval queue
+type ApprovalService struct {
+	approvalRepo     repository.ApprovalRepository
+	websocketService *WebSocketService
+	sender           ApprovalSender
+	aiWhatsapp       AIWhatsappService
+	promptFeedback   *PromptFeedbackService
+}
+
+// NewApprovalService creates a new approval service
+func NewApprovalService(approvalRepo repository.ApprovalRepository, websocketService *WebSocketService) *ApprovalService {
+	return &ApprovalService{
+		approvalRepo:     approvalRepo,
+		websocketService: websocketService,
+	}
+}
+
+// SetSender wires the outbound message path for approved messages
+func (s *ApprovalService) SetSender(sender ApprovalSender) {
+	s.sender = sender
+}
+
+// SetAIWhatsappService wires conversation history saves for sent approvals
+func (s *ApprovalService) SetAIWhatsappService(aiWhatsapp AIWhatsappService) {
+	s.aiWhatsapp = aiWhatsapp
+}
+
+// SetPromptFeedbackService wires edit capture for the prompt feedback loop
+func (s *ApprovalService) SetPromptFeedbackService(promptFeedback *PromptFeedbackService) {
+	s.promptFeedback = promptFeedback
+}
+
+// IsReviewEnabled reports whether the flow holds AI responses for review
+func (s *ApprovalService) IsReviewEnabled(flowID string) bool {
+	enabled, err := s.approvalRepo.IsReviewEnabled(flowID)
+	if err != nil {
+		return false
+	}
+	return enabled
+}
+
+// SetReviewEnabled switches review-before-send mode for a flow
+func (s *ApprovalService) SetReviewEnabled(flowID string, enabled bool) error {
+	if err := s.approvalRepo.SetReviewEnabled(flowID, enabled); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id": flowID,
+		"enabled": enabled,
+	}).Info("✋ APPROVAL: Review mode changed")
+
+	return nil
+}
+
+// Enqueue holds one AI response for review and notifies the device's agents
+func (s *ApprovalService) Enqueue(message *models.PendingMessage) error {
+	if err := s.approvalRepo.EnqueueMessage(message); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"message_id":   message.ID,
+		"flow_id":      message.FlowID,
+		"prospect_num": message.ProspectNum,
+		"id_device":    message.IDDevice,
+	}).Info("✋ APPROVAL: Message queued for review")
+
+	if s.websocketService != nil {
+		s.websocketService.SendToDevice(message.IDDevice, approvalPendingEventType, message)
+	}
+
+	return nil
+}
+
+// Pending returns the device's unreviewed messages, oldest first
+func (s *ApprovalService) Pending(idDevice string) ([]models.PendingMessage, error) {
+	return s.approvalRepo.GetPendingMessages(idDevice)
+}
+
+// GetMessage returns a queued message by ID, or nil when it does not exist
+func (s *ApprovalService) GetMessage(id int) (*models.PendingMessage, error) {
+	return s.approvalRepo.GetMessage(id)
+}
+
+// Approve releases a queued message to the prospect, optionally with the
+// reviewer's edit; edits are kept as training signal in the flow's edit log
+func (s *ApprovalService) Approve(id int, reviewer, editedContent string) error {
+	message, err := s.approvalRepo.GetMessage(id)
+	if err != nil {
+		return err
+	}
+	if message == nil || message.Status != models.PendingMessageStatusPending {
+		return fmt.Errorf("pending message not found")
+	}
+
+	content := message.Content
+	if editedContent != "" {
+		content = editedContent
+	}
+
+	if s.sender == nil {
+		return fmt.Errorf("message sender not available")
+	}
+
+	if message.MessageType == "text" {
+		err = s.sender.SendMessageFromDevice(message.IDDevice, message.ProspectNum, content)
+	} else {
+		err = s.sender.SendMediaMessage(message.IDDevice, message.ProspectNum, content)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send approved message: %w", err)
+	}
+
+	if err := s.approvalRepo.ResolveMessage(id, models.PendingMessageStatusApproved, editedContent, reviewer); err != nil {
+		return err
+	}
+
+	if s.aiWhatsapp != nil && message.MessageType == "text" {
+		if err := s.aiWhatsapp.SaveConversationHistory(message.ProspectNum, message.IDDevice, "", content, "", ""); err != nil {
+			logrus.WithError(err).Warn("✋ APPROVAL: Failed to save approved message to conversation history")
+		}
+	}
+
+	// Feed the prompt improvement loop when the reviewer changed the text
+	if s.promptFeedback != nil && editedContent != "" && editedContent != message.Content {
+		err := s.promptFeedback.RecordEdit(&models.RecordedEdit{
+			FlowID:      message.FlowID,
+			NodeID:      message.NodeID,
+			IDDevice:    message.IDDevice,
+			ProspectNum: message.ProspectNum,
+			Source:      models.MessageEditSourceApproval,
+			Original:    message.Content,
+			Edited:      editedContent,
+			EditedBy:    reviewer,
+		})
+		if err != nil {
+			logrus.WithError(err).Warn("✋ APPROVAL: Failed to record edit for prompt feedback")
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"message_id": id,
+		"reviewer":   reviewer,
+		"edited":     editedContent != "" && editedContent != message.Content,
+	}).Info("✋ APPROVAL: Message approved and sent")
+
+	s.notifyResolved(message.IDDevice, id, models.PendingMessageStatusApproved)
+	return nil
+}
+
+// Reject drops a queued message without sending it
+func (s *ApprovalService) Reject(id int, reviewer string) error {
+	message, err := s.approvalRepo.GetMessage(id)
+	if err != nil {
+		return err
+	}
+	if message == nil || message.Status != models.PendingMessageStatusPending {
+		return fmt.Errorf("pending message not found")
+	}
+
+	if err := s.approvalRepo.ResolveMessage(id, models.PendingMessageStatusRejected, "", reviewer); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"message_id": id,
+		"reviewer":   reviewer,
+	}).Info("✋ APPROVAL: Message rejected")
+
+	s.notifyResolved(message.IDDevice, id, models.PendingMessageStatusRejected)
+	return nil
+}
+
+// EditLog returns the flow's approved-with-changes messages, newest first
+func (s *ApprovalService) EditLog(flowID string) ([]models.MessageEdit, error) {
+	return s.approvalRepo.GetEditLog(flowID, approvalEditLogLimit)
+}
+
+func (s *ApprovalService) notifyResolved(idDevice string, id int, status string) {
+	if s.websocketService != nil {
+		s.websocketService.SendToDevice(idDevice, approvalResolvedEventType, map[string]interface{}{
+			"message_id": id,
+			"status":     status,
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AssignmentService routes handoff conversations to online agents. It supports
+// round-robin and least-active strategies plus manual reassignment, and records
+// every change in the conversation's assignment history
+type AssignmentService struct {
+	assignmentRepo   repository.AssignmentRepository
+	websocketService *WebSocketService
+	slaService       *SLAService
+}
+
+// SetSLAService wires the SLA service so handoffs start a first-response clock
+func (s *AssignmentService) SetSLAService(slaService *SLAService) {
+	s.slaService = slaService
+}
+
+// NewAssignmentService creates a new assignment service
+func NewAssignmentService(assignmentRepo repository.AssignmentRepository, websocketService *WebSocketService) *AssignmentService {
+	return &AssignmentService{
+		assignmentRepo:   assignmentRepo,
+		websocketService: websocketService,
+	}
+}
+
+// SetAgentOnline marks an agent online or offline for assignment on a device.
+// When an agent comes online, queued handoffs matching their skills are drained
+func (s *AssignmentService) SetAgentOnline(agentID, idDevice string, online bool) error {
+	if err := s.assignmentRepo.SetAgentOnline(agentID, idDevice, online); err != nil {
+		return err
+	}
+
+	if online {
+		go s.drainHandoffQueue(idDevice)
+	}
+
+	return nil
+}
+
+// SetAgentSkills replaces an agent's declared skill tags for a device
+func (s *AssignmentService) SetAgentSkills(agentID, idDevice string, skills []string) error {
+	if err := s.assignmentRepo.SetAgentSkills(agentID, idDevice, skills); err != nil {
+		return err
+	}
+
+	// New skills may unlock queued handoffs waiting for a match
+	go s.drainHandoffQueue(idDevice)
+
+	return nil
+}
+
+// AssignConversation picks an agent using the requested strategy and assigns the
+// conversation to them. An empty strategy defaults to round-robin. When a skill is
+// given, agents with that skill are preferred; if none are online the assignment
+// overflows to any online agent, and with no agents online at all the handoff is
+// queued until someone matching comes online
+func (s *AssignmentService) AssignConversation(prospectNum, idDevice, skill string, strategy models.AssignmentStrategy) (*models.ConversationAssignment, error) {
+	if strategy == "" {
+		strategy = models.AssignmentStrategyRoundRobin
+	}
+
+	// Start the SLA first-response clock for this handoff
+	if s.slaService != nil {
+		s.slaService.RecordHandoff(prospectNum, idDevice)
+	}
+
+	agents, err := s.assignmentRepo.GetOnlineAgents(idDevice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get online agents: %w", err)
+	}
+
+	// Prefer agents with the requested skill, overflow to everyone online
+	if skill != "" {
+		skilled := make([]models.AgentStatus, 0, len(agents))
+		for _, agent := range agents {
+			if agent.HasSkill(skill) {
+				skilled = append(skilled, agent)
+			}
+		}
+		if len(skilled) > 0 {
+			agents = skilled
+		} else if len(agents) > 0 {
+			logrus.WithFields(logrus.Fields{
+				"id_device": idDevice,
+				"skill":     skill,
+			}).Info("👥 ASSIGNMENT: No agent with requested skill online, overflowing to any online agent")
+		}
+	}
+
+	if len(agents) == 0 {
+		logrus.WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"skill":        skill,
+		}).Warn("👥 ASSIGNMENT: No online agents available, queueing handoff")
+
+		if err := s.assignmentRepo.EnqueueHandoff(prospectNum, idDevice, skill, string(strategy)); err != nil {
+			return nil, fmt.Errorf("failed to queue handoff: %w", err)
+		}
+		return nil, nil
+	}
+
+	var selectedAgent string
+	switch strategy {
+	case models.AssignmentStrategyLeastActive:
+		selectedAgent, err = s.pickLeastActiveAgent(agents, idDevice)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		// Round-robin: GetOnlineAgents orders by oldest last assignment first
+		selectedAgent = agents[0].AgentID
+	}
+
+	return s.assignToAgent(prospectNum, idDevice, selectedAgent, string(strategy), skill)
+}
+
+// ReassignConversation manually assigns a conversation to a specific agent
+func (s *AssignmentService) ReassignConversation(prospectNum, idDevice, agentID string) (*models.ConversationAssignment, error) {
+	return s.assignToAgent(prospectNum, idDevice, agentID, string(models.AssignmentStrategyManual), "")
+}
+
+// drainHandoffQueue retries assignment for queued handoffs on a device
+func (s *AssignmentService) drainHandoffQueue(idDevice string) {
+	handoffs, err := s.assignmentRepo.GetQueuedHandoffs(idDevice)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to read handoff queue")
+		return
+	}
+
+	for _, handoff := range handoffs {
+		skill := ""
+		if handoff.Skill != nil {
+			skill = *handoff.Skill
+		}
+
+		assignment, err := s.AssignConversation(handoff.ProspectNum, handoff.IDDevice, skill, models.AssignmentStrategy(handoff.Strategy))
+		if err != nil {
+			logrus.WithError(err).Error("Failed to assign queued handoff")
+			continue
+		}
+
+		if assignment != nil {
+			if err := s.assignmentRepo.RemoveQueuedHandoff(handoff.ID); err != nil {
+				logrus.WithError(err).Warn("Failed to remove handoff from queue after assignment")
+			}
+		}
+	}
+}
+
+// GetAssignment returns the current assignment for a conversation, or nil if unassigned
+func (s *AssignmentService) GetAssignment(prospectNum, idDevice string) (*models.ConversationAssignment, error) {
+	return s.assignmentRepo.GetAssignment(prospectNum, idDevice)
+}
+
+// GetAssignmentHistory returns the assignment trail for a conversation
+func (s *AssignmentService) GetAssignmentHistory(prospectNum, idDevice string) ([]models.AssignmentHistoryEntry, error) {
+	return s.assignmentRepo.GetAssignmentHistory(prospectNum, idDevice)
+}
+
+// pickLeastActiveAgent selects the online agent with the fewest active assignments
+func (s *AssignmentService) pickLeastActiveAgent(agents []models.AgentStatus, idDevice string) (string, error) {
+	counts, err := s.assignmentRepo.GetActiveAssignmentCounts(idDevice)
+	if err != nil {
+		return "", fmt.Errorf("failed to get assignment counts: %w", err)
+	}
+
+	selected := agents[0].AgentID
+	lowest := counts[selected]
+	for _, agent := range agents[1:] {
+		if counts[agent.AgentID] < lowest {
+			selected = agent.AgentID
+			lowest = counts[agent.AgentID]
+		}
+	}
+
+	return selected, nil
+}
+
+// assignToAgent persists the assignment, updates round-robin ordering and notifies agents
+func (s *AssignmentService) assignToAgent(prospectNum, idDevice, agentID, strategy, skill string) (*models.ConversationAssignment, error) {
+	if err := s.assignmentRepo.UpsertAssignment(prospectNum, idDevice, agentID, strategy, skill); err != nil {
+		return nil, fmt.Errorf("failed to save assignment: %w", err)
+	}
+
+	if err := s.assignmentRepo.TouchAgentAssignment(agentID, idDevice); err != nil {
+		logrus.WithError(err).Warn("Failed to update agent round-robin position")
+	}
+
+	// Notify connected agents so inboxes reflect the new owner immediately
+	if s.websocketService != nil {
+		s.websocketService.SendToDevice(idDevice, "conversation_assigned", map[string]interface{}{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"agent_id":     agentID,
+			"strategy":     strategy,
+			"skill":        skill,
+			"assigned_at":  time.Now().Unix(),
+		})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"agent_id":     agentID,
+		"strategy":     strategy,
+	}).Info("👥 ASSIGNMENT: Conversation assigned to agent")
+
+	return s.assignmentRepo.GetAssignment(prospectNum, idDevice)
+}
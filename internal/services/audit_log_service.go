@@ -0,0 +1,130 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AuditLogService maintains a tamper-evident log of every message per
+// device for compliance audits. Each entry is hashed together with the
+// previous entry's hash, so any after-the-fact edit, insertion or deletion
+// breaks the chain and shows up in verification. Recording never fails the
+// message path - a device that cannot append to its chain logs the error
+// and keeps chatting
+
+// auditTimestampLayout is the exact timestamp form fed into the hash.
+// Second precision matches what MySQL DATETIME stores, so a verifier can
+// recompute hashes from the stored rows alone
+const auditTimestampLayout = "2006-01-02 15:04:05"
+
+// AuditLogService appends to and verifies per-device hash chains
+type AuditLogService struct {
+	auditRepo repository.AuditLogRepository
+	// One writer at a time per process keeps the chain linear; entries are
+	// rare enough (one per message) that a single lock is fine
+	mu sync.Mutex
+}
+
+// NewAuditLogService creates a new audit log service
+func NewAuditLogService(auditRepo repository.AuditLogRepository) *AuditLogService {
+	return &AuditLogService{auditRepo: auditRepo}
+}
+
+// Record appends one message to the device's chain
+func (s *AuditLogService) Record(deviceID, prospectNum, sender, content string) {
+	if content == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prevHash, err := s.auditRepo.LatestHash(deviceID)
+	if err != nil {
+		logrus.WithError(err).Error("🔏 AUDIT: Failed to read chain head, skipping entry")
+		return
+	}
+
+	entry := &models.AuditLogEntry{
+		IDDevice:    deviceID,
+		ProspectNum: prospectNum,
+		Sender:      sender,
+		Content:     content,
+		PrevHash:    prevHash,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+	entry.Hash = auditEntryHash(entry)
+
+	if err := s.auditRepo.Insert(entry); err != nil {
+		logrus.WithError(err).Error("🔏 AUDIT: Failed to append audit log entry")
+	}
+}
+
+// Export returns the device's entries for the period together with a
+// verification of the full chain, ready to hand to an auditor
+func (s *AuditLogService) Export(deviceID string, start, end time.Time) ([]models.AuditLogEntry, *models.AuditVerification, error) {
+	entries, err := s.auditRepo.ListByDevicePeriod(deviceID, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	verification, err := s.Verify(deviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, verification, nil
+}
+
+// Verify recomputes the device's whole chain and reports the first break,
+// if any
+func (s *AuditLogService) Verify(deviceID string) (*models.AuditVerification, error) {
+	entries, err := s.auditRepo.ListByDevice(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &models.AuditVerification{Valid: true, Records: len(entries)}
+	prevHash := ""
+	for i := range entries {
+		entry := &entries[i]
+		if entry.PrevHash != prevHash {
+			verification.Valid = false
+			verification.FirstBrokenID = entry.ID
+			verification.Reason = "previous hash does not match preceding entry"
+			break
+		}
+		if auditEntryHash(entry) != entry.Hash {
+			verification.Valid = false
+			verification.FirstBrokenID = entry.ID
+			verification.Reason = "entry hash does not match its content"
+			break
+		}
+		prevHash = entry.Hash
+	}
+
+	if !verification.Valid {
+		logrus.WithFields(logrus.Fields{
+			"device_id":       deviceID,
+			"first_broken_id": verification.FirstBrokenID,
+			"reason":          verification.Reason,
+		}).Warn("🔏 AUDIT: Chain verification failed")
+	}
+
+	return verification, nil
+}
+
+// auditEntryHash computes an entry's chain hash from its stored fields
+func auditEntryHash(entry *models.AuditLogEntry) string {
+	payload := fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+		entry.PrevHash, entry.IDDevice, entry.ProspectNum, entry.Sender,
+		entry.Content, entry.CreatedAt.UTC().Format(auditTimestampLayout))
+	hash := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BackupService exports a tenant's data (flows, devices minus secrets,
+// prospects with their conversations) as one JSON bundle and re-imports such
+// bundles into a fresh environment. Bundles are also written to the local
+// backup directory so scheduled copies can be shipped to object storage
+
+// backupVersion marks the bundle format so future restores can migrate
+const backupVersion = 1
+
+// backupDir is where exported bundles are kept on disk
+const backupDir = "./storage/backups"
+
+// Columns exported per table. Device secrets (api_key) are deliberately
+// excluded: a restored environment gets fresh credentials
+var (
+	backupFlowColumns = []string{
+		"id", "name", "description", "niche", "id_device", "nodes", "edges",
+	}
+	backupDeviceColumns = []string{
+		"id", "device_id", "api_key_option", "webhook_id", "provider",
+		"phone_number", "id_device", "id_erp", "id_admin", "user_id", "instance",
+	}
+	backupProspectColumns = []string{
+		"flow_reference", "execution_id", "date_order", "id_device", "niche",
+		"prospect_name", "prospect_num", "intro", "stage", "conv_last",
+		"conv_current", "execution_status", "flow_id", "current_node_id",
+		"last_node_id", "waiting_for_reply", "balas", "human", "keywordiklan",
+		"marketer",
+	}
+)
+
+// TenantBackup is one exported bundle
+type TenantBackup struct {
+	Version    int                      `json:"version"`
+	ExportedAt time.Time                `json:"exported_at"`
+	UserID     string                   `json:"user_id"`
+	Flows      []map[string]interface{} `json:"flows"`
+	Devices    []map[string]interface{} `json:"devices"`
+	Prospects  []map[string]interface{} `json:"prospects"`
+}
+
+// RestoreResult reports how many rows a restore imported per section
+type RestoreResult struct {
+	Flows     int `json:"flows"`
+	Devices   int `json:"devices"`
+	Prospects int `json:"prospects"`
+}
+
+// BackupService exports and restores tenant data bundles
+type BackupService struct {
+	db *sql.DB
+}
+
+// NewBackupService creates a new backup service
+func NewBackupService(db *sql.DB) *BackupService {
+	return &BackupService{db: db}
+}
+
+// Export builds the tenant bundle for a user and writes a copy to the backup
+// directory
+func (s *BackupService) Export(userID string) (*TenantBackup, error) {
+	backup := &TenantBackup{
+		Version:    backupVersion,
+		ExportedAt: time.Now().UTC(),
+		UserID:     userID,
+	}
+
+	var err error
+	backup.Devices, err = s.exportRows(
+		fmt.Sprintf("SELECT %s FROM device_setting WHERE user_id = ?", strings.Join(backupDeviceColumns, ", ")),
+		backupDeviceColumns, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export devices: %w", err)
+	}
+
+	backup.Flows, err = s.exportRows(
+		fmt.Sprintf("SELECT %s FROM chatbot_flows WHERE id_device IN (SELECT id_device FROM device_setting WHERE user_id = ?)",
+			strings.Join(backupFlowColumns, ", ")),
+		backupFlowColumns, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export flows: %w", err)
+	}
+
+	backup.Prospects, err = s.exportRows(
+		fmt.Sprintf("SELECT %s FROM ai_whatsapp WHERE id_device IN (SELECT id_device FROM device_setting WHERE user_id = ?)",
+			strings.Join(backupProspectColumns, ", ")),
+		backupProspectColumns, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export prospects: %w", err)
+	}
+
+	if err := s.writeBundle(backup); err != nil {
+		// The caller still gets the bundle; only the on-disk copy failed
+		logrus.WithError(err).Warn("💾 BACKUP: Failed to write bundle to backup directory")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"flows":     len(backup.Flows),
+		"devices":   len(backup.Devices),
+		"prospects": len(backup.Prospects),
+	}).Info("💾 BACKUP: Tenant backup exported")
+
+	return backup, nil
+}
+
+// ErrRestoreDeviceOwned is returned when a bundle references a device that
+// already belongs to a different account
+var ErrRestoreDeviceOwned = errors.New("backup bundle references a device owned by another account")
+
+// Restore imports a bundle into the caller's account. Existing rows with the
+// same keys are left untouched so a restore into a fresh environment is safe
+// to re-run. The bundle is never trusted about tenancy: restored devices are
+// forced onto the caller's user_id, and any id_device in the bundle that
+// already belongs to a different account aborts the restore
+func (s *BackupService) Restore(backup *TenantBackup, userID string) (*RestoreResult, error) {
+	if backup.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", backup.Version)
+	}
+
+	if err := s.checkDeviceOwnership(backup, userID); err != nil {
+		return nil, err
+	}
+
+	// The bundle's user_id column is replaced wholesale: a crafted bundle
+	// must not be able to plant devices under someone else's account
+	for _, device := range backup.Devices {
+		device["user_id"] = userID
+	}
+
+	result := &RestoreResult{}
+	result.Devices = s.restoreRows("device_setting", backupDeviceColumns, backup.Devices)
+	result.Flows = s.restoreRows("chatbot_flows", backupFlowColumns, backup.Flows)
+	result.Prospects = s.restoreRows("ai_whatsapp", backupProspectColumns, backup.Prospects)
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"flows":     result.Flows,
+		"devices":   result.Devices,
+		"prospects": result.Prospects,
+	}).Info("💾 BACKUP: Tenant backup restored")
+
+	return result, nil
+}
+
+// checkDeviceOwnership verifies every id_device the bundle touches (devices,
+// flows and prospects alike) is either new or already owned by the caller
+func (s *BackupService) checkDeviceOwnership(backup *TenantBackup, userID string) error {
+	devices := make(map[string]bool)
+	for _, section := range [][]map[string]interface{}{backup.Devices, backup.Flows, backup.Prospects} {
+		for _, row := range section {
+			if idDevice, ok := row["id_device"].(string); ok && idDevice != "" {
+				devices[idDevice] = true
+			}
+		}
+	}
+
+	for idDevice := range devices {
+		var owner sql.NullString
+		err := s.db.QueryRow(
+			repository.Rebind(`SELECT user_id FROM device_setting WHERE id_device = ?`), idDevice,
+		).Scan(&owner)
+		if err == sql.ErrNoRows {
+			continue // new device, the restore creates it under the caller
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check device ownership: %w", err)
+		}
+		if owner.Valid && owner.String != "" && owner.String != userID {
+			logrus.WithFields(logrus.Fields{
+				"id_device": idDevice,
+				"user_id":   userID,
+			}).Warn("💾 BACKUP: Restore rejected, bundle references another account's device")
+			return ErrRestoreDeviceOwned
+		}
+	}
+	return nil
+}
+
+// exportRows runs the query and returns the rows as column→value maps
+func (s *BackupService) exportRows(query string, columns []string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := s.db.Query(repository.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exported := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			logrus.WithError(err).Error("💾 BACKUP: Failed to scan row during export")
+			continue
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if bytes, ok := values[i].([]byte); ok {
+				row[column] = string(bytes)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		exported = append(exported, row)
+	}
+
+	return exported, rows.Err()
+}
+
+// restoreRows inserts the bundle rows, skipping ones that already exist, and
+// returns how many were attempted without error
+func (s *BackupService) restoreRows(table string, columns []string, rows []map[string]interface{}) int {
+	if len(rows) == 0 {
+		return 0
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT DO NOTHING",
+		table, strings.Join(columns, ", "), placeholders)
+
+	restored := 0
+	for _, row := range rows {
+		values := make([]interface{}, len(columns))
+		for i, column := range columns {
+			values[i] = row[column]
+		}
+		if _, err := s.db.Exec(repository.Rebind(query), values...); err != nil {
+			logrus.WithError(err).WithField("table", table).Error("💾 BACKUP: Failed to restore row")
+			continue
+		}
+		restored++
+	}
+
+	return restored
+}
+
+// writeBundle stores a timestamped copy of the bundle in the backup directory
+func (s *BackupService) writeBundle(backup *TenantBackup) error {
+	if err := os.MkdirAll(backupDir, 0o755); err != nil {
+		return err
+	}
+
+	bundleJSON, err := json.Marshal(backup)
+	if err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf("backup_%s_%s.json", backup.UserID, backup.ExportedAt.Format("20060102T150405Z"))
+	return os.WriteFile(filepath.Join(backupDir, fileName), bundleJSON, 0o644)
+}
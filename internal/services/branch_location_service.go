@@ -0,0 +1,134 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// earthRadiusKm is the mean Earth radius used by the haversine distance
+const earthRadiusKm = 6371.0
+
+// BranchLocationService manages the organisation's outlets and finds the
+// nearest one to a prospect's shared location
+type BranchLocationService struct {
+	branchRepo repository.BranchLocationRepository
+}
+
+// NewBranchLocationService creates a new branch location service
+func NewBranchLocationService(branchRepo repository.BranchLocationRepository) *BranchLocationService {
+	return &BranchLocationService{branchRepo: branchRepo}
+}
+
+// CreateBranch validates and stores a new branch
+func (s *BranchLocationService) CreateBranch(branch *models.BranchLocation) error {
+	if err := validateBranch(branch); err != nil {
+		return err
+	}
+
+	if branch.ID == "" {
+		branch.ID = uuid.New().String()
+	}
+
+	if err := s.branchRepo.CreateBranchLocation(branch); err != nil {
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"branch_id": branch.ID,
+		"name":      branch.Name,
+	}).Info("✅ Branch location created")
+
+	return nil
+}
+
+// GetBranch returns a single branch by ID
+func (s *BranchLocationService) GetBranch(id string) (*models.BranchLocation, error) {
+	return s.branchRepo.GetBranchLocationByID(id)
+}
+
+// GetBranches returns all branches for a device
+func (s *BranchLocationService) GetBranches(idDevice string) ([]models.BranchLocation, error) {
+	return s.branchRepo.GetBranchLocationsByDevice(idDevice, false)
+}
+
+// UpdateBranch validates and saves changes to a branch
+func (s *BranchLocationService) UpdateBranch(branch *models.BranchLocation) error {
+	if err := validateBranch(branch); err != nil {
+		return err
+	}
+
+	existing, err := s.branchRepo.GetBranchLocationByID(branch.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("branch not found: %s", branch.ID)
+	}
+
+	return s.branchRepo.UpdateBranchLocation(branch)
+}
+
+// DeleteBranch removes a branch
+func (s *BranchLocationService) DeleteBranch(id string) error {
+	return s.branchRepo.DeleteBranchLocation(id)
+}
+
+// FindNearestBranch returns the active branch closest to the given coordinates
+// and the distance to it in kilometres. Returns nil when no branches exist
+func (s *BranchLocationService) FindNearestBranch(idDevice string, latitude, longitude float64) (*models.BranchLocation, float64, error) {
+	branches, err := s.branchRepo.GetBranchLocationsByDevice(idDevice, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(branches) == 0 {
+		return nil, 0, nil
+	}
+
+	var nearest *models.BranchLocation
+	nearestDistance := math.MaxFloat64
+	for i := range branches {
+		distance := haversineKm(latitude, longitude, branches[i].Latitude, branches[i].Longitude)
+		if distance < nearestDistance {
+			nearest = &branches[i]
+			nearestDistance = distance
+		}
+	}
+
+	return nearest, nearestDistance, nil
+}
+
+// validateBranch checks the fields needed to locate a branch
+func validateBranch(branch *models.BranchLocation) error {
+	if strings.TrimSpace(branch.Name) == "" {
+		return fmt.Errorf("branch name is required")
+	}
+	if branch.IDDevice == "" {
+		return fmt.Errorf("branch device is required")
+	}
+	if branch.Latitude < -90 || branch.Latitude > 90 {
+		return fmt.Errorf("branch latitude must be between -90 and 90")
+	}
+	if branch.Longitude < -180 || branch.Longitude > 180 {
+		return fmt.Errorf("branch longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+// haversineKm computes the great-circle distance between two coordinates
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLng := (lng2 - lng1) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*
+			math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusKm * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CartService manages the per-conversation shopping cart that catalog nodes
+// fill and order summary nodes turn into orders
+type CartService struct {
+	cartRepo repository.CartRepository
+}
+
+// NewCartService creates a new cart service
+func NewCartService(cartRepo repository.CartRepository) *CartService {
+	return &CartService{cartRepo: cartRepo}
+}
+
+// AddToCart puts quantity of a product into the conversation's cart
+func (s *CartService) AddToCart(prospectNum, idDevice string, product *models.Product, quantity int) error {
+	if quantity < 1 {
+		quantity = 1
+	}
+
+	if err := s.cartRepo.UpsertCartItem(prospectNum, idDevice, product.ID, quantity); err != nil {
+		return fmt.Errorf("failed to add product to cart: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"product":      product.Name,
+		"quantity":     quantity,
+	}).Info("🛒 CART: Product added to cart")
+
+	return nil
+}
+
+// RemoveFromCart drops a product from the conversation's cart
+func (s *CartService) RemoveFromCart(prospectNum, idDevice, productID string) error {
+	return s.cartRepo.RemoveCartItem(prospectNum, idDevice, productID)
+}
+
+// GetCart returns the cart items joined with catalog pricing
+func (s *CartService) GetCart(prospectNum, idDevice string) ([]models.CartItem, error) {
+	return s.cartRepo.GetCartItems(prospectNum, idDevice)
+}
+
+// ClearCart empties the conversation's cart
+func (s *CartService) ClearCart(prospectNum, idDevice string) error {
+	return s.cartRepo.ClearCart(prospectNum, idDevice)
+}
+
+// CartTotal sums the cart's line prices
+func CartTotal(items []models.CartItem) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += item.Price * float64(item.Quantity)
+	}
+	return total
+}
+
+// FormatCartSummary renders the cart as a WhatsApp order summary message
+func FormatCartSummary(items []models.CartItem) string {
+	if len(items) == 0 {
+		return "Your cart is empty"
+	}
+
+	var builder strings.Builder
+	builder.WriteString("🧾 *Order Summary*\n")
+	for _, item := range items {
+		builder.WriteString(fmt.Sprintf("%dx %s - RM%.2f\n", item.Quantity, item.Name, item.Price*float64(item.Quantity)))
+	}
+	builder.WriteString(fmt.Sprintf("\n*Total: RM%.2f*", CartTotal(items)))
+
+	return builder.String()
+}
@@ -5,7 +5,6 @@ import (
 	"github.com/sirupsen/logrus"
 	"nodepath-chat/internal/models"
 	"strconv"
-	"strings"
 )
 
 // EvaluateConditionNodeFixed properly matches user input with condition edges
@@ -42,9 +41,6 @@ func (s *FlowService) EvaluateConditionNodeFixed(flow *models.ChatbotFlow, condi
 		return s.evaluateDirectEdgeSelection(flow, userInput, outgoingEdges)
 	}
 
-	// Normalize user input
-	userInputLower := strings.ToLower(strings.TrimSpace(userInput))
-
 	logrus.WithFields(logrus.Fields{
 		"user_input":       userInput,
 		"conditions_count": len(conditions),
@@ -98,21 +94,16 @@ func (s *FlowService) EvaluateConditionNodeFixed(flow *models.ChatbotFlow, condi
 			}
 		}
 
-		// Check condition value match
+		// Check condition value match, including any configured synonyms.
+		// Matching is normalized and typo-tolerant (see condition_matching.go)
 		if conditionValue != "" {
-			conditionValueLower := strings.ToLower(strings.TrimSpace(conditionValue))
+			values := append([]string{conditionValue}, conditionSynonyms(condition)...)
 			var matches bool
-
-			switch conditionType {
-			case "equals":
-				// Exact match (case-insensitive)
-				matches = userInputLower == conditionValueLower
-			case "contains":
-				// Contains match (case-insensitive)
-				matches = strings.Contains(userInputLower, conditionValueLower)
-			default:
-				// Default to contains for backward compatibility
-				matches = strings.Contains(userInputLower, conditionValueLower)
+			for _, value := range values {
+				if conditionValueMatches(conditionType, value, userInput) {
+					matches = true
+					break
+				}
 			}
 
 			if matches {
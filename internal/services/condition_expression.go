@@ -0,0 +1,494 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Condition expression engine for condition nodes. Where edge conditions
+// only support `<subject> <op> "<value>"`, condition nodes can use full
+// boolean expressions over the execution state:
+//
+//	{{stage}} == "Closing" && contains(input, "yes")
+//	message_count > 5 || matches(input, "^(ya|ok|boleh)")
+//
+// Identifiers resolve against the evaluation context: `input` is the user's
+// message, `stage` and `message_count` come from the execution, and any
+// other name reads a flow variable. The {{name}} placeholder form is
+// accepted as an alias for a bare identifier so expressions read like
+// message templates
+
+// ConditionContext is the state an expression evaluates against
+type ConditionContext struct {
+	Input        string
+	Stage        string
+	MessageCount int
+	Variables    map[string]interface{}
+}
+
+// EvaluateConditionExpression parses and evaluates a boolean expression.
+// Malformed expressions return an error rather than silently not matching,
+// so the editor can surface them
+func EvaluateConditionExpression(expression string, ctx *ConditionContext) (bool, error) {
+	tokens, err := tokenizeCondition(expression)
+	if err != nil {
+		return false, err
+	}
+	parser := &conditionParser{tokens: tokens, ctx: ctx}
+	result, err := parser.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if parser.pos < len(parser.tokens) {
+		return false, fmt.Errorf("unexpected %q in expression", parser.tokens[parser.pos].text)
+	}
+	return result, nil
+}
+
+// conditionToken kinds
+const (
+	tokenIdent = iota
+	tokenString
+	tokenNumber
+	tokenOperator // == != > >= < <=
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type conditionToken struct {
+	kind int
+	text string
+}
+
+// tokenizeCondition splits an expression into tokens, folding {{name}}
+// placeholders into plain identifiers
+func tokenizeCondition(expression string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(expression)
+	i := 0
+	for i < len(runes) {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, conditionToken{tokenLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, conditionToken{tokenRParen, ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, conditionToken{tokenComma, ","})
+			i++
+		case c == '&':
+			if i+1 < len(runes) && runes[i+1] == '&' {
+				tokens = append(tokens, conditionToken{tokenAnd, "&&"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '&' at position %d", i)
+			}
+		case c == '|':
+			if i+1 < len(runes) && runes[i+1] == '|' {
+				tokens = append(tokens, conditionToken{tokenOr, "||"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '|' at position %d", i)
+			}
+		case c == '!':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, conditionToken{tokenOperator, "!="})
+				i += 2
+			} else {
+				tokens = append(tokens, conditionToken{tokenNot, "!"})
+				i++
+			}
+		case c == '=':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, conditionToken{tokenOperator, "=="})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '=' at position %d (use ==)", i)
+			}
+		case c == '>' || c == '<':
+			op := string(c)
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			}
+			tokens = append(tokens, conditionToken{tokenOperator, op})
+		case c == '"' || c == '\'':
+			quote := c
+			i++
+			start := i
+			for i < len(runes) && runes[i] != quote {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string literal")
+			}
+			tokens = append(tokens, conditionToken{tokenString, string(runes[start:i])})
+			i++
+		case c == '{':
+			if i+1 >= len(runes) || runes[i+1] != '{' {
+				return nil, fmt.Errorf("unexpected '{' at position %d", i)
+			}
+			end := strings.Index(string(runes[i:]), "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated {{placeholder}}")
+			}
+			name := strings.TrimSpace(string(runes[i+2 : i+end]))
+			if name == "" {
+				return nil, fmt.Errorf("empty {{placeholder}}")
+			}
+			tokens = append(tokens, conditionToken{tokenIdent, name})
+			i += end + 2
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(runes) && runes[i+1] >= '0' && runes[i+1] <= '9':
+			start := i
+			i++
+			for i < len(runes) && (runes[i] >= '0' && runes[i] <= '9' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, conditionToken{tokenNumber, string(runes[start:i])})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] >= 'a' && runes[i] <= 'z' || runes[i] >= 'A' && runes[i] <= 'Z' || runes[i] >= '0' && runes[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, conditionToken{tokenIdent, string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", string(c), i)
+		}
+	}
+	return tokens, nil
+}
+
+// conditionParser is a recursive-descent parser evaluating as it goes
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+	ctx    *ConditionContext
+}
+
+func (p *conditionParser) peek() *conditionToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *conditionParser) parseOr() (bool, error) {
+	result, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for tok := p.peek(); tok != nil && tok.kind == tokenOr; tok = p.peek() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		result = result || right
+	}
+	return result, nil
+}
+
+func (p *conditionParser) parseAnd() (bool, error) {
+	result, err := p.parseUnary()
+	if err != nil {
+		return false, err
+	}
+	for tok := p.peek(); tok != nil && tok.kind == tokenAnd; tok = p.peek() {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		result = result && right
+	}
+	return result, nil
+}
+
+func (p *conditionParser) parseUnary() (bool, error) {
+	if tok := p.peek(); tok != nil && tok.kind == tokenNot {
+		p.pos++
+		result, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		return !result, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *conditionParser) parsePrimary() (bool, error) {
+	tok := p.peek()
+	if tok == nil {
+		return false, fmt.Errorf("unexpected end of expression")
+	}
+
+	// A parenthesized group is a sub-expression unless it follows a function name
+	if tok.kind == tokenLParen {
+		p.pos++
+		result, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if next := p.peek(); next == nil || next.kind != tokenRParen {
+			return false, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return result, nil
+	}
+
+	// Function call returning a boolean
+	if tok.kind == tokenIdent && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].kind == tokenLParen {
+		return p.parseFunction()
+	}
+
+	// Comparison or bare value truthiness
+	left, err := p.parseValue()
+	if err != nil {
+		return false, err
+	}
+	if next := p.peek(); next != nil && next.kind == tokenOperator {
+		operator := next.text
+		p.pos++
+		right, err := p.parseValue()
+		if err != nil {
+			return false, err
+		}
+		return compareConditionValues(left, operator, right)
+	}
+	return conditionTruthy(left), nil
+}
+
+// parseFunction evaluates contains/not_contains/matches/startswith/endswith
+func (p *conditionParser) parseFunction() (bool, error) {
+	name := strings.ToLower(p.tokens[p.pos].text)
+	p.pos += 2 // name and opening parenthesis
+
+	var args []string
+	for {
+		arg, err := p.parseValue()
+		if err != nil {
+			return false, err
+		}
+		args = append(args, arg)
+		next := p.peek()
+		if next == nil {
+			return false, fmt.Errorf("missing closing parenthesis in %s()", name)
+		}
+		if next.kind == tokenComma {
+			p.pos++
+			continue
+		}
+		if next.kind == tokenRParen {
+			p.pos++
+			break
+		}
+		return false, fmt.Errorf("unexpected %q in %s()", next.text, name)
+	}
+	if len(args) != 2 {
+		return false, fmt.Errorf("%s() expects 2 arguments, got %d", name, len(args))
+	}
+
+	haystack := strings.ToLower(args[0])
+	needle := strings.ToLower(args[1])
+	switch name {
+	case "contains":
+		return strings.Contains(haystack, needle), nil
+	case "not_contains":
+		return !strings.Contains(haystack, needle), nil
+	case "startswith":
+		return strings.HasPrefix(haystack, needle), nil
+	case "endswith":
+		return strings.HasSuffix(haystack, needle), nil
+	case "matches":
+		pattern, err := regexp.Compile("(?i)" + args[1])
+		if err != nil {
+			return false, fmt.Errorf("invalid regex in matches(): %w", err)
+		}
+		return pattern.MatchString(args[0]), nil
+	default:
+		return false, fmt.Errorf("unknown function %s()", name)
+	}
+}
+
+// parseValue resolves one operand to its string form
+func (p *conditionParser) parseValue() (string, error) {
+	tok := p.peek()
+	if tok == nil {
+		return "", fmt.Errorf("unexpected end of expression")
+	}
+	switch tok.kind {
+	case tokenString, tokenNumber:
+		p.pos++
+		return tok.text, nil
+	case tokenIdent:
+		p.pos++
+		return p.resolveIdent(tok.text), nil
+	default:
+		return "", fmt.Errorf("unexpected %q where a value was expected", tok.text)
+	}
+}
+
+// resolveIdent reads an identifier from the evaluation context
+func (p *conditionParser) resolveIdent(name string) string {
+	switch strings.ToLower(name) {
+	case "input":
+		return p.ctx.Input
+	case "stage":
+		return p.ctx.Stage
+	case "message_count":
+		return strconv.Itoa(p.ctx.MessageCount)
+	case "true":
+		return "true"
+	case "false":
+		return "false"
+	}
+	if p.ctx.Variables != nil {
+		if value, exists := p.ctx.Variables[name]; exists {
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	return ""
+}
+
+// compareConditionValues compares numerically when both sides are numbers,
+// case-insensitively as strings otherwise
+func compareConditionValues(left, operator, right string) (bool, error) {
+	leftNum, leftErr := strconv.ParseFloat(strings.TrimSpace(left), 64)
+	rightNum, rightErr := strconv.ParseFloat(strings.TrimSpace(right), 64)
+	if leftErr == nil && rightErr == nil {
+		switch operator {
+		case "==":
+			return leftNum == rightNum, nil
+		case "!=":
+			return leftNum != rightNum, nil
+		case ">":
+			return leftNum > rightNum, nil
+		case ">=":
+			return leftNum >= rightNum, nil
+		case "<":
+			return leftNum < rightNum, nil
+		case "<=":
+			return leftNum <= rightNum, nil
+		}
+	}
+
+	leftText := strings.ToLower(strings.TrimSpace(left))
+	rightText := strings.ToLower(strings.TrimSpace(right))
+	switch operator {
+	case "==":
+		return leftText == rightText, nil
+	case "!=":
+		return leftText != rightText, nil
+	case ">":
+		return leftText > rightText, nil
+	case ">=":
+		return leftText >= rightText, nil
+	case "<":
+		return leftText < rightText, nil
+	case "<=":
+		return leftText <= rightText, nil
+	default:
+		return false, fmt.Errorf("unknown operator %q", operator)
+	}
+}
+
+// conditionTruthy treats a bare value as true when it is non-empty and not
+// "false"/"0"
+func conditionTruthy(value string) bool {
+	trimmed := strings.ToLower(strings.TrimSpace(value))
+	return trimmed != "" && trimmed != "false" && trimmed != "0"
+}
+
+// EvaluateConditionNodeExpressions routes a condition node through its
+// expression branches: Data["expressions"] holds {expression, label} pairs,
+// the first expression that evaluates true picks the outgoing edge whose
+// source handle carries the label, and the "fallback"/"default" edge catches
+// everything else. The second return is false when the node defines no
+// expressions, so callers can fall back to the legacy keyword matching
+func (s *FlowService) EvaluateConditionNodeExpressions(flow *models.ChatbotFlow, conditionNodeID string, ctx *ConditionContext) (*models.FlowNode, bool, error) {
+	conditionNode, err := s.FindNodeByID(flow, conditionNodeID)
+	if err != nil || conditionNode == nil {
+		return nil, false, fmt.Errorf("condition node not found: %s", conditionNodeID)
+	}
+
+	rawExpressions, ok := conditionNode.Data["expressions"].([]interface{})
+	if !ok || len(rawExpressions) == 0 {
+		return nil, false, nil
+	}
+
+	matchedLabel := ""
+	matched := false
+	for _, rawExpression := range rawExpressions {
+		entry, ok := rawExpression.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		expression, _ := entry["expression"].(string)
+		label, _ := entry["label"].(string)
+		if strings.TrimSpace(expression) == "" {
+			continue
+		}
+
+		result, err := EvaluateConditionExpression(expression, ctx)
+		if err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"node_id":    conditionNodeID,
+				"expression": expression,
+			}).Warn("🔀 CONDITION: Invalid expression, skipping branch")
+			continue
+		}
+		if result {
+			matchedLabel = label
+			matched = true
+			logrus.WithFields(logrus.Fields{
+				"node_id":    conditionNodeID,
+				"expression": expression,
+				"label":      label,
+			}).Info("✅ CONDITION: Expression matched")
+			break
+		}
+	}
+
+	edges, err := s.GetFlowEdges(flow)
+	if err != nil {
+		return nil, true, err
+	}
+
+	var fallbackEdge *models.FlowEdge
+	for _, edge := range edges {
+		if edge.Source != conditionNodeID {
+			continue
+		}
+		if matched && strings.EqualFold(edge.SourceHandle, matchedLabel) {
+			target, err := s.FindNodeByID(flow, edge.Target)
+			return target, true, err
+		}
+		switch strings.ToLower(edge.SourceHandle) {
+		case "fallback", "default", "":
+			if fallbackEdge == nil {
+				fallbackEdge = edge
+			}
+		}
+	}
+
+	if fallbackEdge == nil {
+		return nil, true, nil
+	}
+	target, err := s.FindNodeByID(flow, fallbackEdge.Target)
+	return target, true, err
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"strings"
+)
+
+// Condition values match user text loosely instead of byte-for-byte: both
+// sides are case-folded, accent-stripped and whitespace-collapsed, and small
+// typos within a Levenshtein threshold still count, so "ya", "Yaa" and
+// "yes pls" all route down the same edge. A condition may also carry a
+// synonyms list whose entries match like additional values.
+
+// accentReplacer maps common accented Latin letters onto their base letter so
+// "sí" matches a condition value of "si"
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// normalizeConditionText lowercases, strips accents and collapses whitespace
+func normalizeConditionText(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = accentReplacer.Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// typoTolerance returns the Levenshtein distance allowed against a value of
+// the given rune length. Very short values allow none ("no" must not match
+// "ok"), ordinary words allow one, long values allow two
+func typoTolerance(length int) int {
+	switch {
+	case length <= 2:
+		return 0
+	case length <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// conditionValueMatches reports whether the user input satisfies one condition
+// value under the given match type, with normalization and typo tolerance
+func conditionValueMatches(conditionType, conditionValue, userInput string) bool {
+	value := normalizeConditionText(conditionValue)
+	input := normalizeConditionText(userInput)
+	if value == "" || input == "" {
+		return false
+	}
+
+	tolerance := typoTolerance(len([]rune(value)))
+
+	switch conditionType {
+	case "equals":
+		if input == value {
+			return true
+		}
+		return levenshteinDistance(input, value) <= tolerance
+	default:
+		// "contains" and legacy untyped conditions
+		if strings.Contains(input, value) {
+			return true
+		}
+		// A typo in any single word of the reply still counts for
+		// single-word values ("yess pls" against "yes")
+		if tolerance > 0 && !strings.Contains(value, " ") {
+			for _, word := range strings.Fields(input) {
+				if levenshteinDistance(word, value) <= tolerance {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// conditionSynonyms reads the optional synonyms list off a raw condition map
+func conditionSynonyms(condition map[string]interface{}) []string {
+	items, ok := condition["synonyms"].([]interface{})
+	if !ok {
+		return nil
+	}
+	var synonyms []string
+	for _, item := range items {
+		if synonym, ok := item.(string); ok && synonym != "" {
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	return synonyms
+}
+
+// levenshteinDistance is the minimum number of single-rune edits (insert,
+// delete, substitute) turning a into b
+func levenshteinDistance(a, b string) int {
+	runesA := []rune(a)
+	runesB := []rune(b)
+
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = min(min(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(runesB)]
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ContactService links the device-scoped prospects of one phone number into
+// an org-level contact. The same number messaging two devices of the same
+// org historically created two isolated histories; the contact entity makes
+// the duplication visible, serves a unified history across devices, and —
+// when the org opts into shared identity mode — keeps flow state to one
+// active execution per contact instead of one per device
+
+// contactUnifiedHistoryLimit caps the merged cross-device history response
+const contactUnifiedHistoryLimit = 200
+
+// ContactService resolves org-level contacts behind device-scoped prospects
+type ContactService struct {
+	contactRepo repository.ContactRepository
+}
+
+// NewContactService creates a new contact service
+func NewContactService(contactRepo repository.ContactRepository) *ContactService {
+	return &ContactService{contactRepo: contactRepo}
+}
+
+// ResolveContact returns the org-level contact behind a device-scoped
+// prospect, with every linked device conversation attached
+func (s *ContactService) ResolveContact(idDevice, prospectNum string) (*models.Contact, error) {
+	userID, err := s.contactRepo.GetUserIDByDevice(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("device %s has no owner", idDevice)
+	}
+
+	return s.contactForUser(userID, prospectNum)
+}
+
+// ContactForUser returns the contact of a phone number within an org, with
+// its linked device conversations attached
+func (s *ContactService) ContactForUser(userID, phoneNumber string) (*models.Contact, error) {
+	return s.contactForUser(userID, phoneNumber)
+}
+
+func (s *ContactService) contactForUser(userID, phoneNumber string) (*models.Contact, error) {
+	contact, err := s.contactRepo.GetOrCreateContact(userID, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	prospects, err := s.contactRepo.GetContactProspects(userID, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	contact.Prospects = prospects
+
+	return contact, nil
+}
+
+// UnifiedHistory returns the contact's conversation logs merged across every
+// device of the org, newest first
+func (s *ContactService) UnifiedHistory(userID, phoneNumber string) ([]models.ConversationLog, error) {
+	return s.contactRepo.GetUnifiedHistory(userID, phoneNumber, contactUnifiedHistoryLimit)
+}
+
+// Duplicates lists phone numbers with isolated histories on more than one of
+// the org's devices
+func (s *ContactService) Duplicates(userID string) ([]models.DuplicateProspect, error) {
+	return s.contactRepo.GetDuplicateProspects(userID)
+}
+
+// IdentityMode returns the org's flow-state sharing mode
+func (s *ContactService) IdentityMode(userID string) (string, error) {
+	return s.contactRepo.GetIdentityMode(userID)
+}
+
+// SetIdentityMode saves the org's flow-state sharing mode
+func (s *ContactService) SetIdentityMode(userID, mode string) error {
+	if mode != models.ContactIdentityShared && mode != models.ContactIdentityIsolated {
+		return fmt.Errorf("identity mode must be %q or %q", models.ContactIdentityShared, models.ContactIdentityIsolated)
+	}
+
+	if err := s.contactRepo.SetIdentityMode(userID, mode); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":       userID,
+		"identity_mode": mode,
+	}).Info("👥 CONTACT: Identity mode saved")
+
+	return nil
+}
+
+// ActiveSharedExecution reports whether the contact behind a device-scoped
+// prospect already has an active flow execution on a sibling device, and the
+// org shares flow state. Isolated orgs (the default) always get nil
+func (s *ContactService) ActiveSharedExecution(idDevice, prospectNum string) *models.ContactProspect {
+	userID, err := s.contactRepo.GetUserIDByDevice(idDevice)
+	if err != nil || userID == "" {
+		return nil
+	}
+
+	mode, err := s.contactRepo.GetIdentityMode(userID)
+	if err != nil || mode != models.ContactIdentityShared {
+		return nil
+	}
+
+	sibling, err := s.contactRepo.GetActiveSiblingExecution(userID, prospectNum, idDevice)
+	if err != nil {
+		return nil
+	}
+
+	return sibling
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Per-flow conversation context windowing. How much history reaches the AI
+// used to be implicit - the whole conv_last blob went with every call. The
+// flow's start node can now bound it by turns and tokens, strip media links
+// and keep a compact summary of what was trimmed
+
+// contextCharsPerToken is the rough character budget per token used to
+// enforce the max-token setting without a tokenizer
+const contextCharsPerToken = 4
+
+// contextSummaryLineLength is how much of each trimmed line survives into
+// the summary
+const contextSummaryLineLength = 60
+
+// contextSummaryMaxLength caps the whole trimmed-history summary
+const contextSummaryMaxLength = 500
+
+// contextMediaURLPattern matches media-file URLs inside history lines so
+// flows can exclude them from the AI context
+var contextMediaURLPattern = regexp.MustCompile(`https?://[^\s\[\]()]+\.(?:jpg|jpeg|png|gif|bmp|webp|svg|mp3|wav|flac|aac|ogg|wma|m4a|mp4|avi|mov|wmv|flv|webm|mkv|m4v)(?:\?[^\s\[\]()]*)?`)
+
+// ContextWindowConfig bounds the conversation history sent to the AI.
+// Zero values leave the corresponding dimension unlimited
+type ContextWindowConfig struct {
+	MaxTurns       int  // Maximum user turns kept, counted from the most recent
+	MaxTokens      int  // Approximate token budget for the whole window
+	ExcludeMedia   bool // Strip media URLs from history lines
+	IncludeSummary bool // Keep a compact summary of trimmed lines
+}
+
+// FlowContextConfig reads the flow's context window settings off its start
+// node; flows without settings get an unlimited window, matching the old
+// behavior
+func (s *FlowService) FlowContextConfig(flow *models.ChatbotFlow) ContextWindowConfig {
+	nodes, err := s.GetFlowNodes(flow)
+	if err != nil {
+		logrus.WithError(err).WithField("flow_id", flow.ID).Warn("Failed to read flow nodes for context config")
+		return ContextWindowConfig{}
+	}
+
+	for _, node := range nodes {
+		if node.Type != models.NodeTypeStart {
+			continue
+		}
+		config := node.StartConfig()
+		return ContextWindowConfig{
+			MaxTurns:       config.ContextMaxTurns,
+			MaxTokens:      config.ContextMaxTokens,
+			ExcludeMedia:   config.ContextExcludeMedia,
+			IncludeSummary: config.ContextIncludeSummary,
+		}
+	}
+	return ContextWindowConfig{}
+}
+
+// BuildContextWindow applies the flow's context settings to the conv_last
+// blob and returns the history the AI should see
+func BuildContextWindow(convLast string, config ContextWindowConfig) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(convLast, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if config.ExcludeMedia {
+			line = strings.TrimSpace(contextMediaURLPattern.ReplaceAllString(line, ""))
+			if line == "" || line == "USER:" || line == "BOT:" {
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	kept := lines
+
+	// Max turns: keep from the n-th most recent user turn onward
+	if config.MaxTurns > 0 {
+		turns := 0
+		cut := 0
+		for i := len(kept) - 1; i >= 0; i-- {
+			if strings.HasPrefix(kept[i], "USER:") {
+				turns++
+				if turns == config.MaxTurns {
+					cut = i
+					break
+				}
+			}
+		}
+		if turns >= config.MaxTurns {
+			kept = kept[cut:]
+		}
+	}
+
+	// Max tokens: drop the oldest lines until the window fits the budget
+	if config.MaxTokens > 0 {
+		budget := config.MaxTokens * contextCharsPerToken
+		total := 0
+		for _, line := range kept {
+			total += len(line) + 1
+		}
+		for len(kept) > 1 && total > budget {
+			total -= len(kept[0]) + 1
+			kept = kept[1:]
+		}
+	}
+
+	trimmed := len(lines) - len(kept)
+	if trimmed > 0 && config.IncludeSummary {
+		summary := summarizeTrimmedLines(lines[:trimmed])
+		if summary != "" {
+			kept = append([]string{summary}, kept...)
+		}
+	}
+
+	if trimmed > 0 {
+		logrus.WithFields(logrus.Fields{
+			"total_lines": len(lines),
+			"kept_lines":  len(kept),
+			"max_turns":   config.MaxTurns,
+			"max_tokens":  config.MaxTokens,
+		}).Debug("Conversation context window trimmed")
+	}
+
+	return strings.Join(kept, "\n")
+}
+
+// summarizeTrimmedLines folds the dropped history lines into one compact
+// line so the AI keeps a sense of how the conversation started
+func summarizeTrimmedLines(trimmed []string) string {
+	parts := make([]string, 0, len(trimmed))
+	for _, line := range trimmed {
+		if len(line) > contextSummaryLineLength {
+			line = line[:contextSummaryLineLength] + "..."
+		}
+		parts = append(parts, line)
+	}
+
+	summary := fmt.Sprintf("[context summary] Earlier conversation (%d lines): %s",
+		len(trimmed), strings.Join(parts, " | "))
+	if len(summary) > contextSummaryMaxLength {
+		summary = summary[:contextSummaryMaxLength] + "..."
+	}
+	return summary
+}
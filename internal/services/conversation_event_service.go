@@ -0,0 +1,61 @@
+package services
+
+import (
+	"encoding/json"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ConversationEventService records every conversation state mutation (message
+// received/sent, node transition, stage change) into an append-only event log.
+// Recording is best-effort: a failed append is logged but never fails the
+// mutation that triggered it, so the hot path is unaffected
+type ConversationEventService struct {
+	repo repository.ConversationEventRepository
+}
+
+// NewConversationEventService creates a new conversation event service
+func NewConversationEventService(repo repository.ConversationEventRepository) *ConversationEventService {
+	return &ConversationEventService{repo: repo}
+}
+
+// Record appends one event to the conversation's log. The detail map is
+// stored as JSON so analytics pipelines can parse it without schema changes
+func (s *ConversationEventService) Record(prospectNum, idDevice, eventType string, detail map[string]interface{}) {
+	if s == nil || prospectNum == "" || idDevice == "" {
+		return
+	}
+
+	detailJSON := ""
+	if detail != nil {
+		if encoded, err := json.Marshal(detail); err == nil {
+			detailJSON = string(encoded)
+		}
+	}
+
+	seq, err := s.repo.Append(prospectNum, idDevice, eventType, detailJSON)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"event_type":   eventType,
+		}).Warn("📜 EVENTS: Failed to append conversation event")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"event_type":   eventType,
+		"seq":          seq,
+	}).Debug("📜 EVENTS: Appended conversation event")
+}
+
+// ListEvents returns events for a conversation with seq greater than afterSeq,
+// in sequence order, for replay and analytics consumers
+func (s *ConversationEventService) ListEvents(prospectNum, idDevice string, afterSeq, limit int) ([]models.ConversationEvent, error) {
+	return s.repo.List(prospectNum, idDevice, afterSeq, limit)
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+)
+
+// ConversationSearchService powers the inbox's conversation search: it runs
+// the full-text query, then extracts the matching message lines as snippets
+// with the query terms highlighted so the inbox can show why each
+// conversation matched
+
+// maxSearchSnippets caps how many matching lines a single conversation
+// contributes to the response
+const maxSearchSnippets = 3
+
+// ConversationSearchService searches conversation content with highlighting
+type ConversationSearchService struct {
+	searchRepo repository.ConversationSearchRepository
+}
+
+// NewConversationSearchService creates a new conversation search service
+func NewConversationSearchService(searchRepo repository.ConversationSearchRepository) *ConversationSearchService {
+	return &ConversationSearchService{searchRepo: searchRepo}
+}
+
+// Search returns conversations on the device whose messages mention the
+// query, best match first. The sender filter ("user" or "bot") keeps only
+// conversations where that side said the words - e.g. every customer who
+// mentioned "refund", not every bot reply about the refund policy
+func (s *ConversationSearchService) Search(deviceID, query, sender string, limit int) ([]models.ConversationSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	rows, err := s.searchRepo.Search(deviceID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ConversationSearchResult, 0, len(rows))
+	for _, row := range rows {
+		snippets, matchCount := s.extractSnippets(row.ConvLast.String, query, sender)
+		if matchCount == 0 {
+			// Full-text stemming can match conversations whose lines don't
+			// contain the literal terms, or the sender filter removed every
+			// match - either way there is nothing to show
+			continue
+		}
+
+		result := models.ConversationSearchResult{
+			ProspectNum: row.ProspectNum,
+			Score:       row.Score,
+			MatchCount:  matchCount,
+			UpdatedAt:   row.UpdatedAt,
+			Snippets:    snippets,
+		}
+		if row.ProspectName.Valid {
+			result.ProspectName = row.ProspectName.String
+		}
+		if row.Stage.Valid {
+			result.Stage = row.Stage.String
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// extractSnippets walks the conversation's message lines and returns the
+// ones containing a query term, highlighted, plus the total match count
+func (s *ConversationSearchService) extractSnippets(convLast, query, senderFilter string) ([]models.ConversationSearchSnippet, int) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, 0
+	}
+
+	var snippets []models.ConversationSearchSnippet
+	matchCount := 0
+	for _, line := range strings.Split(convLast, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		sender := "bot"
+		text := line
+		switch {
+		case strings.HasPrefix(line, "USER: "):
+			sender = "user"
+			text = strings.TrimPrefix(line, "USER: ")
+		case strings.HasPrefix(line, "BOT: "):
+			text = strings.TrimPrefix(line, "BOT: ")
+		}
+
+		if senderFilter != "" && sender != senderFilter {
+			continue
+		}
+		if !lineMatches(text, terms) {
+			continue
+		}
+
+		matchCount++
+		if len(snippets) < maxSearchSnippets {
+			snippets = append(snippets, models.ConversationSearchSnippet{
+				Sender: sender,
+				Text:   highlightTerms(text, terms),
+			})
+		}
+	}
+
+	return snippets, matchCount
+}
+
+// searchTerms splits the query into lowercase terms, dropping short noise words
+func searchTerms(query string) []string {
+	var terms []string
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if len(term) < 2 {
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// lineMatches reports whether the line contains any of the query terms
+func lineMatches(line string, terms []string) bool {
+	lower := strings.ToLower(line)
+	for _, term := range terms {
+		if strings.Contains(lower, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightTerms HTML-escapes the line and wraps each query term occurrence
+// in <mark> tags. Terms are matched in a single left-to-right pass so the
+// inserted tags are never themselves re-matched; matching is
+// case-insensitive while the original casing is preserved in the output
+func highlightTerms(line string, terms []string) string {
+	escaped := html.EscapeString(line)
+	lower := strings.ToLower(escaped)
+
+	var sb strings.Builder
+	start := 0
+	for start < len(escaped) {
+		matchIdx, matchLen := -1, 0
+		for _, term := range terms {
+			escapedTerm := html.EscapeString(term)
+			if idx := strings.Index(lower[start:], escapedTerm); idx >= 0 {
+				if matchIdx < 0 || start+idx < matchIdx {
+					matchIdx = start + idx
+					matchLen = len(escapedTerm)
+				}
+			}
+		}
+		if matchIdx < 0 {
+			sb.WriteString(escaped[start:])
+			break
+		}
+		sb.WriteString(escaped[start:matchIdx])
+		sb.WriteString("<mark>")
+		sb.WriteString(escaped[matchIdx : matchIdx+matchLen])
+		sb.WriteString("</mark>")
+		start = matchIdx + matchLen
+	}
+	return sb.String()
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CredentialHealthService periodically validates every device's provider
+// credentials with a cheap status call. Wablas tokens and WAHA keys expire or
+// get revoked silently; instead of surfacing as opaque provider failures
+// mid-flow, unhealthy credentials are flagged, owners are alerted through the
+// notification connectors, and sends are blocked with a clear error
+
+// credentialCheckInterval is how often the monitor re-validates credentials
+const credentialCheckInterval = 30 * time.Minute
+
+// CredentialHealth is the recorded outcome of the latest credential check
+// for one device
+type CredentialHealth struct {
+	IDDevice    string    `json:"id_device"`
+	Provider    string    `json:"provider"`
+	Healthy     bool      `json:"healthy"`
+	Status      string    `json:"status"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastChecked time.Time `json:"last_checked"`
+}
+
+// CredentialHealthService runs the scheduled credential monitor and answers
+// health queries from the send path and the API
+type CredentialHealthService struct {
+	deviceRepo          repository.DeviceSettingsRepository
+	notificationService *NotificationConnectorService
+	mu                  sync.RWMutex
+	statuses            map[string]*CredentialHealth
+	done                chan struct{}
+}
+
+// NewCredentialHealthService creates a new credential health service
+func NewCredentialHealthService(deviceRepo repository.DeviceSettingsRepository) *CredentialHealthService {
+	return &CredentialHealthService{
+		deviceRepo: deviceRepo,
+		statuses:   make(map[string]*CredentialHealth),
+		done:       make(chan struct{}),
+	}
+}
+
+// SetNotificationService wires expiry alerts into Slack/Discord connectors
+func (s *CredentialHealthService) SetNotificationService(notificationService *NotificationConnectorService) {
+	s.notificationService = notificationService
+}
+
+// StartMonitor runs scheduled credential validation until Stop is called.
+// Call in a goroutine
+func (s *CredentialHealthService) StartMonitor() {
+	logrus.Info("🔑 CREDENTIALS: Health monitor started")
+	ticker := time.NewTicker(credentialCheckInterval)
+	defer ticker.Stop()
+
+	// Validate once on startup so sends are protected before the first tick
+	s.checkAll()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.checkAll()
+		}
+	}
+}
+
+// Stop stops the monitor
+func (s *CredentialHealthService) Stop() {
+	close(s.done)
+}
+
+// Healthy reports whether the device's credentials passed their latest check.
+// Devices that have never been checked are treated as healthy so a freshly
+// created device is not blocked before the monitor reaches it
+func (s *CredentialHealthService) Healthy(idDevice string) (bool, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	health, ok := s.statuses[idDevice]
+	if !ok || health.Healthy {
+		return true, ""
+	}
+
+	reason := health.LastError
+	if reason == "" {
+		reason = fmt.Sprintf("provider reported status %q", health.Status)
+	}
+	return false, reason
+}
+
+// GetHealth returns the latest check result for one device, or nil when the
+// device has not been checked yet
+func (s *CredentialHealthService) GetHealth(idDevice string) *CredentialHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	health, ok := s.statuses[idDevice]
+	if !ok {
+		return nil
+	}
+	copied := *health
+	return &copied
+}
+
+// ListHealth returns the latest check result for every monitored device
+func (s *CredentialHealthService) ListHealth() []CredentialHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]CredentialHealth, 0, len(s.statuses))
+	for _, health := range s.statuses {
+		results = append(results, *health)
+	}
+	return results
+}
+
+// CheckDevice validates one device's credentials immediately and records the
+// result, alerting owners when credentials go from healthy to unhealthy
+func (s *CredentialHealthService) CheckDevice(device *models.DeviceSettings) *CredentialHealth {
+	health := &CredentialHealth{
+		IDDevice:    device.ID,
+		Provider:    strings.ToLower(device.Provider),
+		LastChecked: time.Now(),
+	}
+
+	provider, ok := GetProvider(health.Provider)
+	if !ok {
+		health.Status = "unknown"
+		health.LastError = fmt.Sprintf("unsupported provider: %s", device.Provider)
+		s.record(health)
+		return health
+	}
+
+	status := provider.GetStatus(device)
+	if connected, ok := status["connected"].(bool); ok {
+		health.Healthy = connected
+	}
+	if statusVal, ok := status["status"].(string); ok {
+		health.Status = statusVal
+	}
+	if !health.Healthy {
+		if details, ok := status["details"].(map[string]interface{}); ok {
+			if errVal, ok := details["error"].(string); ok {
+				health.LastError = errVal
+			}
+		}
+	}
+
+	s.record(health)
+	return health
+}
+
+// checkAll validates credentials for every configured device
+func (s *CredentialHealthService) checkAll() {
+	devices, err := s.deviceRepo.GetAllDeviceSettings()
+	if err != nil {
+		logrus.WithError(err).Error("🔑 CREDENTIALS: Failed to load devices for scheduled check")
+		return
+	}
+
+	unhealthy := 0
+	for i := range devices {
+		if !s.CheckDevice(&devices[i]).Healthy {
+			unhealthy++
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"devices":   len(devices),
+		"unhealthy": unhealthy,
+	}).Info("🔑 CREDENTIALS: Scheduled credential check completed")
+}
+
+// record stores a check result and alerts on the healthy → unhealthy edge,
+// so owners get one alert per expiry instead of one per check
+func (s *CredentialHealthService) record(health *CredentialHealth) {
+	s.mu.Lock()
+	previous, known := s.statuses[health.IDDevice]
+	wasHealthy := !known || previous.Healthy
+	s.statuses[health.IDDevice] = health
+	s.mu.Unlock()
+
+	if health.Healthy || !wasHealthy {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device": health.IDDevice,
+		"provider":  health.Provider,
+		"status":    health.Status,
+		"error":     health.LastError,
+	}).Warn("🔑 CREDENTIALS: Device credentials became unhealthy")
+
+	if s.notificationService != nil {
+		reason := health.LastError
+		if reason == "" {
+			reason = fmt.Sprintf("provider reported status %q", health.Status)
+		}
+		s.notificationService.NotifyEvent(health.IDDevice, models.OpsEventCredentialUnhealthy,
+			fmt.Sprintf("Provider credentials for device %s (%s) failed validation: %s. Message sends are blocked until the credentials are fixed.",
+				health.IDDevice, health.Provider, reason))
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CredentialRotationService swaps a device's provider credential for a new
+// one without recreating the device: the new token is validated against the
+// provider first, the stored credential is replaced atomically, webhooks are
+// re-registered where the provider supports it, and the credential health
+// record is refreshed so blocked sends resume immediately
+
+// WebhookRegistrar is implemented by providers whose message webhook is
+// configured through their API (currently WAHA); Wablas and Whacenter
+// configure webhooks in the provider dashboard instead
+type WebhookRegistrar interface {
+	RegisterWebhook(deviceSettings *models.DeviceSettings) error
+}
+
+// CredentialRotationResult reports what one rotation did
+type CredentialRotationResult struct {
+	IDDevice            string            `json:"id_device"`
+	Provider            string            `json:"provider"`
+	Rotated             bool              `json:"rotated"`
+	WebhookReregistered bool              `json:"webhook_reregistered"`
+	WebhookNote         string            `json:"webhook_note,omitempty"`
+	Health              *CredentialHealth `json:"health,omitempty"`
+}
+
+// CredentialRotationService validates and applies credential rotations
+type CredentialRotationService struct {
+	deviceSettingsService *DeviceSettingsService
+	credentialHealth      *CredentialHealthService
+}
+
+// NewCredentialRotationService creates a new credential rotation service
+func NewCredentialRotationService(deviceSettingsService *DeviceSettingsService, credentialHealth *CredentialHealthService) *CredentialRotationService {
+	return &CredentialRotationService{
+		deviceSettingsService: deviceSettingsService,
+		credentialHealth:      credentialHealth,
+	}
+}
+
+// Rotate validates the new credential against the provider and swaps it in.
+// The device row, conversations and flows are untouched apart from the
+// credential itself, so history survives the rotation
+func (s *CredentialRotationService) Rotate(device *models.DeviceSettings, newInstance string) (*CredentialRotationResult, error) {
+	newInstance = strings.TrimSpace(newInstance)
+	if newInstance == "" {
+		return nil, fmt.Errorf("new credential cannot be empty")
+	}
+
+	providerName := strings.ToLower(device.Provider)
+	provider, ok := GetProvider(providerName)
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", device.Provider)
+	}
+
+	result := &CredentialRotationResult{
+		IDDevice: device.ID,
+		Provider: providerName,
+	}
+
+	// Validate the new credential with a status call before touching the row,
+	// so a bad paste never replaces a working token
+	candidate := *device
+	candidate.Instance = sql.NullString{String: newInstance, Valid: true}
+	status := provider.GetStatus(&candidate)
+	if connected, ok := status["connected"].(bool); !ok || !connected {
+		reason := "provider rejected the credential"
+		if details, ok := status["details"].(map[string]interface{}); ok {
+			if errVal, ok := details["error"].(string); ok && errVal != "" {
+				reason = errVal
+			}
+		}
+		return nil, fmt.Errorf("new credential failed validation: %s", reason)
+	}
+
+	// Atomic swap guarded by the credential we read, so two concurrent
+	// rotations cannot silently overwrite each other
+	if err := s.deviceSettingsService.SwapInstance(device.ID, device.Instance, newInstance); err != nil {
+		return nil, err
+	}
+	result.Rotated = true
+	device.Instance = candidate.Instance
+
+	logrus.WithFields(logrus.Fields{
+		"id":        device.ID,
+		"id_device": device.IDDevice.String,
+		"provider":  providerName,
+	}).Info("🔑 CREDENTIALS: Provider credential rotated")
+
+	// Re-register the message webhook where the provider API supports it
+	if registrar, ok := provider.(WebhookRegistrar); ok {
+		if err := registrar.RegisterWebhook(device); err != nil {
+			logrus.WithError(err).WithField("id_device", device.IDDevice.String).
+				Warn("🔑 CREDENTIALS: Webhook re-registration failed after rotation")
+			result.WebhookNote = fmt.Sprintf("webhook re-registration failed: %v", err)
+		} else {
+			result.WebhookReregistered = true
+		}
+	} else {
+		result.WebhookNote = fmt.Sprintf("provider %s configures webhooks in its dashboard; nothing to re-register", providerName)
+	}
+
+	// Refresh the health record so sends blocked on the old credential
+	// resume without waiting for the next scheduled check
+	if s.credentialHealth != nil {
+		result.Health = s.credentialHealth.CheckDevice(device)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// CustomNodeService lets partners register node types implemented as
+// external webhooks. When the engine reaches a node whose type carries the
+// custom_ prefix, the endpoint receives the node config plus execution state
+// and answers with effects and a next-node decision. Endpoint calls run
+// inside a sandbox timeout and responses are validated before the engine
+// acts on them
+
+// customNodeDefaultTimeout applies when a registration sets no timeout
+const customNodeDefaultTimeout = 10 * time.Second
+
+// customNodeMaxTimeout caps what a registration may configure
+const customNodeMaxTimeout = 30 * time.Second
+
+// customNodeMaxEffects caps how many effects one response may return
+const customNodeMaxEffects = 5
+
+// customNodeMaxContentLength caps each effect's content and media URL
+const customNodeMaxContentLength = 4096
+
+// customNodeMaxResponseBytes caps how much of the endpoint response is read
+const customNodeMaxResponseBytes = 64 * 1024
+
+// customNodeTypeNamePattern restricts registered names to what the flow
+// editor can store as a node type
+var customNodeTypeNamePattern = regexp.MustCompile(`^custom_[a-z0-9_]{1,48}$`)
+
+// CustomNodeService manages the custom node registry and executes nodes
+// against their endpoints
+type CustomNodeService struct {
+	customNodeRepo repository.CustomNodeRepository
+	httpClient     *http.Client
+}
+
+// NewCustomNodeService creates a new custom node service
+func NewCustomNodeService(customNodeRepo repository.CustomNodeRepository) *CustomNodeService {
+	return &CustomNodeService{
+		customNodeRepo: customNodeRepo,
+		httpClient:     &http.Client{},
+	}
+}
+
+// RegisterType registers a webhook-backed node type with a fresh secret
+func (s *CustomNodeService) RegisterType(idDevice, typeName, endpointURL string, timeoutSeconds int) (*models.CustomNodeType, error) {
+	typeName = strings.TrimSpace(strings.ToLower(typeName))
+	if !customNodeTypeNamePattern.MatchString(typeName) {
+		return nil, fmt.Errorf("type_name must match %s (e.g. custom_crm_lookup)", customNodeTypeNamePattern.String())
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(endpointURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("endpoint_url must be a valid http(s) URL")
+	}
+
+	if timeoutSeconds < 0 || time.Duration(timeoutSeconds)*time.Second > customNodeMaxTimeout {
+		return nil, fmt.Errorf("timeout_seconds must be between 0 and %d", int(customNodeMaxTimeout/time.Second))
+	}
+
+	existing, err := s.customNodeRepo.GetEnabledCustomNodeType(idDevice, typeName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("type %s is already registered for this device", typeName)
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	nodeType := &models.CustomNodeType{
+		ID:             uuid.New().String(),
+		IDDevice:       idDevice,
+		TypeName:       typeName,
+		EndpointURL:    parsed.String(),
+		Secret:         secret,
+		TimeoutSeconds: timeoutSeconds,
+		Enabled:        true,
+	}
+
+	if err := s.customNodeRepo.CreateCustomNodeType(nodeType); err != nil {
+		return nil, fmt.Errorf("failed to register custom node type: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device": idDevice,
+		"type_name": typeName,
+		"endpoint":  nodeType.EndpointURL,
+	}).Info("🧩 CUSTOM NODE: Type registered")
+
+	return nodeType, nil
+}
+
+// GetTypes returns a device's registered node types
+func (s *CustomNodeService) GetTypes(idDevice string) ([]models.CustomNodeType, error) {
+	return s.customNodeRepo.GetCustomNodeTypesByDevice(idDevice)
+}
+
+// GetEnabledType returns the device's enabled registration for a type name,
+// or nil when none exists
+func (s *CustomNodeService) GetEnabledType(idDevice, typeName string) (*models.CustomNodeType, error) {
+	return s.customNodeRepo.GetEnabledCustomNodeType(idDevice, typeName)
+}
+
+// SetTypeEnabled toggles a registration, scoped to the owning device
+func (s *CustomNodeService) SetTypeEnabled(id, idDevice string, enabled bool) error {
+	return s.customNodeRepo.SetCustomNodeTypeEnabled(id, idDevice, enabled)
+}
+
+// DeleteType removes a registration, scoped to the owning device
+func (s *CustomNodeService) DeleteType(id, idDevice string) error {
+	return s.customNodeRepo.DeleteCustomNodeType(id, idDevice)
+}
+
+// Execute posts the node config and execution state to the registered
+// endpoint and returns its validated result
+func (s *CustomNodeService) Execute(nodeType *models.CustomNodeType, request *models.CustomNodeRequest) (*models.CustomNodeResult, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal custom node request: %w", err)
+	}
+
+	timeout := customNodeDefaultTimeout
+	if nodeType.TimeoutSeconds > 0 {
+		timeout = time.Duration(nodeType.TimeoutSeconds) * time.Second
+	}
+	if timeout > customNodeMaxTimeout {
+		timeout = customNodeMaxTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, nodeType.EndpointURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build custom node request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Nodepath-Node-Type", nodeType.TypeName)
+	req.Header.Set("X-Nodepath-Signature", signWebhookPayload(nodeType.Secret, payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("custom node endpoint call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("custom node endpoint returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, customNodeMaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom node response: %w", err)
+	}
+
+	var result models.CustomNodeResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("custom node endpoint returned invalid JSON: %w", err)
+	}
+
+	if err := validateCustomNodeResult(&result); err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"type_name": nodeType.TypeName,
+		"node_id":   request.NodeID,
+		"effects":   len(result.Effects),
+		"next_node": result.NextNodeID,
+	}).Info("🧩 CUSTOM NODE: Endpoint executed")
+
+	return &result, nil
+}
+
+// validateCustomNodeResult enforces the response schema before the engine
+// acts on an endpoint's answer
+func validateCustomNodeResult(result *models.CustomNodeResult) error {
+	if len(result.Effects) > customNodeMaxEffects {
+		return fmt.Errorf("custom node returned %d effects, maximum is %d", len(result.Effects), customNodeMaxEffects)
+	}
+
+	for i := range result.Effects {
+		effect := &result.Effects[i]
+		switch effect.Type {
+		case models.CustomNodeEffectMessage:
+			if effect.Content == "" {
+				return fmt.Errorf("message effect %d has no content", i)
+			}
+		case models.CustomNodeEffectImage, models.CustomNodeEffectAudio, models.CustomNodeEffectVideo:
+			parsed, err := url.Parse(effect.MediaURL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return fmt.Errorf("%s effect %d needs a valid http(s) media_url", effect.Type, i)
+			}
+		default:
+			return fmt.Errorf("effect %d has unknown type %q", i, effect.Type)
+		}
+
+		if len(effect.Content) > customNodeMaxContentLength || len(effect.MediaURL) > customNodeMaxContentLength {
+			return fmt.Errorf("effect %d exceeds the %d character limit", i, customNodeMaxContentLength)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DeadLetterService manages the webhook dead-letter queue: messages that
+// exhausted their retries land here for operator review and requeueing
+
+// DeadLetterService records and serves dead-lettered webhook messages
+type DeadLetterService struct {
+	failedMessageRepo repository.FailedMessageRepository
+}
+
+// NewDeadLetterService creates a new dead letter service
+func NewDeadLetterService(failedMessageRepo repository.FailedMessageRepository) *DeadLetterService {
+	return &DeadLetterService{failedMessageRepo: failedMessageRepo}
+}
+
+// Record dead-letters a webhook message after its final failed attempt
+func (s *DeadLetterService) Record(message *models.FailedMessage) error {
+	if err := s.failedMessageRepo.Insert(message); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id":           message.ID,
+		"device_id":    message.DeviceID,
+		"prospect_num": message.ProspectNum,
+		"retries":      message.Retries,
+		"last_error":   message.LastError,
+	}).Warn("💀 DEAD LETTER: Webhook message dead-lettered after exhausting retries")
+
+	return nil
+}
+
+// List returns the newest dead-lettered messages for a device
+func (s *DeadLetterService) List(deviceID string, limit int) ([]models.FailedMessage, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.failedMessageRepo.ListByDevice(deviceID, limit)
+}
+
+// Get returns one dead-lettered message, nil when it does not exist
+func (s *DeadLetterService) Get(id int64) (*models.FailedMessage, error) {
+	return s.failedMessageRepo.GetByID(id)
+}
+
+// Remove deletes a dead-lettered message after a requeue or discard
+func (s *DeadLetterService) Remove(id int64) error {
+	return s.failedMessageRepo.Delete(id)
+}
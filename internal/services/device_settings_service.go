@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
 	"nodepath-chat/internal/utils"
 
 	"github.com/google/uuid"
@@ -619,6 +620,33 @@ func (s *DeviceSettingsService) Update(id string, req *models.UpdateDeviceSettin
 	return existing, nil
 }
 
+// SwapInstance atomically replaces a device's provider credential. The update
+// only applies while the stored credential still matches the one the caller
+// read, so concurrent rotations fail loudly instead of overwriting each other
+func (s *DeviceSettingsService) SwapInstance(id string, currentInstance sql.NullString, newInstance string) error {
+	query := `
+		UPDATE device_setting
+		SET instance = ?, updated_at = ?
+		WHERE id = ? AND instance IS NOT DISTINCT FROM ?
+	`
+
+	result, err := s.db.Exec(repository.Rebind(query), newInstance, time.Now(), id, currentInstance)
+	if err != nil {
+		return fmt.Errorf("failed to swap device credential: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to confirm credential swap: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("credential was changed by another request; reload the device and retry")
+	}
+
+	logrus.WithField("id", id).Info("Device credential swapped")
+	return nil
+}
+
 // Delete deletes a device setting
 func (s *DeviceSettingsService) Delete(id string) error {
 	// Check if device setting exists
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/smtp"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// DigestService generates per-device digest reports (messages, new
+// prospects, stage movement, AI usage, top intents) on a daily and weekly
+// schedule, stores them for the API and emails them to the device owner when
+// SMTP is configured. Message volume is counted in-memory per day; database
+// tables supply prospects and stages
+
+// digestRunHour is the local hour scheduled digests are generated at
+const digestRunHour = 8
+
+// digestTopIntents is how many intents a digest lists
+const digestTopIntents = 5
+
+// digestRetentionDays is how many daily message-counter buckets are kept
+const digestRetentionDays = 35
+
+// digestMessageDay accumulates one device's message volume for one day
+type digestMessageDay struct {
+	in  int64
+	out int64
+}
+
+// DigestService computes, stores and delivers digest reports
+type DigestService struct {
+	digestRepo repository.DigestReportRepository
+	aiService  *AIService
+
+	mu       sync.Mutex
+	messages map[string]map[string]*digestMessageDay
+
+	lastDailyRun  string
+	lastWeeklyRun string
+	done          chan struct{}
+}
+
+// NewDigestService creates a new digest service
+func NewDigestService(digestRepo repository.DigestReportRepository, aiService *AIService) *DigestService {
+	return &DigestService{
+		digestRepo: digestRepo,
+		aiService:  aiService,
+		messages:   make(map[string]map[string]*digestMessageDay),
+		done:       make(chan struct{}),
+	}
+}
+
+// messageDay returns the device's counter bucket for the day, creating it if
+// needed. Caller must hold the mutex
+func (s *DigestService) messageDay(idDevice string, t time.Time) *digestMessageDay {
+	days, ok := s.messages[idDevice]
+	if !ok {
+		days = make(map[string]*digestMessageDay)
+		s.messages[idDevice] = days
+	}
+
+	key := t.Format("2006-01-02")
+	day, ok := days[key]
+	if !ok {
+		day = &digestMessageDay{}
+		days[key] = day
+
+		horizon := t.AddDate(0, 0, -digestRetentionDays).Format("2006-01-02")
+		for date := range days {
+			if date < horizon {
+				delete(days, date)
+			}
+		}
+	}
+	return day
+}
+
+// CountInbound counts one received prospect message for a device
+func (s *DigestService) CountInbound(idDevice string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messageDay(idDevice, time.Now()).in++
+}
+
+// CountOutbound counts one successfully sent message for a device
+func (s *DigestService) CountOutbound(idDevice string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messageDay(idDevice, time.Now()).out++
+}
+
+// messagesBetween sums the device's message counters for [from, to)
+func (s *DigestService) messagesBetween(idDevice string, from, to time.Time) (in, out int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fromKey := from.Format("2006-01-02")
+	toKey := to.Format("2006-01-02")
+	for date, day := range s.messages[idDevice] {
+		if date < fromKey || date >= toKey {
+			continue
+		}
+		in += day.in
+		out += day.out
+	}
+	return in, out
+}
+
+// GenerateDigest computes, stores and returns one digest for the period
+// ending at end
+func (s *DigestService) GenerateDigest(idDevice, period string, end time.Time) (*models.DigestReport, error) {
+	var start time.Time
+	switch period {
+	case models.DigestPeriodDaily:
+		start = end.AddDate(0, 0, -1)
+	case models.DigestPeriodWeekly:
+		start = end.AddDate(0, 0, -7)
+	default:
+		return nil, fmt.Errorf("period must be %q or %q", models.DigestPeriodDaily, models.DigestPeriodWeekly)
+	}
+
+	metrics := models.DigestMetrics{StageCounts: make(map[string]int64), TopIntents: make([]models.DigestIntent, 0)}
+	metrics.MessagesIn, metrics.MessagesOut = s.messagesBetween(idDevice, start, end)
+
+	if newProspects, err := s.digestRepo.CountNewProspects(idDevice, start, end); err == nil {
+		metrics.NewProspects = newProspects
+	}
+	if stages, err := s.digestRepo.GetStageMovement(idDevice, start, end); err == nil {
+		metrics.StageCounts = stages
+	}
+
+	usage := s.aiService.UsageBetween(idDevice, start, end, digestTopIntents)
+	metrics.AIRequests = usage.Requests
+	metrics.AITokens = usage.TotalTokens
+	metrics.AICostUSD = usage.EstimatedCostUSD
+	for _, intent := range usage.TopIntents {
+		metrics.TopIntents = append(metrics.TopIntents, models.DigestIntent{Intent: intent.Intent, Count: intent.Count})
+	}
+
+	reportJSON, err := json.Marshal(metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal digest metrics: %w", err)
+	}
+
+	report := &models.DigestReport{
+		ID:          uuid.New().String(),
+		IDDevice:    idDevice,
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Report:      reportJSON,
+	}
+
+	if err := s.digestRepo.CreateDigestReport(report); err != nil {
+		return nil, fmt.Errorf("failed to store digest report: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device": idDevice,
+		"period":    period,
+	}).Info("📊 DIGEST: Digest report generated")
+
+	return report, nil
+}
+
+// GetReports returns a device's stored digests, newest first
+func (s *DigestService) GetReports(idDevice string, limit int) ([]models.DigestReport, error) {
+	return s.digestRepo.GetDigestReportsByDevice(idDevice, limit)
+}
+
+// StartScheduler runs scheduled digest generation until Stop is called.
+// Call in a goroutine
+func (s *DigestService) StartScheduler() {
+	logrus.Info("📊 DIGEST: Digest scheduler started")
+	ticker := time.NewTicker(15 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.runScheduled(now)
+		}
+	}
+}
+
+// Stop stops the scheduler
+func (s *DigestService) Stop() {
+	close(s.done)
+}
+
+// runScheduled generates the daily digests once per day after the run hour,
+// plus the weekly digests on Mondays
+func (s *DigestService) runScheduled(now time.Time) {
+	if now.Hour() < digestRunHour {
+		return
+	}
+
+	today := now.Format("2006-01-02")
+	if s.lastDailyRun != today {
+		s.lastDailyRun = today
+		s.runForAllDevices(models.DigestPeriodDaily, now)
+	}
+	if now.Weekday() == time.Monday && s.lastWeeklyRun != today {
+		s.lastWeeklyRun = today
+		s.runForAllDevices(models.DigestPeriodWeekly, now)
+	}
+}
+
+// runForAllDevices generates and delivers one digest per known device
+func (s *DigestService) runForAllDevices(period string, end time.Time) {
+	deviceIDs, err := s.digestRepo.GetActiveDeviceIDs()
+	if err != nil {
+		logrus.WithError(err).Error("📊 DIGEST: Failed to list devices for scheduled run")
+		return
+	}
+
+	for _, idDevice := range deviceIDs {
+		report, err := s.GenerateDigest(idDevice, period, end)
+		if err != nil {
+			logrus.WithError(err).WithField("id_device", idDevice).Error("📊 DIGEST: Failed to generate scheduled digest")
+			continue
+		}
+		s.deliverByEmail(report)
+	}
+}
+
+// deliverByEmail sends the digest to the device owner. Delivery is skipped
+// quietly when SMTP is not configured or the owner has no email
+func (s *DigestService) deliverByEmail(report *models.DigestReport) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USERNAME")
+	}
+
+	recipient, err := s.digestRepo.GetOwnerEmail(report.IDDevice)
+	if err != nil || recipient == "" {
+		return
+	}
+
+	subject := fmt.Sprintf("%s digest for device %s", strings.ToUpper(report.Period[:1])+report.Period[1:], report.IDDevice)
+	body := s.renderEmailBody(report)
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s", from, recipient, subject, body)
+
+	var auth smtp.Auth
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	if err := smtp.SendMail(host+":"+port, auth, from, []string{recipient}, []byte(message)); err != nil {
+		logrus.WithError(err).WithField("id_device", report.IDDevice).Warn("📊 DIGEST: Failed to email digest")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device": report.IDDevice,
+		"period":    report.Period,
+	}).Info("📊 DIGEST: Digest emailed to device owner")
+}
+
+// renderEmailBody renders the digest metrics as a plain-text email
+func (s *DigestService) renderEmailBody(report *models.DigestReport) string {
+	var metrics models.DigestMetrics
+	if err := json.Unmarshal(report.Report, &metrics); err != nil {
+		return string(report.Report)
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Digest for device %s (%s to %s)\n\n",
+		report.IDDevice, report.PeriodStart.Format("2 Jan 2006"), report.PeriodEnd.Format("2 Jan 2006"))
+	fmt.Fprintf(&builder, "Messages received: %d\n", metrics.MessagesIn)
+	fmt.Fprintf(&builder, "Messages sent: %d\n", metrics.MessagesOut)
+	fmt.Fprintf(&builder, "New prospects: %d\n", metrics.NewProspects)
+	fmt.Fprintf(&builder, "AI requests: %d (%d tokens, ~$%.2f)\n", metrics.AIRequests, metrics.AITokens, metrics.AICostUSD)
+
+	if len(metrics.StageCounts) > 0 {
+		builder.WriteString("\nStage movement:\n")
+		stages := make([]string, 0, len(metrics.StageCounts))
+		for stage := range metrics.StageCounts {
+			stages = append(stages, stage)
+		}
+		sort.Strings(stages)
+		for _, stage := range stages {
+			fmt.Fprintf(&builder, "  %s: %d\n", stage, metrics.StageCounts[stage])
+		}
+	}
+
+	if len(metrics.TopIntents) > 0 {
+		builder.WriteString("\nTop intents:\n")
+		for _, intent := range metrics.TopIntents {
+			fmt.Fprintf(&builder, "  %s: %d\n", intent.Intent, intent.Count)
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DocumentService fills PDF document templates (invoices, quotes) with flow
+// variables, stores the rendered file through MediaService and hands back the
+// URL to send as a document message
+
+// documentLineItemsPlaceholder expands a slice variable into one rendered
+// line per item when a template line contains only this placeholder
+const documentLineItemsPlaceholder = "{{line_items}}"
+
+// DocumentService renders and manages PDF document templates
+type DocumentService struct {
+	templateRepo repository.DocumentTemplateRepository
+	mediaService *MediaService
+}
+
+// NewDocumentService creates a new document service
+func NewDocumentService(templateRepo repository.DocumentTemplateRepository, mediaService *MediaService) *DocumentService {
+	return &DocumentService{
+		templateRepo: templateRepo,
+		mediaService: mediaService,
+	}
+}
+
+// CreateTemplate validates and stores a new template
+func (s *DocumentService) CreateTemplate(template *models.DocumentTemplate) error {
+	if template.Name == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if len(template.Lines) == 0 {
+		return fmt.Errorf("template needs at least one line")
+	}
+
+	existing, err := s.templateRepo.GetByName(template.UserID, template.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("a template named %s already exists", template.Name)
+	}
+
+	return s.templateRepo.Insert(template)
+}
+
+// GetTemplate returns one template, nil when it does not exist
+func (s *DocumentService) GetTemplate(id int64) (*models.DocumentTemplate, error) {
+	return s.templateRepo.GetByID(id)
+}
+
+// ListTemplates returns all templates owned by a user
+func (s *DocumentService) ListTemplates(userID string) ([]models.DocumentTemplate, error) {
+	return s.templateRepo.ListByUser(userID)
+}
+
+// DeleteTemplate removes a user's template
+func (s *DocumentService) DeleteTemplate(id int64, userID string) error {
+	return s.templateRepo.Delete(id, userID)
+}
+
+// RenderTemplate fills the template with variables and returns the PDF bytes
+func (s *DocumentService) RenderTemplate(template *models.DocumentTemplate, variables map[string]interface{}) []byte {
+	title := substituteDocumentVariables(template.Title, variables)
+
+	var lines []PDFLine
+	for _, line := range template.Lines {
+		if strings.TrimSpace(line.Text) == documentLineItemsPlaceholder {
+			for _, item := range documentLineItems(variables) {
+				lines = append(lines, PDFLine{Text: item, Size: line.Size, Bold: line.Bold})
+			}
+			continue
+		}
+		lines = append(lines, PDFLine{
+			Text: substituteDocumentVariables(line.Text, variables),
+			Size: line.Size,
+			Bold: line.Bold,
+		})
+	}
+
+	return RenderPDF(title, lines)
+}
+
+// GenerateDocument renders the template and stores the PDF under cacheKey,
+// reusing a previously stored render with the same personalized inputs
+func (s *DocumentService) GenerateDocument(cacheKey string, template *models.DocumentTemplate, variables map[string]interface{}) (string, error) {
+	if url, ok := s.mediaService.GeneratedDocumentURL(cacheKey); ok {
+		logrus.WithField("cache_key", cacheKey).Info("📄 DOCUMENT: Reusing cached render")
+		return url, nil
+	}
+
+	data := s.RenderTemplate(template, variables)
+	return s.mediaService.SaveGeneratedDocument(cacheKey, data)
+}
+
+// substituteDocumentVariables replaces {{variable}} placeholders with their
+// execution values
+func substituteDocumentVariables(text string, variables map[string]interface{}) string {
+	for name, value := range variables {
+		placeholder := fmt.Sprintf("{{%s}}", name)
+		if strings.Contains(text, placeholder) {
+			text = strings.ReplaceAll(text, placeholder, fmt.Sprintf("%v", value))
+		}
+	}
+	return text
+}
+
+// documentLineItems flattens the line_items variable into printable lines;
+// slices yield one line per item, anything else its string form split on
+// newlines
+func documentLineItems(variables map[string]interface{}) []string {
+	value, ok := variables["line_items"]
+	if !ok || value == nil {
+		return nil
+	}
+
+	if items, ok := value.([]interface{}); ok {
+		var lines []string
+		for _, item := range items {
+			lines = append(lines, fmt.Sprintf("%v", item))
+		}
+		return lines
+	}
+	return strings.Split(fmt.Sprintf("%v", value), "\n")
+}
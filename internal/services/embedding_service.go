@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Text embeddings through the AI provider's /embeddings endpoint, powering
+// semantic search over conversation history
+
+// embeddingModel balances quality against cost for conversation-sized chunks
+const embeddingModel = "text-embedding-3-small"
+
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// EmbedTexts returns one embedding vector per input text, in input order
+func (s *AIService) EmbedTexts(texts []string, apiKey, deviceID string) ([][]float64, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	apiKey = s.getAPIKey(apiKey, deviceID)
+	if apiKey == "" {
+		return nil, fmt.Errorf("no API key provided")
+	}
+
+	body, err := json.Marshal(embeddingRequest{Model: embeddingModel, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+
+	// Embeddings always go to the OpenAI endpoint - OpenRouter does not
+	// serve an /embeddings route
+	req, err := http.NewRequest("POST", openAIBaseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding request returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed embeddingResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding response: %w", err)
+	}
+	if len(parsed.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding response returned %d vectors for %d inputs", len(parsed.Data), len(texts))
+	}
+
+	vectors := make([][]float64, len(texts))
+	for _, item := range parsed.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			continue
+		}
+		vectors[item.Index] = item.Embedding
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"inputs":    len(texts),
+		"device_id": deviceID,
+	}).Debug("🧠 EMBED: Generated embeddings")
+
+	return vectors, nil
+}
+
+// EmbedText embeds a single text
+func (s *AIService) EmbedText(text, apiKey, deviceID string) ([]float64, error) {
+	vectors, err := s.EmbedTexts([]string{text}, apiKey, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) == 0 || vectors[0] == nil {
+		return nil, fmt.Errorf("embedding response was empty")
+	}
+	return vectors[0], nil
+}
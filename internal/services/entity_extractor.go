@@ -0,0 +1,155 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entity extraction pulls structured values (appointment dates, order
+// numbers, amounts) out of free text via the AI provider, then validates
+// every value against its declared type before it reaches flow variables -
+// the model's output is never trusted as-is.
+
+// entityExtractorModel is the cheap model used for extraction; like intent
+// routing this is a labeling task, not a conversational one
+const entityExtractorModel = "openai/gpt-4o-mini"
+
+// entityDateLayouts are the formats an extracted date may arrive in; values
+// are normalized to the first layout
+var entityDateLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"02-01-2006",
+	"2 January 2006",
+	"January 2, 2006",
+	time.RFC3339,
+}
+
+// ExtractEntities asks the AI provider to pull the requested entities out of
+// the user message. The reply must be a JSON object keyed by entity name with
+// null for anything the message does not contain; each value is validated
+// against the field's type and invalid or missing entities are dropped
+func (s *AIService) ExtractEntities(userInput string, fields []models.EntityField, apiKey, deviceID string) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no entity fields provided")
+	}
+
+	apiKey = s.getAPIKey(apiKey, deviceID)
+	if apiKey == "" {
+		return nil, fmt.Errorf("no API key provided")
+	}
+
+	var schema strings.Builder
+	for _, field := range fields {
+		fieldType := field.Type
+		if fieldType == "" {
+			fieldType = models.EntityTypeText
+		}
+		schema.WriteString(fmt.Sprintf("- %q (%s)", field.Name, fieldType))
+		if field.Description != "" {
+			schema.WriteString(": " + field.Description)
+		}
+		schema.WriteString("\n")
+	}
+
+	systemPrompt := "You are an entity extractor. Extract these fields from the user message:\n" +
+		schema.String() +
+		"Respond with ONLY a JSON object keyed by field name and no other text. " +
+		"Use null for fields the message does not contain. " +
+		"Format dates as YYYY-MM-DD and numbers as plain numbers without currency symbols."
+
+	request := models.OpenRouterRequest{
+		Model:             entityExtractorModel,
+		Messages:          s.buildMessages(systemPrompt, userInput, nil),
+		Stream:            false,
+		Temperature:       0, // Extraction should be deterministic
+		TopP:              1.0,
+		RepetitionPenalty: 1.0,
+	}
+
+	response, err := s.makeOpenRouterRequest(request, apiKey, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("entity extraction request failed: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("entity extraction returned no choices")
+	}
+
+	content := strings.TrimSpace(response.Choices[0].Message.Content)
+	// Models occasionally wrap the JSON in code fences - cut them away
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse extracted entities %q: %w", content, err)
+	}
+
+	extracted := make(map[string]interface{})
+	for _, field := range fields {
+		value, ok := validateEntityValue(field, raw[field.Name])
+		if !ok {
+			continue
+		}
+		extracted[field.Name] = value
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"requested": len(fields),
+		"extracted": len(extracted),
+		"device_id": deviceID,
+	}).Info("🔎 ENTITY: Extracted entities from user message")
+
+	return extracted, nil
+}
+
+// validateEntityValue checks a raw extracted value against the field's type
+// and returns the normalized value, or ok=false when the value is missing or
+// does not fit the type
+func validateEntityValue(field models.EntityField, raw interface{}) (interface{}, bool) {
+	if raw == nil {
+		return nil, false
+	}
+
+	switch field.Type {
+	case models.EntityTypeNumber:
+		switch value := raw.(type) {
+		case float64:
+			return value, true
+		case string:
+			cleaned := strings.TrimSpace(strings.ReplaceAll(value, ",", ""))
+			if number, err := strconv.ParseFloat(cleaned, 64); err == nil {
+				return number, true
+			}
+		}
+		return nil, false
+	case models.EntityTypeDate:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, false
+		}
+		text = strings.TrimSpace(text)
+		for _, layout := range entityDateLayouts {
+			if date, err := time.Parse(layout, text); err == nil {
+				return date.Format("2006-01-02"), true
+			}
+		}
+		return nil, false
+	default:
+		// Text fields accept whatever the model returned, stringified
+		text := strings.TrimSpace(fmt.Sprintf("%v", raw))
+		if text == "" {
+			return nil, false
+		}
+		return text, true
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlowDebugService streams engine internals (node entered, condition result,
+// AI prompt, variable snapshots) over WebSocket to the flow editor's debug
+// panel. Debugging is toggled per conversation so a test prospect can be
+// inspected live without flooding the editor with production traffic.
+
+// flowDebugEventType is the WebSocket message type the editor listens for
+const flowDebugEventType = "flow_debug"
+
+// FlowDebugSession is one conversation currently running in debug mode
+type FlowDebugSession struct {
+	IDDevice    string    `json:"id_device"`
+	ProspectNum string    `json:"prospect_num"`
+	EnabledAt   time.Time `json:"enabled_at"`
+}
+
+// FlowDebugService tracks which conversations are in debug mode and relays
+// their engine events to the device's WebSocket connection
+type FlowDebugService struct {
+	websocketService *WebSocketService
+	sessions         map[string]*FlowDebugSession
+	mu               sync.RWMutex
+}
+
+// NewFlowDebugService creates a new flow debug service
+func NewFlowDebugService(websocketService *WebSocketService) *FlowDebugService {
+	return &FlowDebugService{
+		websocketService: websocketService,
+		sessions:         make(map[string]*FlowDebugSession),
+	}
+}
+
+func debugSessionKey(idDevice, prospectNum string) string {
+	return idDevice + "|" + prospectNum
+}
+
+// Enable switches a conversation into debug mode
+func (s *FlowDebugService) Enable(idDevice, prospectNum string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[debugSessionKey(idDevice, prospectNum)] = &FlowDebugSession{
+		IDDevice:    idDevice,
+		ProspectNum: prospectNum,
+		EnabledAt:   time.Now(),
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device":    idDevice,
+		"prospect_num": prospectNum,
+	}).Info("🐞 DEBUG: Flow debug mode enabled for conversation")
+}
+
+// Disable switches a conversation back to normal processing
+func (s *FlowDebugService) Disable(idDevice, prospectNum string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, debugSessionKey(idDevice, prospectNum))
+
+	logrus.WithFields(logrus.Fields{
+		"id_device":    idDevice,
+		"prospect_num": prospectNum,
+	}).Info("🐞 DEBUG: Flow debug mode disabled for conversation")
+}
+
+// IsEnabled reports whether a conversation is in debug mode. Callers should
+// check this before assembling expensive event payloads
+func (s *FlowDebugService) IsEnabled(idDevice, prospectNum string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, enabled := s.sessions[debugSessionKey(idDevice, prospectNum)]
+	return enabled
+}
+
+// Sessions lists the conversations currently in debug mode
+func (s *FlowDebugService) Sessions() []*FlowDebugSession {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sessions := make([]*FlowDebugSession, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// Emit streams one engine event to the device's editor connection. Events
+// for conversations not in debug mode are dropped silently
+func (s *FlowDebugService) Emit(idDevice, prospectNum, event string, data map[string]interface{}) {
+	if !s.IsEnabled(idDevice, prospectNum) {
+		return
+	}
+
+	s.websocketService.SendToDevice(idDevice, flowDebugEventType, map[string]interface{}{
+		"prospect_num": prospectNum,
+		"event":        event,
+		"timestamp":    time.Now().UTC().Format(time.RFC3339),
+		"data":         data,
+	})
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Edge routing extends the simple next-node link with two editor features:
+// per-edge condition expressions evaluated against the user input and execution
+// variables, and weighted-random selection across a node's outgoing edges so a
+// single flow can A/B test message copy.
+
+// GetNextNodeWithContext finds the next node from currentNodeID taking edge
+// conditions and weights into account. Conditioned edges are checked first
+// against the user input and variables; when none match, the unconditioned
+// edges are used, weighted-randomly if any carries a weight
+func (s *FlowService) GetNextNodeWithContext(flow *models.ChatbotFlow, currentNodeID, userInput string, variables map[string]interface{}) (*models.FlowNode, error) {
+	edges, err := s.GetFlowEdges(flow)
+	if err != nil {
+		return nil, err
+	}
+
+	var outgoing []*models.FlowEdge
+	for _, edge := range edges {
+		if edge.Source == currentNodeID {
+			outgoing = append(outgoing, edge)
+		}
+	}
+
+	if len(outgoing) == 0 {
+		return nil, fmt.Errorf("no next node found for node %s", currentNodeID)
+	}
+
+	selected := selectOutgoingEdge(outgoing, userInput, variables)
+	if selected == nil {
+		return nil, fmt.Errorf("no edge condition matched for node %s", currentNodeID)
+	}
+
+	return s.FindNodeByID(flow, selected.Target)
+}
+
+// selectOutgoingEdge picks one edge: the first conditioned edge whose expression
+// matches wins; otherwise selection falls back to the unconditioned edges,
+// weighted-randomly when weights are set
+func selectOutgoingEdge(edges []*models.FlowEdge, userInput string, variables map[string]interface{}) *models.FlowEdge {
+	var fallback []*models.FlowEdge
+	for _, edge := range edges {
+		if edge.Condition == "" {
+			fallback = append(fallback, edge)
+			continue
+		}
+		if EvaluateEdgeCondition(edge.Condition, userInput, variables) {
+			logrus.WithFields(logrus.Fields{
+				"edge_id":   edge.ID,
+				"condition": edge.Condition,
+			}).Debug("Edge condition matched")
+			return edge
+		}
+	}
+
+	if len(fallback) == 0 {
+		return nil
+	}
+
+	totalWeight := 0.0
+	for _, edge := range fallback {
+		if edge.Weight > 0 {
+			totalWeight += edge.Weight
+		}
+	}
+	if totalWeight <= 0 {
+		return fallback[0]
+	}
+
+	// Weighted-random pick for A/B branches; edges without a weight are skipped
+	roll := rand.Float64() * totalWeight
+	for _, edge := range fallback {
+		if edge.Weight <= 0 {
+			continue
+		}
+		roll -= edge.Weight
+		if roll <= 0 {
+			return edge
+		}
+	}
+	return fallback[len(fallback)-1]
+}
+
+// EvaluateEdgeCondition evaluates an expression of the form
+// `<subject> <operator> "<value>"` where subject is `input` or a variable name
+// and operator is one of ==, !=, contains, not_contains. Comparison is
+// case-insensitive; an unparseable expression never matches
+func EvaluateEdgeCondition(condition, userInput string, variables map[string]interface{}) bool {
+	subject, operator, value, ok := parseEdgeCondition(condition)
+	if !ok {
+		logrus.WithField("condition", condition).Warn("Unparseable edge condition, treating as no match")
+		return false
+	}
+
+	actual := userInput
+	if subject != "input" {
+		actual = ""
+		if variables != nil {
+			if varValue, exists := variables[subject]; exists {
+				actual = fmt.Sprintf("%v", varValue)
+			}
+		}
+	}
+
+	actualLower := strings.ToLower(strings.TrimSpace(actual))
+	valueLower := strings.ToLower(value)
+
+	switch operator {
+	case "==":
+		return actualLower == valueLower
+	case "!=":
+		return actualLower != valueLower
+	case "contains":
+		return strings.Contains(actualLower, valueLower)
+	case "not_contains":
+		return !strings.Contains(actualLower, valueLower)
+	default:
+		return false
+	}
+}
+
+// parseEdgeCondition splits a condition into subject, operator and value,
+// accepting an optionally quoted value
+func parseEdgeCondition(condition string) (subject, operator, value string, ok bool) {
+	fields := strings.Fields(strings.TrimSpace(condition))
+	if len(fields) < 3 {
+		return "", "", "", false
+	}
+
+	subject = fields[0]
+	operator = fields[1]
+	switch operator {
+	case "==", "!=", "contains", "not_contains":
+	default:
+		return "", "", "", false
+	}
+
+	value = strings.Join(fields[2:], " ")
+	value = strings.Trim(value, `"'`)
+	return subject, operator, value, true
+}
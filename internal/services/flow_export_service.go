@@ -0,0 +1,188 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Flow export/import: a flow serializes to a portable JSON document that can
+// be re-imported into another environment or device. Import assigns fresh
+// node and edge IDs (remapping every cross-reference) so an imported flow
+// never collides with an existing one
+
+// flowExportFormat versions the export document so future shape changes can
+// stay importable
+const flowExportFormat = "nodepath-flow/1"
+
+// FlowExport is the portable JSON document for one flow. The device binding
+// is intentionally left out - the importer picks the target device
+type FlowExport struct {
+	Format     string             `json:"format"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Name       string             `json:"name"`
+	Niche      string             `json:"niche,omitempty"`
+	Nodes      []*models.FlowNode `json:"nodes"`
+	Edges      []*models.FlowEdge `json:"edges"`
+}
+
+// FlowExportService serializes flows to portable documents and imports them back
+type FlowExportService struct {
+	flowService *FlowService
+}
+
+// NewFlowExportService creates a new flow export service
+func NewFlowExportService(flowService *FlowService) *FlowExportService {
+	return &FlowExportService{flowService: flowService}
+}
+
+// ExportFlow serializes the flow into a portable document. Returns nil when
+// the flow does not exist
+func (s *FlowExportService) ExportFlow(flowID string) (*FlowExport, error) {
+	flow, err := s.flowService.GetFlow(flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow for export: %w", err)
+	}
+	if flow == nil {
+		return nil, nil
+	}
+
+	nodes, err := s.flowService.GetFlowNodes(flow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flow nodes for export: %w", err)
+	}
+	edges, err := s.flowService.GetFlowEdges(flow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flow edges for export: %w", err)
+	}
+
+	return &FlowExport{
+		Format:     flowExportFormat,
+		ExportedAt: time.Now(),
+		Name:       flow.Name,
+		Niche:      flow.Niche,
+		Nodes:      nodes,
+		Edges:      edges,
+	}, nil
+}
+
+// ImportFlow creates a new flow on the target device from an export
+// document. Every node and edge gets a fresh ID, with edge endpoints and
+// any node data values naming an old node ID remapped to the new ones
+func (s *FlowExportService) ImportFlow(export *FlowExport, idDevice, name string) (*models.ChatbotFlow, error) {
+	if export.Format != "" && export.Format != flowExportFormat {
+		return nil, fmt.Errorf("unsupported export format %q", export.Format)
+	}
+	if len(export.Nodes) == 0 {
+		return nil, fmt.Errorf("export document contains no nodes")
+	}
+
+	// Fresh IDs for every node, remembered so references can follow
+	idMap := make(map[string]string, len(export.Nodes))
+	for _, node := range export.Nodes {
+		idMap[node.ID] = uuid.New().String()
+	}
+
+	nodes := make([]*models.FlowNode, 0, len(export.Nodes))
+	for _, node := range export.Nodes {
+		imported := *node
+		imported.ID = idMap[node.ID]
+		imported.Data = remapNodeData(node.Data, idMap)
+		nodes = append(nodes, &imported)
+	}
+
+	edges := make([]*models.FlowEdge, 0, len(export.Edges))
+	for _, edge := range export.Edges {
+		imported := *edge
+		imported.ID = uuid.New().String()
+		if mapped, ok := idMap[edge.Source]; ok {
+			imported.Source = mapped
+		}
+		if mapped, ok := idMap[edge.Target]; ok {
+			imported.Target = mapped
+		}
+		edges = append(edges, &imported)
+	}
+
+	nodesJSON, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize imported nodes: %w", err)
+	}
+	edgesJSON, err := json.Marshal(edges)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize imported edges: %w", err)
+	}
+
+	if name == "" {
+		name = export.Name
+	}
+
+	nodesRaw := json.RawMessage(nodesJSON)
+	edgesRaw := json.RawMessage(edgesJSON)
+	flow := &models.ChatbotFlow{
+		Name:     name,
+		Niche:    export.Niche,
+		IdDevice: idDevice,
+		Nodes:    &nodesRaw,
+		Edges:    &edgesRaw,
+	}
+
+	if err := s.flowService.CreateFlow(flow); err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id":   flow.ID,
+		"name":      flow.Name,
+		"id_device": idDevice,
+		"nodes":     len(nodes),
+		"edges":     len(edges),
+	}).Info("📦 FLOW IMPORT: Imported flow from export document")
+
+	return flow, nil
+}
+
+// remapNodeData deep-copies a node's data, replacing any string value that
+// names an exported node ID with the imported one. Node configs reference
+// other nodes by ID in places (e.g. loop targets), and this keeps those
+// references pointing inside the imported flow
+func remapNodeData(data map[string]interface{}, idMap map[string]string) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	remapped := remapValue(data, idMap)
+	if asMap, ok := remapped.(map[string]interface{}); ok {
+		return asMap
+	}
+	return data
+}
+
+// remapValue recursively rebuilds maps and slices, swapping node ID strings
+func remapValue(value interface{}, idMap map[string]string) interface{} {
+	switch typed := value.(type) {
+	case string:
+		if mapped, ok := idMap[typed]; ok {
+			return mapped
+		}
+		return typed
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, entry := range typed {
+			result[key] = remapValue(entry, idMap)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, entry := range typed {
+			result[i] = remapValue(entry, idMap)
+		}
+		return result
+	default:
+		return value
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Join state tracking for parallel branches. Each branch that reaches a join
+// node records its arrival edge; the join releases once every incoming edge
+// has arrived. State lives in flow_join_state_nodepath so forks survive restarts
+
+// RecordBranchArrival marks one parallel branch as arrived at a join node.
+// Recording the same edge twice is a no-op
+func (s *FlowService) RecordBranchArrival(prospectNum, idDevice, flowID, joinNodeID, branchEdgeID string) error {
+	if s.db == nil {
+		logrus.Warn("Database not available, branch arrival not recorded (fallback mode)")
+		return nil
+	}
+
+	query := `
+		INSERT INTO flow_join_state_nodepath
+		(prospect_num, id_device, flow_id, join_node_id, branch_edge_id)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := s.db.Exec(repository.Rebind(query), prospectNum, idDevice, flowID, joinNodeID, branchEdgeID)
+	if err != nil {
+		return fmt.Errorf("failed to record branch arrival: %w", err)
+	}
+
+	return nil
+}
+
+// CountBranchArrivals returns how many branches have reached a join node
+func (s *FlowService) CountBranchArrivals(prospectNum, idDevice, joinNodeID string) (int, error) {
+	if s.db == nil {
+		return 0, nil
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM flow_join_state_nodepath
+		WHERE prospect_num = ? AND id_device = ? AND join_node_id = ?
+	`
+
+	var count int
+	err := s.db.QueryRow(repository.Rebind(query), prospectNum, idDevice, joinNodeID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count branch arrivals: %w", err)
+	}
+
+	return count, nil
+}
+
+// ClearJoinState removes the arrival records for a join node once it releases,
+// so the flow can fork again in a later conversation turn
+func (s *FlowService) ClearJoinState(prospectNum, idDevice, joinNodeID string) error {
+	if s.db == nil {
+		return nil
+	}
+
+	query := `
+		DELETE FROM flow_join_state_nodepath
+		WHERE prospect_num = ? AND id_device = ? AND join_node_id = ?
+	`
+
+	_, err := s.db.Exec(repository.Rebind(query), prospectNum, idDevice, joinNodeID)
+	if err != nil {
+		return fmt.Errorf("failed to clear join state: %w", err)
+	}
+
+	return nil
+}
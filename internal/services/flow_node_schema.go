@@ -0,0 +1,234 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlowValidationError reports node config problems found while saving a flow so
+// the editor can show them next to the offending node
+type FlowValidationError struct {
+	Problems []string
+}
+
+func (e *FlowValidationError) Error() string {
+	return fmt.Sprintf("flow validation failed: %s", strings.Join(e.Problems, "; "))
+}
+
+// nodeDataAliases maps each node type's canonical data key to the legacy alias
+// keys that older editor versions wrote for the same setting
+var nodeDataAliases = map[models.NodeType]map[string][]string{
+	models.NodeTypeStart:         {"contextMaxTurns": {"context_max_turns"}, "contextMaxTokens": {"context_max_tokens"}, "contextExcludeMedia": {"context_exclude_media"}, "contextIncludeSummary": {"context_include_summary"}},
+	models.NodeTypeMessage:       {"message": {"text"}, "quickReplies": {"buttons", "suggestions"}},
+	models.NodeTypeImage:         {"imageUrl": {"image", "mediaUrl"}},
+	models.NodeTypeAudio:         {"audioUrl": {"audio", "mediaUrl"}},
+	models.NodeTypeVideo:         {"videoUrl": {"video", "mediaUrl"}},
+	models.NodeTypeDelay:         {"delay": {"delaySeconds"}},
+	models.NodeTypeManual:        {"skill": {"handoff_skill"}},
+	models.NodeTypeAIPrompt:      {"systemPrompt": {"system_prompt"}, "apiProvider": {"apiprovider"}, "topP": {"top_p"}, "maxTokens": {"max_tokens"}},
+	models.NodeTypeWaitEvent:     {"event": {"event_name"}, "timeoutSeconds": {"timeout"}},
+	models.NodeTypeLoop:          {"iterations": {"count", "times"}, "condition": {"while"}},
+	models.NodeTypeCatalog:       {"message": {"text"}, "pageSize": {"page_size"}, "variable": {"saveAs"}},
+	models.NodeTypeOrderSummary:  {"message": {"text"}},
+	models.NodeTypeVoucher:       {"message": {"text"}, "expiryDays": {"expiry_days"}, "maxRedemptions": {"max_redemptions"}},
+	models.NodeTypeNearestBranch: {"message": {"text"}},
+	models.NodeTypeIntentRouter:  {"minConfidence": {"min_confidence"}},
+	models.NodeTypeForm:          {"confirmMessage": {"confirm_message"}},
+}
+
+// maxLoopIterations caps how many times a loop node may repeat its body so a
+// misconfigured flow cannot spam a prospect indefinitely
+const maxLoopIterations = 25
+
+// maxQuickReplies caps how many suggested replies a message node may declare
+// so the numbered-list fallback stays readable
+const maxQuickReplies = 10
+
+// NormalizeAndValidateFlow rewrites node data onto canonical keys and validates
+// each node's config. Called on every flow save; a *FlowValidationError is
+// returned when a node is misconfigured so the editor can surface it
+func (s *FlowService) NormalizeAndValidateFlow(flow *models.ChatbotFlow) error {
+	nodes, err := s.GetFlowNodes(flow)
+	if err != nil {
+		return &FlowValidationError{Problems: []string{fmt.Sprintf("nodes are not valid JSON: %v", err)}}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	var problems []string
+	for _, node := range nodes {
+		normalizeNodeData(node)
+		if problem := validateNodeConfig(node); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &FlowValidationError{Problems: problems}
+	}
+
+	nodesJSON, err := json.Marshal(nodes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal normalized nodes: %w", err)
+	}
+	normalized := json.RawMessage(nodesJSON)
+	flow.Nodes = &normalized
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id":    flow.ID,
+		"node_count": len(nodes),
+	}).Debug("Flow nodes normalized to canonical config keys")
+
+	return nil
+}
+
+// normalizeNodeData moves legacy alias keys onto the canonical key for the node
+// type, keeping any unrelated data (labels, positions) untouched
+func normalizeNodeData(node *models.FlowNode) {
+	aliases, ok := nodeDataAliases[node.Type]
+	if !ok || node.Data == nil {
+		return
+	}
+
+	for canonical, legacyKeys := range aliases {
+		for _, legacy := range legacyKeys {
+			value, exists := node.Data[legacy]
+			if !exists {
+				continue
+			}
+			if _, hasCanonical := node.Data[canonical]; !hasCanonical {
+				node.Data[canonical] = value
+			}
+			delete(node.Data, legacy)
+		}
+	}
+}
+
+// validateNodeConfig checks a node's typed config and returns a problem
+// description, or "" when the node is valid
+func validateNodeConfig(node *models.FlowNode) string {
+	switch node.Type {
+	case models.NodeTypeStart:
+		config := node.StartConfig()
+		if config.ContextMaxTurns < 0 {
+			return fmt.Sprintf("node %s (start): context max turns cannot be negative", node.ID)
+		}
+		if config.ContextMaxTokens < 0 {
+			return fmt.Sprintf("node %s (start): context max tokens cannot be negative", node.ID)
+		}
+	case models.NodeTypeMessage:
+		config := node.MessageConfig()
+		if config.Message == "" {
+			return fmt.Sprintf("node %s (message): message text is required", node.ID)
+		}
+		if len(config.QuickReplies) > maxQuickReplies {
+			return fmt.Sprintf("node %s (message): at most %d quick replies are allowed", node.ID, maxQuickReplies)
+		}
+	case models.NodeTypeImage:
+		if node.ImageConfig().ImageURL == "" {
+			return fmt.Sprintf("node %s (image): image URL is required", node.ID)
+		}
+	case models.NodeTypeAudio:
+		if node.AudioConfig().AudioURL == "" {
+			return fmt.Sprintf("node %s (audio): audio URL is required", node.ID)
+		}
+	case models.NodeTypeVideo:
+		if node.VideoConfig().VideoURL == "" {
+			return fmt.Sprintf("node %s (video): video URL is required", node.ID)
+		}
+	case models.NodeTypeDelay:
+		if node.DelayConfig().Seconds < 0 {
+			return fmt.Sprintf("node %s (delay): delay cannot be negative", node.ID)
+		}
+	case models.NodeTypeStage:
+		if node.StageConfig().Stage == "" {
+			return fmt.Sprintf("node %s (stage): stage name is required", node.ID)
+		}
+	case models.NodeTypeWaitEvent:
+		if node.WaitEventConfig().Event == "" {
+			return fmt.Sprintf("node %s (wait_event): event name is required", node.ID)
+		}
+	case models.NodeTypeLoop:
+		config := node.LoopConfig()
+		if config.Iterations < 1 && config.Condition == "" {
+			return fmt.Sprintf("node %s (loop): iterations or a condition is required", node.ID)
+		}
+		if config.Iterations > maxLoopIterations {
+			return fmt.Sprintf("node %s (loop): iterations cannot exceed %d", node.ID, maxLoopIterations)
+		}
+	case models.NodeTypeCatalog:
+		if node.CatalogConfig().PageSize < 0 {
+			return fmt.Sprintf("node %s (catalog): page size cannot be negative", node.ID)
+		}
+	case models.NodeTypeVoucher:
+		config := node.VoucherConfig()
+		if config.Discount < 0 {
+			return fmt.Sprintf("node %s (voucher): discount cannot be negative", node.ID)
+		}
+		if config.ExpiryDays < 0 {
+			return fmt.Sprintf("node %s (voucher): expiry days cannot be negative", node.ID)
+		}
+	case models.NodeTypeAIPrompt:
+		config := node.AIPromptConfig()
+		if config.Temperature < 0 || config.Temperature > 2 {
+			return fmt.Sprintf("node %s (ai_prompt): temperature must be between 0 and 2", node.ID)
+		}
+		if config.TopP < 0 || config.TopP > 1 {
+			return fmt.Sprintf("node %s (ai_prompt): top_p must be between 0 and 1", node.ID)
+		}
+		if config.MaxTokens < 0 {
+			return fmt.Sprintf("node %s (ai_prompt): max tokens cannot be negative", node.ID)
+		}
+	case models.NodeTypeIntentRouter:
+		config := node.IntentRouterConfig()
+		if len(config.Intents) == 0 {
+			return fmt.Sprintf("node %s (intent_router): at least one intent is required", node.ID)
+		}
+		if config.MinConfidence < 0 || config.MinConfidence > 1 {
+			return fmt.Sprintf("node %s (intent_router): minimum confidence must be between 0 and 1", node.ID)
+		}
+	case models.NodeTypeEntityExtract:
+		config := node.EntityExtractConfig()
+		if len(config.Entities) == 0 {
+			return fmt.Sprintf("node %s (entity_extract): at least one entity is required", node.ID)
+		}
+		for i, entity := range config.Entities {
+			if entity.Name == "" {
+				return fmt.Sprintf("node %s (entity_extract): entity %d needs a name", node.ID, i+1)
+			}
+			switch entity.Type {
+			case "", models.EntityTypeText, models.EntityTypeNumber, models.EntityTypeDate:
+			default:
+				return fmt.Sprintf("node %s (entity_extract): entity %q has unknown type %q", node.ID, entity.Name, entity.Type)
+			}
+		}
+	case models.NodeTypeForm:
+		config := node.FormConfig()
+		if len(config.Slots) == 0 {
+			return fmt.Sprintf("node %s (form): at least one slot is required", node.ID)
+		}
+		for i, slot := range config.Slots {
+			if slot.Name == "" {
+				return fmt.Sprintf("node %s (form): slot %d needs a name", node.ID, i+1)
+			}
+			switch slot.Type {
+			case "", models.EntityTypeText, models.EntityTypeNumber, models.EntityTypeDate:
+			default:
+				return fmt.Sprintf("node %s (form): slot %q has unknown type %q", node.ID, slot.Name, slot.Type)
+			}
+		}
+	case models.NodeTypeCondition:
+		for i, condition := range node.ConditionConfig().Conditions {
+			if condition.Type != "default" && condition.Value == "" && condition.Label == "" {
+				return fmt.Sprintf("node %s (condition): condition %d needs a value or label", node.ID, i+1)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// orderStatusTransitions defines which lifecycle moves an order may make.
+// Integrations syncing to e-commerce backends rely on these being enforced
+var orderStatusTransitions = map[models.OrderStatus][]models.OrderStatus{
+	models.OrderStatusPending:   {models.OrderStatusConfirmed, models.OrderStatusCancelled},
+	models.OrderStatusConfirmed: {models.OrderStatusPaid, models.OrderStatusCancelled},
+	models.OrderStatusPaid:      {models.OrderStatusFulfilled, models.OrderStatusCancelled},
+	models.OrderStatusFulfilled: {},
+	models.OrderStatusCancelled: {},
+}
+
+// FlowOrderService captures orders from flow conversations and walks them
+// through their status lifecycle
+type FlowOrderService struct {
+	orderRepo repository.FlowOrderRepository
+}
+
+// NewFlowOrderService creates a new flow order service
+func NewFlowOrderService(orderRepo repository.FlowOrderRepository) *FlowOrderService {
+	return &FlowOrderService{orderRepo: orderRepo}
+}
+
+// CreateOrderFromCart snapshots the cart into a pending order
+func (s *FlowOrderService) CreateOrderFromCart(prospectNum, idDevice string, items []models.CartItem) (*models.FlowOrder, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("cannot create an order from an empty cart")
+	}
+
+	order := &models.FlowOrder{
+		ID:          uuid.New().String(),
+		ProspectNum: prospectNum,
+		IDDevice:    idDevice,
+		Status:      models.OrderStatusPending,
+		Total:       CartTotal(items),
+	}
+	for _, item := range items {
+		order.Items = append(order.Items, models.OrderLine{
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			SKU:       item.SKU,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	if err := s.orderRepo.CreateFlowOrder(order); err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"order_id":     order.ID,
+		"prospect_num": prospectNum,
+		"total":        order.Total,
+		"line_count":   len(order.Items),
+	}).Info("✅ ORDER: Order captured from cart")
+
+	return order, nil
+}
+
+// GetOrder returns a single order by ID
+func (s *FlowOrderService) GetOrder(id string) (*models.FlowOrder, error) {
+	return s.orderRepo.GetFlowOrderByID(id)
+}
+
+// GetOrders returns a device's orders, optionally filtered by status
+func (s *FlowOrderService) GetOrders(idDevice, status string) ([]models.FlowOrder, error) {
+	return s.orderRepo.GetFlowOrdersByDevice(idDevice, status)
+}
+
+// UpdateOrderStatus moves an order to a new status, enforcing the lifecycle
+func (s *FlowOrderService) UpdateOrderStatus(id string, status models.OrderStatus) error {
+	order, err := s.orderRepo.GetFlowOrderByID(id)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return fmt.Errorf("order not found: %s", id)
+	}
+
+	allowed := false
+	for _, next := range orderStatusTransitions[order.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("order %s cannot move from %s to %s", id, order.Status, status)
+	}
+
+	if err := s.orderRepo.UpdateFlowOrderStatus(id, status); err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"order_id": id,
+		"from":     order.Status,
+		"to":       status,
+	}).Info("✅ ORDER: Status updated")
+
+	return nil
+}
@@ -44,6 +44,11 @@ func (s *FlowService) CreateFlow(flow *models.ChatbotFlow) error {
 		flow.ID = uuid.New().String()
 	}
 
+	// Normalize node data to canonical keys and reject misconfigured nodes
+	if err := s.NormalizeAndValidateFlow(flow); err != nil {
+		return err
+	}
+
 	flow.CreatedAt = time.Now()
 	flow.UpdatedAt = time.Now()
 
@@ -440,10 +445,15 @@ func (s *FlowService) UpdateFlow(flow *models.ChatbotFlow) error {
 		return nil // Return success in fallback mode
 	}
 
+	// Normalize node data to canonical keys and reject misconfigured nodes
+	if err := s.NormalizeAndValidateFlow(flow); err != nil {
+		return err
+	}
+
 	flow.UpdatedAt = time.Now()
 
 	query := `
-		UPDATE chatbot_flows 
+		UPDATE chatbot_flows
 		SET name = ?, niche = ?, id_device = ?,
 		    nodes = ?, edges = ?, updated_at = ?
 		WHERE id = ?
@@ -478,27 +488,11 @@ func (s *FlowService) DeleteFlow(flowID string) error {
 	return nil
 }
 
-// GetNextNode finds the next node in the flow based on the current node
+// GetNextNode finds the next node in the flow based on the current node.
+// Edge conditions referencing the user input cannot match here; callers with
+// input context should use GetNextNodeWithContext
 func (s *FlowService) GetNextNode(flow *models.ChatbotFlow, currentNodeID string) (*models.FlowNode, error) {
-	edges, err := s.GetFlowEdges(flow)
-	if err != nil {
-		return nil, err
-	}
-
-	// Find edge from current node
-	var nextNodeID string
-	for _, edge := range edges {
-		if edge.Source == currentNodeID {
-			nextNodeID = edge.Target
-			break
-		}
-	}
-
-	if nextNodeID == "" {
-		return nil, fmt.Errorf("no next node found for node %s", currentNodeID)
-	}
-
-	return s.FindNodeByID(flow, nextNodeID)
+	return s.GetNextNodeWithContext(flow, currentNodeID, "", nil)
 }
 
 // EvaluateConditionNode evaluates a condition node and returns the appropriate next node based on user input
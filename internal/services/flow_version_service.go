@@ -0,0 +1,152 @@
+package services
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FlowVersionService manages the draft/publish lifecycle of chatbot flows.
+// The live chatbot_flows row is the editable draft; publishing snapshots it
+// as the next immutable version. Conversations pin the version that was
+// current when they started, so editing or rolling back a flow never breaks
+// executions already in flight
+
+// FlowVersionService publishes, lists and restores flow versions
+type FlowVersionService struct {
+	versionRepo repository.FlowVersionRepository
+	flowService *FlowService
+}
+
+// NewFlowVersionService creates a new flow version service
+func NewFlowVersionService(versionRepo repository.FlowVersionRepository, flowService *FlowService) *FlowVersionService {
+	return &FlowVersionService{
+		versionRepo: versionRepo,
+		flowService: flowService,
+	}
+}
+
+// Publish snapshots the flow's current draft as its next version
+func (s *FlowVersionService) Publish(flowID string) (*models.FlowVersion, error) {
+	flow, err := s.flowService.GetFlow(flowID)
+	if err != nil {
+		return nil, err
+	}
+	if flow == nil {
+		return nil, fmt.Errorf("flow not found: %s", flowID)
+	}
+
+	version := &models.FlowVersion{
+		FlowID: flowID,
+		Nodes:  flow.Nodes,
+		Edges:  flow.Edges,
+	}
+	if err := s.versionRepo.CreateVersion(version); err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id": flowID,
+		"version": version.Version,
+	}).Info("📦 VERSION: Flow published")
+
+	return version, nil
+}
+
+// ListVersions returns the flow's version history, newest first
+func (s *FlowVersionService) ListVersions(flowID string) ([]models.FlowVersion, error) {
+	return s.versionRepo.ListVersions(flowID)
+}
+
+// GetVersion returns one snapshot with its payload
+func (s *FlowVersionService) GetVersion(flowID string, version int) (*models.FlowVersion, error) {
+	return s.versionRepo.GetVersion(flowID, version)
+}
+
+// Rollback restores a published version into the live draft and republishes
+// it as the newest version, keeping history linear
+func (s *FlowVersionService) Rollback(flowID string, versionNumber int) (*models.FlowVersion, error) {
+	version, err := s.versionRepo.GetVersion(flowID, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		return nil, fmt.Errorf("version %d not found for flow %s", versionNumber, flowID)
+	}
+
+	flow, err := s.flowService.GetFlow(flowID)
+	if err != nil {
+		return nil, err
+	}
+	if flow == nil {
+		return nil, fmt.Errorf("flow not found: %s", flowID)
+	}
+
+	flow.Nodes = version.Nodes
+	flow.Edges = version.Edges
+	if err := s.flowService.UpdateFlow(flow); err != nil {
+		return nil, err
+	}
+
+	republished, err := s.Publish(flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id":      flowID,
+		"from_version": versionNumber,
+		"new_version":  republished.Version,
+	}).Info("⏪ VERSION: Flow rolled back")
+
+	return republished, nil
+}
+
+// PinExecution pins a conversation to the flow's newest published version;
+// unpublished flows stay unpinned and run the live draft
+func (s *FlowVersionService) PinExecution(prospectNum, idDevice, flowID string) {
+	latest, err := s.versionRepo.LatestVersion(flowID)
+	if err != nil || latest == 0 {
+		return
+	}
+	if err := s.versionRepo.PinExecution(prospectNum, idDevice, flowID, latest); err == nil {
+		logrus.WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"flow_id":      flowID,
+			"version":      latest,
+		}).Info("📌 VERSION: Execution pinned to published flow version")
+	}
+}
+
+// ResolveFlow swaps the flow's nodes and edges for the conversation's pinned
+// version when one exists; otherwise the flow is returned unchanged
+func (s *FlowVersionService) ResolveFlow(flow *models.ChatbotFlow, prospectNum, idDevice string) *models.ChatbotFlow {
+	if flow == nil {
+		return nil
+	}
+
+	pinned, err := s.versionRepo.GetPin(prospectNum, idDevice, flow.ID)
+	if err != nil || pinned == 0 {
+		return flow
+	}
+
+	version, err := s.versionRepo.GetVersion(flow.ID, pinned)
+	if err != nil || version == nil {
+		return flow
+	}
+
+	resolved := *flow
+	resolved.Nodes = version.Nodes
+	resolved.Edges = version.Edges
+	return &resolved
+}
+
+// ClearPin removes a conversation's pin once its execution completes
+func (s *FlowVersionService) ClearPin(prospectNum, idDevice string) {
+	if err := s.versionRepo.ClearPin(prospectNum, idDevice); err != nil {
+		logrus.WithError(err).Warn("📌 VERSION: Failed to clear execution flow pin")
+	}
+}
@@ -148,6 +148,15 @@ func (h *HealthService) GetSystemHealth(ctx context.Context) *SystemHealth {
 	// Determine overall system health
 	health.Status = h.calculateOverallHealth(health.Components)
 
+	// Push component health gauges so /metrics reflects the latest check
+	for name, component := range health.Components {
+		value := 0.0
+		if component.Status == HealthStatusHealthy {
+			value = 1.0
+		}
+		Metrics().SetGauge(MetricComponentHealthy, map[string]string{"component": name}, value)
+	}
+
 	// Cache the result
 	h.cachedHealth = health
 	h.lastCheck = time.Now()
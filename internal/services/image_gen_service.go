@@ -0,0 +1,221 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/disintegration/imaging"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// ImageGenService renders personalized images for image_gen nodes: either
+// text overlays (name, order details) drawn onto a base template, or a fresh
+// image from the AI image provider. Results are cached through MediaService
+// so the same personalization is only rendered once
+
+// openAIImagesURL is the AI image provider endpoint, keyed per device like
+// the text models in AIService
+const openAIImagesURL = "https://api.openai.com/v1/images/generations"
+
+// imageGenDefaultSize is the overlay text height used when a node leaves
+// the overlay size unset
+const imageGenDefaultSize = 24
+
+// ImageGenService generates and caches personalized images
+type ImageGenService struct {
+	mediaService *MediaService
+	httpClient   *http.Client
+}
+
+// NewImageGenService creates a new image generation service
+func NewImageGenService(mediaService *MediaService) *ImageGenService {
+	return &ImageGenService{
+		mediaService: mediaService,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second, // AI image providers are slow
+		},
+	}
+}
+
+// GenerateFromTemplate draws the overlays onto the base image, stores the
+// result under cacheKey and returns its URL. A previously stored render for
+// the same cache key is reused without re-rendering
+func (s *ImageGenService) GenerateFromTemplate(cacheKey, baseImageURL string, overlays []models.ImageGenOverlay) (string, error) {
+	if url, ok := s.mediaService.GeneratedImageURL(cacheKey); ok {
+		logrus.WithField("cache_key", cacheKey).Info("🖼️ IMAGE GEN: Reusing cached render")
+		return url, nil
+	}
+
+	baseData, err := s.fetchImage(baseImageURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch base image: %w", err)
+	}
+
+	baseImage, _, err := image.Decode(bytes.NewReader(baseData))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base image: %w", err)
+	}
+
+	canvas := imaging.Clone(baseImage)
+	for _, overlay := range overlays {
+		if overlay.Text == "" {
+			continue
+		}
+		s.drawOverlay(canvas, overlay)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return "", fmt.Errorf("failed to encode rendered image: %w", err)
+	}
+
+	return s.mediaService.SaveGeneratedImage(cacheKey, buf.Bytes())
+}
+
+// GenerateFromPrompt asks the AI image provider for an image, stores the
+// result under cacheKey and returns its URL
+func (s *ImageGenService) GenerateFromPrompt(cacheKey, prompt, apiKey string) (string, error) {
+	if url, ok := s.mediaService.GeneratedImageURL(cacheKey); ok {
+		logrus.WithField("cache_key", cacheKey).Info("🖼️ IMAGE GEN: Reusing cached render")
+		return url, nil
+	}
+
+	if apiKey == "" {
+		return "", fmt.Errorf("no API key available for AI image generation")
+	}
+
+	payload := map[string]interface{}{
+		"model":           "gpt-image-1",
+		"prompt":          prompt,
+		"size":            "1024x1024",
+		"n":               1,
+		"response_format": "b64_json",
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal image request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", openAIImagesURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create image request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("image provider request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image provider response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("image provider returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Data []struct {
+			B64JSON string `json:"b64_json"`
+			URL     string `json:"url"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to parse image provider response: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return "", fmt.Errorf("image provider returned no images")
+	}
+
+	var imageData []byte
+	if result.Data[0].B64JSON != "" {
+		imageData, err = base64.StdEncoding.DecodeString(result.Data[0].B64JSON)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode generated image: %w", err)
+		}
+	} else if result.Data[0].URL != "" {
+		imageData, err = s.fetchImage(result.Data[0].URL)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch generated image: %w", err)
+		}
+	} else {
+		return "", fmt.Errorf("image provider returned an empty image")
+	}
+
+	return s.mediaService.SaveGeneratedImage(cacheKey, imageData)
+}
+
+// fetchImage downloads an image over HTTP
+func (s *ImageGenService) fetchImage(url string) ([]byte, error) {
+	resp, err := s.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// drawOverlay renders one text overlay onto the canvas. Text is drawn with
+// the built-in bitmap face onto its own layer, scaled to the requested size
+// and pasted at the overlay position
+func (s *ImageGenService) drawOverlay(canvas *image.NRGBA, overlay models.ImageGenOverlay) {
+	face := basicfont.Face7x13
+	textWidth := font.MeasureString(face, overlay.Text).Ceil()
+	if textWidth == 0 {
+		return
+	}
+	textHeight := face.Metrics().Height.Ceil()
+
+	layer := image.NewNRGBA(image.Rect(0, 0, textWidth, textHeight))
+	drawer := &font.Drawer{
+		Dst:  layer,
+		Src:  image.NewUniform(parseOverlayColor(overlay.Color)),
+		Face: face,
+		Dot:  fixed.P(0, face.Metrics().Ascent.Ceil()),
+	}
+	drawer.DrawString(overlay.Text)
+
+	size := overlay.Size
+	if size <= 0 {
+		size = imageGenDefaultSize
+	}
+	scaledWidth := textWidth * size / textHeight
+	scaled := imaging.Resize(layer, scaledWidth, size, imaging.Lanczos)
+
+	draw := imaging.Overlay(canvas, scaled, image.Pt(overlay.X, overlay.Y), 1.0)
+	copy(canvas.Pix, draw.Pix)
+}
+
+// parseOverlayColor converts a #rrggbb hex value to a color, defaulting to
+// near-black when the value is missing or malformed
+func parseOverlayColor(hexColor string) color.Color {
+	fallback := color.NRGBA{R: 26, G: 26, B: 26, A: 255}
+	if len(hexColor) != 7 || hexColor[0] != '#' {
+		return fallback
+	}
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(hexColor[1:], "%02x%02x%02x", &r, &g, &b); err != nil {
+		return fallback
+	}
+	return color.NRGBA{R: r, G: g, B: b, A: 255}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// inboundMediaTimeout bounds how long a provider media download may take
+	inboundMediaTimeout = 30 * time.Second
+	// maxInboundMediaSize caps downloaded media at 25MB, matching provider limits
+	maxInboundMediaSize = 25 * 1024 * 1024
+)
+
+// InboundMediaService downloads media referenced by provider webhooks
+// (Wablas, Whacenter, WAHA) and stores it via MediaService so incoming
+// images and voice notes survive the provider's short-lived URLs
+type InboundMediaService struct {
+	mediaService *MediaService
+	client       *http.Client
+}
+
+// NewInboundMediaService creates a new inbound media service instance
+func NewInboundMediaService(mediaService *MediaService) *InboundMediaService {
+	return &InboundMediaService{
+		mediaService: mediaService,
+		client:       &http.Client{Timeout: inboundMediaTimeout},
+	}
+}
+
+// Ingest downloads the media at the provider URL, stores it locally and
+// returns the URL it is served from; redelivered webhooks reuse the stored file
+func (ims *InboundMediaService) Ingest(mediaURL string) (string, error) {
+	parsed, err := url.Parse(mediaURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", fmt.Errorf("invalid media URL: %s", mediaURL)
+	}
+
+	resp, err := ims.client.Get(mediaURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download media: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("media download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxInboundMediaSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read media body: %v", err)
+	}
+	if len(data) > maxInboundMediaSize {
+		return "", fmt.Errorf("media exceeds maximum size of %d bytes", maxInboundMediaSize)
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("media download returned empty body")
+	}
+
+	mimeType := strings.TrimSpace(strings.SplitN(resp.Header.Get("Content-Type"), ";", 2)[0])
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = http.DetectContentType(data)
+	}
+	if !isAllowedInboundMediaType(mimeType) {
+		return "", fmt.Errorf("unsupported inbound media type: %s", mimeType)
+	}
+
+	// Reuse an already stored file when the provider redelivers the webhook
+	if storedURL, ok := ims.mediaService.InboundMediaURL(mediaURL, mimeType); ok {
+		logrus.WithFields(logrus.Fields{
+			"source_url": mediaURL,
+			"url":        storedURL,
+		}).Info("📥 INBOUND MEDIA: Reusing previously stored media")
+		return storedURL, nil
+	}
+
+	return ims.mediaService.SaveInboundMedia(mediaURL, data, mimeType)
+}
+
+// isAllowedInboundMediaType restricts storage to the media kinds the flow
+// engine and AI prompts can actually use
+func isAllowedInboundMediaType(mimeType string) bool {
+	if strings.HasPrefix(mimeType, "image/") ||
+		strings.HasPrefix(mimeType, "audio/") ||
+		strings.HasPrefix(mimeType, "video/") {
+		return true
+	}
+	return mimeType == "application/pdf"
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// inboxIdleTimeout is how long a human-mode conversation may sit without
+// agent activity before it is handed back to the bot
+const inboxIdleTimeout = 30 * time.Minute
+
+// inboxSweepInterval is how often idle conversations are checked
+const inboxSweepInterval = time.Minute
+
+// InboxMessageSender sends an agent reply through the device's provider
+type InboxMessageSender interface {
+	SendMessageFromDevice(deviceID, phoneNumber, message string) error
+}
+
+// InboxService backs the agent inbox: it lists conversations in human
+// takeover, routes agent replies through the device's provider, streams
+// inbox events over the WebSocket service, and automatically returns idle
+// conversations to the bot
+type InboxService struct {
+	aiRepo            repository.AIWhatsappRepository
+	aiWhatsappService AIWhatsappService
+	websocketService  *WebSocketService
+	assignmentService *AssignmentService
+	messageSender     InboxMessageSender
+
+	mu           sync.Mutex
+	lastActivity map[string]time.Time // "idDevice|prospectNum" -> last agent activity
+	stopSweeper  chan struct{}
+}
+
+// NewInboxService creates a new inbox service
+func NewInboxService(
+	aiRepo repository.AIWhatsappRepository,
+	aiWhatsappService AIWhatsappService,
+	websocketService *WebSocketService,
+) *InboxService {
+	return &InboxService{
+		aiRepo:            aiRepo,
+		aiWhatsappService: aiWhatsappService,
+		websocketService:  websocketService,
+		lastActivity:      make(map[string]time.Time),
+		stopSweeper:       make(chan struct{}),
+	}
+}
+
+// SetAssignmentService wires the agent assignment engine into the inbox
+func (s *InboxService) SetAssignmentService(assignmentService *AssignmentService) {
+	s.assignmentService = assignmentService
+}
+
+// SetMessageSender sets the sender used to deliver agent replies
+func (s *InboxService) SetMessageSender(sender InboxMessageSender) {
+	s.messageSender = sender
+}
+
+// inboxKey builds the activity map key for a conversation
+func inboxKey(idDevice, prospectNum string) string {
+	return idDevice + "|" + prospectNum
+}
+
+// ListHumanConversations returns the user's conversations in human takeover
+// and seeds the idle timer for any conversation seen for the first time, so
+// handoffs that never get picked up still return to the bot eventually
+func (s *InboxService) ListHumanConversations(userID string) ([]models.AIWhatsapp, error) {
+	conversations, err := s.aiRepo.ListHumanConversations(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	for _, conv := range conversations {
+		key := inboxKey(conv.IDDevice, conv.ProspectNum)
+		if _, seen := s.lastActivity[key]; !seen {
+			s.lastActivity[key] = time.Now()
+		}
+	}
+	s.mu.Unlock()
+
+	return conversations, nil
+}
+
+// AssignConversation hands a human-mode conversation to a specific agent and
+// streams the assignment to connected inbox clients
+func (s *InboxService) AssignConversation(prospectNum, idDevice, agentID string) (*models.ConversationAssignment, error) {
+	if s.assignmentService == nil {
+		return nil, fmt.Errorf("assignment service not available")
+	}
+
+	assignment, err := s.assignmentService.ReassignConversation(prospectNum, idDevice, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.touchActivity(idDevice, prospectNum)
+	s.broadcast(idDevice, "inbox_assigned", map[string]interface{}{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"agent_id":     agentID,
+	})
+
+	return assignment, nil
+}
+
+// SendAgentReply sends an agent's reply through the conversation's provider,
+// records it in the conversation history and resets the idle timer
+func (s *InboxService) SendAgentReply(idDevice, prospectNum, agentID, message string) error {
+	conv, err := s.aiRepo.GetAIWhatsappByProspectAndDevice(prospectNum, idDevice)
+	if err != nil {
+		return fmt.Errorf("failed to load conversation: %w", err)
+	}
+	if conv == nil {
+		return fmt.Errorf("conversation not found")
+	}
+	if conv.Human == 0 {
+		return fmt.Errorf("conversation is not in human mode")
+	}
+
+	if s.messageSender == nil {
+		return fmt.Errorf("message sender not available")
+	}
+	if err := s.messageSender.SendMessageFromDevice(idDevice, prospectNum, message); err != nil {
+		return err
+	}
+
+	// Record the reply so the transcript stays complete when the bot resumes
+	if err := s.aiRepo.SaveConversationHistory(prospectNum, idDevice, "", message, conv.Stage.String, conv.ProspectName.String); err != nil {
+		logrus.WithError(err).Warn("📥 INBOX: Failed to record agent reply in conversation history")
+	}
+
+	s.touchActivity(idDevice, prospectNum)
+	s.broadcast(idDevice, "inbox_reply", map[string]interface{}{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"agent_id":     agentID,
+		"message":      message,
+	})
+
+	logrus.WithFields(logrus.Fields{
+		"id_device":    idDevice,
+		"prospect_num": prospectNum,
+		"agent_id":     agentID,
+	}).Info("📥 INBOX: Agent reply sent")
+
+	return nil
+}
+
+// ReturnToBot ends human takeover for a conversation and notifies inbox
+// clients
+func (s *InboxService) ReturnToBot(idDevice, prospectNum, reason string) error {
+	if err := s.aiWhatsappService.SetHumanMode(prospectNum, idDevice, false); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.lastActivity, inboxKey(idDevice, prospectNum))
+	s.mu.Unlock()
+
+	s.broadcast(idDevice, "inbox_returned_to_bot", map[string]interface{}{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"reason":       reason,
+	})
+
+	logrus.WithFields(logrus.Fields{
+		"id_device":    idDevice,
+		"prospect_num": prospectNum,
+		"reason":       reason,
+	}).Info("🤖 INBOX: Conversation returned to bot")
+
+	return nil
+}
+
+// StartIdleSweeper runs the background loop that returns idle human-mode
+// conversations to the bot. Call once at startup
+func (s *InboxService) StartIdleSweeper() {
+	ticker := time.NewTicker(inboxSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sweepIdleConversations()
+		case <-s.stopSweeper:
+			return
+		}
+	}
+}
+
+// Stop terminates the idle sweeper loop
+func (s *InboxService) Stop() {
+	close(s.stopSweeper)
+}
+
+// sweepIdleConversations returns conversations without recent agent activity
+// to the bot
+func (s *InboxService) sweepIdleConversations() {
+	cutoff := time.Now().Add(-inboxIdleTimeout)
+
+	s.mu.Lock()
+	var idle []string
+	for key, last := range s.lastActivity {
+		if last.Before(cutoff) {
+			idle = append(idle, key)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, key := range idle {
+		idDevice, prospectNum := splitInboxKey(key)
+		if err := s.ReturnToBot(idDevice, prospectNum, "agent_inactivity"); err != nil {
+			logrus.WithError(err).WithField("key", key).Warn("🤖 INBOX: Failed to return idle conversation to bot")
+		}
+	}
+}
+
+// touchActivity resets the conversation's idle timer
+func (s *InboxService) touchActivity(idDevice, prospectNum string) {
+	s.mu.Lock()
+	s.lastActivity[inboxKey(idDevice, prospectNum)] = time.Now()
+	s.mu.Unlock()
+}
+
+// splitInboxKey is the inverse of inboxKey
+func splitInboxKey(key string) (idDevice, prospectNum string) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == '|' {
+			return key[:i], key[i+1:]
+		}
+	}
+	return key, ""
+}
+
+// broadcast streams an inbox event to the device's connected clients
+func (s *InboxService) broadcast(idDevice, eventType string, data map[string]interface{}) {
+	if s.websocketService == nil {
+		return
+	}
+	s.websocketService.SendToDevice(idDevice, eventType, data)
+}
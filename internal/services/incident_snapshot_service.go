@@ -0,0 +1,136 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// How much history goes into a snapshot bundle
+const (
+	snapshotConversationLogLimit = 200
+	snapshotFailedMessageLimit   = 50
+	snapshotWebhookPayloadLimit  = 20
+)
+
+// IncidentSnapshotService captures a downloadable diagnostic bundle for one
+// prospect/device pair: the conversation and execution state, recent webhook
+// payloads, queue stats, failed messages and the redacted log tail — so bug
+// reports can carry real state instead of screenshots
+type IncidentSnapshotService struct {
+	aiRepo             repository.AIWhatsappRepository
+	executionRepo      repository.ExecutionProcessRepository
+	failedMessageRepo  repository.FailedMessageRepository
+	unknownPayloadRepo repository.UnknownPayloadRepository
+	queueService       *QueueService
+}
+
+// NewIncidentSnapshotService creates a new incident snapshot service
+func NewIncidentSnapshotService(
+	aiRepo repository.AIWhatsappRepository,
+	executionRepo repository.ExecutionProcessRepository,
+	failedMessageRepo repository.FailedMessageRepository,
+	unknownPayloadRepo repository.UnknownPayloadRepository,
+	queueService *QueueService,
+) *IncidentSnapshotService {
+	return &IncidentSnapshotService{
+		aiRepo:             aiRepo,
+		executionRepo:      executionRepo,
+		failedMessageRepo:  failedMessageRepo,
+		unknownPayloadRepo: unknownPayloadRepo,
+		queueService:       queueService,
+	}
+}
+
+// BuildSnapshot assembles the zipped diagnostic bundle. Sections that cannot
+// be collected are recorded inside the bundle instead of failing the capture
+func (s *IncidentSnapshotService) BuildSnapshot(prospectNum, idDevice string) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	errors := map[string]string{}
+	addSection := func(name string, value interface{}, err error) {
+		if err != nil {
+			errors[name] = err.Error()
+			return
+		}
+		payload, marshalErr := json.MarshalIndent(value, "", "  ")
+		if marshalErr != nil {
+			errors[name] = marshalErr.Error()
+			return
+		}
+		writer, createErr := archive.Create(name)
+		if createErr != nil {
+			errors[name] = createErr.Error()
+			return
+		}
+		if _, writeErr := writer.Write(payload); writeErr != nil {
+			errors[name] = writeErr.Error()
+		}
+	}
+
+	conversation, err := s.aiRepo.GetAIWhatsappByProspectAndDevice(prospectNum, idDevice)
+	addSection("conversation.json", conversation, err)
+
+	execution, err := s.executionRepo.GetOldestExecution(idDevice, prospectNum)
+	addSection("execution.json", execution, err)
+
+	conversationLog, err := s.aiRepo.GetConversationHistory(prospectNum, snapshotConversationLogLimit)
+	addSection("conversation_log.json", conversationLog, err)
+
+	failedMessages, err := s.failedMessageRepo.ListByDevice(idDevice, snapshotFailedMessageLimit)
+	addSection("failed_messages.json", failedMessages, err)
+
+	// Unknown payloads are already PII-masked at capture time; keep only the
+	// device's rows
+	payloads, err := s.unknownPayloadRepo.GetUnknownPayloads("", snapshotWebhookPayloadLimit*5)
+	if err == nil {
+		deviceRows := payloads[:0]
+		for _, row := range payloads {
+			if row.IDDevice == idDevice {
+				deviceRows = append(deviceRows, row)
+				if len(deviceRows) >= snapshotWebhookPayloadLimit {
+					break
+				}
+			}
+		}
+		payloads = deviceRows
+	}
+	addSection("webhook_payloads.json", payloads, err)
+
+	if s.queueService != nil {
+		queueStats, err := s.queueService.GetQueueStats()
+		addSection("queue.json", queueStats, err)
+	}
+
+	// Redacted log tail from the in-memory ring buffer
+	logID, _, backlog := LogStream().Subscribe(idDevice, "")
+	LogStream().Unsubscribe(logID)
+	addSection("logs.json", backlog, nil)
+
+	addSection("snapshot.json", map[string]interface{}{
+		"prospect_num":    prospectNum,
+		"id_device":       idDevice,
+		"captured_at":     time.Now().Format(time.RFC3339),
+		"section_errors":  errors,
+		"format_revision": 1,
+	}, nil)
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize snapshot archive: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device":    idDevice,
+		"prospect_num": prospectNum,
+		"bundle_bytes": buf.Len(),
+	}).Info("📦 SNAPSHOT: Incident bundle captured")
+
+	return buf.Bytes(), nil
+}
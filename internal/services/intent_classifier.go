@@ -0,0 +1,110 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IntentClassification is the AI's verdict on which labeled intent a user
+// message expresses
+type IntentClassification struct {
+	Intent     string  `json:"intent"`
+	Confidence float64 `json:"confidence"`
+}
+
+// intentClassifierModel is the cheap model used for intent routing - picking
+// one label out of a short list does not need conversational quality
+const intentClassifierModel = "openai/gpt-4o-mini"
+
+// ClassifyIntent asks the AI provider which of the labeled intents the user
+// message expresses and how confident it is. The returned intent is snapped
+// onto the configured list; an unknown label comes back with zero confidence
+// so the caller routes to its fallback
+func (s *AIService) ClassifyIntent(userInput string, intents []string, apiKey, deviceID string) (*IntentClassification, error) {
+	if len(intents) == 0 {
+		return nil, fmt.Errorf("no intents provided")
+	}
+
+	apiKey = s.getAPIKey(apiKey, deviceID)
+	if apiKey == "" {
+		return nil, fmt.Errorf("no API key provided")
+	}
+
+	systemPrompt := fmt.Sprintf(
+		"You are an intent classifier. Classify the user message into exactly one of these intents: %s.\n"+
+			"Respond with ONLY a JSON object and no other text:\n"+
+			"{\"intent\": \"<one of the intents>\", \"confidence\": <number between 0.0 and 1.0>}",
+		strings.Join(intents, ", "))
+
+	request := models.OpenRouterRequest{
+		Model:             intentClassifierModel,
+		Messages:          s.buildMessages(systemPrompt, userInput, nil),
+		Stream:            false,
+		Temperature:       0, // Deterministic labels, not creative text
+		TopP:              1.0,
+		RepetitionPenalty: 1.0,
+	}
+
+	response, err := s.makeOpenRouterRequest(request, apiKey, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("intent classification request failed: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("intent classification returned no choices")
+	}
+
+	content := strings.TrimSpace(response.Choices[0].Message.Content)
+	// Models occasionally wrap the JSON in code fences - cut them away
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var classification IntentClassification
+	if err := json.Unmarshal([]byte(content), &classification); err != nil {
+		return nil, fmt.Errorf("failed to parse intent classification %q: %w", content, err)
+	}
+
+	// Snap the label back onto the configured list so downstream edge
+	// matching is exact
+	matched := ""
+	for _, intent := range intents {
+		if strings.EqualFold(strings.TrimSpace(classification.Intent), intent) {
+			matched = intent
+			break
+		}
+	}
+	if matched == "" {
+		logrus.WithFields(logrus.Fields{
+			"returned_intent": classification.Intent,
+			"intents":         intents,
+		}).Warn("🧭 INTENT: Classifier returned an unknown label, treating as no confidence")
+		classification.Confidence = 0
+	} else {
+		classification.Intent = matched
+	}
+
+	if classification.Confidence < 0 {
+		classification.Confidence = 0
+	}
+	if classification.Confidence > 1 {
+		classification.Confidence = 1
+	}
+
+	if matched != "" {
+		s.recordIntent(deviceID, matched)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"intent":     classification.Intent,
+		"confidence": classification.Confidence,
+		"device_id":  deviceID,
+	}).Info("🧭 INTENT: Classified user message")
+
+	return &classification, nil
+}
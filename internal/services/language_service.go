@@ -0,0 +1,296 @@
+package services
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+	"nodepath-chat/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LanguageService resolves the language a conversation should be answered in
+// and reports translation coverage per flow. The first clearly detected
+// language is stored per prospect+device so later messages stay consistent
+// even when a single reply is ambiguous
+
+// translationLanguageNames maps the detectable language codes to the names
+// used in the translation prompt
+var translationLanguageNames = map[string]string{
+	"ms": "Malay",
+	"en": "English",
+	"zh": "Chinese (Simplified)",
+}
+
+// machineTranslatePrompt is the system prompt for the translation model call.
+// The language name is interpolated per request
+const machineTranslatePrompt = `You are a professional translator for a WhatsApp sales chatbot.
+Translate the user's message into %s.
+Keep {{variable}} placeholders, {spintax|groups} and URLs exactly as they are.
+Match the casual, friendly register of the original.
+Respond with ONLY the translated text, nothing else.`
+
+type LanguageService struct {
+	languageRepo repository.LanguageRepository
+	aiService    *AIService
+}
+
+// NewLanguageService creates a new language service
+func NewLanguageService(languageRepo repository.LanguageRepository) *LanguageService {
+	return &LanguageService{languageRepo: languageRepo}
+}
+
+// SetAIService enables the machine translation fallback; without it only
+// human translations are served
+func (s *LanguageService) SetAIService(aiService *AIService) {
+	s.aiService = aiService
+}
+
+// ResolveLanguage returns the conversation's language: the stored value when
+// one exists, otherwise the language detected from the user's message (which
+// is then stored for the rest of the conversation). Empty means unknown and
+// callers should fall back to the node's default content
+func (s *LanguageService) ResolveLanguage(prospectNum, idDevice, userInput string) string {
+	stored, err := s.languageRepo.GetLanguage(prospectNum, idDevice)
+	if err == nil && stored != "" {
+		return stored
+	}
+
+	detected := utils.DetectLanguage(userInput)
+	if detected == "" {
+		return ""
+	}
+
+	if err := s.languageRepo.UpsertLanguage(prospectNum, idDevice, detected, "detected"); err == nil {
+		logrus.WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"language":     detected,
+		}).Info("🌐 LANGUAGE: Detected and stored conversation language")
+	}
+
+	return detected
+}
+
+// Translate returns the translation for the language when the node carries
+// one, otherwise the default content unchanged
+func (s *LanguageService) Translate(defaultContent string, translations map[string]string, language string) string {
+	if language == "" || len(translations) == 0 {
+		return defaultContent
+	}
+	if translated, ok := translations[language]; ok && translated != "" {
+		return translated
+	}
+	return defaultContent
+}
+
+// AutoTranslateEnabled returns the flow's machine translation fallback toggle
+func (s *LanguageService) AutoTranslateEnabled(flowID string) (bool, error) {
+	return s.languageRepo.GetAutoTranslate(flowID)
+}
+
+// SetAutoTranslate stores the flow's machine translation fallback toggle
+func (s *LanguageService) SetAutoTranslate(flowID string, enabled bool) error {
+	return s.languageRepo.SetAutoTranslate(flowID, enabled)
+}
+
+// MachineTranslations returns the flow's cached machine translations for
+// review; promoting one into the node's translations map overrides it
+func (s *LanguageService) MachineTranslations(flowID string) ([]models.MachineTranslation, error) {
+	return s.languageRepo.GetMachineTranslationsByFlow(flowID)
+}
+
+// MachineTranslationLog returns the conversation's machine-translated
+// deliveries so the conversation view can flag those messages
+func (s *LanguageService) MachineTranslationLog(prospectNum, idDevice string) ([]models.MachineTranslationUse, error) {
+	return s.languageRepo.GetMachineTranslationLog(prospectNum, idDevice)
+}
+
+// LocalizedMessage returns the message node content for the language: the
+// node's own translation when present, otherwise - when the flow has
+// auto-translate enabled - a cached or freshly machine-translated version of
+// the default content. Every machine-translated delivery is flagged in the
+// conversation's translation log. Failures fall back to the default content
+func (s *LanguageService) LocalizedMessage(flowID, nodeID, prospectNum, idDevice, language, defaultContent string, translations map[string]string) string {
+	if language == "" || defaultContent == "" {
+		return defaultContent
+	}
+
+	// A human translation on the node always wins
+	if translated, ok := translations[language]; ok && translated != "" {
+		return translated
+	}
+
+	if s.aiService == nil {
+		return defaultContent
+	}
+	if enabled, err := s.languageRepo.GetAutoTranslate(flowID); err != nil || !enabled {
+		return defaultContent
+	}
+
+	sourceHash := translationSourceHash(defaultContent)
+
+	translated, err := s.languageRepo.GetCachedTranslation(nodeID, language, sourceHash)
+	if err == nil && translated != "" {
+		s.flagMachineTranslation(prospectNum, idDevice, flowID, nodeID, language)
+		return translated
+	}
+
+	translated, err = s.machineTranslate(defaultContent, language, idDevice)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"flow_id":  flowID,
+			"node_id":  nodeID,
+			"language": language,
+		}).Warn("🌐 TRANSLATE: Machine translation failed, using default content")
+		return defaultContent
+	}
+
+	if err := s.languageRepo.UpsertCachedTranslation(&models.MachineTranslation{
+		FlowID:         flowID,
+		NodeID:         nodeID,
+		Language:       language,
+		SourceHash:     sourceHash,
+		SourceText:     defaultContent,
+		TranslatedText: translated,
+	}); err == nil {
+		logrus.WithFields(logrus.Fields{
+			"flow_id":  flowID,
+			"node_id":  nodeID,
+			"language": language,
+		}).Info("🌐 TRANSLATE: Machine translation cached")
+	}
+
+	s.flagMachineTranslation(prospectNum, idDevice, flowID, nodeID, language)
+	return translated
+}
+
+// machineTranslate runs one translation model call using the device's API key
+func (s *LanguageService) machineTranslate(content, language, idDevice string) (string, error) {
+	languageName, ok := translationLanguageNames[language]
+	if !ok {
+		return "", fmt.Errorf("no translation support for language %q", language)
+	}
+
+	apiKey, err := s.languageRepo.GetDeviceAPIKey(idDevice)
+	if err != nil {
+		return "", fmt.Errorf("failed to get device API key: %w", err)
+	}
+	if apiKey == "" {
+		return "", fmt.Errorf("device %s has no API key configured", idDevice)
+	}
+
+	systemPrompt := fmt.Sprintf(machineTranslatePrompt, languageName)
+	translated, err := s.aiService.GenerateResponse(systemPrompt, content, apiKey, idDevice, nil)
+	if err != nil {
+		return "", fmt.Errorf("translation call failed: %w", err)
+	}
+
+	translated = strings.TrimSpace(translated)
+	if translated == "" {
+		return "", fmt.Errorf("translation call returned empty content")
+	}
+	return translated, nil
+}
+
+// flagMachineTranslation records a machine-translated delivery in the
+// conversation's translation log
+func (s *LanguageService) flagMachineTranslation(prospectNum, idDevice, flowID, nodeID, language string) {
+	if err := s.languageRepo.LogMachineTranslationUse(prospectNum, idDevice, flowID, nodeID, language); err == nil {
+		logrus.WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"node_id":      nodeID,
+			"language":     language,
+		}).Info("🌐 TRANSLATE: Machine-translated message flagged in conversation log")
+	}
+}
+
+// translationSourceHash fingerprints the default content so edits invalidate
+// cached translations
+func translationSourceHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return fmt.Sprintf("%x", sum[:8])
+}
+
+// TranslationReport walks the flow's translatable nodes (message and image)
+// and reports which of the flow's languages each node is missing
+func (s *LanguageService) TranslationReport(flowID string, nodes []*models.FlowNode) *models.FlowTranslationReport {
+	report := &models.FlowTranslationReport{
+		FlowID:   flowID,
+		Nodes:    make([]models.NodeTranslationStatus, 0),
+		Complete: true,
+	}
+
+	// First pass: the flow's language set is the union across all nodes
+	languageSet := make(map[string]bool)
+	nodeLanguages := make(map[string][]string)
+	for _, node := range nodes {
+		translations := translatableNodeTranslations(node)
+		if translations == nil {
+			continue
+		}
+		languages := make([]string, 0, len(translations))
+		for language := range translations {
+			languageSet[language] = true
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+		nodeLanguages[node.ID] = languages
+	}
+
+	report.Languages = make([]string, 0, len(languageSet))
+	for language := range languageSet {
+		report.Languages = append(report.Languages, language)
+	}
+	sort.Strings(report.Languages)
+
+	// Second pass: every translatable node is checked against the union, so
+	// a node with no translations at all is reported as missing everything
+	for _, node := range nodes {
+		if node.Type != models.NodeTypeMessage && node.Type != models.NodeTypeImage {
+			continue
+		}
+
+		languages := nodeLanguages[node.ID]
+		covered := make(map[string]bool, len(languages))
+		for _, language := range languages {
+			covered[language] = true
+		}
+
+		missing := make([]string, 0)
+		for _, language := range report.Languages {
+			if !covered[language] {
+				missing = append(missing, language)
+			}
+		}
+		if len(missing) > 0 {
+			report.Complete = false
+		}
+
+		report.Nodes = append(report.Nodes, models.NodeTranslationStatus{
+			NodeID:    node.ID,
+			Type:      node.Type,
+			Languages: languages,
+			Missing:   missing,
+		})
+	}
+
+	return report
+}
+
+// translatableNodeTranslations returns the node's translations map for the
+// node types that support one
+func translatableNodeTranslations(node *models.FlowNode) map[string]string {
+	switch node.Type {
+	case models.NodeTypeMessage:
+		return node.MessageConfig().Translations
+	case models.NodeTypeImage:
+		return node.ImageConfig().Translations
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const leadIntakeTokenLength = 24
+
+// Lead is a normalized lead extracted from an intake payload
+type Lead struct {
+	Name  string
+	Phone string
+}
+
+// LeadIntakeService turns external form fills (generic webhooks, Meta Lead
+// Ads) into WhatsApp prospects. Each configured source has a public intake
+// URL keyed by its verify token
+type LeadIntakeService struct {
+	leadSourceRepo repository.LeadSourceRepository
+}
+
+// NewLeadIntakeService creates a new lead intake service
+func NewLeadIntakeService(leadSourceRepo repository.LeadSourceRepository) *LeadIntakeService {
+	return &LeadIntakeService{leadSourceRepo: leadSourceRepo}
+}
+
+// CreateLeadSource configures a new intake source with a fresh verify token
+func (s *LeadIntakeService) CreateLeadSource(idDevice, name, sourceType, flowID, templateMessage string) (*models.LeadSource, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("lead source name is required")
+	}
+	if sourceType != models.LeadSourceGeneric && sourceType != models.LeadSourceMeta {
+		sourceType = models.LeadSourceGeneric
+	}
+
+	token, err := generateLeadIntakeToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate intake token: %w", err)
+	}
+
+	source := &models.LeadSource{
+		ID:              uuid.New().String(),
+		IDDevice:        idDevice,
+		Name:            name,
+		SourceType:      sourceType,
+		FlowID:          flowID,
+		TemplateMessage: templateMessage,
+		VerifyToken:     token,
+	}
+
+	if err := s.leadSourceRepo.CreateLeadSource(source); err != nil {
+		return nil, fmt.Errorf("failed to create lead source: %w", err)
+	}
+
+	source.IntakeURL = s.intakeURL(source.VerifyToken)
+
+	logrus.WithFields(logrus.Fields{
+		"source_id":   source.ID,
+		"source_type": source.SourceType,
+	}).Info("📥 LEAD: Intake source created")
+
+	return source, nil
+}
+
+// GetLeadSources returns a device's intake sources with their public URLs
+func (s *LeadIntakeService) GetLeadSources(idDevice string) ([]models.LeadSource, error) {
+	sources, err := s.leadSourceRepo.GetLeadSourcesByDevice(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sources {
+		sources[i].IntakeURL = s.intakeURL(sources[i].VerifyToken)
+	}
+	return sources, nil
+}
+
+// GetLeadSourceByToken returns the source behind an intake token
+func (s *LeadIntakeService) GetLeadSourceByToken(token string) (*models.LeadSource, error) {
+	return s.leadSourceRepo.GetLeadSourceByToken(token)
+}
+
+// DeleteLeadSource removes an intake source
+func (s *LeadIntakeService) DeleteLeadSource(id, idDevice string) error {
+	return s.leadSourceRepo.DeleteLeadSource(id, idDevice)
+}
+
+// CountLead counts one lead received through a source
+func (s *LeadIntakeService) CountLead(id string) {
+	if err := s.leadSourceRepo.IncrementLeadCount(id); err != nil {
+		logrus.WithError(err).Error("📥 LEAD: Failed to count lead")
+	}
+}
+
+// ExtractLead pulls a normalized name and phone number out of an intake
+// payload according to the source type
+func (s *LeadIntakeService) ExtractLead(source *models.LeadSource, body []byte) (*Lead, error) {
+	var lead *Lead
+	if source.SourceType == models.LeadSourceMeta {
+		lead = extractMetaLead(body)
+	} else {
+		lead = extractGenericLead(body)
+	}
+
+	if lead == nil || lead.Phone == "" {
+		return nil, fmt.Errorf("no phone number found in lead payload")
+	}
+
+	return lead, nil
+}
+
+// extractGenericLead reads a flat JSON form payload. Common field names from
+// form builders are accepted for the phone and name
+func extractGenericLead(body []byte) *Lead {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+
+	lead := &Lead{}
+	for _, key := range []string{"phone", "phone_number", "whatsapp", "mobile", "tel"} {
+		if value, ok := payload[key].(string); ok && value != "" {
+			lead.Phone = normalizeLeadPhone(value)
+			break
+		}
+	}
+	for _, key := range []string{"name", "full_name", "first_name"} {
+		if value, ok := payload[key].(string); ok && value != "" {
+			lead.Name = strings.TrimSpace(value)
+			break
+		}
+	}
+
+	return lead
+}
+
+// metaLeadPayload mirrors the Meta Lead Ads webhook structure, with field_data
+// included the way lead forwarding integrations deliver it
+type metaLeadPayload struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				FieldData []struct {
+					Name   string   `json:"name"`
+					Values []string `json:"values"`
+				} `json:"field_data"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// extractMetaLead walks a Meta Lead Ads webhook payload for the first lead's
+// field data
+func extractMetaLead(body []byte) *Lead {
+	var payload metaLeadPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+
+	lead := &Lead{}
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			for _, field := range change.Value.FieldData {
+				if len(field.Values) == 0 {
+					continue
+				}
+				switch strings.ToLower(field.Name) {
+				case "phone_number", "phone", "whatsapp_number":
+					if lead.Phone == "" {
+						lead.Phone = normalizeLeadPhone(field.Values[0])
+					}
+				case "full_name", "name", "first_name":
+					if lead.Name == "" {
+						lead.Name = strings.TrimSpace(field.Values[0])
+					}
+				}
+			}
+		}
+	}
+
+	return lead
+}
+
+// normalizeLeadPhone strips everything but digits so the number matches the
+// prospect_num format used by the webhook providers
+func normalizeLeadPhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// intakeURL renders the public intake URL for a token, resolving the base the
+// same way billing callbacks do
+func (s *LeadIntakeService) intakeURL(token string) string {
+	baseURL := os.Getenv("BASE_URL")
+	if baseURL == "" {
+		if domain := os.Getenv("RAILWAY_PUBLIC_DOMAIN"); domain != "" {
+			baseURL = "https://" + domain
+		} else {
+			baseURL = "http://localhost:8080"
+		}
+	}
+	return fmt.Sprintf("%s/api/leads/intake/%s", strings.TrimRight(baseURL, "/"), token)
+}
+
+// generateLeadIntakeToken builds a random URL-safe intake token
+func generateLeadIntakeToken() (string, error) {
+	buf := make([]byte, leadIntakeTokenLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for _, b := range buf {
+		builder.WriteByte(voucherCodeCharset[int(b)%len(voucherCodeCharset)])
+	}
+
+	return strings.ToLower(builder.String()), nil
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LeadScoringService turns conversation signals into a per-prospect score.
+// Devices configure which signals count — stages reached, keywords in inbound
+// messages, classified intents and fast replies — and how many points each is
+// worth. The score is exposed as the lead_score flow variable; crossing the
+// configured threshold fires the lead.qualified notification and can start a
+// drip flow
+
+// leadScoringConfigTTL bounds how long a cached device config is reused
+// before it is re-read, so rule edits take effect without a restart
+const leadScoringConfigTTL = 30 * time.Second
+
+type cachedScoringConfig struct {
+	config   *models.LeadScoringConfig
+	cachedAt time.Time
+}
+
+// LeadScoringService assigns and updates lead scores from conversation signals
+type LeadScoringService struct {
+	leadScoringRepo repository.LeadScoringRepository
+	notifications   *NotificationConnectorService
+	aiWhatsapp      AIWhatsappService
+
+	mu           sync.Mutex
+	configCache  map[string]cachedScoringConfig
+	lastOutbound map[string]time.Time
+}
+
+// NewLeadScoringService creates a new lead scoring service
+func NewLeadScoringService(leadScoringRepo repository.LeadScoringRepository) *LeadScoringService {
+	return &LeadScoringService{
+		leadScoringRepo: leadScoringRepo,
+		configCache:     make(map[string]cachedScoringConfig),
+		lastOutbound:    make(map[string]time.Time),
+	}
+}
+
+// SetNotificationService wires threshold triggers into Slack/Discord
+func (s *LeadScoringService) SetNotificationService(notifications *NotificationConnectorService) {
+	s.notifications = notifications
+}
+
+// SetAIWhatsappService wires drip flow starts in
+func (s *LeadScoringService) SetAIWhatsappService(aiWhatsapp AIWhatsappService) {
+	s.aiWhatsapp = aiWhatsapp
+}
+
+// GetConfig returns a device's scoring rules, or nil when never configured
+func (s *LeadScoringService) GetConfig(idDevice string) (*models.LeadScoringConfig, error) {
+	return s.leadScoringRepo.GetLeadScoringConfig(idDevice)
+}
+
+// SaveConfig saves a device's scoring rules
+func (s *LeadScoringService) SaveConfig(config *models.LeadScoringConfig) error {
+	if err := s.leadScoringRepo.SetLeadScoringConfig(config); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.configCache, config.IDDevice)
+	s.mu.Unlock()
+
+	logrus.WithFields(logrus.Fields{
+		"id_device": config.IDDevice,
+		"enabled":   config.Enabled,
+	}).Info("🔢 LEAD SCORE: Scoring config saved")
+
+	return nil
+}
+
+// Score returns a prospect's current score
+func (s *LeadScoringService) Score(prospectNum, idDevice string) (int, error) {
+	return s.leadScoringRepo.GetLeadScore(prospectNum, idDevice)
+}
+
+// MarkOutbound records when the bot last messaged a prospect, so the next
+// inbound message can be checked for the fast-reply bonus
+func (s *LeadScoringService) MarkOutbound(idDevice, prospectNum string) {
+	s.mu.Lock()
+	s.lastOutbound[idDevice+":"+prospectNum] = time.Now()
+	s.mu.Unlock()
+}
+
+// ScoreMessage scores an inbound message: configured keywords it contains
+// plus the fast-reply bonus when it arrives soon after the last outbound
+func (s *LeadScoringService) ScoreMessage(idDevice, prospectNum, content string) {
+	config := s.enabledConfig(idDevice)
+	if config == nil {
+		return
+	}
+
+	delta := 0
+	lowered := strings.ToLower(content)
+	for keyword, points := range config.KeywordPoints {
+		if keyword != "" && strings.Contains(lowered, strings.ToLower(keyword)) {
+			delta += points
+		}
+	}
+
+	if config.FastReplyPoints != 0 && config.FastReplySeconds > 0 {
+		s.mu.Lock()
+		sentAt, ok := s.lastOutbound[idDevice+":"+prospectNum]
+		delete(s.lastOutbound, idDevice+":"+prospectNum)
+		s.mu.Unlock()
+
+		if ok && time.Since(sentAt) <= time.Duration(config.FastReplySeconds)*time.Second {
+			delta += config.FastReplyPoints
+		}
+	}
+
+	s.applyDelta(config, prospectNum, delta, "message")
+}
+
+// ScoreStage scores a prospect reaching a stage
+func (s *LeadScoringService) ScoreStage(idDevice, prospectNum, stage string) {
+	config := s.enabledConfig(idDevice)
+	if config == nil {
+		return
+	}
+
+	s.applyDelta(config, prospectNum, config.StagePoints[stage], "stage "+stage)
+}
+
+// ScoreIntent scores a classified intent
+func (s *LeadScoringService) ScoreIntent(idDevice, prospectNum, intent string) {
+	config := s.enabledConfig(idDevice)
+	if config == nil {
+		return
+	}
+
+	s.applyDelta(config, prospectNum, config.IntentPoints[intent], "intent "+intent)
+}
+
+// enabledConfig returns the device's config when scoring is enabled, caching
+// reads so scoring hooks stay off the hot path's database budget
+func (s *LeadScoringService) enabledConfig(idDevice string) *models.LeadScoringConfig {
+	s.mu.Lock()
+	cached, ok := s.configCache[idDevice]
+	s.mu.Unlock()
+
+	if !ok || time.Since(cached.cachedAt) > leadScoringConfigTTL {
+		config, err := s.leadScoringRepo.GetLeadScoringConfig(idDevice)
+		if err != nil {
+			return nil
+		}
+		cached = cachedScoringConfig{config: config, cachedAt: time.Now()}
+		s.mu.Lock()
+		s.configCache[idDevice] = cached
+		s.mu.Unlock()
+	}
+
+	if cached.config == nil || !cached.config.Enabled {
+		return nil
+	}
+	return cached.config
+}
+
+// applyDelta adds the points and fires the qualification trigger when the
+// score crosses the threshold upward
+func (s *LeadScoringService) applyDelta(config *models.LeadScoringConfig, prospectNum string, delta int, signal string) {
+	if delta == 0 {
+		return
+	}
+
+	previous, err := s.leadScoringRepo.GetLeadScore(prospectNum, config.IDDevice)
+	if err != nil {
+		return
+	}
+
+	score, err := s.leadScoringRepo.AddLeadScore(prospectNum, config.IDDevice, delta)
+	if err != nil {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    config.IDDevice,
+		"signal":       signal,
+		"delta":        delta,
+		"score":        score,
+	}).Info("🔢 LEAD SCORE: Score updated")
+
+	if config.NotifyThreshold > 0 && previous < config.NotifyThreshold && score >= config.NotifyThreshold {
+		s.onThresholdCrossed(config, prospectNum, score)
+	}
+}
+
+// onThresholdCrossed notifies sales and optionally starts the drip flow
+func (s *LeadScoringService) onThresholdCrossed(config *models.LeadScoringConfig, prospectNum string, score int) {
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    config.IDDevice,
+		"score":        score,
+		"threshold":    config.NotifyThreshold,
+	}).Info("🔢 LEAD SCORE: Prospect qualified")
+
+	if s.notifications != nil {
+		s.notifications.NotifyEvent(config.IDDevice, models.OpsEventLeadQualified,
+			fmt.Sprintf("Prospect %s reached lead score %d (threshold %d) on device %s",
+				prospectNum, score, config.NotifyThreshold, config.IDDevice))
+	}
+
+	if config.DripFlowID != "" && s.aiWhatsapp != nil {
+		go func() {
+			if _, err := s.aiWhatsapp.StartFlowExecution(prospectNum, config.IDDevice, config.DripFlowID, nil); err != nil {
+				logrus.WithError(err).Error("🔢 LEAD SCORE: Failed to start drip flow for qualified lead")
+			}
+		}()
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const webhookSecretLength = 32
+
+// LifecycleWebhookService delivers execution lifecycle events (started,
+// stage changed, completed, ...) to subscribed external URLs so funnel state
+// can be mirrored without polling. Payloads are signed with HMAC-SHA256 in
+// the X-Nodepath-Signature header
+type LifecycleWebhookService struct {
+	subscriptionRepo repository.WebhookSubscriptionRepository
+	httpClient       *http.Client
+	bus              MessageBus
+}
+
+// busLifecycleEvent is the bus payload for one lifecycle event
+type busLifecycleEvent struct {
+	IDDevice string                 `json:"id_device"`
+	Event    string                 `json:"event"`
+	Data     map[string]interface{} `json:"data"`
+}
+
+// NewLifecycleWebhookService creates a new lifecycle webhook service
+func NewLifecycleWebhookService(subscriptionRepo repository.WebhookSubscriptionRepository) *LifecycleWebhookService {
+	return &LifecycleWebhookService{
+		subscriptionRepo: subscriptionRepo,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// CreateSubscription registers a webhook URL for a device with a fresh secret
+func (s *LifecycleWebhookService) CreateSubscription(idDevice, webhookURL, events string) (*models.WebhookSubscription, error) {
+	parsed, err := url.Parse(strings.TrimSpace(webhookURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("url must be a valid http(s) URL")
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	subscription := &models.WebhookSubscription{
+		ID:       uuid.New().String(),
+		IDDevice: idDevice,
+		URL:      parsed.String(),
+		Events:   strings.TrimSpace(events),
+		Secret:   secret,
+		Enabled:  true,
+	}
+
+	if err := s.subscriptionRepo.CreateWebhookSubscription(subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"subscription_id": subscription.ID,
+		"url":             subscription.URL,
+	}).Info("📡 LIFECYCLE: Webhook subscription created")
+
+	return subscription, nil
+}
+
+// GetSubscriptions returns a device's webhook subscriptions
+func (s *LifecycleWebhookService) GetSubscriptions(idDevice string) ([]models.WebhookSubscription, error) {
+	return s.subscriptionRepo.GetWebhookSubscriptionsByDevice(idDevice)
+}
+
+// SetSubscriptionEnabled toggles a subscription, scoped to the owning device
+func (s *LifecycleWebhookService) SetSubscriptionEnabled(id, idDevice string, enabled bool) error {
+	return s.subscriptionRepo.SetWebhookSubscriptionEnabled(id, idDevice, enabled)
+}
+
+// DeleteSubscription removes a subscription, scoped to the owning device
+func (s *LifecycleWebhookService) DeleteSubscription(id, idDevice string) error {
+	return s.subscriptionRepo.DeleteWebhookSubscription(id, idDevice)
+}
+
+// SetMessageBus routes event fan-out through the message bus instead of a
+// direct goroutine, so delivery can move out of process
+func (s *LifecycleWebhookService) SetMessageBus(bus MessageBus) {
+	s.bus = bus
+	bus.Subscribe(BusTopicLifecycleEvents, func(payload []byte) {
+		var busEvent busLifecycleEvent
+		if err := json.Unmarshal(payload, &busEvent); err != nil {
+			logrus.WithError(err).Error("📡 LIFECYCLE: Failed to decode bus event")
+			return
+		}
+		s.deliverEvent(busEvent.IDDevice, busEvent.Event, busEvent.Data)
+	})
+}
+
+// EmitEvent delivers an event to every matching subscription off the calling
+// goroutine — emission never blocks or fails message processing
+func (s *LifecycleWebhookService) EmitEvent(idDevice, event string, data map[string]interface{}) {
+	if s.bus != nil {
+		payload, err := json.Marshal(busLifecycleEvent{IDDevice: idDevice, Event: event, Data: data})
+		if err == nil && s.bus.Publish(BusTopicLifecycleEvents, payload) == nil {
+			return
+		}
+	}
+	go s.deliverEvent(idDevice, event, data)
+}
+
+func (s *LifecycleWebhookService) deliverEvent(idDevice, event string, data map[string]interface{}) {
+	subscriptions, err := s.subscriptionRepo.GetEnabledWebhookSubscriptions(idDevice)
+	if err != nil || len(subscriptions) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"event":     event,
+		"id_device": idDevice,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"data":      data,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("📡 LIFECYCLE: Failed to marshal event payload")
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscription.WantsEvent(event) {
+			continue
+		}
+		s.deliverToSubscription(&subscription, event, payload)
+	}
+}
+
+func (s *LifecycleWebhookService) deliverToSubscription(subscription *models.WebhookSubscription, event string, payload []byte) {
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(payload))
+	if err != nil {
+		logrus.WithError(err).Error("📡 LIFECYCLE: Failed to build webhook request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Nodepath-Event", event)
+	req.Header.Set("X-Nodepath-Signature", signWebhookPayload(subscription.Secret, payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).WithField("url", subscription.URL).Warn("📡 LIFECYCLE: Webhook delivery failed")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		logrus.WithFields(logrus.Fields{
+			"url":    subscription.URL,
+			"status": resp.StatusCode,
+			"event":  event,
+		}).Warn("📡 LIFECYCLE: Webhook endpoint returned non-success status")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"url":   subscription.URL,
+		"event": event,
+	}).Debug("📡 LIFECYCLE: Webhook delivered")
+}
+
+// signWebhookPayload computes the hex HMAC-SHA256 signature receivers verify
+func signWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateWebhookSecret builds a random signing secret
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
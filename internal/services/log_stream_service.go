@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Live log tailing for support. A logrus hook captures every log line into a
+// ring buffer and fans it out to subscribers filtered by device or trace ID,
+// so support can watch a customer's message processing without SSH access.
+// Phone numbers and email addresses are redacted before a line is stored
+
+const (
+	// logStreamBufferSize is how many recent lines are kept for backlog replay
+	logStreamBufferSize = 500
+
+	// logStreamChannelSize buffers per-subscriber delivery; slow consumers
+	// drop lines instead of blocking the logger
+	logStreamChannelSize = 64
+
+	// logStreamMaxFieldLen truncates oversized field values (webhook payloads)
+	logStreamMaxFieldLen = 300
+)
+
+var (
+	logStreamPhonePattern = regexp.MustCompile(`\+?\d{9,15}`)
+	logStreamEmailPattern = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
+)
+
+// LogLine is one captured, redacted log entry
+type LogLine struct {
+	Time     string            `json:"time"`
+	Level    string            `json:"level"`
+	Message  string            `json:"message"`
+	DeviceID string            `json:"device_id,omitempty"`
+	TraceID  string            `json:"trace_id,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+type logSubscriber struct {
+	deviceID string
+	traceID  string
+	ch       chan LogLine
+}
+
+// LogStreamService is a logrus hook feeding the support log tail
+type LogStreamService struct {
+	mu          sync.Mutex
+	buffer      []LogLine
+	subscribers map[int]*logSubscriber
+	nextID      int
+}
+
+var (
+	logStreamOnce    sync.Once
+	defaultLogStream *LogStreamService
+)
+
+// LogStream returns the process-wide log stream service
+func LogStream() *LogStreamService {
+	logStreamOnce.Do(func() {
+		defaultLogStream = &LogStreamService{
+			subscribers: make(map[int]*logSubscriber),
+		}
+	})
+	return defaultLogStream
+}
+
+// Levels implements logrus.Hook; every level is captured
+func (s *LogStreamService) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook: it redacts the entry, appends it to the ring
+// buffer and fans it out to matching subscribers. It never blocks the logger
+func (s *LogStreamService) Fire(entry *logrus.Entry) error {
+	line := LogLine{
+		Time:    entry.Time.Format(time.RFC3339),
+		Level:   entry.Level.String(),
+		Message: redactLogValue(entry.Message),
+	}
+
+	for _, key := range []string{"id_device", "device_id", "deviceID", "idDevice"} {
+		if value, ok := entry.Data[key]; ok {
+			line.DeviceID = fmt.Sprint(value)
+			break
+		}
+	}
+	if value, ok := entry.Data["trace_id"]; ok {
+		line.TraceID = fmt.Sprint(value)
+	}
+
+	if len(entry.Data) > 0 {
+		line.Fields = make(map[string]string, len(entry.Data))
+		for key, value := range entry.Data {
+			rendered := fmt.Sprint(value)
+			if len(rendered) > logStreamMaxFieldLen {
+				rendered = rendered[:logStreamMaxFieldLen] + "…"
+			}
+			line.Fields[key] = redactLogValue(rendered)
+		}
+	}
+
+	s.mu.Lock()
+	s.buffer = append(s.buffer, line)
+	if len(s.buffer) > logStreamBufferSize {
+		s.buffer = s.buffer[len(s.buffer)-logStreamBufferSize:]
+	}
+	for _, sub := range s.subscribers {
+		if !sub.matches(line) {
+			continue
+		}
+		select {
+		case sub.ch <- line:
+		default:
+			// Slow consumer: drop the line rather than stall logging
+		}
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// matches applies the subscriber's device/trace filter to a line
+func (sub *logSubscriber) matches(line LogLine) bool {
+	if sub.deviceID != "" && line.DeviceID != sub.deviceID {
+		return false
+	}
+	if sub.traceID != "" && line.TraceID != sub.traceID {
+		return false
+	}
+	return true
+}
+
+// Subscribe registers a tail for the given filters (either may be empty, but
+// callers should require at least one). It returns the subscriber ID, the
+// live channel and the matching backlog from the ring buffer
+func (s *LogStreamService) Subscribe(deviceID, traceID string) (int, <-chan LogLine, []LogLine) {
+	sub := &logSubscriber{
+		deviceID: deviceID,
+		traceID:  traceID,
+		ch:       make(chan LogLine, logStreamChannelSize),
+	}
+
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = sub
+
+	var backlog []LogLine
+	for _, line := range s.buffer {
+		if sub.matches(line) {
+			backlog = append(backlog, line)
+		}
+	}
+	s.mu.Unlock()
+
+	return id, sub.ch, backlog
+}
+
+// Unsubscribe removes a tail and closes its channel
+func (s *LogStreamService) Unsubscribe(id int) {
+	s.mu.Lock()
+	sub, ok := s.subscribers[id]
+	if ok {
+		delete(s.subscribers, id)
+	}
+	s.mu.Unlock()
+
+	if ok {
+		close(sub.ch)
+	}
+}
+
+// redactLogValue masks phone numbers and email addresses in captured text
+func redactLogValue(value string) string {
+	value = logStreamEmailPattern.ReplaceAllStringFunc(value, MaskEmail)
+	value = logStreamPhonePattern.ReplaceAllStringFunc(value, MaskPhone)
+	return value
+}
@@ -373,6 +373,162 @@ func (ms *MediaService) GetStats() map[string]interface{} {
 	}
 }
 
+// mediaPublicBaseURL is the public origin for locally served media, used when
+// no CDN is configured so providers can fetch generated files
+const mediaPublicBaseURL = "https://nodepath-chat-production.up.railway.app"
+
+// generatedFileName maps an image generation cache key to its stored file
+func generatedFileName(cacheKey string) string {
+	return fmt.Sprintf("gen_%s.png", cacheKey)
+}
+
+// publicFileURL returns the URL providers should fetch a stored file from
+func (ms *MediaService) publicFileURL(fileName string) string {
+	if ms.cdnEnabled && ms.cdnBaseURL != "" {
+		return fmt.Sprintf("%s/%s", strings.TrimRight(ms.cdnBaseURL, "/"), fileName)
+	}
+	return fmt.Sprintf("%s/media/%s", mediaPublicBaseURL, fileName)
+}
+
+// generatedDocumentName maps a document generation cache key to its stored file
+func generatedDocumentName(cacheKey string) string {
+	return fmt.Sprintf("gen_%s.pdf", cacheKey)
+}
+
+// GeneratedImageURL returns the URL of a previously generated image, or false
+// when nothing is stored for the cache key
+func (ms *MediaService) GeneratedImageURL(cacheKey string) (string, bool) {
+	fileName := generatedFileName(cacheKey)
+	filePath := filepath.Join(ms.localBasePath, fileName)
+	if _, err := os.Stat(filePath); err != nil {
+		return "", false
+	}
+	return ms.publicFileURL(fileName), true
+}
+
+// GeneratedDocumentURL returns the URL of a previously generated document,
+// or false when nothing is stored for the cache key
+func (ms *MediaService) GeneratedDocumentURL(cacheKey string) (string, bool) {
+	fileName := generatedDocumentName(cacheKey)
+	filePath := filepath.Join(ms.localBasePath, fileName)
+	if _, err := os.Stat(filePath); err != nil {
+		return "", false
+	}
+	return ms.publicFileURL(fileName), true
+}
+
+// SaveGeneratedDocument stores a rendered PDF under its cache key and
+// returns the URL to send as a document message
+func (ms *MediaService) SaveGeneratedDocument(cacheKey string, data []byte) (string, error) {
+	fileName := generatedDocumentName(cacheKey)
+	filePath := filepath.Join(ms.localBasePath, fileName)
+
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save generated document: %v", err)
+	}
+	ms.setCachedFile(fileName, data, "application/pdf")
+
+	url := ms.publicFileURL(fileName)
+	logrus.WithFields(logrus.Fields{
+		"cache_key": cacheKey,
+		"file_name": fileName,
+		"url":       url,
+		"file_size": len(data),
+	}).Info("📄 MEDIA SERVICE: Generated document stored")
+
+	return url, nil
+}
+
+// SaveGeneratedImage stores a rendered image under its cache key and returns
+// the URL to send; repeated personalizations with the same inputs reuse the
+// stored file via GeneratedImageURL
+func (ms *MediaService) SaveGeneratedImage(cacheKey string, data []byte) (string, error) {
+	fileName := generatedFileName(cacheKey)
+	filePath := filepath.Join(ms.localBasePath, fileName)
+
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save generated image: %v", err)
+	}
+	ms.setCachedFile(fileName, data, "image/png")
+
+	url := ms.publicFileURL(fileName)
+	logrus.WithFields(logrus.Fields{
+		"cache_key": cacheKey,
+		"file_name": fileName,
+		"url":       url,
+		"file_size": len(data),
+	}).Info("🖼️ MEDIA SERVICE: Generated image stored")
+
+	return url, nil
+}
+
+// inboundFileName maps a provider media URL to its stored file; the name is
+// derived from the source URL so redelivered webhooks reuse the same file
+func inboundFileName(sourceURL, mimeType string) string {
+	hash := md5.Sum([]byte(sourceURL))
+	return fmt.Sprintf("in_%s%s", hex.EncodeToString(hash[:])[:16], inboundFileExtension(mimeType))
+}
+
+// inboundFileExtension picks a file extension for downloaded inbound media
+func inboundFileExtension(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "audio/ogg":
+		return ".ogg"
+	case "audio/mpeg":
+		return ".mp3"
+	case "audio/mp4":
+		return ".m4a"
+	case "video/mp4":
+		return ".mp4"
+	case "application/pdf":
+		return ".pdf"
+	default:
+		return ".bin"
+	}
+}
+
+// InboundMediaURL returns the URL of previously stored inbound media,
+// or false when the source URL has not been downloaded yet
+func (ms *MediaService) InboundMediaURL(sourceURL, mimeType string) (string, bool) {
+	fileName := inboundFileName(sourceURL, mimeType)
+	filePath := filepath.Join(ms.localBasePath, fileName)
+	if _, err := os.Stat(filePath); err != nil {
+		return "", false
+	}
+	return ms.publicFileURL(fileName), true
+}
+
+// SaveInboundMedia stores media downloaded from a provider webhook and
+// returns the URL it is served from
+func (ms *MediaService) SaveInboundMedia(sourceURL string, data []byte, mimeType string) (string, error) {
+	fileName := inboundFileName(sourceURL, mimeType)
+	filePath := filepath.Join(ms.localBasePath, fileName)
+
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save inbound media: %v", err)
+	}
+	ms.setCachedFile(fileName, data, mimeType)
+
+	url := ms.publicFileURL(fileName)
+	logrus.WithFields(logrus.Fields{
+		"source_url": sourceURL,
+		"file_name":  fileName,
+		"mime_type":  mimeType,
+		"url":        url,
+		"file_size":  len(data),
+	}).Info("📥 MEDIA SERVICE: Inbound media stored")
+
+	return url, nil
+}
+
 // DeleteFile removes a file from local storage and cache
 func (ms *MediaService) DeleteFile(fileName string) error {
 	// Remove from cache
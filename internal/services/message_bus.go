@@ -0,0 +1,146 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MessageBus abstracts inter-service event delivery — webhook fan-out, AI job
+// dispatch and similar asynchronous work. Small deployments run the default
+// in-process implementation; multi-process setups select an external
+// transport (NATS, Kafka) with the MESSAGE_BUS env var once a driver build
+// registers it via RegisterBusDriver
+
+// BusTopicLifecycleEvents carries execution lifecycle events for webhook
+// fan-out
+const BusTopicLifecycleEvents = "lifecycle.events"
+
+// busChannelBuffer is the per-subscriber buffer of the in-process bus; when a
+// slow subscriber falls this far behind, new messages for it are dropped
+const busChannelBuffer = 256
+
+// BusHandler processes one message from a subscribed topic
+type BusHandler func(payload []byte)
+
+// MessageBus publishes and subscribes raw payloads on named topics
+type MessageBus interface {
+	Publish(topic string, payload []byte) error
+	Subscribe(topic string, handler BusHandler)
+	Close() error
+}
+
+var (
+	busDriversMu sync.Mutex
+	busDrivers   = make(map[string]func() (MessageBus, error))
+)
+
+// RegisterBusDriver makes an external transport selectable via MESSAGE_BUS.
+// Driver builds call this from an init function
+func RegisterBusDriver(name string, factory func() (MessageBus, error)) {
+	busDriversMu.Lock()
+	defer busDriversMu.Unlock()
+	busDrivers[name] = factory
+}
+
+// NewMessageBusFromEnv builds the bus selected by MESSAGE_BUS, falling back
+// to the in-process implementation when unset or when the driver fails
+func NewMessageBusFromEnv() MessageBus {
+	name := strings.TrimSpace(strings.ToLower(os.Getenv("MESSAGE_BUS")))
+	if name == "" || name == "inproc" {
+		return newInProcessBus()
+	}
+
+	busDriversMu.Lock()
+	factory, ok := busDrivers[name]
+	busDriversMu.Unlock()
+
+	if !ok {
+		logrus.Warnf("🚌 BUS: No driver registered for MESSAGE_BUS=%s, using in-process bus", name)
+		return newInProcessBus()
+	}
+
+	bus, err := factory()
+	if err != nil {
+		logrus.WithError(err).Warnf("🚌 BUS: Failed to start %s driver, using in-process bus", name)
+		return newInProcessBus()
+	}
+
+	logrus.Infof("🚌 BUS: Using %s message bus", name)
+	return bus
+}
+
+// inProcessBus is the default single-process bus: per-subscriber buffered
+// channels drained by their own goroutines, so publishing never blocks
+// message processing
+type inProcessBus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]chan []byte
+	closed      bool
+}
+
+func newInProcessBus() *inProcessBus {
+	return &inProcessBus{
+		subscribers: make(map[string][]chan []byte),
+	}
+}
+
+// Publish delivers the payload to every subscriber of the topic. Subscribers
+// that have fallen busChannelBuffer messages behind miss this one
+func (b *inProcessBus) Publish(topic string, payload []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return fmt.Errorf("message bus is closed")
+	}
+
+	for _, subscriber := range b.subscribers[topic] {
+		select {
+		case subscriber <- payload:
+		default:
+			logrus.WithField("topic", topic).Warn("🚌 BUS: Subscriber buffer full, dropping message")
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a handler for the topic; each subscriber drains its own
+// buffered channel on a dedicated goroutine
+func (b *inProcessBus) Subscribe(topic string, handler BusHandler) {
+	channel := make(chan []byte, busChannelBuffer)
+
+	b.mu.Lock()
+	b.subscribers[topic] = append(b.subscribers[topic], channel)
+	b.mu.Unlock()
+
+	go func() {
+		for payload := range channel {
+			handler(payload)
+		}
+	}()
+}
+
+// Close stops every subscriber goroutine
+func (b *inProcessBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	for _, subscribers := range b.subscribers {
+		for _, subscriber := range subscribers {
+			close(subscriber)
+		}
+	}
+	b.subscribers = make(map[string][]chan []byte)
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MessageEventService handles inbound message edit and delete events from WhatsApp providers
+// Providers like WAHA send message.edited / message.revoked events when users modify messages
+type MessageEventService struct {
+	aiRepo           repository.AIWhatsappRepository
+	websocketService *WebSocketService
+}
+
+// NewMessageEventService creates a new message event service
+func NewMessageEventService(aiRepo repository.AIWhatsappRepository, websocketService *WebSocketService) *MessageEventService {
+	return &MessageEventService{
+		aiRepo:           aiRepo,
+		websocketService: websocketService,
+	}
+}
+
+// HandleMessageEdited updates the stored conversation history when a user edits a message.
+// Returns true when the edited message was the pending user input (conversation is waiting
+// for a reply and the edited message is the latest USER entry), so the caller can re-evaluate
+// the current flow step with the new text.
+func (s *MessageEventService) HandleMessageEdited(prospectNum, idDevice, oldText, newText string) (bool, error) {
+	conversation, err := s.aiRepo.GetAIWhatsappByProspectAndDevice(prospectNum, idDevice)
+	if err != nil {
+		return false, fmt.Errorf("failed to get conversation for edit event: %w", err)
+	}
+	if conversation == nil {
+		logrus.WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+		}).Debug("✏️ EDIT: No conversation found for edit event, ignoring")
+		return false, nil
+	}
+
+	editedIdx := -1
+	lastUserIdx := -1
+
+	if conversation.ConvLast.Valid && conversation.ConvLast.String != "" {
+		lines := strings.Split(conversation.ConvLast.String, "\n")
+
+		// Find the line being edited (last match wins) and the latest USER entry
+		for i, line := range lines {
+			if !strings.HasPrefix(line, "USER: ") {
+				continue
+			}
+			lastUserIdx = i
+			if oldText == "" || strings.TrimSpace(strings.TrimPrefix(line, "USER: ")) == strings.TrimSpace(oldText) {
+				editedIdx = i
+			}
+		}
+
+		// If we couldn't match the old text, fall back to the latest USER entry
+		if editedIdx == -1 {
+			editedIdx = lastUserIdx
+		}
+
+		if editedIdx != -1 {
+			lines[editedIdx] = fmt.Sprintf("USER: %s (edited)", newText)
+			updatedConvLast := strings.Join(lines, "\n")
+			conversation.ConvLast = sql.NullString{String: updatedConvLast, Valid: true}
+
+			if err := s.aiRepo.UpdateConvLast(prospectNum, updatedConvLast); err != nil {
+				return false, fmt.Errorf("failed to update conversation history for edit event: %w", err)
+			}
+		}
+	}
+
+	// Flag the edit in the inbox via WebSocket so agents see the updated message
+	if s.websocketService != nil {
+		s.websocketService.SendToDevice(idDevice, "message_edited", map[string]interface{}{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"old_text":     oldText,
+			"new_text":     newText,
+			"timestamp":    time.Now().Unix(),
+		})
+	}
+
+	// The edit is the pending user input when the conversation is waiting for a reply
+	// and the edited message is the most recent USER entry
+	pendingInput := editedIdx != -1 && editedIdx == lastUserIdx &&
+		conversation.WaitingForReply.Valid && conversation.WaitingForReply.Int32 == 1
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num":  prospectNum,
+		"id_device":     idDevice,
+		"pending_input": pendingInput,
+	}).Info("✏️ EDIT: Conversation history updated for edited message")
+
+	return pendingInput, nil
+}
+
+// HandleMessageDeleted marks a deleted message in the stored conversation history
+// and notifies the inbox so agents don't act on revoked content
+func (s *MessageEventService) HandleMessageDeleted(prospectNum, idDevice, deletedText string) error {
+	conversation, err := s.aiRepo.GetAIWhatsappByProspectAndDevice(prospectNum, idDevice)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation for delete event: %w", err)
+	}
+	if conversation == nil {
+		logrus.WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+		}).Debug("🗑️ DELETE: No conversation found for delete event, ignoring")
+		return nil
+	}
+
+	if conversation.ConvLast.Valid && conversation.ConvLast.String != "" {
+		lines := strings.Split(conversation.ConvLast.String, "\n")
+
+		deletedIdx := -1
+		for i, line := range lines {
+			if !strings.HasPrefix(line, "USER: ") {
+				continue
+			}
+			if deletedText == "" || strings.TrimSpace(strings.TrimPrefix(line, "USER: ")) == strings.TrimSpace(deletedText) {
+				deletedIdx = i
+			}
+		}
+
+		if deletedIdx != -1 {
+			lines[deletedIdx] = "USER: (deleted)"
+			updatedConvLast := strings.Join(lines, "\n")
+
+			if err := s.aiRepo.UpdateConvLast(prospectNum, updatedConvLast); err != nil {
+				return fmt.Errorf("failed to update conversation history for delete event: %w", err)
+			}
+		}
+	}
+
+	if s.websocketService != nil {
+		s.websocketService.SendToDevice(idDevice, "message_deleted", map[string]interface{}{
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"deleted_text": deletedText,
+			"timestamp":    time.Now().Unix(),
+		})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+	}).Info("🗑️ DELETE: Conversation history updated for deleted message")
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MetricsService is a small in-process metrics registry exposing the
+// Prometheus text format on /metrics. The message pipeline pushes counters,
+// gauges and histograms here; no external client library is involved, the
+// text exposition format is written directly
+
+// metricLatencyBuckets are the histogram bucket upper bounds (seconds) used
+// for processing and AI call latency
+var metricLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metric names pushed by the message pipeline
+const (
+	MetricWebhookReceived      = "nodepath_webhook_received_total"
+	MetricQueueDepth           = "nodepath_queue_depth"
+	MetricProcessingSeconds    = "nodepath_message_processing_seconds"
+	MetricAICallSeconds        = "nodepath_ai_call_seconds"
+	MetricProviderSendFailures = "nodepath_provider_send_failures_total"
+	MetricFlowCompletions      = "nodepath_flow_completions_total"
+	MetricQueueErrors          = "nodepath_queue_errors_total"
+	MetricComponentHealthy     = "nodepath_component_healthy"
+	MetricTurnLatencySeconds   = "nodepath_turn_latency_seconds"
+)
+
+// metricHelp describes each metric in the exposition output
+var metricHelp = map[string]string{
+	MetricWebhookReceived:      "Webhook payloads received per provider",
+	MetricQueueDepth:           "Current queue depth per queue",
+	MetricProcessingSeconds:    "Message processing latency in seconds",
+	MetricAICallSeconds:        "AI completion call latency in seconds",
+	MetricProviderSendFailures: "Failed provider sends per provider",
+	MetricFlowCompletions:      "Completed flow executions per flow",
+	MetricQueueErrors:          "Queue processing errors",
+	MetricComponentHealthy:     "Component health from the health checker (1 healthy, 0 unhealthy)",
+	MetricTurnLatencySeconds:   "Webhook receipt to first outbound send latency per turn",
+}
+
+// metricHistogram holds cumulative bucket counts for one label set
+type metricHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// MetricsService stores all metric series, keyed by name and rendered labels
+type MetricsService struct {
+	mu         sync.Mutex
+	counters   map[string]map[string]float64
+	gauges     map[string]map[string]float64
+	histograms map[string]map[string]*metricHistogram
+}
+
+// defaultMetrics is the process-wide registry; services push to it the same
+// way providers use the package-level provider registry
+var defaultMetrics = NewMetricsService()
+
+// Metrics returns the process-wide metrics registry
+func Metrics() *MetricsService {
+	return defaultMetrics
+}
+
+// NewMetricsService creates an empty metrics registry
+func NewMetricsService() *MetricsService {
+	return &MetricsService{
+		counters:   make(map[string]map[string]float64),
+		gauges:     make(map[string]map[string]float64),
+		histograms: make(map[string]map[string]*metricHistogram),
+	}
+}
+
+// IncCounter adds one to a counter series
+func (ms *MetricsService) IncCounter(name string, labels map[string]string) {
+	ms.AddCounter(name, labels, 1)
+}
+
+// AddCounter adds a value to a counter series
+func (ms *MetricsService) AddCounter(name string, labels map[string]string, value float64) {
+	key := renderLabels(labels)
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.counters[name] == nil {
+		ms.counters[name] = make(map[string]float64)
+	}
+	ms.counters[name][key] += value
+}
+
+// SetGauge sets a gauge series to a value
+func (ms *MetricsService) SetGauge(name string, labels map[string]string, value float64) {
+	key := renderLabels(labels)
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.gauges[name] == nil {
+		ms.gauges[name] = make(map[string]float64)
+	}
+	ms.gauges[name][key] = value
+}
+
+// ObserveHistogram records one observation into a histogram series
+func (ms *MetricsService) ObserveHistogram(name string, labels map[string]string, value float64) {
+	key := renderLabels(labels)
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.histograms[name] == nil {
+		ms.histograms[name] = make(map[string]*metricHistogram)
+	}
+	hist := ms.histograms[name][key]
+	if hist == nil {
+		hist = &metricHistogram{buckets: make([]uint64, len(metricLatencyBuckets))}
+		ms.histograms[name][key] = hist
+	}
+	for i, upper := range metricLatencyBuckets {
+		if value <= upper {
+			hist.buckets[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+// Render writes every series in the Prometheus text exposition format
+func (ms *MetricsService) Render() string {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	var out strings.Builder
+	for _, name := range sortedMetricNames(ms.counters) {
+		writeMetricHeader(&out, name, "counter")
+		for _, key := range sortedSeriesKeys(ms.counters[name]) {
+			fmt.Fprintf(&out, "%s%s %g\n", name, key, ms.counters[name][key])
+		}
+	}
+	for _, name := range sortedMetricNames(ms.gauges) {
+		writeMetricHeader(&out, name, "gauge")
+		for _, key := range sortedSeriesKeys(ms.gauges[name]) {
+			fmt.Fprintf(&out, "%s%s %g\n", name, key, ms.gauges[name][key])
+		}
+	}
+	for name := range ms.histograms {
+		writeMetricHeader(&out, name, "histogram")
+		for key, hist := range ms.histograms[name] {
+			for i, upper := range metricLatencyBuckets {
+				fmt.Fprintf(&out, "%s_bucket%s %d\n", name, mergeLabel(key, "le", fmt.Sprintf("%g", upper)), hist.buckets[i])
+			}
+			fmt.Fprintf(&out, "%s_bucket%s %d\n", name, mergeLabel(key, "le", "+Inf"), hist.count)
+			fmt.Fprintf(&out, "%s_sum%s %g\n", name, key, hist.sum)
+			fmt.Fprintf(&out, "%s_count%s %d\n", name, key, hist.count)
+		}
+	}
+	return out.String()
+}
+
+// writeMetricHeader emits the HELP and TYPE lines for one metric
+func writeMetricHeader(out *strings.Builder, name, metricType string) {
+	if help, ok := metricHelp[name]; ok {
+		fmt.Fprintf(out, "# HELP %s %s\n", name, help)
+	}
+	fmt.Fprintf(out, "# TYPE %s %s\n", name, metricType)
+}
+
+// renderLabels formats a label set as {k="v",...} with sorted keys, or ""
+// when there are no labels, so identical label sets share one series
+func renderLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%q", key, labels[key]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// mergeLabel adds one label pair into an already rendered label set, used
+// for histogram bucket boundaries
+func mergeLabel(rendered, key, value string) string {
+	pair := fmt.Sprintf("%s=%q", key, value)
+	if rendered == "" {
+		return "{" + pair + "}"
+	}
+	return strings.TrimSuffix(rendered, "}") + "," + pair + "}"
+}
+
+// sortedMetricNames returns the metric names in stable order
+func sortedMetricNames(series map[string]map[string]float64) []string {
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedSeriesKeys returns one metric's label sets in stable order
+func sortedSeriesKeys(series map[string]float64) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
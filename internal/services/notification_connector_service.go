@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationConnectorService posts operational events (device disconnected,
+// SLA breaches, flow completions, quota warnings) to Slack or Discord channel
+// webhooks configured per device. Delivery is fire-and-forget so a slow
+// channel never blocks message processing
+type NotificationConnectorService struct {
+	connectorRepo repository.NotificationConnectorRepository
+	httpClient    *http.Client
+
+	// Last status seen per device so disconnect notifications fire on the
+	// transition, not on every status poll
+	mu               sync.Mutex
+	lastDeviceStatus map[string]string
+}
+
+// NewNotificationConnectorService creates a new notification connector service
+func NewNotificationConnectorService(connectorRepo repository.NotificationConnectorRepository) *NotificationConnectorService {
+	return &NotificationConnectorService{
+		connectorRepo: connectorRepo,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		lastDeviceStatus: make(map[string]string),
+	}
+}
+
+// CreateConnector registers a Slack or Discord channel webhook for a device
+func (s *NotificationConnectorService) CreateConnector(idDevice, platform, webhookURL, events string) (*models.NotificationConnector, error) {
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	if platform != models.ConnectorPlatformSlack && platform != models.ConnectorPlatformDiscord {
+		return nil, fmt.Errorf("platform must be %q or %q", models.ConnectorPlatformSlack, models.ConnectorPlatformDiscord)
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(webhookURL))
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("webhook_url must be a valid https URL")
+	}
+
+	connector := &models.NotificationConnector{
+		ID:         uuid.New().String(),
+		IDDevice:   idDevice,
+		Platform:   platform,
+		WebhookURL: parsed.String(),
+		Events:     strings.TrimSpace(events),
+		Enabled:    true,
+	}
+
+	if err := s.connectorRepo.CreateNotificationConnector(connector); err != nil {
+		return nil, fmt.Errorf("failed to create notification connector: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"connector_id": connector.ID,
+		"platform":     connector.Platform,
+	}).Info("🔔 NOTIFY: Notification connector created")
+
+	return connector, nil
+}
+
+// GetConnectors returns a device's notification connectors
+func (s *NotificationConnectorService) GetConnectors(idDevice string) ([]models.NotificationConnector, error) {
+	return s.connectorRepo.GetNotificationConnectorsByDevice(idDevice)
+}
+
+// SetConnectorEnabled toggles a connector, scoped to the owning device
+func (s *NotificationConnectorService) SetConnectorEnabled(id, idDevice string, enabled bool) error {
+	return s.connectorRepo.SetNotificationConnectorEnabled(id, idDevice, enabled)
+}
+
+// DeleteConnector removes a connector, scoped to the owning device
+func (s *NotificationConnectorService) DeleteConnector(id, idDevice string) error {
+	return s.connectorRepo.DeleteNotificationConnector(id, idDevice)
+}
+
+// NotifyEvent posts an operational event to every matching connector off the
+// calling goroutine — delivery never blocks or fails the caller
+func (s *NotificationConnectorService) NotifyEvent(idDevice, event, message string) {
+	go s.deliverEvent(idDevice, event, message)
+}
+
+// NotifyDeviceStatus feeds one device status observation in and raises the
+// disconnect event only when the status transitions to disconnected
+func (s *NotificationConnectorService) NotifyDeviceStatus(idDevice, status string) {
+	status = strings.ToLower(strings.TrimSpace(status))
+
+	s.mu.Lock()
+	previous, seen := s.lastDeviceStatus[idDevice]
+	s.lastDeviceStatus[idDevice] = status
+	s.mu.Unlock()
+
+	if status != "disconnected" || !seen || previous == "disconnected" {
+		return
+	}
+
+	s.NotifyEvent(idDevice, models.OpsEventDeviceDisconnected,
+		fmt.Sprintf("Device %s is disconnected from WhatsApp — scan the QR code to reconnect", idDevice))
+}
+
+func (s *NotificationConnectorService) deliverEvent(idDevice, event, message string) {
+	connectors, err := s.connectorRepo.GetEnabledNotificationConnectors(idDevice)
+	if err != nil || len(connectors) == 0 {
+		return
+	}
+
+	for _, connector := range connectors {
+		if !connector.WantsEvent(event) {
+			continue
+		}
+		s.deliverToConnector(&connector, event, message)
+	}
+}
+
+// deliverToConnector posts one event in the platform's webhook format
+func (s *NotificationConnectorService) deliverToConnector(connector *models.NotificationConnector, event, message string) {
+	var body map[string]string
+	switch connector.Platform {
+	case models.ConnectorPlatformDiscord:
+		body = map[string]string{"content": message}
+	default:
+		body = map[string]string{"text": message}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+
+	resp, err := s.httpClient.Post(connector.WebhookURL, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"connector_id": connector.ID,
+			"platform":     connector.Platform,
+			"event":        event,
+		}).Warn("🔔 NOTIFY: Failed to deliver notification")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logrus.WithFields(logrus.Fields{
+			"connector_id": connector.ID,
+			"platform":     connector.Platform,
+			"event":        event,
+			"status":       resp.StatusCode,
+		}).Warn("🔔 NOTIFY: Channel webhook rejected the notification")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"connector_id": connector.ID,
+		"platform":     connector.Platform,
+		"event":        event,
+	}).Debug("🔔 NOTIFY: Notification delivered")
+}
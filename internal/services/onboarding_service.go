@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OnboardingService tracks a new user's setup progress through the wizard
+// (create device, connect provider, scan QR, create first flow, send a test
+// message). Steps the data already proves are derived from the live tables;
+// the rest are marked explicitly by the frontend. It also offers one-click
+// creation of a starter flow bound to the user's device
+
+// starterFlowName is the name of the one-click starter flow
+const starterFlowName = "Starter Flow"
+
+// OnboardingService serves wizard state and the starter flow shortcut
+type OnboardingService struct {
+	onboardingRepo repository.OnboardingRepository
+	flowService    *FlowService
+}
+
+// NewOnboardingService creates a new onboarding service
+func NewOnboardingService(onboardingRepo repository.OnboardingRepository, flowService *FlowService) *OnboardingService {
+	return &OnboardingService{
+		onboardingRepo: onboardingRepo,
+		flowService:    flowService,
+	}
+}
+
+// State returns the user's wizard progress in step order
+func (s *OnboardingService) State(userID string) (*models.OnboardingState, error) {
+	marked, err := s.onboardingRepo.GetMarkedSteps(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	derived := map[string]func(string) (bool, error){
+		models.OnboardingStepCreateDevice:    s.onboardingRepo.HasDevice,
+		models.OnboardingStepConnectProvider: s.onboardingRepo.HasProvider,
+		models.OnboardingStepCreateFlow:      s.onboardingRepo.HasFlow,
+	}
+
+	state := &models.OnboardingState{
+		Steps:     make([]models.OnboardingStepState, 0, len(models.OnboardingSteps)),
+		Completed: true,
+	}
+	for _, step := range models.OnboardingSteps {
+		completed := marked[step]
+		if !completed {
+			if check, ok := derived[step]; ok {
+				completed, _ = check(userID)
+			}
+		}
+		if !completed {
+			state.Completed = false
+		}
+		state.Steps = append(state.Steps, models.OnboardingStepState{Step: step, Completed: completed})
+	}
+
+	return state, nil
+}
+
+// CompleteStep marks one wizard step done
+func (s *OnboardingService) CompleteStep(userID, step string) error {
+	known := false
+	for _, candidate := range models.OnboardingSteps {
+		if candidate == step {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown onboarding step %q", step)
+	}
+
+	if err := s.onboardingRepo.MarkStep(userID, step); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": userID,
+		"step":    step,
+	}).Info("🚀 ONBOARDING: Step completed")
+
+	return nil
+}
+
+// CreateStarterFlow creates a minimal welcome flow bound to the given device
+// (or the user's first device when none is given) and marks the create-flow
+// step done
+func (s *OnboardingService) CreateStarterFlow(userID, idDevice string) (*models.ChatbotFlow, error) {
+	if idDevice == "" {
+		first, err := s.onboardingRepo.GetFirstDeviceID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if first == "" {
+			return nil, fmt.Errorf("create a device before creating the starter flow")
+		}
+		idDevice = first
+	}
+
+	nodes := []models.FlowNode{
+		{
+			ID:       "start",
+			Type:     models.NodeTypeStart,
+			Data:     map[string]interface{}{"keyword": ""},
+			Position: models.Position{X: 100, Y: 100},
+		},
+		{
+			ID:   "welcome",
+			Type: models.NodeTypeMessage,
+			Data: map[string]interface{}{
+				"message": "Hi {name}! 👋 Thanks for reaching out. How can we help you today?",
+			},
+			Position: models.Position{X: 100, Y: 250},
+		},
+	}
+	edges := []models.FlowEdge{
+		{ID: "start-welcome", Source: "start", Target: "welcome"},
+	}
+
+	nodesJSON, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, err
+	}
+	edgesJSON, err := json.Marshal(edges)
+	if err != nil {
+		return nil, err
+	}
+
+	nodesRaw := json.RawMessage(nodesJSON)
+	edgesRaw := json.RawMessage(edgesJSON)
+	flow := &models.ChatbotFlow{
+		Name:     starterFlowName,
+		Niche:    "onboarding",
+		IdDevice: idDevice,
+		Nodes:    &nodesRaw,
+		Edges:    &edgesRaw,
+	}
+
+	if err := s.flowService.CreateFlow(flow); err != nil {
+		return nil, err
+	}
+
+	if err := s.onboardingRepo.MarkStep(userID, models.OnboardingStepCreateFlow); err != nil {
+		logrus.WithError(err).Warn("🚀 ONBOARDING: Failed to mark create-flow step")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"id_device": idDevice,
+		"flow_id":   flow.ID,
+	}).Info("🚀 ONBOARDING: Starter flow created")
+
+	return flow, nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Sentinel errors the handlers translate into HTTP statuses
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrOrganizationForbidden = errors.New("insufficient organization role")
+)
+
+// OrganizationService manages teams and the role checks that let members
+// share each other's devices, flows and conversations. A user always has
+// full access to their own resources; organization roles only ever grant
+// additional access, never take it away
+type OrganizationService struct {
+	orgRepo repository.OrganizationRepository
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(orgRepo repository.OrganizationRepository) *OrganizationService {
+	return &OrganizationService{orgRepo: orgRepo}
+}
+
+// CreateOrganization creates an organization with the creator as owner
+func (s *OrganizationService) CreateOrganization(userID, name string) (*models.Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("organization name is required")
+	}
+
+	org := &models.Organization{
+		ID:      uuid.New().String(),
+		Name:    name,
+		OwnerID: userID,
+	}
+	if err := s.orgRepo.CreateOrganization(org); err != nil {
+		return nil, err
+	}
+	if err := s.orgRepo.AddMember(org.ID, userID, models.OrgRoleOwner); err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"org_id":  org.ID,
+		"user_id": userID,
+	}).Info("👥 ORG: Organization created")
+
+	return org, nil
+}
+
+// ListOrganizations returns the organizations the user belongs to
+func (s *OrganizationService) ListOrganizations(userID string) ([]*models.Organization, error) {
+	return s.orgRepo.ListOrganizationsByUser(userID)
+}
+
+// ListMembers returns the organization's members; the caller must be a member
+func (s *OrganizationService) ListMembers(actorID, orgID string) ([]*models.OrganizationMember, error) {
+	if err := s.requireRole(actorID, orgID, models.OrgRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.ListMembers(orgID)
+}
+
+// AddMember adds a user to the organization or changes their role. Admins
+// manage members; only the owner can grant the owner or admin roles
+func (s *OrganizationService) AddMember(actorID, orgID, userID, role string) error {
+	if !models.OrgRoleValid(role) {
+		return fmt.Errorf("invalid role: %s", role)
+	}
+
+	required := models.OrgRoleAdmin
+	if models.OrgRoleAllows(role, models.OrgRoleAdmin) {
+		required = models.OrgRoleOwner
+	}
+	if err := s.requireRole(actorID, orgID, required); err != nil {
+		return err
+	}
+
+	if err := s.orgRepo.AddMember(orgID, userID, role); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"org_id":  orgID,
+		"user_id": userID,
+		"role":    role,
+	}).Info("👥 ORG: Member added")
+
+	return nil
+}
+
+// RemoveMember removes a user from the organization. Members can always
+// leave on their own; removing someone else requires admin. The owner
+// membership cannot be removed
+func (s *OrganizationService) RemoveMember(actorID, orgID, userID string) error {
+	org, err := s.orgRepo.GetOrganization(orgID)
+	if err != nil {
+		return err
+	}
+	if org == nil {
+		return ErrOrganizationNotFound
+	}
+	if userID == org.OwnerID {
+		return fmt.Errorf("the organization owner cannot be removed")
+	}
+
+	if actorID != userID {
+		if err := s.requireRole(actorID, orgID, models.OrgRoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	return s.orgRepo.RemoveMember(orgID, userID)
+}
+
+// RoleInOrganization returns the user's role, or empty when not a member
+func (s *OrganizationService) RoleInOrganization(orgID, userID string) (string, error) {
+	return s.orgRepo.GetMemberRole(orgID, userID)
+}
+
+// CanAccessUserResources reports whether the actor may touch resources owned
+// by ownerID at the required role level: either they are the owner, or they
+// hold a sufficient role in an organization the owner belongs to
+func (s *OrganizationService) CanAccessUserResources(actorID, ownerID, requiredRole string) bool {
+	if actorID == ownerID {
+		return true
+	}
+
+	roles, err := s.orgRepo.ListSharedRoles(actorID, ownerID)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to resolve shared organization roles, denying access")
+		return false
+	}
+	for _, role := range roles {
+		if models.OrgRoleAllows(role, requiredRole) {
+			return true
+		}
+	}
+	return false
+}
+
+// TeamUserIDs returns the user plus everyone sharing an organization with
+// them, for expanding "my resources" listings to the whole team
+func (s *OrganizationService) TeamUserIDs(userID string) []string {
+	ids, err := s.orgRepo.ListTeamUserIDs(userID)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to list team members, falling back to own resources")
+		return []string{userID}
+	}
+	return ids
+}
+
+// requireRole checks the actor's role in the organization
+func (s *OrganizationService) requireRole(actorID, orgID, requiredRole string) error {
+	org, err := s.orgRepo.GetOrganization(orgID)
+	if err != nil {
+		return err
+	}
+	if org == nil {
+		return ErrOrganizationNotFound
+	}
+
+	role, err := s.orgRepo.GetMemberRole(orgID, actorID)
+	if err != nil {
+		return err
+	}
+	if role == "" || !models.OrgRoleAllows(role, requiredRole) {
+		return ErrOrganizationForbidden
+	}
+	return nil
+}
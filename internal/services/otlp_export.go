@@ -0,0 +1,113 @@
+package services
+
+import "fmt"
+
+// OTLP/HTTP JSON request encoding for trace export. Only the subset of the
+// protocol the tracer produces is modelled - string attributes and spans of
+// kind internal - which keeps the exporter dependency-free
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// OTLP span kind and status codes used by the exporter
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusCodeOK     = 1
+	otlpStatusCodeError  = 2
+)
+
+// buildOTLPRequest packs finished spans into one OTLP export request
+func buildOTLPRequest(serviceName string, spans []*TraceSpan) otlpExportRequest {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		encoded = append(encoded, encodeOTLPSpan(span))
+	}
+
+	return otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{{
+					Key:   "service.name",
+					Value: otlpAnyValue{StringValue: serviceName},
+				}},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: serviceName},
+				Spans: encoded,
+			}},
+		}},
+	}
+}
+
+// encodeOTLPSpan converts one span into its wire representation
+func encodeOTLPSpan(span *TraceSpan) otlpSpan {
+	attributes := make([]otlpKeyValue, 0, len(span.Attributes))
+	for key, value := range span.Attributes {
+		attributes = append(attributes, otlpKeyValue{
+			Key:   key,
+			Value: otlpAnyValue{StringValue: value},
+		})
+	}
+
+	status := otlpStatus{Code: otlpStatusCodeOK}
+	if span.ErrorMsg != "" {
+		status = otlpStatus{Code: otlpStatusCodeError, Message: span.ErrorMsg}
+	}
+
+	return otlpSpan{
+		TraceID:           span.TraceID,
+		SpanID:            span.SpanID,
+		ParentSpanID:      span.ParentID,
+		Name:              span.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: fmt.Sprintf("%d", span.StartTime.UnixNano()),
+		EndTimeUnixNano:   fmt.Sprintf("%d", span.EndTime.UnixNano()),
+		Attributes:        attributes,
+		Status:            status,
+	}
+}
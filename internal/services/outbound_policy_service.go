@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+)
+
+// OutboundPolicyService decides whether a proactive outbound message may be
+// sent to a number: opted-out numbers are never messaged and quiet hours
+// block sends inside the device's do-not-disturb window
+type OutboundPolicyService struct {
+	policyRepo repository.OutboundPolicyRepository
+}
+
+// NewOutboundPolicyService creates a new outbound policy service
+func NewOutboundPolicyService(policyRepo repository.OutboundPolicyRepository) *OutboundPolicyService {
+	return &OutboundPolicyService{policyRepo: policyRepo}
+}
+
+// CanMessage reports whether a proactive message to the number is allowed
+// right now. The reason is set when the message is blocked
+func (s *OutboundPolicyService) CanMessage(idDevice, prospectNum string) (bool, string, error) {
+	optedOut, err := s.policyRepo.IsOptedOut(idDevice, prospectNum)
+	if err != nil {
+		return false, "", err
+	}
+	if optedOut {
+		return false, "number has opted out of messages", nil
+	}
+
+	quietHours, err := s.policyRepo.GetQuietHours(idDevice)
+	if err != nil {
+		return false, "", err
+	}
+	if quietHours != nil && quietHours.Enabled && inQuietWindow(time.Now().Hour(), quietHours.StartHour, quietHours.EndHour) {
+		return false, fmt.Sprintf("inside quiet hours (%02d:00-%02d:00)", quietHours.StartHour, quietHours.EndHour), nil
+	}
+
+	return true, "", nil
+}
+
+// inQuietWindow checks whether an hour falls inside a window that may wrap
+// past midnight (e.g. start 22, end 8)
+func inQuietWindow(hour, start, end int) bool {
+	if start == end {
+		return false
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// AddOptOut puts a number on the device's opt-out list
+func (s *OutboundPolicyService) AddOptOut(idDevice, prospectNum string) error {
+	return s.policyRepo.AddOptOut(idDevice, prospectNum)
+}
+
+// RemoveOptOut takes a number off the device's opt-out list
+func (s *OutboundPolicyService) RemoveOptOut(idDevice, prospectNum string) error {
+	return s.policyRepo.RemoveOptOut(idDevice, prospectNum)
+}
+
+// GetOptOuts returns the device's opt-out list
+func (s *OutboundPolicyService) GetOptOuts(idDevice string) ([]models.OptOut, error) {
+	return s.policyRepo.GetOptOuts(idDevice)
+}
+
+// GetQuietHours returns the device's quiet hours with defaults when never set
+func (s *OutboundPolicyService) GetQuietHours(idDevice string) (*models.QuietHours, error) {
+	quietHours, err := s.policyRepo.GetQuietHours(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	if quietHours == nil {
+		quietHours = &models.QuietHours{IDDevice: idDevice, StartHour: 22, EndHour: 8}
+	}
+	return quietHours, nil
+}
+
+// SetQuietHours saves the device's quiet hours after validating the window
+func (s *OutboundPolicyService) SetQuietHours(quietHours *models.QuietHours) error {
+	if quietHours.StartHour < 0 || quietHours.StartHour > 23 || quietHours.EndHour < 0 || quietHours.EndHour > 23 {
+		return fmt.Errorf("start_hour and end_hour must be between 0 and 23")
+	}
+	return s.policyRepo.SetQuietHours(quietHours)
+}
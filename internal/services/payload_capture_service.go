@@ -0,0 +1,114 @@
+package services
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PayloadCaptureService stores webhook payloads that failed normalization so new
+// provider payload variants can be added to the extraction logic quickly. All
+// values are PII-masked before they are persisted
+type PayloadCaptureService struct {
+	unknownPayloadRepo repository.UnknownPayloadRepository
+}
+
+// sensitivePayloadKeys are payload fields whose values are fully redacted because
+// they carry message content or identity data
+var sensitivePayloadKeys = map[string]bool{
+	"message":     true,
+	"text":        true,
+	"body":        true,
+	"caption":     true,
+	"name":        true,
+	"sender_name": true,
+	"pushname":    true,
+	"email":       true,
+}
+
+// phoneDigitsPattern matches digit runs long enough to be a phone number
+var phoneDigitsPattern = regexp.MustCompile(`\d{7,}`)
+
+// NewPayloadCaptureService creates a new payload capture service
+func NewPayloadCaptureService(unknownPayloadRepo repository.UnknownPayloadRepository) *PayloadCaptureService {
+	return &PayloadCaptureService{
+		unknownPayloadRepo: unknownPayloadRepo,
+	}
+}
+
+// CaptureUnknownPayload masks and stores a payload whose required fields could not
+// be extracted. Failures are logged but never block webhook processing
+func (s *PayloadCaptureService) CaptureUnknownPayload(idDevice, provider string, missingFields []string, webhookData map[string]interface{}) {
+	masked := maskPayloadValue(webhookData)
+
+	payloadJSON, err := json.Marshal(masked)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to marshal masked webhook payload")
+		return
+	}
+
+	err = s.unknownPayloadRepo.CreateUnknownPayload(idDevice, provider, strings.Join(missingFields, ","), string(payloadJSON))
+	if err != nil {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device":      idDevice,
+		"provider":       provider,
+		"missing_fields": missingFields,
+	}).Info("📋 WEBHOOK: Captured unknown payload format for maintainer review")
+}
+
+// GetUnknownPayloads returns recently captured payloads, optionally filtered by provider
+func (s *PayloadCaptureService) GetUnknownPayloads(provider string, limit int) ([]models.UnknownWebhookPayload, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.unknownPayloadRepo.GetUnknownPayloads(provider, limit)
+}
+
+// maskPayloadValue walks a decoded JSON value and masks PII while preserving the
+// payload structure and key names maintainers need to see
+func maskPayloadValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			if str, ok := child.(string); ok && sensitivePayloadKeys[strings.ToLower(key)] {
+				masked[key] = maskString(str, true)
+				continue
+			}
+			masked[key] = maskPayloadValue(child)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(typed))
+		for i, child := range typed {
+			masked[i] = maskPayloadValue(child)
+		}
+		return masked
+	case string:
+		return maskString(typed, false)
+	default:
+		return typed
+	}
+}
+
+// maskString redacts a string value. Sensitive fields are fully redacted keeping
+// only the length; other strings keep their shape with phone-length digit runs masked
+func maskString(value string, sensitive bool) string {
+	if sensitive {
+		return strings.Repeat("*", len(value))
+	}
+	return phoneDigitsPattern.ReplaceAllStringFunc(value, func(digits string) string {
+		if len(digits) <= 4 {
+			return digits
+		}
+		return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+	})
+}
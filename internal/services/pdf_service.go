@@ -0,0 +1,109 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Minimal PDF writer for document nodes. Invoices and quotes only need
+// positioned text on an A4 page, so documents are written directly in the
+// PDF 1.4 format instead of pulling in a PDF dependency
+
+// A4 page dimensions in PDF points
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+
+	// pdfMargin is the left margin and top/bottom inset for text
+	pdfMargin = 50
+
+	// pdfTitleSize and pdfBodySize are the default font sizes
+	pdfTitleSize = 18
+	pdfBodySize  = 11
+
+	// pdfLineSpacing multiplies the font size into the line advance
+	pdfLineSpacing = 1.5
+)
+
+// PDFLine is one rendered line of a PDF document
+type PDFLine struct {
+	Text string
+	Size int
+	Bold bool
+}
+
+// RenderPDF builds a single-page A4 PDF from a title and text lines. Lines
+// that run past the bottom margin are dropped
+func RenderPDF(title string, lines []PDFLine) []byte {
+	var content bytes.Buffer
+	y := float64(pdfPageHeight - pdfMargin - pdfTitleSize)
+
+	if title != "" {
+		writePDFText(&content, title, pdfTitleSize, true, y)
+		y -= pdfTitleSize * pdfLineSpacing * 1.5
+	}
+
+	for _, line := range lines {
+		size := line.Size
+		if size <= 0 {
+			size = pdfBodySize
+		}
+		if y < pdfMargin {
+			break
+		}
+		if line.Text != "" {
+			writePDFText(&content, line.Text, size, line.Bold, y)
+		}
+		y -= float64(size) * pdfLineSpacing
+	}
+
+	return assemblePDF(content.Bytes())
+}
+
+// writePDFText appends one positioned text operation to the content stream
+func writePDFText(content *bytes.Buffer, text string, size int, bold bool, y float64) {
+	font := "/F1"
+	if bold {
+		font = "/F2"
+	}
+	fmt.Fprintf(content, "BT %s %d Tf %d %.1f Td (%s) Tj ET\n", font, size, pdfMargin, y, escapePDFText(text))
+}
+
+// escapePDFText escapes the characters that delimit PDF string literals
+func escapePDFText(text string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "(", "\\(", ")", "\\)", "\r", " ", "\n", " ")
+	return replacer.Replace(text)
+}
+
+// assemblePDF wraps the content stream in the document skeleton - catalog,
+// page tree, one page, the two standard fonts - and the cross-reference table
+func assemblePDF(content []byte) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", pdfPageWidth, pdfPageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(objects)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+
+	return buf.Bytes()
+}
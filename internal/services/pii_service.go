@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PIIService implements field-level access control over personal data:
+// viewer-role users see masked phone numbers and emails everywhere prospect
+// data surfaces, while admins see full values. Users without an assigned
+// role keep full access, so enabling the feature never locks existing
+// accounts out of their data
+
+// User roles. RoleAdmin sees full PII; RoleViewer sees masked values
+const (
+	RoleAdmin  = "admin"
+	RoleViewer = "viewer"
+)
+
+// piiFieldNames are the map keys the generic masker treats as PII
+var piiFieldNames = map[string]bool{
+	"prospect_num": true,
+	"phone":        true,
+	"phone_number": true,
+	"email":        true,
+	"gmail":        true,
+}
+
+// PIIService resolves user roles and masks personal fields
+type PIIService struct {
+	roleRepo repository.UserRoleRepository
+}
+
+// NewPIIService creates a new PII service
+func NewPIIService(roleRepo repository.UserRoleRepository) *PIIService {
+	return &PIIService{roleRepo: roleRepo}
+}
+
+// RoleForUser returns the user's role. Users without an assigned role keep
+// full access; a failed lookup falls back to the restrictive viewer role so
+// infrastructure errors never expose unmasked PII
+func (s *PIIService) RoleForUser(userID string) string {
+	role, err := s.roleRepo.GetRole(userID)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to look up user role, defaulting to viewer")
+		return RoleViewer
+	}
+	if role == "" {
+		return RoleAdmin
+	}
+	return role
+}
+
+// SetRole assigns a role to a user
+func (s *PIIService) SetRole(userID, role string) error {
+	if role != RoleAdmin && role != RoleViewer {
+		return fmt.Errorf("unknown role %q", role)
+	}
+	return s.roleRepo.SetRole(userID, role)
+}
+
+// HasAssignedAdminRole reports whether the user was explicitly granted the
+// admin role. Unlike RoleForUser it never defaults to admin, so gates on
+// operational endpoints stay closed for unassigned users and on lookup errors
+func (s *PIIService) HasAssignedAdminRole(userID string) bool {
+	role, err := s.roleRepo.GetRole(userID)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to look up user role for admin gate")
+		return false
+	}
+	return role == RoleAdmin
+}
+
+// CanManageRoles reports whether the user may assign roles. Only explicitly
+// assigned admins qualify - the permissive no-role default that keeps PII
+// visible must not let everyone grant themselves admin. The one exception is
+// bootstrap: while no admin has been assigned at all, any authenticated user
+// may create the first one
+func (s *PIIService) CanManageRoles(userID string) bool {
+	if s.HasAssignedAdminRole(userID) {
+		return true
+	}
+
+	anyAdmin, err := s.roleRepo.HasAnyAdmin()
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to check for assigned admins, denying role change")
+		return false
+	}
+	return !anyAdmin
+}
+
+// IsViewer reports whether the user only sees masked PII
+func (s *PIIService) IsViewer(userID string) bool {
+	return s.RoleForUser(userID) == RoleViewer
+}
+
+// MaskPhone keeps the first three and last two digits: 60123456789 becomes
+// 601******89. Short values mask entirely
+func MaskPhone(phone string) string {
+	if len(phone) <= 5 {
+		return strings.Repeat("*", len(phone))
+	}
+	return phone[:3] + strings.Repeat("*", len(phone)-5) + phone[len(phone)-2:]
+}
+
+// MaskEmail keeps the first character of the local part and the domain:
+// john@example.com becomes j***@example.com
+func MaskEmail(email string) string {
+	at := strings.Index(email, "@")
+	if at <= 0 {
+		return MaskPhone(email)
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskPIIString masks a value as an email or phone depending on its shape
+func maskPIIString(value string) string {
+	if strings.Contains(value, "@") {
+		return MaskEmail(value)
+	}
+	return MaskPhone(value)
+}
+
+// MaskValue recursively masks PII fields inside maps and slices, returning
+// a masked copy. Non-container values pass through untouched, so it is safe
+// to run over any API or WebSocket payload
+func (s *PIIService) MaskValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(typed))
+		for key, entry := range typed {
+			if piiFieldNames[strings.ToLower(key)] {
+				if text, ok := entry.(string); ok {
+					masked[key] = maskPIIString(text)
+					continue
+				}
+			}
+			masked[key] = s.MaskValue(entry)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(typed))
+		for i, entry := range typed {
+			masked[i] = s.MaskValue(entry)
+		}
+		return masked
+	case []map[string]interface{}:
+		masked := make([]map[string]interface{}, len(typed))
+		for i, entry := range typed {
+			masked[i] = s.MaskValue(entry).(map[string]interface{})
+		}
+		return masked
+	default:
+		return value
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ProductService manages the per-device product catalog that catalog flow
+// nodes send to prospects
+type ProductService struct {
+	productRepo repository.ProductRepository
+}
+
+// NewProductService creates a new product service
+func NewProductService(productRepo repository.ProductRepository) *ProductService {
+	return &ProductService{productRepo: productRepo}
+}
+
+// CreateProduct validates and stores a new catalog product
+func (s *ProductService) CreateProduct(product *models.Product) error {
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+
+	if product.ID == "" {
+		product.ID = uuid.New().String()
+	}
+
+	if err := s.productRepo.CreateProduct(product); err != nil {
+		return fmt.Errorf("failed to create product: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"product_id": product.ID,
+		"id_device":  product.IDDevice,
+		"sku":        product.SKU,
+	}).Info("✅ Product created")
+
+	return nil
+}
+
+// GetProduct returns a single product by ID
+func (s *ProductService) GetProduct(id string) (*models.Product, error) {
+	return s.productRepo.GetProductByID(id)
+}
+
+// GetProducts returns all products for a device
+func (s *ProductService) GetProducts(idDevice string) ([]models.Product, error) {
+	return s.productRepo.GetProductsByDevice(idDevice, false)
+}
+
+// GetActiveProducts returns the products a catalog node may send
+func (s *ProductService) GetActiveProducts(idDevice string) ([]models.Product, error) {
+	return s.productRepo.GetProductsByDevice(idDevice, true)
+}
+
+// UpdateProduct validates and saves changes to an existing product
+func (s *ProductService) UpdateProduct(product *models.Product) error {
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+
+	existing, err := s.productRepo.GetProductByID(product.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("product not found: %s", product.ID)
+	}
+
+	if err := s.productRepo.UpdateProduct(product); err != nil {
+		return fmt.Errorf("failed to update product: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProduct removes a product from the catalog
+func (s *ProductService) DeleteProduct(id string) error {
+	return s.productRepo.DeleteProduct(id)
+}
+
+// validateProduct checks the fields required to sell a product
+func validateProduct(product *models.Product) error {
+	if strings.TrimSpace(product.Name) == "" {
+		return fmt.Errorf("product name is required")
+	}
+	if strings.TrimSpace(product.SKU) == "" {
+		return fmt.Errorf("product SKU is required")
+	}
+	if product.Price < 0 {
+		return fmt.Errorf("product price cannot be negative")
+	}
+	if product.IDDevice == "" {
+		return fmt.Errorf("product device is required")
+	}
+	return nil
+}
+
+// FormatCatalogPage renders one page of products as a numbered WhatsApp list
+// the prospect replies to by number. Numbering is continuous across pages so
+// "7" always means the same product
+func FormatCatalogPage(products []models.Product, page, pageSize int) string {
+	if pageSize < 1 {
+		pageSize = 5
+	}
+	start := page * pageSize
+	if start >= len(products) {
+		return ""
+	}
+	end := start + pageSize
+	if end > len(products) {
+		end = len(products)
+	}
+
+	var builder strings.Builder
+	for i := start; i < end; i++ {
+		product := products[i]
+		builder.WriteString(fmt.Sprintf("%d. %s - RM%.2f", i+1, product.Name, product.Price))
+		if product.Description != "" {
+			builder.WriteString(fmt.Sprintf("\n   %s", product.Description))
+		}
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("\nReply with a number (1-%d) to choose", len(products)))
+	if end < len(products) {
+		builder.WriteString(", or reply *next* for more products")
+	}
+
+	return builder.String()
+}
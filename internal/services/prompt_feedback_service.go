@@ -0,0 +1,243 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PromptFeedbackService closes the loop between agents and the AI prompts:
+// every time an agent rewrites an AI-proposed message (approval queue or
+// takeover console) the diff is captured, recurring corrections are
+// aggregated per flow, and the service derives system-prompt amendments
+// from the patterns
+
+// promptFeedbackEditLimit caps how many recent edits feed the aggregation
+const promptFeedbackEditLimit = 500
+
+// promptFeedbackMinOccurrences is the minimum number of edits showing the
+// same pattern before it is reported as recurring
+const promptFeedbackMinOccurrences = 3
+
+// promptFeedbackWordLimit caps the frequent-word lists in the report
+const promptFeedbackWordLimit = 10
+
+// Correction pattern kinds derived from the length and wording of each edit
+const (
+	correctionKindShortened = "shortened"
+	correctionKindExpanded  = "expanded"
+	correctionKindReworded  = "reworded"
+)
+
+// PromptFeedbackService aggregates agent message edits into prompt guidance
+type PromptFeedbackService struct {
+	feedbackRepo repository.PromptFeedbackRepository
+}
+
+// NewPromptFeedbackService creates a new prompt feedback service
+func NewPromptFeedbackService(feedbackRepo repository.PromptFeedbackRepository) *PromptFeedbackService {
+	return &PromptFeedbackService{feedbackRepo: feedbackRepo}
+}
+
+// RecordEdit captures one diff between an AI-proposed message and the text
+// the agent actually sent. Edits with no real change are dropped
+func (s *PromptFeedbackService) RecordEdit(edit *models.RecordedEdit) error {
+	if edit.FlowID == "" {
+		return fmt.Errorf("flow_id is required")
+	}
+	if edit.Source != models.MessageEditSourceApproval && edit.Source != models.MessageEditSourceTakeover {
+		return fmt.Errorf("invalid edit source: %s", edit.Source)
+	}
+	if strings.TrimSpace(edit.Edited) == "" || edit.Edited == edit.Original {
+		return nil
+	}
+
+	if err := s.feedbackRepo.InsertEdit(edit); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id": edit.FlowID,
+		"source":  edit.Source,
+	}).Info("📝 FEEDBACK: Agent edit captured")
+
+	return nil
+}
+
+// Report aggregates the flow's captured edits into recurring correction
+// patterns and frequently added/removed words
+func (s *PromptFeedbackService) Report(flowID string) (*models.PromptFeedbackReport, error) {
+	edits, err := s.feedbackRepo.GetEditsByFlow(flowID, promptFeedbackEditLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PromptFeedbackReport{
+		FlowID:      flowID,
+		TotalEdits:  len(edits),
+		BySource:    make(map[string]int),
+		GeneratedAt: time.Now(),
+	}
+
+	kindCounts := make(map[string]int)
+	kindExamples := make(map[string]string)
+	additionCounts := make(map[string]int)
+	removalCounts := make(map[string]int)
+
+	for _, edit := range edits {
+		report.BySource[edit.Source]++
+
+		kind := classifyEdit(edit.Original, edit.Edited)
+		kindCounts[kind]++
+		if kindExamples[kind] == "" {
+			kindExamples[kind] = edit.Edited
+		}
+
+		originalWords := editWordSet(edit.Original)
+		editedWords := editWordSet(edit.Edited)
+		for word := range editedWords {
+			if !originalWords[word] {
+				additionCounts[word]++
+			}
+		}
+		for word := range originalWords {
+			if !editedWords[word] {
+				removalCounts[word]++
+			}
+		}
+	}
+
+	report.Patterns = make([]models.CorrectionPattern, 0, len(kindCounts))
+	for kind, count := range kindCounts {
+		report.Patterns = append(report.Patterns, models.CorrectionPattern{
+			Kind:    kind,
+			Count:   count,
+			Example: kindExamples[kind],
+		})
+	}
+	sort.Slice(report.Patterns, func(i, j int) bool {
+		return report.Patterns[i].Count > report.Patterns[j].Count
+	})
+
+	report.FrequentAdditions = topWords(additionCounts)
+	report.FrequentRemovals = topWords(removalCounts)
+
+	return report, nil
+}
+
+// Suggest derives system-prompt amendments from the flow's recurring
+// corrections. An empty slice means not enough signal has accumulated yet
+func (s *PromptFeedbackService) Suggest(flowID string) ([]models.PromptSuggestion, error) {
+	report, err := s.Report(flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]models.PromptSuggestion, 0)
+
+	for _, pattern := range report.Patterns {
+		if pattern.Count < promptFeedbackMinOccurrences {
+			continue
+		}
+		switch pattern.Kind {
+		case correctionKindShortened:
+			suggestions = append(suggestions, models.PromptSuggestion{
+				Amendment:   "Keep responses short and to the point - avoid filler sentences.",
+				Reason:      fmt.Sprintf("Agents shortened %d of %d AI responses before sending", pattern.Count, report.TotalEdits),
+				Occurrences: pattern.Count,
+			})
+		case correctionKindExpanded:
+			suggestions = append(suggestions, models.PromptSuggestion{
+				Amendment:   "Give more complete answers - include the details the prospect needs instead of one-line replies.",
+				Reason:      fmt.Sprintf("Agents expanded %d of %d AI responses before sending", pattern.Count, report.TotalEdits),
+				Occurrences: pattern.Count,
+			})
+		}
+	}
+
+	if words := recurringWords(report.FrequentAdditions); len(words) > 0 {
+		suggestions = append(suggestions, models.PromptSuggestion{
+			Amendment:   fmt.Sprintf("Use wording like: %s.", strings.Join(words, ", ")),
+			Reason:      "Agents repeatedly add these words when rewriting AI responses",
+			Occurrences: report.FrequentAdditions[0].Count,
+		})
+	}
+	if words := recurringWords(report.FrequentRemovals); len(words) > 0 {
+		suggestions = append(suggestions, models.PromptSuggestion{
+			Amendment:   fmt.Sprintf("Avoid wording like: %s.", strings.Join(words, ", ")),
+			Reason:      "Agents repeatedly remove these words when rewriting AI responses",
+			Occurrences: report.FrequentRemovals[0].Count,
+		})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id":     flowID,
+		"total_edits": report.TotalEdits,
+		"suggestions": len(suggestions),
+	}).Debug("📝 FEEDBACK: Prompt suggestions generated")
+
+	return suggestions, nil
+}
+
+// classifyEdit buckets an edit by how the agent changed the message length
+func classifyEdit(original, edited string) string {
+	if len(original) == 0 {
+		return correctionKindReworded
+	}
+	ratio := float64(len(edited)) / float64(len(original))
+	if ratio <= 0.7 {
+		return correctionKindShortened
+	}
+	if ratio >= 1.3 {
+		return correctionKindExpanded
+	}
+	return correctionKindReworded
+}
+
+// editWordSet tokenizes a message into lowercase words long enough to carry
+// meaning (short stopwords are skipped)
+func editWordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		word := strings.Trim(field, ".,!?;:\"'()[]{}*_~")
+		if len([]rune(word)) > 3 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// topWords returns the most frequent recurring words, highest count first
+func topWords(counts map[string]int) []models.WordFrequency {
+	frequencies := make([]models.WordFrequency, 0, len(counts))
+	for word, count := range counts {
+		if count >= promptFeedbackMinOccurrences {
+			frequencies = append(frequencies, models.WordFrequency{Word: word, Count: count})
+		}
+	}
+	sort.Slice(frequencies, func(i, j int) bool {
+		if frequencies[i].Count != frequencies[j].Count {
+			return frequencies[i].Count > frequencies[j].Count
+		}
+		return frequencies[i].Word < frequencies[j].Word
+	})
+	if len(frequencies) > promptFeedbackWordLimit {
+		frequencies = frequencies[:promptFeedbackWordLimit]
+	}
+	return frequencies
+}
+
+// recurringWords extracts just the words from a frequency list
+func recurringWords(frequencies []models.WordFrequency) []string {
+	words := make([]string, 0, len(frequencies))
+	for _, frequency := range frequencies {
+		words = append(words, frequency.Word)
+	}
+	return words
+}
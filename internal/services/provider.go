@@ -0,0 +1,98 @@
+package services
+
+import (
+	"strings"
+	"sync"
+
+	"nodepath-chat/internal/models"
+)
+
+// Messaging channels a provider may serve. Flows are channel-agnostic; the
+// channel mainly decides what the prospect identifier is (phone number for
+// WhatsApp, chat ID for Telegram) and which operator conventions apply
+const (
+	ChannelWhatsApp = "whatsapp"
+	ChannelTelegram = "telegram"
+)
+
+// Provider is the plugin interface for message gateways. Each provider wraps
+// one external API (Wablas, Whacenter, WAHA, ...) and is looked up from the
+// registry by the lowercase provider name stored in device_setting, so new
+// gateways - including non-WhatsApp channels - can be added by registering
+// another implementation without touching handlers or whatsapp.Service
+type Provider interface {
+	// Name returns the lowercase provider name used in device settings
+	Name() string
+	// Channel returns the messaging channel the provider serves (one of the
+	// Channel* constants)
+	Channel() string
+	// SendText sends a plain text message to the prospect
+	SendText(deviceSettings *models.DeviceSettings, phoneNumber, message string) error
+	// SendMedia sends a media message (image/video/audio) by URL
+	SendMedia(deviceSettings *models.DeviceSettings, phoneNumber, mediaURL string) error
+	// GetStatus returns the device connection status in the shape the
+	// device status endpoint has always served
+	GetStatus(deviceSettings *models.DeviceSettings) map[string]interface{}
+	// GetQR returns the pairing QR code (data URL or provider URL),
+	// empty when unavailable
+	GetQR(deviceSettings *models.DeviceSettings) string
+	// CreateDevice provisions a device/session on the provider side and
+	// returns the raw API response for the caller to interpret
+	CreateDevice(idDevice, phoneNumber string) (map[string]interface{}, error)
+	// DeleteDevice removes the provider-side device/session referenced by
+	// the device's instance value
+	DeleteDevice(deviceSettings *models.DeviceSettings) error
+}
+
+var (
+	providerRegistryMutex sync.RWMutex
+	providerRegistry      = make(map[string]Provider)
+)
+
+// RegisterProvider adds a provider implementation to the registry. Later
+// registrations with the same name replace earlier ones
+func RegisterProvider(provider Provider) {
+	providerRegistryMutex.Lock()
+	defer providerRegistryMutex.Unlock()
+	providerRegistry[provider.Name()] = provider
+}
+
+// GetProvider looks up a registered provider by its lowercase name
+func GetProvider(name string) (Provider, bool) {
+	providerRegistryMutex.RLock()
+	defer providerRegistryMutex.RUnlock()
+	provider, ok := providerRegistry[name]
+	return provider, ok
+}
+
+// RegisteredProviders returns the names of all registered providers
+func RegisteredProviders() []string {
+	providerRegistryMutex.RLock()
+	defer providerRegistryMutex.RUnlock()
+
+	names := make([]string, 0, len(providerRegistry))
+	for name := range providerRegistry {
+		names = append(names, name)
+	}
+	return names
+}
+
+// The built-in gateways register themselves here so every consumer of the
+// registry sees the same set without extra wiring
+func init() {
+	RegisterProvider(newWablasProvider())
+	RegisterProvider(newWhacenterProvider())
+	RegisterProvider(newWahaProvider())
+	RegisterProvider(newTwilioProvider())
+	RegisterProvider(newMetaProvider())
+	RegisterProvider(newTelegramProvider())
+}
+
+// ChannelForProvider returns the channel a provider name serves, defaulting
+// to WhatsApp for unregistered names so legacy webhook paths keep working
+func ChannelForProvider(name string) string {
+	if provider, ok := GetProvider(strings.ToLower(name)); ok {
+		return provider.Channel()
+	}
+	return ChannelWhatsApp
+}
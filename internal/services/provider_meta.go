@@ -0,0 +1,488 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const metaGraphAPIBaseURL = "https://graph.facebook.com/v19.0"
+
+// metaProvider implements the Provider interface for Meta's official WhatsApp
+// Cloud API. The device's instance column stores the credentials as
+// "PHONE_NUMBER_ID:PERMANENT_TOKEN" with an optional ":APP_SECRET" third
+// segment used to verify webhook signatures. Numbers are registered in the
+// Meta Business Manager, so there is no QR pairing
+type metaProvider struct {
+	httpClient *http.Client
+}
+
+func newMetaProvider() *metaProvider {
+	return &metaProvider{
+		httpClient: newProviderHTTPClient(30 * time.Second),
+	}
+}
+
+// Name returns the provider name stored in device settings
+func (p *metaProvider) Name() string {
+	return "meta"
+}
+
+// Channel returns the messaging channel this provider serves
+func (p *metaProvider) Channel() string {
+	return ChannelWhatsApp
+}
+
+// SendText sends a text message via the Cloud API messages endpoint
+func (p *metaProvider) SendText(deviceSettings *models.DeviceSettings, phoneNumber, message string) error {
+	// Prevent sending empty or whitespace-only messages to avoid <nil> messages
+	if message == "" || strings.TrimSpace(message) == "" {
+		logrus.WithFields(logrus.Fields{
+			"phone_number": phoneNumber,
+			"device_id":    deviceSettings.IDDevice.String,
+		}).Warn("[META] Skipping empty message to prevent <nil> message")
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                phoneNumber,
+		"type":              "text",
+		"text": map[string]interface{}{
+			"body": message,
+		},
+	}
+
+	return p.sendMessage(deviceSettings, phoneNumber, payload)
+}
+
+// SendMedia sends a media message by URL via the Cloud API messages endpoint
+func (p *metaProvider) SendMedia(deviceSettings *models.DeviceSettings, phoneNumber, mediaURL string) error {
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                phoneNumber,
+		"type":              "image",
+		"image": map[string]interface{}{
+			"link": mediaURL,
+		},
+	}
+
+	return p.sendMessage(deviceSettings, phoneNumber, payload)
+}
+
+// SendTemplate sends a pre-approved template message. Parameters fill the
+// template body's {{n}} placeholders in order; languageCode defaults to "en"
+func (p *metaProvider) SendTemplate(deviceSettings *models.DeviceSettings, phoneNumber, templateName, languageCode string, parameters []string) error {
+	if templateName == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if languageCode == "" {
+		languageCode = "en"
+	}
+
+	template := map[string]interface{}{
+		"name": templateName,
+		"language": map[string]interface{}{
+			"code": languageCode,
+		},
+	}
+
+	if len(parameters) > 0 {
+		bodyParameters := make([]map[string]interface{}, 0, len(parameters))
+		for _, parameter := range parameters {
+			bodyParameters = append(bodyParameters, map[string]interface{}{
+				"type": "text",
+				"text": parameter,
+			})
+		}
+		template["components"] = []map[string]interface{}{
+			{
+				"type":       "body",
+				"parameters": bodyParameters,
+			},
+		}
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                phoneNumber,
+		"type":              "template",
+		"template":          template,
+	}
+
+	return p.sendMessage(deviceSettings, phoneNumber, payload)
+}
+
+// sendMessage posts one message payload to the Cloud API messages endpoint
+func (p *metaProvider) sendMessage(deviceSettings *models.DeviceSettings, phoneNumber string, payload map[string]interface{}) error {
+	phoneNumberID, token, _, err := metaCredentials(deviceSettings)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	apiURL := fmt.Sprintf("%s/%s/messages", metaGraphAPIBaseURL, phoneNumberID)
+
+	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	startTime := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	duration := time.Since(startTime)
+	logrus.WithFields(logrus.Fields{
+		"status_code":  resp.StatusCode,
+		"response":     string(body),
+		"duration":     duration,
+		"phone_number": phoneNumber,
+	}).Debug("[META] Response received")
+
+	// Check for success (200-299 status codes)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("meta API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"phone_number": phoneNumber,
+		"duration":     duration,
+		"device_id":    deviceSettings.IDDevice.String,
+	}).Info("[META] ✅ Message sent successfully")
+
+	return nil
+}
+
+// UploadMedia downloads the file at fileURL and uploads it to the Cloud API
+// media endpoint, returning the media ID for use in later messages
+func (p *metaProvider) UploadMedia(deviceSettings *models.DeviceSettings, fileURL string) (string, error) {
+	phoneNumberID, token, _, err := metaCredentials(deviceSettings)
+	if err != nil {
+		return "", err
+	}
+
+	fileResp, err := p.httpClient.Get(fileURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch media file: %w", err)
+	}
+	defer fileResp.Body.Close()
+
+	if fileResp.StatusCode != 200 {
+		return "", fmt.Errorf("media file fetch returned status %d", fileResp.StatusCode)
+	}
+
+	contentType := fileResp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+	if err := writer.WriteField("messaging_product", "whatsapp"); err != nil {
+		return "", fmt.Errorf("failed to build upload form: %w", err)
+	}
+	if err := writer.WriteField("type", contentType); err != nil {
+		return "", fmt.Errorf("failed to build upload form: %w", err)
+	}
+	part, err := writer.CreateFormFile("file", "media")
+	if err != nil {
+		return "", fmt.Errorf("failed to build upload form: %w", err)
+	}
+	if _, err := io.Copy(part, fileResp.Body); err != nil {
+		return "", fmt.Errorf("failed to copy media into upload form: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize upload form: %w", err)
+	}
+
+	apiURL := fmt.Sprintf("%s/%s/media", metaGraphAPIBaseURL, phoneNumberID)
+	req, err := http.NewRequest("POST", apiURL, &buffer)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload media: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("meta media upload error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var uploadResponse struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &uploadResponse); err != nil {
+		return "", fmt.Errorf("failed to parse upload response: %w", err)
+	}
+	if uploadResponse.ID == "" {
+		return "", fmt.Errorf("meta media upload returned no id: %s", string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"media_id":  uploadResponse.ID,
+		"device_id": deviceSettings.IDDevice.String,
+	}).Info("[META] ✅ Media uploaded successfully")
+
+	return uploadResponse.ID, nil
+}
+
+// DownloadMedia resolves a webhook media ID to its short-lived URL and
+// downloads the content, returning the bytes and content type
+func (p *metaProvider) DownloadMedia(deviceSettings *models.DeviceSettings, mediaID string) ([]byte, string, error) {
+	_, token, _, err := metaCredentials(deviceSettings)
+	if err != nil {
+		return nil, "", err
+	}
+
+	lookupURL := fmt.Sprintf("%s/%s", metaGraphAPIBaseURL, mediaID)
+	lookupReq, err := http.NewRequest("GET", lookupURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create media lookup request: %w", err)
+	}
+	lookupReq.Header.Set("Authorization", "Bearer "+token)
+
+	lookupResp, err := p.httpClient.Do(lookupReq)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to look up media: %w", err)
+	}
+	defer lookupResp.Body.Close()
+
+	lookupBody, err := io.ReadAll(lookupResp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read media lookup response: %w", err)
+	}
+	if lookupResp.StatusCode != 200 {
+		return nil, "", fmt.Errorf("meta media lookup error: status %d, body: %s", lookupResp.StatusCode, string(lookupBody))
+	}
+
+	var lookupResponse struct {
+		URL      string `json:"url"`
+		MimeType string `json:"mime_type"`
+	}
+	if err := json.Unmarshal(lookupBody, &lookupResponse); err != nil {
+		return nil, "", fmt.Errorf("failed to parse media lookup response: %w", err)
+	}
+	if lookupResponse.URL == "" {
+		return nil, "", fmt.Errorf("meta media lookup returned no url: %s", string(lookupBody))
+	}
+
+	// The lookup URL is short-lived and requires the same bearer token
+	downloadReq, err := http.NewRequest("GET", lookupResponse.URL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create media download request: %w", err)
+	}
+	downloadReq.Header.Set("Authorization", "Bearer "+token)
+
+	downloadResp, err := p.httpClient.Do(downloadReq)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download media: %w", err)
+	}
+	defer downloadResp.Body.Close()
+
+	if downloadResp.StatusCode != 200 {
+		return nil, "", fmt.Errorf("meta media download returned status %d", downloadResp.StatusCode)
+	}
+
+	content, err := io.ReadAll(downloadResp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read media content: %w", err)
+	}
+
+	contentType := lookupResponse.MimeType
+	if contentType == "" {
+		contentType = downloadResp.Header.Get("Content-Type")
+	}
+
+	return content, contentType, nil
+}
+
+// GetStatus checks the credentials by fetching the phone number resource.
+// Cloud API numbers have no session, so valid credentials mean the device can
+// send
+func (p *metaProvider) GetStatus(device *models.DeviceSettings) map[string]interface{} {
+	status := map[string]interface{}{
+		"device_id":    device.ID,
+		"provider":     "meta",
+		"connected":    false,
+		"status":       "disconnected",
+		"last_checked": time.Now(),
+		"details":      map[string]interface{}{},
+	}
+
+	phoneNumberID, token, _, err := metaCredentials(device)
+	if err != nil {
+		status["status"] = "not_configured"
+		status["details"] = map[string]interface{}{
+			"error": err.Error(),
+		}
+		return status
+	}
+
+	apiURL := fmt.Sprintf("%s/%s?fields=display_phone_number,verified_name,quality_rating", metaGraphAPIBaseURL, phoneNumberID)
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to create status request",
+			"details": err.Error(),
+		}
+		return status
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).Error("[META] HTTP request failed")
+		status["status"] = "connection_error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to connect to Meta Graph API",
+			"details": err.Error(),
+		}
+		return status
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to read API response",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		status["status"] = "invalid_credentials"
+		status["details"] = map[string]interface{}{
+			"error":       "Meta rejected the phone number ID / access token",
+			"http_status": resp.StatusCode,
+		}
+		return status
+	}
+
+	if resp.StatusCode != 200 {
+		status["status"] = "api_error"
+		status["details"] = map[string]interface{}{
+			"http_status":   resp.StatusCode,
+			"error":         "API returned error status",
+			"response_body": string(bodyBytes),
+		}
+		return status
+	}
+
+	var numberData map[string]interface{}
+	if err := json.Unmarshal(bodyBytes, &numberData); err == nil {
+		status["details"] = numberData
+		if qualityRating, ok := numberData["quality_rating"].(string); ok {
+			status["device_status"] = qualityRating
+		}
+	}
+
+	status["connected"] = true
+	status["status"] = "connected"
+
+	logrus.WithField("final_status", status).Info("[META] Returning status")
+	return status
+}
+
+// GetQR returns empty - the official Cloud API has no QR pairing
+func (p *metaProvider) GetQR(device *models.DeviceSettings) string {
+	return ""
+}
+
+// CreateDevice is not supported through the generic interface - use the
+// generate-meta endpoint which takes the phone number ID and permanent token
+func (p *metaProvider) CreateDevice(idDevice, phoneNumber string) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("meta devices require a phone number ID and permanent token - use the generate-meta device endpoint")
+}
+
+// DeleteDevice is a no-op - the number remains registered in the Meta
+// Business Manager
+func (p *metaProvider) DeleteDevice(device *models.DeviceSettings) error {
+	logrus.WithField("id_device", device.IDDevice.String).Info("[META] Nothing to delete - number is managed in the Meta Business Manager")
+	return nil
+}
+
+// metaCredentials splits the instance value into phone number ID, permanent
+// token and optional app secret
+func metaCredentials(device *models.DeviceSettings) (string, string, string, error) {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return "", "", "", fmt.Errorf("no instance found for Meta device %s", device.IDDevice.String)
+	}
+
+	parts := strings.SplitN(device.Instance.String, ":", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("meta instance must be PHONE_NUMBER_ID:PERMANENT_TOKEN[:APP_SECRET] for device %s", device.IDDevice.String)
+	}
+
+	appSecret := ""
+	if len(parts) == 3 {
+		appSecret = parts[2]
+	}
+
+	return parts[0], parts[1], appSecret, nil
+}
+
+// MetaAppSecret returns the app secret configured for a Meta device, empty
+// when the instance value carries none
+func MetaAppSecret(device *models.DeviceSettings) string {
+	_, _, appSecret, err := metaCredentials(device)
+	if err != nil {
+		return ""
+	}
+	return appSecret
+}
+
+// MetaVerifySignature checks a webhook body against the X-Hub-Signature-256
+// header using the app secret (HMAC-SHA256, "sha256=<hex>" format)
+func MetaVerifySignature(appSecret string, body []byte, signatureHeader string) bool {
+	if appSecret == "" || signatureHeader == "" {
+		return false
+	}
+
+	expected := strings.TrimPrefix(signatureHeader, "sha256=")
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write(body)
+	computed := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(computed), []byte(expected))
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FormatQuickReplyList renders quick-reply options under the message as a
+// numbered list - the fallback for providers without button support and the
+// text stored in conversation history
+func FormatQuickReplyList(message string, options []string) string {
+	var builder strings.Builder
+	builder.WriteString(message)
+	for i, option := range options {
+		builder.WriteString(fmt.Sprintf("\n%d. %s", i+1, option))
+	}
+	return builder.String()
+}
+
+// SendQuickReplyMessage sends a text message with quick-reply suggestions.
+// WAHA renders them as interactive reply buttons; Wablas and Whacenter have
+// no button endpoint, so the options are appended as a numbered list
+func (ps *ProviderService) SendQuickReplyMessage(deviceSettings *models.DeviceSettings, phoneNumber, message string, options []string) error {
+	if deviceSettings == nil {
+		return fmt.Errorf("device settings cannot be nil")
+	}
+	if len(options) == 0 {
+		return ps.SendMessage(deviceSettings, phoneNumber, message)
+	}
+
+	provider, err := resolveProvider(deviceSettings)
+	if err != nil {
+		return err
+	}
+	if err := ps.checkCredentialHealth(deviceSettings); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"provider":     provider.Name(),
+		"device_id":    deviceSettings.Instance.String,
+		"phone_number": phoneNumber,
+		"options":      len(options),
+	}).Info("📤 MESSAGE: Sending quick-reply message through provider")
+
+	if provider.Name() == "waha" {
+		err = ps.sendWahaButtonsMessage(deviceSettings, phoneNumber, message, options)
+	} else {
+		err = provider.SendText(deviceSettings, phoneNumber, FormatQuickReplyList(message, options))
+	}
+	if err != nil {
+		Metrics().IncCounter(MetricProviderSendFailures, map[string]string{"provider": provider.Name()})
+		return err
+	}
+	ps.recordTurnCompleted(deviceSettings, phoneNumber)
+	return nil
+}
+
+// sendWahaButtonsMessage sends a text message with reply buttons via the WAHA
+// sendButtons endpoint. When the endpoint rejects the request (not every WAHA
+// engine supports buttons) the message falls back to the numbered-list text
+func (ps *ProviderService) sendWahaButtonsMessage(deviceSettings *models.DeviceSettings, phoneNumber, message string, options []string) error {
+	// Hardcoded API key for WAHA provider
+	apiKey := "dckr_pat_vxeqEu_CqRi5O3CBHnD7FxhnBz0"
+
+	// Get instance for session
+	instance := ""
+	if deviceSettings.Instance.Valid {
+		instance = deviceSettings.Instance.String
+	} else {
+		return fmt.Errorf("no instance found for WAHA device %s", deviceSettings.Instance.String)
+	}
+
+	// Format phone number for WAHA (international format without + and add @c.us)
+	chatId := phoneNumber
+	if !strings.HasSuffix(chatId, "@c.us") {
+		chatId = strings.TrimPrefix(chatId, "+") + "@c.us"
+	}
+
+	buttons := make([]map[string]interface{}, 0, len(options))
+	for _, option := range options {
+		buttons = append(buttons, map[string]interface{}{
+			"type": "reply",
+			"text": option,
+		})
+	}
+
+	payload := map[string]interface{}{
+		"session": instance,
+		"chatId":  chatId,
+		"body":    message,
+		"buttons": buttons,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	apiURL := "https://waha-plus-production-705f.up.railway.app/api/sendButtons"
+
+	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Api-Key", apiKey)
+
+	resp, err := ps.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logrus.WithFields(logrus.Fields{
+			"status_code":  resp.StatusCode,
+			"response":     string(body),
+			"instance":     instance,
+			"phone_number": phoneNumber,
+		}).Warn("[WAHA-BUTTONS] Button send rejected, falling back to numbered list")
+		return ps.SendMessage(deviceSettings, phoneNumber, FormatQuickReplyList(message, options))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"phone_number": phoneNumber,
+		"device_id":    deviceSettings.Instance.String,
+		"buttons":      len(buttons),
+	}).Info("[WAHA-BUTTONS] ✅ Quick-reply message sent successfully")
+
+	return nil
+}
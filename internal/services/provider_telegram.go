@@ -0,0 +1,233 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const telegramAPIBaseURL = "https://api.telegram.org"
+
+// telegramProvider implements the Provider interface for Telegram bots. The
+// device's instance column stores the bot token from BotFather and the
+// prospect identifier is the Telegram chat ID instead of a phone number, so
+// the same flows run unchanged over Telegram
+type telegramProvider struct {
+	httpClient *http.Client
+}
+
+func newTelegramProvider() *telegramProvider {
+	return &telegramProvider{
+		httpClient: newProviderHTTPClient(30 * time.Second),
+	}
+}
+
+// Name returns the provider name stored in device settings
+func (p *telegramProvider) Name() string {
+	return "telegram"
+}
+
+// Channel returns the messaging channel this provider serves
+func (p *telegramProvider) Channel() string {
+	return ChannelTelegram
+}
+
+// SendText sends a text message via the Telegram sendMessage method. The
+// phoneNumber parameter carries the Telegram chat ID
+func (p *telegramProvider) SendText(deviceSettings *models.DeviceSettings, phoneNumber, message string) error {
+	// Prevent sending empty or whitespace-only messages to avoid <nil> messages
+	if message == "" || strings.TrimSpace(message) == "" {
+		logrus.WithFields(logrus.Fields{
+			"chat_id":   phoneNumber,
+			"device_id": deviceSettings.IDDevice.String,
+		}).Warn("[TELEGRAM] Skipping empty message to prevent <nil> message")
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"chat_id": phoneNumber,
+		"text":    message,
+	}
+
+	return p.callMethod(deviceSettings, "sendMessage", payload)
+}
+
+// SendMedia sends a media message by URL via the Telegram sendPhoto method
+func (p *telegramProvider) SendMedia(deviceSettings *models.DeviceSettings, phoneNumber, mediaURL string) error {
+	payload := map[string]interface{}{
+		"chat_id": phoneNumber,
+		"photo":   mediaURL,
+	}
+
+	return p.callMethod(deviceSettings, "sendPhoto", payload)
+}
+
+// callMethod posts one request to a Telegram bot API method
+func (p *telegramProvider) callMethod(deviceSettings *models.DeviceSettings, method string, payload map[string]interface{}) error {
+	token, err := telegramToken(deviceSettings)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	apiURL := fmt.Sprintf("%s/bot%s/%s", telegramAPIBaseURL, token, method)
+
+	startTime := time.Now()
+	resp, err := p.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	duration := time.Since(startTime)
+	logrus.WithFields(logrus.Fields{
+		"method":      method,
+		"status_code": resp.StatusCode,
+		"response":    string(body),
+		"duration":    duration,
+	}).Debug("[TELEGRAM] Response received")
+
+	// Telegram reports failures in the ok field alongside the HTTP status
+	var apiResponse struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(body, &apiResponse); err == nil && !apiResponse.OK {
+		return fmt.Errorf("telegram API error: %s", apiResponse.Description)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telegram API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"method":    method,
+		"duration":  duration,
+		"device_id": deviceSettings.IDDevice.String,
+	}).Info("[TELEGRAM] ✅ Message sent successfully")
+
+	return nil
+}
+
+// GetStatus checks the bot token via the getMe method. Telegram bots have no
+// session, so a valid token means the device can send
+func (p *telegramProvider) GetStatus(device *models.DeviceSettings) map[string]interface{} {
+	status := map[string]interface{}{
+		"device_id":    device.ID,
+		"provider":     "telegram",
+		"connected":    false,
+		"status":       "disconnected",
+		"last_checked": time.Now(),
+		"details":      map[string]interface{}{},
+	}
+
+	token, err := telegramToken(device)
+	if err != nil {
+		status["status"] = "not_configured"
+		status["details"] = map[string]interface{}{
+			"error": err.Error(),
+		}
+		return status
+	}
+
+	apiURL := fmt.Sprintf("%s/bot%s/getMe", telegramAPIBaseURL, token)
+
+	resp, err := p.httpClient.Get(apiURL)
+	if err != nil {
+		logrus.WithError(err).Error("[TELEGRAM] HTTP request failed")
+		status["status"] = "connection_error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to connect to Telegram API",
+			"details": err.Error(),
+		}
+		return status
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to read API response",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	var apiResponse struct {
+		OK          bool                   `json:"ok"`
+		Description string                 `json:"description"`
+		Result      map[string]interface{} `json:"result"`
+	}
+	if err := json.Unmarshal(bodyBytes, &apiResponse); err != nil || !apiResponse.OK {
+		status["status"] = "invalid_credentials"
+		status["details"] = map[string]interface{}{
+			"error":         "Telegram rejected the bot token",
+			"http_status":   resp.StatusCode,
+			"response_body": string(bodyBytes),
+		}
+		return status
+	}
+
+	status["connected"] = true
+	status["status"] = "connected"
+	status["details"] = apiResponse.Result
+	if username, ok := apiResponse.Result["username"].(string); ok {
+		status["device_status"] = "@" + username
+	}
+
+	logrus.WithField("final_status", status).Info("[TELEGRAM] Returning status")
+	return status
+}
+
+// GetQR returns empty - Telegram bots pair via token, not QR
+func (p *telegramProvider) GetQR(device *models.DeviceSettings) string {
+	return ""
+}
+
+// CreateDevice is not supported through the generic interface - bots are
+// created with BotFather and registered via the generate-telegram endpoint
+func (p *telegramProvider) CreateDevice(idDevice, phoneNumber string) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("telegram bots are created with BotFather - use the generate-telegram device endpoint with the bot token")
+}
+
+// DeleteDevice removes the bot's webhook so Telegram stops delivering updates
+func (p *telegramProvider) DeleteDevice(device *models.DeviceSettings) error {
+	if err := p.callMethod(device, "deleteWebhook", map[string]interface{}{}); err != nil {
+		logrus.WithError(err).WithField("id_device", device.IDDevice.String).Warn("[TELEGRAM] Failed to delete webhook")
+		return err
+	}
+	return nil
+}
+
+// SetWebhook points the bot's updates at the given URL
+func (p *telegramProvider) SetWebhook(device *models.DeviceSettings, webhookURL string) error {
+	return p.callMethod(device, "setWebhook", map[string]interface{}{
+		"url":             webhookURL,
+		"allowed_updates": []string{"message"},
+	})
+}
+
+// telegramToken returns the bot token stored in the device's instance column
+func telegramToken(device *models.DeviceSettings) (string, error) {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return "", fmt.Errorf("no bot token found for Telegram device %s", device.IDDevice.String)
+	}
+	return device.Instance.String, nil
+}
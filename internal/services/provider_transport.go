@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Shared outbound HTTP stack for provider calls. Every provider client used
+// to bring its own default transport, so under load each send could pay for
+// a fresh DNS lookup and TLS handshake. All providers now share one warm
+// transport with keep-alive pooling and a short-TTL DNS cache; regional
+// gateway endpoints can be selected per provider through environment
+// overrides
+
+const (
+	// providerDNSCacheTTL is how long resolved provider IPs are reused
+	providerDNSCacheTTL = 5 * time.Minute
+
+	// Keep-alive pool sizing; providers talk to a handful of hosts, so a
+	// generous per-host pool keeps connections warm across bursts
+	providerMaxIdleConns        = 100
+	providerMaxIdleConnsPerHost = 20
+	providerIdleConnTimeout     = 90 * time.Second
+)
+
+// dnsCacheEntry is one cached resolution
+type dnsCacheEntry struct {
+	ips     []net.IP
+	expires time.Time
+}
+
+var (
+	providerDNSMu    sync.Mutex
+	providerDNSCache = make(map[string]dnsCacheEntry)
+)
+
+// providerTransport is the shared warm transport behind every provider
+// client. TLS still handshakes against the request's hostname, so the
+// cached-IP dialing below does not weaken certificate verification
+var providerTransport = &http.Transport{
+	DialContext:           dialWithDNSCache,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          providerMaxIdleConns,
+	MaxIdleConnsPerHost:   providerMaxIdleConnsPerHost,
+	IdleConnTimeout:       providerIdleConnTimeout,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+// newProviderHTTPClient returns a client on the shared warm transport
+func newProviderHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: providerTransport,
+	}
+}
+
+// dialWithDNSCache dials using cached DNS resolutions, trying each address
+// until one connects
+func dialWithDNSCache(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	// Literal IPs need no resolution
+	if net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	ips, err := cachedLookupIP(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no addresses resolved for %s", host)
+	}
+	return nil, lastErr
+}
+
+// cachedLookupIP resolves a host, serving from the cache within the TTL.
+// Expired entries are kept as a fallback when the resolver is down
+func cachedLookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	providerDNSMu.Lock()
+	entry, cached := providerDNSCache[host]
+	providerDNSMu.Unlock()
+
+	if cached && time.Now().Before(entry.expires) {
+		return entry.ips, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		if cached {
+			logrus.WithError(err).WithField("host", host).Warn("🌐 DNS: Lookup failed, serving stale cache entry")
+			return entry.ips, nil
+		}
+		return nil, err
+	}
+
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+
+	providerDNSMu.Lock()
+	providerDNSCache[host] = dnsCacheEntry{ips: ips, expires: time.Now().Add(providerDNSCacheTTL)}
+	providerDNSMu.Unlock()
+
+	return ips, nil
+}
+
+// providerBaseURL returns the provider's API base URL, honoring a regional
+// override from the environment (e.g. WABLAS_API_BASE_URL)
+func providerBaseURL(envKey, fallback string) string {
+	value := strings.TrimSpace(os.Getenv(envKey))
+	if value == "" {
+		return fallback
+	}
+	value = strings.TrimRight(value, "/")
+	logrus.WithFields(logrus.Fields{
+		"env_key":  envKey,
+		"endpoint": value,
+	}).Info("🌍 PROVIDER: Using regional endpoint override")
+	return value
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const twilioAPIBaseURL = "https://api.twilio.com/2010-04-01"
+
+// twilioProvider implements the Provider interface for Twilio's official
+// WhatsApp Business API. The device's instance column stores the credentials
+// as "ACCOUNT_SID:AUTH_TOKEN" and the device's phone_number is the approved
+// WhatsApp sender number. Devices are provisioned in the Twilio console, so
+// there is no QR pairing or device creation API
+type twilioProvider struct {
+	httpClient *http.Client
+}
+
+func newTwilioProvider() *twilioProvider {
+	return &twilioProvider{
+		httpClient: newProviderHTTPClient(30 * time.Second),
+	}
+}
+
+// Name returns the provider name stored in device settings
+func (p *twilioProvider) Name() string {
+	return "twilio"
+}
+
+// Channel returns the messaging channel this provider serves
+func (p *twilioProvider) Channel() string {
+	return ChannelWhatsApp
+}
+
+// SendText sends a text message via the Twilio Messages API
+func (p *twilioProvider) SendText(deviceSettings *models.DeviceSettings, phoneNumber, message string) error {
+	// Prevent sending empty or whitespace-only messages to avoid <nil> messages
+	if message == "" || strings.TrimSpace(message) == "" {
+		logrus.WithFields(logrus.Fields{
+			"phone_number": phoneNumber,
+			"device_id":    deviceSettings.Instance.String,
+		}).Warn("[TWILIO] Skipping empty message to prevent <nil> message")
+		return nil
+	}
+
+	data := url.Values{}
+	data.Set("Body", message)
+
+	return p.sendMessage(deviceSettings, phoneNumber, data)
+}
+
+// SendMedia sends a media message (image/video/audio) by URL via the Twilio
+// Messages API
+func (p *twilioProvider) SendMedia(deviceSettings *models.DeviceSettings, phoneNumber, mediaURL string) error {
+	data := url.Values{}
+	data.Set("MediaUrl", mediaURL)
+
+	return p.sendMessage(deviceSettings, phoneNumber, data)
+}
+
+// sendMessage posts one message to the Twilio Messages API with the From/To
+// pair filled in from the device settings
+func (p *twilioProvider) sendMessage(deviceSettings *models.DeviceSettings, phoneNumber string, data url.Values) error {
+	accountSID, authToken, err := twilioCredentials(deviceSettings)
+	if err != nil {
+		return err
+	}
+
+	if !deviceSettings.PhoneNumber.Valid || deviceSettings.PhoneNumber.String == "" {
+		return fmt.Errorf("no sender phone number configured for Twilio device %s", deviceSettings.IDDevice.String)
+	}
+
+	data.Set("From", twilioWhatsAppNumber(deviceSettings.PhoneNumber.String))
+	data.Set("To", twilioWhatsAppNumber(phoneNumber))
+
+	apiURL := fmt.Sprintf("%s/Accounts/%s/Messages.json", twilioAPIBaseURL, url.PathEscape(accountSID))
+
+	req, err := http.NewRequest("POST", apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.SetBasicAuth(accountSID, authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	startTime := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	duration := time.Since(startTime)
+	logrus.WithFields(logrus.Fields{
+		"status_code":  resp.StatusCode,
+		"response":     string(body),
+		"duration":     duration,
+		"phone_number": phoneNumber,
+	}).Debug("[TWILIO] Response received")
+
+	// Check for success (200-299 status codes)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("twilio API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"phone_number": phoneNumber,
+		"duration":     duration,
+		"device_id":    deviceSettings.IDDevice.String,
+	}).Info("[TWILIO] ✅ Message sent successfully")
+
+	return nil
+}
+
+// GetStatus checks the Twilio account credentials by fetching the account
+// resource. Twilio senders have no session, so valid credentials mean the
+// device can send
+func (p *twilioProvider) GetStatus(device *models.DeviceSettings) map[string]interface{} {
+	status := map[string]interface{}{
+		"device_id":    device.ID,
+		"provider":     "twilio",
+		"connected":    false,
+		"status":       "disconnected",
+		"last_checked": time.Now(),
+		"details":      map[string]interface{}{},
+	}
+
+	accountSID, authToken, err := twilioCredentials(device)
+	if err != nil {
+		status["status"] = "not_configured"
+		status["details"] = map[string]interface{}{
+			"error": err.Error(),
+		}
+		return status
+	}
+
+	apiURL := fmt.Sprintf("%s/Accounts/%s.json", twilioAPIBaseURL, url.PathEscape(accountSID))
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to create status request",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	req.SetBasicAuth(accountSID, authToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).Error("[TWILIO] HTTP request failed")
+		status["status"] = "connection_error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to connect to Twilio API",
+			"details": err.Error(),
+		}
+		return status
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to read API response",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	if resp.StatusCode == 401 {
+		status["status"] = "invalid_credentials"
+		status["details"] = map[string]interface{}{
+			"error":       "Twilio rejected the Account SID / Auth Token",
+			"http_status": 401,
+		}
+		return status
+	}
+
+	if resp.StatusCode != 200 {
+		status["status"] = "api_error"
+		status["details"] = map[string]interface{}{
+			"http_status":   resp.StatusCode,
+			"error":         "API returned error status",
+			"response_body": string(bodyBytes),
+		}
+		return status
+	}
+
+	var accountData map[string]interface{}
+	if err := json.Unmarshal(bodyBytes, &accountData); err == nil {
+		status["details"] = accountData
+		if accountStatus, ok := accountData["status"].(string); ok {
+			status["device_status"] = accountStatus
+		}
+	}
+
+	status["connected"] = true
+	status["status"] = "connected"
+
+	logrus.WithField("final_status", status).Info("[TWILIO] Returning status")
+	return status
+}
+
+// GetQR returns empty - the official Twilio API has no QR pairing
+func (p *twilioProvider) GetQR(device *models.DeviceSettings) string {
+	return ""
+}
+
+// CreateDevice is not supported - Twilio WhatsApp senders are provisioned in
+// the Twilio console
+func (p *twilioProvider) CreateDevice(idDevice, phoneNumber string) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("twilio devices are provisioned in the Twilio console - store the credentials as ACCOUNT_SID:AUTH_TOKEN in the device instance field")
+}
+
+// DeleteDevice is a no-op - the sender remains managed in the Twilio console
+func (p *twilioProvider) DeleteDevice(device *models.DeviceSettings) error {
+	logrus.WithField("id_device", device.IDDevice.String).Info("[TWILIO] Nothing to delete - sender is managed in the Twilio console")
+	return nil
+}
+
+// twilioCredentials splits the instance value into Account SID and Auth Token
+func twilioCredentials(device *models.DeviceSettings) (string, string, error) {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return "", "", fmt.Errorf("no instance found for Twilio device %s", device.IDDevice.String)
+	}
+
+	parts := strings.SplitN(device.Instance.String, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("twilio instance must be ACCOUNT_SID:AUTH_TOKEN for device %s", device.IDDevice.String)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// twilioWhatsAppNumber formats a phone number in Twilio's whatsapp:+E164 form
+func twilioWhatsAppNumber(phoneNumber string) string {
+	number := strings.NewReplacer("-", "", " ", "", "(", "", ")", "").Replace(phoneNumber)
+	number = strings.TrimPrefix(number, "whatsapp:")
+	if !strings.HasPrefix(number, "+") {
+		number = "+" + number
+	}
+	return "whatsapp:" + number
+}
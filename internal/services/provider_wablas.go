@@ -0,0 +1,529 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// wablasAPIBaseURL is the Wablas gateway endpoint; WABLAS_API_BASE_URL
+// selects a regional server (my/sby/solo etc.)
+var wablasAPIBaseURL = providerBaseURL("WABLAS_API_BASE_URL", "https://my.wablas.com")
+
+// Hardcoded token for provisioning new Wablas devices
+const wablasDefaultToken = "j0oB1aibqYDQlgyk9SIqLyfeGgRJjjmOUFMVqxGd8Irk6JCwl1ZxYtY.7hDkbW0f"
+
+// wablasProvider implements the Provider interface for the Wablas gateway
+type wablasProvider struct {
+	httpClient *http.Client
+}
+
+func newWablasProvider() *wablasProvider {
+	return &wablasProvider{
+		httpClient: newProviderHTTPClient(30 * time.Second),
+	}
+}
+
+// Name returns the provider name stored in device settings
+func (p *wablasProvider) Name() string {
+	return "wablas"
+}
+
+// Channel returns the messaging channel this provider serves
+func (p *wablasProvider) Channel() string {
+	return ChannelWhatsApp
+}
+
+// SendText sends a text message via Wablas API
+// Uses the exact API format specified by user requirements
+func (p *wablasProvider) SendText(deviceSettings *models.DeviceSettings, phoneNumber, message string) error {
+	// Prevent sending empty or whitespace-only messages to avoid <nil> messages
+	if message == "" || strings.TrimSpace(message) == "" {
+		logrus.WithFields(logrus.Fields{
+			"phone_number": phoneNumber,
+			"device_id":    deviceSettings.Instance.String,
+		}).Warn("[WABLAS-TEXT] Skipping empty message to prevent <nil> message")
+		return nil
+	}
+
+	apiURL := wablasAPIBaseURL + "/api/send-message"
+
+	logrus.WithFields(logrus.Fields{
+		"api_url":      apiURL,
+		"phone_number": phoneNumber,
+		"message_len":  len(message),
+		"device_id":    deviceSettings.Instance.String,
+	}).Debug("[WABLAS-TEXT] Preparing request")
+
+	// Get instance for authorization (as per user requirements)
+	instance := ""
+	if deviceSettings.Instance.Valid {
+		instance = deviceSettings.Instance.String
+	} else {
+		return fmt.Errorf("no instance found for Wablas device %s", deviceSettings.Instance.String)
+	}
+
+	// Prepare form data exactly as specified by user
+	data := url.Values{}
+	data.Set("phone", phoneNumber) // Recipient phone number
+	data.Set("message", message)   // Message content
+
+	// Create request
+	req, err := http.NewRequest("POST", apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers exactly as specified by user
+	req.Header.Set("Authorization", instance) // Set the Authorization header
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Send request
+	startTime := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	duration := time.Since(startTime)
+	logrus.WithFields(logrus.Fields{
+		"status_code": resp.StatusCode,
+		"response":    string(body),
+		"duration":    duration,
+		"instance":    instance,
+	}).Debug("[WABLAS-TEXT] Response received")
+
+	// Check for success (200-299 status codes)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("wablas API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"phone_number": phoneNumber,
+		"duration":     duration,
+		"device_id":    deviceSettings.Instance.String,
+	}).Info("[WABLAS-TEXT] ✅ Message sent successfully")
+
+	return nil
+}
+
+// SendMedia sends a media message via Wablas API with type detection
+// Handles video, audio, and image files with appropriate API endpoints
+func (p *wablasProvider) SendMedia(deviceSettings *models.DeviceSettings, phoneNumber, mediaURL string) error {
+	// Detect media type based on file extension
+	mediaType := ""
+	var apiURL string
+	var fieldName string
+
+	if strings.Contains(mediaURL, ".mp4") {
+		mediaType = "video"
+		apiURL = wablasAPIBaseURL + "/api/send-video"
+		fieldName = "video"
+	} else if strings.Contains(mediaURL, ".mp3") {
+		mediaType = "audio"
+		apiURL = wablasAPIBaseURL + "/api/send-audio"
+		fieldName = "audio"
+	} else {
+		// Default to image for all other file types
+		mediaType = "image"
+		apiURL = wablasAPIBaseURL + "/api/send-image"
+		fieldName = "image"
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"api_url":      apiURL,
+		"phone_number": phoneNumber,
+		"media_url":    mediaURL,
+		"media_type":   mediaType,
+		"device_id":    deviceSettings.Instance.String,
+	}).Debug("[WABLAS-MEDIA] Preparing request")
+
+	// Get instance for authorization (as per user requirements)
+	instance := ""
+	if deviceSettings.Instance.Valid {
+		instance = deviceSettings.Instance.String
+	} else {
+		return fmt.Errorf("no instance found for Wablas device %s", deviceSettings.Instance.String)
+	}
+
+	// Prepare form data with appropriate field name
+	data := url.Values{}
+	data.Set("phone", phoneNumber) // Recipient phone number
+	data.Set(fieldName, mediaURL)  // Media file URL with correct field name
+
+	// Create request
+	req, err := http.NewRequest("POST", apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers (using instance for authorization as per user requirements)
+	req.Header.Set("Authorization", instance)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Send request
+	startTime := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	duration := time.Since(startTime)
+	logrus.WithFields(logrus.Fields{
+		"status_code": resp.StatusCode,
+		"response":    string(body),
+		"duration":    duration,
+		"instance":    instance,
+		"media_type":  mediaType,
+	}).Debug("[WABLAS-MEDIA] Response received")
+
+	// Check for success (200-299 status codes)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("wablas API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"phone_number": phoneNumber,
+		"duration":     duration,
+		"device_id":    deviceSettings.Instance.String,
+		"media_type":   mediaType,
+	}).Info("[WABLAS-MEDIA] ✅ Media sent successfully")
+
+	return nil
+}
+
+// GetStatus checks the status of a Wablas device following the PHP pattern:
+// fetch device info, then expose the scan URL when disconnected
+func (p *wablasProvider) GetStatus(device *models.DeviceSettings) map[string]interface{} {
+	status := map[string]interface{}{
+		"device_id":    device.ID,
+		"provider":     "wablas",
+		"connected":    false,
+		"status":       "disconnected",
+		"last_checked": time.Now(),
+		"details":      map[string]interface{}{},
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"device_id":      device.ID,
+		"instance_valid": device.Instance.Valid,
+		"instance_value": device.Instance.String,
+	}).Info("[WABLAS] Starting Wablas status check")
+
+	// Check if instance (API key) is configured
+	if !device.Instance.Valid || device.Instance.String == "" {
+		logrus.Error("[WABLAS] Device instance not configured")
+		status["status"] = "NOT CONNECTED"
+		status["qr"] = "timeout"
+		status["details"] = map[string]interface{}{
+			"error": "Device instance not configured",
+		}
+		return status
+	}
+
+	// Extract token from instance - following PHP pattern: $token = explode('.', $auth_header)[0];
+	authHeader := device.Instance.String
+	token := wablasToken(authHeader)
+
+	// **STEP 1: CHECK DEVICE STATUS** - following PHP pattern
+	apiURL := fmt.Sprintf("%s/api/device/info?token=%s", wablasAPIBaseURL, url.QueryEscape(token))
+
+	logrus.WithFields(logrus.Fields{
+		"api_url":      wablasAPIBaseURL + "/api/device/info",
+		"token_prefix": token[:min(8, len(token))] + "...",
+	}).Info("[WABLAS] Making API request")
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		logrus.WithError(err).Error("[WABLAS] Failed to create HTTP request")
+		status["status"] = "NOT CONNECTED"
+		status["qr"] = "timeout"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to create status request",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	req.Header.Set("Authorization", authHeader)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).Error("[WABLAS] HTTP request failed")
+		status["status"] = "NOT CONNECTED"
+		status["qr"] = "timeout"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to connect to Wablas API",
+			"details": err.Error(),
+		}
+		return status
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logrus.WithError(err).Error("[WABLAS] Failed to read response body")
+		status["status"] = "NOT CONNECTED"
+		status["qr"] = "timeout"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to read API response",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":   resp.StatusCode,
+		"response_body": string(bodyBytes),
+	}).Info("[WABLAS] Received API response")
+
+	if resp.StatusCode != 200 {
+		logrus.WithFields(logrus.Fields{
+			"status_code":   resp.StatusCode,
+			"response_body": string(bodyBytes),
+		}).Error("[WABLAS] API returned non-200 status")
+
+		status["status"] = "NOT CONNECTED"
+		status["qr"] = "timeout"
+		status["details"] = map[string]interface{}{
+			"http_status":   resp.StatusCode,
+			"error":         "API returned error status",
+			"response_body": string(bodyBytes),
+		}
+		return status
+	}
+
+	// **Decode JSON Response** - following PHP pattern
+	var data map[string]interface{}
+	if err := json.Unmarshal(bodyBytes, &data); err != nil {
+		logrus.WithError(err).Error("[WABLAS] Failed to parse JSON response")
+		status["status"] = "NOT CONNECTED"
+		status["qr"] = "timeout"
+		status["details"] = map[string]interface{}{
+			"error":        "Failed to parse API response",
+			"raw_response": string(bodyBytes),
+			"parse_error":  err.Error(),
+		}
+		return status
+	}
+
+	// Check API response status - following PHP pattern
+	if apiStatus, ok := data["status"].(bool); !ok || !apiStatus {
+		logrus.Warn("[WABLAS] API response status is false or missing")
+		status["status"] = "NOT CONNECTED"
+		status["qr"] = "timeout"
+		status["details"] = data
+		return status
+	}
+
+	// **Extract Device Status** - following PHP pattern
+	var deviceStatus string = "UNKNOWN"
+	var deviceID string
+	var image interface{} = nil
+
+	if dataObj, ok := data["data"].(map[string]interface{}); ok {
+		if ds, ok := dataObj["status"].(string); ok {
+			deviceStatus = ds
+		}
+		if serial, ok := dataObj["serial"].(string); ok {
+			deviceID = serial
+		}
+	}
+
+	// **STEP 2: FETCH QR CODE IF NOT CONNECTED** - following PHP pattern
+	if deviceStatus == "disconnected" && deviceID != "" {
+		qrURL := fmt.Sprintf("%s/api/device/scan?token=%s", wablasAPIBaseURL, url.QueryEscape(token))
+		image = qrURL
+	}
+
+	// **Return Final Response** - following PHP pattern
+	status["status"] = deviceStatus
+	status["connected"] = deviceStatus == "connected"
+	if dataObj, ok := data["data"].(map[string]interface{}); ok {
+		status["data"] = dataObj
+	} else {
+		status["data"] = map[string]interface{}{}
+	}
+	if image != nil {
+		status["image"] = image
+		status["qr"] = image // Also set qr field for compatibility
+	} else {
+		status["image"] = nil
+		status["qr"] = nil
+	}
+	if message, ok := data["message"].(string); ok {
+		status["message"] = message
+	} else {
+		status["message"] = "No message returned"
+	}
+
+	logrus.WithField("final_status", status).Info("[WABLAS] Returning status")
+	return status
+}
+
+// GetQR fetches the QR code from the Wablas scan endpoint when the device is
+// disconnected
+func (p *wablasProvider) GetQR(device *models.DeviceSettings) string {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return ""
+	}
+
+	token := wablasToken(device.Instance.String)
+	qrURL := fmt.Sprintf("%s/api/device/scan?token=%s", wablasAPIBaseURL, url.QueryEscape(token))
+
+	logrus.WithField("qr_url", qrURL).Info("[WABLAS] Fetching QR code")
+
+	req, err := http.NewRequest("GET", qrURL, nil)
+	if err != nil {
+		logrus.WithError(err).Error("[WABLAS] Failed to create QR request")
+		return ""
+	}
+
+	req.Header.Set("Authorization", device.Instance.String)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).Error("[WABLAS] Failed to fetch QR code")
+		return ""
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logrus.WithError(err).Error("[WABLAS] Failed to read QR response body")
+		return ""
+	}
+
+	if resp.StatusCode == 200 {
+		var qrResponse map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &qrResponse); err == nil {
+			if data, ok := qrResponse["data"].(map[string]interface{}); ok {
+				if qrCode, ok := data["qr"].(string); ok {
+					logrus.WithField("qr_code_length", len(qrCode)).Info("[WABLAS] QR code fetched successfully")
+					return qrCode
+				}
+			}
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":   resp.StatusCode,
+		"response_body": string(bodyBytes),
+	}).Warn("[WABLAS] Failed to get QR code")
+
+	return ""
+}
+
+// CreateDevice provisions a new Wablas device using the default token and
+// returns the raw creation response
+func (p *wablasProvider) CreateDevice(idDevice, phoneNumber string) (map[string]interface{}, error) {
+	formData := url.Values{}
+	formData.Set("name", idDevice)
+	formData.Set("phone", phoneNumber)
+	formData.Set("bank", "BCA")
+	formData.Set("periode", "monthly")
+	formData.Set("product", "large")
+
+	req, err := http.NewRequest("POST", wablasAPIBaseURL+"/api/device/create", strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", wablasDefaultToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	logrus.WithFields(logrus.Fields{
+		"provider":     "wablas",
+		"device_name":  idDevice,
+		"phone_number": phoneNumber,
+	}).Info("🔵 WABLAS: Making device creation request")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Wablas API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":   resp.StatusCode,
+		"response_body": string(body),
+	}).Info("📥 WABLAS: Device creation response received")
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Wablas response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return response, fmt.Errorf("wablas API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return response, nil
+}
+
+// DeleteDevice removes the Wablas device referenced by the device's instance
+func (p *wablasProvider) DeleteDevice(device *models.DeviceSettings) error {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return fmt.Errorf("no instance found for Wablas device %s", device.IDDevice.String)
+	}
+
+	req, err := http.NewRequest("DELETE", wablasAPIBaseURL+"/api/device/delete", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+
+	req.Header.Set("Authorization", device.Instance.String)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete Wablas device: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logrus.WithFields(logrus.Fields{
+		"status_code": resp.StatusCode,
+		"auth_token":  device.Instance.String,
+	}).Info("📥 WABLAS: Device deletion attempted")
+
+	return nil
+}
+
+// wablasToken extracts the token part of the instance value, following the
+// PHP pattern: $token = explode('.', $auth_header)[0];
+func wablasToken(authHeader string) string {
+	if strings.Contains(authHeader, ".") {
+		return strings.Split(authHeader, ".")[0]
+	}
+	return authHeader
+}
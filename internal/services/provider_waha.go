@@ -0,0 +1,761 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// wahaAPIBaseURL is the WAHA container endpoint; WAHA_API_BASE_URL selects a
+// different deployment region
+var wahaAPIBaseURL = providerBaseURL("WAHA_API_BASE_URL", "https://waha-plus-production-705f.up.railway.app")
+
+const (
+	// Hardcoded API key for WAHA provider (must match WHATSAPP_API_KEY in container)
+	wahaAPIKey = "dckr_pat_vxeqEu_CqRi5O3CBHnD7FxhnBz0"
+	// Webhook endpoint template for incoming WA messages
+	wahaWebhookTemplate = "https://nodepath-chat-production.up.railway.app/api/ai-whatsapp/webhook/waha/%s"
+)
+
+// wahaProvider implements the Provider interface for the WAHA gateway
+type wahaProvider struct {
+	httpClient *http.Client
+}
+
+func newWahaProvider() *wahaProvider {
+	return &wahaProvider{
+		httpClient: newProviderHTTPClient(30 * time.Second),
+	}
+}
+
+// Name returns the provider name stored in device settings
+func (p *wahaProvider) Name() string {
+	return "waha"
+}
+
+// Channel returns the messaging channel this provider serves
+func (p *wahaProvider) Channel() string {
+	return ChannelWhatsApp
+}
+
+// SendText sends a text message via WAHA API
+// Uses the WAHA HTTP API format as per documentation
+func (p *wahaProvider) SendText(deviceSettings *models.DeviceSettings, phoneNumber, message string) error {
+	// Prevent sending empty or whitespace-only messages to avoid <nil> messages
+	if message == "" || strings.TrimSpace(message) == "" {
+		logrus.WithFields(logrus.Fields{
+			"phone_number": phoneNumber,
+			"device_id":    deviceSettings.Instance.String,
+		}).Warn("[WAHA-TEXT] Skipping empty message to prevent <nil> message")
+		return nil
+	}
+
+	// Get instance for session (as per user requirements)
+	instance := ""
+	if deviceSettings.Instance.Valid {
+		instance = deviceSettings.Instance.String
+	} else {
+		return fmt.Errorf("no instance found for WAHA device %s", deviceSettings.Instance.String)
+	}
+
+	// WAHA API endpoint for sending text messages
+	apiURL := wahaAPIBaseURL + "/api/sendText"
+
+	// 🚨 DEBUG: Log API key details (masked for security)
+	maskedAPIKey := "<empty>"
+	if len(wahaAPIKey) > 8 {
+		maskedAPIKey = wahaAPIKey[:4] + "******" + wahaAPIKey[len(wahaAPIKey)-4:]
+	} else if len(wahaAPIKey) > 0 {
+		maskedAPIKey = "****" + wahaAPIKey[len(wahaAPIKey)-2:]
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"api_url":        apiURL,
+		"phone_number":   phoneNumber,
+		"message_len":    len(message),
+		"device_id":      deviceSettings.Instance.String,
+		"instance":       instance,
+		"api_key_masked": maskedAPIKey,
+		"api_key_length": len(wahaAPIKey),
+	}).Error("🚨 WAHA DEBUG: Preparing request with API key details")
+
+	// Format phone number for WAHA (international format without + and add @c.us)
+	chatId := phoneNumber
+	if !strings.HasSuffix(chatId, "@c.us") {
+		// Remove + if present and add @c.us
+		chatId = strings.TrimPrefix(chatId, "+") + "@c.us"
+	}
+
+	// Prepare JSON payload as per WAHA API documentation
+	payload := map[string]interface{}{
+		"session": instance, // Session name from instance
+		"chatId":  chatId,   // Phone number in WAHA format
+		"text":    message,  // Message content
+	}
+
+	// Convert payload to JSON
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	// 🚨 DEBUG: Log complete payload details
+	logrus.WithFields(logrus.Fields{
+		"payload":   payload,
+		"json_data": string(jsonData),
+		"chat_id":   chatId,
+		"session":   instance,
+		"message":   message,
+	}).Error("🚨 WAHA DEBUG: Complete payload prepared")
+
+	// Create request
+	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers as per WAHA API documentation
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Api-Key", wahaAPIKey) // API key for authentication
+
+	// 🚨 DEBUG: Log request headers (with masked API key)
+	headersCopy := make(map[string]string)
+	for key, values := range req.Header {
+		if key == "X-Api-Key" {
+			headersCopy[key] = maskedAPIKey
+		} else {
+			headersCopy[key] = strings.Join(values, ", ")
+		}
+	}
+	logrus.WithFields(logrus.Fields{
+		"method":         "POST",
+		"url":            apiURL,
+		"headers":        headersCopy,
+		"content_length": len(jsonData),
+	}).Error("🚨 WAHA DEBUG: Request headers and details")
+
+	// Send request
+	startTime := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	duration := time.Since(startTime)
+
+	// 🚨 DEBUG: Log complete response details
+	responseHeaders := make(map[string]string)
+	for key, values := range resp.Header {
+		responseHeaders[key] = strings.Join(values, ", ")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":      resp.StatusCode,
+		"response_body":    string(body),
+		"response_headers": responseHeaders,
+		"duration":         duration,
+		"instance":         instance,
+		"success":          resp.StatusCode >= 200 && resp.StatusCode < 300,
+	}).Error("🚨 WAHA DEBUG: Complete response received")
+
+	// Check for success (200-299 status codes)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// 🚨 DEBUG: Log error details for 401 Unauthorized
+		if resp.StatusCode == 401 {
+			logrus.WithFields(logrus.Fields{
+				"error_type":       "UNAUTHORIZED",
+				"api_key_provided": len(wahaAPIKey) > 0,
+				"api_key_length":   len(wahaAPIKey),
+				"api_key_masked":   maskedAPIKey,
+				"instance":         instance,
+				"endpoint":         apiURL,
+				"response_body":    string(body),
+			}).Error("🚨 WAHA DEBUG: 401 UNAUTHORIZED ERROR - API Key Issue")
+		}
+		return fmt.Errorf("WAHA API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	// 🚨 DEBUG: Log successful send
+	logrus.WithFields(logrus.Fields{
+		"phone_number":  phoneNumber,
+		"duration":      duration,
+		"device_id":     deviceSettings.Instance.String,
+		"status_code":   resp.StatusCode,
+		"response_body": string(body),
+	}).Error("🚨 WAHA DEBUG: ✅ Message sent successfully")
+
+	return nil
+}
+
+// SendMedia sends media message (image/video/audio) via WAHA API
+// Handles video, audio, and image files with appropriate API endpoints matching PHP logic exactly
+func (p *wahaProvider) SendMedia(deviceSettings *models.DeviceSettings, phoneNumber, mediaURL string) error {
+	// Get instance for session
+	instance := ""
+	if deviceSettings.Instance.Valid {
+		instance = deviceSettings.Instance.String
+	} else {
+		return fmt.Errorf("no instance found for WAHA device %s", deviceSettings.Instance.String)
+	}
+
+	// Format phone number - remove all non-numeric characters (matching PHP preg_replace)
+	number := strings.NewReplacer(
+		"+", "",
+		"-", "",
+		" ", "",
+		"(", "",
+		")", "",
+	).Replace(phoneNumber)
+
+	// Format chatId for WAHA
+	chatId := number + "@c.us"
+
+	// Initialize variables for API endpoint and payload
+	var apiURL string
+	var payload map[string]interface{}
+
+	// Check file type based on extension (matching PHP logic exactly)
+	if strings.Contains(mediaURL, ".mp4") {
+		// VIDEO - use sendVideo endpoint
+		apiURL = wahaAPIBaseURL + "/api/sendVideo"
+		payload = map[string]interface{}{
+			"session": instance,
+			"chatId":  chatId,
+			"file": map[string]interface{}{
+				"mimetype": "video/mp4",
+				"url":      mediaURL,
+				"filename": "Video",
+			},
+			"caption": nil, // Can add caption if needed
+		}
+	} else if strings.Contains(mediaURL, ".mp3") {
+		// AUDIO - use sendFile endpoint (matching PHP)
+		apiURL = wahaAPIBaseURL + "/api/sendFile"
+		payload = map[string]interface{}{
+			"session": instance,
+			"chatId":  chatId,
+			"file": map[string]interface{}{
+				"mimetype": "audio/mp3",
+				"url":      mediaURL,
+				"filename": "Audio",
+			},
+			"caption": nil,
+		}
+	} else {
+		// IMAGE or other - determine mimetype from extension
+		// Parse URL to get extension
+		parsedURL, _ := url.Parse(mediaURL)
+		path := parsedURL.Path
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+
+		// Mimetype map (matching PHP exactly)
+		mimeMap := map[string]string{
+			"jpg":  "image/jpeg",
+			"jpeg": "image/jpeg",
+			"png":  "image/png",
+			"gif":  "image/gif",
+			"webp": "image/webp",
+			"bmp":  "image/bmp",
+			"svg":  "image/svg+xml",
+		}
+
+		// Step 1: Try using extension
+		mimetype := ""
+		if ext != "" {
+			if mime, ok := mimeMap[ext]; ok {
+				mimetype = mime
+			}
+		}
+
+		// Step 2: If no extension match, try detecting from HTTP headers
+		if mimetype == "" {
+			// Try HEAD request to get Content-Type
+			headReq, _ := http.NewRequest("HEAD", mediaURL, nil)
+			headResp, err := p.httpClient.Do(headReq)
+			if err == nil && headResp != nil {
+				defer headResp.Body.Close()
+				contentType := headResp.Header.Get("Content-Type")
+				if contentType != "" {
+					// Extract mime type (remove charset etc)
+					if idx := strings.Index(contentType, ";"); idx > 0 {
+						contentType = contentType[:idx]
+					}
+					mimetype = strings.TrimSpace(contentType)
+				}
+			}
+		}
+
+		// Step 3: Fallback to default
+		if mimetype == "" {
+			mimetype = "image/jpeg"
+		}
+
+		// Use sendImage endpoint for images
+		apiURL = wahaAPIBaseURL + "/api/sendImage"
+		payload = map[string]interface{}{
+			"session": instance,
+			"chatId":  chatId,
+			"file": map[string]interface{}{
+				"mimetype": mimetype,
+				"url":      mediaURL,
+				"filename": "Image",
+			},
+			"caption": nil,
+		}
+	}
+
+	// Log the request details
+	logrus.WithFields(logrus.Fields{
+		"api_url":      apiURL,
+		"session":      instance,
+		"chatId":       chatId,
+		"media_url":    mediaURL,
+		"phone_number": phoneNumber,
+		"payload":      payload,
+	}).Info("📤 WAHA MEDIA: Sending media message")
+
+	// Convert payload to JSON
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	// Create request
+	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers (matching PHP exactly)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", wahaAPIKey)
+
+	// Send request
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Check for success
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logrus.WithFields(logrus.Fields{
+			"status_code":   resp.StatusCode,
+			"response_body": string(body),
+			"api_url":       apiURL,
+			"media_url":     mediaURL,
+		}).Error("❌ WAHA MEDIA: Failed to send media")
+		return fmt.Errorf("WAHA API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":   resp.StatusCode,
+		"response_body": string(body),
+		"media_url":     mediaURL,
+	}).Info("✅ WAHA MEDIA: Media sent successfully")
+
+	return nil
+}
+
+// GetStatus checks the WAHA session state. The session is connected when the
+// engine reports WORKING; otherwise the pairing QR code is attached
+func (p *wahaProvider) GetStatus(device *models.DeviceSettings) map[string]interface{} {
+	status := map[string]interface{}{
+		"device_id":    device.ID,
+		"provider":     "waha",
+		"connected":    false,
+		"status":       "disconnected",
+		"last_checked": time.Now(),
+		"details":      map[string]interface{}{},
+	}
+
+	if !device.Instance.Valid || device.Instance.String == "" {
+		status["status"] = "not_configured"
+		status["details"] = map[string]interface{}{
+			"error": "Device instance not configured",
+		}
+		return status
+	}
+	session := device.Instance.String
+
+	apiURL := fmt.Sprintf("%s/api/sessions/%s", wahaAPIBaseURL, url.PathEscape(session))
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to create status request",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Api-Key", wahaAPIKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).Error("[WAHA] HTTP request failed")
+		status["status"] = "connection_error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to connect to WAHA API",
+			"details": err.Error(),
+		}
+		return status
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to read API response",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":   resp.StatusCode,
+		"response_body": string(bodyBytes),
+		"session":       session,
+	}).Info("[WAHA] Session status response received")
+
+	if resp.StatusCode == 404 {
+		status["status"] = "device_not_found"
+		status["details"] = map[string]interface{}{
+			"error":       "Session not found in WAHA",
+			"session":     session,
+			"http_status": 404,
+		}
+		return status
+	}
+
+	if resp.StatusCode != 200 {
+		status["status"] = "api_error"
+		status["details"] = map[string]interface{}{
+			"http_status":   resp.StatusCode,
+			"error":         "API returned error status",
+			"response_body": string(bodyBytes),
+		}
+		return status
+	}
+
+	var sessionData map[string]interface{}
+	if err := json.Unmarshal(bodyBytes, &sessionData); err != nil {
+		status["status"] = "parse_error"
+		status["details"] = map[string]interface{}{
+			"error":        "Failed to parse API response",
+			"raw_response": string(bodyBytes),
+			"parse_error":  err.Error(),
+		}
+		return status
+	}
+
+	sessionStatus, _ := sessionData["status"].(string)
+	status["device_status"] = sessionStatus
+	status["details"] = sessionData
+
+	if sessionStatus == "WORKING" {
+		status["connected"] = true
+		status["status"] = "connected"
+	} else {
+		status["connected"] = false
+		status["status"] = "disconnected"
+
+		// Fetch QR code when session is waiting for pairing
+		qrCode := p.GetQR(device)
+		if qrCode != "" {
+			status["qr_code"] = qrCode
+		}
+	}
+
+	return status
+}
+
+// GetQR fetches the pairing QR code for a WAHA session as a base64 data URL
+func (p *wahaProvider) GetQR(device *models.DeviceSettings) string {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return ""
+	}
+	session := device.Instance.String
+
+	qrURL := fmt.Sprintf("%s/api/%s/auth/qr?format=image", wahaAPIBaseURL, url.PathEscape(session))
+
+	req, err := http.NewRequest("GET", qrURL, nil)
+	if err != nil {
+		logrus.WithError(err).Error("[WAHA] Failed to create QR request")
+		return ""
+	}
+
+	req.Header.Set("Accept", "image/png")
+	req.Header.Set("X-Api-Key", wahaAPIKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).Error("[WAHA] QR request failed")
+		return ""
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logrus.WithError(err).Error("[WAHA] Failed to read QR response")
+		return ""
+	}
+
+	if resp.StatusCode == 200 && len(bodyBytes) >= 8 && bodyBytes[0] == 0x89 && string(bodyBytes[1:4]) == "PNG" {
+		logrus.Info("[WAHA] Successfully fetched QR code as PNG image")
+		return fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(bodyBytes))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":     resp.StatusCode,
+		"response_length": len(bodyBytes),
+		"session":         session,
+	}).Warn("[WAHA] Failed to fetch QR code")
+
+	return ""
+}
+
+// CreateDevice creates and starts a WAHA session for the device, wiring the
+// message webhook, and returns the raw creation response
+func (p *wahaProvider) CreateDevice(idDevice, phoneNumber string) (map[string]interface{}, error) {
+	// Create unique session name using device ID
+	sessionName := fmt.Sprintf("user_%s", idDevice)
+
+	// Webhook endpoint for incoming WA messages - Use dedicated WAHA endpoint
+	webhook := fmt.Sprintf(wahaWebhookTemplate, idDevice)
+
+	sessionData := map[string]interface{}{
+		"name":  sessionName,
+		"start": false,
+		"config": map[string]interface{}{
+			"debug":    false,
+			"markSeen": false, // Disable auto-read
+			"noweb": map[string]interface{}{
+				"store": map[string]interface{}{
+					"enabled":  true,
+					"fullSync": false,
+				},
+			},
+			"webhooks": []map[string]interface{}{
+				{
+					"url":    webhook,
+					"events": []string{"message"},
+					"retries": map[string]interface{}{
+						"attempts": 1,
+						"delay":    3,
+						"policy":   "constant",
+					},
+				},
+			},
+		},
+	}
+
+	sessionJSON, err := json.Marshal(sessionData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session data: %w", err)
+	}
+
+	createURL := wahaAPIBaseURL + "/api/sessions"
+	createRequest, err := http.NewRequest("POST", createURL, bytes.NewBuffer(sessionJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session request: %w", err)
+	}
+
+	createRequest.Header.Set("Content-Type", "application/json")
+	createRequest.Header.Set("X-Api-Key", wahaAPIKey)
+
+	logrus.WithFields(logrus.Fields{
+		"provider":     "waha",
+		"url":          createURL,
+		"session_name": sessionName,
+		"webhook":      webhook,
+	}).Info("🟢 WAHA: Making session creation request")
+
+	createResp, err := p.httpClient.Do(createRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to communicate with WAHA API: %w", err)
+	}
+	defer createResp.Body.Close()
+
+	createBody, err := io.ReadAll(createResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API response: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"provider":      "waha",
+		"status_code":   createResp.StatusCode,
+		"response_body": string(createBody),
+	}).Info("📄 WAHA: Session creation response received")
+
+	var createResponse map[string]interface{}
+	if err := json.Unmarshal(createBody, &createResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	// Check if session creation was successful
+	// Handle both successful 200/201 status codes and error responses
+	if createResp.StatusCode != http.StatusOK && createResp.StatusCode != http.StatusCreated {
+		errorMsg := "Unknown error"
+		if errResp, exists := createResponse["error"]; exists {
+			errorMsg = fmt.Sprintf("%v", errResp)
+		} else if msgResp, exists := createResponse["message"]; exists {
+			errorMsg = fmt.Sprintf("%v", msgResp)
+		}
+
+		// Special case: session already exists is not an error
+		if strings.Contains(strings.ToLower(errorMsg), "already") || strings.Contains(strings.ToLower(errorMsg), "exists") {
+			logrus.WithFields(logrus.Fields{
+				"session_name": sessionName,
+				"message":      errorMsg,
+			}).Info("🔄 WAHA: Session already exists, proceeding with existing session")
+		} else {
+			return createResponse, fmt.Errorf("WAHA session creation error: %s", errorMsg)
+		}
+	}
+
+	// Start session immediately
+	startURL := fmt.Sprintf("%s/api/sessions/%s/start", wahaAPIBaseURL, sessionName)
+	startRequest, err := http.NewRequest("PUT", startURL, nil)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create start session request")
+	} else {
+		startRequest.Header.Set("Content-Type", "application/json")
+		startRequest.Header.Set("X-Api-Key", wahaAPIKey)
+
+		startResp, err := p.httpClient.Do(startRequest)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to start WAHA session")
+		} else {
+			defer startResp.Body.Close()
+			logrus.WithFields(logrus.Fields{
+				"status_code":  startResp.StatusCode,
+				"session_name": sessionName,
+			}).Info("📥 WAHA: Session start attempted")
+		}
+	}
+
+	if createResponse == nil {
+		createResponse = map[string]interface{}{}
+	}
+	createResponse["session_name"] = sessionName
+	createResponse["webhook_url"] = webhook
+
+	return createResponse, nil
+}
+
+// DeleteDevice removes the WAHA session referenced by the device's instance
+func (p *wahaProvider) DeleteDevice(device *models.DeviceSettings) error {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return fmt.Errorf("no instance found for WAHA device %s", device.IDDevice.String)
+	}
+	session := device.Instance.String
+
+	deleteURL := fmt.Sprintf("%s/api/sessions/%s", wahaAPIBaseURL, url.PathEscape(session))
+	req, err := http.NewRequest("DELETE", deleteURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+
+	req.Header.Set("X-Api-Key", wahaAPIKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete WAHA session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":  resp.StatusCode,
+		"session_name": session,
+	}).Info("📥 WAHA: Session deletion attempted")
+
+	return nil
+}
+
+// RegisterWebhook re-applies the message webhook configuration on the
+// device's WAHA session, used after credential rotation so incoming messages
+// keep arriving without recreating the session
+func (p *wahaProvider) RegisterWebhook(device *models.DeviceSettings) error {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return fmt.Errorf("no instance found for WAHA device %s", device.IDDevice.String)
+	}
+	session := device.Instance.String
+	webhook := fmt.Sprintf(wahaWebhookTemplate, device.IDDevice.String)
+
+	updateData := map[string]interface{}{
+		"config": map[string]interface{}{
+			"webhooks": []map[string]interface{}{
+				{
+					"url":    webhook,
+					"events": []string{"message"},
+					"retries": map[string]interface{}{
+						"attempts": 1,
+						"delay":    3,
+						"policy":   "constant",
+					},
+				},
+			},
+		},
+	}
+
+	updateJSON, err := json.Marshal(updateData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook config: %w", err)
+	}
+
+	updateURL := fmt.Sprintf("%s/api/sessions/%s", wahaAPIBaseURL, url.PathEscape(session))
+	req, err := http.NewRequest("PUT", updateURL, bytes.NewBuffer(updateJSON))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook update request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", wahaAPIKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update WAHA session webhooks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("WAHA webhook update returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"session_name": session,
+		"webhook":      webhook,
+	}).Info("🟢 WAHA: Session webhook re-registered")
+
+	return nil
+}
@@ -0,0 +1,518 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// whacenterAPIBaseURL is the Whacenter gateway endpoint; WHACENTER_API_BASE_URL
+// selects a regional server
+var whacenterAPIBaseURL = providerBaseURL("WHACENTER_API_BASE_URL", "https://api.whacenter.com")
+
+// Hardcoded API key for Whacenter requests
+const whacenterAPIKey = "abebe840-156c-441c-8252-da0342c5a07c"
+
+// whacenterProvider implements the Provider interface for the Whacenter gateway
+type whacenterProvider struct {
+	httpClient *http.Client
+}
+
+func newWhacenterProvider() *whacenterProvider {
+	return &whacenterProvider{
+		httpClient: newProviderHTTPClient(30 * time.Second),
+	}
+}
+
+// Name returns the provider name stored in device settings
+func (p *whacenterProvider) Name() string {
+	return "whacenter"
+}
+
+// Channel returns the messaging channel this provider serves
+func (p *whacenterProvider) Channel() string {
+	return ChannelWhatsApp
+}
+
+// SendText sends a text message via Whacenter API
+// Uses the exact API format specified by user requirements
+func (p *whacenterProvider) SendText(deviceSettings *models.DeviceSettings, phoneNumber, message string) error {
+	// Prevent sending empty or whitespace-only messages to avoid <nil> messages
+	if message == "" || strings.TrimSpace(message) == "" {
+		logrus.WithFields(logrus.Fields{
+			"phone_number": phoneNumber,
+			"device_id":    deviceSettings.Instance.String,
+		}).Warn("[WHACENTER] Skipping empty message to prevent <nil> message")
+		return nil
+	}
+
+	apiURL := whacenterAPIBaseURL + "/api/send"
+
+	logrus.WithFields(logrus.Fields{
+		"api_url":      apiURL,
+		"phone_number": phoneNumber,
+		"message_len":  len(message),
+		"device_id":    deviceSettings.Instance.String,
+	}).Debug("[WHACENTER] Preparing request")
+
+	// Get instance for device_id (as per user requirements)
+	instance := ""
+	if deviceSettings.Instance.Valid {
+		instance = deviceSettings.Instance.String
+	} else {
+		return fmt.Errorf("no instance found for Whacenter device %s", deviceSettings.Instance.String)
+	}
+
+	// Prepare form data exactly as specified by user
+	data := url.Values{}
+	data.Set("device_id", instance) // device_id from instance
+	data.Set("number", phoneNumber) // recipient number
+	data.Set("message", message)    // message content
+
+	// Create request
+	req, err := http.NewRequest("POST", apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers (form data, no authorization header as per user example)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Send request
+	startTime := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	duration := time.Since(startTime)
+	logrus.WithFields(logrus.Fields{
+		"status_code": resp.StatusCode,
+		"response":    string(body),
+		"duration":    duration,
+		"instance":    instance,
+	}).Debug("[WHACENTER] Response received")
+
+	// Check for success (200-299 status codes)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("whacenter API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"phone_number": phoneNumber,
+		"duration":     duration,
+		"device_id":    deviceSettings.Instance.String,
+	}).Info("[WHACENTER] ✅ Message sent successfully")
+
+	return nil
+}
+
+// SendMedia sends a media message via Whacenter API
+// Uses the exact API format specified by user requirements with type detection
+func (p *whacenterProvider) SendMedia(deviceSettings *models.DeviceSettings, phoneNumber, mediaURL string) error {
+	apiURL := whacenterAPIBaseURL + "/api/send"
+
+	logrus.WithFields(logrus.Fields{
+		"api_url":      apiURL,
+		"phone_number": phoneNumber,
+		"media_url":    mediaURL,
+		"device_id":    deviceSettings.Instance.String,
+	}).Debug("[WHACENTER] Preparing media request")
+
+	// Get instance for device_id (as per user requirements)
+	instance := ""
+	if deviceSettings.Instance.Valid {
+		instance = deviceSettings.Instance.String
+	} else {
+		return fmt.Errorf("no instance found for Whacenter device %s", deviceSettings.Instance.String)
+	}
+
+	// Detect media type based on file extension (as per PHP code)
+	mediaType := ""
+	if strings.Contains(mediaURL, ".mp4") {
+		mediaType = "video"
+	} else if strings.Contains(mediaURL, ".mp3") {
+		mediaType = "audio"
+	} else {
+		mediaType = "image"
+	}
+
+	// Prepare form data exactly as specified by user PHP code
+	data := url.Values{}
+	data.Set("device_id", instance) // device_id from instance
+	data.Set("number", phoneNumber) // recipient number
+	data.Set("file", mediaURL)      // media file URL
+
+	// Add type parameter for video and audio only (as per PHP code)
+	if mediaType != "" && mediaType != "image" {
+		data.Set("type", mediaType)
+	}
+
+	// Create request
+	req, err := http.NewRequest("POST", apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers (form data, no authorization header as per user example)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Send request
+	startTime := time.Now()
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	duration := time.Since(startTime)
+	logrus.WithFields(logrus.Fields{
+		"status_code": resp.StatusCode,
+		"response":    string(body),
+		"duration":    duration,
+		"instance":    instance,
+		"media_type":  mediaType,
+	}).Debug("[WHACENTER] Media response received")
+
+	// Check for success (200-299 status codes)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("whacenter API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"phone_number": phoneNumber,
+		"duration":     duration,
+		"device_id":    deviceSettings.Instance.String,
+		"media_type":   mediaType,
+	}).Info("[WHACENTER] ✅ Media sent successfully")
+
+	return nil
+}
+
+// GetStatus checks the status of a Whacenter device via the statusDevice
+// endpoint, attaching the QR code when the device is not connected
+func (p *whacenterProvider) GetStatus(device *models.DeviceSettings) map[string]interface{} {
+	status := map[string]interface{}{
+		"device_id":    device.ID,
+		"provider":     "whacenter",
+		"connected":    false,
+		"status":       "disconnected",
+		"last_checked": time.Now(),
+		"details":      map[string]interface{}{},
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"device_id":      device.ID,
+		"instance_valid": device.Instance.Valid,
+		"instance_value": device.Instance.String,
+	}).Info("[WHACENTER] Starting Whacenter status check")
+
+	if !device.Instance.Valid || device.Instance.String == "" {
+		logrus.Error("[WHACENTER] Device instance not configured")
+		status["status"] = "not_configured"
+		status["details"] = map[string]interface{}{
+			"error": "Device instance not configured",
+		}
+		return status
+	}
+
+	// Use the correct statusDevice API endpoint with device_id and api_key parameters
+	apiURL := fmt.Sprintf("%s/api/statusDevice?api_key=%s&device_id=%s",
+		whacenterAPIBaseURL, whacenterAPIKey, url.QueryEscape(device.Instance.String))
+
+	logrus.WithFields(logrus.Fields{
+		"api_url": apiURL,
+	}).Info("[WHACENTER] Making API request")
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		logrus.WithError(err).Error("[WHACENTER] Failed to create HTTP request")
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to create status request",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	// No authorization header needed for statusDevice endpoint
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).Error("[WHACENTER] HTTP request failed")
+		status["status"] = "connection_error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to connect to Whacenter API",
+			"details": err.Error(),
+		}
+		return status
+	}
+	defer resp.Body.Close()
+
+	// Read response body for logging
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logrus.WithError(err).Error("[WHACENTER] Failed to read response body")
+		status["status"] = "error"
+		status["details"] = map[string]interface{}{
+			"error":   "Failed to read API response",
+			"details": err.Error(),
+		}
+		return status
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"response_body": string(bodyBytes),
+		"body_length":   len(bodyBytes),
+	}).Info("[WHACENTER] Response body received")
+
+	if resp.StatusCode == 200 {
+		var apiResponse map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &apiResponse); err == nil {
+			logrus.WithField("parsed_response", apiResponse).Info("[WHACENTER] Successfully parsed JSON response")
+
+			// Parse the response according to statusDevice API format
+			if data, ok := apiResponse["data"].(map[string]interface{}); ok {
+				if deviceStatus, ok := data["status"].(string); ok {
+					logrus.WithField("device_status", deviceStatus).Info("[WHACENTER] Found device status")
+
+					if deviceStatus == "NOT CONNECTED" {
+						status["connected"] = false
+						status["status"] = "disconnected"
+
+						// Fetch QR code when device is not connected
+						qrCode := p.GetQR(device)
+						if qrCode != "" {
+							status["qr_code"] = qrCode
+						}
+					} else {
+						status["connected"] = true
+						status["status"] = "connected"
+					}
+					status["device_status"] = deviceStatus
+				} else {
+					logrus.Warn("[WHACENTER] No 'status' field found in data")
+					status["status"] = "unknown"
+				}
+				status["details"] = data
+			} else {
+				logrus.Warn("[WHACENTER] No 'data' field found in response")
+				status["status"] = "invalid_response"
+				status["details"] = apiResponse
+			}
+		} else {
+			logrus.WithError(err).Error("[WHACENTER] Failed to parse JSON response")
+			status["status"] = "parse_error"
+			status["details"] = map[string]interface{}{
+				"error":        "Failed to parse API response",
+				"raw_response": string(bodyBytes),
+				"parse_error":  err.Error(),
+			}
+		}
+	} else if resp.StatusCode == 404 {
+		// Handle 404 specifically - device not found in Whacenter
+		logrus.WithFields(logrus.Fields{
+			"device_instance": device.Instance.String,
+			"api_url":         apiURL,
+		}).Warn("[WHACENTER] Device not found in Whacenter system")
+
+		status["status"] = "device_not_found"
+		status["connected"] = false
+		status["details"] = map[string]interface{}{
+			"error":           "Device not found in Whacenter system",
+			"message":         "The device may have been deleted from Whacenter or the device ID is incorrect",
+			"device_instance": device.Instance.String,
+			"http_status":     404,
+			"response_body":   string(bodyBytes),
+			"suggestion":      "Please regenerate the device or check if it exists in your Whacenter dashboard",
+		}
+	} else {
+		logrus.WithFields(logrus.Fields{
+			"status_code":   resp.StatusCode,
+			"response_body": string(bodyBytes),
+		}).Error("[WHACENTER] API returned non-200 status")
+
+		status["status"] = "api_error"
+		status["details"] = map[string]interface{}{
+			"http_status":   resp.StatusCode,
+			"error":         "API returned error status",
+			"response_body": string(bodyBytes),
+		}
+	}
+
+	logrus.WithField("final_status", status).Info("[WHACENTER] Returning status")
+	return status
+}
+
+// GetQR fetches the QR code for a Whacenter device when not connected. The
+// endpoint returns either a PNG image or a JSON payload with the code
+func (p *whacenterProvider) GetQR(device *models.DeviceSettings) string {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return ""
+	}
+	deviceID := device.Instance.String
+
+	logrus.WithField("device_id", deviceID).Info("[WHACENTER] Fetching QR code")
+
+	qrURL := fmt.Sprintf("%s/api/qr?api_key=%s&device_id=%s",
+		whacenterAPIBaseURL, whacenterAPIKey, url.QueryEscape(deviceID))
+
+	req, err := http.NewRequest("GET", qrURL, nil)
+	if err != nil {
+		logrus.WithError(err).Error("[WHACENTER] Failed to create QR request")
+		return ""
+	}
+
+	// Accept both JSON and image responses
+	req.Header.Set("Accept", "application/json, image/png")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logrus.WithError(err).Error("[WHACENTER] QR request failed")
+		return ""
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logrus.WithError(err).Error("[WHACENTER] Failed to read QR response")
+		return ""
+	}
+
+	if resp.StatusCode == 200 {
+		// Check if response is a PNG image (like in the PHP code)
+		// PNG signature: \x89PNG\r\n\x1a\n (first 8 bytes)
+		if len(bodyBytes) >= 8 && bodyBytes[0] == 0x89 && string(bodyBytes[1:4]) == "PNG" {
+			// It's a valid PNG image, convert to base64 data URL
+			logrus.Info("[WHACENTER] Successfully fetched QR code as PNG image")
+			return fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(bodyBytes))
+		}
+
+		// If not PNG, try to parse as JSON response
+		var qrResponse map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &qrResponse); err == nil {
+			if data, ok := qrResponse["data"].(map[string]interface{}); ok {
+				if qrCode, ok := data["qr"].(string); ok {
+					logrus.Info("[WHACENTER] Successfully fetched QR code from JSON")
+					return qrCode
+				}
+				logrus.Warn("[WHACENTER] No 'qr' field found in JSON data")
+			} else {
+				logrus.Warn("[WHACENTER] No 'data' field found in JSON response")
+			}
+		} else {
+			logrus.WithError(err).Warn("[WHACENTER] Failed to parse response as JSON")
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":     resp.StatusCode,
+		"response_length": len(bodyBytes),
+		"content_type":    resp.Header.Get("Content-Type"),
+	}).Warn("[WHACENTER] Failed to fetch QR code")
+
+	return ""
+}
+
+// CreateDevice provisions a new Whacenter device and returns the raw
+// creation response
+func (p *whacenterProvider) CreateDevice(idDevice, phoneNumber string) (map[string]interface{}, error) {
+	apiURL := fmt.Sprintf("%s/api/addDevice?api_key=%s&name=%s&number=%s",
+		whacenterAPIBaseURL, whacenterAPIKey, url.QueryEscape(idDevice), url.QueryEscape(phoneNumber))
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	logrus.WithFields(logrus.Fields{
+		"provider":     "whacenter",
+		"device_name":  idDevice,
+		"phone_number": phoneNumber,
+	}).Info("🔵 WHACENTER: Making device creation request")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Whacenter API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"status_code":   resp.StatusCode,
+		"response_body": string(body),
+	}).Info("📥 WHACENTER: Device creation response received")
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Whacenter response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return response, fmt.Errorf("whacenter API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return response, nil
+}
+
+// DeleteDevice removes the Whacenter device referenced by the device's
+// instance value
+func (p *whacenterProvider) DeleteDevice(device *models.DeviceSettings) error {
+	if !device.Instance.Valid || device.Instance.String == "" {
+		return fmt.Errorf("no instance found for Whacenter device %s", device.IDDevice.String)
+	}
+
+	deleteURL := fmt.Sprintf("%s/api/deleteDevice?api_key=%s&device_id=%s",
+		whacenterAPIBaseURL, whacenterAPIKey, url.QueryEscape(device.Instance.String))
+
+	req, err := http.NewRequest("GET", deleteURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete Whacenter device: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logrus.WithFields(logrus.Fields{
+		"status":    resp.StatusCode,
+		"device_id": device.Instance.String,
+	}).Info("📥 WHACENTER: Device deletion attempted")
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// qrImageAPIBase renders QR images. Generation is delegated the same way the
+// platform already delegates messaging and payments to external APIs
+const qrImageAPIBase = "https://api.qrserver.com/v1/create-qr-code/"
+
+// QRCampaignService manages printed QR entry points. Each campaign gets a
+// unique entry keyword (base keyword + suffix) so scans are attributable per
+// campaign while still starting the mapped flow
+type QRCampaignService struct {
+	qrRepo                repository.QRCampaignRepository
+	deviceSettingsService *DeviceSettingsService
+	httpClient            *http.Client
+}
+
+// NewQRCampaignService creates a new QR campaign service
+func NewQRCampaignService(qrRepo repository.QRCampaignRepository, deviceSettingsService *DeviceSettingsService) *QRCampaignService {
+	return &QRCampaignService{
+		qrRepo:                qrRepo,
+		deviceSettingsService: deviceSettingsService,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// CreateCampaign generates a campaign with a unique entry keyword
+func (s *QRCampaignService) CreateCampaign(idDevice, name, flowID, keyword string) (*models.QRCampaign, error) {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return nil, fmt.Errorf("campaign keyword is required")
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("campaign name is required")
+	}
+
+	suffix, err := generateQRSuffix()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate campaign suffix: %w", err)
+	}
+
+	campaign := &models.QRCampaign{
+		ID:           uuid.New().String(),
+		IDDevice:     idDevice,
+		Name:         name,
+		FlowID:       flowID,
+		Keyword:      keyword,
+		EntryKeyword: fmt.Sprintf("%s-%s", keyword, suffix),
+	}
+
+	if err := s.qrRepo.CreateQRCampaign(campaign); err != nil {
+		return nil, fmt.Errorf("failed to create QR campaign: %w", err)
+	}
+
+	campaign.InviteLink = s.buildWaLink(idDevice, campaign.EntryKeyword)
+
+	logrus.WithFields(logrus.Fields{
+		"campaign_id":   campaign.ID,
+		"entry_keyword": campaign.EntryKeyword,
+	}).Info("✅ QR: Campaign created")
+
+	return campaign, nil
+}
+
+// GetCampaigns returns a device's campaigns with their invite links
+func (s *QRCampaignService) GetCampaigns(idDevice string) ([]models.QRCampaign, error) {
+	campaigns, err := s.qrRepo.GetQRCampaignsByDevice(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	for i := range campaigns {
+		campaigns[i].InviteLink = s.buildWaLink(idDevice, campaigns[i].EntryKeyword)
+	}
+	return campaigns, nil
+}
+
+// GetCampaign returns a single campaign with its invite link
+func (s *QRCampaignService) GetCampaign(id string) (*models.QRCampaign, error) {
+	campaign, err := s.qrRepo.GetQRCampaignByID(id)
+	if err != nil || campaign == nil {
+		return campaign, err
+	}
+	campaign.InviteLink = s.buildWaLink(campaign.IDDevice, campaign.EntryKeyword)
+	return campaign, nil
+}
+
+// DeleteCampaign removes a campaign
+func (s *QRCampaignService) DeleteCampaign(id string) error {
+	return s.qrRepo.DeleteQRCampaign(id)
+}
+
+// RenderQRImage fetches the campaign's QR as PNG or SVG bytes
+func (s *QRCampaignService) RenderQRImage(campaign *models.QRCampaign, format string, size int) ([]byte, string, error) {
+	if campaign.InviteLink == "" {
+		return nil, "", fmt.Errorf("device has no phone number configured, cannot build wa.me link")
+	}
+
+	contentType := "image/png"
+	if format == "svg" {
+		contentType = "image/svg+xml"
+	} else {
+		format = "png"
+	}
+	if size < 100 || size > 1000 {
+		size = 300
+	}
+
+	params := url.Values{}
+	params.Set("data", campaign.InviteLink)
+	params.Set("format", format)
+	params.Set("size", fmt.Sprintf("%dx%d", size, size))
+
+	resp, err := s.httpClient.Get(qrImageAPIBase + "?" + params.Encode())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to render QR image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("QR image API returned status %d", resp.StatusCode)
+	}
+
+	image, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read QR image: %w", err)
+	}
+
+	return image, contentType, nil
+}
+
+// TrackScan matches an inbound message against the device's entry keywords.
+// On a match the scan is counted and the base keyword is returned so the
+// mapped flow still starts; ok is false when the message is not a QR entry
+func (s *QRCampaignService) TrackScan(idDevice, content string) (string, bool) {
+	entryKeyword := strings.ToLower(strings.TrimSpace(content))
+	if entryKeyword == "" || !strings.Contains(entryKeyword, "-") {
+		return "", false
+	}
+
+	campaign, err := s.qrRepo.GetQRCampaignByEntryKeyword(idDevice, entryKeyword)
+	if err != nil || campaign == nil {
+		return "", false
+	}
+
+	if err := s.qrRepo.IncrementScanCount(campaign.ID); err != nil {
+		logrus.WithError(err).Error("📷 QR: Failed to count scan")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"campaign_id":   campaign.ID,
+		"campaign_name": campaign.Name,
+		"entry_keyword": campaign.EntryKeyword,
+	}).Info("✅ QR: Campaign scan tracked")
+
+	return campaign.Keyword, true
+}
+
+// buildWaLink renders the wa.me deep link prefilled with the entry keyword
+func (s *QRCampaignService) buildWaLink(idDevice, entryKeyword string) string {
+	phone := ""
+	if s.deviceSettingsService != nil {
+		if device, err := s.deviceSettingsService.GetByIDDevice(idDevice); err == nil && device != nil && device.PhoneNumber.Valid {
+			phone = strings.Map(func(r rune) rune {
+				if r >= '0' && r <= '9' {
+					return r
+				}
+				return -1
+			}, device.PhoneNumber.String)
+		}
+	}
+	if phone == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("https://wa.me/%s?text=%s", phone, url.QueryEscape(entryKeyword))
+}
+
+// generateQRSuffix builds a short random campaign suffix
+func generateQRSuffix() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for _, b := range buf {
+		builder.WriteByte(voucherCodeCharset[int(b)%len(voucherCodeCharset)])
+	}
+
+	return strings.ToLower(builder.String()), nil
+}
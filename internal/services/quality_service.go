@@ -0,0 +1,232 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// QualityService runs the automated conversation quality evaluator: it
+// samples completed conversations in the background, asks the device's AI
+// model to score the transcript against a rubric (accuracy, tone, goal
+// completion), and stores the scores per flow version so prompt changes can
+// be compared objectively
+
+// qualityEvalInterval is how often the background evaluator samples
+const qualityEvalInterval = 1 * time.Hour
+
+// qualitySampleSize caps how many conversations are evaluated per run
+const qualitySampleSize = 10
+
+// qualityTranscriptLimit caps how many messages feed one evaluation
+const qualityTranscriptLimit = 50
+
+// qualityScoreLimit caps the score listing endpoint
+const qualityScoreLimit = 100
+
+// qualityRubricPrompt is the system prompt for the evaluator model
+const qualityRubricPrompt = `You are a strict conversation quality evaluator for a WhatsApp sales chatbot.
+Score the conversation transcript on three criteria, each from 0 to 10:
+- accuracy: the bot's answers were correct, relevant and consistent
+- tone: the bot was polite, natural and matched the customer's language
+- goal_completion: the conversation moved the customer toward the sales goal
+
+Respond with ONLY a JSON object in this exact format:
+{"accuracy": 0, "tone": 0, "goal_completion": 0, "summary": "one sentence explaining the scores"}`
+
+// QualityService evaluates completed conversations with an LLM rubric
+type QualityService struct {
+	qualityRepo repository.QualityRepository
+	aiService   *AIService
+	done        chan struct{}
+}
+
+// NewQualityService creates a new quality service
+func NewQualityService(qualityRepo repository.QualityRepository, aiService *AIService) *QualityService {
+	return &QualityService{
+		qualityRepo: qualityRepo,
+		aiService:   aiService,
+		done:        make(chan struct{}),
+	}
+}
+
+// StartScheduler runs the background evaluator until Stop is called.
+// Call in a goroutine
+func (s *QualityService) StartScheduler() {
+	logrus.Info("🎯 QUALITY: Conversation evaluator started")
+	ticker := time.NewTicker(qualityEvalInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.runBatch()
+		}
+	}
+}
+
+// Stop stops the background evaluator
+func (s *QualityService) Stop() {
+	close(s.done)
+}
+
+// runBatch samples unscored completed conversations and evaluates each one
+func (s *QualityService) runBatch() {
+	conversations, err := s.qualityRepo.GetUnscoredCompletedConversations(qualitySampleSize)
+	if err != nil {
+		logrus.WithError(err).Error("🎯 QUALITY: Failed to sample conversations")
+		return
+	}
+	if len(conversations) == 0 {
+		return
+	}
+
+	logrus.WithField("sample_size", len(conversations)).Info("🎯 QUALITY: Evaluating conversation sample")
+
+	for _, conversation := range conversations {
+		if err := s.EvaluateConversation(conversation.ProspectNum, conversation.IDDevice, conversation.FlowID.String); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"prospect_num": conversation.ProspectNum,
+				"id_device":    conversation.IDDevice,
+			}).Warn("🎯 QUALITY: Evaluation failed")
+		}
+	}
+}
+
+// EvaluateConversation scores one conversation against the rubric and stores
+// the result under the flow's current version fingerprint
+func (s *QualityService) EvaluateConversation(prospectNum, idDevice, flowID string) error {
+	transcript, err := s.qualityRepo.GetConversationTranscript(prospectNum, idDevice, qualityTranscriptLimit)
+	if err != nil {
+		return err
+	}
+	if len(transcript) == 0 {
+		return fmt.Errorf("no transcript for conversation %s on device %s", prospectNum, idDevice)
+	}
+
+	apiKey, err := s.qualityRepo.GetDeviceAPIKey(idDevice)
+	if err != nil {
+		return fmt.Errorf("failed to get device API key: %w", err)
+	}
+	if apiKey == "" {
+		return fmt.Errorf("device %s has no API key configured", idDevice)
+	}
+
+	response, err := s.aiService.GenerateResponse(qualityRubricPrompt, formatTranscript(transcript), apiKey, idDevice, nil)
+	if err != nil {
+		return fmt.Errorf("evaluator call failed: %w", err)
+	}
+
+	scores, summary, err := parseRubricResponse(response)
+	if err != nil {
+		return fmt.Errorf("failed to parse evaluator response: %w", err)
+	}
+
+	score := &models.QualityScore{
+		ProspectNum:    prospectNum,
+		IDDevice:       idDevice,
+		FlowID:         flowID,
+		FlowVersion:    s.flowVersion(flowID),
+		Accuracy:       scores["accuracy"],
+		Tone:           scores["tone"],
+		GoalCompletion: scores["goal_completion"],
+		Overall:        (scores["accuracy"] + scores["tone"] + scores["goal_completion"]) / 3,
+		Summary:        summary,
+	}
+
+	if err := s.qualityRepo.InsertScore(score); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"flow_id":      flowID,
+		"flow_version": score.FlowVersion,
+		"overall":      score.Overall,
+	}).Info("🎯 QUALITY: Conversation scored")
+
+	return nil
+}
+
+// Scores returns a device's evaluations, newest first
+func (s *QualityService) Scores(idDevice string) ([]models.QualityScore, error) {
+	return s.qualityRepo.GetScoresByDevice(idDevice, qualityScoreLimit)
+}
+
+// VersionSummaries returns the flow's averaged scores per version so prompt
+// changes can be compared
+func (s *QualityService) VersionSummaries(flowID string) ([]models.QualityVersionSummary, error) {
+	return s.qualityRepo.GetVersionSummaries(flowID)
+}
+
+// flowVersion fingerprints the flow's current nodes JSON; unchanged prompts
+// keep the same version so their scores aggregate together
+func (s *QualityService) flowVersion(flowID string) string {
+	nodes, err := s.qualityRepo.GetFlowNodesJSON(flowID)
+	if err != nil || nodes == "" {
+		return "unknown"
+	}
+	sum := sha256.Sum256([]byte(nodes))
+	return fmt.Sprintf("%x", sum[:6])
+}
+
+// formatTranscript renders the conversation for the evaluator prompt
+func formatTranscript(messages []models.ConversationLog) string {
+	var builder strings.Builder
+	for _, message := range messages {
+		role := "Customer"
+		if message.Sender == "bot" {
+			role = "Bot"
+		}
+		builder.WriteString(fmt.Sprintf("%s: %s\n", role, message.Message))
+	}
+	return builder.String()
+}
+
+// parseRubricResponse extracts the rubric JSON from the evaluator response,
+// tolerating markdown fences around the object
+func parseRubricResponse(response string) (map[string]float64, string, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end <= start {
+		return nil, "", fmt.Errorf("no JSON object in response: %s", response)
+	}
+
+	var parsed struct {
+		Accuracy       float64 `json:"accuracy"`
+		Tone           float64 `json:"tone"`
+		GoalCompletion float64 `json:"goal_completion"`
+		Summary        string  `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return nil, "", err
+	}
+
+	scores := map[string]float64{
+		"accuracy":        clampScore(parsed.Accuracy),
+		"tone":            clampScore(parsed.Tone),
+		"goal_completion": clampScore(parsed.GoalCompletion),
+	}
+	return scores, parsed.Summary, nil
+}
+
+// clampScore keeps rubric scores inside the 0-10 range
+func clampScore(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 10 {
+		return 10
+	}
+	return score
+}
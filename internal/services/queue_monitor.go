@@ -122,6 +122,8 @@ func (qm *QueueMonitor) Stop() {
 
 // RecordProcessingTime records the time taken to process a queue item
 func (qm *QueueMonitor) RecordProcessingTime(duration time.Duration) {
+	Metrics().ObserveHistogram(MetricProcessingSeconds, nil, duration.Seconds())
+
 	qm.mu.Lock()
 	defer qm.mu.Unlock()
 
@@ -150,6 +152,8 @@ func (qm *QueueMonitor) RecordProcessingTime(duration time.Duration) {
 
 // RecordQueueSize records the current size of a specific queue
 func (qm *QueueMonitor) RecordQueueSize(queueName string, size int64) {
+	Metrics().SetGauge(MetricQueueDepth, map[string]string{"queue": queueName}, float64(size))
+
 	qm.mu.Lock()
 	defer qm.mu.Unlock()
 
@@ -189,6 +193,8 @@ func (qm *QueueMonitor) RecordWorkerUtilization(poolName string, utilization flo
 
 // RecordError records a processing error
 func (qm *QueueMonitor) RecordError() {
+	Metrics().IncCounter(MetricQueueErrors, nil)
+
 	qm.mu.Lock()
 	defer qm.mu.Unlock()
 
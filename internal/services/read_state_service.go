@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReadStateService synchronizes per-agent read cursors for the agent inbox.
+// Read-state changes are broadcast over WebSocket so every connected agent sees
+// which threads are already being handled
+type ReadStateService struct {
+	readStateRepo    repository.ReadStateRepository
+	websocketService *WebSocketService
+}
+
+// NewReadStateService creates a new read state service
+func NewReadStateService(readStateRepo repository.ReadStateRepository, websocketService *WebSocketService) *ReadStateService {
+	return &ReadStateService{
+		readStateRepo:    readStateRepo,
+		websocketService: websocketService,
+	}
+}
+
+// MarkConversationRead moves an agent's read cursor to now and broadcasts the change
+func (s *ReadStateService) MarkConversationRead(agentID, prospectNum, idDevice string) error {
+	if err := s.readStateRepo.UpsertReadCursor(agentID, prospectNum, idDevice); err != nil {
+		return fmt.Errorf("failed to update read cursor: %w", err)
+	}
+
+	// Broadcast so other agents' inboxes clear the unread badge for this thread
+	if s.websocketService != nil {
+		s.websocketService.SendToDevice(idDevice, "read_state_changed", map[string]interface{}{
+			"agent_id":     agentID,
+			"prospect_num": prospectNum,
+			"id_device":    idDevice,
+			"read_at":      time.Now().Unix(),
+		})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"agent_id":     agentID,
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+	}).Info("📖 READ STATE: Conversation marked as read")
+
+	return nil
+}
+
+// GetUnreadStates returns per-conversation unread flags and the total unread count
+// for one agent on one device
+func (s *ReadStateService) GetUnreadStates(agentID, idDevice string) ([]models.ConversationUnreadState, int, error) {
+	states, err := s.readStateRepo.GetUnreadStates(agentID, idDevice)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get unread states: %w", err)
+	}
+
+	unreadCount := 0
+	for _, state := range states {
+		if state.Unread {
+			unreadCount++
+		}
+	}
+
+	return states, unreadCount, nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Receipt OCR through the AI provider's vision models. The receipt image is
+// sent alongside an extraction prompt; the model reads the amount, payment
+// reference and date off the receipt and reports its own confidence, so
+// unreadable photos fall through to manual review instead of a wrong verdict
+
+// ReceiptExtraction is what the vision model read off a receipt image
+type ReceiptExtraction struct {
+	Amount     float64 `json:"amount"`
+	Reference  string  `json:"reference"`
+	Date       string  `json:"date"`
+	Confidence float64 `json:"confidence"`
+}
+
+// receiptOCRPrompt instructs the vision model to answer with bare JSON
+const receiptOCRPrompt = "You are reading a payment receipt image. Extract the total amount paid, " +
+	"the payment reference or transaction number, and the payment date.\n" +
+	"Respond with ONLY a JSON object and no other text:\n" +
+	"{\"amount\": <number>, \"reference\": \"<string>\", \"date\": \"<YYYY-MM-DD or empty>\", " +
+	"\"confidence\": <number between 0.0 and 1.0 for how clearly you could read the receipt>}"
+
+// receiptVisionContent is one part of a multimodal message - the plain
+// OpenRouterMessage only carries text, so receipt requests build their own
+type receiptVisionContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL *struct {
+		URL string `json:"url"`
+	} `json:"image_url,omitempty"`
+}
+
+type receiptVisionMessage struct {
+	Role    string                 `json:"role"`
+	Content []receiptVisionContent `json:"content"`
+}
+
+type receiptVisionRequest struct {
+	Model       string                 `json:"model"`
+	Messages    []receiptVisionMessage `json:"messages"`
+	Temperature float64                `json:"temperature"`
+}
+
+// ExtractReceipt OCRs a receipt image through the AI provider's vision model
+func (s *AIService) ExtractReceipt(imageURL, apiKey, deviceID string) (*ReceiptExtraction, error) {
+	apiKey = s.getAPIKey(apiKey, deviceID)
+	if apiKey == "" {
+		return nil, fmt.Errorf("no API key provided")
+	}
+
+	baseURL := s.getAPIURL(deviceID)
+	model := "openai/gpt-4o-mini"
+	if baseURL == openAIBaseURL {
+		model = "gpt-4o-mini"
+	}
+
+	request := receiptVisionRequest{
+		Model: model,
+		Messages: []receiptVisionMessage{{
+			Role: "user",
+			Content: []receiptVisionContent{
+				{Type: "text", Text: receiptOCRPrompt},
+				{Type: "image_url", ImageURL: &struct {
+					URL string `json:"url"`
+				}{URL: imageURL}},
+			},
+		}},
+		Temperature: 0, // Deterministic extraction, not creative text
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal receipt OCR request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create receipt OCR request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("receipt OCR request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt OCR response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("receipt OCR returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse receipt OCR response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return nil, fmt.Errorf("receipt OCR returned no choices")
+	}
+
+	content := strings.TrimSpace(parsed.Choices[0].Message.Content)
+	// Models occasionally wrap the JSON in code fences - cut them away
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var extraction ReceiptExtraction
+	if err := json.Unmarshal([]byte(content), &extraction); err != nil {
+		return nil, fmt.Errorf("failed to parse receipt extraction %q: %w", content, err)
+	}
+
+	if extraction.Confidence < 0 {
+		extraction.Confidence = 0
+	}
+	if extraction.Confidence > 1 {
+		extraction.Confidence = 1
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"amount":     extraction.Amount,
+		"reference":  extraction.Reference,
+		"date":       extraction.Date,
+		"confidence": extraction.Confidence,
+		"device_id":  deviceID,
+	}).Info("🧾 RECEIPT: Extracted receipt fields from image")
+
+	return &extraction, nil
+}
@@ -9,6 +9,8 @@ import (
 
 	"nodepath-chat/internal/config"
 
+	"nodepath-chat/internal/models"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
@@ -113,12 +115,29 @@ type QueueMessage struct {
 	ExecutionID string        `json:"execution_id,omitempty"`
 	NodeID      string        `json:"node_id,omitempty"`
 	Delay       time.Duration `json:"delay,omitempty"`
+	// Attempt numbers each scheduled continuation of an execution/node pair so
+	// its idempotency token is unique per legitimate visit (loops revisit the
+	// same delay node) while duplicates of one visit share a token
+	Attempt int `json:"attempt,omitempty"`
+	// OnReply is the delay node's policy when the user replies mid-delay:
+	// cancel (default), send, or queue
+	OnReply string `json:"on_reply,omitempty"`
 }
 
 const (
 	queueKeyOutbound = "queue:outbound"
 	queueKeyFailed   = "queue:failed"
 	queueKeyDelay    = "queue:delay"
+
+	// Continuation idempotency token keys and the per-node attempt counters
+	// behind them
+	continuationTokenPrefix   = "queue:continuation:"
+	continuationAttemptPrefix = "queue:continuation_attempt:"
+	continuationTokenTTL      = time.Hour
+
+	// continuationRequeueGrace is how far a queue-policy continuation is
+	// pushed back so the reply is handled first
+	continuationRequeueGrace = 5 * time.Second
 )
 
 // EnqueueOutboundMessage queues an outbound WhatsApp message with monitoring
@@ -292,12 +311,25 @@ func (s *QueueService) EnqueueDelayedMessage(message *QueueMessage) error {
 	message.MaxRetries = 3
 	message.Retries = 0
 
+	ctx := context.Background()
+
+	// Number this continuation so its idempotency token is unique per visit
+	if message.MessageType == "flow_continuation" {
+		attemptKey := fmt.Sprintf("%s%s:%s", continuationAttemptPrefix, message.ExecutionID, message.NodeID)
+		attempt, err := s.redis.Incr(ctx, attemptKey).Result()
+		if err != nil {
+			logrus.WithError(err).Warn("🕐 QUEUE: Failed to number continuation attempt")
+		} else {
+			message.Attempt = int(attempt)
+			s.redis.Expire(ctx, attemptKey, continuationTokenTTL)
+		}
+	}
+
 	messageJSON, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal delayed message: %w", err)
 	}
 
-	ctx := context.Background()
 	// Add to delay queue with score as timestamp
 	score := float64(message.ScheduledAt.Unix())
 	err = s.redis.ZAdd(ctx, queueKeyDelay, redis.Z{
@@ -349,9 +381,19 @@ func (s *QueueService) ProcessDelayedMessages() error {
 
 		// Handle flow continuation messages differently
 		if message.MessageType == "flow_continuation" {
-			// Process flow continuation directly
-			err = s.processFlowContinuation(&message)
-			if err != nil {
+			// The idempotency token makes continuation processing exactly-once:
+			// a duplicate of an already-claimed attempt is dropped from the queue
+			// without running the node again
+			if !s.claimContinuationToken(&message) {
+				logrus.WithFields(logrus.Fields{
+					"execution_id": message.ExecutionID,
+					"node_id":      message.NodeID,
+					"attempt":      message.Attempt,
+				}).Warn("🔄 QUEUE: Duplicate continuation detected, skipping")
+			} else if err = s.processFlowContinuation(&message); err != nil {
+				// Release the token so the retry on the next tick is not
+				// mistaken for a duplicate
+				s.releaseContinuationToken(&message)
 				logrus.WithError(err).Error("Failed to process flow continuation")
 				continue
 			}
@@ -412,6 +454,100 @@ func (s *QueueService) GetQueueStats() (map[string]int64, error) {
 	}, nil
 }
 
+// claimContinuationToken claims the idempotency token for one continuation
+// attempt; only the first claim succeeds
+func (s *QueueService) claimContinuationToken(message *QueueMessage) bool {
+	if s.redis == nil {
+		return true
+	}
+
+	ctx := context.Background()
+	claimed, err := s.redis.SetNX(ctx, continuationTokenKey(message), 1, continuationTokenTTL).Result()
+	if err != nil {
+		logrus.WithError(err).Warn("🔄 QUEUE: Failed to claim continuation token, processing anyway")
+		return true
+	}
+	return claimed
+}
+
+// releaseContinuationToken frees a claimed token after a failed attempt so
+// the retry is not treated as a duplicate
+func (s *QueueService) releaseContinuationToken(message *QueueMessage) {
+	if s.redis == nil {
+		return
+	}
+	if err := s.redis.Del(context.Background(), continuationTokenKey(message)).Err(); err != nil {
+		logrus.WithError(err).Warn("🔄 QUEUE: Failed to release continuation token")
+	}
+}
+
+func continuationTokenKey(message *QueueMessage) string {
+	return fmt.Sprintf("%s%s:%s:%d", continuationTokenPrefix, message.ExecutionID, message.NodeID, message.Attempt)
+}
+
+// CancelPendingContinuations applies each pending continuation's on-reply
+// policy for a conversation when the user replies mid-delay: cancel drops the
+// continuation, send leaves it scheduled, and queue postpones it until after
+// the reply is handled. Returns how many continuations were cancelled
+func (s *QueueService) CancelPendingContinuations(phoneNumber, deviceID string) int {
+	if s.redis == nil {
+		return 0
+	}
+
+	ctx := context.Background()
+	pending, err := s.redis.ZRange(ctx, queueKeyDelay, 0, -1).Result()
+	if err != nil {
+		logrus.WithError(err).Error("🔄 QUEUE: Failed to scan delay queue for cancellation")
+		return 0
+	}
+
+	cancelled := 0
+	for _, messageJSON := range pending {
+		var message QueueMessage
+		if err := json.Unmarshal([]byte(messageJSON), &message); err != nil {
+			continue
+		}
+		if message.MessageType != "flow_continuation" || message.PhoneNumber != phoneNumber || message.DeviceID != deviceID {
+			continue
+		}
+
+		switch message.OnReply {
+		case models.DelayOnReplySend:
+			// The node opted to fire as scheduled even after a reply
+			continue
+		case models.DelayOnReplyQueue:
+			// Push the continuation back so the reply is handled first
+			newScore := float64(time.Now().Add(continuationRequeueGrace).Unix())
+			if err := s.redis.ZAdd(ctx, queueKeyDelay, redis.Z{Score: newScore, Member: messageJSON}).Err(); err != nil {
+				logrus.WithError(err).Error("🔄 QUEUE: Failed to postpone pending continuation")
+			} else {
+				logrus.WithFields(logrus.Fields{
+					"execution_id": message.ExecutionID,
+					"node_id":      message.NodeID,
+				}).Info("🔄 QUEUE: Postponed pending continuation behind user reply")
+			}
+			continue
+		}
+
+		// Default policy: cancel
+		if err := s.redis.ZRem(ctx, queueKeyDelay, messageJSON).Err(); err != nil {
+			logrus.WithError(err).Error("🔄 QUEUE: Failed to cancel pending continuation")
+			continue
+		}
+		cancelled++
+	}
+
+	if cancelled > 0 {
+		logrus.WithFields(logrus.Fields{
+			"phone_number": phoneNumber,
+			"device_id":    deviceID,
+			"cancelled":    cancelled,
+		}).Info("🔄 QUEUE: Cancelled pending continuations superseded by user reply")
+	}
+
+	return cancelled
+}
+
 // processFlowContinuation processes a flow continuation message after delay
 func (s *QueueService) processFlowContinuation(message *QueueMessage) error {
 	logrus.WithFields(logrus.Fields{
@@ -477,3 +613,32 @@ func (s *QueueService) ClearFailedMessages() error {
 	logrus.Info("Failed message queue cleared")
 	return nil
 }
+
+// CacheGet reads a cached JSON blob by key. Returns false on a miss or when
+// Redis is unavailable
+func (s *QueueService) CacheGet(key string) ([]byte, bool) {
+	if s.redis == nil {
+		return nil, false
+	}
+
+	ctx := context.Background()
+	value, err := s.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// CacheSet stores a JSON blob under key with the given TTL. Failures are
+// logged and swallowed - a cold cache only costs a recomputation
+func (s *QueueService) CacheSet(key string, value []byte, ttl time.Duration) {
+	if s.redis == nil {
+		return
+	}
+
+	ctx := context.Background()
+	if err := s.redis.Set(ctx, key, value, ttl).Err(); err != nil {
+		logrus.WithError(err).WithField("key", key).Warn("Failed to write cache entry")
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// referralCodePattern matches an invite code inside an inbound message. Codes
+// use the same unambiguous charset as vouchers
+var referralCodePattern = regexp.MustCompile(`(?i)REF-([A-Z2-9]{6})`)
+
+// ReferralService issues per-prospect invite links and attributes new
+// conversations that open with a referral code
+type ReferralService struct {
+	referralRepo          repository.ReferralRepository
+	deviceSettingsService *DeviceSettingsService
+}
+
+// NewReferralService creates a new referral service
+func NewReferralService(referralRepo repository.ReferralRepository, deviceSettingsService *DeviceSettingsService) *ReferralService {
+	return &ReferralService{
+		referralRepo:          referralRepo,
+		deviceSettingsService: deviceSettingsService,
+	}
+}
+
+// GetOrCreateInviteLink returns the prospect's wa.me deep link, generating
+// their referral code on first use
+func (s *ReferralService) GetOrCreateInviteLink(idDevice, prospectNum string) (*models.Referral, error) {
+	referral, err := s.referralRepo.GetReferralByProspect(idDevice, prospectNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if referral == nil {
+		code, err := generateReferralCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate referral code: %w", err)
+		}
+
+		referral = &models.Referral{
+			ID:          uuid.New().String(),
+			IDDevice:    idDevice,
+			ProspectNum: prospectNum,
+			Code:        code,
+		}
+		if err := s.referralRepo.CreateReferral(referral); err != nil {
+			return nil, fmt.Errorf("failed to create referral: %w", err)
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"code":         code,
+		}).Info("🔗 REFERRAL: Invite code generated")
+	}
+
+	referral.InviteLink = s.buildInviteLink(idDevice, referral.Code)
+	return referral, nil
+}
+
+// buildInviteLink renders the wa.me deep link carrying the referral code
+func (s *ReferralService) buildInviteLink(idDevice, code string) string {
+	phone := ""
+	if s.deviceSettingsService != nil {
+		if device, err := s.deviceSettingsService.GetByIDDevice(idDevice); err == nil && device != nil && device.PhoneNumber.Valid {
+			phone = strings.Map(func(r rune) rune {
+				if r >= '0' && r <= '9' {
+					return r
+				}
+				return -1
+			}, device.PhoneNumber.String)
+		}
+	}
+
+	text := url.QueryEscape(fmt.Sprintf("Hi! REF-%s", code))
+	if phone == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://wa.me/%s?text=%s", phone, text)
+}
+
+// AttributeFromMessage credits a referrer when an inbound message carries a
+// referral code. Self-referrals and repeat messages are ignored
+func (s *ReferralService) AttributeFromMessage(idDevice, referredNum, content string) {
+	match := referralCodePattern.FindStringSubmatch(content)
+	if match == nil {
+		return
+	}
+	code := strings.ToUpper(match[1])
+
+	referral, err := s.referralRepo.GetReferralByCode(code)
+	if err != nil || referral == nil {
+		return
+	}
+	if referral.IDDevice != idDevice || referral.ProspectNum == referredNum {
+		return
+	}
+
+	if err := s.referralRepo.RecordAttribution(code, idDevice, referral.ProspectNum, referredNum); err != nil {
+		logrus.WithError(err).Error("🔗 REFERRAL: Failed to record attribution")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"code":         code,
+		"referrer_num": referral.ProspectNum,
+		"referred_num": referredNum,
+	}).Info("✅ REFERRAL: New conversation attributed to referrer")
+}
+
+// GetReferralCount returns how many conversations a prospect has referred
+func (s *ReferralService) GetReferralCount(idDevice, prospectNum string) (int, error) {
+	return s.referralRepo.CountReferrals(idDevice, prospectNum)
+}
+
+// GetLeaderboard returns the device's top referrers
+func (s *ReferralService) GetLeaderboard(idDevice string, limit int) ([]models.ReferralLeaderboardEntry, error) {
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return s.referralRepo.GetLeaderboard(idDevice, limit)
+}
+
+// generateReferralCode builds a short random code from the voucher charset
+func generateReferralCode() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for _, b := range buf {
+		builder.WriteByte(voucherCodeCharset[int(b)%len(voucherCodeCharset)])
+	}
+
+	return builder.String(), nil
+}
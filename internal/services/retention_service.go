@@ -0,0 +1,186 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RetentionService enforces per-device data retention policies: conversation
+// content past the configured age is purged by a scheduled background run
+// while aggregates (digests, stage counters) are kept. A dry-run preview
+// reports affected row counts without deleting anything
+
+// retentionCheckInterval is how often the purger wakes up
+const retentionCheckInterval = 6 * time.Hour
+
+// retentionMinDays is the shortest retention a policy may configure, so a
+// typo cannot wipe yesterday's conversations
+const retentionMinDays = 7
+
+// RetentionPurgeResult reports what one enforcement run removed
+type RetentionPurgeResult struct {
+	IDDevice            string `json:"id_device"`
+	ConversationLogs    int64  `json:"conversation_logs"`
+	ConversationBlobs   int64  `json:"conversation_blobs"`
+	CompletedExecutions int64  `json:"completed_executions"`
+}
+
+// RetentionService stores retention policies and runs the scheduled purger
+type RetentionService struct {
+	retentionRepo repository.RetentionRepository
+	done          chan struct{}
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(retentionRepo repository.RetentionRepository) *RetentionService {
+	return &RetentionService{
+		retentionRepo: retentionRepo,
+		done:          make(chan struct{}),
+	}
+}
+
+// GetPolicy returns a device's retention policy, or a disabled default when
+// never configured
+func (s *RetentionService) GetPolicy(idDevice string) (*models.RetentionPolicy, error) {
+	policy, err := s.retentionRepo.GetRetentionPolicy(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &models.RetentionPolicy{IDDevice: idDevice}
+	}
+	return policy, nil
+}
+
+// SavePolicy validates and stores a device's retention policy
+func (s *RetentionService) SavePolicy(policy *models.RetentionPolicy) error {
+	if policy.ConversationDays < 0 || policy.ExecutionDays < 0 {
+		return fmt.Errorf("retention days cannot be negative")
+	}
+	if policy.Enabled && policy.ConversationDays == 0 && policy.ExecutionDays == 0 {
+		return fmt.Errorf("an enabled policy needs at least one retention period")
+	}
+	if policy.ConversationDays > 0 && policy.ConversationDays < retentionMinDays {
+		return fmt.Errorf("conversation retention cannot be shorter than %d days", retentionMinDays)
+	}
+	if policy.ExecutionDays > 0 && policy.ExecutionDays < retentionMinDays {
+		return fmt.Errorf("execution retention cannot be shorter than %d days", retentionMinDays)
+	}
+
+	return s.retentionRepo.SetRetentionPolicy(policy)
+}
+
+// Preview reports how many rows enforcing the device's policy right now
+// would touch, without deleting anything
+func (s *RetentionService) Preview(idDevice string) (*models.RetentionPreview, error) {
+	policy, err := s.GetPolicy(idDevice)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.RetentionPreview{IDDevice: idDevice}
+	now := time.Now()
+
+	if policy.ConversationDays > 0 {
+		cutoff := now.AddDate(0, 0, -policy.ConversationDays)
+		if count, err := s.retentionRepo.CountOldConversationLogs(idDevice, cutoff); err == nil {
+			preview.ConversationLogs = count
+		}
+		if count, err := s.retentionRepo.CountOldConversationBlobs(idDevice, cutoff); err == nil {
+			preview.ConversationBlobs = count
+		}
+	}
+	if policy.ExecutionDays > 0 {
+		cutoff := now.AddDate(0, 0, -policy.ExecutionDays)
+		if count, err := s.retentionRepo.CountOldCompletedExecutions(idDevice, cutoff); err == nil {
+			preview.CompletedExecutions = count
+		}
+	}
+
+	return preview, nil
+}
+
+// Enforce runs the device's policy immediately and reports what was removed
+func (s *RetentionService) Enforce(idDevice string) (*RetentionPurgeResult, error) {
+	policy, err := s.GetPolicy(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Enabled {
+		return nil, fmt.Errorf("retention policy is not enabled for device %s", idDevice)
+	}
+	return s.enforcePolicy(policy), nil
+}
+
+// enforcePolicy purges one device according to its policy
+func (s *RetentionService) enforcePolicy(policy *models.RetentionPolicy) *RetentionPurgeResult {
+	result := &RetentionPurgeResult{IDDevice: policy.IDDevice}
+	now := time.Now()
+
+	if policy.ConversationDays > 0 {
+		cutoff := now.AddDate(0, 0, -policy.ConversationDays)
+		if removed, err := s.retentionRepo.DeleteOldConversationLogs(policy.IDDevice, cutoff); err == nil {
+			result.ConversationLogs = removed
+		}
+		if cleared, err := s.retentionRepo.ClearOldConversationBlobs(policy.IDDevice, cutoff); err == nil {
+			result.ConversationBlobs = cleared
+		}
+	}
+	if policy.ExecutionDays > 0 {
+		cutoff := now.AddDate(0, 0, -policy.ExecutionDays)
+		if removed, err := s.retentionRepo.DeleteOldCompletedExecutions(policy.IDDevice, cutoff); err == nil {
+			result.CompletedExecutions = removed
+		}
+	}
+
+	if result.ConversationLogs > 0 || result.ConversationBlobs > 0 || result.CompletedExecutions > 0 {
+		logrus.WithFields(logrus.Fields{
+			"id_device":            policy.IDDevice,
+			"conversation_logs":    result.ConversationLogs,
+			"conversation_blobs":   result.ConversationBlobs,
+			"completed_executions": result.CompletedExecutions,
+		}).Info("🗑️ RETENTION: Purged expired conversation data")
+	}
+
+	return result
+}
+
+// StartPurger runs scheduled retention enforcement until Stop is called.
+// Call in a goroutine
+func (s *RetentionService) StartPurger() {
+	logrus.Info("🗑️ RETENTION: Purger started")
+	ticker := time.NewTicker(retentionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.runAll()
+		}
+	}
+}
+
+// Stop stops the purger
+func (s *RetentionService) Stop() {
+	close(s.done)
+}
+
+// runAll enforces every enabled policy
+func (s *RetentionService) runAll() {
+	policies, err := s.retentionRepo.GetEnabledRetentionPolicies()
+	if err != nil {
+		logrus.WithError(err).Error("🗑️ RETENTION: Failed to load policies for scheduled run")
+		return
+	}
+
+	for i := range policies {
+		s.enforcePolicy(&policies[i])
+	}
+}
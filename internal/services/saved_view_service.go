@@ -0,0 +1,121 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SavedViewService manages per-user saved filters/views for the inbox and
+// prospect lists: named filter+sort+column presets, a default per scope, and
+// views shared with everyone working the same device
+
+// savedViewMaxJSONLength bounds stored filter and column definitions
+const savedViewMaxJSONLength = 4096
+
+// SavedViewService manages saved list views
+type SavedViewService struct {
+	savedViewRepo repository.SavedViewRepository
+}
+
+// NewSavedViewService creates a new saved view service
+func NewSavedViewService(savedViewRepo repository.SavedViewRepository) *SavedViewService {
+	return &SavedViewService{savedViewRepo: savedViewRepo}
+}
+
+// validateView checks a view definition before it is stored
+func validateView(view *models.SavedView) error {
+	if view.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if view.Scope != models.SavedViewScopeInbox && view.Scope != models.SavedViewScopeProspects {
+		return fmt.Errorf("scope must be %q or %q", models.SavedViewScopeInbox, models.SavedViewScopeProspects)
+	}
+	if view.Shared && view.IDDevice == "" {
+		return fmt.Errorf("id_device is required for shared views")
+	}
+	for field, value := range map[string]string{"filters": view.Filters, "columns": view.Columns} {
+		if value == "" {
+			continue
+		}
+		if len(value) > savedViewMaxJSONLength {
+			return fmt.Errorf("%s definition too long", field)
+		}
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("%s must be valid JSON", field)
+		}
+	}
+	return nil
+}
+
+// Create stores a new view for the user
+func (s *SavedViewService) Create(view *models.SavedView) error {
+	if err := validateView(view); err != nil {
+		return err
+	}
+
+	if err := s.savedViewRepo.CreateView(view); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"view_id": view.ID,
+		"user_id": view.UserID,
+		"scope":   view.Scope,
+		"name":    view.Name,
+		"shared":  view.Shared,
+	}).Info("📋 VIEWS: Saved view created")
+
+	return nil
+}
+
+// Update changes an owned view's definition. Scope and device binding are
+// fixed at creation
+func (s *SavedViewService) Update(view *models.SavedView) error {
+	existing, err := s.savedViewRepo.GetView(view.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil || existing.UserID != view.UserID {
+		return fmt.Errorf("view not found")
+	}
+
+	view.Scope = existing.Scope
+	view.IDDevice = existing.IDDevice
+	if err := validateView(view); err != nil {
+		return err
+	}
+
+	return s.savedViewRepo.UpdateView(view)
+}
+
+// Delete removes an owned view
+func (s *SavedViewService) Delete(id int, userID string) error {
+	return s.savedViewRepo.DeleteView(id, userID)
+}
+
+// List returns the user's views plus views shared to the device, optionally
+// filtered by scope
+func (s *SavedViewService) List(userID, scope, idDevice string) ([]models.SavedView, error) {
+	if scope != "" && scope != models.SavedViewScopeInbox && scope != models.SavedViewScopeProspects {
+		return nil, fmt.Errorf("scope must be %q or %q", models.SavedViewScopeInbox, models.SavedViewScopeProspects)
+	}
+	return s.savedViewRepo.ListViews(userID, scope, idDevice)
+}
+
+// SetDefault makes one owned view the user's default for its scope
+func (s *SavedViewService) SetDefault(id int, userID string) error {
+	view, err := s.savedViewRepo.GetView(id)
+	if err != nil {
+		return err
+	}
+	if view == nil || view.UserID != userID {
+		return fmt.Errorf("view not found")
+	}
+
+	return s.savedViewRepo.SetDefaultView(id, userID, view.Scope)
+}
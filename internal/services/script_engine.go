@@ -0,0 +1,588 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The script node runs short user-provided programs against the execution
+// variables, for transformations too awkward for the edge-condition
+// expression language. Scripts run in an in-house interpreter rather than an
+// embedded JS/Lua VM, which keeps the sandbox airtight by construction: no
+// loops, no network, no filesystem, a fixed stdlib and hard step/size
+// budgets.
+//
+// A script is a sequence of assignments, one per line (or ; separated):
+//
+//	greeting = "Hi " + upper(trim(name))
+//	total = number(qty) * 2.5
+//	output = if(contains(input, "price"), "RM" + text(total), greeting)
+//
+// `input` is the user's message, every other identifier reads or writes an
+// execution variable, and whatever ends up in `output` is sent as the reply
+
+// scriptMaxLength caps the program source size
+const scriptMaxLength = 2000
+
+// scriptMaxStatements caps how many assignments one script may run
+const scriptMaxStatements = 50
+
+// scriptMaxSteps caps evaluation work across the whole script
+const scriptMaxSteps = 2000
+
+// scriptMaxValueLength caps any string a script can build
+const scriptMaxValueLength = 4096
+
+// scriptOutputVariable is the variable whose final value becomes the reply
+const scriptOutputVariable = "output"
+
+// RunFlowScript executes a script and returns the updated variables plus the
+// output text. The input variables map is not mutated
+func RunFlowScript(script, userInput string, variables map[string]interface{}) (map[string]interface{}, string, error) {
+	if len(script) > scriptMaxLength {
+		return nil, "", fmt.Errorf("script exceeds %d characters", scriptMaxLength)
+	}
+
+	locals := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		locals[name] = value
+	}
+
+	runner := &scriptRunner{userInput: userInput, locals: locals}
+
+	statements := splitScriptStatements(script)
+	if len(statements) > scriptMaxStatements {
+		return nil, "", fmt.Errorf("script exceeds %d statements", scriptMaxStatements)
+	}
+
+	for lineNo, statement := range statements {
+		if err := runner.runStatement(statement); err != nil {
+			return nil, "", fmt.Errorf("statement %d: %w", lineNo+1, err)
+		}
+	}
+
+	output := ""
+	if value, ok := locals[scriptOutputVariable]; ok {
+		output = scriptToString(value)
+		delete(locals, scriptOutputVariable)
+	}
+
+	return locals, output, nil
+}
+
+// splitScriptStatements splits on newlines and semicolons, dropping blanks
+// and # comments
+func splitScriptStatements(script string) []string {
+	var statements []string
+	for _, line := range strings.FieldsFunc(script, func(r rune) bool { return r == '\n' || r == ';' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		statements = append(statements, line)
+	}
+	return statements
+}
+
+// scriptRunner holds one execution's state and budget
+type scriptRunner struct {
+	userInput string
+	locals    map[string]interface{}
+	steps     int
+}
+
+// runStatement executes one `name = expression` assignment
+func (r *scriptRunner) runStatement(statement string) error {
+	equals := strings.Index(statement, "=")
+	// Skip comparison operators that start with '='
+	for equals > 0 && equals+1 < len(statement) && statement[equals+1] == '=' {
+		next := strings.Index(statement[equals+2:], "=")
+		if next < 0 {
+			equals = -1
+			break
+		}
+		equals = equals + 2 + next
+	}
+	if equals <= 0 {
+		return fmt.Errorf("expected an assignment (name = expression)")
+	}
+
+	name := strings.TrimSpace(statement[:equals])
+	if !isScriptIdent(name) || name == "input" {
+		return fmt.Errorf("cannot assign to %q", name)
+	}
+
+	tokens, err := tokenizeScript(statement[equals+1:])
+	if err != nil {
+		return err
+	}
+
+	parser := &scriptParser{runner: r, tokens: tokens}
+	value, err := parser.parseComparison()
+	if err != nil {
+		return err
+	}
+	if !parser.atEnd() {
+		return fmt.Errorf("unexpected %q", parser.peek().text)
+	}
+
+	r.locals[name] = value
+	return nil
+}
+
+// step charges one unit of evaluation budget
+func (r *scriptRunner) step() error {
+	r.steps++
+	if r.steps > scriptMaxSteps {
+		return fmt.Errorf("script exceeded its execution budget")
+	}
+	return nil
+}
+
+// resolve reads an identifier: the user input or an execution variable
+func (r *scriptRunner) resolve(name string) interface{} {
+	if name == "input" {
+		return r.userInput
+	}
+	if value, ok := r.locals[name]; ok {
+		return value
+	}
+	return ""
+}
+
+type scriptToken struct {
+	kind string // ident, number, string, op
+	text string
+}
+
+// tokenizeScript scans one expression into tokens
+func tokenizeScript(source string) ([]scriptToken, error) {
+	var tokens []scriptToken
+	runes := []rune(source)
+	i := 0
+
+	for i < len(runes) {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			quote := c
+			start := i + 1
+			i++
+			for i < len(runes) && runes[i] != quote {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, scriptToken{kind: "string", text: string(runes[start:i])})
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(runes) && (runes[i] >= '0' && runes[i] <= '9' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, scriptToken{kind: "number", text: string(runes[start:i])})
+		case isScriptIdentRune(c):
+			start := i
+			for i < len(runes) && (isScriptIdentRune(runes[i]) || runes[i] >= '0' && runes[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, scriptToken{kind: "ident", text: string(runes[start:i])})
+		case strings.ContainsRune("=!<>", c):
+			op := string(c)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+				i++
+			}
+			if op == "=" || op == "!" {
+				return nil, fmt.Errorf("unexpected %q", op)
+			}
+			tokens = append(tokens, scriptToken{kind: "op", text: op})
+			i++
+		case strings.ContainsRune("+-*/(),", c):
+			tokens = append(tokens, scriptToken{kind: "op", text: string(c)})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q", string(c))
+		}
+	}
+
+	return tokens, nil
+}
+
+func isScriptIdent(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if !isScriptIdentRune(c) && !(i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isScriptIdentRune(c rune) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// scriptParser is a recursive-descent evaluator over the token stream
+type scriptParser struct {
+	runner *scriptRunner
+	tokens []scriptToken
+	pos    int
+}
+
+func (p *scriptParser) atEnd() bool { return p.pos >= len(p.tokens) }
+
+func (p *scriptParser) peek() scriptToken {
+	if p.atEnd() {
+		return scriptToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *scriptParser) acceptOp(ops ...string) (string, bool) {
+	token := p.peek()
+	if token.kind != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if token.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// parseComparison handles ==, !=, <, >, <=, >=
+func (p *scriptParser) parseComparison() (interface{}, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := p.acceptOp("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.runner.step(); err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "==":
+		return scriptToString(left) == scriptToString(right), nil
+	case "!=":
+		return scriptToString(left) != scriptToString(right), nil
+	}
+
+	leftNum, rightNum := scriptToNumber(left), scriptToNumber(right)
+	switch op {
+	case "<":
+		return leftNum < rightNum, nil
+	case ">":
+		return leftNum > rightNum, nil
+	case "<=":
+		return leftNum <= rightNum, nil
+	default:
+		return leftNum >= rightNum, nil
+	}
+}
+
+// parseAdditive handles + (numeric add or string concat) and -
+func (p *scriptParser) parseAdditive() (interface{}, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.runner.step(); err != nil {
+			return nil, err
+		}
+
+		if op == "-" {
+			left = scriptToNumber(left) - scriptToNumber(right)
+			continue
+		}
+
+		_, leftIsNum := left.(float64)
+		_, rightIsNum := right.(float64)
+		if leftIsNum && rightIsNum {
+			left = left.(float64) + right.(float64)
+			continue
+		}
+
+		joined := scriptToString(left) + scriptToString(right)
+		if len(joined) > scriptMaxValueLength {
+			return nil, fmt.Errorf("string exceeds %d characters", scriptMaxValueLength)
+		}
+		left = joined
+	}
+}
+
+// parseMultiplicative handles * and /
+func (p *scriptParser) parseMultiplicative() (interface{}, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptOp("*", "/")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.runner.step(); err != nil {
+			return nil, err
+		}
+
+		if op == "*" {
+			left = scriptToNumber(left) * scriptToNumber(right)
+			continue
+		}
+
+		divisor := scriptToNumber(right)
+		if divisor == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		left = scriptToNumber(left) / divisor
+	}
+}
+
+// parseUnary handles leading minus
+func (p *scriptParser) parseUnary() (interface{}, error) {
+	if _, ok := p.acceptOp("-"); ok {
+		value, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return -scriptToNumber(value), nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary handles literals, identifiers, calls and parentheses
+func (p *scriptParser) parsePrimary() (interface{}, error) {
+	if err := p.runner.step(); err != nil {
+		return nil, err
+	}
+
+	token := p.peek()
+	switch token.kind {
+	case "string":
+		p.pos++
+		return token.text, nil
+	case "number":
+		p.pos++
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", token.text)
+		}
+		return value, nil
+	case "ident":
+		p.pos++
+		if _, ok := p.acceptOp("("); ok {
+			return p.parseCall(token.text)
+		}
+		switch token.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return p.runner.resolve(token.text), nil
+	case "op":
+		if token.text == "(" {
+			p.pos++
+			value, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.acceptOp(")"); !ok {
+				return nil, fmt.Errorf("expected )")
+			}
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %q", token.text)
+}
+
+// parseCall evaluates a stdlib function call after its ( was consumed
+func (p *scriptParser) parseCall(name string) (interface{}, error) {
+	var args []interface{}
+	if _, ok := p.acceptOp(")"); !ok {
+		for {
+			arg, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.acceptOp(","); ok {
+				continue
+			}
+			if _, ok := p.acceptOp(")"); ok {
+				break
+			}
+			return nil, fmt.Errorf("expected , or ) in call to %s", name)
+		}
+	}
+
+	return callScriptBuiltin(name, args)
+}
+
+// callScriptBuiltin dispatches the fixed stdlib
+func callScriptBuiltin(name string, args []interface{}) (interface{}, error) {
+	argString := func(i int) string { return scriptToString(args[i]) }
+
+	switch name {
+	case "upper":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("upper takes 1 argument")
+		}
+		return strings.ToUpper(argString(0)), nil
+	case "lower":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("lower takes 1 argument")
+		}
+		return strings.ToLower(argString(0)), nil
+	case "trim":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("trim takes 1 argument")
+		}
+		return strings.TrimSpace(argString(0)), nil
+	case "len":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("len takes 1 argument")
+		}
+		return float64(len([]rune(argString(0)))), nil
+	case "contains":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("contains takes 2 arguments")
+		}
+		return strings.Contains(strings.ToLower(argString(0)), strings.ToLower(argString(1))), nil
+	case "replace":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("replace takes 3 arguments")
+		}
+		replaced := strings.ReplaceAll(argString(0), argString(1), argString(2))
+		if len(replaced) > scriptMaxValueLength {
+			return nil, fmt.Errorf("string exceeds %d characters", scriptMaxValueLength)
+		}
+		return replaced, nil
+	case "substr":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("substr takes 3 arguments")
+		}
+		runes := []rune(argString(0))
+		start, length := int(scriptToNumber(args[1])), int(scriptToNumber(args[2]))
+		if start < 0 {
+			start = 0
+		}
+		if start > len(runes) {
+			start = len(runes)
+		}
+		end := start + length
+		if length < 0 || end > len(runes) {
+			end = len(runes)
+		}
+		return string(runes[start:end]), nil
+	case "number":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("number takes 1 argument")
+		}
+		return scriptToNumber(args[0]), nil
+	case "text":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("text takes 1 argument")
+		}
+		return scriptToString(args[0]), nil
+	case "if":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("if takes 3 arguments")
+		}
+		if scriptToBool(args[0]) {
+			return args[1], nil
+		}
+		return args[2], nil
+	default:
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+}
+
+// scriptToString renders a value the way flows expect: numbers without a
+// trailing .0, booleans as true/false
+func scriptToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// scriptToNumber coerces a value to a number, non-numeric strings become 0
+func scriptToNumber(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0
+		}
+		return parsed
+	default:
+		return 0
+	}
+}
+
+// scriptToBool coerces a value to a condition result
+func scriptToBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != "" && !strings.EqualFold(v, "false")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ScriptService gates the sandboxed script engine behind a per-flow
+// permission: script nodes only run in flows an operator has explicitly
+// enabled them for
+type ScriptService struct {
+	permissionRepo repository.ScriptPermissionRepository
+}
+
+// NewScriptService creates a new script service
+func NewScriptService(permissionRepo repository.ScriptPermissionRepository) *ScriptService {
+	return &ScriptService{permissionRepo: permissionRepo}
+}
+
+// IsFlowScriptEnabled reports whether script nodes may run in the flow
+func (s *ScriptService) IsFlowScriptEnabled(flowID string) bool {
+	enabled, err := s.permissionRepo.GetScriptPermission(flowID)
+	if err != nil {
+		return false
+	}
+	return enabled
+}
+
+// SetFlowScriptEnabled grants or revokes script execution for a flow
+func (s *ScriptService) SetFlowScriptEnabled(flowID string, enabled bool) error {
+	if err := s.permissionRepo.SetScriptPermission(flowID, enabled); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id": flowID,
+		"enabled": enabled,
+	}).Info("📜 SCRIPT: Flow permission updated")
+
+	return nil
+}
+
+// Run executes a script node's program in the sandbox
+func (s *ScriptService) Run(script, userInput string, variables map[string]interface{}) (map[string]interface{}, string, error) {
+	return RunFlowScript(script, userInput, variables)
+}
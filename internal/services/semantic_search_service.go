@@ -0,0 +1,235 @@
+package services
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SemanticSearchService ranks conversations by meaning instead of keywords:
+// conversation history is chunked and embedded into
+// conversation_embedding_nodepath, and a query is embedded the same way and
+// ranked by cosine similarity. This finds "customer confused about pricing"
+// even when no message contains those words
+
+// semanticChunkLines is how many consecutive messages go into one embedded
+// chunk - large enough for context, small enough that one topic dominates
+const semanticChunkLines = 12
+
+// semanticMinSimilarity drops results too far from the query to be useful
+const semanticMinSimilarity = 0.2
+
+// SemanticSearchService indexes and searches conversation embeddings
+type SemanticSearchService struct {
+	embeddingRepo         repository.ConversationEmbeddingRepository
+	aiRepo                repository.AIWhatsappRepository
+	aiService             *AIService
+	deviceSettingsService *DeviceSettingsService
+}
+
+// NewSemanticSearchService creates a new semantic search service
+func NewSemanticSearchService(
+	embeddingRepo repository.ConversationEmbeddingRepository,
+	aiRepo repository.AIWhatsappRepository,
+	aiService *AIService,
+	deviceSettingsService *DeviceSettingsService,
+) *SemanticSearchService {
+	return &SemanticSearchService{
+		embeddingRepo:         embeddingRepo,
+		aiRepo:                aiRepo,
+		aiService:             aiService,
+		deviceSettingsService: deviceSettingsService,
+	}
+}
+
+// ReindexDevice (re)embeds every conversation on the device, skipping
+// chunks whose content has not changed since the last run. Returns how many
+// chunks were embedded
+func (s *SemanticSearchService) ReindexDevice(deviceID string) (int, error) {
+	apiKey := s.deviceAPIKey(deviceID)
+	if apiKey == "" {
+		return 0, fmt.Errorf("device has no API key configured")
+	}
+
+	conversations, err := s.aiRepo.GetAIWhatsappByDevice(deviceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load conversations for indexing: %w", err)
+	}
+
+	indexed := 0
+	for _, conversation := range conversations {
+		if !conversation.ConvLast.Valid || strings.TrimSpace(conversation.ConvLast.String) == "" {
+			continue
+		}
+
+		chunks := chunkConversation(conversation.ConvLast.String)
+		storedHashes, err := s.embeddingRepo.GetHashes(deviceID, conversation.ProspectNum)
+		if err != nil {
+			logrus.WithError(err).Warn("🧠 SEMANTIC: Failed to load stored hashes, reindexing all chunks")
+			storedHashes = make(map[int]string)
+		}
+
+		for chunkIndex, chunk := range chunks {
+			hash := chunkHash(chunk)
+			if storedHashes[chunkIndex] == hash {
+				continue
+			}
+
+			vector, err := s.aiService.EmbedText(chunk, apiKey, deviceID)
+			if err != nil {
+				return indexed, fmt.Errorf("failed to embed conversation chunk: %w", err)
+			}
+
+			embedding := &models.ConversationEmbedding{
+				IDDevice:    deviceID,
+				ProspectNum: conversation.ProspectNum,
+				ChunkIndex:  chunkIndex,
+				ContentHash: hash,
+				Snippet:     chunk,
+				Embedding:   vector,
+			}
+			if err := s.embeddingRepo.Upsert(embedding); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+
+		// Drop chunks past the end for conversations that were trimmed
+		if err := s.embeddingRepo.DeleteFromChunk(deviceID, conversation.ProspectNum, len(chunks)); err != nil {
+			logrus.WithError(err).Warn("🧠 SEMANTIC: Failed to prune stale chunks")
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"device_id":     deviceID,
+		"conversations": len(conversations),
+		"indexed":       indexed,
+	}).Info("🧠 SEMANTIC: Reindexed device conversations")
+
+	return indexed, nil
+}
+
+// Search embeds the query and returns the device's closest conversation
+// chunks, best match first. Each conversation appears once, represented by
+// its best-matching chunk
+func (s *SemanticSearchService) Search(deviceID, query string, limit int) ([]models.SemanticSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	apiKey := s.deviceAPIKey(deviceID)
+	if apiKey == "" {
+		return nil, fmt.Errorf("device has no API key configured")
+	}
+
+	queryVector, err := s.aiService.EmbedText(query, apiKey, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed search query: %w", err)
+	}
+
+	embeddings, err := s.embeddingRepo.ListByDevice(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep only the best chunk per conversation
+	best := make(map[string]models.SemanticSearchResult)
+	for _, embedding := range embeddings {
+		similarity := cosineSimilarity(queryVector, embedding.Embedding)
+		if similarity < semanticMinSimilarity {
+			continue
+		}
+		if existing, ok := best[embedding.ProspectNum]; ok && existing.Similarity >= similarity {
+			continue
+		}
+		best[embedding.ProspectNum] = models.SemanticSearchResult{
+			ProspectNum: embedding.ProspectNum,
+			Snippet:     embedding.Snippet,
+			Similarity:  similarity,
+			UpdatedAt:   embedding.UpdatedAt,
+		}
+	}
+
+	results := make([]models.SemanticSearchResult, 0, len(best))
+	for _, result := range best {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Similarity > results[j].Similarity
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// deviceAPIKey returns the device's AI provider key, or empty when unset
+func (s *SemanticSearchService) deviceAPIKey(deviceID string) string {
+	deviceSettings, err := s.deviceSettingsService.GetByIDDevice(deviceID)
+	if err != nil || deviceSettings == nil || !deviceSettings.APIKey.Valid {
+		return ""
+	}
+	return deviceSettings.APIKey.String
+}
+
+// chunkConversation splits a conversation's message lines into fixed-size
+// windows of consecutive messages
+func chunkConversation(convLast string) []string {
+	var lines []string
+	for _, line := range strings.Split(convLast, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var chunks []string
+	for start := 0; start < len(lines); start += semanticChunkLines {
+		end := start + semanticChunkLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+		chunks = append(chunks, strings.Join(lines[start:end], "\n"))
+	}
+	return chunks
+}
+
+// chunkHash fingerprints a chunk's content so unchanged chunks skip re-embedding
+func chunkHash(chunk string) string {
+	hash := md5.Sum([]byte(chunk))
+	return hex.EncodeToString(hash[:])
+}
+
+// cosineSimilarity measures how close two embedding vectors point
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const shortLinkCodeLength = 7
+
+// shortLinkURLPattern finds URLs embedded in outbound message text
+var shortLinkURLPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// Short link resolution errors
+var (
+	ErrShortLinkNotFound = errors.New("short link not found")
+	ErrShortLinkExpired  = errors.New("short link expired")
+)
+
+// ShortLinkService shortens long media/CDN URLs into /s/:code redirects with
+// per-link click counting and optional expiry
+type ShortLinkService struct {
+	shortLinkRepo repository.ShortLinkRepository
+}
+
+// NewShortLinkService creates a new short link service
+func NewShortLinkService(shortLinkRepo repository.ShortLinkRepository) *ShortLinkService {
+	return &ShortLinkService{shortLinkRepo: shortLinkRepo}
+}
+
+// CreateShortLink shortens a URL, reusing an existing non-expired link for the
+// same target. prospectNum attributes the link to a conversation so clicks can
+// trigger that prospect's flow (empty for device-level links); expiryDays of 0
+// means the link never expires
+func (s *ShortLinkService) CreateShortLink(idDevice, prospectNum, targetURL string, expiryDays int) (*models.ShortLink, error) {
+	targetURL = strings.TrimSpace(targetURL)
+	parsed, err := url.Parse(targetURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("target_url must be a valid http(s) URL")
+	}
+
+	if existing, err := s.shortLinkRepo.GetShortLinkByTarget(idDevice, prospectNum, targetURL); err == nil && existing != nil && !existing.IsExpired() {
+		existing.ShortURL = s.shortURL(existing.Code)
+		return existing, nil
+	}
+
+	code, err := generateShortLinkCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate short link code: %w", err)
+	}
+
+	link := &models.ShortLink{
+		ID:          uuid.New().String(),
+		IDDevice:    idDevice,
+		ProspectNum: prospectNum,
+		Code:        code,
+		TargetURL:   targetURL,
+	}
+	if expiryDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, expiryDays)
+		link.ExpiresAt = &expiresAt
+	}
+
+	if err := s.shortLinkRepo.CreateShortLink(link); err != nil {
+		return nil, fmt.Errorf("failed to create short link: %w", err)
+	}
+
+	link.ShortURL = s.shortURL(link.Code)
+
+	logrus.WithFields(logrus.Fields{
+		"code":      link.Code,
+		"id_device": idDevice,
+	}).Info("🔗 SHORTLINK: Link created")
+
+	return link, nil
+}
+
+// Resolve returns the link behind a code and counts the click. The caller can
+// use the link's prospect attribution to trigger flow events
+func (s *ShortLinkService) Resolve(code string) (*models.ShortLink, error) {
+	link, err := s.shortLinkRepo.GetShortLinkByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, ErrShortLinkNotFound
+	}
+	if link.IsExpired() {
+		return nil, ErrShortLinkExpired
+	}
+
+	if err := s.shortLinkRepo.IncrementClickCount(link.ID); err != nil {
+		logrus.WithError(err).Error("🔗 SHORTLINK: Failed to count click")
+	}
+
+	return link, nil
+}
+
+// GetLinks returns a device's short links with their public URLs
+func (s *ShortLinkService) GetLinks(idDevice string) ([]models.ShortLink, error) {
+	links, err := s.shortLinkRepo.GetShortLinksByDevice(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		links[i].ShortURL = s.shortURL(links[i].Code)
+	}
+	return links, nil
+}
+
+// DeleteLink removes a short link, scoped to the owning device
+func (s *ShortLinkService) DeleteLink(id, idDevice string) error {
+	return s.shortLinkRepo.DeleteShortLink(id, idDevice)
+}
+
+// GetAutoShorten returns the per-device outbound shortening toggle
+func (s *ShortLinkService) GetAutoShorten(idDevice string) (bool, error) {
+	return s.shortLinkRepo.GetAutoShorten(idDevice)
+}
+
+// SetAutoShorten sets the per-device outbound shortening toggle
+func (s *ShortLinkService) SetAutoShorten(idDevice string, enabled bool) error {
+	return s.shortLinkRepo.SetAutoShorten(idDevice, enabled)
+}
+
+// ShortenMessageURLs rewrites every URL in an outbound message with a short
+// link attributed to the receiving prospect when the device has
+// auto-shortening enabled. On any failure the original message is returned
+// unchanged so sending is never blocked
+func (s *ShortLinkService) ShortenMessageURLs(idDevice, prospectNum, message string) string {
+	enabled, err := s.shortLinkRepo.GetAutoShorten(idDevice)
+	if err != nil || !enabled {
+		return message
+	}
+
+	base := s.baseURL()
+	return shortLinkURLPattern.ReplaceAllStringFunc(message, func(match string) string {
+		// Already one of ours — leave it alone
+		if strings.HasPrefix(match, base+"/s/") {
+			return match
+		}
+
+		link, err := s.CreateShortLink(idDevice, prospectNum, match, 0)
+		if err != nil {
+			logrus.WithError(err).Warn("🔗 SHORTLINK: Failed to shorten outbound URL")
+			return match
+		}
+		return link.ShortURL
+	})
+}
+
+// shortURL renders the public redirect URL for a code
+func (s *ShortLinkService) shortURL(code string) string {
+	return fmt.Sprintf("%s/s/%s", s.baseURL(), code)
+}
+
+// baseURL resolves the public base URL the same way billing callbacks do:
+// BASE_URL (set in Railway) > RAILWAY_PUBLIC_DOMAIN > localhost
+func (s *ShortLinkService) baseURL() string {
+	if baseURL := os.Getenv("BASE_URL"); baseURL != "" {
+		return strings.TrimRight(baseURL, "/")
+	}
+	if domain := os.Getenv("RAILWAY_PUBLIC_DOMAIN"); domain != "" {
+		return "https://" + domain
+	}
+	return "http://localhost:8080"
+}
+
+// generateShortLinkCode builds a random URL-safe code
+func generateShortLinkCode() (string, error) {
+	buf := make([]byte, shortLinkCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for _, b := range buf {
+		builder.WriteByte(voucherCodeCharset[int(b)%len(voucherCodeCharset)])
+	}
+
+	return strings.ToLower(builder.String()), nil
+}
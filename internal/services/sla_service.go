@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SLAMessageSender sends a message to a prospect, used for apology escalations
+type SLAMessageSender interface {
+	SendMessageFromDevice(deviceID, phoneNumber, message string) error
+}
+
+// SLAService enforces first-response SLAs on human-handoff conversations.
+// A background monitor detects breaches and runs the configured escalation
+// action (notify manager, reassign or send an apology message)
+type SLAService struct {
+	slaRepo             repository.SLARepository
+	assignmentService   *AssignmentService
+	websocketService    *WebSocketService
+	messageSender       SLAMessageSender
+	notificationService *NotificationConnectorService
+	done                chan struct{}
+}
+
+// NewSLAService creates a new SLA service
+func NewSLAService(slaRepo repository.SLARepository, assignmentService *AssignmentService, websocketService *WebSocketService) *SLAService {
+	return &SLAService{
+		slaRepo:           slaRepo,
+		assignmentService: assignmentService,
+		websocketService:  websocketService,
+		done:              make(chan struct{}),
+	}
+}
+
+// SetMessageSender sets the sender used for apology-message escalations
+func (s *SLAService) SetMessageSender(sender SLAMessageSender) {
+	s.messageSender = sender
+}
+
+// SetNotificationService wires breach alerts into Slack/Discord connectors
+func (s *SLAService) SetNotificationService(notificationService *NotificationConnectorService) {
+	s.notificationService = notificationService
+}
+
+// StartMonitor runs the breach monitor until Stop is called. Call in a goroutine
+func (s *SLAService) StartMonitor() {
+	logrus.Info("⏱️ SLA: Breach monitor started")
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.checkBreaches()
+		}
+	}
+}
+
+// Stop stops the breach monitor
+func (s *SLAService) Stop() {
+	close(s.done)
+}
+
+// RecordHandoff starts the SLA clock for a new handoff when the device has an active policy
+func (s *SLAService) RecordHandoff(prospectNum, idDevice string) {
+	policy, err := s.slaRepo.GetPolicy(idDevice)
+	if err != nil || policy == nil || !policy.IsActive {
+		return
+	}
+
+	if err := s.slaRepo.CreateEvent(prospectNum, idDevice); err != nil {
+		logrus.WithError(err).Warn("⏱️ SLA: Failed to record handoff event")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"target_min":   policy.FirstResponseMinutes,
+	}).Info("⏱️ SLA: First-response clock started for handoff")
+}
+
+// RecordFirstResponse stops the SLA clock when an agent sends their first reply
+func (s *SLAService) RecordFirstResponse(prospectNum, idDevice string) {
+	if err := s.slaRepo.MarkFirstResponse(prospectNum, idDevice); err != nil {
+		logrus.WithError(err).Warn("⏱️ SLA: Failed to record first response")
+	}
+}
+
+// GetPolicy returns the SLA policy for a device
+func (s *SLAService) GetPolicy(idDevice string) (*models.SLAPolicy, error) {
+	return s.slaRepo.GetPolicy(idDevice)
+}
+
+// SavePolicy creates or updates the SLA policy for a device
+func (s *SLAService) SavePolicy(policy *models.SLAPolicy) error {
+	if policy.FirstResponseMinutes <= 0 {
+		return fmt.Errorf("first_response_minutes must be positive")
+	}
+
+	switch models.SLAEscalationAction(policy.EscalationAction) {
+	case models.SLAActionNotifyManager, models.SLAActionReassign, models.SLAActionApologyMessage:
+	default:
+		return fmt.Errorf("unknown escalation action: %s", policy.EscalationAction)
+	}
+
+	return s.slaRepo.UpsertPolicy(policy)
+}
+
+// GetComplianceReport summarizes SLA performance for a device
+func (s *SLAService) GetComplianceReport(idDevice string) (*models.SLAComplianceReport, error) {
+	return s.slaRepo.GetComplianceReport(idDevice)
+}
+
+// checkBreaches escalates handoffs that passed their first-response target
+func (s *SLAService) checkBreaches() {
+	policies, err := s.slaRepo.GetActivePolicies()
+	if err != nil {
+		logrus.WithError(err).Error("⏱️ SLA: Failed to load active policies")
+		return
+	}
+
+	for _, policy := range policies {
+		breaches, err := s.slaRepo.GetOpenBreaches(policy.IDDevice, policy.FirstResponseMinutes)
+		if err != nil {
+			logrus.WithError(err).Error("⏱️ SLA: Failed to check breaches")
+			continue
+		}
+
+		for _, event := range breaches {
+			s.escalate(&policy, &event)
+		}
+	}
+}
+
+// escalate runs the policy's escalation action for one breached handoff
+func (s *SLAService) escalate(policy *models.SLAPolicy, event *models.SLAEvent) {
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": event.ProspectNum,
+		"id_device":    event.IDDevice,
+		"action":       policy.EscalationAction,
+		"waiting_min":  time.Since(event.HandoffAt).Minutes(),
+	}).Warn("🚨 SLA: First-response SLA breached, escalating")
+
+	switch models.SLAEscalationAction(policy.EscalationAction) {
+	case models.SLAActionReassign:
+		if s.assignmentService != nil {
+			if _, err := s.assignmentService.AssignConversation(event.ProspectNum, event.IDDevice, "", models.AssignmentStrategyLeastActive); err != nil {
+				logrus.WithError(err).Error("🚨 SLA: Failed to reassign breached handoff")
+			}
+		}
+
+	case models.SLAActionApologyMessage:
+		if s.messageSender != nil && policy.ApologyMessage != nil && *policy.ApologyMessage != "" {
+			if err := s.messageSender.SendMessageFromDevice(event.IDDevice, event.ProspectNum, *policy.ApologyMessage); err != nil {
+				logrus.WithError(err).Error("🚨 SLA: Failed to send apology message")
+			}
+		}
+	}
+
+	// Breaches also reach the team's Slack/Discord channel when configured
+	if s.notificationService != nil {
+		s.notificationService.NotifyEvent(event.IDDevice, models.OpsEventSLABreached,
+			fmt.Sprintf("Handoff for %s has waited %.0f min without a first response (target %d min) — escalation: %s",
+				event.ProspectNum, time.Since(event.HandoffAt).Minutes(), policy.FirstResponseMinutes, policy.EscalationAction))
+	}
+
+	// Every escalation notifies agents (and the manager, when configured) over WebSocket
+	if s.websocketService != nil {
+		managerID := ""
+		if policy.ManagerAgentID != nil {
+			managerID = *policy.ManagerAgentID
+		}
+		s.websocketService.SendToDevice(event.IDDevice, "sla_breached", map[string]interface{}{
+			"prospect_num": event.ProspectNum,
+			"id_device":    event.IDDevice,
+			"handoff_at":   event.HandoffAt.Unix(),
+			"action":       policy.EscalationAction,
+			"manager_id":   managerID,
+		})
+	}
+
+	if err := s.slaRepo.MarkEscalated(event.ID); err != nil {
+		logrus.WithError(err).Error("🚨 SLA: Failed to mark event escalated")
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"sort"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StageHistoryService records every stage transition with its timestamp and
+// trigger, so the prospect's journey survives the single Stage column being
+// overwritten, and computes average time-in-stage reports from the trail
+
+// StageHistoryService records and reports stage transitions
+type StageHistoryService struct {
+	stageHistoryRepo repository.StageHistoryRepository
+}
+
+// NewStageHistoryService creates a new stage history service
+func NewStageHistoryService(stageHistoryRepo repository.StageHistoryRepository) *StageHistoryService {
+	return &StageHistoryService{stageHistoryRepo: stageHistoryRepo}
+}
+
+// Record stores one transition; unchanged stages are not recorded
+func (s *StageHistoryService) Record(prospectNum, idDevice, fromStage, toStage, source string) {
+	if toStage == "" || fromStage == toStage {
+		return
+	}
+
+	err := s.stageHistoryRepo.RecordStageTransition(&models.StageTransition{
+		ProspectNum: prospectNum,
+		IDDevice:    idDevice,
+		FromStage:   fromStage,
+		ToStage:     toStage,
+		Source:      source,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("🎯 STAGE: Failed to record stage transition")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"id_device":    idDevice,
+		"from_stage":   fromStage,
+		"to_stage":     toStage,
+		"source":       source,
+	}).Info("🎯 STAGE: Transition recorded")
+}
+
+// History returns a prospect's stage journey, oldest first
+func (s *StageHistoryService) History(prospectNum, idDevice string) ([]models.StageTransition, error) {
+	return s.stageHistoryRepo.GetStageHistory(prospectNum, idDevice)
+}
+
+// AverageTimeInStage reports how long prospects spend in each stage before
+// the next transition. Time in a stage is measured from the transition into
+// it until the next transition away; a prospect's current (open-ended) stage
+// contributes no sample
+func (s *StageHistoryService) AverageTimeInStage(idDevice string) ([]models.StageDuration, error) {
+	transitions, err := s.stageHistoryRepo.GetStageTransitionsByDevice(idDevice)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]float64)
+	samples := make(map[string]int)
+
+	for i := 1; i < len(transitions); i++ {
+		previous, current := &transitions[i-1], &transitions[i]
+		if previous.ProspectNum != current.ProspectNum {
+			continue
+		}
+		stage := previous.ToStage
+		totals[stage] += current.CreatedAt.Sub(previous.CreatedAt).Seconds()
+		samples[stage]++
+	}
+
+	durations := make([]models.StageDuration, 0, len(totals))
+	for stage, total := range totals {
+		durations = append(durations, models.StageDuration{
+			Stage:          stage,
+			AverageSeconds: total / float64(samples[stage]),
+			Samples:        samples[stage],
+		})
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i].Stage < durations[j].Stage })
+	return durations, nil
+}
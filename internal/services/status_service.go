@@ -0,0 +1,259 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StatusService serves the public status page: a cached, unauthenticated
+// summary of API uptime, per-provider delivery connectivity and admin-posted
+// incidents, so customers stop filing tickets during known provider outages
+
+const (
+	// statusCacheTTL is how long one computed summary is served before it is
+	// rebuilt; the page is public so reads must never hit the hot path
+	statusCacheTTL = 30 * time.Second
+
+	// statusWindowMinutes is the rolling window provider connectivity is
+	// judged over
+	statusWindowMinutes = 15
+
+	// statusIncidentLimit caps the incident list on the admin endpoint
+	statusIncidentLimit = 50
+
+	// Provider success-rate thresholds for the degraded/down classification
+	statusDegradedBelow = 0.90
+	statusDownBelow     = 0.50
+)
+
+// Overall and per-provider status values
+const (
+	statusOperational = "operational"
+	statusDegraded    = "degraded"
+	statusDown        = "down"
+	statusMajorOutage = "major_outage"
+)
+
+// providerBucket counts one minute of send results for one provider
+type providerBucket struct {
+	minute int64
+	ok     int
+	failed int
+}
+
+// StatusService exposes tenant-visible system health
+type StatusService struct {
+	incidentRepo repository.IncidentRepository
+	startTime    time.Time
+
+	mu        sync.Mutex
+	providers map[string][]providerBucket
+	cached    *models.PublicStatus
+	cachedAt  time.Time
+}
+
+// NewStatusService creates a new status service
+func NewStatusService(incidentRepo repository.IncidentRepository) *StatusService {
+	return &StatusService{
+		incidentRepo: incidentRepo,
+		startTime:    time.Now(),
+		providers:    make(map[string][]providerBucket),
+	}
+}
+
+// RecordProviderResult counts one delivery attempt for a provider's
+// connectivity window
+func (s *StatusService) RecordProviderResult(provider string, ok bool) {
+	if provider == "" {
+		provider = "unknown"
+	}
+	minute := time.Now().Unix() / 60
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buckets := s.providers[provider]
+	if len(buckets) == 0 || buckets[len(buckets)-1].minute != minute {
+		buckets = append(buckets, providerBucket{minute: minute})
+	}
+	if ok {
+		buckets[len(buckets)-1].ok++
+	} else {
+		buckets[len(buckets)-1].failed++
+	}
+
+	// Drop buckets that fell out of the window
+	cutoff := minute - statusWindowMinutes
+	for len(buckets) > 0 && buckets[0].minute < cutoff {
+		buckets = buckets[1:]
+	}
+	s.providers[provider] = buckets
+}
+
+// PublicStatus returns the cached status page payload, rebuilding it at most
+// once per cache interval
+func (s *StatusService) PublicStatus() *models.PublicStatus {
+	s.mu.Lock()
+	if s.cached != nil && time.Since(s.cachedAt) < statusCacheTTL {
+		cached := s.cached
+		s.mu.Unlock()
+		return cached
+	}
+	providers := s.providerStatusesLocked()
+	s.mu.Unlock()
+
+	incidents := make([]models.Incident, 0)
+	if open, err := s.incidentRepo.GetOpenIncidents(); err == nil {
+		incidents = open
+	}
+
+	status := &models.PublicStatus{
+		Status:        overallStatus(providers, incidents),
+		UptimeSeconds: int64(time.Since(s.startTime).Seconds()),
+		GeneratedAt:   time.Now(),
+		Providers:     providers,
+		Incidents:     incidents,
+	}
+
+	s.mu.Lock()
+	s.cached = status
+	s.cachedAt = time.Now()
+	s.mu.Unlock()
+
+	return status
+}
+
+// providerStatusesLocked classifies each provider's recent delivery success
+// rate; the caller holds the mutex
+func (s *StatusService) providerStatusesLocked() []models.ProviderStatus {
+	cutoff := time.Now().Unix()/60 - statusWindowMinutes
+
+	statuses := make([]models.ProviderStatus, 0, len(s.providers))
+	for provider, buckets := range s.providers {
+		ok, failed := 0, 0
+		for _, bucket := range buckets {
+			if bucket.minute < cutoff {
+				continue
+			}
+			ok += bucket.ok
+			failed += bucket.failed
+		}
+
+		samples := ok + failed
+		if samples == 0 {
+			continue
+		}
+
+		rate := float64(ok) / float64(samples)
+		state := statusOperational
+		if rate < statusDownBelow {
+			state = statusDown
+		} else if rate < statusDegradedBelow {
+			state = statusDegraded
+		}
+
+		statuses = append(statuses, models.ProviderStatus{
+			Provider:    provider,
+			Status:      state,
+			SuccessRate: rate,
+			Samples:     samples,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Provider < statuses[j].Provider })
+	return statuses
+}
+
+// overallStatus folds provider connectivity and open incidents into the
+// page's headline state
+func overallStatus(providers []models.ProviderStatus, incidents []models.Incident) string {
+	overall := statusOperational
+
+	for _, provider := range providers {
+		if provider.Status == statusDown {
+			return statusMajorOutage
+		}
+		if provider.Status == statusDegraded {
+			overall = statusDegraded
+		}
+	}
+
+	for _, incident := range incidents {
+		if incident.Severity == models.IncidentSeverityCritical {
+			return statusMajorOutage
+		}
+		overall = statusDegraded
+	}
+
+	return overall
+}
+
+// CreateIncident posts a new incident to the status page
+func (s *StatusService) CreateIncident(title, severity, message string) (*models.Incident, error) {
+	if title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if severity != models.IncidentSeverityMinor && severity != models.IncidentSeverityMajor && severity != models.IncidentSeverityCritical {
+		return nil, fmt.Errorf("severity must be %q, %q or %q",
+			models.IncidentSeverityMinor, models.IncidentSeverityMajor, models.IncidentSeverityCritical)
+	}
+
+	incident := &models.Incident{
+		Title:    title,
+		Severity: severity,
+		Status:   models.IncidentStatusOpen,
+		Message:  message,
+	}
+	if err := s.incidentRepo.CreateIncident(incident); err != nil {
+		return nil, err
+	}
+
+	s.invalidateCache()
+
+	logrus.WithFields(logrus.Fields{
+		"incident_id": incident.ID,
+		"severity":    severity,
+		"title":       title,
+	}).Info("📟 STATUS: Incident posted")
+
+	return incident, nil
+}
+
+// UpdateIncident changes an incident's status and latest message
+func (s *StatusService) UpdateIncident(id int, status, message string) error {
+	if status != models.IncidentStatusOpen && status != models.IncidentStatusMonitoring && status != models.IncidentStatusResolved {
+		return fmt.Errorf("status must be %q, %q or %q",
+			models.IncidentStatusOpen, models.IncidentStatusMonitoring, models.IncidentStatusResolved)
+	}
+
+	if err := s.incidentRepo.UpdateIncident(id, status, message); err != nil {
+		return err
+	}
+
+	s.invalidateCache()
+
+	logrus.WithFields(logrus.Fields{
+		"incident_id": id,
+		"status":      status,
+	}).Info("📟 STATUS: Incident updated")
+
+	return nil
+}
+
+// Incidents returns recent incidents including resolved ones for the admin UI
+func (s *StatusService) Incidents() ([]models.Incident, error) {
+	return s.incidentRepo.GetIncidents(statusIncidentLimit)
+}
+
+func (s *StatusService) invalidateCache() {
+	s.mu.Lock()
+	s.cached = nil
+	s.mu.Unlock()
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Distributed tracing for the message pipeline. A root span is opened when a
+// webhook arrives and child spans are attached as the message moves through
+// the flow engine, AI calls and the outbound provider send. Spans are
+// exported over OTLP/HTTP JSON when an endpoint is configured; without one,
+// trace IDs still land in the logs so requests can be correlated there
+
+// tracingFlushInterval is how often buffered spans are exported
+const tracingFlushInterval = 10 * time.Second
+
+// maxBufferedSpans caps the export buffer so a dead collector cannot grow
+// memory without bound; oldest spans are dropped first
+const maxBufferedSpans = 2048
+
+// TraceKey builds the correlation key a pipeline stage uses to attach child
+// spans to the trace opened for this device and prospect
+func TraceKey(idDevice, prospectNum string) string {
+	return idDevice + "|" + prospectNum
+}
+
+// DeviceTraceKey is the device-level fallback key for stages that know the
+// device but not the prospect (AI calls, provider sends). With concurrent
+// prospects on one device the parent is approximate - the most recently
+// opened trace wins
+func DeviceTraceKey(idDevice string) string {
+	return "device:" + idDevice
+}
+
+// TraceSpan is one timed operation inside a trace
+type TraceSpan struct {
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Name       string
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes map[string]string
+	ErrorMsg   string
+
+	tracer *TracingService
+	keys   []string
+	mu     sync.Mutex
+	ended  bool
+}
+
+// SetAttribute records a key/value pair on the span
+func (sp *TraceSpan) SetAttribute(key, value string) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.Attributes[key] = value
+}
+
+// LogFields returns the logrus fields that tie a log line to this span
+func (sp *TraceSpan) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"trace_id": sp.TraceID,
+		"span_id":  sp.SpanID,
+	}
+}
+
+// End closes the span and hands it to the exporter
+func (sp *TraceSpan) End() {
+	sp.EndWithError(nil)
+}
+
+// EndWithError closes the span, recording the error on it when non-nil
+func (sp *TraceSpan) EndWithError(err error) {
+	sp.mu.Lock()
+	if sp.ended {
+		sp.mu.Unlock()
+		return
+	}
+	sp.ended = true
+	sp.EndTime = time.Now()
+	if err != nil {
+		sp.ErrorMsg = err.Error()
+	}
+	sp.mu.Unlock()
+
+	if sp.tracer != nil {
+		sp.tracer.record(sp)
+	}
+}
+
+// TracingService owns the active trace registry and the OTLP exporter
+type TracingService struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+
+	mu       sync.Mutex
+	active   map[string]*TraceSpan
+	finished []*TraceSpan
+	done     chan struct{}
+}
+
+// defaultTracer is the process-wide tracer; InitTracing swaps in the
+// configured one at startup, mirroring the metrics registry
+var defaultTracer = NewTracingService("", "nodepath-chat")
+
+// Tracing returns the process-wide tracer
+func Tracing() *TracingService {
+	return defaultTracer
+}
+
+// InitTracing configures the process-wide tracer from config and returns it
+func InitTracing(endpoint, serviceName string) *TracingService {
+	defaultTracer = NewTracingService(endpoint, serviceName)
+	return defaultTracer
+}
+
+// NewTracingService creates a tracer. An empty endpoint disables export;
+// spans are still created so trace IDs appear in logs
+func NewTracingService(endpoint, serviceName string) *TracingService {
+	return &TracingService{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		active:      make(map[string]*TraceSpan),
+		done:        make(chan struct{}),
+	}
+}
+
+// Begin opens a root span for a new trace and registers it under the given
+// correlation keys so later pipeline stages can attach children
+func (t *TracingService) Begin(name string, attrs map[string]string, keys ...string) *TraceSpan {
+	span := t.newSpan(name, "", "")
+	for key, value := range attrs {
+		span.Attributes[key] = value
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		t.active[key] = span
+		span.keys = append(span.keys, key)
+	}
+	return span
+}
+
+// Alias registers an extra correlation key for an already open trace, used
+// once the prospect number has been extracted from the payload
+func (t *TracingService) Alias(span *TraceSpan, key string) {
+	if span == nil || key == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active[key] = span
+	span.keys = append(span.keys, key)
+}
+
+// StartChild opens a child span under the first open trace among the given
+// correlation keys. When none is open the span becomes its own root so the
+// stage is still timed
+func (t *TracingService) StartChild(name string, keys ...string) *TraceSpan {
+	t.mu.Lock()
+	var parent *TraceSpan
+	for _, key := range keys {
+		if span, ok := t.active[key]; ok {
+			parent = span
+			break
+		}
+	}
+	t.mu.Unlock()
+
+	if parent == nil {
+		return t.newSpan(name, "", "")
+	}
+	return t.newSpan(name, parent.TraceID, parent.SpanID)
+}
+
+// Finish closes a root span and removes its correlation keys
+func (t *TracingService) Finish(span *TraceSpan) {
+	if span == nil {
+		return
+	}
+	t.mu.Lock()
+	for _, key := range span.keys {
+		if t.active[key] == span {
+			delete(t.active, key)
+		}
+	}
+	t.mu.Unlock()
+
+	span.End()
+}
+
+// newSpan builds a span with fresh IDs, inheriting the trace ID when given
+func (t *TracingService) newSpan(name, traceID, parentID string) *TraceSpan {
+	if traceID == "" {
+		traceID = randomHex(16)
+	}
+	return &TraceSpan{
+		TraceID:    traceID,
+		SpanID:     randomHex(8),
+		ParentID:   parentID,
+		Name:       name,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]string),
+		tracer:     t,
+	}
+}
+
+// record buffers a finished span for export; without an endpoint the span is
+// discarded since only the logged trace IDs are of use
+func (t *TracingService) record(span *TraceSpan) {
+	if t.endpoint == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.finished) >= maxBufferedSpans {
+		t.finished = t.finished[1:]
+	}
+	t.finished = append(t.finished, span)
+}
+
+// StartExporter flushes buffered spans to the OTLP endpoint until Stop is
+// called. It is a no-op when no endpoint is configured
+func (t *TracingService) StartExporter() {
+	if t.endpoint == "" {
+		logrus.Info("🔭 TRACING: No OTLP endpoint configured, trace IDs logged only")
+		return
+	}
+
+	logrus.WithField("endpoint", t.endpoint).Info("🔭 TRACING: OTLP span exporter started")
+	ticker := time.NewTicker(tracingFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			t.flush()
+			return
+		case <-ticker.C:
+			t.flush()
+		}
+	}
+}
+
+// Stop shuts down the exporter after a final flush
+func (t *TracingService) Stop() {
+	close(t.done)
+}
+
+// flush exports the buffered spans; on failure they are dropped rather than
+// retried so a dead collector cannot stall the pipeline
+func (t *TracingService) flush() {
+	t.mu.Lock()
+	spans := t.finished
+	t.finished = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(buildOTLPRequest(t.serviceName, spans))
+	if err != nil {
+		logrus.WithError(err).Warn("🔭 TRACING: Failed to encode spans for export")
+		return
+	}
+
+	resp, err := t.client.Post(t.endpoint+"/v1/traces", "application/json", bytes.NewReader(body))
+	if err != nil {
+		logrus.WithError(err).WithField("span_count", len(spans)).Warn("🔭 TRACING: Span export failed")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logrus.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"span_count":  len(spans),
+		}).Warn("🔭 TRACING: OTLP endpoint rejected span export")
+	}
+}
+
+// randomHex returns n random bytes hex-encoded, the OTLP wire form for IDs
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		// Fall back to a time-derived ID; correlation beats cryptographic
+		// quality here
+		return fmt.Sprintf("%0*x", n*2, time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
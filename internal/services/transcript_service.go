@@ -0,0 +1,234 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+)
+
+// TranscriptService renders conversation transcripts as shareable HTML documents
+// with media thumbnails inlined, suitable for customers or support tickets
+type TranscriptService struct {
+	aiRepo                repository.AIWhatsappRepository
+	mediaDetectionService *MediaDetectionService
+}
+
+// NewTranscriptService creates a new transcript service
+func NewTranscriptService(aiRepo repository.AIWhatsappRepository, mediaDetectionService *MediaDetectionService) *TranscriptService {
+	return &TranscriptService{
+		aiRepo:                aiRepo,
+		mediaDetectionService: mediaDetectionService,
+	}
+}
+
+// RenderHTMLTranscript builds a self-contained HTML transcript for a conversation.
+// Returns nil when the conversation does not exist
+func (s *TranscriptService) RenderHTMLTranscript(prospectNum, idDevice string) ([]byte, error) {
+	conversation, err := s.aiRepo.GetAIWhatsappByProspectAndDevice(prospectNum, idDevice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation for transcript: %w", err)
+	}
+	if conversation == nil {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString(fmt.Sprintf("<title>Conversation Transcript - %s</title>\n", html.EscapeString(prospectNum)))
+	sb.WriteString(`<style>
+body { font-family: Arial, sans-serif; max-width: 720px; margin: 0 auto; padding: 20px; background: #f5f5f5; }
+.header { background: #fff; border-radius: 8px; padding: 16px; margin-bottom: 16px; }
+.header h1 { font-size: 18px; margin: 0 0 8px 0; }
+.header .meta { color: #666; font-size: 13px; }
+.message { border-radius: 8px; padding: 10px 14px; margin: 8px 0; max-width: 80%; word-wrap: break-word; }
+.message.user { background: #dcf8c6; margin-left: auto; }
+.message.bot { background: #fff; }
+.message .sender { font-size: 11px; color: #888; margin-bottom: 4px; }
+.message img { max-width: 240px; border-radius: 6px; display: block; margin-top: 6px; }
+.message .media-link { font-size: 12px; color: #4a90d9; }
+</style>
+</head>
+<body>
+`)
+
+	sb.WriteString("<div class=\"header\">\n")
+	sb.WriteString(fmt.Sprintf("<h1>Conversation with %s</h1>\n", html.EscapeString(s.prospectLabel(conversation))))
+	sb.WriteString(fmt.Sprintf("<div class=\"meta\">Device: %s &middot; Exported: %s</div>\n",
+		html.EscapeString(idDevice), time.Now().Format("2006-01-02 15:04")))
+	sb.WriteString("</div>\n")
+
+	if conversation.ConvLast.Valid && conversation.ConvLast.String != "" {
+		for _, line := range strings.Split(conversation.ConvLast.String, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			sb.WriteString(s.renderLine(line))
+		}
+	} else {
+		sb.WriteString("<p>No messages recorded for this conversation.</p>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+	return []byte(sb.String()), nil
+}
+
+// prospectLabel prefers the prospect's name over the raw number
+func (s *TranscriptService) prospectLabel(conversation *models.AIWhatsapp) string {
+	if conversation.ProspectName.Valid && conversation.ProspectName.String != "" {
+		return fmt.Sprintf("%s (%s)", conversation.ProspectName.String, conversation.ProspectNum)
+	}
+	return conversation.ProspectNum
+}
+
+// renderLine converts one conv_last entry into a chat bubble, inlining media thumbnails
+func (s *TranscriptService) renderLine(line string) string {
+	role := "bot"
+	sender := "Bot"
+	text := line
+
+	switch {
+	case strings.HasPrefix(line, "USER: "):
+		role = "user"
+		sender = "Customer"
+		text = strings.TrimPrefix(line, "USER: ")
+	case strings.HasPrefix(line, "BOT: "):
+		text = strings.TrimPrefix(line, "BOT: ")
+	}
+
+	var media string
+	if s.mediaDetectionService != nil && s.mediaDetectionService.HasMedia(text) {
+		results := s.mediaDetectionService.DetectMedia(text)
+		for _, result := range results {
+			if !result.IsMedia {
+				continue
+			}
+			switch result.MediaType {
+			case "image":
+				media += fmt.Sprintf("<img src=\"%s\" alt=\"image\">\n", html.EscapeString(result.MediaURL))
+			default:
+				media += fmt.Sprintf("<div class=\"media-link\"><a href=\"%s\">%s attachment</a></div>\n",
+					html.EscapeString(result.MediaURL), html.EscapeString(result.MediaType))
+			}
+			text = result.CleanText
+		}
+	}
+
+	return fmt.Sprintf("<div class=\"message %s\">\n<div class=\"sender\">%s</div>\n%s%s</div>\n",
+		role, sender, html.EscapeString(strings.TrimSpace(text)), media)
+}
+
+// TranscriptEntry is one message in a structured transcript export
+type TranscriptEntry struct {
+	Role      string `json:"role"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Content   string `json:"content"`
+	MediaURL  string `json:"media_url,omitempty"`
+	MediaType string `json:"media_type,omitempty"`
+}
+
+// BuildStructuredTranscript reconstructs the conversation's conv_last log
+// into structured entries, enriching them with timestamps from the
+// conversation log where the messages can be matched. Entries without a
+// known timestamp are kept unless a date filter is given. Returns nil when
+// the conversation does not exist
+func (s *TranscriptService) BuildStructuredTranscript(prospectNum, idDevice string, start, end *time.Time) ([]TranscriptEntry, error) {
+	conversation, err := s.aiRepo.GetAIWhatsappByProspectAndDevice(prospectNum, idDevice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation for transcript: %w", err)
+	}
+	if conversation == nil {
+		return nil, nil
+	}
+
+	// Load the timestamped log so entries can carry per-message timestamps;
+	// conv_last itself only stores role-prefixed lines
+	logs, err := s.aiRepo.GetConversationHistoryByDevice(prospectNum, idDevice, 1000)
+	if err != nil {
+		logs = nil
+	}
+	// GetConversationHistory returns newest first; walk chronologically
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+
+	entries := []TranscriptEntry{}
+	logIndex := 0
+	if !conversation.ConvLast.Valid || conversation.ConvLast.String == "" {
+		return entries, nil
+	}
+
+	for _, line := range strings.Split(conversation.ConvLast.String, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		entry := TranscriptEntry{Role: "bot", Content: line}
+		switch {
+		case strings.HasPrefix(line, "USER:"):
+			entry.Role = "user"
+			entry.Content = strings.TrimSpace(strings.TrimPrefix(line, "USER:"))
+		case strings.HasPrefix(line, "BOT:"):
+			entry.Content = strings.TrimSpace(strings.TrimPrefix(line, "BOT:"))
+		}
+
+		// Match the entry to the next log row with the same sender and
+		// message to recover its timestamp
+		for i := logIndex; i < len(logs); i++ {
+			if logs[i].Sender == entry.Role && strings.TrimSpace(logs[i].Message) == entry.Content {
+				entry.Timestamp = logs[i].CreatedAt.Format(time.RFC3339)
+				logIndex = i + 1
+				break
+			}
+		}
+
+		// Split detected media out of the message body
+		if s.mediaDetectionService != nil && s.mediaDetectionService.HasMedia(entry.Content) {
+			for _, result := range s.mediaDetectionService.DetectMedia(entry.Content) {
+				if !result.IsMedia {
+					continue
+				}
+				entry.MediaURL = result.MediaURL
+				entry.MediaType = result.MediaType
+				if cleaned := strings.TrimSpace(result.CleanText); cleaned != "" {
+					entry.Content = cleaned
+				}
+				break
+			}
+		}
+
+		if !transcriptEntryInRange(entry.Timestamp, start, end) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// transcriptEntryInRange applies the optional date filter; entries without a
+// timestamp only pass when no filter is set
+func transcriptEntryInRange(timestamp string, start, end *time.Time) bool {
+	if start == nil && end == nil {
+		return true
+	}
+	if timestamp == "" {
+		return false
+	}
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return false
+	}
+	if start != nil && parsed.Before(*start) {
+		return false
+	}
+	if end != nil && parsed.After(*end) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+)
+
+// Speech-to-text through the AI provider's /audio/transcriptions endpoint,
+// turning customer voice notes into text the flow engine can process
+
+// transcriptionModel is the Whisper model used for voice note transcription
+const transcriptionModel = "whisper-1"
+
+type transcriptionResponse struct {
+	Text string `json:"text"`
+}
+
+// TranscribeAudio transcribes an audio file with Whisper and returns the text
+func (s *AIService) TranscribeAudio(audioData []byte, fileName, apiKey, deviceID string) (string, error) {
+	if len(audioData) == 0 {
+		return "", fmt.Errorf("audio data cannot be empty")
+	}
+
+	apiKey = s.getAPIKey(apiKey, deviceID)
+	if apiKey == "" {
+		return "", fmt.Errorf("no API key provided")
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	filePart, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create transcription form file: %w", err)
+	}
+	if _, err := filePart.Write(audioData); err != nil {
+		return "", fmt.Errorf("failed to write transcription audio: %w", err)
+	}
+	if err := writer.WriteField("model", transcriptionModel); err != nil {
+		return "", fmt.Errorf("failed to write transcription model field: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize transcription request: %w", err)
+	}
+
+	// Transcription always goes to the OpenAI endpoint - OpenRouter does not
+	// serve an /audio/transcriptions route
+	req, err := http.NewRequest("POST", openAIBaseURL+"/audio/transcriptions", &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create transcription request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	client := &http.Client{Timeout: 120 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("transcription request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read transcription response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("transcription API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed transcriptionResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse transcription response: %w", err)
+	}
+
+	return parsed.Text, nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Per-turn response latency SLO tracking. A turn starts when a webhook is
+// received for a prospect and ends at the first outbound send back to them;
+// that gap is what the prospect experiences as "how long did the bot take".
+// Samples are kept in memory per device, like the webhook stats, and an ops
+// alert fires when the device's p95 drifts past the configured SLO
+
+// turnLatencyMaxSamples caps the rolling window per device
+const turnLatencyMaxSamples = 500
+
+// turnLatencyMinSamples is how many samples a device needs before its p95 is
+// trusted enough to alert on
+const turnLatencyMinSamples = 20
+
+// turnPendingTTL is how long an unanswered turn start is kept; turns that
+// never send (flow waiting for a reply, manual handoff) are discarded rather
+// than recorded as slow
+const turnPendingTTL = 5 * time.Minute
+
+// turnLatencyAlertCooldown limits how often one device can re-alert
+const turnLatencyAlertCooldown = 30 * time.Minute
+
+// TurnLatencyReport is the per-device latency summary for the dashboard
+type TurnLatencyReport struct {
+	IDDevice  string  `json:"id_device"`
+	Samples   int     `json:"samples"`
+	P50Ms     float64 `json:"p50_ms"`
+	P95Ms     float64 `json:"p95_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+	SLOMs     float64 `json:"slo_ms"`
+	Breaching bool    `json:"breaching"`
+}
+
+// TurnLatencyService measures webhook-to-first-send latency per turn
+type TurnLatencyService struct {
+	slo           time.Duration
+	notifications *NotificationConnectorService
+
+	mu        sync.Mutex
+	pending   map[string]time.Time
+	samples   map[string][]time.Duration
+	lastAlert map[string]time.Time
+}
+
+// NewTurnLatencyService creates a turn latency tracker with the given SLO
+func NewTurnLatencyService(slo time.Duration) *TurnLatencyService {
+	return &TurnLatencyService{
+		slo:       slo,
+		pending:   make(map[string]time.Time),
+		samples:   make(map[string][]time.Duration),
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// SetNotificationService wires the ops alert connectors
+func (s *TurnLatencyService) SetNotificationService(notificationService *NotificationConnectorService) {
+	s.notifications = notificationService
+}
+
+// TurnStarted marks webhook receipt for a prospect's turn. A turn already in
+// flight keeps its original start so duplicate webhooks cannot shrink the
+// measured latency
+func (s *TurnLatencyService) TurnStarted(idDevice, prospectNum string) {
+	if idDevice == "" || prospectNum == "" {
+		return
+	}
+	key := idDevice + "|" + prospectNum
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) > 1000 {
+		s.purgeStaleLocked()
+	}
+	if _, inFlight := s.pending[key]; !inFlight {
+		s.pending[key] = time.Now()
+	}
+}
+
+// TurnCompleted marks the first outbound send of a turn and records the
+// latency sample. Sends without a matching turn start (broadcasts, manual
+// messages) are ignored
+func (s *TurnLatencyService) TurnCompleted(idDevice, prospectNum string) {
+	if idDevice == "" || prospectNum == "" {
+		return
+	}
+	key := idDevice + "|" + prospectNum
+
+	s.mu.Lock()
+	started, ok := s.pending[key]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.pending, key)
+
+	latency := time.Since(started)
+	if latency > turnPendingTTL {
+		// The start was stale - the flow was waiting on the prospect, not
+		// the other way around
+		s.mu.Unlock()
+		return
+	}
+
+	window := append(s.samples[idDevice], latency)
+	if len(window) > turnLatencyMaxSamples {
+		window = window[1:]
+	}
+	s.samples[idDevice] = window
+	breaching, p95 := s.sloBreachLocked(idDevice)
+	s.mu.Unlock()
+
+	Metrics().ObserveHistogram(MetricTurnLatencySeconds, map[string]string{"device_id": idDevice}, latency.Seconds())
+
+	if breaching {
+		s.alertBreach(idDevice, p95)
+	}
+}
+
+// Report returns the latency summary for one device
+func (s *TurnLatencyService) Report(idDevice string) TurnLatencyReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reportLocked(idDevice)
+}
+
+// ListReports returns summaries for every device with samples
+func (s *TurnLatencyService) ListReports() []TurnLatencyReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reports := make([]TurnLatencyReport, 0, len(s.samples))
+	for idDevice := range s.samples {
+		reports = append(reports, s.reportLocked(idDevice))
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].IDDevice < reports[j].IDDevice })
+	return reports
+}
+
+// reportLocked builds a device summary; caller must hold the mutex
+func (s *TurnLatencyService) reportLocked(idDevice string) TurnLatencyReport {
+	window := s.samples[idDevice]
+	report := TurnLatencyReport{
+		IDDevice: idDevice,
+		Samples:  len(window),
+		SLOMs:    float64(s.slo.Milliseconds()),
+	}
+	if len(window) == 0 {
+		return report
+	}
+
+	report.P50Ms = percentileMs(window, 0.50)
+	report.P95Ms = percentileMs(window, 0.95)
+	report.P99Ms = percentileMs(window, 0.99)
+	report.Breaching = len(window) >= turnLatencyMinSamples && report.P95Ms > report.SLOMs
+	return report
+}
+
+// sloBreachLocked reports whether the device's p95 currently exceeds the SLO
+// and an alert is due; caller must hold the mutex
+func (s *TurnLatencyService) sloBreachLocked(idDevice string) (bool, float64) {
+	window := s.samples[idDevice]
+	if len(window) < turnLatencyMinSamples {
+		return false, 0
+	}
+
+	p95 := percentileMs(window, 0.95)
+	if p95 <= float64(s.slo.Milliseconds()) {
+		return false, p95
+	}
+	if time.Since(s.lastAlert[idDevice]) < turnLatencyAlertCooldown {
+		return false, p95
+	}
+	s.lastAlert[idDevice] = time.Now()
+	return true, p95
+}
+
+// alertBreach notifies the device's ops connectors about the SLO breach
+func (s *TurnLatencyService) alertBreach(idDevice string, p95 float64) {
+	logrus.WithFields(logrus.Fields{
+		"id_device": idDevice,
+		"p95_ms":    p95,
+		"slo_ms":    s.slo.Milliseconds(),
+	}).Warn("⏱️ LATENCY: Device p95 response latency exceeds SLO")
+
+	if s.notifications == nil {
+		return
+	}
+	s.notifications.NotifyEvent(idDevice, models.OpsEventLatencySLOBreach,
+		fmt.Sprintf("Device %s p95 response latency is %.0fms, above the %dms SLO", idDevice, p95, s.slo.Milliseconds()))
+}
+
+// purgeStaleLocked drops turn starts that never saw a send; caller must hold
+// the mutex
+func (s *TurnLatencyService) purgeStaleLocked() {
+	cutoff := time.Now().Add(-turnPendingTTL)
+	for key, started := range s.pending {
+		if started.Before(cutoff) {
+			delete(s.pending, key)
+		}
+	}
+}
+
+// percentileMs returns the given percentile of the window in milliseconds
+func percentileMs(window []time.Duration, quantile float64) float64 {
+	sorted := make([]time.Duration, len(window))
+	copy(sorted, window)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(float64(len(sorted)-1) * quantile)
+	return float64(sorted[index].Microseconds()) / 1000
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UsageService aggregates one org's activity into the usage dashboard:
+// messages in/out per day, AI tokens per day, active conversations, top
+// flows and provider error rates. Summaries are cached in Redis so dashboard
+// refreshes do not hammer the aggregation queries
+
+const (
+	// usageCacheTTL is how long one computed dashboard is served from Redis
+	usageCacheTTL = 5 * time.Minute
+
+	// usageCachePrefix keys cached dashboards per user
+	usageCachePrefix = "usage:dashboard:"
+
+	// usageWindowDays is how far back the per-day series reach
+	usageWindowDays = 30
+
+	// usageTopFlowLimit caps the top-flows ranking
+	usageTopFlowLimit = 10
+)
+
+// UsageService serves per-tenant usage dashboard data
+type UsageService struct {
+	usageRepo       repository.UsageRepository
+	aiService       *AIService
+	alertingService *AlertingService
+	queueService    *QueueService
+}
+
+// NewUsageService creates a new usage service
+func NewUsageService(usageRepo repository.UsageRepository, aiService *AIService, alertingService *AlertingService, queueService *QueueService) *UsageService {
+	return &UsageService{
+		usageRepo:       usageRepo,
+		aiService:       aiService,
+		alertingService: alertingService,
+		queueService:    queueService,
+	}
+}
+
+// Dashboard returns the user's aggregated usage, served from Redis when a
+// fresh copy exists
+func (s *UsageService) Dashboard(userID string) (*models.UsageDashboard, error) {
+	cacheKey := usageCachePrefix + userID
+
+	if s.queueService != nil {
+		if cached, ok := s.queueService.CacheGet(cacheKey); ok {
+			dashboard := &models.UsageDashboard{}
+			if err := json.Unmarshal(cached, dashboard); err == nil {
+				return dashboard, nil
+			}
+		}
+	}
+
+	dashboard, err := s.buildDashboard(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.queueService != nil {
+		if encoded, err := json.Marshal(dashboard); err == nil {
+			s.queueService.CacheSet(cacheKey, encoded, usageCacheTTL)
+		}
+	}
+
+	return dashboard, nil
+}
+
+// buildDashboard runs the aggregation queries for one user
+func (s *UsageService) buildDashboard(userID string) (*models.UsageDashboard, error) {
+	messages, err := s.usageRepo.GetMessagesPerDay(userID, usageWindowDays)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.usageRepo.GetActiveConversations(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	topFlows, err := s.usageRepo.GetTopFlows(userID, usageTopFlowLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceIDs, err := s.usageRepo.GetUserDeviceIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]models.DailyTokenCount, 0)
+	if s.aiService != nil {
+		tokens = s.aiService.TokensPerDay(deviceIDs, time.Now().AddDate(0, 0, -usageWindowDays))
+	}
+
+	errorRates := make([]models.ProviderErrorRate, 0)
+	if s.alertingService != nil {
+		errorRates = s.alertingService.SendErrorRates(deviceIDs)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": userID,
+		"devices": len(deviceIDs),
+	}).Debug("📊 USAGE: Dashboard rebuilt")
+
+	return &models.UsageDashboard{
+		GeneratedAt:         time.Now(),
+		MessagesPerDay:      messages,
+		TokensPerDay:        tokens,
+		ActiveConversations: active,
+		TopFlows:            topFlows,
+		ProviderErrorRates:  errorRates,
+	}, nil
+}
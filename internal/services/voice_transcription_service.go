@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// VoiceTranscriptionService turns incoming voice notes into text so the flow
+// engine and AI prompts see what the customer actually said instead of a bare
+// audio URL
+
+// VoiceNoteMarker flags audio-origin content in conversation history, so
+// transcripts are distinguishable from typed messages
+const VoiceNoteMarker = "[voice note]"
+
+// maxVoiceNoteSize caps voice note downloads; WhatsApp voice notes are far
+// smaller, so anything bigger is not a voice note
+const maxVoiceNoteSize = 25 * 1024 * 1024
+
+// VoiceTranscriptionService downloads voice notes and transcribes them
+// through the AI service
+type VoiceTranscriptionService struct {
+	aiService      *AIService
+	mediaDetection *MediaDetectionService
+	client         *http.Client
+}
+
+// NewVoiceTranscriptionService creates a new voice transcription service
+func NewVoiceTranscriptionService(aiService *AIService, mediaDetection *MediaDetectionService) *VoiceTranscriptionService {
+	return &VoiceTranscriptionService{
+		aiService:      aiService,
+		mediaDetection: mediaDetection,
+		client:         &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// TranscribeIfAudio transcribes the media at the URL when it is audio,
+// returning false for non-audio media or when transcription fails so callers
+// can fall back to the plain URL
+func (s *VoiceTranscriptionService) TranscribeIfAudio(mediaURL, deviceID string) (string, bool) {
+	if mediaURL == "" || !s.isAudioURL(mediaURL) {
+		return "", false
+	}
+
+	audioData, err := s.downloadAudio(mediaURL)
+	if err != nil {
+		logrus.WithError(err).WithField("media_url", mediaURL).Warn("🎙️ TRANSCRIPTION: Failed to download voice note")
+		return "", false
+	}
+
+	fileName := path.Base(mediaURL)
+	if idx := strings.IndexAny(fileName, "?#"); idx >= 0 {
+		fileName = fileName[:idx]
+	}
+
+	transcript, err := s.aiService.TranscribeAudio(audioData, fileName, "", deviceID)
+	if err != nil {
+		logrus.WithError(err).WithField("media_url", mediaURL).Warn("🎙️ TRANSCRIPTION: Voice note transcription failed")
+		return "", false
+	}
+
+	transcript = strings.TrimSpace(transcript)
+	if transcript == "" {
+		return "", false
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"media_url":  mediaURL,
+		"device_id":  deviceID,
+		"transcript": transcript,
+	}).Info("🎙️ TRANSCRIPTION: Voice note transcribed")
+
+	return transcript, true
+}
+
+// isAudioURL reports whether the URL points at an audio file
+func (s *VoiceTranscriptionService) isAudioURL(mediaURL string) bool {
+	for _, result := range s.mediaDetection.DetectMedia(mediaURL) {
+		if result.IsMedia && result.MediaType == "audio" {
+			return true
+		}
+	}
+	return false
+}
+
+// downloadAudio fetches the voice note bytes with a size cap
+func (s *VoiceTranscriptionService) downloadAudio(mediaURL string) ([]byte, error) {
+	resp, err := s.client.Get(mediaURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download audio: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("audio download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxVoiceNoteSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio body: %v", err)
+	}
+	if len(data) > maxVoiceNoteSize {
+		return nil, fmt.Errorf("audio exceeds maximum size of %d bytes", maxVoiceNoteSize)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("audio download returned empty body")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// voucherCodeCharset omits ambiguous characters (0/O, 1/I/L) so prospects can
+// read codes back over the phone without mistakes
+const voucherCodeCharset = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// voucherCodeLength is the length of the random part of a generated code
+const voucherCodeLength = 8
+
+// Redemption failure reasons returned to the flow and POS systems
+var (
+	ErrVoucherNotFound  = fmt.Errorf("voucher code not found")
+	ErrVoucherExpired   = fmt.Errorf("voucher code has expired")
+	ErrVoucherExhausted = fmt.Errorf("voucher code has reached its usage limit")
+)
+
+// VoucherService generates and redeems discount codes issued by voucher nodes
+type VoucherService struct {
+	voucherRepo repository.VoucherRepository
+}
+
+// NewVoucherService creates a new voucher service
+func NewVoucherService(voucherRepo repository.VoucherRepository) *VoucherService {
+	return &VoucherService{voucherRepo: voucherRepo}
+}
+
+// GenerateVoucher creates a unique code for a prospect. expiryDays of 0 means
+// the code never expires; maxRedemptions below 1 defaults to single use
+func (s *VoucherService) GenerateVoucher(idDevice, campaign, issuedTo string, discount float64, expiryDays, maxRedemptions int) (*models.Voucher, error) {
+	if maxRedemptions < 1 {
+		maxRedemptions = 1
+	}
+
+	code, err := generateVoucherCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate voucher code: %w", err)
+	}
+
+	voucher := &models.Voucher{
+		ID:             uuid.New().String(),
+		IDDevice:       idDevice,
+		Code:           code,
+		Campaign:       campaign,
+		Discount:       discount,
+		IssuedTo:       issuedTo,
+		MaxRedemptions: maxRedemptions,
+	}
+	if expiryDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, expiryDays)
+		voucher.ExpiresAt = &expiresAt
+	}
+
+	if err := s.voucherRepo.CreateVoucher(voucher); err != nil {
+		return nil, fmt.Errorf("failed to create voucher: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"code":      voucher.Code,
+		"campaign":  campaign,
+		"issued_to": issuedTo,
+		"discount":  discount,
+	}).Info("🎟️ VOUCHER: Code generated")
+
+	return voucher, nil
+}
+
+// GetVouchers returns a device's issued vouchers
+func (s *VoucherService) GetVouchers(idDevice, campaign string) ([]models.Voucher, error) {
+	return s.voucherRepo.GetVouchersByDevice(idDevice, campaign)
+}
+
+// RedeemVoucher validates and consumes one use of a code. Returns the voucher
+// on success; ErrVoucherNotFound, ErrVoucherExpired or ErrVoucherExhausted
+// describe why a redemption was rejected
+func (s *VoucherService) RedeemVoucher(code, idDevice, prospectNum, source string) (*models.Voucher, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	voucher, err := s.voucherRepo.GetVoucherByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	// Codes from another device's campaigns are indistinguishable from unknown codes
+	if voucher == nil || voucher.IDDevice != idDevice {
+		return nil, ErrVoucherNotFound
+	}
+
+	if voucher.ExpiresAt != nil && time.Now().After(*voucher.ExpiresAt) {
+		return nil, ErrVoucherExpired
+	}
+
+	consumed, err := s.voucherRepo.IncrementRedemption(code)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrVoucherExhausted
+	}
+	voucher.RedemptionCount++
+
+	if source == "" {
+		source = "flow"
+	}
+	if err := s.voucherRepo.RecordRedemption(code, prospectNum, source); err != nil {
+		logrus.WithError(err).Error("🎟️ VOUCHER: Failed to log redemption")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"code":         code,
+		"prospect_num": prospectNum,
+		"source":       source,
+	}).Info("✅ VOUCHER: Code redeemed")
+
+	return voucher, nil
+}
+
+// generateVoucherCode builds a random code from the unambiguous charset
+func generateVoucherCode() (string, error) {
+	buf := make([]byte, voucherCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for _, b := range buf {
+		builder.WriteByte(voucherCodeCharset[int(b)%len(voucherCodeCharset)])
+	}
+
+	return builder.String(), nil
+}
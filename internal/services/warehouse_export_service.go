@@ -0,0 +1,275 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WarehouseExportService streams conversation and prospect events to a
+// customer-managed analytics warehouse. Events are buffered per device and
+// flushed as JSON-lines batches to the configured HTTP endpoint — the format
+// ClickHouse ingests directly (FORMAT JSONEachRow) and BigQuery accepts
+// through an ingestion proxy. A backfill replays existing prospects so the
+// warehouse starts with full history
+
+// warehouseFlushInterval is how often buffered events are shipped
+const warehouseFlushInterval = 30 * time.Second
+
+// warehouseBatchSize is the largest number of events sent in one request
+const warehouseBatchSize = 500
+
+// warehouseMaxBuffered caps a device's buffer; beyond it the oldest events
+// are dropped so a dead endpoint cannot grow memory without bound
+const warehouseMaxBuffered = 5000
+
+// warehouseBackfillEvent marks rows replayed from existing data
+const warehouseBackfillEvent = "prospect.snapshot"
+
+// WarehouseExportService buffers and ships warehouse export batches
+type WarehouseExportService struct {
+	warehouseRepo repository.WarehouseExportRepository
+	httpClient    *http.Client
+	mu            sync.Mutex
+	buffers       map[string][]models.WarehouseEvent
+	done          chan struct{}
+}
+
+// NewWarehouseExportService creates a new warehouse export service
+func NewWarehouseExportService(warehouseRepo repository.WarehouseExportRepository) *WarehouseExportService {
+	return &WarehouseExportService{
+		warehouseRepo: warehouseRepo,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		buffers: make(map[string][]models.WarehouseEvent),
+		done:    make(chan struct{}),
+	}
+}
+
+// GetConfig returns a device's connector, or a disabled default when never set
+func (s *WarehouseExportService) GetConfig(idDevice string) (*models.WarehouseExportConfig, error) {
+	config, err := s.warehouseRepo.GetWarehouseExportConfig(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &models.WarehouseExportConfig{IDDevice: idDevice}
+	}
+	return config, nil
+}
+
+// SaveConfig validates and stores a device's connector
+func (s *WarehouseExportService) SaveConfig(config *models.WarehouseExportConfig) error {
+	parsed, err := url.Parse(strings.TrimSpace(config.Endpoint))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("endpoint must be a valid http(s) URL")
+	}
+	config.Endpoint = parsed.String()
+
+	return s.warehouseRepo.SetWarehouseExportConfig(config)
+}
+
+// RecordEvent buffers one event for the next flush. Recording never blocks
+// message processing; events for devices without an enabled connector are
+// discarded at flush time
+func (s *WarehouseExportService) RecordEvent(idDevice, event string, data map[string]interface{}) {
+	s.enqueue(idDevice, models.WarehouseEvent{
+		SchemaVersion: models.WarehouseSchemaVersion,
+		Event:         event,
+		IDDevice:      idDevice,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		Data:          data,
+	})
+}
+
+// Backfill replays a device's existing prospects as snapshot events and
+// returns how many rows were queued
+func (s *WarehouseExportService) Backfill(idDevice string) (int, error) {
+	config, err := s.GetConfig(idDevice)
+	if err != nil {
+		return 0, err
+	}
+	if !config.Enabled {
+		return 0, fmt.Errorf("warehouse export is not enabled for device %s", idDevice)
+	}
+
+	snapshots, err := s.warehouseRepo.GetProspectsForBackfill(idDevice)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, snapshot := range snapshots {
+		s.enqueue(idDevice, models.WarehouseEvent{
+			SchemaVersion: models.WarehouseSchemaVersion,
+			Event:         warehouseBackfillEvent,
+			IDDevice:      idDevice,
+			Timestamp:     now,
+			Data: map[string]interface{}{
+				"prospect_num":     snapshot.ProspectNum,
+				"prospect_name":    snapshot.ProspectName,
+				"stage":            snapshot.Stage,
+				"execution_status": snapshot.ExecutionStatus,
+				"niche":            snapshot.Niche,
+				"created_at":       snapshot.CreatedAt,
+				"updated_at":       snapshot.UpdatedAt,
+			},
+		})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device": idDevice,
+		"rows":      len(snapshots),
+	}).Info("🏭 WAREHOUSE: Backfill queued")
+
+	return len(snapshots), nil
+}
+
+// enqueue appends one event, dropping the oldest when the buffer is full
+func (s *WarehouseExportService) enqueue(idDevice string, event models.WarehouseEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buffer := append(s.buffers[idDevice], event)
+	if len(buffer) > warehouseMaxBuffered {
+		buffer = buffer[len(buffer)-warehouseMaxBuffered:]
+	}
+	s.buffers[idDevice] = buffer
+}
+
+// StartExporter ships buffered batches until Stop is called. Call in a
+// goroutine
+func (s *WarehouseExportService) StartExporter() {
+	logrus.Info("🏭 WAREHOUSE: Exporter started")
+	ticker := time.NewTicker(warehouseFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.flushAll()
+		}
+	}
+}
+
+// Stop stops the exporter
+func (s *WarehouseExportService) Stop() {
+	close(s.done)
+}
+
+// flushAll ships every buffered device with an enabled connector and discards
+// buffers for devices without one
+func (s *WarehouseExportService) flushAll() {
+	s.mu.Lock()
+	pending := s.buffers
+	s.buffers = make(map[string][]models.WarehouseEvent)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	configs, err := s.warehouseRepo.GetEnabledWarehouseExportConfigs()
+	if err != nil {
+		logrus.WithError(err).Error("🏭 WAREHOUSE: Failed to load connectors for flush")
+		s.requeue(pending)
+		return
+	}
+
+	byDevice := make(map[string]*models.WarehouseExportConfig, len(configs))
+	for i := range configs {
+		byDevice[configs[i].IDDevice] = &configs[i]
+	}
+
+	for idDevice, events := range pending {
+		config, ok := byDevice[idDevice]
+		if !ok {
+			continue
+		}
+		s.shipBatches(config, events)
+	}
+}
+
+// requeue puts unshipped events back at the front of the buffers
+func (s *WarehouseExportService) requeue(pending map[string][]models.WarehouseEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idDevice, events := range pending {
+		buffer := append(events, s.buffers[idDevice]...)
+		if len(buffer) > warehouseMaxBuffered {
+			buffer = buffer[len(buffer)-warehouseMaxBuffered:]
+		}
+		s.buffers[idDevice] = buffer
+	}
+}
+
+// shipBatches sends the device's events in batch-sized requests; a failed
+// batch and everything after it is requeued for the next flush
+func (s *WarehouseExportService) shipBatches(config *models.WarehouseExportConfig, events []models.WarehouseEvent) {
+	for start := 0; start < len(events); start += warehouseBatchSize {
+		end := start + warehouseBatchSize
+		if end > len(events) {
+			end = len(events)
+		}
+
+		if err := s.shipBatch(config, events[start:end]); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"id_device": config.IDDevice,
+				"events":    len(events) - start,
+			}).Warn("🏭 WAREHOUSE: Batch failed, requeueing")
+			s.requeue(map[string][]models.WarehouseEvent{config.IDDevice: events[start:]})
+			return
+		}
+	}
+}
+
+// shipBatch posts one batch as JSON lines
+func (s *WarehouseExportService) shipBatch(config *models.WarehouseExportConfig, events []models.WarehouseEvent) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest("POST", config.Endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if config.AuthHeader != "" {
+		req.Header.Set("Authorization", config.AuthHeader)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("warehouse endpoint returned status %d", resp.StatusCode)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device": config.IDDevice,
+		"events":    len(events),
+	}).Debug("🏭 WAREHOUSE: Batch shipped")
+
+	return nil
+}
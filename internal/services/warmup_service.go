@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// warmupMaxSpreadDelay caps the pacing delay so a nearly-unused quota late in
+// the day never stalls a send for hours
+const warmupMaxSpreadDelay = 5 * time.Minute
+
+// WarmupService enforces per-device daily send caps that grow over time so
+// new WhatsApp numbers are warmed up instead of blasting on day one. Sends
+// inside the cap are paced so the remaining quota spreads across the day
+type WarmupService struct {
+	warmupRepo          repository.WarmupRepository
+	notificationService *NotificationConnectorService
+}
+
+// quotaWarningFraction is how full the daily cap must be before the
+// quota-near-limit notification fires
+const quotaWarningFraction = 0.8
+
+// NewWarmupService creates a new warmup service
+func NewWarmupService(warmupRepo repository.WarmupRepository) *WarmupService {
+	return &WarmupService{warmupRepo: warmupRepo}
+}
+
+// SetNotificationService wires quota warnings into Slack/Discord connectors
+func (s *WarmupService) SetNotificationService(notificationService *NotificationConnectorService) {
+	s.notificationService = notificationService
+}
+
+// ReserveSend checks the device's warm-up cap for one proactive send. When
+// allowed the send is counted and delay tells the caller how long to pace
+// before sending so traffic spreads over the rest of the day
+func (s *WarmupService) ReserveSend(idDevice string) (allowed bool, delay time.Duration, err error) {
+	profile, err := s.warmupRepo.GetWarmupProfile(idDevice)
+	if err != nil {
+		return false, 0, err
+	}
+	if profile == nil || !profile.Enabled {
+		return true, 0, nil
+	}
+
+	now := time.Now()
+	cap := profile.CapForDate(now)
+	used, err := s.warmupRepo.GetDailySendCount(idDevice, now)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if used >= cap {
+		logrus.WithFields(logrus.Fields{
+			"id_device": idDevice,
+			"cap":       cap,
+			"used":      used,
+		}).Warn("🔥 WARMUP: Daily send cap reached, send blocked")
+		return false, 0, nil
+	}
+
+	if err := s.warmupRepo.IncrementDailySendCount(idDevice, now); err != nil {
+		return false, 0, err
+	}
+
+	// Warn the team's channel once when today's cap crosses the warning mark
+	threshold := int(float64(cap) * quotaWarningFraction)
+	if s.notificationService != nil && threshold > 0 && used < threshold && used+1 >= threshold {
+		s.notificationService.NotifyEvent(idDevice, models.OpsEventQuotaNearLimit,
+			fmt.Sprintf("Device %s has used %d of %d warm-up sends today (%.0f%%) — proactive sends will be blocked at the cap",
+				idDevice, used+1, cap, float64(used+1)/float64(cap)*100))
+	}
+
+	return true, s.spreadDelay(now, cap, used), nil
+}
+
+// spreadDelay paces sends so the remaining daily quota covers the remaining
+// hours of the day instead of being used up in one burst
+func (s *WarmupService) spreadDelay(now time.Time, cap, used int) time.Duration {
+	remaining := cap - used
+	if remaining <= 0 {
+		return 0
+	}
+
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+	delay := endOfDay.Sub(now) / time.Duration(remaining)
+	if delay > warmupMaxSpreadDelay {
+		delay = warmupMaxSpreadDelay
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+// GetProfile returns a device's warm-up profile with defaults when never set
+func (s *WarmupService) GetProfile(idDevice string) (*models.WarmupProfile, error) {
+	profile, err := s.warmupRepo.GetWarmupProfile(idDevice)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &models.WarmupProfile{
+			IDDevice:     idDevice,
+			StartedAt:    time.Now(),
+			BaseCap:      50,
+			GrowthPerDay: 25,
+			MaxCap:       1000,
+		}
+	}
+	return profile, nil
+}
+
+// SaveProfile validates and saves a device's warm-up profile. A zero
+// started_at defaults to today so enabling warm-up starts the schedule now
+func (s *WarmupService) SaveProfile(profile *models.WarmupProfile) error {
+	if profile.BaseCap <= 0 || profile.GrowthPerDay < 0 || profile.MaxCap < profile.BaseCap {
+		return fmt.Errorf("base_cap must be positive, growth_per_day non-negative, and max_cap at least base_cap")
+	}
+	if profile.StartedAt.IsZero() {
+		profile.StartedAt = time.Now()
+	}
+	return s.warmupRepo.SetWarmupProfile(profile)
+}
+
+// GetUsage returns today's cap and how much of it is used
+func (s *WarmupService) GetUsage(idDevice string) (cap, used int, err error) {
+	profile, err := s.warmupRepo.GetWarmupProfile(idDevice)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	now := time.Now()
+	used, err = s.warmupRepo.GetDailySendCount(idDevice, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if profile == nil || !profile.Enabled {
+		return 0, used, nil
+	}
+	return profile.CapForDate(now), used, nil
+}
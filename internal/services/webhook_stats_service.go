@@ -0,0 +1,118 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// WebhookStatsService collects per-provider metrics from the webhook normalization
+// layer (volume, parse failures by field, extract→send latency, group-message skips)
+// so problematic provider payloads can be spotted without digging through logs.
+// Counters are in-memory and reset on restart
+type WebhookStatsService struct {
+	mu    sync.Mutex
+	stats map[string]*providerStats
+}
+
+// providerStats accumulates counters for a single provider
+type providerStats struct {
+	received      int64
+	groupSkips    int64
+	parseFailures map[string]int64
+	totalLatency  time.Duration
+	latencyCount  int64
+}
+
+// ProviderWebhookStats is the serializable snapshot returned to the API
+type ProviderWebhookStats struct {
+	Provider          string           `json:"provider"`
+	Received          int64            `json:"received"`
+	GroupSkips        int64            `json:"group_skips"`
+	ParseFailures     map[string]int64 `json:"parse_failures_by_field"`
+	ProcessedMessages int64            `json:"processed_messages"`
+	AvgLatencyMs      float64          `json:"avg_extract_to_send_latency_ms"`
+}
+
+// NewWebhookStatsService creates a new webhook stats service
+func NewWebhookStatsService() *WebhookStatsService {
+	return &WebhookStatsService{
+		stats: make(map[string]*providerStats),
+	}
+}
+
+// providerEntry returns the stats bucket for a provider, creating it if needed.
+// Caller must hold the mutex
+func (s *WebhookStatsService) providerEntry(provider string) *providerStats {
+	if provider == "" {
+		provider = "generic"
+	}
+	entry, ok := s.stats[provider]
+	if !ok {
+		entry = &providerStats{parseFailures: make(map[string]int64)}
+		s.stats[provider] = entry
+	}
+	return entry
+}
+
+// RecordReceived counts an incoming webhook payload for a provider
+func (s *WebhookStatsService) RecordReceived(provider string) {
+	if provider == "" {
+		provider = "generic"
+	}
+	Metrics().IncCounter(MetricWebhookReceived, map[string]string{"provider": provider})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providerEntry(provider).received++
+}
+
+// RecordParseFailure counts a payload where a required field could not be extracted
+func (s *WebhookStatsService) RecordParseFailure(provider, field string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providerEntry(provider).parseFailures[field]++
+}
+
+// RecordGroupSkip counts a group message that was skipped during normalization
+func (s *WebhookStatsService) RecordGroupSkip(provider string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providerEntry(provider).groupSkips++
+}
+
+// RecordProcessingLatency records the extract→send latency for one message
+func (s *WebhookStatsService) RecordProcessingLatency(provider string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.providerEntry(provider)
+	entry.totalLatency += latency
+	entry.latencyCount++
+}
+
+// GetStats returns a snapshot of all per-provider webhook metrics
+func (s *WebhookStatsService) GetStats() []ProviderWebhookStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make([]ProviderWebhookStats, 0, len(s.stats))
+	for provider, entry := range s.stats {
+		failures := make(map[string]int64, len(entry.parseFailures))
+		for field, count := range entry.parseFailures {
+			failures[field] = count
+		}
+
+		avgLatencyMs := 0.0
+		if entry.latencyCount > 0 {
+			avgLatencyMs = float64(entry.totalLatency.Milliseconds()) / float64(entry.latencyCount)
+		}
+
+		snapshot = append(snapshot, ProviderWebhookStats{
+			Provider:          provider,
+			Received:          entry.received,
+			GroupSkips:        entry.groupSkips,
+			ParseFailures:     failures,
+			ProcessedMessages: entry.latencyCount,
+			AvgLatencyMs:      avgLatencyMs,
+		})
+	}
+	return snapshot
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Redis Streams backing for the webhook message queue. Incoming webhook
+// messages are journaled to a stream and consumed through a consumer group,
+// so messages survive process restarts and deploys instead of dying with the
+// in-memory channel. Entries are acknowledged after processing; anything a
+// previous process left pending is claimed and replayed on startup
+
+const (
+	webhookStreamKey   = "queue:webhook_stream"
+	webhookStreamGroup = "webhook-workers"
+
+	// webhookStreamMaxLen caps the stream so a stalled consumer cannot grow
+	// Redis without bound; trimming is approximate for performance
+	webhookStreamMaxLen = 50000
+
+	// webhookStreamBlock is how long a read waits for new entries
+	webhookStreamBlock = 5 * time.Second
+)
+
+// Webhook attempt idempotency tokens, mirroring the flow continuation
+// tokens: each (message, attempt) pair may only be processed once, so a
+// redelivered duplicate is dropped while a numbered retry still runs
+const (
+	webhookAttemptTokenPrefix = "queue:webhook_attempt:"
+	webhookAttemptTokenTTL    = 10 * time.Minute
+)
+
+// ClaimWebhookAttempt claims the idempotency token for one processing
+// attempt of a webhook message; only the first claim succeeds
+func (s *QueueService) ClaimWebhookAttempt(idempotencyKey string, attempt int) bool {
+	if s.redis == nil {
+		return true
+	}
+
+	key := fmt.Sprintf("%s%s:%d", webhookAttemptTokenPrefix, idempotencyKey, attempt)
+	claimed, err := s.redis.SetNX(context.Background(), key, 1, webhookAttemptTokenTTL).Result()
+	if err != nil {
+		logrus.WithError(err).Warn("📨 STREAM: Failed to claim webhook attempt token, processing anyway")
+		return true
+	}
+	return claimed
+}
+
+// WebhookStreamEntry is one journaled webhook message read from the stream
+type WebhookStreamEntry struct {
+	StreamID string
+	Payload  string
+}
+
+// WebhookStreamAvailable reports whether the durable webhook queue can be
+// used; without Redis the caller falls back to the in-memory channel
+func (s *QueueService) WebhookStreamAvailable() bool {
+	return s.redis != nil
+}
+
+// EnsureWebhookStreamGroup creates the consumer group (and stream) if needed
+func (s *QueueService) EnsureWebhookStreamGroup() error {
+	if s.redis == nil {
+		return fmt.Errorf("redis not available")
+	}
+
+	ctx := context.Background()
+	err := s.redis.XGroupCreateMkStream(ctx, webhookStreamKey, webhookStreamGroup, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create webhook stream group: %w", err)
+	}
+	return nil
+}
+
+// EnqueueWebhookMessage journals a webhook message payload to the stream
+func (s *QueueService) EnqueueWebhookMessage(payload string) error {
+	if s.redis == nil {
+		return fmt.Errorf("redis not available")
+	}
+
+	ctx := context.Background()
+	err := s.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: webhookStreamKey,
+		MaxLen: webhookStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"payload": payload},
+	}).Err()
+	if err != nil {
+		if s.queueMonitor != nil {
+			s.queueMonitor.RecordError()
+		}
+		return fmt.Errorf("failed to enqueue webhook message: %w", err)
+	}
+	return nil
+}
+
+// ReadWebhookMessages blocks for new stream entries assigned to this consumer
+func (s *QueueService) ReadWebhookMessages(consumer string, count int64) ([]WebhookStreamEntry, error) {
+	if s.redis == nil {
+		return nil, fmt.Errorf("redis not available")
+	}
+
+	ctx := context.Background()
+	streams, err := s.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    webhookStreamGroup,
+		Consumer: consumer,
+		Streams:  []string{webhookStreamKey, ">"},
+		Count:    count,
+		Block:    webhookStreamBlock,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // No new entries within the block window
+		}
+		return nil, fmt.Errorf("failed to read webhook stream: %w", err)
+	}
+
+	return flattenWebhookStreams(streams), nil
+}
+
+// ClaimPendingWebhookMessages takes over every entry still pending in the
+// group - delivered to a consumer but never acknowledged - so messages in
+// flight when the previous process died are replayed after a restart
+func (s *QueueService) ClaimPendingWebhookMessages(consumer string) ([]WebhookStreamEntry, error) {
+	if s.redis == nil {
+		return nil, fmt.Errorf("redis not available")
+	}
+
+	ctx := context.Background()
+	var entries []WebhookStreamEntry
+	start := "0-0"
+	for {
+		messages, next, err := s.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   webhookStreamKey,
+			Group:    webhookStreamGroup,
+			Consumer: consumer,
+			MinIdle:  0,
+			Start:    start,
+			Count:    100,
+		}).Result()
+		if err != nil {
+			return entries, fmt.Errorf("failed to claim pending webhook messages: %w", err)
+		}
+		for _, message := range messages {
+			if payload, ok := message.Values["payload"].(string); ok {
+				entries = append(entries, WebhookStreamEntry{StreamID: message.ID, Payload: payload})
+			}
+		}
+		if next == "0-0" || len(messages) == 0 {
+			break
+		}
+		start = next
+	}
+
+	return entries, nil
+}
+
+// AckWebhookMessage acknowledges and deletes a processed stream entry
+func (s *QueueService) AckWebhookMessage(streamID string) {
+	if s.redis == nil {
+		return
+	}
+
+	ctx := context.Background()
+	if err := s.redis.XAck(ctx, webhookStreamKey, webhookStreamGroup, streamID).Err(); err != nil {
+		logrus.WithError(err).WithField("stream_id", streamID).Warn("📨 STREAM: Failed to ack webhook message")
+		return
+	}
+	if err := s.redis.XDel(ctx, webhookStreamKey, streamID).Err(); err != nil {
+		logrus.WithError(err).WithField("stream_id", streamID).Warn("📨 STREAM: Failed to delete acked webhook message")
+	}
+}
+
+// WebhookStreamDepth returns how many entries the stream currently holds
+func (s *QueueService) WebhookStreamDepth() int64 {
+	if s.redis == nil {
+		return 0
+	}
+
+	depth, err := s.redis.XLen(context.Background(), webhookStreamKey).Result()
+	if err != nil {
+		return 0
+	}
+	return depth
+}
+
+// flattenWebhookStreams extracts the payload entries from an XReadGroup result
+func flattenWebhookStreams(streams []redis.XStream) []WebhookStreamEntry {
+	var entries []WebhookStreamEntry
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			if payload, ok := message.Values["payload"].(string); ok {
+				entries = append(entries, WebhookStreamEntry{StreamID: message.ID, Payload: payload})
+			}
+		}
+	}
+	return entries
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
 	"time"
 
@@ -31,6 +32,9 @@ type WebSocketService struct {
 
 	// Connection cleanup
 	cleanupTicker *time.Ticker
+
+	// Optional PII masking for viewer-role connections
+	pii *PIIService
 }
 
 // ConnectionInfo holds connection details with metadata for leak prevention
@@ -40,6 +44,8 @@ type ConnectionInfo struct {
 	LastPong  time.Time
 	CreatedAt time.Time
 	cancel    context.CancelFunc
+	// Viewer marks connections whose user only sees masked PII
+	Viewer bool
 }
 
 // BroadcastMessage represents a message to be broadcast
@@ -107,6 +113,16 @@ func (ws *WebSocketService) HandleWebSocket(c *fiber.Ctx) error {
 		ws.registerConnection(deviceID, conn)
 		defer ws.unregisterConnection(deviceID)
 
+		// Connections may identify their user; viewer-role users receive
+		// masked PII in every event
+		if userID := c.Query("user_id"); userID != "" && ws.pii != nil && ws.pii.IsViewer(userID) {
+			ws.connMutex.Lock()
+			if connInfo, exists := ws.connections[deviceID]; exists {
+				connInfo.Viewer = true
+			}
+			ws.connMutex.Unlock()
+		}
+
 		logrus.WithField("device_id", deviceID).Info("WebSocket connection established")
 
 		// Handle incoming messages
@@ -127,6 +143,12 @@ func (ws *WebSocketService) HandleWebSocket(c *fiber.Ctx) error {
 	})(c)
 }
 
+// SetPIIService wires role-based PII masking into outgoing events: viewer
+// connections receive masked phone numbers and emails
+func (ws *WebSocketService) SetPIIService(pii *PIIService) {
+	ws.pii = pii
+}
+
 // registerConnection adds a new WebSocket connection with proper metadata tracking
 func (ws *WebSocketService) registerConnection(deviceID string, conn *websocket.Conn) {
 	ws.connMutex.Lock()
@@ -254,6 +276,13 @@ func (ws *WebSocketService) handleBroadcasts() {
 
 // sendToConnection sends a message to a specific WebSocket connection
 func (ws *WebSocketService) sendToConnection(connInfo *ConnectionInfo, msg *BroadcastMessage, deviceID string) {
+	// Viewer connections get PII fields masked in the event payload
+	if connInfo.Viewer && ws.pii != nil {
+		masked := *msg
+		masked.Data = ws.maskEventData(msg.Data)
+		msg = &masked
+	}
+
 	// Set write deadline for performance
 	connInfo.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 
@@ -265,6 +294,23 @@ func (ws *WebSocketService) sendToConnection(connInfo *ConnectionInfo, msg *Broa
 	}
 }
 
+// maskEventData runs an event payload through the PII masker. Typed structs
+// go through a JSON round-trip so the generic masker can walk their fields
+func (ws *WebSocketService) maskEventData(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var generic interface{}
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return data
+	}
+	return ws.pii.MaskValue(generic)
+}
+
 // sendToConnectionBytes sends a message to a specific connection with proper error handling
 func (ws *WebSocketService) sendToConnectionBytes(connInfo *ConnectionInfo, message []byte) {
 	defer func() {
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts time.Now and time.Sleep so services that pace outbound
+// messages or schedule work can be driven deterministically in tests
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	Since(t time.Time) time.Duration
+}
+
+// systemClock delegates to the real time package
+type systemClock struct{}
+
+// NewSystemClock returns the Clock used in production
+func NewSystemClock() Clock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time                  { return time.Now() }
+func (systemClock) Sleep(d time.Duration)           { time.Sleep(d) }
+func (systemClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+// FakeClock is a Clock for tests: Sleep returns immediately while advancing the
+// fake time, and every requested delay is recorded for assertions
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+// NewFakeClock creates a fake clock starting at the given time
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the current fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the fake time by d without blocking and records the delay
+func (c *FakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+	c.sleeps = append(c.sleeps, d)
+}
+
+// Since returns the fake time elapsed since t
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now.Sub(t)
+}
+
+// Advance moves the fake time forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Sleeps returns every delay requested through Sleep, in order
+func (c *FakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sleeps := make([]time.Duration, len(c.sleeps))
+	copy(sleeps, c.sleeps)
+	return sleeps
+}
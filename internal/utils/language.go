@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DetectLanguage guesses the language of a short chat message from stopword
+// hits so flow nodes can pick the matching translation. It recognizes the
+// languages the platform's customers actually write in - Malay ("ms"),
+// English ("en") and Chinese ("zh") - and returns "" when the message is too
+// ambiguous to call, so callers keep whatever language was stored before
+
+// languageStopwords maps a language code to words that strongly indicate it.
+// Malay and English share a lot of loanwords in chat, so only unambiguous
+// words are listed
+var languageStopwords = map[string][]string{
+	"ms": {
+		"saya", "awak", "anda", "boleh", "tak", "tidak", "nak", "hendak",
+		"macam", "mana", "berapa", "harga", "ada", "sudah", "belum", "ini",
+		"itu", "dengan", "untuk", "kami", "kita", "terima", "kasih", "tolong",
+		"bila", "kenapa", "apa", "yang", "dan", "atau", "dalam", "dari",
+	},
+	"en": {
+		"i", "you", "the", "is", "are", "was", "can", "could", "would",
+		"how", "what", "when", "where", "why", "much", "price", "have",
+		"do", "does", "this", "that", "with", "for", "we", "thanks",
+		"thank", "please", "and", "or", "in", "from", "my", "your",
+	},
+}
+
+// DetectLanguage returns the best-guess language code for the text, or ""
+// when no language is clearly ahead
+func DetectLanguage(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return ""
+	}
+
+	// Chinese is detected by script rather than stopwords
+	hanCount := 0
+	for _, r := range trimmed {
+		if unicode.Is(unicode.Han, r) {
+			hanCount++
+		}
+	}
+	if hanCount >= 2 {
+		return "zh"
+	}
+
+	words := strings.Fields(strings.ToLower(trimmed))
+	if len(words) == 0 {
+		return ""
+	}
+
+	scores := make(map[string]int)
+	for language, stopwords := range languageStopwords {
+		for _, word := range words {
+			cleaned := strings.Trim(word, ".,!?;:\"'()")
+			for _, stopword := range stopwords {
+				if cleaned == stopword {
+					scores[language]++
+					break
+				}
+			}
+		}
+	}
+
+	best, bestScore := "", 0
+	tied := false
+	for language, score := range scores {
+		if score > bestScore {
+			best, bestScore = language, score
+			tied = false
+		} else if score == bestScore && score > 0 {
+			tied = true
+		}
+	}
+
+	if bestScore == 0 || tied {
+		return ""
+	}
+	return best
+}
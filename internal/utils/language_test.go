@@ -0,0 +1,50 @@
+package utils
+
+import "testing"
+
+// TestDetectLanguage verifies the stopword/script heuristics pick the right
+// language for typical chat messages and stay silent on ambiguous input
+func TestDetectLanguage(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected string
+		desc     string
+	}{
+		{
+			text:     "berapa harga untuk produk ini",
+			expected: "ms",
+			desc:     "Malay question is detected",
+		},
+		{
+			text:     "how much is the price for this",
+			expected: "en",
+			desc:     "English question is detected",
+		},
+		{
+			text:     "请问这个多少钱",
+			expected: "zh",
+			desc:     "Chinese script is detected",
+		},
+		{
+			text:     "ok",
+			expected: "",
+			desc:     "Ambiguous short reply returns empty",
+		},
+		{
+			text:     "",
+			expected: "",
+			desc:     "Empty input returns empty",
+		},
+		{
+			text:     "123 456",
+			expected: "",
+			desc:     "Numbers only return empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := DetectLanguage(tc.text); got != tc.expected {
+			t.Errorf("%s: DetectLanguage(%q) = %q, want %q", tc.desc, tc.text, got, tc.expected)
+		}
+	}
+}
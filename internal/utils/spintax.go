@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"regexp"
+	"strings"
+)
+
+// Spintax lets a single message template carry several phrasings:
+// "{Hi|Hello|Hey} {there|friend}" expands to one of the combinations.
+// Sending varied copy per recipient makes bulk sends look less like
+// identical spam to WhatsApp's filters.
+//
+// Groups may be nested ({Good {morning|evening}|Hi}); the innermost
+// groups are resolved first. Braces without a pipe (including {{variable}}
+// placeholders) are left untouched, so spintax can be applied safely
+// after or before variable replacement.
+
+// spintaxGroupPattern matches the innermost {a|b|c} group - no nested
+// braces inside, at least one pipe so plain braces survive
+var spintaxGroupPattern = regexp.MustCompile(`\{[^{}|]*(\|[^{}|]*)+\}`)
+
+// maxSpintaxDepth caps nesting resolution so malformed input cannot loop
+const maxSpintaxDepth = 10
+
+// ExpandSpintax resolves every spintax group in text with a random choice.
+// Each call can produce a different expansion
+func ExpandSpintax(text string) string {
+	return expandSpintax(text, func(n int) int { return rand.Intn(n) })
+}
+
+// ExpandSpintaxForRecipient resolves spintax deterministically for one
+// recipient: the same recipient always gets the same variant of a given
+// template, while different recipients get different ones
+func ExpandSpintaxForRecipient(text, recipient string) string {
+	if recipient == "" {
+		return ExpandSpintax(text)
+	}
+
+	hasher := fnv.New64a()
+	hasher.Write([]byte(recipient))
+	hasher.Write([]byte(text))
+	rng := rand.New(rand.NewSource(int64(hasher.Sum64())))
+
+	return expandSpintax(text, rng.Intn)
+}
+
+// expandSpintax resolves groups innermost-first, re-scanning until no
+// group remains so nested spintax collapses level by level
+func expandSpintax(text string, pick func(n int) int) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+
+	for depth := 0; depth < maxSpintaxDepth; depth++ {
+		replaced := false
+		text = spintaxGroupPattern.ReplaceAllStringFunc(text, func(group string) string {
+			replaced = true
+			options := strings.Split(group[1:len(group)-1], "|")
+			return options[pick(len(options))]
+		})
+		if !replaced {
+			break
+		}
+	}
+
+	return text
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestExpandSpintax verifies spintax groups resolve to one of their options
+// while plain text and {{variable}} placeholders pass through untouched
+func TestExpandSpintax(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected []string
+		desc     string
+	}{
+		{
+			text:     "no spintax here",
+			expected: []string{"no spintax here"},
+			desc:     "Plain text is returned unchanged",
+		},
+		{
+			text:     "{Hi|Hello} there",
+			expected: []string{"Hi there", "Hello there"},
+			desc:     "Single group picks one option",
+		},
+		{
+			text:     "{Good {morning|evening}|Hi}",
+			expected: []string{"Good morning", "Good evening", "Hi"},
+			desc:     "Nested groups resolve innermost first",
+		},
+		{
+			text:     "Hello {{name}}",
+			expected: []string{"Hello {{name}}"},
+			desc:     "Variable placeholders are left for ReplaceVariables",
+		},
+		{
+			text:     "{Hi|Hello} {{name}}, {how are you|hope you are well}",
+			expected: []string{"Hi {{name}}, how are you", "Hi {{name}}, hope you are well", "Hello {{name}}, how are you", "Hello {{name}}, hope you are well"},
+			desc:     "Groups and placeholders mix in one template",
+		},
+	}
+
+	for _, tc := range testCases {
+		// Expand repeatedly - every result must be a valid combination
+		for i := 0; i < 20; i++ {
+			result := ExpandSpintax(tc.text)
+
+			valid := false
+			for _, expected := range tc.expected {
+				if result == expected {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				t.Errorf("%s: got %q, want one of %v", tc.desc, result, tc.expected)
+				break
+			}
+		}
+	}
+}
+
+// TestExpandSpintaxForRecipient verifies the per-recipient expansion is
+// stable for one recipient and varies across recipients
+func TestExpandSpintaxForRecipient(t *testing.T) {
+	text := "{Hi|Hello|Hey} {there|friend}, {how are you|hope you are well|good to see you}"
+
+	first := ExpandSpintaxForRecipient(text, "60123456789")
+	for i := 0; i < 10; i++ {
+		if got := ExpandSpintaxForRecipient(text, "60123456789"); got != first {
+			t.Fatalf("Expansion not stable for same recipient: %q vs %q", got, first)
+		}
+	}
+
+	// With 18 combinations, 50 recipients should produce at least one variant
+	// different from the first recipient's
+	varied := false
+	for i := 0; i < 50; i++ {
+		recipient := "6012345" + strings.Repeat("9", i%5) + string(rune('0'+i%10))
+		if ExpandSpintaxForRecipient(text, recipient) != first {
+			varied = true
+			break
+		}
+	}
+	if !varied {
+		t.Error("Expansion did not vary across recipients")
+	}
+}
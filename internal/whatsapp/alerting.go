@@ -0,0 +1,31 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/services"
+)
+
+// SetAlertingService wires failure-rate tracking into outbound sends
+func (s *Service) SetAlertingService(alertingService *services.AlertingService) {
+	s.alertingService = alertingService
+}
+
+// SetStatusService wires provider connectivity into the public status page
+func (s *Service) SetStatusService(statusService *services.StatusService) {
+	s.statusService = statusService
+}
+
+// recordSendResult feeds one provider send outcome into the alerting service
+// so failure-rate anomalies surface on the dashboard, counts successful
+// sends for digest reporting, and feeds the public status page's provider
+// connectivity window
+func (s *Service) recordSendResult(deviceID, provider string, sendErr error) {
+	if s.alertingService != nil {
+		s.alertingService.RecordSendResult(deviceID, provider, sendErr != nil)
+	}
+	if s.digestService != nil && sendErr == nil {
+		s.digestService.CountOutbound(deviceID)
+	}
+	if s.statusService != nil {
+		s.statusService.RecordProviderResult(provider, sendErr == nil)
+	}
+}
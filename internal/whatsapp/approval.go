@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetApprovalService wires review-before-send mode into AI responses: flows
+// with review enabled queue their generated messages for an agent instead of
+// sending them directly
+func (s *Service) SetApprovalService(approvalService *services.ApprovalService) {
+	s.approvalService = approvalService
+}
+
+// reviewEnabled reports whether the flow's AI responses need human approval
+func (s *Service) reviewEnabled(flowID string) bool {
+	return s.approvalService != nil && s.approvalService.IsReviewEnabled(flowID)
+}
+
+// enqueueForApproval holds a parsed AI response in the approval queue. The
+// stage update still applies immediately - only the outbound messages wait
+// for the reviewer
+func (s *Service) enqueueForApproval(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, parsedResponse *services.AIWhatsappResponse) {
+	for _, item := range parsedResponse.Response {
+		messageType := item.Type
+		if messageType != "image" && messageType != "audio" && messageType != "video" {
+			messageType = "text"
+		}
+
+		err := s.approvalService.Enqueue(&models.PendingMessage{
+			FlowID:      flow.ID,
+			NodeID:      node.ID,
+			ProspectNum: execution.ProspectNum,
+			IDDevice:    execution.IDDevice,
+			MessageType: messageType,
+			Content:     item.Content,
+		})
+		if err != nil {
+			logrus.WithError(err).Error("✋ APPROVAL: Failed to queue AI response for review")
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id":      flow.ID,
+		"node_id":      node.ID,
+		"prospect_num": execution.ProspectNum,
+		"messages":     len(parsedResponse.Response),
+	}).Info("✋ APPROVAL: AI response held for review")
+}
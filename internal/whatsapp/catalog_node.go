@@ -0,0 +1,230 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Catalog nodes send the device's active products as a numbered, paginated
+// list. The prospect replies with a number to pick a product (or "next" for
+// the following page); the pick is stored as a flow variable and also routes
+// conditioned edges, so sales flows can branch per product.
+
+// defaultCatalogPageSize is how many products one catalog page lists when the
+// node does not configure a page size
+const defaultCatalogPageSize = 5
+
+// processCatalogNode sends the first catalog page and waits for the prospect's
+// selection, behaving like a user_reply node afterwards
+func (s *Service) processCatalogNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if s.productService == nil {
+		logrus.Warn("🛒 CATALOG: Product service not available, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	products, err := s.productService.GetActiveProducts(execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Error("🛒 CATALOG: Failed to load products")
+		return "", err
+	}
+	if len(products) == 0 {
+		logrus.WithField("id_device", execution.IDDevice).Warn("🛒 CATALOG: Device has no active products, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	config := node.CatalogConfig()
+	s.setCatalogPage(execution, 0)
+
+	page := services.FormatCatalogPage(products, 0, config.PageSize)
+	response := page
+	if config.Message != "" {
+		response = config.Message + "\n\n" + page
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":       node.ID,
+		"product_count": len(products),
+		"prospect_num":  execution.ProspectNum,
+	}).Info("🛒 CATALOG: Sending product catalog, waiting for selection")
+
+	// Park on this node waiting for the prospect's pick, like user_reply
+	if err := s.updateFlowTrackingFields(execution, node.ID, flow.ID, true); err != nil {
+		logrus.WithError(err).Error("🛒 CATALOG: Failed to set waiting state")
+		return "", err
+	}
+
+	return response, nil
+}
+
+// handleCatalogReply interprets the prospect's reply to a catalog page. It
+// returns the translated input (the chosen product name) when a product was
+// picked; handled=true means the reply was consumed (next page sent or pick
+// invalid) and the execution stays parked on the catalog node
+func (s *Service) handleCatalogReply(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, bool, error) {
+	if s.productService == nil {
+		return userInput, false, nil
+	}
+
+	products, err := s.productService.GetActiveProducts(execution.IDDevice)
+	if err != nil || len(products) == 0 {
+		return userInput, false, err
+	}
+
+	config := node.CatalogConfig()
+	pageSize := config.PageSize
+	if pageSize < 1 {
+		pageSize = defaultCatalogPageSize
+	}
+
+	reply := strings.ToLower(strings.TrimSpace(userInput))
+
+	// Cart commands ("add 2", "add 2 x3", "remove 2") keep the prospect on the
+	// catalog so they can build up an order before the summary node
+	if handled, err := s.handleCartCommand(execution, products, reply); handled {
+		return "", true, err
+	}
+
+	// Pagination: show the following page and keep waiting
+	if reply == "next" || reply == "more" {
+		page := s.catalogPage(execution) + 1
+		pageText := services.FormatCatalogPage(products, page, pageSize)
+		if pageText == "" {
+			// Past the last page - wrap around to the first
+			page = 0
+			pageText = services.FormatCatalogPage(products, page, pageSize)
+		}
+		s.setCatalogPage(execution, page)
+
+		logrus.WithFields(logrus.Fields{
+			"node_id": node.ID,
+			"page":    page,
+		}).Info("🛒 CATALOG: Sending next catalog page")
+
+		return "", true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, pageText)
+	}
+
+	// A number picks the product with that list position
+	if pick, err := strconv.Atoi(reply); err == nil {
+		if pick < 1 || pick > len(products) {
+			prompt := fmt.Sprintf("Please reply with a number between 1 and %d", len(products))
+			return "", true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, prompt)
+		}
+
+		product := products[pick-1]
+		s.clearCatalogPage(execution)
+
+		// A direct pick also lands in the cart so an order summary node
+		// downstream can capture it
+		if s.cartService != nil {
+			if err := s.cartService.AddToCart(execution.ProspectNum, execution.IDDevice, &product, 1); err != nil {
+				logrus.WithError(err).Error("🛒 CATALOG: Failed to add picked product to cart")
+			}
+		}
+
+		variable := config.Variable
+		if variable == "" {
+			variable = "selected_product"
+		}
+		variables := map[string]interface{}{
+			variable:         product.Name,
+			"selected_sku":   product.SKU,
+			"selected_price": fmt.Sprintf("%.2f", product.Price),
+		}
+		if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, variables, "active"); err != nil {
+			logrus.WithError(err).Error("🛒 CATALOG: Failed to store product selection")
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"node_id":  node.ID,
+			"product":  product.Name,
+			"sku":      product.SKU,
+			"variable": variable,
+		}).Info("✅ CATALOG: Prospect selected a product")
+
+		// Route downstream edges on the product name
+		return product.Name, false, nil
+	}
+
+	// Free-text reply - let condition edges route on it unchanged
+	return userInput, false, nil
+}
+
+// handleCartCommand parses "add <n> [x<qty>]" and "remove <n>" replies against
+// the catalog numbering. Returns handled=true when the reply was a cart command
+func (s *Service) handleCartCommand(execution *models.AIWhatsapp, products []models.Product, reply string) (bool, error) {
+	if s.cartService == nil {
+		return false, nil
+	}
+
+	fields := strings.Fields(reply)
+	if len(fields) < 2 {
+		return false, nil
+	}
+	action := fields[0]
+	if action != "add" && action != "remove" {
+		return false, nil
+	}
+
+	pick, err := strconv.Atoi(fields[1])
+	if err != nil || pick < 1 || pick > len(products) {
+		prompt := fmt.Sprintf("Please use a product number between 1 and %d, e.g. *%s 1*", len(products), action)
+		return true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, prompt)
+	}
+	product := products[pick-1]
+
+	if action == "remove" {
+		if err := s.cartService.RemoveFromCart(execution.ProspectNum, execution.IDDevice, product.ID); err != nil {
+			logrus.WithError(err).Error("🛒 CATALOG: Failed to remove product from cart")
+			return true, err
+		}
+		confirmation := fmt.Sprintf("Removed *%s* from your cart", product.Name)
+		return true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, confirmation)
+	}
+
+	quantity := 1
+	if len(fields) >= 3 {
+		qtyText := strings.TrimPrefix(fields[2], "x")
+		if qty, err := strconv.Atoi(qtyText); err == nil && qty > 0 {
+			quantity = qty
+		}
+	}
+
+	if err := s.cartService.AddToCart(execution.ProspectNum, execution.IDDevice, &product, quantity); err != nil {
+		logrus.WithError(err).Error("🛒 CATALOG: Failed to add product to cart")
+		return true, err
+	}
+
+	confirmation := fmt.Sprintf("Added %dx *%s* to your cart. Reply with more items, or continue when you are done", quantity, product.Name)
+	return true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, confirmation)
+}
+
+// catalogPage returns the current catalog page for a conversation
+func (s *Service) catalogPage(execution *models.AIWhatsapp) int {
+	s.catalogPagesMu.Lock()
+	defer s.catalogPagesMu.Unlock()
+	return s.catalogPages[catalogPageKey(execution)]
+}
+
+// setCatalogPage records the catalog page a conversation is viewing
+func (s *Service) setCatalogPage(execution *models.AIWhatsapp, page int) {
+	s.catalogPagesMu.Lock()
+	defer s.catalogPagesMu.Unlock()
+	s.catalogPages[catalogPageKey(execution)] = page
+}
+
+// clearCatalogPage drops pagination state once a product is picked
+func (s *Service) clearCatalogPage(execution *models.AIWhatsapp) {
+	s.catalogPagesMu.Lock()
+	defer s.catalogPagesMu.Unlock()
+	delete(s.catalogPages, catalogPageKey(execution))
+}
+
+func catalogPageKey(execution *models.AIWhatsapp) string {
+	return execution.ProspectNum + "|" + execution.IDDevice
+}
@@ -0,0 +1,127 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetCustomNodeService wires webhook-backed custom node execution into the
+// flow engine
+func (s *Service) SetCustomNodeService(customNodeService *services.CustomNodeService) {
+	s.customNodeService = customNodeService
+}
+
+// processCustomNode executes a partner-registered node type: the endpoint
+// receives the node config plus execution state and answers with effects and
+// a next-node decision. An unregistered type or a failed call falls through
+// to default-node handling so the flow never stalls on a partner endpoint
+func (s *Service) processCustomNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if s.customNodeService == nil {
+		return s.processDefaultNode(flow, execution, node, userInput)
+	}
+
+	nodeType, err := s.customNodeService.GetEnabledType(execution.IDDevice, string(node.Type))
+	if err != nil || nodeType == nil {
+		logrus.WithFields(logrus.Fields{
+			"node_id":   node.ID,
+			"node_type": node.Type,
+			"id_device": execution.IDDevice,
+		}).Warn("🧩 CUSTOM NODE: No enabled registration for node type, using default handling")
+		return s.processDefaultNode(flow, execution, node, userInput)
+	}
+
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get execution variables")
+		variables = make(map[string]interface{})
+	}
+
+	result, err := s.customNodeService.Execute(nodeType, &models.CustomNodeRequest{
+		NodeID:      node.ID,
+		NodeType:    string(node.Type),
+		Config:      node.Data,
+		IDDevice:    execution.IDDevice,
+		ProspectNum: execution.ProspectNum,
+		Stage:       execution.Stage.String,
+		UserInput:   userInput,
+		Variables:   variables,
+	})
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"node_id":   node.ID,
+			"node_type": node.Type,
+		}).Error("🧩 CUSTOM NODE: Execution failed, using default handling")
+		return s.processDefaultNode(flow, execution, node, userInput)
+	}
+
+	message := s.applyCustomNodeEffects(execution, result.Effects)
+
+	if len(result.Variables) > 0 {
+		if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, result.Variables, "active"); err != nil {
+			logrus.WithError(err).Error("🧩 CUSTOM NODE: Failed to merge returned variables")
+		}
+	}
+
+	// An explicit next-node decision overrides the default edge
+	if result.NextNodeID != "" {
+		nextNode, err := s.flowService.FindNodeByID(flow, result.NextNodeID)
+		if err != nil || nextNode == nil {
+			logrus.WithFields(logrus.Fields{
+				"node_id":   node.ID,
+				"next_node": result.NextNodeID,
+			}).Error("🧩 CUSTOM NODE: Returned next node does not exist in flow, using default edge")
+		} else {
+			s.updateCurrentNode(execution, nextNode.ID)
+			if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, nextNode.ID, make(map[string]interface{}), "active"); err != nil {
+				logrus.WithError(err).Error("🧩 CUSTOM NODE: Failed to advance to returned next node")
+				return message, err
+			}
+
+			nextResponse, err := s.processFlowMessage(flow, execution, userInput)
+			if err != nil {
+				return message, err
+			}
+			return combineCustomNodeResponses(message, nextResponse), nil
+		}
+	}
+
+	nextResponse, err := s.processDefaultNode(flow, execution, node, userInput)
+	if err != nil {
+		return message, err
+	}
+	return combineCustomNodeResponses(message, nextResponse), nil
+}
+
+// applyCustomNodeEffects delivers media effects directly and collects message
+// effects into the response text
+func (s *Service) applyCustomNodeEffects(execution *models.AIWhatsapp, effects []models.CustomNodeEffect) string {
+	message := ""
+	for _, effect := range effects {
+		switch effect.Type {
+		case models.CustomNodeEffectMessage:
+			message = combineCustomNodeResponses(message, effect.Content)
+		case models.CustomNodeEffectImage, models.CustomNodeEffectAudio, models.CustomNodeEffectVideo:
+			if err := s.SendMediaMessage(execution.IDDevice, execution.ProspectNum, effect.MediaURL); err != nil {
+				logrus.WithError(err).WithField("media_url", effect.MediaURL).Error("🧩 CUSTOM NODE: Failed to send media effect")
+			}
+			if effect.Content != "" {
+				message = combineCustomNodeResponses(message, effect.Content)
+			}
+		}
+	}
+	return message
+}
+
+// combineCustomNodeResponses joins two response fragments like the engine's
+// other node processors do
+func combineCustomNodeResponses(first, second string) string {
+	if first == "" {
+		return second
+	}
+	if second == "" {
+		return first
+	}
+	return first + "\n" + second
+}
@@ -0,0 +1,104 @@
+package whatsapp
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Retry and dead-letter handling for failed webhook messages. Each message
+// carries an idempotency key; every (key, attempt) pair is claimed once
+// through Redis before processing, so retries with exponential backoff are
+// safe against the duplicate processing that previously forced retries off.
+// Messages that exhaust their retries land in the dead-letter table for
+// operator review and requeueing.
+
+// webhookMaxRetries is how many attempts a message gets before dead-lettering
+const webhookMaxRetries = 3
+
+// webhookRetryBaseDelay is the first retry delay; it doubles per attempt
+const webhookRetryBaseDelay = 30 * time.Second
+
+// SetDeadLetterService injects the dead letter service for failed messages
+func (s *Service) SetDeadLetterService(deadLetterService *services.DeadLetterService) {
+	s.deadLetterService = deadLetterService
+}
+
+// webhookIdempotencyKey identifies one incoming webhook message across
+// retries and stream redeliveries
+func webhookIdempotencyKey(deviceID, phoneNumber, content string, timestamp time.Time) string {
+	hash := md5.Sum([]byte(fmt.Sprintf("%s|%s|%s|%d", deviceID, phoneNumber, content, timestamp.UnixNano())))
+	return hex.EncodeToString(hash[:])
+}
+
+// claimWebhookAttempt reports whether this attempt of the message may run;
+// a duplicate delivery of an already-claimed attempt is dropped
+func (s *Service) claimWebhookAttempt(msg *WebhookMessage) bool {
+	if msg.IdempotencyKey == "" || s.queueService == nil {
+		return true
+	}
+	return s.queueService.ClaimWebhookAttempt(msg.IdempotencyKey, msg.Retries)
+}
+
+// handleProcessingFailure schedules the next retry with exponential backoff,
+// or dead-letters the message once its retries are exhausted
+func (s *Service) handleProcessingFailure(msg *WebhookMessage, procErr error) {
+	if msg.Retries < webhookMaxRetries {
+		retry := *msg
+		retry.Retries++
+		// The retried copy gets its own processing attempt; the original
+		// stream entry was already acknowledged
+		retry.StreamID = ""
+
+		delay := webhookRetryBaseDelay << uint(msg.Retries)
+		logrus.WithFields(logrus.Fields{
+			"device_id":    msg.DeviceID,
+			"phone_number": msg.PhoneNumber,
+			"attempt":      retry.Retries,
+			"max_retries":  webhookMaxRetries,
+			"retry_in":     delay,
+		}).Warn("🔁 RETRY: Message processing failed, scheduling retry")
+
+		time.AfterFunc(delay, func() {
+			select {
+			case s.messageQueue <- &retry:
+			default:
+				s.deadLetterMessage(&retry, fmt.Errorf("queue full during retry: %v", procErr))
+			}
+		})
+		return
+	}
+
+	s.deadLetterMessage(msg, procErr)
+}
+
+// deadLetterMessage records the message in the dead-letter table
+func (s *Service) deadLetterMessage(msg *WebhookMessage, procErr error) {
+	if s.deadLetterService == nil {
+		logrus.WithFields(logrus.Fields{
+			"device_id":    msg.DeviceID,
+			"phone_number": msg.PhoneNumber,
+		}).Error("💀 DEAD LETTER: Dead letter service not available, dropping failed message")
+		return
+	}
+
+	failed := &models.FailedMessage{
+		IdempotencyKey: msg.IdempotencyKey,
+		DeviceID:       msg.DeviceID,
+		ProspectNum:    msg.PhoneNumber,
+		Content:        msg.Content,
+		Provider:       msg.Provider,
+		SenderName:     msg.SenderName,
+		LastError:      procErr.Error(),
+		Retries:        msg.Retries,
+	}
+	if err := s.deadLetterService.Record(failed); err != nil {
+		logrus.WithError(err).Error("💀 DEAD LETTER: Failed to record dead-lettered message")
+	}
+}
@@ -0,0 +1,11 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/services"
+)
+
+// SetDigestService wires message-volume counting into inbound and outbound
+// processing so digests can report traffic per device
+func (s *Service) SetDigestService(digestService *services.DigestService) {
+	s.digestService = digestService
+}
@@ -0,0 +1,99 @@
+package whatsapp
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Document nodes fill a PDF template (invoice, quote) with the execution
+// variables and send the rendered file as a document message. Renders are
+// cached by template and personalized inputs, so identical documents are
+// only generated once.
+
+// SetDocumentService injects the document service used by document nodes
+func (s *Service) SetDocumentService(documentService *services.DocumentService) {
+	s.documentService = documentService
+}
+
+// processDocumentNode renders the template and sends the document URL
+func (s *Service) processDocumentNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if s.documentService == nil {
+		logrus.Warn("📄 DOCUMENT: Document service not available, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	config := node.DocumentConfig()
+	if config.TemplateID == 0 {
+		logrus.WithField("node_id", node.ID).Warn("📄 DOCUMENT: Node has no template configured, skipping")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	template, err := s.documentService.GetTemplate(config.TemplateID)
+	if err != nil {
+		return "", err
+	}
+	if template == nil {
+		logrus.WithFields(logrus.Fields{
+			"node_id":     node.ID,
+			"template_id": config.TemplateID,
+		}).Error("📄 DOCUMENT: Template not found, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get execution variables")
+		variables = make(map[string]interface{})
+	}
+
+	documentURL, err := s.documentService.GenerateDocument(documentCacheKey(template, variables), template, variables)
+	if err != nil {
+		logrus.WithError(err).WithField("node_id", node.ID).Error("📄 DOCUMENT: Failed to generate document")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"template_id":  template.ID,
+		"prospect_num": execution.ProspectNum,
+		"document_url": documentURL,
+	}).Info("✅ DOCUMENT: Document ready, advancing flow")
+
+	response := documentURL
+	if config.Caption != "" {
+		caption := s.flowService.ReplaceVariables(config.Caption, variables)
+		response = fmt.Sprintf("%s\n\n%s", documentURL, caption)
+	}
+
+	// Advance like other sending nodes; the document URL is this node's response
+	nextNode, nextErr := s.flowService.GetNextNode(flow, node.ID)
+	if nextErr != nil || nextNode == nil {
+		if err := s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice); err != nil {
+			logrus.WithError(err).Error("📄 DOCUMENT: Failed to complete flow after sending document")
+		}
+		return response, nil
+	}
+
+	s.updateCurrentNode(execution, nextNode.ID)
+	waiting := nextNode.Type == models.NodeTypeUserReply
+	if err := s.updateFlowTrackingFields(execution, nextNode.ID, flow.ID, waiting); err != nil {
+		logrus.WithError(err).Error("📄 DOCUMENT: Failed to advance execution after sending document")
+	}
+
+	return response, nil
+}
+
+// documentCacheKey hashes the template and its personalized inputs so
+// identical documents reuse the stored file
+func documentCacheKey(template *models.DocumentTemplate, variables map[string]interface{}) string {
+	serialized, _ := json.Marshal(variables)
+	hash := md5.Sum([]byte(fmt.Sprintf("%d|%s|%s", template.ID, template.UpdatedAt.Format("20060102150405"), serialized)))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,111 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EngineAPI is the in-process implementation behind the FlowEngine gRPC
+// contract (proto/flowengine.proto): StartExecution, AdvanceWithInput and
+// GetState. The generated gRPC server wraps these methods once the
+// grpc/protobuf dependencies land; until then the same surface is available
+// to Go callers directly
+
+// EngineAPI drives flow executions for worker processes
+type EngineAPI struct {
+	service *Service
+}
+
+// NewEngineAPI creates the engine API over the WhatsApp service
+func NewEngineAPI(service *Service) *EngineAPI {
+	return &EngineAPI{service: service}
+}
+
+// EngineExecutionState mirrors the ExecutionState protobuf message
+type EngineExecutionState struct {
+	IDDevice        string                 `json:"id_device"`
+	ProspectNum     string                 `json:"prospect_num"`
+	FlowID          string                 `json:"flow_id"`
+	CurrentNodeID   string                 `json:"current_node_id"`
+	LastNodeID      string                 `json:"last_node_id"`
+	ExecutionStatus string                 `json:"execution_status"`
+	Stage           string                 `json:"stage"`
+	WaitingForReply bool                   `json:"waiting_for_reply"`
+	Variables       map[string]interface{} `json:"variables"`
+}
+
+// StartExecution begins a flow for a prospect on a device
+func (e *EngineAPI) StartExecution(idDevice, prospectNum, flowID string) (*EngineExecutionState, error) {
+	if idDevice == "" || prospectNum == "" || flowID == "" {
+		return nil, fmt.Errorf("id_device, prospect_num and flow_id are required")
+	}
+
+	execution, err := e.service.aiWhatsappService.StartFlowExecution(prospectNum, idDevice, flowID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start flow execution: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"id_device":    idDevice,
+		"prospect_num": prospectNum,
+		"flow_id":      flowID,
+	}).Info("⚙️ ENGINE: Execution started via engine API")
+
+	return e.stateFromExecution(execution), nil
+}
+
+// AdvanceWithInput feeds one user message into the execution. Processing runs
+// through the regular message queue, so resulting effects (messages, media,
+// delays) are delivered by the engine itself rather than returned
+func (e *EngineAPI) AdvanceWithInput(idDevice, prospectNum, userInput, senderName string) error {
+	if idDevice == "" || prospectNum == "" {
+		return fmt.Errorf("id_device and prospect_num are required")
+	}
+
+	deviceSettings, err := e.service.deviceSettingsService.GetByIDDevice(idDevice)
+	if err != nil || deviceSettings == nil {
+		return fmt.Errorf("device not found: %s", idDevice)
+	}
+
+	return e.service.ProcessIncomingMessageFromWebhook(prospectNum, userInput, idDevice, deviceSettings.Provider, senderName)
+}
+
+// GetState returns the current execution state without advancing it
+func (e *EngineAPI) GetState(idDevice, prospectNum string) (*EngineExecutionState, error) {
+	if idDevice == "" || prospectNum == "" {
+		return nil, fmt.Errorf("id_device and prospect_num are required")
+	}
+
+	execution, err := e.service.aiWhatsappService.GetAIWhatsappByProspectAndDevice(prospectNum, idDevice)
+	if err != nil {
+		return nil, err
+	}
+	if execution == nil {
+		return nil, fmt.Errorf("no execution found for prospect %s on device %s", prospectNum, idDevice)
+	}
+
+	return e.stateFromExecution(execution), nil
+}
+
+// stateFromExecution maps an ai_whatsapp row onto the engine state shape
+func (e *EngineAPI) stateFromExecution(execution *models.AIWhatsapp) *EngineExecutionState {
+	state := &EngineExecutionState{
+		IDDevice:        execution.IDDevice,
+		ProspectNum:     execution.ProspectNum,
+		FlowID:          execution.FlowID.String,
+		CurrentNodeID:   execution.CurrentNodeID.String,
+		LastNodeID:      execution.LastNodeID.String,
+		ExecutionStatus: execution.ExecutionStatus.String,
+		Stage:           execution.Stage.String,
+		WaitingForReply: execution.WaitingForReply.Valid && execution.WaitingForReply.Int32 == 1,
+	}
+
+	if variables, err := e.service.aiWhatsappService.GetFlowExecutionVariables(state.ProspectNum, state.IDDevice); err == nil {
+		state.Variables = variables
+	}
+
+	return state
+}
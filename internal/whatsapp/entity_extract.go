@@ -0,0 +1,66 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entity-extract nodes capture structured values (appointment dates, order
+// numbers, amounts) from the prospect's message into typed flow variables,
+// so later nodes and edge conditions can use {{appointment_date}} instead of
+// re-parsing free text.
+
+// processEntityExtractNode parks the execution waiting for the prospect's
+// message, or extracts entities from the pending input and continues the flow
+func (s *Service) processEntityExtractNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if userInput == "" {
+		logrus.WithFields(logrus.Fields{
+			"node_id":      node.ID,
+			"prospect_num": execution.ProspectNum,
+		}).Info("🔎 ENTITY: No input yet, waiting for prospect message")
+		return "", s.updateFlowTrackingFields(execution, node.ID, flow.ID, true)
+	}
+
+	s.captureEntities(execution, node, userInput)
+	return "", s.advancePastNode(flow, execution, node)
+}
+
+// captureEntities runs the extractor over the input and stores every valid
+// entity as a flow variable under the entity's name. Extraction failures are
+// logged and skipped - the flow continues without the variables rather than
+// stalling the conversation
+func (s *Service) captureEntities(execution *models.AIWhatsapp, node *models.FlowNode, userInput string) {
+	config := node.EntityExtractConfig()
+	if len(config.Entities) == 0 {
+		logrus.WithField("node_id", node.ID).Warn("🔎 ENTITY: Node has no entities configured")
+		return
+	}
+
+	// The device's AI key drives extraction, same as AI prompt nodes
+	var apiKey string
+	if deviceSettings, err := s.deviceSettingsService.GetByIDDevice(execution.IDDevice); err == nil && deviceSettings != nil && deviceSettings.APIKey.Valid {
+		apiKey = deviceSettings.APIKey.String
+	}
+
+	extracted, err := s.aiService.ExtractEntities(userInput, config.Entities, apiKey, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).WithField("node_id", node.ID).Error("🔎 ENTITY: Extraction failed, continuing without variables")
+		return
+	}
+	if len(extracted) == 0 {
+		logrus.WithField("node_id", node.ID).Info("🔎 ENTITY: No entities found in message")
+		return
+	}
+
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, extracted, "active"); err != nil {
+		logrus.WithError(err).Error("🔎 ENTITY: Failed to store extracted variables")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"prospect_num": execution.ProspectNum,
+		"entities":     len(extracted),
+	}).Info("✅ ENTITY: Stored extracted entities as flow variables")
+}
@@ -0,0 +1,47 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+)
+
+// Debug-mode hooks for the flow editor: when a test prospect's conversation
+// is in debug mode, the engine streams each decision it makes (node entered,
+// condition result, AI prompt) to the editor's debug panel over WebSocket.
+// The hooks are nil-safe and cost nothing for conversations not being
+// debugged.
+
+// SetFlowDebugService wires live engine-event streaming into flow processing
+func (s *Service) SetFlowDebugService(flowDebugService *services.FlowDebugService) {
+	s.flowDebugService = flowDebugService
+}
+
+// debugEvent streams one engine event for the conversation when it is in
+// debug mode
+func (s *Service) debugEvent(execution *models.AIWhatsapp, event string, data map[string]interface{}) {
+	if s.flowDebugService == nil {
+		return
+	}
+	s.flowDebugService.Emit(execution.IDDevice, execution.ProspectNum, event, data)
+}
+
+// debugNodeEntered reports the engine arriving at a node, including a
+// snapshot of the flow variables at that moment. The snapshot is only
+// fetched when the conversation is actually being debugged
+func (s *Service) debugNodeEntered(execution *models.AIWhatsapp, node *models.FlowNode, userInput string) {
+	if s.flowDebugService == nil || !s.flowDebugService.IsEnabled(execution.IDDevice, execution.ProspectNum) {
+		return
+	}
+
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		variables = make(map[string]interface{})
+	}
+
+	s.flowDebugService.Emit(execution.IDDevice, execution.ProspectNum, "node_entered", map[string]interface{}{
+		"node_id":    node.ID,
+		"node_type":  node.Type,
+		"user_input": userInput,
+		"variables":  variables,
+	})
+}
@@ -0,0 +1,34 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+)
+
+// Flow version pinning. New executions are pinned to the flow's newest
+// published version and every later flow load swaps in that snapshot, so
+// editing or rolling back a flow never changes a conversation mid-execution.
+// Flows that have never been published run the live draft unpinned.
+
+// SetFlowVersionService injects the flow version service
+func (s *Service) SetFlowVersionService(flowVersionService *services.FlowVersionService) {
+	s.flowVersionService = flowVersionService
+}
+
+// pinExecutionFlow pins a newly started conversation to the flow's newest
+// published version
+func (s *Service) pinExecutionFlow(prospectNum, idDevice, flowID string) {
+	if s.flowVersionService == nil {
+		return
+	}
+	s.flowVersionService.PinExecution(prospectNum, idDevice, flowID)
+}
+
+// resolveExecutionFlow swaps in the conversation's pinned flow version when
+// one exists; without a pin (or the version service) the flow is unchanged
+func (s *Service) resolveExecutionFlow(flow *models.ChatbotFlow, prospectNum, idDevice string) *models.ChatbotFlow {
+	if s.flowVersionService == nil || flow == nil {
+		return flow
+	}
+	return s.flowVersionService.ResolveFlow(flow, prospectNum, idDevice)
+}
@@ -0,0 +1,90 @@
+package flowtest
+
+import (
+	"fmt"
+	"testing"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+)
+
+// Benchmarks for the engine's hot paths: flow traversal, media URL
+// detection and AI response parsing. Run with
+//
+//	go test ./internal/whatsapp/flowtest -bench=. -benchmem
+//
+// to compare allocations and timings across changes
+
+func benchmarkScenario(b *testing.B, flow *models.ChatbotFlow, inputs []string) {
+	b.Helper()
+	scenario := &Scenario{Name: b.Name(), Flow: flow, Inputs: inputs}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewRunner().Run(scenario); err != nil {
+			b.Fatalf("scenario failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkFlowTraversalLinear(b *testing.B) {
+	builder := NewFlow("bench-linear", "Linear chain").Start("start", "hi")
+	for i := 0; i < 25; i++ {
+		builder.Message(fmt.Sprintf("msg-%d", i), fmt.Sprintf("Step %d of the chain", i))
+	}
+	benchmarkScenario(b, builder.MustBuild(), []string{"hi"})
+}
+
+func BenchmarkFlowTraversalBranching(b *testing.B) {
+	builder := NewFlow("bench-branch", "Menu").
+		Start("start", "hi").
+		Message("menu", "Reply 1 for pricing, 2 for support").
+		Condition("choice", []map[string]interface{}{
+			{"type": "equals", "value": "1", "label": "1"},
+			{"type": "equals", "value": "2", "label": "2"},
+		}).
+		Edge("choice", "pricing").
+		Edge("choice", "support")
+	builder.From("").Node("pricing", models.NodeTypeMessage, map[string]interface{}{"message": "Pricing starts at RM99"})
+	builder.From("").Node("support", models.NodeTypeMessage, map[string]interface{}{"message": "Support is on the way"})
+
+	benchmarkScenario(b, builder.MustBuild(), []string{"hi", "1"})
+}
+
+func BenchmarkMediaDetection(b *testing.B) {
+	mediaDetection := services.NewMediaDetectionService()
+	samples := []string{
+		"Here is the catalog [IMAGE: https://example.com/catalog.jpg] and our intro [VIDEO: https://example.com/intro.mp4]",
+		"Gambar 1: [https://example.com/produk-satu.png] untuk rujukan anda",
+		"![promo](https://example.com/promo.webp) and a plain link https://example.com/file.mp3?dl=1",
+		"No media here, just a long reply explaining delivery times, payment options and the return policy in detail",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, sample := range samples {
+			mediaDetection.DetectMedia(sample)
+		}
+	}
+}
+
+func BenchmarkAIResponseParsing(b *testing.B) {
+	processor := services.NewAIResponseProcessor(0)
+	samples := []string{
+		`{"Stage":"Prospek","Response":[{"type":"text","content":"Salam! Produk ini RM99 sahaja."},{"type":"image","content":"https://example.com/produk.jpg"}]}`,
+		"```json\n{\"Stage\":\"Order\",\"Response\":[{\"type\":\"text\",\"content\":\"Boleh saya dapatkan alamat penghantaran?\"}]}\n```",
+		"Stage: Follow Up\nResponse: Terima kasih, kami hubungi anda esok.",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, sample := range samples {
+			if _, err := processor.ProcessAIResponse(sample, nil); err != nil {
+				b.Fatalf("failed to parse response: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package flowtest provides a small DSL for describing chatbot flows and
+// scripted conversations in tests, together with golden-file helpers, so node
+// types and traversal edge cases in the flow engine get regression coverage
+// without a database or live providers.
+package flowtest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"nodepath-chat/internal/models"
+)
+
+// FlowBuilder assembles a models.ChatbotFlow from nodes and edges without
+// hand-writing the JSON blobs the engine consumes
+type FlowBuilder struct {
+	id     string
+	name   string
+	nodes  []*models.FlowNode
+	edges  []*models.FlowEdge
+	lastID string
+}
+
+// NewFlow starts building a flow with the given ID and name
+func NewFlow(id, name string) *FlowBuilder {
+	return &FlowBuilder{id: id, name: name}
+}
+
+// Node appends a node of the given type. Successive Node calls are chained with
+// an edge automatically; use Edge for explicit branching
+func (b *FlowBuilder) Node(id string, nodeType models.NodeType, data map[string]interface{}) *FlowBuilder {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	b.nodes = append(b.nodes, &models.FlowNode{
+		ID:   id,
+		Type: nodeType,
+		Data: data,
+	})
+	if b.lastID != "" {
+		b.Edge(b.lastID, id)
+	}
+	b.lastID = id
+	return b
+}
+
+// Start appends a start node
+func (b *FlowBuilder) Start(id string, keyword string) *FlowBuilder {
+	return b.Node(id, models.NodeTypeStart, map[string]interface{}{"keyword": keyword})
+}
+
+// Message appends a message node
+func (b *FlowBuilder) Message(id, message string) *FlowBuilder {
+	return b.Node(id, models.NodeTypeMessage, map[string]interface{}{"message": message})
+}
+
+// Image appends an image node
+func (b *FlowBuilder) Image(id, imageURL string) *FlowBuilder {
+	return b.Node(id, models.NodeTypeImage, map[string]interface{}{"imageUrl": imageURL})
+}
+
+// Delay appends a delay node with the given duration in seconds
+func (b *FlowBuilder) Delay(id string, seconds float64) *FlowBuilder {
+	return b.Node(id, models.NodeTypeDelay, map[string]interface{}{"delay": seconds})
+}
+
+// UserReply appends a user_reply node that pauses the flow for input
+func (b *FlowBuilder) UserReply(id string) *FlowBuilder {
+	return b.Node(id, models.NodeTypeUserReply, nil)
+}
+
+// Condition appends a condition node with the given conditions list. Branch
+// targets must be connected explicitly with Edge; the automatic chain stops here
+func (b *FlowBuilder) Condition(id string, conditions []map[string]interface{}) *FlowBuilder {
+	values := make([]interface{}, len(conditions))
+	for i, condition := range conditions {
+		asInterface := make(map[string]interface{}, len(condition))
+		for k, v := range condition {
+			asInterface[k] = v
+		}
+		values[i] = asInterface
+	}
+	b.Node(id, models.NodeTypeCondition, map[string]interface{}{"conditions": values})
+	b.lastID = ""
+	return b
+}
+
+// Stage appends a stage node
+func (b *FlowBuilder) Stage(id, stage string) *FlowBuilder {
+	return b.Node(id, models.NodeTypeStage, map[string]interface{}{"stage": stage})
+}
+
+// Manual appends a manual handoff node
+func (b *FlowBuilder) Manual(id string) *FlowBuilder {
+	return b.Node(id, models.NodeTypeManual, nil)
+}
+
+// Edge adds an explicit edge between two nodes (for condition branches or joins)
+func (b *FlowBuilder) Edge(source, target string) *FlowBuilder {
+	b.edges = append(b.edges, &models.FlowEdge{
+		ID:     fmt.Sprintf("e-%s-%s", source, target),
+		Source: source,
+		Target: target,
+	})
+	return b
+}
+
+// EdgeIf adds an edge guarded by a condition expression (e.g. `input == "yes"`)
+func (b *FlowBuilder) EdgeIf(source, target, condition string) *FlowBuilder {
+	b.edges = append(b.edges, &models.FlowEdge{
+		ID:        fmt.Sprintf("e-%s-%s", source, target),
+		Source:    source,
+		Target:    target,
+		Condition: condition,
+	})
+	return b
+}
+
+// WeightedEdge adds an edge with a weight for weighted-random branch selection
+func (b *FlowBuilder) WeightedEdge(source, target string, weight float64) *FlowBuilder {
+	b.edges = append(b.edges, &models.FlowEdge{
+		ID:     fmt.Sprintf("e-%s-%s", source, target),
+		Source: source,
+		Target: target,
+		Weight: weight,
+	})
+	return b
+}
+
+// From resets the automatic chain so the next Node call connects from the given
+// node, which keeps linear branches readable
+func (b *FlowBuilder) From(id string) *FlowBuilder {
+	b.lastID = id
+	return b
+}
+
+// Build serializes the nodes and edges into a ChatbotFlow the engine can consume
+func (b *FlowBuilder) Build() (*models.ChatbotFlow, error) {
+	nodesJSON, err := json.Marshal(b.nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal nodes: %w", err)
+	}
+	edgesJSON, err := json.Marshal(b.edges)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal edges: %w", err)
+	}
+
+	nodes := json.RawMessage(nodesJSON)
+	edges := json.RawMessage(edgesJSON)
+	return &models.ChatbotFlow{
+		ID:    b.id,
+		Name:  b.name,
+		Nodes: &nodes,
+		Edges: &edges,
+	}, nil
+}
+
+// MustBuild is Build for test setup where a marshal failure is a programming error
+func (b *FlowBuilder) MustBuild() *models.ChatbotFlow {
+	flow, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return flow
+}
@@ -0,0 +1,192 @@
+package flowtest
+
+import (
+	"strings"
+	"testing"
+
+	"nodepath-chat/internal/models"
+)
+
+func TestLinearMessageFlow(t *testing.T) {
+	flow := NewFlow("flow-linear", "Linear greeting").
+		Start("start", "hi").
+		Message("welcome", "Welcome to the store!").
+		Image("catalog", "https://example.com/catalog.jpg").
+		Message("closing", "Reply anytime for help").
+		MustBuild()
+
+	transcript, err := NewRunner().Run(&Scenario{
+		Name:   "linear message flow runs to completion",
+		Flow:   flow,
+		Inputs: []string{"hi"},
+	})
+	if err != nil {
+		t.Fatalf("scenario failed: %v", err)
+	}
+
+	Golden(t, "linear_message_flow", transcript)
+}
+
+func TestUserReplyPausesAndResumes(t *testing.T) {
+	flow := NewFlow("flow-reply", "Ask for name").
+		Start("start", "hi").
+		Message("ask", "What is your name?").
+		UserReply("wait-name").
+		Message("thanks", "Thanks for telling us!").
+		MustBuild()
+
+	transcript, err := NewRunner().Run(&Scenario{
+		Name:   "user_reply pauses the flow until the next input",
+		Flow:   flow,
+		Inputs: []string{"hi", "Aina"},
+	})
+	if err != nil {
+		t.Fatalf("scenario failed: %v", err)
+	}
+
+	Golden(t, "user_reply_pause_resume", transcript)
+}
+
+func TestConditionBranching(t *testing.T) {
+	builder := NewFlow("flow-branch", "Menu").
+		Start("start", "hi").
+		Message("menu", "Reply 1 for pricing, 2 for support").
+		Condition("choice", []map[string]interface{}{
+			{"type": "equals", "value": "1", "label": "1"},
+			{"type": "equals", "value": "2", "label": "2"},
+		}).
+		Edge("choice", "pricing").
+		Edge("choice", "support")
+	builder.From("").Node("pricing", models.NodeTypeMessage, map[string]interface{}{"message": "Pricing starts at RM99"})
+	builder.From("").Node("support", models.NodeTypeMessage, map[string]interface{}{"message": "An agent will help you"})
+	flow := builder.MustBuild()
+
+	t.Run("first branch by number", func(t *testing.T) {
+		transcript, err := NewRunner().Run(&Scenario{
+			Name:   "condition routes input 1 to pricing",
+			Flow:   flow,
+			Inputs: []string{"hi", "1"},
+		})
+		if err != nil {
+			t.Fatalf("scenario failed: %v", err)
+		}
+		Golden(t, "condition_branch_pricing", transcript)
+	})
+
+	t.Run("second branch by number", func(t *testing.T) {
+		transcript, err := NewRunner().Run(&Scenario{
+			Name:   "condition routes input 2 to support",
+			Flow:   flow,
+			Inputs: []string{"hi", "2"},
+		})
+		if err != nil {
+			t.Fatalf("scenario failed: %v", err)
+		}
+		Golden(t, "condition_branch_support", transcript)
+	})
+}
+
+func TestDelayStageAndHandoff(t *testing.T) {
+	flow := NewFlow("flow-handoff", "Escalation").
+		Start("start", "hi").
+		Stage("stage-qualify", "qualified").
+		Delay("pause", 3).
+		Message("notice", "Connecting you to a human agent").
+		Manual("handoff").
+		MustBuild()
+
+	transcript, err := NewRunner().Run(&Scenario{
+		Name:   "stage, delay and manual handoff effects are recorded",
+		Flow:   flow,
+		Inputs: []string{"hi"},
+	})
+	if err != nil {
+		t.Fatalf("scenario failed: %v", err)
+	}
+
+	Golden(t, "delay_stage_handoff", transcript)
+}
+
+func TestEdgeConditionRouting(t *testing.T) {
+	builder := NewFlow("flow-edge-cond", "Yes/no routing").
+		Start("start", "hi").
+		Message("ask", "Do you want the brochure? (yes/no)").
+		UserReply("wait-answer").
+		EdgeIf("wait-answer", "send-yes", `input == "yes"`).
+		Edge("wait-answer", "send-no")
+	builder.From("").Node("send-yes", models.NodeTypeMessage, map[string]interface{}{"message": "Sending the brochure now"})
+	builder.From("").Node("send-no", models.NodeTypeMessage, map[string]interface{}{"message": "No problem, ask anytime"})
+	flow := builder.MustBuild()
+
+	t.Run("condition matches", func(t *testing.T) {
+		transcript, err := NewRunner().Run(&Scenario{
+			Name:   "edge condition routes yes to the brochure branch",
+			Flow:   flow,
+			Inputs: []string{"hi", "yes"},
+		})
+		if err != nil {
+			t.Fatalf("scenario failed: %v", err)
+		}
+		Golden(t, "edge_condition_yes", transcript)
+	})
+
+	t.Run("falls back to unconditioned edge", func(t *testing.T) {
+		transcript, err := NewRunner().Run(&Scenario{
+			Name:   "edge condition falls through to the default branch",
+			Flow:   flow,
+			Inputs: []string{"hi", "maybe later"},
+		})
+		if err != nil {
+			t.Fatalf("scenario failed: %v", err)
+		}
+		Golden(t, "edge_condition_fallback", transcript)
+	})
+}
+
+func TestWeightedBranchSelectsOnlyWeightedTargets(t *testing.T) {
+	builder := NewFlow("flow-ab", "A/B copy test").
+		Start("start", "hi").
+		Message("intro", "Hold on...").
+		WeightedEdge("intro", "variant-a", 50).
+		WeightedEdge("intro", "variant-b", 50)
+	builder.From("").Node("variant-a", models.NodeTypeMessage, map[string]interface{}{"message": "Copy A"})
+	builder.From("").Node("variant-b", models.NodeTypeMessage, map[string]interface{}{"message": "Copy B"})
+	flow := builder.MustBuild()
+
+	// Weighted selection is random, so assert the traversal always lands on one
+	// of the two variants rather than comparing a golden transcript
+	for i := 0; i < 20; i++ {
+		transcript, err := NewRunner().Run(&Scenario{
+			Name:   "weighted branch",
+			Flow:   flow,
+			Inputs: []string{"hi"},
+		})
+		if err != nil {
+			t.Fatalf("scenario failed: %v", err)
+		}
+		if !strings.Contains(transcript, `SEND text="Copy A"`) && !strings.Contains(transcript, `SEND text="Copy B"`) {
+			t.Fatalf("expected one of the weighted variants, got transcript:\n%s", transcript)
+		}
+	}
+}
+
+func TestVariableReplacementInMessages(t *testing.T) {
+	flow := NewFlow("flow-vars", "Personalized greeting").
+		Start("start", "hi").
+		Message("greet", "Hello {{name}}, welcome back!").
+		MustBuild()
+
+	runner := NewRunner()
+	runner.Variables["name"] = "Aina"
+
+	transcript, err := runner.Run(&Scenario{
+		Name:   "message text substitutes execution variables",
+		Flow:   flow,
+		Inputs: []string{"hi"},
+	})
+	if err != nil {
+		t.Fatalf("scenario failed: %v", err)
+	}
+
+	Golden(t, "variable_replacement", transcript)
+}
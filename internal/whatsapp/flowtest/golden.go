@@ -0,0 +1,37 @@
+package flowtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files with current output")
+
+// Golden compares got against testdata/<name>.golden. Run tests with -update to
+// rewrite the golden files after an intentional engine change
+func Golden(t *testing.T, name, got string) {
+	t.Helper()
+
+	goldenPath := filepath.Join("testdata", name+".golden")
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("failed to create testdata dir: %v", err)
+		}
+		if err := os.WriteFile(goldenPath, []byte(got), 0o644); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", goldenPath, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s (run with -update to create it): %v", goldenPath, err)
+	}
+
+	if got != string(want) {
+		t.Errorf("transcript mismatch for %s\n--- want\n%s\n--- got\n%s", name, want, got)
+	}
+}
@@ -0,0 +1,162 @@
+package flowtest
+
+import (
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+)
+
+// Scenario scripts a conversation against a flow: each user input is fed to the
+// traversal harness and the outbound effects (sends, delays, waits, handoffs)
+// are recorded into a transcript suitable for golden-file comparison
+type Scenario struct {
+	Name   string
+	Flow   *models.ChatbotFlow
+	Inputs []string
+}
+
+// Runner walks a flow deterministically using the same FlowService traversal
+// primitives the engine uses (FindNodeByID, GetNextNode, EvaluateConditionNode,
+// ReplaceVariables), recording effects instead of sending messages
+type Runner struct {
+	flowService *services.FlowService
+	Variables   map[string]interface{}
+}
+
+// NewRunner creates a runner backed by a FlowService in fallback (no database) mode
+func NewRunner() *Runner {
+	return &Runner{
+		flowService: services.NewFlowService(nil, nil),
+		Variables:   make(map[string]interface{}),
+	}
+}
+
+// Run executes the scenario and returns the transcript. Each scripted input
+// either starts the flow or resumes it at the node waiting for a reply
+func (r *Runner) Run(scenario *Scenario) (string, error) {
+	var transcript strings.Builder
+	transcript.WriteString(fmt.Sprintf("SCENARIO: %s\n", scenario.Name))
+
+	nodes, err := r.flowService.GetFlowNodes(scenario.Flow)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse flow nodes: %w", err)
+	}
+
+	var startNode *models.FlowNode
+	for _, node := range nodes {
+		if node.Type == models.NodeTypeStart {
+			startNode = node
+			break
+		}
+	}
+	if startNode == nil {
+		return "", fmt.Errorf("flow %s has no start node", scenario.Flow.ID)
+	}
+
+	var waitingAt *models.FlowNode
+	for _, input := range scenario.Inputs {
+		transcript.WriteString(fmt.Sprintf("USER: %s\n", input))
+
+		var current *models.FlowNode
+		switch {
+		case waitingAt == nil:
+			// First input starts the flow from the start node
+			current, err = r.flowService.GetNextNode(scenario.Flow, startNode.ID)
+			if err != nil {
+				return "", fmt.Errorf("start node has no next node: %w", err)
+			}
+		case waitingAt.Type == models.NodeTypeCondition:
+			// Condition nodes branch on the user input
+			current, err = r.flowService.EvaluateConditionNode(scenario.Flow, waitingAt.ID, input)
+			if err != nil {
+				transcript.WriteString(fmt.Sprintf("  -> NO_MATCH node=%s\n", waitingAt.ID))
+				continue
+			}
+			transcript.WriteString(fmt.Sprintf("  -> BRANCH node=%s target=%s\n", waitingAt.ID, current.ID))
+		default:
+			// user_reply nodes resume at the node after the wait; conditioned
+			// edges route on the scripted input
+			current, err = r.flowService.GetNextNodeWithContext(scenario.Flow, waitingAt.ID, input, r.Variables)
+			if err != nil {
+				transcript.WriteString("  -> FLOW_COMPLETE\n")
+				waitingAt = nil
+				continue
+			}
+		}
+		waitingAt = nil
+
+		// Walk forward until the flow pauses or completes
+		for current != nil {
+			pause, err := r.applyNode(&transcript, scenario.Flow, current)
+			if err != nil {
+				return "", err
+			}
+			if pause {
+				waitingAt = current
+				break
+			}
+
+			next, err := r.flowService.GetNextNode(scenario.Flow, current.ID)
+			if err != nil || next == nil {
+				transcript.WriteString("  -> FLOW_COMPLETE\n")
+				current = nil
+				continue
+			}
+			current = next
+		}
+	}
+
+	return transcript.String(), nil
+}
+
+// applyNode records a node's outbound effect and reports whether the flow pauses
+func (r *Runner) applyNode(transcript *strings.Builder, flow *models.ChatbotFlow, node *models.FlowNode) (bool, error) {
+	switch node.Type {
+	case models.NodeTypeMessage:
+		message, _ := node.Data["message"].(string)
+		message = r.flowService.ReplaceVariables(message, r.Variables)
+		transcript.WriteString(fmt.Sprintf("  -> SEND text=%q\n", message))
+		return false, nil
+
+	case models.NodeTypeImage:
+		imageURL, _ := node.Data["imageUrl"].(string)
+		if imageURL == "" {
+			imageURL, _ = node.Data["image"].(string)
+		}
+		transcript.WriteString(fmt.Sprintf("  -> SEND_IMAGE url=%q\n", imageURL))
+		return false, nil
+
+	case models.NodeTypeDelay:
+		delaySeconds := 5.0
+		if delay, ok := node.Data["delay"].(float64); ok {
+			delaySeconds = delay
+		} else if delay, ok := node.Data["delaySeconds"].(float64); ok {
+			delaySeconds = delay
+		}
+		transcript.WriteString(fmt.Sprintf("  -> DELAY seconds=%v\n", delaySeconds))
+		return false, nil
+
+	case models.NodeTypeStage:
+		stage, _ := node.Data["stage"].(string)
+		transcript.WriteString(fmt.Sprintf("  -> SET_STAGE stage=%q\n", stage))
+		return false, nil
+
+	case models.NodeTypeUserReply:
+		transcript.WriteString(fmt.Sprintf("  -> WAIT_FOR_REPLY node=%s\n", node.ID))
+		return true, nil
+
+	case models.NodeTypeCondition:
+		transcript.WriteString(fmt.Sprintf("  -> WAIT_FOR_CHOICE node=%s\n", node.ID))
+		return true, nil
+
+	case models.NodeTypeManual:
+		transcript.WriteString(fmt.Sprintf("  -> HANDOFF node=%s\n", node.ID))
+		return true, nil
+
+	default:
+		transcript.WriteString(fmt.Sprintf("  -> UNSUPPORTED node=%s type=%s\n", node.ID, node.Type))
+		return true, nil
+	}
+}
@@ -0,0 +1,230 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Form nodes collect several values in one conversation step instead of a
+// brittle chain of input nodes: the node asks for whichever slots are still
+// missing, accepts slots in any order (a single reply can fill more than
+// one), then reads the collected data back for confirmation. Only a
+// confirmed form lets the flow advance; slot values live on as flow
+// variables under the slot names.
+
+// formConfirmYes are the replies accepted as form confirmation
+var formConfirmYes = []string{"yes", "ya", "y", "ok", "okay", "confirm", "betul", "correct"}
+
+// formConfirmNo are the replies that restart the form from scratch
+var formConfirmNo = []string{"no", "tak", "n", "wrong", "salah", "edit", "restart"}
+
+// processFormNode starts (or resumes) a form: it asks for the first missing
+// slot and parks the execution waiting for the reply
+func (s *Service) processFormNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	config := node.FormConfig()
+	if len(config.Slots) == 0 {
+		logrus.WithField("node_id", node.ID).Warn("📋 FORM: Node has no slots configured, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	missing := s.missingFormSlots(execution, config.Slots)
+	if len(missing) == 0 {
+		// Everything already known from earlier nodes - go straight to
+		// confirmation
+		s.setFormConfirmPending(execution, true)
+		if err := s.updateFlowTrackingFields(execution, node.ID, flow.ID, true); err != nil {
+			return "", err
+		}
+		return s.formConfirmationText(execution, config), nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"missing":      len(missing),
+		"prospect_num": execution.ProspectNum,
+	}).Info("📋 FORM: Asking for first missing slot")
+
+	if err := s.updateFlowTrackingFields(execution, node.ID, flow.ID, true); err != nil {
+		return "", err
+	}
+	return formSlotPrompt(missing[0]), nil
+}
+
+// handleFormReply consumes one reply while the execution is parked on a form
+// node. handled=true means the form is still collecting (or confirming) and
+// the execution stays parked; handled=false means the form was confirmed and
+// the flow should route past the node
+func (s *Service) handleFormReply(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (bool, error) {
+	config := node.FormConfig()
+	if len(config.Slots) == 0 {
+		return false, nil
+	}
+
+	reply := strings.ToLower(strings.TrimSpace(userInput))
+
+	// Confirmation round: advance on yes, restart on no, re-ask otherwise
+	if s.formConfirmPending(execution) {
+		if containsFold(formConfirmYes, reply) {
+			s.setFormConfirmPending(execution, false)
+			logrus.WithFields(logrus.Fields{
+				"node_id":      node.ID,
+				"prospect_num": execution.ProspectNum,
+			}).Info("✅ FORM: Prospect confirmed collected data")
+			return false, nil
+		}
+		if containsFold(formConfirmNo, reply) {
+			s.setFormConfirmPending(execution, false)
+			s.clearFormSlots(execution, node, config.Slots)
+			logrus.WithField("node_id", node.ID).Info("📋 FORM: Prospect rejected data, restarting form")
+			prompt := "Okay, let's start over.\n" + formSlotPrompt(config.Slots[0])
+			return true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, prompt)
+		}
+		return true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, s.formConfirmationText(execution, config))
+	}
+
+	s.fillFormSlots(execution, node, config.Slots, userInput)
+
+	missing := s.missingFormSlots(execution, config.Slots)
+	if len(missing) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"node_id": node.ID,
+			"missing": len(missing),
+		}).Info("📋 FORM: Slots still missing, asking for the next one")
+		return true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, formSlotPrompt(missing[0]))
+	}
+
+	// All slots filled - read the data back for confirmation
+	s.setFormConfirmPending(execution, true)
+	return true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, s.formConfirmationText(execution, config))
+}
+
+// fillFormSlots extracts slot values from the reply and stores them as flow
+// variables. The AI extractor lets one reply fill several slots out of
+// order; when it fails the raw reply fills the first missing slot so the
+// form still progresses
+func (s *Service) fillFormSlots(execution *models.AIWhatsapp, node *models.FlowNode, slots []models.FormSlot, userInput string) {
+	missing := s.missingFormSlots(execution, slots)
+	if len(missing) == 0 {
+		return
+	}
+
+	fields := make([]models.EntityField, 0, len(missing))
+	for _, slot := range missing {
+		fields = append(fields, models.EntityField{Name: slot.Name, Type: slot.Type, Description: slot.Prompt})
+	}
+
+	var apiKey string
+	if deviceSettings, err := s.deviceSettingsService.GetByIDDevice(execution.IDDevice); err == nil && deviceSettings != nil && deviceSettings.APIKey.Valid {
+		apiKey = deviceSettings.APIKey.String
+	}
+
+	extracted, err := s.aiService.ExtractEntities(userInput, fields, apiKey, execution.IDDevice)
+	if err != nil || len(extracted) == 0 {
+		if err != nil {
+			logrus.WithError(err).WithField("node_id", node.ID).Warn("📋 FORM: Extraction failed, using raw reply for the current slot")
+		}
+		// The reply answers the slot we just asked for
+		extracted = map[string]interface{}{missing[0].Name: strings.TrimSpace(userInput)}
+	}
+
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, extracted, "active"); err != nil {
+		logrus.WithError(err).Error("📋 FORM: Failed to store slot values")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id": node.ID,
+		"filled":  len(extracted),
+	}).Info("📋 FORM: Stored slot values")
+}
+
+// missingFormSlots returns the slots without a non-empty flow variable yet
+func (s *Service) missingFormSlots(execution *models.AIWhatsapp, slots []models.FormSlot) []models.FormSlot {
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		variables = make(map[string]interface{})
+	}
+
+	var missing []models.FormSlot
+	for _, slot := range slots {
+		value, ok := variables[slot.Name]
+		if !ok || strings.TrimSpace(fmt.Sprintf("%v", value)) == "" {
+			missing = append(missing, slot)
+		}
+	}
+	return missing
+}
+
+// clearFormSlots blanks every slot variable so a rejected form starts fresh
+func (s *Service) clearFormSlots(execution *models.AIWhatsapp, node *models.FlowNode, slots []models.FormSlot) {
+	cleared := make(map[string]interface{}, len(slots))
+	for _, slot := range slots {
+		cleared[slot.Name] = ""
+	}
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, cleared, "active"); err != nil {
+		logrus.WithError(err).Error("📋 FORM: Failed to clear slot values")
+	}
+}
+
+// formConfirmationText renders the collected slots for the prospect to
+// confirm, using the node's confirm message when configured
+func (s *Service) formConfirmationText(execution *models.AIWhatsapp, config models.FormNodeConfig) string {
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		variables = make(map[string]interface{})
+	}
+
+	header := config.ConfirmMessage
+	if header == "" {
+		header = "Please confirm your details:"
+	}
+
+	var builder strings.Builder
+	builder.WriteString(header)
+	for _, slot := range config.Slots {
+		builder.WriteString(fmt.Sprintf("\n- %s: %v", slot.Name, variables[slot.Name]))
+	}
+	builder.WriteString("\n\nReply *yes* to confirm or *no* to start over")
+	return builder.String()
+}
+
+// formSlotPrompt is the question asked for a missing slot
+func formSlotPrompt(slot models.FormSlot) string {
+	if slot.Prompt != "" {
+		return slot.Prompt
+	}
+	return fmt.Sprintf("Please share your %s", slot.Name)
+}
+
+// formConfirmPending reports whether the conversation's form is waiting for
+// a yes/no confirmation
+func (s *Service) formConfirmPending(execution *models.AIWhatsapp) bool {
+	s.formConfirmsMu.Lock()
+	defer s.formConfirmsMu.Unlock()
+	return s.formConfirms[catalogPageKey(execution)]
+}
+
+// setFormConfirmPending records or clears the confirmation round
+func (s *Service) setFormConfirmPending(execution *models.AIWhatsapp, pending bool) {
+	s.formConfirmsMu.Lock()
+	defer s.formConfirmsMu.Unlock()
+	if pending {
+		s.formConfirms[catalogPageKey(execution)] = true
+	} else {
+		delete(s.formConfirms, catalogPageKey(execution))
+	}
+}
+
+// containsFold reports whether the list contains the reply, ignoring case
+func containsFold(list []string, reply string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, reply) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package whatsapp
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Image generation nodes render a personalized image per prospect — text
+// overlays (name, order details) on a base template, or an AI-generated
+// image from a prompt — and send it like an image node. Renders are cached
+// by their personalized inputs, so certificates and tickets with the same
+// data are only generated once.
+
+// SetImageGenService injects the image generation service used by image_gen nodes
+func (s *Service) SetImageGenService(imageGenService *services.ImageGenService) {
+	s.imageGenService = imageGenService
+}
+
+// processImageGenNode renders the personalized image and sends its URL
+func (s *Service) processImageGenNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if s.imageGenService == nil {
+		logrus.Warn("🖼️ IMAGE GEN: Image generation service not available, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	config := node.ImageGenConfig()
+
+	// Substitute execution variables into every personalized field before
+	// rendering, so the cache key reflects the final content
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get execution variables")
+		variables = make(map[string]interface{})
+	}
+	config.BaseImageURL = s.flowService.ReplaceVariables(config.BaseImageURL, variables)
+	config.Prompt = s.flowService.ReplaceVariables(config.Prompt, variables)
+	config.Caption = s.flowService.ReplaceVariables(config.Caption, variables)
+	for i := range config.Overlays {
+		config.Overlays[i].Text = s.flowService.ReplaceVariables(config.Overlays[i].Text, variables)
+	}
+
+	cacheKey := imageGenCacheKey(node.ID, config)
+
+	var imageURL string
+	if config.Prompt != "" {
+		imageURL, err = s.imageGenService.GenerateFromPrompt(cacheKey, config.Prompt, s.deviceAPIKey(execution.IDDevice))
+	} else if config.BaseImageURL != "" {
+		imageURL, err = s.imageGenService.GenerateFromTemplate(cacheKey, config.BaseImageURL, config.Overlays)
+	} else {
+		logrus.WithField("node_id", node.ID).Warn("🖼️ IMAGE GEN: Node has neither prompt nor base image, skipping")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("node_id", node.ID).Error("🖼️ IMAGE GEN: Failed to generate image")
+		// Fall back to the unpersonalized base image rather than dropping
+		// the message entirely
+		if config.BaseImageURL != "" {
+			imageURL = config.BaseImageURL
+		} else {
+			return "", s.advancePastNode(flow, execution, node)
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"prospect_num": execution.ProspectNum,
+		"cache_key":    cacheKey,
+		"image_url":    imageURL,
+	}).Info("✅ IMAGE GEN: Image ready, advancing flow")
+
+	response := imageURL
+	if config.Caption != "" {
+		response = fmt.Sprintf("%s\n\n%s", imageURL, config.Caption)
+	}
+
+	// Advance like other sending nodes; the image URL is this node's response
+	nextNode, nextErr := s.flowService.GetNextNode(flow, node.ID)
+	if nextErr != nil || nextNode == nil {
+		if err := s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice); err != nil {
+			logrus.WithError(err).Error("🖼️ IMAGE GEN: Failed to complete flow after sending image")
+		}
+		return response, nil
+	}
+
+	s.updateCurrentNode(execution, nextNode.ID)
+	waiting := nextNode.Type == models.NodeTypeUserReply
+	if err := s.updateFlowTrackingFields(execution, nextNode.ID, flow.ID, waiting); err != nil {
+		logrus.WithError(err).Error("🖼️ IMAGE GEN: Failed to advance execution after sending image")
+	}
+
+	return response, nil
+}
+
+// deviceAPIKey returns the device's AI provider key, or empty when unset
+func (s *Service) deviceAPIKey(deviceID string) string {
+	deviceSettings, err := s.deviceSettingsService.GetByIDDevice(deviceID)
+	if err != nil || deviceSettings == nil || !deviceSettings.APIKey.Valid {
+		return ""
+	}
+	return deviceSettings.APIKey.String
+}
+
+// imageGenCacheKey hashes the personalized render inputs so identical
+// certificates or tickets reuse the stored file
+func imageGenCacheKey(nodeID string, config models.ImageGenNodeConfig) string {
+	var parts []string
+	parts = append(parts, nodeID, config.BaseImageURL, config.Prompt)
+	for _, overlay := range config.Overlays {
+		parts = append(parts, fmt.Sprintf("%s@%d,%d:%d:%s", overlay.Text, overlay.X, overlay.Y, overlay.Size, overlay.Color))
+	}
+	hash := md5.Sum([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(hash[:])
+}
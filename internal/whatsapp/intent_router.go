@@ -0,0 +1,147 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Intent-router nodes branch the flow on what the prospect means rather than
+// on exact words: the message plus the node's labeled intents go to a cheap
+// AI model, and the flow takes the edge whose source handle matches the
+// winning intent. Verdicts below the confidence threshold (and classifier
+// failures) take the fallback edge instead.
+
+// defaultIntentMinConfidence applies when the node does not configure its own
+// confidence threshold
+const defaultIntentMinConfidence = 0.6
+
+// processIntentRouterNode parks the execution waiting for the prospect's
+// message, or classifies the pending input and branches on the result
+func (s *Service) processIntentRouterNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if userInput == "" {
+		logrus.WithFields(logrus.Fields{
+			"node_id":      node.ID,
+			"prospect_num": execution.ProspectNum,
+		}).Info("🧭 INTENT: No input yet, waiting for prospect message")
+		return "", s.updateFlowTrackingFields(execution, node.ID, flow.ID, true)
+	}
+
+	nextNode, err := s.routeIntentReply(flow, execution, node, userInput)
+	if err != nil {
+		return "", err
+	}
+	if nextNode == nil {
+		logrus.WithField("node_id", node.ID).Info("🏁 INTENT: No edge to follow, completing flow")
+		return "", s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice)
+	}
+
+	s.updateCurrentNode(execution, nextNode.ID)
+	if err := s.updateFlowTrackingFields(execution, nextNode.ID, flow.ID, false); err != nil {
+		logrus.WithError(err).Error("🧭 INTENT: Failed to update flow tracking after routing")
+	}
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, nextNode.ID, make(map[string]interface{}), "active"); err != nil {
+		logrus.WithError(err).Error("🧭 INTENT: Failed to advance execution after routing")
+	}
+
+	return s.processFlowMessage(flow, execution, userInput)
+}
+
+// routeIntentReply classifies the prospect's message against the node's
+// intents and returns the node behind the matching edge (or the fallback
+// edge on low confidence). The winning intent and its confidence are stored
+// as flow variables for later nodes
+func (s *Service) routeIntentReply(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (*models.FlowNode, error) {
+	config := node.IntentRouterConfig()
+	if len(config.Intents) == 0 {
+		logrus.WithField("node_id", node.ID).Warn("🧭 INTENT: Node has no intents configured, using fallback edge")
+		return s.intentEdgeTarget(flow, node, "")
+	}
+
+	minConfidence := config.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = defaultIntentMinConfidence
+	}
+
+	// The device's AI key drives classification, same as AI prompt nodes
+	var apiKey string
+	if deviceSettings, err := s.deviceSettingsService.GetByIDDevice(execution.IDDevice); err == nil && deviceSettings != nil && deviceSettings.APIKey.Valid {
+		apiKey = deviceSettings.APIKey.String
+	}
+
+	intent := ""
+	classification, err := s.aiService.ClassifyIntent(userInput, config.Intents, apiKey, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).WithField("node_id", node.ID).Error("🧭 INTENT: Classification failed, using fallback edge")
+	} else if classification.Confidence < minConfidence {
+		logrus.WithFields(logrus.Fields{
+			"node_id":        node.ID,
+			"intent":         classification.Intent,
+			"confidence":     classification.Confidence,
+			"min_confidence": minConfidence,
+		}).Info("🧭 INTENT: Confidence below threshold, using fallback edge")
+	} else {
+		intent = classification.Intent
+	}
+
+	if intent != "" && s.leadScoringService != nil {
+		s.leadScoringService.ScoreIntent(execution.IDDevice, execution.ProspectNum, intent)
+	}
+
+	if classification != nil {
+		variables := map[string]interface{}{
+			"intent":            classification.Intent,
+			"intent_confidence": fmt.Sprintf("%.2f", classification.Confidence),
+		}
+		if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, variables, "active"); err != nil {
+			logrus.WithError(err).Error("🧭 INTENT: Failed to store intent variables")
+		}
+	}
+
+	return s.intentEdgeTarget(flow, node, intent)
+}
+
+// intentEdgeTarget resolves the node behind the edge for the given intent.
+// An empty intent (or one without a matching edge) resolves the fallback
+// edge: source handle "fallback"/"default", or the last edge whose handle
+// names no configured intent
+func (s *Service) intentEdgeTarget(flow *models.ChatbotFlow, node *models.FlowNode, intent string) (*models.FlowNode, error) {
+	edges, err := s.flowService.GetFlowEdges(flow)
+	if err != nil {
+		return nil, err
+	}
+
+	var fallbackEdge *models.FlowEdge
+	for _, edge := range edges {
+		if edge.Source != node.ID {
+			continue
+		}
+		if intent != "" && strings.EqualFold(edge.SourceHandle, intent) {
+			logrus.WithFields(logrus.Fields{
+				"node_id": node.ID,
+				"intent":  intent,
+				"edge_id": edge.ID,
+			}).Info("✅ INTENT: Routing down intent edge")
+			return s.flowService.FindNodeByID(flow, edge.Target)
+		}
+		switch strings.ToLower(edge.SourceHandle) {
+		case "fallback", "default", "":
+			if fallbackEdge == nil {
+				fallbackEdge = edge
+			}
+		}
+	}
+
+	if fallbackEdge == nil {
+		return nil, nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id": node.ID,
+		"edge_id": fallbackEdge.ID,
+	}).Info("🧭 INTENT: Routing down fallback edge")
+	return s.flowService.FindNodeByID(flow, fallbackEdge.Target)
+}
@@ -0,0 +1,74 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProcessLeadIntake turns an external lead (form fill, Meta Lead Ads) into a
+// WhatsApp conversation: the optional template message is sent first, then
+// the source's flow is started for the new prospect
+func (s *Service) ProcessLeadIntake(source *models.LeadSource, name, phone string) error {
+	logrus.WithFields(logrus.Fields{
+		"source_id":    source.ID,
+		"source_name":  source.Name,
+		"prospect_num": phone,
+		"id_device":    source.IDDevice,
+	}).Info("📥 LEAD: Processing incoming lead")
+
+	// Leads are proactive outreach too - respect the device's warm-up cap
+	if blockReason, err := s.reserveProactiveSend(source.IDDevice); err != nil {
+		return err
+	} else if blockReason != "" {
+		return fmt.Errorf("lead intake blocked: %s", blockReason)
+	}
+
+	// Greet the lead with the configured template before the flow starts
+	if source.TemplateMessage != "" {
+		templateMessage := utils.ExpandSpintaxForRecipient(source.TemplateMessage, phone)
+		if err := s.SendMessageFromDevice(source.IDDevice, phone, templateMessage); err != nil {
+			logrus.WithError(err).Error("📥 LEAD: Failed to send template message")
+		} else if err := s.aiWhatsappService.SaveConversationHistory(phone, source.IDDevice, "", templateMessage, "", name); err != nil {
+			logrus.WithError(err).Error("📥 LEAD: Failed to save template message to history")
+		}
+	}
+
+	if source.FlowID == "" {
+		return nil
+	}
+
+	execution, err := s.aiWhatsappService.StartFlowExecution(phone, source.IDDevice, source.FlowID, make(map[string]interface{}))
+	if err != nil {
+		return fmt.Errorf("failed to start flow for lead: %w", err)
+	}
+
+	if name != "" {
+		if err := s.aiWhatsappService.UpdateProspectName(phone, source.IDDevice, name); err != nil {
+			logrus.WithError(err).Warn("📥 LEAD: Failed to save prospect name")
+		}
+	}
+
+	flow, err := s.flowService.GetFlow(source.FlowID)
+	if err != nil {
+		return fmt.Errorf("failed to load flow for lead: %w", err)
+	}
+
+	response, err := s.processFlowMessage(flow, execution, "")
+	if err != nil {
+		return fmt.Errorf("failed to process flow for lead: %w", err)
+	}
+	if response == "" {
+		return nil
+	}
+
+	if s.mediaDetectionService.HasMedia(response) {
+		if mediaInfo := s.mediaDetectionService.ExtractFirstMedia(response); mediaInfo != nil {
+			return s.SendMediaMessage(source.IDDevice, phone, mediaInfo.MediaURL)
+		}
+	}
+	return s.SendMessageFromDevice(source.IDDevice, phone, response)
+}
@@ -0,0 +1,27 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/services"
+)
+
+// SetLeadScoringService wires conversation signals into lead scoring: inbound
+// messages are scored for keywords and reply latency, outbound sends are
+// marked so the fast-reply window can be measured, and classified intents add
+// their configured points
+func (s *Service) SetLeadScoringService(leadScoringService *services.LeadScoringService) {
+	s.leadScoringService = leadScoringService
+}
+
+// scoreInboundMessage feeds an inbound message into lead scoring when wired
+func (s *Service) scoreInboundMessage(deviceID, phoneNumber, content string) {
+	if s.leadScoringService != nil {
+		s.leadScoringService.ScoreMessage(deviceID, phoneNumber, content)
+	}
+}
+
+// markOutboundForScoring records an outbound send for the fast-reply signal
+func (s *Service) markOutboundForScoring(deviceID, phoneNumber string) {
+	if s.leadScoringService != nil {
+		s.leadScoringService.MarkOutbound(deviceID, phoneNumber)
+	}
+}
@@ -0,0 +1,159 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Loop nodes repeat a sub-path a fixed number of times or while a condition
+// holds - e.g. paginating a product list or re-sending a reminder. The body is
+// the edge with source handle "body"; the body path runs until it loops back to
+// the loop node or ends. The flow then continues down the "done" edge.
+
+// loopIterationCap is a runtime safety net on top of the validator's cap, for
+// flows saved before the loop validator existed
+const loopIterationCap = 25
+
+// processLoopNode runs the loop body the configured number of times, then
+// continues the flow past the loop node
+func (s *Service) processLoopNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	config := node.LoopConfig()
+	bodyEdge, doneEdge := s.loopEdges(flow, node)
+
+	if bodyEdge == nil {
+		logrus.WithField("node_id", node.ID).Warn("🔁 LOOP: Node has no body edge, skipping to next node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	iterations := config.Iterations
+	if iterations < 1 || iterations > loopIterationCap {
+		iterations = loopIterationCap
+	}
+
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		variables = make(map[string]interface{})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"iterations":   iterations,
+		"condition":    config.Condition,
+		"prospect_num": execution.ProspectNum,
+	}).Info("🔁 LOOP: Starting loop body")
+
+	completed := 0
+	for i := 0; i < iterations; i++ {
+		if config.Condition != "" && !services.EvaluateEdgeCondition(config.Condition, userInput, variables) {
+			logrus.WithFields(logrus.Fields{
+				"node_id":   node.ID,
+				"iteration": i,
+			}).Info("🔁 LOOP: Condition no longer holds, exiting loop")
+			break
+		}
+		if !s.runLoopBody(flow, execution, node, bodyEdge) {
+			break
+		}
+		completed++
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":    node.ID,
+		"iterations": completed,
+	}).Info("✅ LOOP: Loop finished, continuing flow")
+
+	if doneEdge == nil {
+		logrus.WithField("node_id", node.ID).Info("🔁 LOOP: No done edge, completing flow")
+		return "", s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice)
+	}
+
+	nextNode, err := s.flowService.FindNodeByID(flow, doneEdge.Target)
+	if err != nil || nextNode == nil {
+		return "", s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice)
+	}
+
+	s.updateCurrentNode(execution, nextNode.ID)
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, nextNode.ID, make(map[string]interface{}), "active"); err != nil {
+		logrus.WithError(err).Error("🔁 LOOP: Failed to advance execution past loop node")
+	}
+
+	return s.processFlowMessage(flow, execution, "")
+}
+
+// runLoopBody executes one pass of the loop body using the same node effects as
+// parallel branches. Returns false when the body cannot continue
+func (s *Service) runLoopBody(flow *models.ChatbotFlow, execution *models.AIWhatsapp, loopNode *models.FlowNode, bodyEdge *models.FlowEdge) bool {
+	current, err := s.flowService.FindNodeByID(flow, bodyEdge.Target)
+	if err != nil || current == nil {
+		logrus.WithError(err).WithField("edge_id", bodyEdge.ID).Error("🔁 LOOP: Body entry node not found")
+		return false
+	}
+
+	// Guard against body paths longer than any sane flow
+	for steps := 0; current != nil && steps < loopIterationCap; steps++ {
+		// The body loops back to the loop node to close one iteration
+		if current.ID == loopNode.ID {
+			return true
+		}
+
+		if !s.applyBranchNode(flow, execution, current) {
+			return false
+		}
+
+		next, err := s.flowService.GetNextNode(flow, current.ID)
+		if err != nil || next == nil {
+			// Body path ends without returning to the loop node - still one
+			// completed pass
+			return true
+		}
+		current = next
+	}
+
+	return true
+}
+
+// loopEdges splits a loop node's outgoing edges into the body edge (source
+// handle "body", falling back to the first edge) and the done edge (source
+// handle "done"/"exit", falling back to the second edge)
+func (s *Service) loopEdges(flow *models.ChatbotFlow, node *models.FlowNode) (*models.FlowEdge, *models.FlowEdge) {
+	edges, err := s.flowService.GetFlowEdges(flow)
+	if err != nil {
+		return nil, nil
+	}
+
+	var outgoing []*models.FlowEdge
+	var bodyEdge, doneEdge *models.FlowEdge
+	for _, edge := range edges {
+		if edge.Source != node.ID {
+			continue
+		}
+		outgoing = append(outgoing, edge)
+		switch edge.SourceHandle {
+		case "body", "loop":
+			if bodyEdge == nil {
+				bodyEdge = edge
+			}
+		case "done", "exit":
+			if doneEdge == nil {
+				doneEdge = edge
+			}
+		}
+	}
+
+	// Legacy flows without handles: first edge is the body, second the exit
+	if bodyEdge == nil && len(outgoing) > 0 {
+		bodyEdge = outgoing[0]
+	}
+	if doneEdge == nil {
+		for _, edge := range outgoing {
+			if edge != bodyEdge {
+				doneEdge = edge
+				break
+			}
+		}
+	}
+
+	return bodyEdge, doneEdge
+}
@@ -0,0 +1,112 @@
+package whatsapp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Nearest-branch nodes ask the prospect to share their location, find the
+// closest configured outlet with a haversine search, and inject the branch
+// details as flow variables for directions and branch-specific routing.
+
+// coordinatePattern matches "lat,lng" pairs in plain text as well as inside
+// shared Google Maps links (?q=lat,lng or @lat,lng)
+var coordinatePattern = regexp.MustCompile(`(-?\d{1,2}\.\d+)\s*,\s*(-?\d{1,3}\.\d+)`)
+
+// processNearestBranchNode prompts for the prospect's location and waits.
+// The reply is resolved in handleNearestBranchReply
+func (s *Service) processNearestBranchNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if s.branchLocationService == nil {
+		logrus.Warn("📍 BRANCH: Branch location service not available, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	message := node.NearestBranchConfig().Message
+	if message == "" {
+		message = "📍 Please share your location so we can find the branch nearest to you"
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"prospect_num": execution.ProspectNum,
+	}).Info("📍 BRANCH: Asking for location, waiting for reply")
+
+	if err := s.updateFlowTrackingFields(execution, node.ID, flow.ID, true); err != nil {
+		logrus.WithError(err).Error("📍 BRANCH: Failed to set waiting state")
+		return "", err
+	}
+
+	return message, nil
+}
+
+// handleNearestBranchReply resolves a location reply into the nearest branch.
+// Returns the branch name as translated input for edge routing; handled=true
+// means the reply was consumed (no coordinates found, prospect re-prompted)
+func (s *Service) handleNearestBranchReply(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, bool, error) {
+	if s.branchLocationService == nil {
+		return userInput, false, nil
+	}
+
+	latitude, longitude, ok := parseCoordinates(userInput)
+	if !ok {
+		prompt := "Sorry, we could not read that location. Please share your location pin, or send coordinates like 3.1390, 101.6869"
+		return "", true, s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, prompt)
+	}
+
+	branch, distanceKm, err := s.branchLocationService.FindNearestBranch(execution.IDDevice, latitude, longitude)
+	if err != nil {
+		logrus.WithError(err).Error("📍 BRANCH: Failed to find nearest branch")
+		return "", true, err
+	}
+	if branch == nil {
+		logrus.WithField("id_device", execution.IDDevice).Warn("📍 BRANCH: No branches configured, continuing without one")
+		return userInput, false, nil
+	}
+
+	// Inject branch details for downstream messages and routing
+	variables := map[string]interface{}{
+		"branch_name":        branch.Name,
+		"branch_address":     branch.Address,
+		"branch_phone":       branch.Phone,
+		"branch_device":      branch.BranchDevice,
+		"branch_distance_km": fmt.Sprintf("%.1f", distanceKm),
+		"branch_maps_link":   fmt.Sprintf("https://maps.google.com/?q=%f,%f", branch.Latitude, branch.Longitude),
+	}
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, variables, "active"); err != nil {
+		logrus.WithError(err).Error("📍 BRANCH: Failed to store branch variables")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":     node.ID,
+		"branch":      branch.Name,
+		"distance_km": fmt.Sprintf("%.1f", distanceKm),
+	}).Info("✅ BRANCH: Nearest branch resolved")
+
+	// Route downstream edges on the branch name
+	return branch.Name, false, nil
+}
+
+// parseCoordinates extracts a latitude/longitude pair from a location share
+// or free text
+func parseCoordinates(content string) (float64, float64, bool) {
+	match := coordinatePattern.FindStringSubmatch(content)
+	if match == nil {
+		return 0, 0, false
+	}
+
+	latitude, err1 := strconv.ParseFloat(match[1], 64)
+	longitude, err2 := strconv.ParseFloat(match[2], 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return 0, 0, false
+	}
+
+	return latitude, longitude, true
+}
@@ -0,0 +1,171 @@
+package whatsapp
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The node watchdog bounds how long a single node may run. Only nodes that
+// make outbound calls (AI prompts, webhook-backed custom nodes) are wrapped —
+// a hung provider or partner endpoint would otherwise stall the execution
+// indefinitely. A timed-out step is recorded per node type, and the flow
+// routes to the node's error branch (an outgoing edge with sourceHandle
+// "error") when one exists, otherwise the execution is marked failed
+
+// Default node timeouts; override with NODE_TIMEOUT_AI_SECONDS,
+// NODE_TIMEOUT_CUSTOM_SECONDS and NODE_TIMEOUT_DEFAULT_SECONDS
+const (
+	nodeTimeoutAIDefault      = 90 * time.Second
+	nodeTimeoutCustomDefault  = 45 * time.Second
+	nodeTimeoutDefaultDefault = 30 * time.Second
+)
+
+// nodeErrorBranchHandle marks the edge a timed-out node routes to
+const nodeErrorBranchHandle = "error"
+
+var (
+	nodeTimeoutOnce    sync.Once
+	nodeTimeoutAI      time.Duration
+	nodeTimeoutCustom  time.Duration
+	nodeTimeoutGeneric time.Duration
+)
+
+// nodeTimeoutFromEnv reads one override, keeping the default when unset or
+// invalid
+func nodeTimeoutFromEnv(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		logrus.Warnf("⏱️ WATCHDOG: Ignoring invalid %s=%q", key, raw)
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// nodeTimeoutFor returns the timeout budget for a node type
+func nodeTimeoutFor(nodeType models.NodeType) time.Duration {
+	nodeTimeoutOnce.Do(func() {
+		nodeTimeoutAI = nodeTimeoutFromEnv("NODE_TIMEOUT_AI_SECONDS", nodeTimeoutAIDefault)
+		nodeTimeoutCustom = nodeTimeoutFromEnv("NODE_TIMEOUT_CUSTOM_SECONDS", nodeTimeoutCustomDefault)
+		nodeTimeoutGeneric = nodeTimeoutFromEnv("NODE_TIMEOUT_DEFAULT_SECONDS", nodeTimeoutDefaultDefault)
+	})
+
+	switch nodeType {
+	case models.NodeTypeAIPrompt, models.NodeTypeAdvancedAIPrompt, "prompt":
+		return nodeTimeoutAI
+	default:
+		if isCustomNodeType(nodeType) {
+			return nodeTimeoutCustom
+		}
+		return nodeTimeoutGeneric
+	}
+}
+
+// nodeTimeoutStats counts timed-out steps per node type since startup
+var (
+	nodeTimeoutStatsMux sync.Mutex
+	nodeTimeoutStats    = make(map[string]int64)
+)
+
+// recordNodeTimeout bumps the per-type timeout counter
+func recordNodeTimeout(nodeType models.NodeType) {
+	nodeTimeoutStatsMux.Lock()
+	defer nodeTimeoutStatsMux.Unlock()
+	nodeTimeoutStats[string(nodeType)]++
+}
+
+// NodeTimeoutStats returns the per-node-type timeout counts since startup
+func (s *Service) NodeTimeoutStats() map[string]int64 {
+	nodeTimeoutStatsMux.Lock()
+	defer nodeTimeoutStatsMux.Unlock()
+
+	stats := make(map[string]int64, len(nodeTimeoutStats))
+	for nodeType, count := range nodeTimeoutStats {
+		stats[nodeType] = count
+	}
+	return stats
+}
+
+// runNodeWithTimeout runs one node processor under the watchdog. The hung
+// call itself cannot be cancelled mid-flight, but the execution is released:
+// its result is discarded and the flow moves on through the error branch
+func (s *Service) runNodeWithTimeout(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string, process func() (string, error)) (string, error) {
+	type nodeOutcome struct {
+		response string
+		err      error
+	}
+
+	outcome := make(chan nodeOutcome, 1)
+	go func() {
+		response, err := process()
+		outcome <- nodeOutcome{response: response, err: err}
+	}()
+
+	timeout := nodeTimeoutFor(node.Type)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-outcome:
+		return result.response, result.err
+	case <-timer.C:
+		recordNodeTimeout(node.Type)
+		logrus.WithFields(logrus.Fields{
+			"flow_id":   flow.ID,
+			"node_id":   node.ID,
+			"node_type": node.Type,
+			"timeout":   timeout.String(),
+		}).Error("⏱️ WATCHDOG: Node timed out, marking step failed")
+		return s.routeNodeTimeout(flow, execution, node, userInput)
+	}
+}
+
+// routeNodeTimeout advances a timed-out step along the node's error branch,
+// or marks the execution failed when the flow has none
+func (s *Service) routeNodeTimeout(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	edges, err := s.flowService.GetFlowEdges(flow)
+	if err == nil {
+		for _, edge := range edges {
+			if edge.Source != node.ID || edge.SourceHandle != nodeErrorBranchHandle {
+				continue
+			}
+			errorNode, err := s.flowService.FindNodeByID(flow, edge.Target)
+			if err != nil || errorNode == nil {
+				continue
+			}
+
+			logrus.WithFields(logrus.Fields{
+				"node_id":    node.ID,
+				"error_node": errorNode.ID,
+			}).Info("⏱️ WATCHDOG: Routing timed-out step to error branch")
+
+			s.updateCurrentNode(execution, errorNode.ID)
+			if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, errorNode.ID, make(map[string]interface{}), "active"); err != nil {
+				logrus.WithError(err).Error("⏱️ WATCHDOG: Failed to advance to error branch")
+				return "", err
+			}
+			return s.processFlowMessage(flow, execution, userInput)
+		}
+	}
+
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, make(map[string]interface{}), "failed"); err != nil {
+		logrus.WithError(err).Error("⏱️ WATCHDOG: Failed to mark execution failed")
+	}
+	return "", nil
+}
+
+// isCustomNodeType reports whether the type is a partner-registered webhook
+// node
+func isCustomNodeType(nodeType models.NodeType) bool {
+	return len(nodeType) > len(models.CustomNodeTypePrefix) &&
+		string(nodeType[:len(models.CustomNodeTypePrefix)]) == models.CustomNodeTypePrefix
+}
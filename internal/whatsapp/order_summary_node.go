@@ -0,0 +1,72 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Order summary nodes close the shopping loop: render the cart, persist it as
+// a pending order for e-commerce sync, and clear the cart for the next visit.
+
+// processOrderSummaryNode captures the conversation's cart as an order and
+// sends the summary with the order reference
+func (s *Service) processOrderSummaryNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if s.cartService == nil || s.flowOrderService == nil {
+		logrus.Warn("🧾 ORDER: Cart or order service not available, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	items, err := s.cartService.GetCart(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Error("🧾 ORDER: Failed to load cart")
+		return "", err
+	}
+
+	if len(items) == 0 {
+		logrus.WithField("prospect_num", execution.ProspectNum).Info("🧾 ORDER: Cart is empty, skipping order capture")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	order, err := s.flowOrderService.CreateOrderFromCart(execution.ProspectNum, execution.IDDevice, items)
+	if err != nil {
+		logrus.WithError(err).Error("🧾 ORDER: Failed to capture order")
+		return "", err
+	}
+
+	if err := s.cartService.ClearCart(execution.ProspectNum, execution.IDDevice); err != nil {
+		logrus.WithError(err).Error("🧾 ORDER: Failed to clear cart after capture")
+	}
+
+	summary := services.FormatCartSummary(items)
+	summary += fmt.Sprintf("\n\nOrder reference: %s", order.ID)
+	if config := node.OrderSummaryConfig(); config.Message != "" {
+		summary = config.Message + "\n\n" + summary
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"order_id":     order.ID,
+		"prospect_num": execution.ProspectNum,
+	}).Info("✅ ORDER: Order summary sent, advancing flow")
+
+	// Advance to the next node; the summary goes out as this node's response
+	nextNode, nextErr := s.flowService.GetNextNode(flow, node.ID)
+	if nextErr != nil || nextNode == nil {
+		if err := s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice); err != nil {
+			logrus.WithError(err).Error("🧾 ORDER: Failed to complete flow after order capture")
+		}
+		return summary, nil
+	}
+
+	s.updateCurrentNode(execution, nextNode.ID)
+	waiting := nextNode.Type == models.NodeTypeUserReply
+	if err := s.updateFlowTrackingFields(execution, nextNode.ID, flow.ID, waiting); err != nil {
+		logrus.WithError(err).Error("🧾 ORDER: Failed to advance execution after order capture")
+	}
+
+	return summary, nil
+}
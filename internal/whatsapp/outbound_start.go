@@ -0,0 +1,68 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StartFlowForProspect proactively starts a flow for a phone number and sends
+// the flow's first messages — the outbound counterpart to keyword-triggered
+// flows. The device's opt-out list and quiet hours are checked first; the
+// returned blockReason is non-empty when policy stopped the send
+func (s *Service) StartFlowForProspect(flowID, idDevice, phone string, variables map[string]interface{}) (string, error) {
+	if s.outboundPolicyService != nil {
+		allowed, reason, err := s.outboundPolicyService.CanMessage(idDevice, phone)
+		if err != nil {
+			return "", fmt.Errorf("failed to check outbound policy: %w", err)
+		}
+		if !allowed {
+			logrus.WithFields(logrus.Fields{
+				"flow_id":      flowID,
+				"prospect_num": phone,
+				"reason":       reason,
+			}).Info("🚫 OUTBOUND: Flow start blocked by policy")
+			return reason, nil
+		}
+	}
+
+	// Respect the device's warm-up cap and pace the send across the day
+	blockReason, err := s.reserveProactiveSend(idDevice)
+	if err != nil {
+		return "", err
+	}
+	if blockReason != "" {
+		return blockReason, nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id":      flowID,
+		"prospect_num": phone,
+		"id_device":    idDevice,
+	}).Info("📤 OUTBOUND: Starting flow proactively")
+
+	flow, err := s.flowService.GetFlow(flowID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load flow: %w", err)
+	}
+
+	execution, err := s.aiWhatsappService.StartFlowExecution(phone, idDevice, flowID, variables)
+	if err != nil {
+		return "", fmt.Errorf("failed to start flow execution: %w", err)
+	}
+
+	response, err := s.processFlowMessage(flow, execution, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to process flow start: %w", err)
+	}
+	if response == "" {
+		return "", nil
+	}
+
+	if s.mediaDetectionService.HasMedia(response) {
+		if mediaInfo := s.mediaDetectionService.ExtractFirstMedia(response); mediaInfo != nil {
+			return "", s.SendMediaMessage(idDevice, phone, mediaInfo.MediaURL)
+		}
+	}
+	return "", s.SendMessageFromDevice(idDevice, phone, response)
+}
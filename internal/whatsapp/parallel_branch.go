@@ -0,0 +1,206 @@
+package whatsapp
+
+import (
+	"time"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Parallel nodes fork the flow into every outgoing edge at once (e.g. sending a
+// media sequence while scheduling a follow-up). Each branch runs in its own
+// goroutine until it reaches a join node or ends; the join node releases once
+// every incoming edge has recorded an arrival.
+
+// processParallelNode forks the execution into all outgoing branches
+func (s *Service) processParallelNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	edges, err := s.flowService.GetFlowEdges(flow)
+	if err != nil {
+		return "", err
+	}
+
+	var outgoing []*models.FlowEdge
+	for _, edge := range edges {
+		if edge.Source == node.ID {
+			outgoing = append(outgoing, edge)
+		}
+	}
+
+	if len(outgoing) == 0 {
+		logrus.WithField("node_id", node.ID).Info("🔀 PARALLEL: No branches, completing flow")
+		return "", s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"branch_count": len(outgoing),
+		"prospect_num": execution.ProspectNum,
+	}).Info("🔀 PARALLEL: Forking flow into parallel branches")
+
+	// Keep the execution parked on the parallel node while branches run; the
+	// join node moves it forward when all branches complete
+	s.updateCurrentNode(execution, node.ID)
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, make(map[string]interface{}), "active"); err != nil {
+		logrus.WithError(err).Error("🔀 PARALLEL: Failed to park execution on parallel node")
+	}
+
+	for _, edge := range outgoing {
+		branchEdge := edge
+		go s.runParallelBranch(flow, execution, branchEdge)
+	}
+
+	return "", nil
+}
+
+// runParallelBranch walks one branch node by node until it hits a join node or
+// the branch ends. Only fire-and-forget node types are supported inside a
+// branch; nodes that wait for user input stop the branch
+func (s *Service) runParallelBranch(flow *models.ChatbotFlow, execution *models.AIWhatsapp, entryEdge *models.FlowEdge) {
+	current, err := s.flowService.FindNodeByID(flow, entryEdge.Target)
+	if err != nil || current == nil {
+		logrus.WithError(err).WithField("edge_id", entryEdge.ID).Error("🔀 PARALLEL: Branch entry node not found")
+		return
+	}
+
+	for current != nil {
+		if current.Type == models.NodeTypeJoin {
+			s.arriveAtJoin(flow, execution, current, entryEdge)
+			return
+		}
+
+		if !s.applyBranchNode(flow, execution, current) {
+			return
+		}
+
+		next, err := s.flowService.GetNextNode(flow, current.ID)
+		if err != nil || next == nil {
+			logrus.WithFields(logrus.Fields{
+				"edge_id": entryEdge.ID,
+				"node_id": current.ID,
+			}).Info("🔀 PARALLEL: Branch ended without a join node")
+			return
+		}
+		current = next
+	}
+}
+
+// applyBranchNode performs one branch node's effect. Returns false when the
+// branch cannot continue (unsupported node type or send failure)
+func (s *Service) applyBranchNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode) bool {
+	switch node.Type {
+	case models.NodeTypeMessage:
+		message := node.MessageConfig().Message
+		variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+		if err != nil {
+			variables = make(map[string]interface{})
+		}
+		message = s.flowService.ReplaceVariables(message, variables)
+		message = utils.ExpandSpintaxForRecipient(message, execution.ProspectNum)
+		if err := s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, message); err != nil {
+			logrus.WithError(err).Error("🔀 PARALLEL: Failed to send branch message")
+			return false
+		}
+		if err := s.aiWhatsappService.SaveConversationHistory(execution.ProspectNum, execution.IDDevice, "", message, "", execution.ProspectName.String); err != nil {
+			logrus.WithError(err).Error("🔀 PARALLEL: Failed to save branch message to conversation")
+		}
+		return true
+
+	case models.NodeTypeImage:
+		return s.sendBranchMedia(execution, node.ImageConfig().ImageURL)
+	case models.NodeTypeAudio:
+		return s.sendBranchMedia(execution, node.AudioConfig().AudioURL)
+	case models.NodeTypeVideo:
+		return s.sendBranchMedia(execution, node.VideoConfig().VideoURL)
+
+	case models.NodeTypeDelay:
+		delaySeconds := 5.0
+		if configured := node.DelayConfig().Seconds; configured > 0 {
+			delaySeconds = configured
+		}
+		s.clock.Sleep(time.Duration(delaySeconds) * time.Second)
+		return true
+
+	case models.NodeTypeStage:
+		stage := node.StageConfig().Stage
+		if stage != "" {
+			if err := s.aiWhatsappService.UpdateStageWithSource(execution.ProspectNum, execution.IDDevice, stage, node.ID); err != nil {
+				logrus.WithError(err).Error("🔀 PARALLEL: Failed to update stage in branch")
+			}
+		}
+		return true
+
+	default:
+		logrus.WithFields(logrus.Fields{
+			"node_id":   node.ID,
+			"node_type": node.Type,
+		}).Warn("🔀 PARALLEL: Node type not supported inside a parallel branch, stopping branch")
+		return false
+	}
+}
+
+// sendBranchMedia sends a media URL from within a parallel branch
+func (s *Service) sendBranchMedia(execution *models.AIWhatsapp, mediaURL string) bool {
+	if mediaURL == "" {
+		return true
+	}
+	if err := s.SendMediaMessage(execution.IDDevice, execution.ProspectNum, mediaURL); err != nil {
+		logrus.WithError(err).Error("🔀 PARALLEL: Failed to send branch media")
+		return false
+	}
+	return true
+}
+
+// arriveAtJoin records a branch arrival and releases the join once every
+// incoming edge has arrived
+func (s *Service) arriveAtJoin(flow *models.ChatbotFlow, execution *models.AIWhatsapp, joinNode *models.FlowNode, entryEdge *models.FlowEdge) {
+	if err := s.flowService.RecordBranchArrival(execution.ProspectNum, execution.IDDevice, flow.ID, joinNode.ID, entryEdge.ID); err != nil {
+		logrus.WithError(err).Error("🔀 PARALLEL: Failed to record branch arrival at join")
+		return
+	}
+
+	edges, err := s.flowService.GetFlowEdges(flow)
+	if err != nil {
+		return
+	}
+	incoming := 0
+	for _, edge := range edges {
+		if edge.Target == joinNode.ID {
+			incoming++
+		}
+	}
+
+	arrived, err := s.flowService.CountBranchArrivals(execution.ProspectNum, execution.IDDevice, joinNode.ID)
+	if err != nil {
+		logrus.WithError(err).Error("🔀 PARALLEL: Failed to count branch arrivals")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"join_node_id": joinNode.ID,
+		"arrived":      arrived,
+		"incoming":     incoming,
+	}).Info("🔀 PARALLEL: Branch arrived at join node")
+
+	if arrived < incoming {
+		return
+	}
+
+	// All branches done - clear state and continue the flow past the join
+	if err := s.flowService.ClearJoinState(execution.ProspectNum, execution.IDDevice, joinNode.ID); err != nil {
+		logrus.WithError(err).Error("🔀 PARALLEL: Failed to clear join state")
+	}
+
+	logrus.WithField("join_node_id", joinNode.ID).Info("✅ PARALLEL: All branches complete, continuing after join")
+
+	if err := s.advancePastNode(flow, execution, joinNode); err != nil {
+		logrus.WithError(err).Error("🔀 PARALLEL: Failed to continue flow after join")
+	}
+}
+
+// processJoinNode handles a join node reached sequentially (outside a fork).
+// With no pending branches it simply passes through to the next node
+func (s *Service) processJoinNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	return "", s.advancePastNode(flow, execution, node)
+}
@@ -0,0 +1,146 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Quick replies let a message node suggest canned answers. The provider layer
+// renders them as tappable buttons where supported and as a numbered list
+// elsewhere; when the prospect answers, the reply is matched back onto the
+// option text before edge conditions route on it, so conditions can target
+// the canonical option regardless of how the prospect typed it.
+
+// SendQuickReplyFromDevice sends a message with quick-reply suggestions from
+// a specific device through the appropriate provider
+func (s *Service) SendQuickReplyFromDevice(deviceID, phoneNumber, message string, options []string) error {
+	logrus.WithFields(logrus.Fields{
+		"device_id":    deviceID,
+		"phone_number": phoneNumber,
+		"message":      message,
+		"options":      len(options),
+	}).Info("📤 MESSAGE: Sending quick-reply message from device")
+
+	// Get device settings by device_id
+	deviceSettings, err := s.deviceSettingsService.GetByIDDevice(deviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get device settings for %s: %w", deviceID, err)
+	}
+
+	// Same short-link rewriting as plain text sends
+	if s.shortLinkService != nil {
+		message = s.shortLinkService.ShortenMessageURLs(deviceID, phoneNumber, message)
+	}
+
+	err = s.providerService.SendQuickReplyMessage(deviceSettings, phoneNumber, message, options)
+	s.recordSendResult(deviceID, deviceSettings.Provider, err)
+	if err != nil {
+		return fmt.Errorf("failed to send quick-reply message through provider: %w", err)
+	}
+
+	return nil
+}
+
+// resolveQuickReply maps the prospect's reply onto the quick-reply options of
+// the message node feeding the given user_reply node. Option text matches
+// case-insensitively and a bare number picks the option with that list
+// position; the input is returned unchanged when nothing matches or the
+// preceding message declared no quick replies
+func (s *Service) resolveQuickReply(flow *models.ChatbotFlow, userReplyNodeID, userInput string) string {
+	options := s.quickRepliesInto(flow, userReplyNodeID)
+	if len(options) == 0 {
+		return userInput
+	}
+
+	reply := strings.TrimSpace(userInput)
+
+	for _, option := range options {
+		if strings.EqualFold(reply, option) {
+			if reply != option {
+				logrus.WithFields(logrus.Fields{
+					"reply":  userInput,
+					"option": option,
+				}).Info("💬 QUICK_REPLY: Matched reply to option text")
+			}
+			return option
+		}
+	}
+
+	// The numbered-list fallback invites replying with the list position.
+	// "1." and "1)" count too since prospects often copy the list formatting
+	if pick, err := strconv.Atoi(strings.TrimRight(reply, ".)")); err == nil && pick >= 1 && pick <= len(options) {
+		logrus.WithFields(logrus.Fields{
+			"reply":  userInput,
+			"option": options[pick-1],
+		}).Info("💬 QUICK_REPLY: Matched reply to option by number")
+		return options[pick-1]
+	}
+
+	// Fuzzy match: a reply that unambiguously points at one option ("pricing
+	// please" or just "pric") still picks it
+	if option, ok := fuzzyMatchOption(reply, options); ok {
+		logrus.WithFields(logrus.Fields{
+			"reply":  userInput,
+			"option": option,
+		}).Info("💬 QUICK_REPLY: Fuzzy-matched reply to option")
+		return option
+	}
+
+	return userInput
+}
+
+// fuzzyMatchMinLength is the shortest reply fuzzy matching considers, so a
+// stray "k" or emoji cannot accidentally pick an option
+const fuzzyMatchMinLength = 3
+
+// fuzzyMatchOption finds the single option the reply points at: either the
+// reply contains the option text or the option starts with the reply. The
+// match only counts when exactly one option qualifies
+func fuzzyMatchOption(reply string, options []string) (string, bool) {
+	reply = strings.ToLower(reply)
+	if len(reply) < fuzzyMatchMinLength {
+		return "", false
+	}
+
+	matched := ""
+	matches := 0
+	for _, option := range options {
+		lowered := strings.ToLower(option)
+		if strings.Contains(reply, lowered) || strings.HasPrefix(lowered, reply) {
+			matched = option
+			matches++
+		}
+	}
+	if matches != 1 {
+		return "", false
+	}
+	return matched, true
+}
+
+// quickRepliesInto returns the quick replies of the message node whose edge
+// leads into the given node
+func (s *Service) quickRepliesInto(flow *models.ChatbotFlow, nodeID string) []string {
+	edges, err := s.flowService.GetFlowEdges(flow)
+	if err != nil {
+		return nil
+	}
+
+	for _, edge := range edges {
+		if edge.Target != nodeID {
+			continue
+		}
+		source, err := s.flowService.FindNodeByID(flow, edge.Source)
+		if err != nil || source == nil || source.Type != models.NodeTypeMessage {
+			continue
+		}
+		if replies := source.MessageConfig().QuickReplies; len(replies) > 0 {
+			return replies
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Receipt verification nodes ask the prospect for a payment receipt photo,
+// OCR it through the vision model and compare the extracted amount (and
+// optionally the payment reference) against expected values from flow
+// variables. The flow branches down the "match" or "mismatch" edge, or down
+// the "review" edge for unreadable photos so a human can check the payment.
+
+const (
+	// defaultReceiptPrompt is sent when the node configures no ask message
+	defaultReceiptPrompt = "Please send a photo of your payment receipt."
+	// defaultReceiptTolerance allows for rounding differences between the
+	// OCR'd amount and the expected amount
+	defaultReceiptTolerance = 0.01
+	// defaultReceiptMinConfidence routes low-confidence extractions to
+	// manual review instead of risking a wrong verdict
+	defaultReceiptMinConfidence = 0.6
+)
+
+// Receipt verdicts double as edge source handles
+const (
+	receiptVerdictMatch    = "match"
+	receiptVerdictMismatch = "mismatch"
+	receiptVerdictReview   = "review"
+)
+
+// processReceiptVerifyNode parks the execution waiting for the receipt
+// image, or verifies the pending image and branches on the verdict
+func (s *Service) processReceiptVerifyNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	config := node.ReceiptVerifyConfig()
+
+	askMessage := config.Message
+	if askMessage == "" {
+		askMessage = defaultReceiptPrompt
+	}
+
+	if userInput == "" {
+		logrus.WithFields(logrus.Fields{
+			"node_id":      node.ID,
+			"prospect_num": execution.ProspectNum,
+		}).Info("🧾 RECEIPT: No input yet, asking for receipt photo")
+		if err := s.updateFlowTrackingFields(execution, node.ID, flow.ID, true); err != nil {
+			return "", err
+		}
+		return askMessage, nil
+	}
+
+	// The receipt must arrive as an image; text replies get re-prompted
+	mediaInfo := s.mediaDetectionService.ExtractFirstMedia(userInput)
+	if mediaInfo == nil || mediaInfo.MediaType != "image" {
+		logrus.WithFields(logrus.Fields{
+			"node_id":      node.ID,
+			"prospect_num": execution.ProspectNum,
+		}).Info("🧾 RECEIPT: Reply has no image, asking again")
+		if err := s.updateFlowTrackingFields(execution, node.ID, flow.ID, true); err != nil {
+			return "", err
+		}
+		return askMessage, nil
+	}
+
+	verdict, extraction := s.verifyReceipt(execution, node, config, mediaInfo.MediaURL)
+
+	variables := map[string]interface{}{
+		"receipt_verdict": verdict,
+	}
+	if extraction != nil {
+		variables["receipt_amount"] = fmt.Sprintf("%.2f", extraction.Amount)
+		variables["receipt_reference"] = extraction.Reference
+		variables["receipt_date"] = extraction.Date
+	}
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, variables, "active"); err != nil {
+		logrus.WithError(err).Error("🧾 RECEIPT: Failed to store receipt variables")
+	}
+
+	nextNode, err := s.receiptEdgeTarget(flow, node, verdict)
+	if err != nil {
+		return "", err
+	}
+	if nextNode == nil {
+		logrus.WithField("node_id", node.ID).Info("🏁 RECEIPT: No edge to follow, completing flow")
+		return "", s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice)
+	}
+
+	s.updateCurrentNode(execution, nextNode.ID)
+	if err := s.updateFlowTrackingFields(execution, nextNode.ID, flow.ID, false); err != nil {
+		logrus.WithError(err).Error("🧾 RECEIPT: Failed to update flow tracking after verification")
+	}
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, nextNode.ID, make(map[string]interface{}), "active"); err != nil {
+		logrus.WithError(err).Error("🧾 RECEIPT: Failed to advance execution after verification")
+	}
+
+	return s.processFlowMessage(flow, execution, userInput)
+}
+
+// verifyReceipt OCRs the receipt image and compares the extraction against
+// the node's expected values. It returns the verdict and the extraction
+// (nil when OCR failed outright)
+func (s *Service) verifyReceipt(execution *models.AIWhatsapp, node *models.FlowNode, config models.ReceiptVerifyNodeConfig, imageURL string) (string, *services.ReceiptExtraction) {
+	minConfidence := config.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = defaultReceiptMinConfidence
+	}
+	tolerance := config.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultReceiptTolerance
+	}
+
+	extraction, err := s.aiService.ExtractReceipt(imageURL, s.deviceAPIKey(execution.IDDevice), execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).WithField("node_id", node.ID).Error("🧾 RECEIPT: OCR failed, routing to manual review")
+		return receiptVerdictReview, nil
+	}
+	if extraction.Confidence < minConfidence {
+		logrus.WithFields(logrus.Fields{
+			"node_id":        node.ID,
+			"confidence":     extraction.Confidence,
+			"min_confidence": minConfidence,
+		}).Info("🧾 RECEIPT: Confidence below threshold, routing to manual review")
+		return receiptVerdictReview, extraction
+	}
+
+	// Expected values may carry {{variable}} placeholders (e.g. an order
+	// total captured earlier in the flow)
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get execution variables")
+		variables = make(map[string]interface{})
+	}
+	expectedAmountText := strings.TrimSpace(s.flowService.ReplaceVariables(config.ExpectedAmount, variables))
+	expectedReference := strings.TrimSpace(s.flowService.ReplaceVariables(config.ExpectedReference, variables))
+
+	if expectedAmountText != "" {
+		expectedAmount, parseErr := strconv.ParseFloat(strings.ReplaceAll(expectedAmountText, ",", ""), 64)
+		if parseErr != nil {
+			logrus.WithField("expected_amount", expectedAmountText).Warn("🧾 RECEIPT: Expected amount is not a number, routing to manual review")
+			return receiptVerdictReview, extraction
+		}
+		diff := extraction.Amount - expectedAmount
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance {
+			logrus.WithFields(logrus.Fields{
+				"node_id":  node.ID,
+				"expected": expectedAmount,
+				"read":     extraction.Amount,
+			}).Info("❌ RECEIPT: Amount does not match")
+			return receiptVerdictMismatch, extraction
+		}
+	}
+
+	if expectedReference != "" && !strings.EqualFold(strings.TrimSpace(extraction.Reference), expectedReference) {
+		logrus.WithFields(logrus.Fields{
+			"node_id":  node.ID,
+			"expected": expectedReference,
+			"read":     extraction.Reference,
+		}).Info("❌ RECEIPT: Reference does not match")
+		return receiptVerdictMismatch, extraction
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"prospect_num": execution.ProspectNum,
+		"amount":       extraction.Amount,
+	}).Info("✅ RECEIPT: Receipt verified")
+	return receiptVerdictMatch, extraction
+}
+
+// receiptEdgeTarget resolves the node behind the edge for the verdict,
+// falling back to the "fallback"/"default" edge when no edge carries the
+// verdict's handle
+func (s *Service) receiptEdgeTarget(flow *models.ChatbotFlow, node *models.FlowNode, verdict string) (*models.FlowNode, error) {
+	edges, err := s.flowService.GetFlowEdges(flow)
+	if err != nil {
+		return nil, err
+	}
+
+	var fallbackEdge *models.FlowEdge
+	for _, edge := range edges {
+		if edge.Source != node.ID {
+			continue
+		}
+		if strings.EqualFold(edge.SourceHandle, verdict) {
+			return s.flowService.FindNodeByID(flow, edge.Target)
+		}
+		switch strings.ToLower(edge.SourceHandle) {
+		case "fallback", "default", "":
+			if fallbackEdge == nil {
+				fallbackEdge = edge
+			}
+		}
+	}
+
+	if fallbackEdge == nil {
+		return nil, nil
+	}
+	return s.flowService.FindNodeByID(flow, fallbackEdge.Target)
+}
@@ -0,0 +1,38 @@
+package whatsapp
+
+import (
+	"strconv"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// addReferralVariables exposes the prospect's referral stats to message
+// templates. Only computed when the template actually references them, since
+// {{referral_link}} creates the prospect's invite code on first use
+func (s *Service) addReferralVariables(execution *models.AIWhatsapp, message string, variables map[string]interface{}) {
+	if s.referralService == nil || !strings.Contains(message, "{{referral_") {
+		return
+	}
+
+	if strings.Contains(message, "{{referral_count}}") {
+		count, err := s.referralService.GetReferralCount(execution.IDDevice, execution.ProspectNum)
+		if err != nil {
+			logrus.WithError(err).Warn("🔗 REFERRAL: Failed to get referral count for template")
+		} else {
+			variables["referral_count"] = strconv.Itoa(count)
+		}
+	}
+
+	if strings.Contains(message, "{{referral_link}}") || strings.Contains(message, "{{referral_code}}") {
+		referral, err := s.referralService.GetOrCreateInviteLink(execution.IDDevice, execution.ProspectNum)
+		if err != nil {
+			logrus.WithError(err).Warn("🔗 REFERRAL: Failed to get invite link for template")
+			return
+		}
+		variables["referral_link"] = referral.InviteLink
+		variables["referral_code"] = "REF-" + referral.Code
+	}
+}
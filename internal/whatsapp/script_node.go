@@ -0,0 +1,64 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetScriptService wires sandboxed script node execution into the flow engine
+func (s *Service) SetScriptService(scriptService *services.ScriptService) {
+	s.scriptService = scriptService
+}
+
+// processScriptNode runs the node's script in the sandbox: variables go in,
+// transformed variables come out and whatever the script assigns to `output`
+// is sent as the reply. Scripts only run in flows with the permission
+// granted; a denied or failed script falls through to default-node handling
+// so the flow never stalls on a bad program
+func (s *Service) processScriptNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if s.scriptService == nil || !s.scriptService.IsFlowScriptEnabled(flow.ID) {
+		logrus.WithFields(logrus.Fields{
+			"flow_id": flow.ID,
+			"node_id": node.ID,
+		}).Warn("📜 SCRIPT: Script execution not enabled for flow, skipping node")
+		return s.processDefaultNode(flow, execution, node, userInput)
+	}
+
+	script := node.ScriptConfig().Script
+	if script == "" {
+		return s.processDefaultNode(flow, execution, node, userInput)
+	}
+
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get execution variables")
+		variables = make(map[string]interface{})
+	}
+
+	updated, output, err := s.scriptService.Run(script, userInput, variables)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"flow_id": flow.ID,
+			"node_id": node.ID,
+		}).Error("📜 SCRIPT: Script failed, skipping node")
+		return s.processDefaultNode(flow, execution, node, userInput)
+	}
+
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, updated, "active"); err != nil {
+		logrus.WithError(err).Error("📜 SCRIPT: Failed to save transformed variables")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"flow_id":    flow.ID,
+		"node_id":    node.ID,
+		"has_output": output != "",
+	}).Info("📜 SCRIPT: Script executed")
+
+	nextResponse, err := s.processDefaultNode(flow, execution, node, userInput)
+	if err != nil {
+		return output, err
+	}
+	return combineCustomNodeResponses(output, nextResponse), nil
+}
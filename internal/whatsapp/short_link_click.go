@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// linkClickEvent is the wait_event name link clicks are delivered as. A flow
+// parks on a wait_event node with this event (e.g. after sending a pricing
+// link) and resumes when the prospect clicks any tracked link, so the nodes
+// after it can move stage or branch
+const linkClickEvent = "link_click"
+
+// HandleShortLinkClick reacts to a prospect clicking a tracked short link:
+// the click is recorded in the conversation timeline and any execution
+// suspended on a wait_event node for "link_click" is resumed. Links without
+// prospect attribution (device-level links) are ignored
+func (s *Service) HandleShortLinkClick(link *models.ShortLink) {
+	if link == nil || link.ProspectNum == "" {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"code":         link.Code,
+		"target_url":   link.TargetURL,
+		"prospect_num": link.ProspectNum,
+		"id_device":    link.IDDevice,
+	}).Info("🔗 CLICK: Prospect clicked tracked link")
+
+	// Record the click in the conversation timeline
+	note := fmt.Sprintf("[Clicked link: %s]", link.TargetURL)
+	if err := s.aiWhatsappService.SaveConversationHistory(link.ProspectNum, link.IDDevice, note, "", "", ""); err != nil {
+		logrus.WithError(err).Error("🔗 CLICK: Failed to record click in conversation history")
+	}
+
+	// Resume the flow when it is parked waiting for a link click
+	execution, err := s.aiWhatsappService.GetFlowExecutionByProspectAndDevice(link.ProspectNum, link.IDDevice)
+	if err != nil || execution == nil {
+		return
+	}
+	if !execution.ExecutionStatus.Valid || execution.ExecutionStatus.String != waitEventStatus ||
+		!execution.CurrentNodeID.Valid || execution.CurrentNodeID.String == "" {
+		return
+	}
+
+	flow := s.flowForExecution(execution)
+	if flow == nil {
+		return
+	}
+
+	node, err := s.flowService.FindNodeByID(flow, execution.CurrentNodeID.String)
+	if err != nil || node == nil || node.Type != models.NodeTypeWaitEvent {
+		return
+	}
+	if node.WaitEventConfig().Event != linkClickEvent {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"prospect_num": link.ProspectNum,
+	}).Info("✅ CLICK: Resuming flow waiting on link click")
+
+	if err := s.advancePastNode(flow, execution, node); err != nil {
+		logrus.WithError(err).Error("🔗 CLICK: Failed to resume flow after link click")
+	}
+}
@@ -0,0 +1,38 @@
+package whatsapp
+
+import (
+	"nodepath-chat/internal/models"
+	"nodepath-chat/internal/services"
+)
+
+// SetLanguageService wires per-conversation language resolution into flow
+// execution so message and image nodes can serve their translations
+func (s *Service) SetLanguageService(languageService *services.LanguageService) {
+	s.languageService = languageService
+}
+
+// translateNodeContent swaps a message node's text for the translation
+// matching the prospect's detected or stored language. When the flow has
+// auto-translate enabled, languages without a human translation fall back to
+// a cached machine translation of the default text
+func (s *Service) translateNodeContent(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput, defaultContent string, translations map[string]string) string {
+	if s.languageService == nil {
+		return defaultContent
+	}
+	language := s.languageService.ResolveLanguage(execution.ProspectNum, execution.IDDevice, userInput)
+	if language == "" {
+		return defaultContent
+	}
+	return s.languageService.LocalizedMessage(flow.ID, node.ID, execution.ProspectNum, execution.IDDevice, language, defaultContent, translations)
+}
+
+// translateNodeMedia swaps an image node's URL for the localized creative
+// matching the prospect's language. Media is never machine translated, so
+// only the node's own translations apply
+func (s *Service) translateNodeMedia(execution *models.AIWhatsapp, userInput, defaultContent string, translations map[string]string) string {
+	if s.languageService == nil || len(translations) == 0 {
+		return defaultContent
+	}
+	language := s.languageService.ResolveLanguage(execution.ProspectNum, execution.IDDevice, userInput)
+	return s.languageService.Translate(defaultContent, translations, language)
+}
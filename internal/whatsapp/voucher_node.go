@@ -0,0 +1,72 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Voucher nodes issue a unique discount code to the prospect mid-flow. Codes
+// carry expiry and usage limits; redemption happens through the voucher
+// service, in-flow or from an external POS via the redeem endpoint.
+
+// processVoucherNode generates a code for the prospect and sends it
+func (s *Service) processVoucherNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	if s.voucherService == nil {
+		logrus.Warn("🎟️ VOUCHER: Voucher service not available, skipping node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	config := node.VoucherConfig()
+	voucher, err := s.voucherService.GenerateVoucher(
+		execution.IDDevice,
+		config.Campaign,
+		execution.ProspectNum,
+		config.Discount,
+		config.ExpiryDays,
+		config.MaxRedemptions,
+	)
+	if err != nil {
+		logrus.WithError(err).Error("🎟️ VOUCHER: Failed to generate code")
+		return "", err
+	}
+
+	message := config.Message
+	if message == "" {
+		message = fmt.Sprintf("🎟️ Here is your voucher code: *%s* (RM%.2f off)", voucher.Code, voucher.Discount)
+	} else if strings.Contains(message, "{{code}}") {
+		message = strings.ReplaceAll(message, "{{code}}", voucher.Code)
+	} else {
+		message = fmt.Sprintf("%s\n\nYour code: *%s*", message, voucher.Code)
+	}
+	if voucher.ExpiresAt != nil {
+		message += fmt.Sprintf("\nValid until %s", voucher.ExpiresAt.Format("2 Jan 2006"))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"code":         voucher.Code,
+		"campaign":     config.Campaign,
+		"prospect_num": execution.ProspectNum,
+	}).Info("✅ VOUCHER: Code issued, advancing flow")
+
+	// Advance like other sending nodes; the code message is this node's response
+	nextNode, nextErr := s.flowService.GetNextNode(flow, node.ID)
+	if nextErr != nil || nextNode == nil {
+		if err := s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice); err != nil {
+			logrus.WithError(err).Error("🎟️ VOUCHER: Failed to complete flow after issuing code")
+		}
+		return message, nil
+	}
+
+	s.updateCurrentNode(execution, nextNode.ID)
+	waiting := nextNode.Type == models.NodeTypeUserReply
+	if err := s.updateFlowTrackingFields(execution, nextNode.ID, flow.ID, waiting); err != nil {
+		logrus.WithError(err).Error("🎟️ VOUCHER: Failed to advance execution after issuing code")
+	}
+
+	return message, nil
+}
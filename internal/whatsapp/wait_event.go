@@ -0,0 +1,217 @@
+package whatsapp
+
+import (
+	"fmt"
+	"time"
+
+	"nodepath-chat/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Wait-event nodes suspend a flow execution until an external system signals it
+// (POST /api/executions/:id/signal) or a configured timeout elapses. This powers
+// patterns like "wait for payment confirmed" or "wait for human approval".
+
+// waitEventStatus marks an execution suspended on a wait_event node
+const waitEventStatus = "waiting_event"
+
+// processWaitEventNode suspends the execution on the current node until the
+// configured event is signalled or the timeout elapses
+func (s *Service) processWaitEventNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	config := node.WaitEventConfig()
+	if config.Event == "" {
+		logrus.WithField("node_id", node.ID).Warn("⏳ WAIT_EVENT: Node has no event name, skipping to next node")
+		return "", s.advancePastNode(flow, execution, node)
+	}
+
+	// Already suspended on this node - ignore user chatter while waiting
+	if execution.ExecutionStatus.Valid && execution.ExecutionStatus.String == waitEventStatus &&
+		execution.CurrentNodeID.Valid && execution.CurrentNodeID.String == node.ID {
+		logrus.WithFields(logrus.Fields{
+			"node_id": node.ID,
+			"event":   config.Event,
+		}).Info("⏳ WAIT_EVENT: Execution already waiting, ignoring input")
+		return "", nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"node_id":         node.ID,
+		"event":           config.Event,
+		"timeout_seconds": config.TimeoutSeconds,
+		"prospect_num":    execution.ProspectNum,
+		"id_device":       execution.IDDevice,
+	}).Info("⏳ WAIT_EVENT: Suspending execution until external signal")
+
+	s.updateCurrentNode(execution, node.ID)
+	err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, make(map[string]interface{}), waitEventStatus)
+	if err != nil {
+		logrus.WithError(err).Error("⏳ WAIT_EVENT: Failed to suspend execution")
+		return "", err
+	}
+
+	// Arm the timeout so the flow cannot hang forever when configured
+	if config.TimeoutSeconds > 0 {
+		prospectNum := execution.ProspectNum
+		idDevice := execution.IDDevice
+		nodeID := node.ID
+		go func() {
+			s.clock.Sleep(time.Duration(config.TimeoutSeconds) * time.Second)
+			s.resolveWaitEventTimeout(prospectNum, idDevice, nodeID)
+		}()
+	}
+
+	return "", nil
+}
+
+// resolveWaitEventTimeout advances the flow past a wait_event node whose timeout
+// elapsed without a signal. Does nothing when the execution was already signalled
+func (s *Service) resolveWaitEventTimeout(prospectNum, idDevice, nodeID string) {
+	execution, err := s.aiWhatsappService.GetFlowExecutionByProspectAndDevice(prospectNum, idDevice)
+	if err != nil || execution == nil {
+		logrus.WithError(err).Warn("⏳ WAIT_EVENT: Execution not found for timeout")
+		return
+	}
+
+	// Only act when the execution is still suspended on the same wait node
+	if !execution.ExecutionStatus.Valid || execution.ExecutionStatus.String != waitEventStatus ||
+		!execution.CurrentNodeID.Valid || execution.CurrentNodeID.String != nodeID {
+		logrus.WithFields(logrus.Fields{
+			"prospect_num": prospectNum,
+			"node_id":      nodeID,
+		}).Debug("⏳ WAIT_EVENT: Timeout fired but execution already moved on")
+		return
+	}
+
+	flow := s.flowForExecution(execution)
+	if flow == nil {
+		return
+	}
+
+	node, err := s.flowService.FindNodeByID(flow, nodeID)
+	if err != nil || node == nil {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"prospect_num": prospectNum,
+		"node_id":      nodeID,
+		"event":        node.WaitEventConfig().Event,
+	}).Info("⏳ WAIT_EVENT: Timeout elapsed without signal, continuing flow")
+
+	if s.lifecycleWebhooks != nil {
+		s.lifecycleWebhooks.EmitEvent(idDevice, models.ExecutionEventExpired, map[string]interface{}{
+			"prospect_num": prospectNum,
+			"node_id":      nodeID,
+			"event":        node.WaitEventConfig().Event,
+		})
+	}
+
+	if err := s.advancePastNode(flow, execution, node); err != nil {
+		logrus.WithError(err).Error("⏳ WAIT_EVENT: Failed to continue flow after timeout")
+	}
+}
+
+// SignalExecution resumes an execution suspended on a wait_event node. The event
+// name must match the node's configured event; the payload is currently only
+// logged for traceability. Returns false when nothing was waiting for this event
+func (s *Service) SignalExecution(executionID, eventName string, payload map[string]interface{}) (bool, error) {
+	execution, err := s.aiWhatsappService.GetFlowExecutionByExecutionID(executionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up execution: %w", err)
+	}
+	if execution == nil {
+		return false, fmt.Errorf("execution not found: %s", executionID)
+	}
+
+	if !execution.ExecutionStatus.Valid || execution.ExecutionStatus.String != waitEventStatus ||
+		!execution.CurrentNodeID.Valid || execution.CurrentNodeID.String == "" {
+		logrus.WithFields(logrus.Fields{
+			"execution_id": executionID,
+			"event":        eventName,
+		}).Info("⏳ WAIT_EVENT: Signal received but execution is not waiting")
+		return false, nil
+	}
+
+	flow := s.flowForExecution(execution)
+	if flow == nil {
+		return false, fmt.Errorf("flow not found for execution %s", executionID)
+	}
+
+	node, err := s.flowService.FindNodeByID(flow, execution.CurrentNodeID.String)
+	if err != nil || node == nil || node.Type != models.NodeTypeWaitEvent {
+		return false, fmt.Errorf("execution %s is not suspended on a wait_event node", executionID)
+	}
+
+	if node.WaitEventConfig().Event != eventName {
+		logrus.WithFields(logrus.Fields{
+			"execution_id": executionID,
+			"expected":     node.WaitEventConfig().Event,
+			"received":     eventName,
+		}).Info("⏳ WAIT_EVENT: Signal event name does not match, ignoring")
+		return false, nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"execution_id": executionID,
+		"event":        eventName,
+		"payload_keys": len(payload),
+	}).Info("✅ WAIT_EVENT: Signal matched, resuming flow")
+
+	return true, s.advancePastNode(flow, execution, node)
+}
+
+// advancePastNode moves the execution to the node after the wait_event node
+// and processes it, sending any resulting response
+func (s *Service) advancePastNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode) error {
+	nextNode, err := s.flowService.GetNextNode(flow, node.ID)
+	if err != nil || nextNode == nil {
+		logrus.WithField("node_id", node.ID).Info("⏳ WAIT_EVENT: No next node, completing flow")
+		return s.aiWhatsappService.CompleteFlowExecution(execution.ProspectNum, execution.IDDevice)
+	}
+
+	s.updateCurrentNode(execution, nextNode.ID)
+	execution.ExecutionStatus.String = "active"
+	execution.ExecutionStatus.Valid = true
+	err = s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, nextNode.ID, make(map[string]interface{}), "active")
+	if err != nil {
+		return fmt.Errorf("failed to reactivate execution: %w", err)
+	}
+
+	response, err := s.processFlowMessage(flow, execution, "")
+	if err != nil {
+		return fmt.Errorf("failed to process flow after wait event: %w", err)
+	}
+
+	if response == "" {
+		return nil
+	}
+
+	// Send the response, handling media the same way as delayed continuations
+	if s.mediaDetectionService.HasMedia(response) {
+		if mediaInfo := s.mediaDetectionService.ExtractFirstMedia(response); mediaInfo != nil {
+			return s.SendMediaMessage(execution.IDDevice, execution.ProspectNum, mediaInfo.MediaURL)
+		}
+	}
+	return s.SendMessageFromDevice(execution.IDDevice, execution.ProspectNum, response)
+}
+
+// flowForExecution loads the flow an execution is running
+func (s *Service) flowForExecution(execution *models.AIWhatsapp) *models.ChatbotFlow {
+	flowID := ""
+	if execution.FlowID.Valid && execution.FlowID.String != "" {
+		flowID = execution.FlowID.String
+	} else if execution.FlowReference.Valid && execution.FlowReference.String != "" {
+		flowID = execution.FlowReference.String
+	}
+	if flowID == "" {
+		return nil
+	}
+
+	flow, err := s.flowService.GetFlow(flowID)
+	if err != nil {
+		logrus.WithError(err).Error("⏳ WAIT_EVENT: Failed to load flow for execution")
+		return nil
+	}
+	return flow
+}
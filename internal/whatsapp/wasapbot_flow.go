@@ -886,7 +886,7 @@ func (s *Service) processWasapBotExamaFlow(phoneNumber, content, deviceID, sende
 					if err != nil {
 						logrus.WithError(err).Error("Failed to send message")
 					}
-					time.Sleep(500 * time.Millisecond) // Small delay between messages
+					s.clock.Sleep(500 * time.Millisecond) // Small delay between messages
 				}
 
 			case "image", "video", "audio":
@@ -896,7 +896,7 @@ func (s *Service) processWasapBotExamaFlow(phoneNumber, content, deviceID, sende
 					if err != nil {
 						logrus.WithError(err).Error("Failed to send media")
 					}
-					time.Sleep(1 * time.Second) // Small delay after media
+					s.clock.Sleep(1 * time.Second) // Small delay after media
 				}
 
 			case "user_reply", "user-reply", "input", "user-input", "question":
@@ -920,10 +920,10 @@ func (s *Service) processWasapBotExamaFlow(phoneNumber, content, deviceID, sende
 				if data, ok := getNodeByID(currentNode)["data"].(map[string]interface{}); ok {
 					if delaySeconds, ok := data["delaySeconds"].(float64); ok {
 						logrus.WithField("delay", delaySeconds).Info("🎯 WASAPBOT: Applying delay")
-						time.Sleep(time.Duration(delaySeconds) * time.Second)
+						s.clock.Sleep(time.Duration(delaySeconds) * time.Second)
 					} else if delay, ok := data["delay"].(float64); ok {
 						logrus.WithField("delay", delay).Info("🎯 WASAPBOT: Applying delay")
-						time.Sleep(time.Duration(delay) * time.Second)
+						s.clock.Sleep(time.Duration(delay) * time.Second)
 					}
 				}
 
@@ -1104,7 +1104,7 @@ func (s *Service) processWasapBotExamaFlow(phoneNumber, content, deviceID, sende
 						if err != nil {
 							logrus.WithError(err).Error("Failed to send message")
 						}
-						time.Sleep(500 * time.Millisecond) // Small delay between messages
+						s.clock.Sleep(500 * time.Millisecond) // Small delay between messages
 					}
 
 				case "image", "video", "audio":
@@ -1114,7 +1114,7 @@ func (s *Service) processWasapBotExamaFlow(phoneNumber, content, deviceID, sende
 						if err != nil {
 							logrus.WithError(err).Error("Failed to send media")
 						}
-						time.Sleep(1 * time.Second) // Delay after media
+						s.clock.Sleep(1 * time.Second) // Delay after media
 					}
 
 				case "user_reply", "user-reply", "input", "user-input", "question":
@@ -1164,10 +1164,10 @@ func (s *Service) processWasapBotExamaFlow(phoneNumber, content, deviceID, sende
 					if data, ok := getNodeByID(currentNode)["data"].(map[string]interface{}); ok {
 						if delaySeconds, ok := data["delaySeconds"].(float64); ok {
 							logrus.WithField("delay", delaySeconds).Info("🎯 WASAPBOT: Applying delay")
-							time.Sleep(time.Duration(delaySeconds) * time.Second)
+							s.clock.Sleep(time.Duration(delaySeconds) * time.Second)
 						} else if delay, ok := data["delay"].(float64); ok {
 							logrus.WithField("delay", delay).Info("🎯 WASAPBOT: Applying delay")
-							time.Sleep(time.Duration(delay) * time.Second)
+							s.clock.Sleep(time.Duration(delay) * time.Second)
 						}
 					}
 
@@ -0,0 +1,90 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Durable webhook queue consumer. When Redis is available incoming webhook
+// messages are journaled to a Redis stream by ProcessIncomingMessageFromWebhook
+// and pulled into the in-memory worker queue here, with acknowledgment after
+// processing - so messages survive restarts and deploys. Without Redis the
+// service keeps the original channel-only behavior
+
+// webhookStreamBatch is how many stream entries one read pulls at a time
+const webhookStreamBatch = 10
+
+// startWebhookStreamConsumer replays entries left pending by a previous
+// process and starts the goroutine that feeds the worker queue from the
+// stream. Returns false when the durable queue cannot be used
+func (s *Service) startWebhookStreamConsumer() bool {
+	if s.queueService == nil || !s.queueService.WebhookStreamAvailable() {
+		logrus.Info("📨 STREAM: Redis not available, webhook queue is in-memory only")
+		return false
+	}
+
+	if err := s.queueService.EnsureWebhookStreamGroup(); err != nil {
+		logrus.WithError(err).Error("📨 STREAM: Failed to create consumer group, webhook queue is in-memory only")
+		return false
+	}
+
+	hostname, _ := os.Hostname()
+	consumer := fmt.Sprintf("%s-%d", hostname, os.Getpid())
+
+	// Replay whatever was delivered but never acknowledged before the last
+	// shutdown - those messages were in flight when the process died
+	pending, err := s.queueService.ClaimPendingWebhookMessages(consumer)
+	if err != nil {
+		logrus.WithError(err).Warn("📨 STREAM: Failed to claim pending webhook messages")
+	}
+	for _, entry := range pending {
+		if msg, ok := s.decodeWebhookStreamEntry(entry.StreamID, entry.Payload); ok {
+			s.messageQueue <- msg
+		} else {
+			s.queueService.AckWebhookMessage(entry.StreamID)
+		}
+	}
+	if len(pending) > 0 {
+		logrus.WithField("count", len(pending)).Info("🔁 STREAM: Replayed webhook messages pending from previous run")
+	}
+
+	go s.webhookStreamPuller(consumer)
+
+	logrus.WithField("consumer", consumer).Info("📨 STREAM: Durable webhook queue active")
+	return true
+}
+
+// webhookStreamPuller moves stream entries into the worker queue. The channel
+// send blocks when workers are saturated, so backpressure stays in Redis
+// instead of dropping messages
+func (s *Service) webhookStreamPuller(consumer string) {
+	for {
+		entries, err := s.queueService.ReadWebhookMessages(consumer, webhookStreamBatch)
+		if err != nil {
+			logrus.WithError(err).Error("📨 STREAM: Failed to read webhook stream")
+			continue
+		}
+		for _, entry := range entries {
+			if msg, ok := s.decodeWebhookStreamEntry(entry.StreamID, entry.Payload); ok {
+				s.messageQueue <- msg
+			} else {
+				s.queueService.AckWebhookMessage(entry.StreamID)
+			}
+		}
+	}
+}
+
+// decodeWebhookStreamEntry parses a journaled payload back into a queue
+// message carrying its stream ID for acknowledgment after processing
+func (s *Service) decodeWebhookStreamEntry(streamID, payload string) (*WebhookMessage, bool) {
+	var msg WebhookMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		logrus.WithError(err).WithField("stream_id", streamID).Error("📨 STREAM: Dropping malformed webhook entry")
+		return nil, false
+	}
+	msg.StreamID = streamID
+	return &msg, true
+}
@@ -34,16 +34,60 @@ type Service struct {
 	flowService           *services.FlowService
 	aiService             *services.AIService
 	aiWhatsappService     services.AIWhatsappService
+	customNodeService     *services.CustomNodeService
+	scriptService         *services.ScriptService
 	websocketService      *services.WebSocketService
 	deviceSettingsService *services.DeviceSettingsService
 	providerService       *services.ProviderService
 	mediaDetectionService *services.MediaDetectionService
 	unifiedFlowService    *services.UnifiedFlowService
+	assignmentService     *services.AssignmentService
+	productService        *services.ProductService
+	cartService           *services.CartService
+	flowOrderService      *services.FlowOrderService
+	voucherService        *services.VoucherService
+	referralService       *services.ReferralService
+	qrCampaignService     *services.QRCampaignService
+	shortLinkService      *services.ShortLinkService
+	outboundPolicyService *services.OutboundPolicyService
+	lifecycleWebhooks     *services.LifecycleWebhookService
+	conversationEvents    *services.ConversationEventService
+	warmupService         *services.WarmupService
+	flowDebugService      *services.FlowDebugService
+	alertingService       *services.AlertingService
+	statusService         *services.StatusService
+	digestService         *services.DigestService
+	branchLocationService *services.BranchLocationService
+	leadScoringService    *services.LeadScoringService
+	approvalService       *services.ApprovalService
+	languageService       *services.LanguageService
+	imageGenService       *services.ImageGenService
+	deadLetterService     *services.DeadLetterService
+	documentService       *services.DocumentService
+	flowVersionService    *services.FlowVersionService
 	urlValidator          *utils.URLValidator
+	clock                 utils.Clock
 
-	// Message processing queue for performance
+	// Catalog pagination position per conversation (prospect|device)
+	catalogPages   map[string]int
+	catalogPagesMu sync.Mutex
+
+	// Form nodes awaiting the prospect's confirmation per conversation
+	// (prospect|device)
+	formConfirms   map[string]bool
+	formConfirmsMu sync.Mutex
+
+	// Message processing queue for performance, drained by a dynamically
+	// scaled worker pool (see worker_pool.go)
 	messageQueue chan *WebhookMessage
 	processingWG sync.WaitGroup
+	workerMu     sync.Mutex
+	workerStops  []chan struct{}
+	baseWorkers  int
+	maxWorkers   int
+	// webhookStreamEnabled is true when the Redis-backed durable webhook
+	// queue is active (see webhook_stream.go)
+	webhookStreamEnabled bool
 }
 
 // WebhookMessage represents an incoming message from webhook
@@ -55,10 +99,27 @@ type WebhookMessage struct {
 	SenderName  string
 	Timestamp   time.Time
 	Retries     int
+	// IdempotencyKey identifies this message across retries and stream
+	// redeliveries so each attempt is processed at most once (see dead_letter.go)
+	IdempotencyKey string
+	// StreamID is the Redis stream entry backing this message, set when the
+	// durable webhook queue is active (see webhook_stream.go)
+	StreamID string `json:"-"`
 }
 
 // NewService creates a new simplified WhatsApp service for webhook-based system
 func NewService(cfg *config.Config, queueService *services.QueueService, flowService *services.FlowService, aiService *services.AIService, aiWhatsappService services.AIWhatsappService, websocketService *services.WebSocketService, deviceSettingsService *services.DeviceSettingsService, providerService *services.ProviderService, mediaDetectionService *services.MediaDetectionService, unifiedFlowService *services.UnifiedFlowService) (*Service, error) {
+	// Worker pool sizing comes from config so operators can tune throughput;
+	// fall back to the historical defaults when unset
+	messageWorkers := cfg.MessageWorkers
+	if messageWorkers <= 0 {
+		messageWorkers = 10
+	}
+	messageQueueSize := cfg.MessageQueueSize
+	if messageQueueSize <= 0 {
+		messageQueueSize = 1000
+	}
+
 	service := &Service{
 		cfg:                   cfg,
 		queueService:          queueService,
@@ -71,13 +132,19 @@ func NewService(cfg *config.Config, queueService *services.QueueService, flowSer
 		mediaDetectionService: mediaDetectionService,
 		unifiedFlowService:    unifiedFlowService,
 		urlValidator:          utils.NewURLValidator(),
-		messageQueue:          make(chan *WebhookMessage, 1000), // Buffered queue for performance
+		clock:                 utils.NewSystemClock(),
+		catalogPages:          make(map[string]int),
+		formConfirms:          make(map[string]bool),
+		messageQueue:          make(chan *WebhookMessage, messageQueueSize), // Buffered queue for performance
 	}
 
-	// Start message processing workers for high performance
-	for i := 0; i < 10; i++ { // 10 worker goroutines for handling 3000+ devices
-		go service.messageProcessor()
-	}
+	// Start message processing workers for high performance; the pool scales
+	// itself with queue depth for handling 3000+ devices
+	service.startWorkerPool(messageWorkers)
+
+	// Switch the webhook queue to the durable Redis stream when available so
+	// in-flight messages survive restarts and deploys
+	service.webhookStreamEnabled = service.startWebhookStreamConsumer()
 
 	logrus.Info("🚀 WHATSAPP: Simplified webhook-based service initialized")
 	return service, nil
@@ -121,31 +188,50 @@ func (s *Service) convertWasapBotToAIWhatsapp(wasapBot *models.WasapBot) *models
 	return aiWhatsapp
 }
 
-// messageProcessor processes incoming webhook messages from the queue
-func (s *Service) messageProcessor() {
-	for msg := range s.messageQueue {
+// messageProcessor processes incoming webhook messages from the queue until
+// its stop channel closes (when the scaler retires the worker)
+func (s *Service) messageProcessor(stop chan struct{}) {
+	for {
+		var msg *WebhookMessage
+		select {
+		case <-stop:
+			return
+		case received, ok := <-s.messageQueue:
+			if !ok {
+				return
+			}
+			msg = received
+		}
+
 		s.processingWG.Add(1)
 		// Process messages sequentially to ensure session locking works properly
 		// This prevents duplicate processing when multiple messages arrive quickly
 		func(webhookMsg *WebhookMessage) {
 			defer s.processingWG.Done()
-			if err := s.processWebhookMessageInternal(webhookMsg); err != nil {
+
+			// Each (message, attempt) pair runs at most once; a redelivered
+			// duplicate is dropped here while scheduled retries still run
+			if !s.claimWebhookAttempt(webhookMsg) {
+				logrus.WithFields(logrus.Fields{
+					"device_id":    webhookMsg.DeviceID,
+					"phone_number": webhookMsg.PhoneNumber,
+					"attempt":      webhookMsg.Retries,
+				}).Warn("Duplicate webhook message attempt detected, skipping")
+			} else if err := s.processWebhookMessageInternal(webhookMsg); err != nil {
 				logrus.WithError(err).WithFields(logrus.Fields{
 					"device_id":    webhookMsg.DeviceID,
 					"phone_number": webhookMsg.PhoneNumber,
 					"retries":      webhookMsg.Retries,
 				}).Error("Failed to process webhook message")
 
-				// Disable retry queuing to prevent duplicate processing issues
-				// Messages that fail will be logged, but not retried
-				if webhookMsg.Retries < 3 {
-					logrus.WithFields(logrus.Fields{
-						"device_id":    webhookMsg.DeviceID,
-						"phone_number": webhookMsg.PhoneNumber,
-						"retry_count":  webhookMsg.Retries + 1,
-						"max_retries":  3,
-					}).Warn("Message processing failed, retry disabled to prevent duplicates")
-				}
+				// Retry with backoff or dead-letter once attempts run out
+				s.handleProcessingFailure(webhookMsg, err)
+			}
+
+			// Acknowledge the durable stream entry after the attempt;
+			// retries are scheduled in-process with their own attempt number
+			if webhookMsg.StreamID != "" && s.queueService != nil {
+				s.queueService.AckWebhookMessage(webhookMsg.StreamID)
 			}
 		}(msg)
 	}
@@ -153,6 +239,20 @@ func (s *Service) messageProcessor() {
 
 // processWebhookMessageInternal processes a single webhook message
 func (s *Service) processWebhookMessageInternal(msg *WebhookMessage) error {
+	// Attribute the conversation to a referrer when the message carries a
+	// referral code (e.g. the prospect opened a wa.me invite link)
+	if s.referralService != nil {
+		s.referralService.AttributeFromMessage(msg.DeviceID, msg.PhoneNumber, msg.Content)
+	}
+
+	// QR campaign entry: count the scan and rewrite the content to the base
+	// keyword so normal keyword matching starts the mapped flow
+	if s.qrCampaignService != nil {
+		if keyword, ok := s.qrCampaignService.TrackScan(msg.DeviceID, msg.Content); ok {
+			msg.Content = keyword
+		}
+	}
+
 	return s.processIncomingMessage(msg.PhoneNumber, msg.Content, msg.DeviceID, msg.SenderName)
 }
 
@@ -174,14 +274,29 @@ func (s *Service) ProcessIncomingMessageFromWebhook(phoneNumber, content, device
 	}).Info("📨 WEBHOOK: Processing incoming message")
 
 	// Add to processing queue for high performance
+	now := time.Now()
 	webhookMsg := &WebhookMessage{
-		PhoneNumber: phoneNumber,
-		Content:     content,
-		DeviceID:    deviceID,
-		Provider:    provider,
-		SenderName:  senderName,
-		Timestamp:   time.Now(),
-		Retries:     0,
+		PhoneNumber:    phoneNumber,
+		Content:        content,
+		DeviceID:       deviceID,
+		Provider:       provider,
+		SenderName:     senderName,
+		Timestamp:      now,
+		Retries:        0,
+		IdempotencyKey: webhookIdempotencyKey(deviceID, phoneNumber, content, now),
+	}
+
+	// Journal to the durable Redis stream when available so the message
+	// survives restarts; the stream consumer feeds the worker queue
+	if s.webhookStreamEnabled {
+		payload, err := json.Marshal(webhookMsg)
+		if err == nil {
+			if err := s.queueService.EnqueueWebhookMessage(string(payload)); err == nil {
+				return nil
+			} else {
+				logrus.WithError(err).Warn("📨 STREAM: Failed to journal webhook message, falling back to in-memory queue")
+			}
+		}
 	}
 
 	select {
@@ -217,12 +332,23 @@ func (s *Service) SendMessageFromDevice(deviceID, phoneNumber, message string) e
 		return fmt.Errorf("failed to get device settings for %s: %w", deviceID, err)
 	}
 
+	// Rewrite long URLs to tracked short links when the device opted in; the
+	// links are attributed to the receiving prospect so clicks can trigger
+	// their flow
+	if s.shortLinkService != nil {
+		message = s.shortLinkService.ShortenMessageURLs(deviceID, phoneNumber, message)
+	}
+
 	// Send message through provider service
 	err = s.providerService.SendMessage(deviceSettings, phoneNumber, message)
+	s.recordSendResult(deviceID, deviceSettings.Provider, err)
 	if err != nil {
 		return fmt.Errorf("failed to send message through provider: %w", err)
 	}
 
+	// Start the fast-reply window for lead scoring
+	s.markOutboundForScoring(deviceID, phoneNumber)
+
 	return nil
 }
 
@@ -271,6 +397,7 @@ func (s *Service) SendMediaMessage(deviceID, phoneNumber, mediaURL string) error
 
 	// Send media message through provider service
 	err = s.providerService.SendMediaMessage(deviceSettings, phoneNumber, mediaURL)
+	s.recordSendResult(deviceID, deviceSettings.Provider, err)
 	if err != nil {
 		return fmt.Errorf("failed to send media message through provider: %w", err)
 	}
@@ -280,6 +407,20 @@ func (s *Service) SendMediaMessage(deviceID, phoneNumber, mediaURL string) error
 
 // processIncomingMessage processes incoming messages and handles flow/AI logic using ai_whatsapp
 func (s *Service) processIncomingMessage(phoneNumber, content, deviceID, senderName string) error {
+	// Count the message for digest reporting
+	if s.digestService != nil {
+		s.digestService.CountInbound(deviceID)
+	}
+
+	// Apply each pending delay's on-reply policy; by default a reply cancels
+	// the scheduled continuation so it cannot fire out of context
+	if s.queueService != nil {
+		s.queueService.CancelPendingContinuations(phoneNumber, deviceID)
+	}
+
+	// Score the inbound message (keywords, reply latency) for lead scoring
+	s.scoreInboundMessage(deviceID, phoneNumber, content)
+
 	// Simple panic recovery to prevent crashes
 	defer func() {
 		if r := recover(); r != nil {
@@ -626,6 +767,9 @@ func (s *Service) processIncomingMessage(phoneNumber, content, deviceID, senderN
 						return fmt.Errorf("flow not found")
 					}
 
+					// Run on the version this conversation was pinned to at start
+					flow = s.resolveExecutionFlow(flow, phoneNumber, deviceID)
+
 					logrus.WithFields(logrus.Fields{
 						"execution_id": aiExecution.ExecutionID.String,
 						"current_node": aiExecution.CurrentNodeID.String,
@@ -726,6 +870,10 @@ func (s *Service) processNewFlowExecution(aiExecution *models.AIWhatsapp, conten
 		"device_id":  flow.IdDevice,
 	}).Info("✅ FLOW: Successfully retrieved flow data from chatbot_flows")
 
+	// Pin this new conversation to the newest published version and run on it
+	s.pinExecutionFlow(phoneNumber, deviceID, flow.ID)
+	flow = s.resolveExecutionFlow(flow, phoneNumber, deviceID)
+
 	// Save user message to conversation history (single save point for user input)
 	logrus.WithFields(logrus.Fields{
 		"execution_id": aiExecution.IDProspect,
@@ -797,7 +945,7 @@ func (s *Service) processNewFlowExecution(aiExecution *models.AIWhatsapp, conten
 				}).Info("📋 FLOW: Saving AI stage to database")
 
 				// Update the stage in ai_whatsapp
-				err = s.aiWhatsappService.UpdateStage(phoneNumber, deviceID, stage)
+				err = s.aiWhatsappService.UpdateStageWithSource(phoneNumber, deviceID, stage, "ai_response")
 				if err != nil {
 					logrus.WithError(err).WithField("stage", stage).Error("❌ FLOW: Failed to update stage")
 				}
@@ -861,7 +1009,7 @@ func (s *Service) processNewFlowExecution(aiExecution *models.AIWhatsapp, conten
 
 				// Add delay between messages
 				if i < len(messages)-1 && msg.Delay > 0 {
-					time.Sleep(msg.Delay)
+					s.clock.Sleep(msg.Delay)
 				}
 			}
 		}
@@ -1022,7 +1170,7 @@ func (s *Service) sendAIResponse(phoneNumber, deviceID string, response *service
 				isOnemessageActive = false
 
 				// Add delay
-				time.Sleep(time.Duration(delayMs) * time.Millisecond)
+				s.clock.Sleep(time.Duration(delayMs) * time.Millisecond)
 			}
 		} else {
 			// If we just finished onemessage sequence, send combined first
@@ -1045,7 +1193,7 @@ func (s *Service) sendAIResponse(phoneNumber, deviceID string, response *service
 				isOnemessageActive = false
 
 				// Add delay
-				time.Sleep(time.Duration(delayMs) * time.Millisecond)
+				s.clock.Sleep(time.Duration(delayMs) * time.Millisecond)
 			}
 
 			// Now handle normal text or media
@@ -1065,7 +1213,7 @@ func (s *Service) sendAIResponse(phoneNumber, deviceID string, response *service
 				}
 
 				// Add delay
-				time.Sleep(time.Duration(delayMs) * time.Millisecond)
+				s.clock.Sleep(time.Duration(delayMs) * time.Millisecond)
 
 			case "image":
 				// Send image message - part.Content contains the image URL
@@ -1083,7 +1231,7 @@ func (s *Service) sendAIResponse(phoneNumber, deviceID string, response *service
 				}
 
 				// Add delay
-				time.Sleep(time.Duration(delayMs) * time.Millisecond)
+				s.clock.Sleep(time.Duration(delayMs) * time.Millisecond)
 
 			case "audio":
 				// Send audio message - part.Content contains the audio URL
@@ -1100,7 +1248,7 @@ func (s *Service) sendAIResponse(phoneNumber, deviceID string, response *service
 				}
 
 				// Add delay
-				time.Sleep(time.Duration(delayMs) * time.Millisecond)
+				s.clock.Sleep(time.Duration(delayMs) * time.Millisecond)
 
 			case "video":
 				// Send video message - part.Content contains the video URL
@@ -1117,7 +1265,7 @@ func (s *Service) sendAIResponse(phoneNumber, deviceID string, response *service
 				}
 
 				// Add delay
-				time.Sleep(time.Duration(delayMs) * time.Millisecond)
+				s.clock.Sleep(time.Duration(delayMs) * time.Millisecond)
 			}
 		}
 	}
@@ -1193,6 +1341,12 @@ func (s *Service) logConversationMessage(phoneNumber, deviceID, messageType, con
 			"phone_number": phoneNumber,
 			"message_type": messageType,
 		}).Info("✅ CONVERSATION: Successfully logged message to database")
+
+		// Dual-write to the structured messages table; conv_last above stays
+		// authoritative for legacy readers
+		if err := s.aiWhatsappService.GetRepository().InsertMessageRow(phoneNumber, deviceID, "outbound", "text", content, existingConv.Stage.String); err != nil {
+			logrus.WithError(err).Warn("Failed to insert structured message row for bot message")
+		}
 	} else {
 		logrus.WithFields(logrus.Fields{
 			"device_id":    deviceID,
@@ -1222,12 +1376,26 @@ func (s *Service) processFlowMessage(flow *models.ChatbotFlow, aiExecution *mode
 		s.updateCurrentNode(aiExecution, currentNode.ID)
 	}
 
+	// Stream the decision to the editor when this conversation is being debugged
+	s.debugNodeEntered(aiExecution, currentNode, userInput)
+
+	// Time this node under the webhook's trace
+	nodeSpan := services.Tracing().StartChild("flow.node",
+		services.TraceKey(aiExecution.IDDevice, aiExecution.ProspectNum),
+		services.DeviceTraceKey(aiExecution.IDDevice))
+	nodeSpan.SetAttribute("node_id", currentNode.ID)
+	nodeSpan.SetAttribute("node_type", string(currentNode.Type))
+	defer nodeSpan.End()
+
 	// Process based on node type
 	switch currentNode.Type {
 	case models.NodeTypeStart:
 		return s.processStartNode(flow, aiExecution, currentNode, userInput)
 	case models.NodeTypeAIPrompt, models.NodeTypeAdvancedAIPrompt, "prompt": // Handle all AI prompt types with one function
-		return s.processAIPromptNode(flow, aiExecution, currentNode, userInput)
+		// AI calls go out over the network, so they run under the watchdog
+		return s.runNodeWithTimeout(flow, aiExecution, currentNode, userInput, func() (string, error) {
+			return s.processAIPromptNode(flow, aiExecution, currentNode, userInput)
+		})
 
 	case models.NodeTypeMessage:
 		return s.processMessageNode(flow, aiExecution, currentNode, userInput)
@@ -1249,10 +1417,70 @@ func (s *Service) processFlowMessage(flow *models.ChatbotFlow, aiExecution *mode
 		// Process waiting_reply_times similar to user_reply
 		return s.processUserReplyNode(flow, aiExecution, currentNode, userInput)
 	case models.NodeTypeManual:
-		// Manual nodes require human intervention - treat as default
-		return s.processDefaultNode(flow, aiExecution, currentNode, userInput)
+		// Manual nodes hand the conversation off to a human agent
+		return s.processManualNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeWaitEvent:
+		// Wait-event nodes suspend the flow until an external signal or timeout
+		return s.processWaitEventNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeParallel:
+		// Parallel nodes fork into all outgoing branches at once
+		return s.processParallelNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeJoin:
+		// Join nodes merge parallel branches back into a single path
+		return s.processJoinNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeLoop:
+		// Loop nodes repeat their body path a bounded number of times
+		return s.processLoopNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeCatalog:
+		// Catalog nodes send the product list and wait for a selection
+		return s.processCatalogNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeOrderSummary:
+		// Order summary nodes capture the cart as a pending order
+		return s.processOrderSummaryNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeVoucher:
+		// Voucher nodes issue a unique discount code to the prospect
+		return s.processVoucherNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeNearestBranch:
+		// Nearest-branch nodes resolve a shared location to the closest outlet
+		return s.processNearestBranchNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeIntentRouter:
+		// Intent-router nodes classify the message and branch on the intent
+		return s.processIntentRouterNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeEntityExtract:
+		// Entity-extract nodes capture structured values into flow variables
+		return s.processEntityExtractNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeForm:
+		// Form nodes collect several slots across turns, then confirm
+		return s.processFormNode(flow, aiExecution, currentNode, userInput)
+	case models.NodeTypeScript:
+		// Script nodes transform variables with a sandboxed user program
+		return s.processScriptNode(flow, aiExecution, currentNode, userInput)
+
+	case models.NodeTypeImageGen:
+		// Rendering may call the AI image provider, so it runs under the watchdog
+		return s.runNodeWithTimeout(flow, aiExecution, currentNode, userInput, func() (string, error) {
+			return s.processImageGenNode(flow, aiExecution, currentNode, userInput)
+		})
+
+	case models.NodeTypeDocument:
+		// Document nodes fill a PDF template with execution variables
+		return s.processDocumentNode(flow, aiExecution, currentNode, userInput)
+
+	case models.NodeTypeReceiptVerify:
+		// Receipt OCR goes through the vision model, so it runs under the watchdog
+		return s.runNodeWithTimeout(flow, aiExecution, currentNode, userInput, func() (string, error) {
+			return s.processReceiptVerifyNode(flow, aiExecution, currentNode, userInput)
+		})
 
 	default:
+		// Partner-registered types carry the custom_ prefix and run through
+		// their external webhook endpoint
+		if strings.HasPrefix(string(currentNode.Type), models.CustomNodeTypePrefix) {
+			// Partner endpoints are outbound calls too, so they share the watchdog
+			return s.runNodeWithTimeout(flow, aiExecution, currentNode, userInput, func() (string, error) {
+				return s.processCustomNode(flow, aiExecution, currentNode, userInput)
+			})
+		}
 		return s.processDefaultNode(flow, aiExecution, currentNode, userInput)
 	}
 }
@@ -1295,6 +1523,14 @@ func (s *Service) processAIPromptNode(flow *models.ChatbotFlow, execution *model
 		apiProvider = ap
 	}
 
+	// Vision option: pass incoming image URLs to a vision-capable model
+	visionEnabled := false
+	if v, ok := node.Data["vision"].(bool); ok {
+		visionEnabled = v
+	} else if v, ok := node.Data["enableVision"].(bool); ok {
+		visionEnabled = v
+	}
+
 	// Log extracted values
 	logrus.WithFields(logrus.Fields{
 		"system_prompt_extracted": systemPrompt,
@@ -1329,6 +1565,13 @@ func (s *Service) processAIPromptNode(flow *models.ChatbotFlow, execution *model
 		"api_provider":         apiProvider,
 	}).Info("🤖 AI_PROMPT: Configuration loaded")
 
+	s.debugEvent(execution, "ai_prompt", map[string]interface{}{
+		"node_id":       node.ID,
+		"system_prompt": systemPrompt,
+		"user_input":    userInput,
+		"api_provider":  apiProvider,
+	})
+
 	// Check if we have complete AI configuration
 	if systemPrompt == "" {
 		logrus.Error("🤖 AI_PROMPT: No system prompt configured")
@@ -1424,6 +1667,10 @@ func (s *Service) processAIPromptNode(flow *models.ChatbotFlow, execution *model
 	if execution.ConvLast.Valid && execution.ConvLast.String != "" {
 		convLastStr = execution.ConvLast.String
 
+		// Apply the flow's context window settings so only the configured
+		// amount of history reaches the AI
+		convLastStr = services.BuildContextWindow(convLastStr, s.flowService.FlowContextConfig(flow))
+
 		// Log processed conversation
 		logrus.WithFields(logrus.Fields{
 			"conv_last_processed": convLastStr,
@@ -1447,13 +1694,44 @@ func (s *Service) processAIPromptNode(flow *models.ChatbotFlow, execution *model
 		"api_key_present":      actualAPIKey != "",
 	}).Info("🔍 AI Request Payload")
 
+	// When vision is enabled, attach image URLs from the incoming message so
+	// the model sees the photo the customer is asking about
+	var imageURLs []string
+	if visionEnabled {
+		for _, media := range s.mediaDetectionService.DetectMedia(userInput) {
+			if media.IsMedia && media.MediaType == "image" {
+				imageURLs = append(imageURLs, media.MediaURL)
+			}
+		}
+		if len(imageURLs) > 0 {
+			logrus.WithFields(logrus.Fields{
+				"node_id": node.ID,
+				"images":  len(imageURLs),
+			}).Info("🤖 AI_PROMPT: Passing incoming images to vision model")
+		}
+	}
+
+	// Per-node generation overrides; zero values fall back to the
+	// device-level defaults
+	var genParams *services.AIGenerationParams
+	if cfg := node.AIPromptConfig(); cfg.Model != "" || cfg.Temperature > 0 || cfg.TopP > 0 || cfg.MaxTokens > 0 {
+		genParams = &services.AIGenerationParams{
+			Model:       cfg.Model,
+			Temperature: cfg.Temperature,
+			TopP:        cfg.TopP,
+			MaxTokens:   cfg.MaxTokens,
+		}
+	}
+
 	// Call AI service with configuration
-	response, err := s.aiService.GenerateResponse(
+	response, err := s.aiService.GenerateResponseWithParams(
 		systemPrompt,
 		userInput,
 		actualAPIKey,
 		execution.IDDevice,
 		conversationHistory,
+		genParams,
+		imageURLs...,
 	)
 	if err != nil {
 		logrus.WithError(err).Error("🤖 AI_PROMPT: Failed to generate AI response")
@@ -1524,8 +1802,12 @@ func (s *Service) processAIPromptNode(flow *models.ChatbotFlow, execution *model
 				}
 			}
 
-			// Send individual messages from parsed response
-			if len(parsedResponse.Response) > 0 {
+			// Review-before-send mode: hold the messages for an agent instead
+			// of sending them directly
+			if len(parsedResponse.Response) > 0 && s.reviewEnabled(flow.ID) {
+				s.enqueueForApproval(flow, execution, node, parsedResponse)
+				messagesSentIndividually = true
+			} else if len(parsedResponse.Response) > 0 {
 				// Skip saving user input here - it's already saved in processIncomingMessage
 				// This prevents duplicate USER entries in conv_last
 				logrus.WithFields(logrus.Fields{
@@ -1536,7 +1818,7 @@ func (s *Service) processAIPromptNode(flow *models.ChatbotFlow, execution *model
 				// Now send and save each bot message
 				for i, item := range parsedResponse.Response {
 					if i > 0 {
-						time.Sleep(2 * time.Second) // Add delay between messages
+						s.clock.Sleep(2 * time.Second) // Add delay between messages
 					}
 
 					switch item.Type {
@@ -1914,11 +2196,10 @@ func (s *Service) processAIPromptNode(flow *models.ChatbotFlow, execution *model
 
 // processMessageNode processes a simple message node
 func (s *Service) processMessageNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
-	// Get message from node data
-	message := ""
-	if msg, ok := node.Data["message"].(string); ok {
-		message = msg
-	}
+	// Get message from node data, in the prospect's language when the node
+	// carries translations
+	config := node.MessageConfig()
+	message := s.translateNodeContent(flow, execution, node, userInput, config.Message, config.Translations)
 
 	// Replace variables in message
 	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
@@ -1926,7 +2207,21 @@ func (s *Service) processMessageNode(flow *models.ChatbotFlow, execution *models
 		logrus.WithError(err).Warn("Failed to get execution variables")
 		variables = make(map[string]interface{})
 	}
+	s.addReferralVariables(execution, message, variables)
 	message = s.flowService.ReplaceVariables(message, variables)
+	message = utils.ExpandSpintaxForRecipient(message, execution.ProspectNum)
+
+	// Quick-reply suggestions travel with the message itself (as provider
+	// buttons where supported), so the node sends directly instead of
+	// returning text for the caller to deliver
+	if len(config.QuickReplies) > 0 {
+		if err := s.SendQuickReplyFromDevice(execution.IDDevice, execution.ProspectNum, message, config.QuickReplies); err != nil {
+			logrus.WithError(err).Error("📝 MESSAGE: Failed to send quick-reply message")
+		} else if err := s.aiWhatsappService.SaveConversationHistory(execution.ProspectNum, execution.IDDevice, "", services.FormatQuickReplyList(message, config.QuickReplies), "", execution.ProspectName.String); err != nil {
+			logrus.WithError(err).Error("📝 MESSAGE: Failed to save quick-reply message to conversation")
+		}
+		message = ""
+	}
 
 	// Check if next node exists and advance to it
 	nextNode, err := s.flowService.GetNextNode(flow, node.ID)
@@ -2006,6 +2301,9 @@ func (s *Service) processMessageNode(flow *models.ChatbotFlow, execution *models
 
 		// Combine responses if next node generated content
 		if nextResponse != "" {
+			if message == "" {
+				return nextResponse, nil
+			}
 			return message + "\n" + nextResponse, nil
 		}
 	} else {
@@ -2022,13 +2320,10 @@ func (s *Service) processMessageNode(flow *models.ChatbotFlow, execution *models
 
 // processImageNode processes an image node
 func (s *Service) processImageNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
-	// Get image URL from node data
-	imageURL := ""
-	if url, ok := node.Data["imageUrl"].(string); ok {
-		imageURL = url
-	} else if url, ok := node.Data["image"].(string); ok {
-		imageURL = url
-	}
+	// Get image URL from node data, preferring the localized creative when
+	// the node carries translations
+	imageConfig := node.ImageConfig()
+	imageURL := s.translateNodeMedia(execution, userInput, imageConfig.ImageURL, imageConfig.Translations)
 
 	// Console log for tracing image URL extraction
 	logrus.WithFields(logrus.Fields{
@@ -2164,14 +2459,7 @@ func (s *Service) processImageNode(flow *models.ChatbotFlow, execution *models.A
 // processAudioNode processes an audio node
 func (s *Service) processAudioNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
 	// Get audio URL from node data
-	audioURL := ""
-	if url, ok := node.Data["audioUrl"].(string); ok {
-		audioURL = url
-	} else if url, ok := node.Data["audio"].(string); ok {
-		audioURL = url
-	} else if url, ok := node.Data["mediaUrl"].(string); ok {
-		audioURL = url
-	}
+	audioURL := node.AudioConfig().AudioURL
 
 	// Console log for tracing audio URL extraction
 	logrus.WithFields(logrus.Fields{
@@ -2284,14 +2572,7 @@ func (s *Service) processAudioNode(flow *models.ChatbotFlow, execution *models.A
 // processVideoNode processes a video node
 func (s *Service) processVideoNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
 	// Get video URL from node data
-	videoURL := ""
-	if url, ok := node.Data["videoUrl"].(string); ok {
-		videoURL = url
-	} else if url, ok := node.Data["video"].(string); ok {
-		videoURL = url
-	} else if url, ok := node.Data["mediaUrl"].(string); ok {
-		videoURL = url
-	}
+	videoURL := node.VideoConfig().VideoURL
 
 	// Console log for tracing video URL extraction
 	logrus.WithFields(logrus.Fields{
@@ -2410,11 +2691,10 @@ func (s *Service) processDelayNode(flow *models.ChatbotFlow, execution *models.A
 	}).Info("🕐 DELAY: Processing delay node")
 
 	// Get delay time from node data (default to 5 seconds if not specified)
+	delayConfig := node.DelayConfig()
 	delaySeconds := 5
-	if delay, ok := node.Data["delay"].(float64); ok {
-		delaySeconds = int(delay)
-	} else if delay, ok := node.Data["delaySeconds"].(float64); ok {
-		delaySeconds = int(delay)
+	if delayConfig.Seconds > 0 {
+		delaySeconds = int(delayConfig.Seconds)
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -2456,6 +2736,7 @@ func (s *Service) processDelayNode(flow *models.ChatbotFlow, execution *models.A
 		NodeID:      nextNode.ID, // This is the node to process AFTER the delay
 		Delay:       time.Duration(delaySeconds) * time.Second,
 		CreatedAt:   time.Now(),
+		OnReply:     delayConfig.OnReply, // What a mid-delay user reply does to this continuation
 	}
 
 	// Queue the delayed message
@@ -2479,8 +2760,26 @@ func (s *Service) processDelayNode(flow *models.ChatbotFlow, execution *models.A
 
 // processConditionNode processes a condition node
 func (s *Service) processConditionNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
-	// Evaluate condition based on user input and move to appropriate next node
-	nextNode, err := s.flowService.EvaluateConditionNode(flow, node.ID, userInput)
+	// Expression-based condition nodes evaluate their boolean branches
+	// against the full execution state; nodes without expressions fall back
+	// to the legacy keyword/number matching below
+	nextNode, handled, err := s.flowService.EvaluateConditionNodeExpressions(flow, node.ID, s.conditionContext(execution, userInput))
+	if !handled {
+		// Evaluate condition based on user input and move to appropriate next node
+		nextNode, err = s.flowService.EvaluateConditionNode(flow, node.ID, userInput)
+	}
+
+	matchedNodeID := ""
+	if nextNode != nil {
+		matchedNodeID = nextNode.ID
+	}
+	s.debugEvent(execution, "condition_result", map[string]interface{}{
+		"node_id":    node.ID,
+		"user_input": userInput,
+		"matched":    nextNode != nil,
+		"next_node":  matchedNodeID,
+	})
+
 	if err == nil && nextNode != nil {
 		if nextNode.Type == models.NodeTypeDelay {
 			// Advance to delay node and process it immediately
@@ -2524,6 +2823,38 @@ func (s *Service) processConditionNode(flow *models.ChatbotFlow, execution *mode
 	return "", nil
 }
 
+// conditionContext assembles the execution state condition expressions
+// evaluate against: the pending input, the current stage, how many messages
+// the conversation holds and the flow variables
+func (s *Service) conditionContext(execution *models.AIWhatsapp, userInput string) *services.ConditionContext {
+	variables, err := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get execution variables")
+		variables = make(map[string]interface{})
+	}
+
+	messageCount := 0
+	if execution.ConvLast.Valid {
+		for _, line := range strings.Split(execution.ConvLast.String, "\n") {
+			if strings.TrimSpace(line) != "" {
+				messageCount++
+			}
+		}
+	}
+
+	stage := ""
+	if execution.Stage.Valid {
+		stage = execution.Stage.String
+	}
+
+	return &services.ConditionContext{
+		Input:        userInput,
+		Stage:        stage,
+		MessageCount: messageCount,
+		Variables:    variables,
+	}
+}
+
 // processStageNode processes a stage node
 func (s *Service) processStageNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
 	// For now, just log the stage transition
@@ -2587,6 +2918,11 @@ func (s *Service) handleUserReplyResume(execution *models.AIWhatsapp, userInput
 		return err
 	}
 
+	if flow != nil {
+		// Run on the version this conversation was pinned to at start
+		flow = s.resolveExecutionFlow(flow, execution.ProspectNum, execution.IDDevice)
+	}
+
 	if flow == nil {
 		logrus.WithField("flow_id", execution.FlowID.String).Error("❌ USER_REPLY: Flow not found for resume")
 		return fmt.Errorf("flow not found for resume")
@@ -2605,11 +2941,79 @@ func (s *Service) handleUserReplyResume(execution *models.AIWhatsapp, userInput
 		return err
 	}
 
-	// Get the next node after the user_reply node
-	nextNode, err := s.flowService.GetNextNode(flow, execution.CurrentNodeID.String)
-	if err != nil {
-		logrus.WithError(err).Error("❌ USER_REPLY: Failed to get next node after user reply")
-		return err
+	// Catalog nodes interpret the reply themselves (pagination, numbered picks)
+	// before the flow routes on it
+	if currentNode, nodeErr := s.flowService.FindNodeByID(flow, execution.CurrentNodeID.String); nodeErr == nil && currentNode != nil && currentNode.Type == models.NodeTypeCatalog {
+		translated, handled, catalogErr := s.handleCatalogReply(flow, execution, currentNode, userInput)
+		if catalogErr != nil {
+			return catalogErr
+		}
+		if handled {
+			// Reply consumed (next page sent or invalid pick) - stay parked
+			return nil
+		}
+		userInput = translated
+	}
+
+	// Nearest-branch nodes resolve the shared location before routing
+	if currentNode, nodeErr := s.flowService.FindNodeByID(flow, execution.CurrentNodeID.String); nodeErr == nil && currentNode != nil && currentNode.Type == models.NodeTypeNearestBranch {
+		translated, handled, branchErr := s.handleNearestBranchReply(flow, execution, currentNode, userInput)
+		if branchErr != nil {
+			return branchErr
+		}
+		if handled {
+			// No usable coordinates - prospect was re-prompted, stay parked
+			return nil
+		}
+		userInput = translated
+	}
+
+	// Form nodes consume replies themselves until every slot is filled and
+	// confirmed; only a confirmed form routes past the node
+	if currentNode, nodeErr := s.flowService.FindNodeByID(flow, execution.CurrentNodeID.String); nodeErr == nil && currentNode != nil && currentNode.Type == models.NodeTypeForm {
+		handled, formErr := s.handleFormReply(flow, execution, currentNode, userInput)
+		if formErr != nil {
+			return formErr
+		}
+		if handled {
+			// Form still collecting or confirming - stay parked
+			return nil
+		}
+	}
+
+	// Quick-reply suggestions: map the reply back onto the option text
+	// (case-insensitively, or by list number) before edges route on it
+	userInput = s.resolveQuickReply(flow, execution.CurrentNodeID.String, userInput)
+
+	// Entity-extract nodes capture structured values from the reply before
+	// the flow routes on it
+	if currentNode, nodeErr := s.flowService.FindNodeByID(flow, execution.CurrentNodeID.String); nodeErr == nil && currentNode != nil && currentNode.Type == models.NodeTypeEntityExtract {
+		s.captureEntities(execution, currentNode, userInput)
+	}
+
+	// Intent-router nodes pick their outgoing edge themselves: the reply is
+	// classified against the node's labeled intents
+	var nextNode *models.FlowNode
+	if currentNode, nodeErr := s.flowService.FindNodeByID(flow, execution.CurrentNodeID.String); nodeErr == nil && currentNode != nil && currentNode.Type == models.NodeTypeIntentRouter {
+		nextNode, err = s.routeIntentReply(flow, execution, currentNode, userInput)
+		if err != nil {
+			logrus.WithError(err).Error("❌ USER_REPLY: Failed to route intent reply")
+			return err
+		}
+	}
+
+	// Get the next node after the user_reply node, letting conditioned edges
+	// route on what the user just typed
+	if nextNode == nil {
+		variables, varErr := s.aiWhatsappService.GetFlowExecutionVariables(execution.ProspectNum, execution.IDDevice)
+		if varErr != nil {
+			variables = make(map[string]interface{})
+		}
+		nextNode, err = s.flowService.GetNextNodeWithContext(flow, execution.CurrentNodeID.String, userInput, variables)
+		if err != nil {
+			logrus.WithError(err).Error("❌ USER_REPLY: Failed to get next node after user reply")
+			return err
+		}
 	}
 
 	if nextNode == nil {
@@ -2682,7 +3086,7 @@ func (s *Service) handleUserReplyResume(execution *models.AIWhatsapp, userInput
 				}).Info("📋 USER_REPLY: Saving AI stage to database")
 
 				// Update the stage in ai_whatsapp
-				err = s.aiWhatsappService.UpdateStage(execution.ProspectNum, execution.IDDevice, stage)
+				err = s.aiWhatsappService.UpdateStageWithSource(execution.ProspectNum, execution.IDDevice, stage, "ai_response")
 				if err != nil {
 					logrus.WithError(err).WithField("stage", stage).Error("❌ USER_REPLY: Failed to update stage")
 				}
@@ -2746,7 +3150,7 @@ func (s *Service) handleUserReplyResume(execution *models.AIWhatsapp, userInput
 
 				// Add delay between messages
 				if i < len(messages)-1 && msg.Delay > 0 {
-					time.Sleep(msg.Delay)
+					s.clock.Sleep(msg.Delay)
 				}
 			}
 		}
@@ -2800,6 +3204,24 @@ func (s *Service) updateFlowTrackingFields(execution *models.AIWhatsapp, current
 		return fmt.Errorf("failed to update flow tracking fields: %w", err)
 	}
 
+	// Record the node transition in the conversation event log
+	if s.conversationEvents != nil && currentNodeID != lastNodeID {
+		s.conversationEvents.Record(execution.ProspectNum, execution.IDDevice, models.ConversationEventNodeTransition, map[string]interface{}{
+			"from_node": lastNodeID,
+			"to_node":   currentNodeID,
+			"flow_id":   flowID,
+		})
+	}
+
+	// Mirror the waiting state to external systems
+	if waitingForReply && s.lifecycleWebhooks != nil {
+		s.lifecycleWebhooks.EmitEvent(execution.IDDevice, models.ExecutionEventWaitingReply, map[string]interface{}{
+			"prospect_num": execution.ProspectNum,
+			"execution_id": executionID,
+			"node_id":      currentNodeID,
+		})
+	}
+
 	// Update the execution model in memory for consistency
 	execution.CurrentNodeID.String = currentNodeID
 	execution.CurrentNodeID.Valid = true
@@ -2838,6 +3260,10 @@ func (s *Service) processUserReplyNode(flow *models.ChatbotFlow, execution *mode
 			"user_input":  userInput,
 		}).Info("💬 USER_REPLY: User input received, advancing to next node")
 
+		// Map the reply onto the preceding message node's quick-reply options
+		// before downstream conditions evaluate it
+		userInput = s.resolveQuickReply(flow, node.ID, userInput)
+
 		// Get the next node after user_reply
 		nextNode, err := s.flowService.GetNextNode(flow, node.ID)
 		if err != nil || nextNode == nil {
@@ -3003,6 +3429,147 @@ func (s *Service) processDefaultNode(flow *models.ChatbotFlow, execution *models
 	return "", nil
 }
 
+// SetAssignmentService sets the assignment service used for skill-based handoff routing
+func (s *Service) SetAssignmentService(assignmentService *services.AssignmentService) {
+	s.assignmentService = assignmentService
+}
+
+// SetProductService wires the product catalog in so catalog nodes can send it
+func (s *Service) SetProductService(productService *services.ProductService) {
+	s.productService = productService
+}
+
+// SetCartService wires the shopping cart in so catalog replies can fill it
+func (s *Service) SetCartService(cartService *services.CartService) {
+	s.cartService = cartService
+}
+
+// SetFlowOrderService wires order capture in for order summary nodes
+func (s *Service) SetFlowOrderService(flowOrderService *services.FlowOrderService) {
+	s.flowOrderService = flowOrderService
+}
+
+// SetVoucherService wires voucher generation in for voucher nodes
+func (s *Service) SetVoucherService(voucherService *services.VoucherService) {
+	s.voucherService = voucherService
+}
+
+// SetReferralService wires referral tracking into inbound message processing
+func (s *Service) SetReferralService(referralService *services.ReferralService) {
+	s.referralService = referralService
+}
+
+// SetBranchLocationService wires branch lookup in for nearest_branch nodes
+func (s *Service) SetBranchLocationService(branchLocationService *services.BranchLocationService) {
+	s.branchLocationService = branchLocationService
+}
+
+// SetQRCampaignService wires QR scan tracking into inbound message processing
+func (s *Service) SetQRCampaignService(qrCampaignService *services.QRCampaignService) {
+	s.qrCampaignService = qrCampaignService
+}
+
+// SetShortLinkService wires URL shortening into outbound message sending
+func (s *Service) SetShortLinkService(shortLinkService *services.ShortLinkService) {
+	s.shortLinkService = shortLinkService
+}
+
+// SetOutboundPolicyService wires opt-out and quiet-hour checks into proactive
+// flow starts
+func (s *Service) SetOutboundPolicyService(outboundPolicyService *services.OutboundPolicyService) {
+	s.outboundPolicyService = outboundPolicyService
+}
+
+// SetCredentialHealthService wires credential validation into the provider
+// send path so flow sends fail fast when a device's token has expired
+func (s *Service) SetCredentialHealthService(credentialHealthService *services.CredentialHealthService) {
+	s.providerService.SetCredentialHealthService(credentialHealthService)
+}
+
+// SetTurnLatencyService wires the per-turn latency tracker into the provider
+// send path so the first outbound send closes the prospect's open turn
+func (s *Service) SetTurnLatencyService(turnLatencyService *services.TurnLatencyService) {
+	s.providerService.SetTurnLatencyService(turnLatencyService)
+}
+
+// SetLifecycleWebhookService wires execution lifecycle webhook emission into
+// flow processing
+func (s *Service) SetLifecycleWebhookService(lifecycleWebhooks *services.LifecycleWebhookService) {
+	s.lifecycleWebhooks = lifecycleWebhooks
+}
+
+// SetConversationEventService wires node transitions into the append-only
+// conversation event log
+func (s *Service) SetConversationEventService(conversationEvents *services.ConversationEventService) {
+	s.conversationEvents = conversationEvents
+}
+
+// SetWarmupService wires daily send caps into proactive sends
+func (s *Service) SetWarmupService(warmupService *services.WarmupService) {
+	s.warmupService = warmupService
+}
+
+// reserveProactiveSend checks the device's warm-up cap before a proactive
+// send and paces the send so traffic spreads across the day. The returned
+// blockReason is non-empty when the daily cap is exhausted
+func (s *Service) reserveProactiveSend(idDevice string) (string, error) {
+	if s.warmupService == nil {
+		return "", nil
+	}
+
+	allowed, delay, err := s.warmupService.ReserveSend(idDevice)
+	if err != nil {
+		return "", fmt.Errorf("failed to check warm-up cap: %w", err)
+	}
+	if !allowed {
+		return "daily send cap reached for this device", nil
+	}
+	if delay > 0 {
+		s.clock.Sleep(delay)
+	}
+	return "", nil
+}
+
+// SetClock replaces the clock used for message pacing delays (tests inject a fake)
+func (s *Service) SetClock(clock utils.Clock) {
+	s.clock = clock
+}
+
+// processManualNode hands the conversation off to a human agent. The node data can
+// tag the handoff with a skill ("skill" or "handoff_skill") so routing prefers
+// agents who declared that skill
+func (s *Service) processManualNode(flow *models.ChatbotFlow, execution *models.AIWhatsapp, node *models.FlowNode, userInput string) (string, error) {
+	logrus.WithFields(logrus.Fields{
+		"node_id":      node.ID,
+		"prospect_num": execution.ProspectNum,
+		"id_device":    execution.IDDevice,
+	}).Info("🙋 MANUAL: Processing manual handoff node")
+
+	// Enable human takeover so the bot stops responding
+	if err := s.aiWhatsappService.SetHumanMode(execution.ProspectNum, execution.IDDevice, true); err != nil {
+		logrus.WithError(err).Error("🙋 MANUAL: Failed to enable human takeover for handoff")
+		return "", err
+	}
+
+	// Read the skill tag from node data
+	skill := node.ManualConfig().Skill
+
+	// Route the handoff to a matching online agent
+	if s.assignmentService != nil {
+		if _, err := s.assignmentService.AssignConversation(execution.ProspectNum, execution.IDDevice, skill, ""); err != nil {
+			logrus.WithError(err).Warn("🙋 MANUAL: Failed to assign handoff conversation")
+		}
+	}
+
+	// Keep position on the manual node; the flow resumes only when an agent hands back
+	s.updateCurrentNode(execution, node.ID)
+	if err := s.aiWhatsappService.UpdateFlowExecution(execution.ProspectNum, execution.IDDevice, node.ID, make(map[string]interface{}), "active"); err != nil {
+		logrus.WithError(err).Error("🙋 MANUAL: Failed to update execution for handoff")
+	}
+
+	return "", nil
+}
+
 // StartQueueProcessor starts the queue processor for handling queued messages
 func (s *Service) StartQueueProcessor() {
 	logrus.Info("🚀 QUEUE: Starting queue processor")
@@ -3086,6 +3653,9 @@ func (s *Service) ProcessFlowContinuation(executionID, flowID, nodeID, phoneNumb
 		return fmt.Errorf("flow not found: %s", flowID)
 	}
 
+	// Run on the version this conversation was pinned to at start
+	flow = s.resolveExecutionFlow(flow, phoneNumber, deviceID)
+
 	// Get the target node (the node to process after delay)
 	targetNode, err := s.flowService.FindNodeByID(flow, nodeID)
 	if err != nil {
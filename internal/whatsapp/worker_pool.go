@@ -0,0 +1,115 @@
+package whatsapp
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Dynamic worker pool for the webhook message queue. The base worker count
+// and queue size come from config (MESSAGE_WORKERS / MESSAGE_QUEUE_SIZE); a
+// scaler goroutine grows the pool when the queue backs up and shrinks it back
+// toward the base count when the backlog clears, so operators running 3000+
+// devices can tune throughput without redeploying
+
+// workerScaleInterval is how often the scaler samples queue occupancy
+const workerScaleInterval = 5 * time.Second
+
+// workerGrowOccupancy is the queue occupancy above which a worker is added
+const workerGrowOccupancy = 0.5
+
+// workerShrinkOccupancy is the queue occupancy below which an extra worker
+// is retired
+const workerShrinkOccupancy = 0.1
+
+// workerMaxMultiplier caps the pool at this multiple of the base count
+const workerMaxMultiplier = 4
+
+// startWorkerPool starts the base workers and the scaler goroutine
+func (s *Service) startWorkerPool(baseWorkers int) {
+	s.baseWorkers = baseWorkers
+	s.maxWorkers = baseWorkers * workerMaxMultiplier
+
+	s.workerMu.Lock()
+	for i := 0; i < baseWorkers; i++ {
+		s.addWorkerLocked()
+	}
+	s.workerMu.Unlock()
+
+	go s.workerScaler()
+
+	logrus.WithFields(logrus.Fields{
+		"base_workers": baseWorkers,
+		"max_workers":  s.maxWorkers,
+		"queue_size":   cap(s.messageQueue),
+	}).Info("🚀 WORKERS: Message worker pool started")
+}
+
+// addWorkerLocked starts one worker goroutine; callers hold workerMu
+func (s *Service) addWorkerLocked() {
+	stop := make(chan struct{})
+	s.workerStops = append(s.workerStops, stop)
+	go s.messageProcessor(stop)
+}
+
+// workerScaler periodically adjusts the pool to the queue backlog
+func (s *Service) workerScaler() {
+	ticker := time.NewTicker(workerScaleInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		occupancy := s.queueOccupancy()
+
+		s.workerMu.Lock()
+		workers := len(s.workerStops)
+		switch {
+		case occupancy >= workerGrowOccupancy && workers < s.maxWorkers:
+			s.addWorkerLocked()
+			logrus.WithFields(logrus.Fields{
+				"queue_occupancy": occupancy,
+				"workers":         workers + 1,
+			}).Info("📈 WORKERS: Queue backing up, added worker")
+		case occupancy <= workerShrinkOccupancy && workers > s.baseWorkers:
+			// Retire the most recently added worker
+			stop := s.workerStops[workers-1]
+			s.workerStops = s.workerStops[:workers-1]
+			close(stop)
+			logrus.WithFields(logrus.Fields{
+				"queue_occupancy": occupancy,
+				"workers":         workers - 1,
+			}).Info("📉 WORKERS: Queue drained, retired worker")
+		}
+		s.workerMu.Unlock()
+	}
+}
+
+// queueOccupancy returns the fraction of the message queue buffer in use
+func (s *Service) queueOccupancy() float64 {
+	capacity := cap(s.messageQueue)
+	if capacity == 0 {
+		return 0
+	}
+	return float64(len(s.messageQueue)) / float64(capacity)
+}
+
+// QueueMetrics reports the message queue occupancy and worker pool size for
+// the operator metrics endpoint
+func (s *Service) QueueMetrics() map[string]interface{} {
+	s.workerMu.Lock()
+	workers := len(s.workerStops)
+	s.workerMu.Unlock()
+
+	metrics := map[string]interface{}{
+		"queue_depth":     len(s.messageQueue),
+		"queue_capacity":  cap(s.messageQueue),
+		"queue_occupancy": s.queueOccupancy(),
+		"workers":         workers,
+		"base_workers":    s.baseWorkers,
+		"max_workers":     s.maxWorkers,
+		"durable_queue":   s.webhookStreamEnabled,
+	}
+	if s.webhookStreamEnabled {
+		metrics["stream_depth"] = s.queueService.WebhookStreamDepth()
+	}
+	return metrics
+}
@@ -0,0 +1,2 @@
+-- Drop conversation_read_state_nodepath table
+DROP TABLE IF EXISTS conversation_read_state_nodepath;
@@ -0,0 +1,19 @@
+-- Create conversation_read_state_nodepath table for per-agent read cursors
+-- Each agent keeps one cursor per conversation so the inbox can show unread
+-- state and multiple agents don't double-handle the same thread
+
+CREATE TABLE IF NOT EXISTS conversation_read_state_nodepath (
+    id SERIAL PRIMARY KEY,
+    agent_id VARCHAR(255) NOT NULL,  -- Authenticated user ID of the agent
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    last_read_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_agent_conversation UNIQUE (agent_id, prospect_num, id_device)
+);
+
+CREATE INDEX IF NOT EXISTS idx_read_state_agent ON conversation_read_state_nodepath (agent_id);
+CREATE INDEX IF NOT EXISTS idx_read_state_device ON conversation_read_state_nodepath (id_device);
+CREATE INDEX IF NOT EXISTS idx_read_state_prospect ON conversation_read_state_nodepath (prospect_num);
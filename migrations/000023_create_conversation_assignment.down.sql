@@ -0,0 +1,4 @@
+-- Drop handoff assignment tables
+DROP TABLE IF EXISTS conversation_assignment_history_nodepath;
+DROP TABLE IF EXISTS conversation_assignment_nodepath;
+DROP TABLE IF EXISTS agent_status_nodepath;
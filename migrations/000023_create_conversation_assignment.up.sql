@@ -0,0 +1,48 @@
+-- Create tables for automatic handoff assignment and routing
+-- agent_status_nodepath tracks which agents are online per device
+-- conversation_assignment_nodepath holds the current assignment per conversation
+-- conversation_assignment_history_nodepath keeps the full assignment trail
+
+CREATE TABLE IF NOT EXISTS agent_status_nodepath (
+    id SERIAL PRIMARY KEY,
+    agent_id VARCHAR(255) NOT NULL,  -- Authenticated user ID of the agent
+    id_device VARCHAR(255) NOT NULL,
+    is_online BOOLEAN NOT NULL DEFAULT FALSE,
+    last_assigned_at TIMESTAMP NULL DEFAULT NULL,  -- Used for round-robin ordering
+    last_seen TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_agent_device UNIQUE (agent_id, id_device)
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_status_device ON agent_status_nodepath (id_device);
+CREATE INDEX IF NOT EXISTS idx_agent_status_online ON agent_status_nodepath (is_online);
+
+CREATE TABLE IF NOT EXISTS conversation_assignment_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    agent_id VARCHAR(255) NOT NULL,
+    strategy VARCHAR(50) NOT NULL DEFAULT 'round_robin',  -- round_robin, least_active or manual
+    assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_assignment_conversation UNIQUE (prospect_num, id_device)
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_agent ON conversation_assignment_nodepath (agent_id);
+CREATE INDEX IF NOT EXISTS idx_assignment_device ON conversation_assignment_nodepath (id_device);
+
+CREATE TABLE IF NOT EXISTS conversation_assignment_history_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    agent_id VARCHAR(255) NOT NULL,
+    previous_agent_id VARCHAR(255) DEFAULT NULL,
+    strategy VARCHAR(50) NOT NULL DEFAULT 'round_robin',
+    assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_history_conversation ON conversation_assignment_history_nodepath (prospect_num, id_device);
+CREATE INDEX IF NOT EXISTS idx_assignment_history_agent ON conversation_assignment_history_nodepath (agent_id);
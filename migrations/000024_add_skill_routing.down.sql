@@ -0,0 +1,6 @@
+-- Remove skill-based routing
+DROP TABLE IF EXISTS handoff_queue_nodepath;
+
+ALTER TABLE conversation_assignment_history_nodepath DROP COLUMN IF EXISTS skill;
+ALTER TABLE conversation_assignment_nodepath DROP COLUMN IF EXISTS skill;
+ALTER TABLE agent_status_nodepath DROP COLUMN IF EXISTS skills;
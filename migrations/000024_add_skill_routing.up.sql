@@ -0,0 +1,26 @@
+-- Add skill-based routing for handoff assignment
+-- Agents declare skills, flows tag handoffs with a required skill, and handoffs
+-- with no matching online agent wait in handoff_queue_nodepath
+
+ALTER TABLE agent_status_nodepath
+ADD COLUMN IF NOT EXISTS skills TEXT DEFAULT NULL;  -- Comma-separated skill tags declared by the agent
+
+ALTER TABLE conversation_assignment_nodepath
+ADD COLUMN IF NOT EXISTS skill VARCHAR(100) DEFAULT NULL;  -- Skill tag requested by the handoff
+
+ALTER TABLE conversation_assignment_history_nodepath
+ADD COLUMN IF NOT EXISTS skill VARCHAR(100) DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS handoff_queue_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    skill VARCHAR(100) DEFAULT NULL,
+    strategy VARCHAR(50) NOT NULL DEFAULT 'round_robin',
+    queued_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_queued_handoff UNIQUE (prospect_num, id_device)
+);
+
+CREATE INDEX IF NOT EXISTS idx_handoff_queue_device ON handoff_queue_nodepath (id_device);
+CREATE INDEX IF NOT EXISTS idx_handoff_queue_skill ON handoff_queue_nodepath (skill);
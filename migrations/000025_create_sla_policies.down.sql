@@ -0,0 +1,3 @@
+-- Drop SLA policy and event tables
+DROP TABLE IF EXISTS sla_event_nodepath;
+DROP TABLE IF EXISTS sla_policy_nodepath;
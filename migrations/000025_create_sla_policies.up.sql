@@ -0,0 +1,32 @@
+-- Create SLA policy and event tables for human-handoff queues
+-- sla_policy_nodepath holds the per-device first-response target and escalation action
+-- sla_event_nodepath tracks each handoff from creation to first agent response
+
+CREATE TABLE IF NOT EXISTS sla_policy_nodepath (
+    id SERIAL PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    first_response_minutes INT NOT NULL DEFAULT 15,  -- Target minutes to first agent response
+    escalation_action VARCHAR(50) NOT NULL DEFAULT 'notify_manager',  -- notify_manager, reassign or apology_message
+    apology_message TEXT DEFAULT NULL,  -- Message sent to the prospect when escalation_action is apology_message
+    manager_agent_id VARCHAR(255) DEFAULT NULL,  -- Agent notified when escalation_action is notify_manager
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_sla_policy_device UNIQUE (id_device)
+);
+
+CREATE TABLE IF NOT EXISTS sla_event_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    handoff_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    first_response_at TIMESTAMP NULL DEFAULT NULL,
+    breached BOOLEAN NOT NULL DEFAULT FALSE,
+    escalated_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sla_event_device ON sla_event_nodepath (id_device);
+CREATE INDEX IF NOT EXISTS idx_sla_event_open ON sla_event_nodepath (first_response_at, escalated_at);
+CREATE INDEX IF NOT EXISTS idx_sla_event_conversation ON sla_event_nodepath (prospect_num, id_device);
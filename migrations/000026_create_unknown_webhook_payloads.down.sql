@@ -0,0 +1,3 @@
+-- Drop the unknown webhook payload store
+
+DROP TABLE IF EXISTS unknown_webhook_payload_nodepath;
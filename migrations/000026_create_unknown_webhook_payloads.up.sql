@@ -0,0 +1,15 @@
+-- Create store for webhook payloads whose format could not be normalized
+-- Payloads are PII-masked before storage so maintainers can inspect the shape
+-- of new provider variants without exposing customer data
+
+CREATE TABLE IF NOT EXISTS unknown_webhook_payload_nodepath (
+    id SERIAL PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    missing_fields VARCHAR(255) NOT NULL,  -- Comma-separated required fields that failed extraction
+    payload_masked TEXT NOT NULL,  -- JSON payload with PII values masked
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_unknown_payload_provider ON unknown_webhook_payload_nodepath (provider);
+CREATE INDEX IF NOT EXISTS idx_unknown_payload_created ON unknown_webhook_payload_nodepath (created_at);
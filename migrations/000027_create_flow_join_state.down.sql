@@ -0,0 +1,3 @@
+-- Drop the parallel branch join state table
+
+DROP TABLE IF EXISTS flow_join_state_nodepath;
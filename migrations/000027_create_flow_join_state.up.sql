@@ -0,0 +1,16 @@
+-- Track which parallel branches have reached a join node per execution
+-- A join releases once every incoming edge has recorded an arrival
+
+CREATE TABLE IF NOT EXISTS flow_join_state_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    flow_id VARCHAR(255) NOT NULL,
+    join_node_id VARCHAR(255) NOT NULL,
+    branch_edge_id VARCHAR(255) NOT NULL,  -- Edge the completed branch arrived on
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_join_arrival UNIQUE (prospect_num, id_device, join_node_id, branch_edge_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_join_state_conversation ON flow_join_state_nodepath (prospect_num, id_device);
@@ -0,0 +1,19 @@
+-- Product catalog per device, sent by catalog flow nodes as a paginated list
+-- SKU is unique per device so flows can reference products stably
+
+CREATE TABLE IF NOT EXISTS product_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    image_url VARCHAR(500),
+    price DECIMAL(10,2) NOT NULL DEFAULT 0,
+    sku VARCHAR(100) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_product_sku UNIQUE (id_device, sku)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_device ON product_nodepath (id_device, is_active);
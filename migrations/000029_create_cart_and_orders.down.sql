@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS flow_order_nodepath;
+DROP TABLE IF EXISTS cart_item_nodepath;
@@ -0,0 +1,31 @@
+-- Shopping cart per conversation and orders captured by order summary nodes
+-- Orders move through pending -> confirmed -> paid -> fulfilled (or cancelled)
+-- so e-commerce integrations can sync them by status
+
+CREATE TABLE IF NOT EXISTS cart_item_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    product_id VARCHAR(36) NOT NULL,
+    quantity INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_cart_item UNIQUE (prospect_num, id_device, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cart_conversation ON cart_item_nodepath (prospect_num, id_device);
+
+CREATE TABLE IF NOT EXISTS flow_order_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total DECIMAL(10,2) NOT NULL DEFAULT 0,
+    items_json TEXT NOT NULL,  -- Order line snapshot (product, sku, price, quantity)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_device_status ON flow_order_nodepath (id_device, status);
+CREATE INDEX IF NOT EXISTS idx_order_conversation ON flow_order_nodepath (prospect_num, id_device);
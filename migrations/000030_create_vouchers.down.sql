@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS voucher_redemption_nodepath;
+DROP TABLE IF EXISTS voucher_nodepath;
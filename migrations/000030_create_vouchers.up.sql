@@ -0,0 +1,29 @@
+-- Voucher codes issued by flow nodes and redeemed in-flow or by external POS
+-- Redemptions are logged individually so campaigns can be audited
+
+CREATE TABLE IF NOT EXISTS voucher_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    code VARCHAR(20) NOT NULL,
+    campaign VARCHAR(255) NOT NULL DEFAULT '',
+    discount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    issued_to VARCHAR(255) NOT NULL DEFAULT '',  -- Prospect number the code was issued to
+    max_redemptions INT NOT NULL DEFAULT 1,
+    redemption_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_voucher_code UNIQUE (code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_device ON voucher_nodepath (id_device, campaign);
+
+CREATE TABLE IF NOT EXISTS voucher_redemption_nodepath (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL,
+    prospect_num VARCHAR(255) NOT NULL DEFAULT '',
+    source VARCHAR(50) NOT NULL DEFAULT 'flow',  -- flow or pos
+    redeemed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_redemption_code ON voucher_redemption_nodepath (code);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS referral_attribution_nodepath;
+DROP TABLE IF EXISTS referral_nodepath;
@@ -0,0 +1,27 @@
+-- Referral tracking: each prospect gets a unique invite code embedded in a
+-- wa.me deep link; new conversations opening with the code are attributed to
+-- the referrer, once per referred number
+
+CREATE TABLE IF NOT EXISTS referral_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    prospect_num VARCHAR(255) NOT NULL,  -- The referrer
+    code VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_referral_code UNIQUE (code),
+    CONSTRAINT uniq_referral_prospect UNIQUE (id_device, prospect_num)
+);
+
+CREATE TABLE IF NOT EXISTS referral_attribution_nodepath (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    referrer_num VARCHAR(255) NOT NULL,
+    referred_num VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_attribution UNIQUE (code, referred_num)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attribution_referrer ON referral_attribution_nodepath (id_device, referrer_num);
@@ -0,0 +1,18 @@
+-- Physical branches/outlets per organisation, used by nearest_branch flow
+-- nodes to route prospects who share their location
+
+CREATE TABLE IF NOT EXISTS branch_location_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    address TEXT,
+    latitude DECIMAL(10,7) NOT NULL,
+    longitude DECIMAL(10,7) NOT NULL,
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    branch_device VARCHAR(255) NOT NULL DEFAULT '',  -- Device handling this branch, for branch-specific routing
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_branch_device ON branch_location_nodepath (id_device, is_active);
@@ -0,0 +1,18 @@
+-- QR campaigns for print/in-store entry points. Each campaign's QR encodes a
+-- wa.me link prefilled with a unique entry keyword (base keyword + suffix);
+-- scans are counted when the entry keyword arrives in a conversation
+
+CREATE TABLE IF NOT EXISTS qr_campaign_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    flow_id VARCHAR(255) NOT NULL DEFAULT '',
+    keyword VARCHAR(100) NOT NULL,  -- Base keyword the target flow starts on
+    entry_keyword VARCHAR(120) NOT NULL,  -- keyword-suffix, unique per campaign
+    scan_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_qr_entry_keyword UNIQUE (entry_keyword)
+);
+
+CREATE INDEX IF NOT EXISTS idx_qr_device ON qr_campaign_nodepath (id_device);
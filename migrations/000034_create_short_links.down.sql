@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS short_link_setting_nodepath;
+DROP TABLE IF EXISTS short_link_nodepath;
@@ -0,0 +1,24 @@
+-- Short links for media and tracking URLs. Outbound messages can have their
+-- long URLs rewritten to /s/:code redirects per device; each redirect counts
+-- a click and links can expire
+
+CREATE TABLE IF NOT EXISTS short_link_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    code VARCHAR(16) NOT NULL,
+    target_url TEXT NOT NULL,
+    click_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_short_link_code UNIQUE (code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_short_link_device ON short_link_nodepath (id_device);
+
+-- Per-device toggle for rewriting URLs in outbound messages
+CREATE TABLE IF NOT EXISTS short_link_setting_nodepath (
+    id_device VARCHAR(255) PRIMARY KEY,
+    auto_shorten BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
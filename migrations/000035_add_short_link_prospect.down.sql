@@ -0,0 +1,3 @@
+ALTER TABLE short_link_nodepath
+    DROP INDEX idx_short_link_prospect,
+    DROP COLUMN prospect_num;
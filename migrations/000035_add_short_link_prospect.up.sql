@@ -0,0 +1,7 @@
+-- Attribute short links to the prospect they were sent to so clicks can
+-- resume that prospect's flow and appear in their conversation timeline
+
+ALTER TABLE short_link_nodepath
+    ADD COLUMN IF NOT EXISTS prospect_num VARCHAR(50) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_short_link_prospect ON short_link_nodepath (id_device, prospect_num);
@@ -0,0 +1,19 @@
+-- Lead sources for external intake (web forms, Meta Lead Ads). Each source has
+-- a verify token used in its public intake URL; incoming leads create a
+-- prospect, optionally get a first template message, and start the mapped flow
+
+CREATE TABLE IF NOT EXISTS lead_source_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    source_type VARCHAR(20) NOT NULL DEFAULT 'generic',  -- generic or meta
+    flow_id VARCHAR(255) NOT NULL DEFAULT '',
+    template_message TEXT,
+    verify_token VARCHAR(64) NOT NULL,
+    lead_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_lead_source_token UNIQUE (verify_token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_source_device ON lead_source_nodepath (id_device);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS quiet_hour_nodepath;
+DROP TABLE IF EXISTS opt_out_nodepath;
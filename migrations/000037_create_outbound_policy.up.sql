@@ -0,0 +1,18 @@
+-- Outbound messaging policy: per-device opt-out list and quiet hours. Both
+-- are checked before a flow is started proactively for a phone number
+
+CREATE TABLE IF NOT EXISTS opt_out_nodepath (
+    id_device VARCHAR(255) NOT NULL,
+    prospect_num VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (id_device, prospect_num)
+);
+
+CREATE TABLE IF NOT EXISTS quiet_hour_nodepath (
+    id_device VARCHAR(255) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    start_hour SMALLINT NOT NULL DEFAULT 22,  -- Hour (0-23) quiet time starts
+    end_hour SMALLINT NOT NULL DEFAULT 8,  -- Hour (0-23) quiet time ends
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
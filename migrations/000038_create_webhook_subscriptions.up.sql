@@ -0,0 +1,16 @@
+-- Outbound webhook subscriptions for execution lifecycle events. External
+-- systems register a URL per device and receive signed JSON payloads when
+-- executions start, change stage, wait for replies, complete, expire, or are
+-- handed off to a human
+
+CREATE TABLE IF NOT EXISTS webhook_subscription_nodepath (
+    id VARCHAR(36) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    events VARCHAR(500) NOT NULL DEFAULT '',  -- Comma-separated event names, empty = all events
+    secret VARCHAR(64) NOT NULL,  -- HMAC-SHA256 signing secret
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_sub_device ON webhook_subscription_nodepath (id_device);
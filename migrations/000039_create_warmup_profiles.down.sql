@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS daily_send_count_nodepath;
+DROP TABLE IF EXISTS warmup_profile_nodepath;
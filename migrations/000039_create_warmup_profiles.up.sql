@@ -0,0 +1,21 @@
+-- Warm-up profiles for new WhatsApp numbers: a daily send cap that grows over
+-- time, with proactive sends spread across the day so fresh numbers are not
+-- banned for blasting on day one
+
+CREATE TABLE IF NOT EXISTS warmup_profile_nodepath (
+    id_device VARCHAR(255) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    started_at DATE NOT NULL,  -- Day one of the warm-up schedule
+    base_cap INT NOT NULL DEFAULT 50,  -- Allowed sends on day one
+    growth_per_day INT NOT NULL DEFAULT 25,  -- Cap increase per day since start
+    max_cap INT NOT NULL DEFAULT 1000,  -- Ceiling the cap grows to
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS daily_send_count_nodepath (
+    id_device VARCHAR(255) NOT NULL,
+    send_date DATE NOT NULL,
+    sent_count INT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (id_device, send_date)
+);
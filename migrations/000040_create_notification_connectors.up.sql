@@ -0,0 +1,14 @@
+-- Slack/Discord connectors for operational event notifications
+-- Each device can register multiple connectors filtered by event list
+
+CREATE TABLE IF NOT EXISTS notification_connector_nodepath (
+    id VARCHAR(255) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    platform VARCHAR(50) NOT NULL,  -- slack or discord
+    webhook_url TEXT NOT NULL,
+    events TEXT,  -- Comma-separated list of subscribed events
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_connector_device ON notification_connector_nodepath (id_device);
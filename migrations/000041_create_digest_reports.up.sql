@@ -0,0 +1,13 @@
+-- Generated daily/weekly digest reports, one row per device and period
+
+CREATE TABLE IF NOT EXISTS digest_report_nodepath (
+    id VARCHAR(255) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    period VARCHAR(20) NOT NULL,  -- daily or weekly
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    report TEXT NOT NULL,  -- Computed metrics as JSON
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_report_device ON digest_report_nodepath (id_device, created_at);
@@ -0,0 +1,8 @@
+-- Per-device data retention policy driving the scheduled purger
+
+CREATE TABLE IF NOT EXISTS retention_policy_nodepath (
+    id_device VARCHAR(255) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    conversation_days INT NOT NULL DEFAULT 0,  -- 0 keeps conversations forever
+    execution_days INT NOT NULL DEFAULT 0  -- 0 keeps execution traces forever
+);
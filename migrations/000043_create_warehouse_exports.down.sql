@@ -0,0 +1 @@
+DROP TABLE IF EXISTS warehouse_export_nodepath;
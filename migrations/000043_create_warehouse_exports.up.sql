@@ -0,0 +1,9 @@
+-- Per-device analytics warehouse connector for lifecycle event streaming
+
+CREATE TABLE IF NOT EXISTS warehouse_export_nodepath (
+    id_device VARCHAR(255) PRIMARY KEY,
+    endpoint TEXT NOT NULL,
+    auth_header TEXT,  -- Optional Authorization header value
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
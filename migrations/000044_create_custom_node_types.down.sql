@@ -0,0 +1 @@
+DROP TABLE IF EXISTS custom_node_type_nodepath;
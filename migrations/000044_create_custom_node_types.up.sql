@@ -0,0 +1,14 @@
+-- Webhook-backed custom node types registered per device
+
+CREATE TABLE IF NOT EXISTS custom_node_type_nodepath (
+    id VARCHAR(255) PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    type_name VARCHAR(100) NOT NULL,
+    endpoint_url TEXT NOT NULL,
+    secret VARCHAR(255),  -- HMAC secret for signing execution requests
+    timeout_seconds INT NOT NULL DEFAULT 10,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_custom_node_type UNIQUE (id_device, type_name)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS script_permission_nodepath;
@@ -0,0 +1,6 @@
+-- Per-flow permission gate for the sandboxed script node
+
+CREATE TABLE IF NOT EXISTS script_permission_nodepath (
+    flow_id VARCHAR(255) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE
+);
@@ -0,0 +1,14 @@
+-- Append-only stage transition history per conversation
+
+CREATE TABLE IF NOT EXISTS stage_history_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    from_stage VARCHAR(255),
+    to_stage VARCHAR(255) NOT NULL,
+    source VARCHAR(50),  -- What triggered the transition (ai_response, manual, flow)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stage_history_conversation ON stage_history_nodepath (prospect_num, id_device, created_at);
+CREATE INDEX IF NOT EXISTS idx_stage_history_device ON stage_history_nodepath (id_device);
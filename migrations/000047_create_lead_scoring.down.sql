@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lead_score_nodepath;
+DROP TABLE IF EXISTS lead_scoring_nodepath;
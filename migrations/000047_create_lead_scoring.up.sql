@@ -0,0 +1,17 @@
+-- Lead scoring: per-device rule config plus accumulated per-prospect scores
+
+CREATE TABLE IF NOT EXISTS lead_scoring_nodepath (
+    id_device VARCHAR(255) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    config TEXT  -- Scoring rules as JSON
+);
+
+CREATE TABLE IF NOT EXISTS lead_score_nodepath (
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    score INT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (prospect_num, id_device)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_score_device ON lead_score_nodepath (id_device, score);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS contact_identity_nodepath;
+DROP TABLE IF EXISTS contact_nodepath;
@@ -0,0 +1,16 @@
+-- Cross-device contact identity: one contact row per account phone number,
+-- plus the account-level sharing mode toggle
+
+CREATE TABLE IF NOT EXISTS contact_nodepath (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_contact UNIQUE (user_id, phone_number)
+);
+
+CREATE TABLE IF NOT EXISTS contact_identity_nodepath (
+    user_id VARCHAR(255) PRIMARY KEY,
+    identity_mode VARCHAR(20) NOT NULL DEFAULT 'isolated'  -- isolated or shared
+);
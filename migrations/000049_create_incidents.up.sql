@@ -0,0 +1,13 @@
+-- Operator-managed incidents shown on the public status page
+
+CREATE TABLE IF NOT EXISTS incident_nodepath (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    severity VARCHAR(20) NOT NULL,  -- minor, major or critical
+    status VARCHAR(20) NOT NULL,  -- investigating, identified, monitoring or resolved
+    message TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_status ON incident_nodepath (status, created_at);
@@ -0,0 +1,11 @@
+-- Explicitly completed onboarding wizard steps; derived steps come from
+-- live tables and are never stored here
+
+CREATE TABLE IF NOT EXISTS onboarding_nodepath (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    step VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_onboarding_step UNIQUE (user_id, step)
+);
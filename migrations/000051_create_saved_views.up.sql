@@ -0,0 +1,18 @@
+-- Saved inbox/conversation list views with filters, sort and columns
+
+CREATE TABLE IF NOT EXISTS saved_view_nodepath (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255),
+    name VARCHAR(255) NOT NULL,
+    scope VARCHAR(50) NOT NULL,
+    filters TEXT,  -- Filter definition as JSON
+    sort VARCHAR(255),
+    columns TEXT,  -- Visible columns as JSON
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_view_user ON saved_view_nodepath (user_id, scope);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS approval_queue_nodepath;
+DROP TABLE IF EXISTS flow_review_nodepath;
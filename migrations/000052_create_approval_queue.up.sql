@@ -0,0 +1,24 @@
+-- Review-before-send mode: per-flow toggle plus the queue of held messages
+
+CREATE TABLE IF NOT EXISTS flow_review_nodepath (
+    flow_id VARCHAR(255) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS approval_queue_nodepath (
+    id SERIAL PRIMARY KEY,
+    flow_id VARCHAR(255) NOT NULL,
+    node_id VARCHAR(255),
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    message_type VARCHAR(20) NOT NULL DEFAULT 'text',
+    content TEXT NOT NULL,
+    edited_content TEXT,  -- Reviewer-edited content, sent instead of content when set
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',  -- pending, approved, rejected
+    reviewed_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_queue_device ON approval_queue_nodepath (id_device, status);
+CREATE INDEX IF NOT EXISTS idx_approval_queue_flow ON approval_queue_nodepath (flow_id, status);
@@ -0,0 +1,17 @@
+-- Agent edits of AI/flow messages, collected for the prompt-improvement loop
+
+CREATE TABLE IF NOT EXISTS message_edit_nodepath (
+    id SERIAL PRIMARY KEY,
+    flow_id VARCHAR(255),
+    node_id VARCHAR(255),
+    id_device VARCHAR(255) NOT NULL,
+    prospect_num VARCHAR(255) NOT NULL,
+    source VARCHAR(50),  -- Where the original came from (ai, flow, approval)
+    original TEXT NOT NULL,
+    edited TEXT NOT NULL,
+    edited_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_edit_flow ON message_edit_nodepath (flow_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_message_edit_device ON message_edit_nodepath (id_device);
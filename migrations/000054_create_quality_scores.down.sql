@@ -0,0 +1 @@
+DROP TABLE IF EXISTS quality_score_nodepath;
@@ -0,0 +1,18 @@
+-- LLM-judged conversation quality scores per flow version
+
+CREATE TABLE IF NOT EXISTS quality_score_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    flow_id VARCHAR(255),
+    flow_version INT NOT NULL DEFAULT 0,
+    accuracy INT NOT NULL DEFAULT 0,
+    tone INT NOT NULL DEFAULT 0,
+    goal_completion INT NOT NULL DEFAULT 0,
+    overall INT NOT NULL DEFAULT 0,
+    summary TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quality_score_conversation ON quality_score_nodepath (prospect_num, id_device);
+CREATE INDEX IF NOT EXISTS idx_quality_score_flow ON quality_score_nodepath (flow_id, flow_version);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS prospect_language_nodepath;
@@ -0,0 +1,12 @@
+-- Detected or explicitly chosen conversation language per prospect
+
+CREATE TABLE IF NOT EXISTS prospect_language_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    source VARCHAR(20),  -- detected or manual
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_prospect_language UNIQUE (prospect_num, id_device)
+);
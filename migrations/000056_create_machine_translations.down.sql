@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS machine_translation_log_nodepath;
+DROP TABLE IF EXISTS machine_translation_nodepath;
+DROP TABLE IF EXISTS flow_auto_translate_nodepath;
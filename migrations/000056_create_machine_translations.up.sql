@@ -0,0 +1,34 @@
+-- Machine translation fallback: per-flow toggle, cached translations keyed
+-- by source content hash, and a log of which sends used a machine fallback
+
+CREATE TABLE IF NOT EXISTS flow_auto_translate_nodepath (
+    flow_id VARCHAR(255) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS machine_translation_nodepath (
+    id SERIAL PRIMARY KEY,
+    flow_id VARCHAR(255) NOT NULL,
+    node_id VARCHAR(255) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    source_hash VARCHAR(64) NOT NULL,  -- Hash of the source text; changed content gets a new row
+    source_text TEXT NOT NULL,
+    translated_text TEXT NOT NULL,
+    use_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NULL DEFAULT NULL,
+
+    CONSTRAINT uniq_machine_translation UNIQUE (flow_id, node_id, language, source_hash)
+);
+
+CREATE TABLE IF NOT EXISTS machine_translation_log_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    flow_id VARCHAR(255),
+    node_id VARCHAR(255),
+    language VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_translation_log_flow ON machine_translation_log_nodepath (flow_id, created_at);
@@ -0,0 +1,17 @@
+-- Dead-letter queue for outbound messages that exhausted send retries
+
+CREATE TABLE IF NOT EXISTS failed_message_nodepath (
+    id SERIAL PRIMARY KEY,
+    idempotency_key VARCHAR(255) NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+    prospect_num VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    provider VARCHAR(50),
+    sender_name VARCHAR(255),
+    last_error TEXT,
+    retries INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_message_device ON failed_message_nodepath (device_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_failed_message_key ON failed_message_nodepath (idempotency_key);
@@ -0,0 +1,13 @@
+-- Reusable PDF document templates for the document flow node
+
+CREATE TABLE IF NOT EXISTS document_template_nodepath (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    title VARCHAR(255),
+    lines TEXT NOT NULL,  -- Template line definitions as JSON
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_template_user ON document_template_nodepath (user_id, name);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS execution_flow_pin_nodepath;
+DROP TABLE IF EXISTS flow_version_nodepath;
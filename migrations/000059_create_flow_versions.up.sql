@@ -0,0 +1,24 @@
+-- Immutable published flow versions, plus per-conversation version pins so
+-- in-flight executions keep running the version they started on
+
+CREATE TABLE IF NOT EXISTS flow_version_nodepath (
+    id SERIAL PRIMARY KEY,
+    flow_id VARCHAR(255) NOT NULL,
+    version INT NOT NULL,
+    nodes TEXT NOT NULL,  -- Snapshot of flow nodes as JSON
+    edges TEXT NOT NULL,  -- Snapshot of flow edges as JSON
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_flow_version UNIQUE (flow_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS execution_flow_pin_nodepath (
+    id SERIAL PRIMARY KEY,
+    prospect_num VARCHAR(255) NOT NULL,
+    id_device VARCHAR(255) NOT NULL,
+    flow_id VARCHAR(255) NOT NULL,
+    version INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_execution_pin UNIQUE (prospect_num, id_device)
+);
@@ -0,0 +1,14 @@
+-- Embedding vectors over conversation history chunks for semantic search
+
+CREATE TABLE IF NOT EXISTS conversation_embedding_nodepath (
+    id SERIAL PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    prospect_num VARCHAR(255) NOT NULL,
+    chunk_index INT NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,  -- Hash of the chunk; unchanged chunks skip re-embedding
+    snippet TEXT NOT NULL,
+    embedding TEXT NOT NULL,  -- Embedding vector as JSON array
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_embedding_chunk UNIQUE (id_device, prospect_num, chunk_index)
+);
@@ -0,0 +1,15 @@
+-- Hash-chained audit log: each row's hash covers its content and the
+-- previous row's hash, making tampering detectable per device
+
+CREATE TABLE IF NOT EXISTS audit_log_nodepath (
+    id SERIAL PRIMARY KEY,
+    id_device VARCHAR(255) NOT NULL,
+    prospect_num VARCHAR(255) NOT NULL,
+    sender VARCHAR(20) NOT NULL,  -- user or bot
+    content TEXT NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL,  -- Hash of the previous entry in this device chain
+    hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_device ON audit_log_nodepath (id_device, id);
@@ -0,0 +1,8 @@
+-- Per-user access role driving PII masking; users without a row keep the
+-- historical full-access behaviour
+
+CREATE TABLE IF NOT EXISTS user_role_nodepath (
+    user_id VARCHAR(255) PRIMARY KEY,
+    role VARCHAR(20) NOT NULL,  -- admin or viewer
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,9 @@
+-- Per-device webhook signing secrets; devices without a row accept
+-- unsigned webhooks
+
+CREATE TABLE IF NOT EXISTS webhook_secret_nodepath (
+    id_device VARCHAR(255) PRIMARY KEY,
+    secret VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
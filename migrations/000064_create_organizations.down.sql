@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS organization_member_nodepath;
+DROP TABLE IF EXISTS organization_nodepath;
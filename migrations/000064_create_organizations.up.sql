@@ -0,0 +1,22 @@
+-- Organizations for role-based resource sharing between accounts
+
+CREATE TABLE IF NOT EXISTS organization_nodepath (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_owner ON organization_nodepath (owner_id);
+
+CREATE TABLE IF NOT EXISTS organization_member_nodepath (
+    id SERIAL PRIMARY KEY,
+    org_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,  -- owner, admin, agent or viewer
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uniq_org_member UNIQUE (org_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_member_user ON organization_member_nodepath (user_id);